@@ -178,7 +178,7 @@ func writeDescsToMetadata(ctx context.Context, sst storage.SSTWriter, m *BackupM
 			k := encodeDescSSTKey(i.ID)
 			var b []byte
 			if i.Desc != nil {
-				t, _, _, _ := descpb.FromDescriptor(i.Desc)
+				t, _, _, _, _ := descpb.FromDescriptor(i.Desc)
 				if t == nil || !t.Dropped() {
 					bytes, err := protoutil.Marshal(i.Desc)
 					if err != nil {
@@ -306,7 +306,7 @@ func writeNamesToMetadata(ctx context.Context, sst storage.SSTWriter, m *BackupM
 	for i, rev := range revs {
 		names[i].id = rev.ID
 		names[i].ts = rev.Time
-		tb, db, typ, sc := descpb.FromDescriptor(rev.Desc)
+		tb, db, typ, sc, _ := descpb.FromDescriptor(rev.Desc)
 		if db != nil {
 			names[i].name = db.Name
 		} else if sc != nil {
@@ -1002,7 +1002,7 @@ func (di *DescIterator) Next(desc *descpb.Descriptor) bool {
 			return false
 		}
 
-		tbl, db, typ, sc := descpb.FromDescriptor(desc)
+		tbl, db, typ, sc, _ := descpb.FromDescriptor(desc)
 		if tbl != nil || db != nil || typ != nil || sc != nil {
 			return true
 		}
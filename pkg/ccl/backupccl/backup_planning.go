@@ -71,6 +71,8 @@ const (
 	defaultLocalityValue      = "default"
 	backupOptDebugMetadataSST = "debug_dump_metadata_sst"
 	backupOptEncDir           = "encryption_info_dir"
+	backupOptCheckFiles       = "check_files"
+	backupOptValidate         = "validate"
 )
 
 type tableAndIndex struct {
@@ -176,7 +178,7 @@ func spansForAllTableIndexes(
 		// at least 2 revisions, and the first one should have the table in a PUBLIC
 		// state. We want (and do) ignore tables that have been dropped for the
 		// entire interval. DROPPED tables should never later become PUBLIC.
-		rawTbl, _, _, _ := descpb.FromDescriptor(rev.Desc)
+		rawTbl, _, _, _, _ := descpb.FromDescriptor(rev.Desc)
 		if rawTbl != nil && rawTbl.Public() {
 			tbl := tabledesc.NewBuilder(rawTbl).BuildImmutableTable()
 			revSpans, err := getPublicIndexTableSpans(tbl, added, execCfg.Codec)
@@ -422,6 +424,7 @@ func checkPrivilegesForBackup(
 	p sql.PlanHookState,
 	targetDescs []catalog.Descriptor,
 	to []string,
+	kms []string,
 ) error {
 	hasAdmin, err := p.HasAdminRole(ctx)
 	if err != nil {
@@ -482,6 +485,24 @@ func checkPrivilegesForBackup(
 		}
 	}
 
+	return checkKMSAccessIsWithExplicitAuth(kms, "BACKUP")
+}
+
+// checkKMSAccessIsWithExplicitAuth returns an error if any of the KMS URIs
+// relies on the node's own access to the key, which only admin users are
+// allowed to use.
+func checkKMSAccessIsWithExplicitAuth(kms []string, op string) error {
+	for _, uri := range kms {
+		explicit, err := cloud.KMSAccessIsWithExplicitAuth(uri)
+		if err != nil {
+			return err
+		}
+		if !explicit {
+			return pgerror.Newf(
+				pgcode.InsufficientPrivilege,
+				"only users with the admin role are allowed to %s with the specified KMS URI", op)
+		}
+	}
 	return nil
 }
 
@@ -679,7 +700,7 @@ func backupPlanHook(
 		}
 
 		// Check BACKUP privileges.
-		err = checkPrivilegesForBackup(ctx, backupStmt, p, targetDescs, to)
+		err = checkPrivilegesForBackup(ctx, backupStmt, p, targetDescs, to, encryptionParams.RawKmsUris)
 		if err != nil {
 			return err
 		}
@@ -1218,7 +1239,7 @@ func getReintroducedSpans(
 	for _, desc := range lastBackup.Descriptors {
 		// TODO(pbardea): Also check that lastWriteTime is set once those are
 		// populated on the table descriptor.
-		if table, _, _, _ := descpb.FromDescriptor(&desc); table != nil && table.Offline() {
+		if table, _, _, _, _ := descpb.FromDescriptor(&desc); table != nil && table.Offline() {
 			offlineInLastBackup[table.GetID()] = struct{}{}
 		}
 	}
@@ -1238,7 +1259,7 @@ func getReintroducedSpans(
 	// the time of the current backup, but may have been PUBLIC at some time in
 	// between.
 	for _, rev := range revs {
-		rawTable, _, _, _ := descpb.FromDescriptor(rev.Desc)
+		rawTable, _, _, _, _ := descpb.FromDescriptor(rev.Desc)
 		if rawTable == nil {
 			continue
 		}
@@ -1253,7 +1274,7 @@ func getReintroducedSpans(
 	// considered.
 	allRevs := make([]BackupManifest_DescriptorRevision, 0, len(revs))
 	for _, rev := range revs {
-		rawTable, _, _, _ := descpb.FromDescriptor(rev.Desc)
+		rawTable, _, _, _, _ := descpb.FromDescriptor(rev.Desc)
 		if rawTable == nil {
 			continue
 		}
@@ -1295,7 +1316,7 @@ func getProtectedTimestampTargetForBackup(backupManifest BackupManifest) *ptpb.T
 	// timestamp record on each table being backed up.
 	tableIDs := make(descpb.IDs, 0)
 	for _, desc := range backupManifest.Descriptors {
-		t, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, hlc.Timestamp{})
+		t, _, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, hlc.Timestamp{})
 		if t != nil {
 			tableIDs = append(tableIDs, t.GetID())
 		}
@@ -1668,7 +1689,7 @@ func createBackupManifest(
 		dbsInPrev := make(map[descpb.ID]struct{})
 		rawDescs := prevBackups[len(prevBackups)-1].Descriptors
 		for i := range rawDescs {
-			if t, _, _, _ := descpb.FromDescriptor(&rawDescs[i]); t != nil {
+			if t, _, _, _, _ := descpb.FromDescriptor(&rawDescs[i]); t != nil {
 				tablesInPrev[t.ID] = struct{}{}
 			}
 		}
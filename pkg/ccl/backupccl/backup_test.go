@@ -8920,6 +8920,160 @@ func TestRestoreNewDatabaseName(t *testing.T) {
 		"RESTORE DATABASE fkdb FROM $1 WITH new_db_name = 'new_fkdb'", localFoo)
 }
 
+// TestRestoreSchemaOnly tests that the schema_only option restores the
+// descriptors of the targets without any of their data.
+func TestRestoreSchemaOnly(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 10
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE TYPE data.status AS ENUM ('open', 'closed')`)
+	sqlDB.Exec(t, `CREATE TABLE data.tickets (id INT PRIMARY KEY, s data.status, INDEX (s))`)
+	sqlDB.Exec(t, `INSERT INTO data.tickets VALUES (1, 'open'), (2, 'closed')`)
+	sqlDB.Exec(t, `CREATE VIEW data.open_tickets AS SELECT id FROM data.tickets WHERE s = 'open'`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1`, localFoo)
+
+	t.Run("option checks", func(t *testing.T) {
+		expectedErr := `"schema_only" can only be used when restoring tables or databases`
+		sqlDB.ExpectErr(t, expectedErr, `RESTORE FROM $1 WITH schema_only`, localFoo)
+		sqlDB.ExpectErr(t, expectedErr, `RESTORE SYSTEM USERS FROM $1 WITH schema_only`, localFoo)
+		sqlDB.ExpectErr(t, `WHERE cannot be used with the "schema_only" option`,
+			`RESTORE TABLE data.bank FROM $1 WHERE id < 5 WITH schema_only`, localFoo)
+	})
+
+	sqlDB.Exec(t, `RESTORE DATABASE data FROM $1 WITH schema_only, new_db_name = 'staging'`, localFoo)
+
+	for _, table := range []string{"bank", "tickets", "open_tickets"} {
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM staging.`+table, [][]string{{"0"}})
+	}
+
+	// The restored descriptors are usable as if they were created from scratch.
+	sqlDB.Exec(t, `INSERT INTO staging.tickets VALUES (3, 'open')`)
+	sqlDB.CheckQueryResults(t, `SELECT id FROM staging.open_tickets`, [][]string{{"3"}})
+	sqlDB.CheckQueryResults(t, `SELECT id FROM staging.tickets@tickets_s_idx WHERE s = 'open'`,
+		[][]string{{"3"}})
+
+	sqlDB.Exec(t, `CREATE DATABASE staging_tables`)
+	sqlDB.Exec(t, `RESTORE TABLE data.bank FROM $1 WITH schema_only, into_db = 'staging_tables'`,
+		localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM staging_tables.bank`, [][]string{{"0"}})
+}
+
+// TestRestoreRowFilter tests restoring the subset of the rows of a table
+// selected by a WHERE clause on its primary key.
+func TestRestoreRowFilter(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 100
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE INDEX bank_balance_idx ON data.bank (balance)`)
+	sqlDB.Exec(t, `CREATE TABLE data.events (ts INT, v STRING, PRIMARY KEY (ts DESC))`)
+	sqlDB.Exec(t, `INSERT INTO data.events SELECT x, x::STRING FROM generate_series(1, 10) AS x`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1`, localFoo)
+	sqlDB.Exec(t, `CREATE DATABASE r`)
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			query string
+			err   string
+		}{
+			{
+				query: `RESTORE DATABASE data FROM $1 WHERE id < 5`,
+				err:   `WHERE can only be used when restoring a single table`,
+			},
+			{
+				query: `RESTORE TABLE data.bank, data.events FROM $1 WHERE id < 5 WITH into_db = 'r'`,
+				err:   `WHERE can only be used when restoring a single table`,
+			},
+			{
+				query: `RESTORE TABLE data.* FROM $1 WHERE id < 5 WITH into_db = 'r'`,
+				err:   `WHERE can only be used when restoring a single table`,
+			},
+			{
+				query: `RESTORE TABLE data.bank FROM $1 WHERE balance < 5 WITH into_db = 'r'`,
+				err:   `unsupported WHERE clause for RESTORE: balance < 5: only comparisons of the first primary key column id of table bank`,
+			},
+			{
+				query: `RESTORE TABLE data.bank FROM $1 WHERE id < 5 OR id > 10 WITH into_db = 'r'`,
+				err:   `unsupported WHERE clause for RESTORE: id < 5 OR id > 10: only comparisons of the first primary key column id of table bank`,
+			},
+			{
+				query: `RESTORE TABLE data.bank FROM $1 WHERE id < 'a' WITH into_db = 'r'`,
+				err:   `could not parse "a" as type int`,
+			},
+			{
+				query: `RESTORE TABLE data.bank FROM $1 WHERE id > 5 AND id < 5 WITH into_db = 'r'`,
+				err:   `WHERE clause id > 5 AND id < 5 matches no rows of table bank`,
+			},
+		} {
+			sqlDB.ExpectErr(t, tc.err, tc.query, localFoo)
+		}
+	})
+
+	sqlDB.Exec(t, `RESTORE TABLE data.bank FROM $1 WHERE id BETWEEN 10 AND 19 WITH into_db = 'r'`,
+		localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT min(id), max(id), count(*) FROM r.bank`,
+		[][]string{{"10", "19", "10"}})
+
+	// The secondary indexes of the table are rebuilt from the restored rows by a
+	// schema change job once the table is published.
+	sqlDB.CheckQueryResultsRetry(t,
+		`SELECT count(*) FROM [SHOW JOBS] WHERE job_type = 'SCHEMA CHANGE' AND status != 'succeeded'`,
+		[][]string{{"0"}})
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM r.bank@bank_balance_idx`, [][]string{{"10"}})
+
+	// Comparisons are translated to spans of descending primary keys as well.
+	sqlDB.Exec(t, `RESTORE TABLE data.events FROM $1 WHERE ts > 3 AND (ts <= 5) WITH into_db = 'r'`,
+		localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT ts, v FROM r.events ORDER BY ts DESC`, [][]string{{"5", "5"}, {"4", "4"}})
+}
+
+// TestRestoreNewTableName tests recovering rows that were deleted after they
+// were captured by a revision history backup into a table next to the one they
+// were deleted from.
+func TestRestoreNewTableName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 100
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	sqlDB.Exec(t, `CREATE TABLE data.other (id INT PRIMARY KEY)`)
+	var beforeDelete string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&beforeDelete)
+	sqlDB.Exec(t, `DELETE FROM data.bank WHERE id BETWEEN 20 AND 29`)
+	sqlDB.Exec(t, `BACKUP DATABASE data TO $1 WITH revision_history`, localFoo)
+
+	t.Run("new_table_name syntax checks", func(t *testing.T) {
+		expectedErr := "new_table_name can only be used for RESTORE TABLE with a single target table"
+		sqlDB.ExpectErr(t, expectedErr, `RESTORE FROM $1 WITH new_table_name = 'x'`, localFoo)
+		sqlDB.ExpectErr(t, expectedErr,
+			`RESTORE DATABASE data FROM $1 WITH new_table_name = 'x'`, localFoo)
+		sqlDB.ExpectErr(t, expectedErr,
+			`RESTORE TABLE data.bank, data.other FROM $1 WITH new_table_name = 'x'`, localFoo)
+		sqlDB.ExpectErr(t, `"new_table_name" can only be used when restoring a single table`,
+			`RESTORE TABLE data.* FROM $1 WITH new_table_name = 'x'`, localFoo)
+	})
+
+	// Should fail because the table still exists.
+	sqlDB.ExpectErr(t, `relation ".+" already exists`, `RESTORE TABLE data.bank FROM $1`, localFoo)
+
+	sqlDB.Exec(t, `RESTORE TABLE data.bank FROM $1 AS OF SYSTEM TIME `+beforeDelete+
+		` WHERE id BETWEEN 20 AND 29 WITH new_table_name = 'bank_recovered'`, localFoo)
+	sqlDB.CheckQueryResults(t, `SELECT min(id), max(id), count(*) FROM data.bank_recovered`,
+		[][]string{{"20", "29", "10"}})
+
+	sqlDB.Exec(t, `INSERT INTO data.bank SELECT * FROM data.bank_recovered`)
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM data.bank`, [][]string{{"100"}})
+}
+
 // TestRestoreRemappingOfExistingUDTInColExpr is a regression test for a nil
 // pointer exception when restoring tables that point to existing types. When
 // updating the back references of the existing types we would index into a map
@@ -0,0 +1,182 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/errors"
+)
+
+// BackupFileCheck describes an SST of a backup that was checked by
+// CheckBackupFiles.
+type BackupFileCheck struct {
+	// Layer is the index of the backup containing the file in the chain of
+	// backups that was checked.
+	Layer int
+	// Path is the path of the file, relative to the backup containing it.
+	Path string
+	// LocalityKV is the locality of the file in locality aware backups.
+	LocalityKV string
+	// FileBytes is the size of the file in external storage.
+	FileBytes int64
+	// Keys is the number of keys in the file. It is only set when the contents
+	// of the files are validated.
+	Keys int64
+}
+
+// backupFileRef groups the manifest entries referring to the same SST: the
+// backup processors write several spans to each file.
+type backupFileRef struct {
+	path       string
+	localityKV string
+	dataSize   int64
+}
+
+// CheckBackupFiles checks that every SST referenced by the manifests of a
+// chain of backups exists in external storage and is an SST that can be
+// opened, decrypting it with enc if set. The manifests themselves are
+// checked against their checksums when they are read.
+//
+// If validate is set, every key of the SSTs is also read, and the sizes of the
+// keys and values of each SST are checked against the sizes recorded for it
+// in the manifest. This catches files that were truncated or overwritten but
+// still have a valid footer, at the cost of reading all of the backup.
+//
+// The files of each backup are checked in the order of its manifest, and fn
+// is called with each file once it was checked. The first missing or
+// unreadable file stops the check and is returned as an error.
+func CheckBackupFiles(
+	ctx context.Context,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	user security.SQLUsername,
+	defaultURIs []string,
+	manifests []BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+	enc *jobspb.BackupEncryptionOptions,
+	validate bool,
+	fn func(BackupFileCheck) error,
+) error {
+	var encryption *roachpb.FileEncryptionOptions
+	stores := make(map[string]cloud.ExternalStorage)
+	defer func() {
+		for _, store := range stores {
+			_ = store.Close()
+		}
+	}()
+
+	for layer := range manifests {
+		var refs []backupFileRef
+		refIdx := make(map[string]int)
+		for _, f := range manifests[layer].Files {
+			i, ok := refIdx[f.Path]
+			if !ok {
+				i = len(refs)
+				refIdx[f.Path] = i
+				refs = append(refs, backupFileRef{path: f.Path, localityKV: f.LocalityKV})
+			}
+			refs[i].dataSize += f.EntryCounts.DataSize
+		}
+
+		for _, ref := range refs {
+			uri := defaultURIs[layer]
+			if layer < len(localityInfo) {
+				if localityURI, ok := localityInfo[layer].URIsByOriginalLocalityKV[ref.localityKV]; ok {
+					uri = localityURI
+				}
+			}
+			store, ok := stores[uri]
+			if !ok {
+				var err error
+				store, err = mkStore(ctx, uri, user)
+				if err != nil {
+					return errors.Wrapf(err, "creating external store for %s",
+						RedactURIForErrorMessage(uri))
+				}
+				stores[uri] = store
+			}
+			if enc != nil && encryption == nil {
+				key, err := getEncryptionKey(ctx, enc, store.Settings(), store.ExternalIOConf())
+				if err != nil {
+					return err
+				}
+				encryption = &roachpb.FileEncryptionOptions{Key: key}
+			}
+
+			check, err := checkBackupFile(ctx, store, ref, encryption, validate)
+			if err != nil {
+				return errors.Wrapf(err, "checking backup file %s in %s",
+					ref.path, RedactURIForErrorMessage(uri))
+			}
+			check.Layer = layer
+			if err := fn(check); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkBackupFile(
+	ctx context.Context,
+	store cloud.ExternalStorage,
+	ref backupFileRef,
+	encryption *roachpb.FileEncryptionOptions,
+	validate bool,
+) (BackupFileCheck, error) {
+	check := BackupFileCheck{Path: ref.path, LocalityKV: ref.localityKV}
+	// Not all external storages report missing files in Size, so open the file
+	// to check that it exists and get its size.
+	f, sz, err := store.ReadFileAt(ctx, ref.path, 0)
+	if err != nil {
+		if errors.Is(err, cloud.ErrFileDoesNotExist) {
+			return check, errors.Wrap(err, "file is missing")
+		}
+		return check, err
+	}
+	check.FileBytes = sz
+	if err := f.Close(ctx); err != nil {
+		return check, err
+	}
+
+	// Opening the SST reads its footer and index, which also authenticates them
+	// when the file is encrypted.
+	iter, err := storageccl.ExternalSSTReader(ctx, store, ref.path, encryption)
+	if err != nil {
+		return check, errors.Wrap(err, "opening file")
+	}
+	defer iter.Close()
+	if !validate {
+		return check, nil
+	}
+
+	var dataSize int64
+	for iter.SeekGE(storage.MVCCKey{Key: keys.MinKey}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return check, errors.Wrap(err, "reading file")
+		} else if !ok {
+			break
+		}
+		dataSize += int64(len(iter.UnsafeKey().Key) + len(iter.UnsafeValue()))
+		check.Keys++
+	}
+	if dataSize != ref.dataSize {
+		return check, errors.Errorf(
+			"file contains %d bytes of keys and values but the backup manifest expects %d",
+			dataSize, ref.dataSize)
+	}
+	return check, nil
+}
@@ -622,6 +622,28 @@ func DescriptorsMatchingTargets(
 		}
 	}
 
+	// Functions are not registered by name in the resolver since overloads
+	// share their name. They are included in the backup along with their
+	// database when the whole database is requested.
+	var functionIDs []descpb.ID
+	for id, desc := range r.DescByID {
+		fn, ok := desc.(catalog.FunctionDescriptor)
+		if !ok {
+			continue
+		}
+		if _, ok := alreadyRequestedDBs[fn.GetParentID()]; !ok {
+			continue
+		}
+		if err := catalog.FilterDescriptorState(fn, tree.CommonLookupFlags{}); err != nil {
+			continue
+		}
+		functionIDs = append(functionIDs, id)
+	}
+	sort.Slice(functionIDs, func(i, j int) bool { return functionIDs[i] < functionIDs[j] })
+	for _, id := range functionIDs {
+		ret.Descs = append(ret.Descs, r.DescByID[id])
+	}
+
 	return ret, nil
 }
 
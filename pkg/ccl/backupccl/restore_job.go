@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/ingesting"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
@@ -589,9 +590,13 @@ func isSchemaEmpty(
 
 // spansForAllRestoreTableIndexes returns non-overlapping spans for every index
 // and table passed in. They would normally overlap if any of them are
-// interleaved.
+// interleaved. Only the span in rowFilters is returned for the tables that
+// have one, since their secondary indexes are rebuilt after the restore.
 func spansForAllRestoreTableIndexes(
-	codec keys.SQLCodec, tables []catalog.TableDescriptor, revs []BackupManifest_DescriptorRevision,
+	codec keys.SQLCodec,
+	tables []catalog.TableDescriptor,
+	revs []BackupManifest_DescriptorRevision,
+	rowFilters map[descpb.ID]roachpb.Span,
 ) []roachpb.Span {
 
 	added := make(map[tableAndIndex]bool, len(tables))
@@ -601,6 +606,12 @@ func spansForAllRestoreTableIndexes(
 		if !table.IsPhysicalTable() {
 			continue
 		}
+		if span, ok := rowFilters[table.GetID()]; ok {
+			if err := sstIntervalTree.Insert(intervalSpan(span), false); err != nil {
+				panic(errors.NewAssertionErrorWithWrappedErrf(err, "row filter span"))
+			}
+			continue
+		}
 		for _, index := range table.ActiveIndexes() {
 			if err := sstIntervalTree.Insert(intervalSpan(table.IndexSpan(codec, index.GetID())), false); err != nil {
 				panic(errors.NewAssertionErrorWithWrappedErrf(err, "IndexSpan"))
@@ -618,7 +629,7 @@ func spansForAllRestoreTableIndexes(
 		// entire interval. DROPPED tables should never later become PUBLIC.
 		// TODO(pbardea): Consider and test the interaction between revision_history
 		// backups and OFFLINE tables.
-		rawTbl, _, _, _ := descpb.FromDescriptor(rev.Desc)
+		rawTbl, _, _, _, _ := descpb.FromDescriptor(rev.Desc)
 		if rawTbl != nil && !rawTbl.Dropped() {
 			tbl := tabledesc.NewBuilder(rawTbl).BuildImmutableTable()
 			// We only import spans for physical tables.
@@ -648,6 +659,18 @@ func spansForAllRestoreTableIndexes(
 	return spans
 }
 
+// getBackupTenantID returns the ID of the tenant in which the backup was taken,
+// which determines the codec that was used to encode its keys.
+func getBackupTenantID(manifest *BackupManifest) (roachpb.TenantID, error) {
+	if len(manifest.Spans) == 0 || manifest.HasTenants() {
+		return roachpb.SystemTenantID, nil
+	}
+	// If there are no tenant targets, then the entire keyspace covered by Spans
+	// must lie in 1 tenant.
+	_, tenantID, err := keys.DecodeTenantPrefix(manifest.Spans[0].Key)
+	return tenantID, err
+}
+
 func shouldPreRestore(table *tabledesc.Mutable) bool {
 	if table.GetParentID() != keys.SystemDatabaseID {
 		return false
@@ -672,6 +695,7 @@ func createImportingDescriptors(
 	var writtenTypes []catalog.TypeDescriptor
 	var schemas []*schemadesc.Mutable
 	var types []*typedesc.Mutable
+	var functions []*funcdesc.Mutable
 	// Store the tables as both the concrete mutable structs and the interface
 	// to deal with the lack of slice covariance in go. We want the slice of
 	// mutable descriptors for rewriting but ultimately want to return the
@@ -710,6 +734,13 @@ func createImportingDescriptors(
 		case catalog.TypeDescriptor:
 			mut := typedesc.NewBuilder(desc.TypeDesc()).BuildCreatedMutableType()
 			types = append(types, mut)
+		case catalog.FunctionDescriptor:
+			// Functions are only restored when their parent database is, so
+			// those without a rewrite are left behind.
+			if _, ok := details.DescriptorRewrites[desc.GetID()]; ok {
+				mut := funcdesc.NewBuilder(desc.FuncDesc()).BuildCreatedMutableFunction()
+				functions = append(functions, mut)
+			}
 		}
 	}
 
@@ -721,9 +752,17 @@ func createImportingDescriptors(
 	}
 
 	// We get the spans of the restoring tables _as they appear in the backup_,
-	// that is, in the 'old' keyspace, before we reassign the table IDs.
-	preRestoreSpans := spansForAllRestoreTableIndexes(backupCodec, preRestoreTables, nil)
-	postRestoreSpans := spansForAllRestoreTableIndexes(backupCodec, postRestoreTables, nil)
+	// that is, in the 'old' keyspace, before we reassign the table IDs. No data
+	// is restored for schema-only restores.
+	var preRestoreSpans, postRestoreSpans []roachpb.Span
+	if !details.SchemaOnly {
+		rowFilters := make(map[descpb.ID]roachpb.Span, len(details.RowFilters))
+		for _, f := range details.RowFilters {
+			rowFilters[f.TableID] = f.Span
+		}
+		preRestoreSpans = spansForAllRestoreTableIndexes(backupCodec, preRestoreTables, nil, rowFilters)
+		postRestoreSpans = spansForAllRestoreTableIndexes(backupCodec, postRestoreTables, nil, rowFilters)
+	}
 
 	log.Eventf(ctx, "starting restore for %d tables", len(mutableTables))
 
@@ -805,6 +844,14 @@ func createImportingDescriptors(
 		return nil, nil, err
 	}
 
+	if err := rewrite.FunctionDescs(functions, details.DescriptorRewrites); err != nil {
+		return nil, nil, err
+	}
+	writtenFunctions := make([]catalog.FunctionDescriptor, len(functions))
+	for i, fn := range functions {
+		writtenFunctions[i] = fn
+	}
+
 	// Set the new descriptors' states to offline.
 	for _, desc := range mutableTables {
 		desc.SetOffline("restoring")
@@ -815,6 +862,9 @@ func createImportingDescriptors(
 	for _, desc := range schemasToWrite {
 		desc.SetOffline("restoring")
 	}
+	for _, desc := range functions {
+		desc.SetOffline("restoring")
+	}
 	for _, desc := range mutableDatabases {
 		desc.SetOffline("restoring")
 	}
@@ -926,7 +976,7 @@ func createImportingDescriptors(
 			// Write the new descriptors which are set in the OFFLINE state.
 			if err := ingesting.WriteDescriptors(
 				ctx, p.ExecCfg().Codec, txn, p.User(), descsCol, databases, writtenSchemas, tables, writtenTypes,
-				details.DescriptorCoverage, nil /* extra */, restoreTempSystemDB,
+				writtenFunctions, details.DescriptorCoverage, nil /* extra */, restoreTempSystemDB,
 			); err != nil {
 				return errors.Wrapf(err, "restoring %d TableDescriptors from %d databases", len(tables), len(databases))
 			}
@@ -1083,6 +1133,10 @@ func createImportingDescriptors(
 			for i := range schemasToWrite {
 				details.SchemaDescs[i] = schemasToWrite[i].SchemaDesc()
 			}
+			details.FunctionDescs = make([]*descpb.FunctionDescriptor, len(functions))
+			for i := range functions {
+				details.FunctionDescs[i] = functions[i].FuncDesc()
+			}
 
 			// Update the job once all descs have been prepared for ingestion.
 			err := r.job.SetDetails(ctx, txn, details)
@@ -1260,15 +1314,11 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 	backupTenantID := roachpb.SystemTenantID
 
 	if len(sqlDescs) != 0 {
-		if len(latestBackupManifest.Spans) != 0 && !latestBackupManifest.HasTenants() {
-			// If there are no tenant targets, then the entire keyspace covered by
-			// Spans must lie in 1 tenant.
-			_, backupTenantID, err = keys.DecodeTenantPrefix(latestBackupManifest.Spans[0].Key)
-			if err != nil {
-				return err
-			}
-			backupCodec = keys.MakeSQLCodec(backupTenantID)
+		backupTenantID, err = getBackupTenantID(&latestBackupManifest)
+		if err != nil {
+			return err
 		}
+		backupCodec = keys.MakeSQLCodec(backupTenantID)
 	}
 
 	lastBackupIndex, err := getBackupIndexAtTime(backupManifests, details.EndTime)
@@ -1330,17 +1380,21 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 		}
 	}
 	var remappedStats []*stats.TableStatisticProto
-	backupStats, err := getStatisticsFromBackup(ctx, defaultStore, details.Encryption,
-		latestBackupManifest)
-	if err == nil {
-		remappedStats = remapRelevantStatistics(ctx, backupStats, details.DescriptorRewrites,
-			details.TableDescs)
-	} else {
-		// We don't want to fail the restore if we are unable to resolve statistics
-		// from the backup, since they can be recomputed after the restore has
-		// completed.
-		log.Warningf(ctx, "failed to resolve table statistics from backup during restore: %+v",
-			err.Error())
+	// The statistics in the backup do not describe tables whose data is not
+	// restored in full, so they are left to be recomputed for those.
+	if !details.SchemaOnly && len(details.RowFilters) == 0 {
+		backupStats, err := getStatisticsFromBackup(ctx, defaultStore, details.Encryption,
+			latestBackupManifest)
+		if err == nil {
+			remappedStats = remapRelevantStatistics(ctx, backupStats, details.DescriptorRewrites,
+				details.TableDescs)
+		} else {
+			// We don't want to fail the restore if we are unable to resolve
+			// statistics from the backup, since they can be recomputed after the
+			// restore has completed.
+			log.Warningf(ctx, "failed to resolve table statistics from backup during restore: %+v",
+				err.Error())
+		}
 	}
 
 	if len(details.TableDescs) == 0 && len(details.Tenants) == 0 && len(details.TypeDescs) == 0 {
@@ -1463,6 +1517,24 @@ func (r *restoreResumer) doResume(ctx context.Context, execCtx interface{}) erro
 		}
 		devalidateIndexes = bad
 	}
+	// The secondary indexes of the tables restored with a row filter were not
+	// restored, so they are rebuilt from the restored rows once published.
+	for _, f := range details.RowFilters {
+		tableID := details.DescriptorRewrites[f.TableID].ID
+		for _, desc := range details.TableDescs {
+			if desc.GetID() != tableID {
+				continue
+			}
+			if devalidateIndexes == nil {
+				devalidateIndexes = make(map[descpb.ID][]descpb.IndexID)
+			}
+			var indexIDs []descpb.IndexID
+			for _, idx := range tabledesc.NewBuilder(desc).BuildImmutableTable().PublicNonPrimaryIndexes() {
+				indexIDs = append(indexIDs, idx.GetID())
+			}
+			devalidateIndexes[tableID] = indexIDs
+		}
+	}
 
 	publishDescriptors := func(ctx context.Context, txn *kv.Txn, descsCol *descs.Collection) (err error) {
 		err = r.publishDescriptors(ctx, txn, p.ExecCfg(), p.User(), descsCol, details, devalidateIndexes)
@@ -1761,6 +1833,7 @@ func (r *restoreResumer) publishDescriptors(
 	newTables := make([]*descpb.TableDescriptor, 0, len(details.TableDescs))
 	newTypes := make([]*descpb.TypeDescriptor, 0, len(details.TypeDescs))
 	newSchemas := make([]*descpb.SchemaDescriptor, 0, len(details.SchemaDescs))
+	newFunctions := make([]*descpb.FunctionDescriptor, 0, len(details.FunctionDescs))
 	newDBs := make([]*descpb.DatabaseDescriptor, 0, len(details.DatabaseDescs))
 
 	// Go through the descriptors and find any declarative schema change jobs
@@ -1850,6 +1923,10 @@ func (r *restoreResumer) publishDescriptors(
 		sc := all.LookupDescriptorEntry(details.SchemaDescs[i].GetID()).(catalog.SchemaDescriptor)
 		newSchemas = append(newSchemas, sc.SchemaDesc())
 	}
+	for i := range details.FunctionDescs {
+		fn := all.LookupDescriptorEntry(details.FunctionDescs[i].GetID()).(catalog.FunctionDescriptor)
+		newFunctions = append(newFunctions, fn.FuncDesc())
+	}
 	for i := range details.DatabaseDescs {
 		db := all.LookupDescriptorEntry(details.DatabaseDescs[i].GetID()).(catalog.DatabaseDescriptor)
 		newDBs = append(newDBs, db.DatabaseDesc())
@@ -1879,6 +1956,7 @@ func (r *restoreResumer) publishDescriptors(
 	details.TableDescs = newTables
 	details.TypeDescs = newTypes
 	details.SchemaDescs = newSchemas
+	details.FunctionDescs = newFunctions
 	details.DatabaseDescs = newDBs
 	if err := r.job.SetDetails(ctx, txn, details); err != nil {
 		return errors.Wrap(err,
@@ -1912,6 +1990,10 @@ func prefetchDescriptors(
 		expVersion[details.SchemaDescs[i].GetID()] = details.SchemaDescs[i].GetVersion()
 		allDescIDs.Add(details.SchemaDescs[i].GetID())
 	}
+	for i := range details.FunctionDescs {
+		expVersion[details.FunctionDescs[i].GetID()] = details.FunctionDescs[i].GetVersion()
+		allDescIDs.Add(details.FunctionDescs[i].GetID())
+	}
 	for i := range details.DatabaseDescs {
 		expVersion[details.DatabaseDescs[i].GetID()] = details.DatabaseDescs[i].GetVersion()
 		allDescIDs.Add(details.DatabaseDescs[i].GetID())
@@ -2120,6 +2202,25 @@ func (r *restoreResumer) dropDescriptors(
 		descsCol.AddDeletedDescriptor(mutType.GetID())
 	}
 
+	// Drop the function descriptors that this restore created. Functions have
+	// no namespace entry, and their parent schemas were created by this restore
+	// as well, so only the descriptor itself needs to be removed.
+	for i := range details.FunctionDescs {
+		fnDesc := details.FunctionDescs[i]
+		mutFn, err := descsCol.GetMutableFunctionByID(ctx, txn, fnDesc.ID, tree.ObjectLookupFlags{
+			CommonLookupFlags: tree.CommonLookupFlags{
+				AvoidLeased:    true,
+				IncludeOffline: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		mutFn.SetDropped()
+		b.Del(catalogkeys.MakeDescMetadataKey(codec, fnDesc.ID))
+		descsCol.AddDeletedDescriptor(mutFn.GetID())
+	}
+
 	// Queue a GC job.
 	gcDetails := jobspb.SchemaChangeGCDetails{}
 	for _, tableID := range tablesToGC {
@@ -2153,6 +2254,9 @@ func (r *restoreResumer) dropDescriptors(
 	for _, schema := range details.SchemaDescs {
 		ignoredChildDescIDs[schema.ID] = struct{}{}
 	}
+	for _, fn := range details.FunctionDescs {
+		ignoredChildDescIDs[fn.ID] = struct{}{}
+	}
 	all, err := descsCol.GetAllDescriptors(ctx, txn)
 	if err != nil {
 		return err
@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/rewrite"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
@@ -65,6 +66,8 @@ const (
 	restoreOptSkipLocalitiesCheck       = "skip_localities_check"
 	restoreOptDebugPauseOn              = "debug_pause_on"
 	restoreOptAsTenant                  = "tenant"
+	restoreOptSchemaOnly                = "schema_only"
+	restoreOptNewTableName              = "new_table_name"
 
 	// The temporary database system tables will be restored into for full
 	// cluster backups.
@@ -165,6 +168,7 @@ func allocateDescriptorRewrites(
 	schemasByID map[descpb.ID]*schemadesc.Mutable,
 	tablesByID map[descpb.ID]*tabledesc.Mutable,
 	typesByID map[descpb.ID]*typedesc.Mutable,
+	functionsByID map[descpb.ID]*funcdesc.Mutable,
 	restoreDBs []catalog.DatabaseDescriptor,
 	descriptorCoverage tree.DescriptorCoverage,
 	opts tree.RestoreOptions,
@@ -530,6 +534,24 @@ func allocateDescriptorRewrites(
 			}
 		}
 
+		// Functions are only restored along with their database. Functions
+		// belonging to a database which is not being restored are skipped
+		// since their parent schema descriptor is not being rewritten.
+		for _, fn := range functionsByID {
+			if _, ok := descriptorRewrites[fn.ID]; ok {
+				continue
+			}
+			targetDB, err := resolveTargetDB(ctx, txn, p, databasesByID, intoDB, descriptorCoverage, fn)
+			if err != nil {
+				return err
+			}
+			if _, ok := restoreDBNames[targetDB]; ok {
+				needsNewParentIDs[targetDB] = append(needsNewParentIDs[targetDB], fn.ID)
+			} else if descriptorCoverage == tree.AllDescriptors {
+				descriptorRewrites[fn.ID] = &jobspb.DescriptorRewrite{ParentID: fn.ParentID}
+			}
+		}
+
 		// Iterate through typesByID to construct a remapping entry for each type.
 		for _, typ := range typesByID {
 			// If a descriptor has already been assigned a rewrite, then move on.
@@ -598,15 +620,18 @@ func allocateDescriptorRewrites(
 					// Create a rewrite entry for the type.
 					descriptorRewrites[typ.ID] = &jobspb.DescriptorRewrite{ParentID: parentID}
 
-					// Ensure that there isn't a collision with the array type name.
-					arrTyp := typesByID[typ.ArrayTypeID]
-					typeName := tree.NewUnqualifiedTypeName(arrTyp.GetName())
-					err = col.Direct().CheckObjectCollision(ctx, txn, parentID, getParentSchemaID(typ), typeName)
-					if err != nil {
-						return errors.Wrapf(err, "name collision for %q's array type", typ.Name)
+					// Domains don't have an array type.
+					if typ.ArrayTypeID != descpb.InvalidID {
+						// Ensure that there isn't a collision with the array type name.
+						arrTyp := typesByID[typ.ArrayTypeID]
+						typeName := tree.NewUnqualifiedTypeName(arrTyp.GetName())
+						err = col.Direct().CheckObjectCollision(ctx, txn, parentID, getParentSchemaID(typ), typeName)
+						if err != nil {
+							return errors.Wrapf(err, "name collision for %q's array type", typ.Name)
+						}
+						// Create the rewrite entry for the array type as well.
+						descriptorRewrites[arrTyp.ID] = &jobspb.DescriptorRewrite{ParentID: parentID}
 					}
-					// Create the rewrite entry for the array type as well.
-					descriptorRewrites[arrTyp.ID] = &jobspb.DescriptorRewrite{ParentID: parentID}
 				} else {
 					// If there was a name collision, we'll try to see if we can remap
 					// this type to the type existing in the cluster.
@@ -634,10 +659,12 @@ func allocateDescriptorRewrites(
 						ID:         existingType.GetID(),
 						ToExisting: true,
 					}
-					descriptorRewrites[typ.ArrayTypeID] = &jobspb.DescriptorRewrite{
-						ParentID:   existingType.GetParentID(),
-						ID:         existingType.GetArrayTypeID(),
-						ToExisting: true,
+					if typ.ArrayTypeID != descpb.InvalidID {
+						descriptorRewrites[typ.ArrayTypeID] = &jobspb.DescriptorRewrite{
+							ParentID:   existingType.GetParentID(),
+							ID:         existingType.GetArrayTypeID(),
+							ToExisting: true,
+						}
 					}
 				}
 				// If we're restoring to a public schema of database that already exists
@@ -647,7 +674,9 @@ func allocateDescriptorRewrites(
 					typ.GetParentSchemaID() == descpb.InvalidID {
 					publicSchemaID := parentDB.GetSchemaID(tree.PublicSchema)
 					descriptorRewrites[typ.ID].ParentSchemaID = publicSchemaID
-					descriptorRewrites[typ.ArrayTypeID].ParentSchemaID = publicSchemaID
+					if typ.ArrayTypeID != descpb.InvalidID {
+						descriptorRewrites[typ.ArrayTypeID].ParentSchemaID = publicSchemaID
+					}
 				}
 			}
 		}
@@ -728,6 +757,19 @@ func allocateDescriptorRewrites(
 		}
 	}
 
+	// Update the remapping information for function descriptors.
+	for _, fn := range functionsByID {
+		if _, ok := descriptorRewrites[fn.ID]; !ok {
+			continue
+		}
+		if descriptorCoverage == tree.AllDescriptors {
+			// The function doesn't need to be remapped.
+			descriptorRewrites[fn.ID].ID = fn.ID
+		} else {
+			descriptorsToRemap = append(descriptorsToRemap, fn)
+		}
+	}
+
 	// Update remapping information for schema descriptors.
 	for _, sc := range schemasByID {
 		if descriptorCoverage == tree.AllDescriptors {
@@ -776,6 +818,11 @@ func allocateDescriptorRewrites(
 	for _, typ := range typesByID {
 		rewriteObject(typ)
 	}
+	for _, fn := range functionsByID {
+		if _, ok := descriptorRewrites[fn.ID]; ok {
+			rewriteObject(fn)
+		}
+	}
 
 	return descriptorRewrites, nil
 }
@@ -927,7 +974,12 @@ func maybeUpgradeDescriptorsInBackupManifests(
 // them to be suitable for displaying in the jobs' description.
 // This includes redacting secrets from external storage URIs.
 func resolveOptionsForRestoreJobDescription(
-	opts tree.RestoreOptions, intoDB string, newDBName string, kmsURIs []string, incFrom []string,
+	opts tree.RestoreOptions,
+	intoDB string,
+	newDBName string,
+	newTableName string,
+	kmsURIs []string,
+	incFrom []string,
 ) (tree.RestoreOptions, error) {
 	if opts.IsDefault() {
 		return opts, nil
@@ -939,6 +991,7 @@ func resolveOptionsForRestoreJobDescription(
 		SkipMissingSequenceOwners: opts.SkipMissingSequenceOwners,
 		SkipMissingViews:          opts.SkipMissingViews,
 		Detached:                  opts.Detached,
+		SchemaOnly:                opts.SchemaOnly,
 	}
 
 	if opts.EncryptionPassphrase != nil {
@@ -953,6 +1006,10 @@ func resolveOptionsForRestoreJobDescription(
 		newOpts.NewDBName = tree.NewDString(newDBName)
 	}
 
+	if opts.NewTableName != nil {
+		newOpts.NewTableName = tree.NewDString(newTableName)
+	}
+
 	for _, uri := range kmsURIs {
 		redactedURI, err := cloud.RedactKMSURI(uri)
 		if err != nil {
@@ -980,6 +1037,7 @@ func restoreJobDescription(
 	opts tree.RestoreOptions,
 	intoDB string,
 	newDBName string,
+	newTableName string,
 	kmsURIs []string,
 ) (string, error) {
 	r := &tree.Restore{
@@ -987,13 +1045,14 @@ func restoreJobDescription(
 		DescriptorCoverage: restore.DescriptorCoverage,
 		AsOf:               restore.AsOf,
 		Targets:            restore.Targets,
+		Where:              restore.Where,
 		From:               make([]tree.StringOrPlaceholderOptList, len(restore.From)),
 	}
 
 	var options tree.RestoreOptions
 	var err error
 	if options, err = resolveOptionsForRestoreJobDescription(opts, intoDB, newDBName,
-		kmsURIs, incFrom); err != nil {
+		newTableName, kmsURIs, incFrom); err != nil {
 		return "", err
 	}
 	r.Options = options
@@ -1114,6 +1173,19 @@ func restorePlanHook(
 		}
 	}
 
+	var newTableNameFn func() (string, error)
+	if restoreStmt.Options.NewTableName != nil {
+		if len(restoreStmt.Targets.Tables) != 1 || restoreStmt.Targets.Databases != nil {
+			err = errors.New("new_table_name can only be used for RESTORE TABLE with a single target" +
+				" table")
+			return nil, nil, nil, false, err
+		}
+		newTableNameFn, err = p.TypeAsString(ctx, restoreStmt.Options.NewTableName, "RESTORE")
+		if err != nil {
+			return nil, nil, nil, false, err
+		}
+	}
+
 	var newTenantIDFn func() (*roachpb.TenantID, error)
 	if restoreStmt.Options.AsTenant != nil {
 		if restoreStmt.DescriptorCoverage == tree.AllDescriptors || !restoreStmt.Targets.TenantID.IsSet() {
@@ -1144,6 +1216,24 @@ func restorePlanHook(
 		}
 	}
 
+	if restoreStmt.Options.SchemaOnly {
+		if restoreStmt.DescriptorCoverage == tree.AllDescriptors || restoreStmt.SystemUsers ||
+			restoreStmt.Targets.TenantID.IsSet() {
+			err := errors.Errorf("%q can only be used when restoring tables or databases", restoreOptSchemaOnly)
+			return nil, nil, nil, false, err
+		}
+	}
+
+	if restoreStmt.Where != nil {
+		if len(restoreStmt.Targets.Tables) != 1 || restoreStmt.Targets.Databases != nil {
+			return nil, nil, nil, false, errors.New("WHERE can only be used when restoring a single table")
+		}
+		if restoreStmt.Options.SchemaOnly {
+			err := errors.Errorf("WHERE cannot be used with the %q option", restoreOptSchemaOnly)
+			return nil, nil, nil, false, err
+		}
+	}
+
 	fn := func(ctx context.Context, _ []sql.PlanNode, resultsCh chan<- tree.Datums) error {
 		// TODO(dan): Move this span into sql.
 		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
@@ -1166,7 +1256,15 @@ func restorePlanHook(
 			}
 		}
 
-		if err := checkPrivilegesForRestore(ctx, restoreStmt, p, from); err != nil {
+		var kms []string
+		if kmsFn != nil {
+			kms, err = kmsFn()
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := checkPrivilegesForRestore(ctx, restoreStmt, p, from, kms); err != nil {
 			return err
 		}
 
@@ -1187,14 +1285,6 @@ func restorePlanHook(
 			}
 		}
 
-		var kms []string
-		if kmsFn != nil {
-			kms, err = kmsFn()
-			if err != nil {
-				return err
-			}
-		}
-
 		var intoDB string
 		if intoDBFn != nil {
 			intoDB, err = intoDBFn()
@@ -1210,6 +1300,14 @@ func restorePlanHook(
 				return err
 			}
 		}
+
+		var newTableName string
+		if newTableNameFn != nil {
+			newTableName, err = newTableNameFn()
+			if err != nil {
+				return err
+			}
+		}
 		var newTenantID *roachpb.TenantID
 		if newTenantIDFn != nil {
 			newTenantID, err = newTenantIDFn()
@@ -1233,7 +1331,7 @@ func restorePlanHook(
 		}
 
 		return doRestorePlan(ctx, restoreStmt, p, from, incFrom, passphrase, kms, intoDB,
-			newDBName, newTenantID, endTime, resultsCh, subdir)
+			newDBName, newTableName, newTenantID, endTime, resultsCh, subdir)
 	}
 
 	if restoreStmt.Options.Detached {
@@ -1243,7 +1341,11 @@ func restorePlanHook(
 }
 
 func checkPrivilegesForRestore(
-	ctx context.Context, restoreStmt *tree.Restore, p sql.PlanHookState, from [][]string,
+	ctx context.Context,
+	restoreStmt *tree.Restore,
+	p sql.PlanHookState,
+	from [][]string,
+	kms []string,
 ) error {
 	hasAdmin, err := p.HasAdminRole(ctx)
 	if err != nil {
@@ -1294,7 +1396,7 @@ func checkPrivilegesForRestore(
 			}
 		}
 	}
-	return nil
+	return checkKMSAccessIsWithExplicitAuth(kms, "RESTORE")
 }
 
 func checkClusterRegions(
@@ -1357,6 +1459,7 @@ func doRestorePlan(
 	kms []string,
 	intoDB string,
 	newDBName string,
+	newTableName string,
 	newTenantID *roachpb.TenantID,
 	endTime hlc.Timestamp,
 	resultsCh chan<- tree.Datums,
@@ -1550,7 +1653,7 @@ func doRestorePlan(
 	for _, m := range mainBackupManifests {
 		spans := roachpb.Spans(m.Spans)
 		for i := range m.Descriptors {
-			table, _, _, _ := descpb.FromDescriptor(&m.Descriptors[i])
+			table, _, _, _, _ := descpb.FromDescriptor(&m.Descriptors[i])
 			if table == nil {
 				continue
 			}
@@ -1620,6 +1723,12 @@ func doRestorePlan(
 		}
 	}
 
+	if restoreStmt.Options.NewTableName != nil {
+		if err := renameTargetTableDescriptor(sqlDescs, newTableName); err != nil {
+			return err
+		}
+	}
+
 	var oldTenantID *roachpb.TenantID
 	if len(tenants) > 0 {
 		if !p.ExecCfg().Codec.ForSystemTenant() {
@@ -1662,6 +1771,7 @@ func doRestorePlan(
 	schemasByID := make(map[descpb.ID]*schemadesc.Mutable)
 	tablesByID := make(map[descpb.ID]*tabledesc.Mutable)
 	typesByID := make(map[descpb.ID]*typedesc.Mutable)
+	functionsByID := make(map[descpb.ID]*funcdesc.Mutable)
 
 	for _, desc := range sqlDescs {
 		switch desc := desc.(type) {
@@ -1673,6 +1783,8 @@ func doRestorePlan(
 			tablesByID[desc.ID] = desc
 		case *typedesc.Mutable:
 			typesByID[desc.ID] = desc
+		case *funcdesc.Mutable:
+			functionsByID[desc.ID] = desc
 		}
 	}
 
@@ -1707,6 +1819,31 @@ func doRestorePlan(
 		return err
 	}
 
+	var rowFilters []jobspb.RestoreDetails_RowFilter
+	if restoreStmt.Where != nil {
+		if len(filteredTablesByID) != 1 {
+			return errors.New("WHERE can only be used when restoring a single table")
+		}
+		backupTenantID, err := getBackupTenantID(&mainBackupManifests[len(mainBackupManifests)-1])
+		if err != nil {
+			return err
+		}
+		for _, table := range filteredTablesByID {
+			if !table.IsTable() {
+				return errors.Errorf("WHERE cannot be used when restoring %s, which is not a table",
+					table.GetName())
+			}
+			span, err := planRestoreRowFilter(
+				ctx, p, keys.MakeSQLCodec(backupTenantID), table, restoreStmt.Where)
+			if err != nil {
+				return err
+			}
+			rowFilters = append(rowFilters, jobspb.RestoreDetails_RowFilter{
+				TableID: table.GetID(), Span: span,
+			})
+		}
+	}
+
 	// When running a full cluster restore, we drop the defaultdb and postgres
 	// databases that are present in a new cluster.
 	// This is done so that they can be restored the same way any other user
@@ -1724,6 +1861,7 @@ func doRestorePlan(
 		schemasByID,
 		filteredTablesByID,
 		typesByID,
+		functionsByID,
 		restoreDBs,
 		restoreStmt.DescriptorCoverage,
 		restoreStmt.Options,
@@ -1747,6 +1885,7 @@ func doRestorePlan(
 		restoreStmt.Options,
 		intoDB,
 		newDBName,
+		newTableName,
 		kms)
 	if err != nil {
 		return err
@@ -1770,6 +1909,12 @@ func doRestorePlan(
 	for _, desc := range typesByID {
 		types = append(types, desc)
 	}
+	var functions []*funcdesc.Mutable
+	for i := range functionsByID {
+		if _, ok := descriptorRewrites[i]; ok {
+			functions = append(functions, functionsByID[i])
+		}
+	}
 
 	// We attempt to rewrite ID's in the collected type and table descriptors
 	// to catch errors during this process here, rather than in the job itself.
@@ -1785,6 +1930,9 @@ func doRestorePlan(
 	if err := rewrite.TypeDescs(types, descriptorRewrites); err != nil {
 		return err
 	}
+	if err := rewrite.FunctionDescs(functions, descriptorRewrites); err != nil {
+		return err
+	}
 	for i := range revalidateIndexes {
 		revalidateIndexes[i].TableID = descriptorRewrites[revalidateIndexes[i].TableID].ID
 	}
@@ -1795,6 +1943,12 @@ func doRestorePlan(
 		if restoreStmt.DescriptorCoverage == tree.AllDescriptors {
 			telemetry.Count("restore.full-cluster")
 		}
+		if restoreStmt.Options.SchemaOnly {
+			telemetry.Count("restore.schema-only")
+		}
+		if len(rowFilters) > 0 {
+			telemetry.Count("restore.row-filter")
+		}
 	}
 
 	encodedTables := make([]*descpb.TableDescriptor, len(tables))
@@ -1827,6 +1981,8 @@ func doRestorePlan(
 			RestoreSystemUsers: restoreStmt.SystemUsers,
 			PreRewriteTenantId: oldTenantID,
 			Validation:         jobspb.RestoreValidation_DefaultRestore,
+			SchemaOnly:         restoreStmt.Options.SchemaOnly,
+			RowFilters:         rowFilters,
 		},
 		Progress: jobspb.RestoreProgress{},
 	}
@@ -1954,6 +2110,29 @@ func renameTargetDatabaseDescriptor(
 	return nil
 }
 
+// renameTargetTableDescriptor updates the name of the table being restored to
+// the user specified new_table_name, which allows restoring a table, or a
+// subset of its rows, next to the table it was backed up from.
+func renameTargetTableDescriptor(sqlDescs []catalog.Descriptor, newTableName string) error {
+	var table *tabledesc.Mutable
+	for _, desc := range sqlDescs {
+		tbl, isTable := desc.(*tabledesc.Mutable)
+		if !isTable {
+			continue
+		}
+		if table != nil {
+			return errors.Errorf("%q can only be used when restoring a single table",
+				restoreOptNewTableName)
+		}
+		table = tbl
+	}
+	if table == nil {
+		return errors.AssertionFailedf("expected a table to rename to %q", newTableName)
+	}
+	table.SetName(newTableName)
+	return nil
+}
+
 // ensureMultiRegionDatabaseRestoreIsAllowed returns an error if restoring a
 // multi-region database is not allowed.
 func ensureMultiRegionDatabaseRestoreIsAllowed(
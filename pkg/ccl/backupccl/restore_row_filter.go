@@ -0,0 +1,220 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/keyside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// restoreRowFilter computes the span of the primary index of a table that
+// holds the rows matching the WHERE clause of a RESTORE.
+type restoreRowFilter struct {
+	p     sql.PlanHookState
+	table catalog.TableDescriptor
+	col   catalog.Column
+	dir   encoding.Direction
+	// indexSpan is the span of the primary index of the table in the keyspace
+	// of the backup.
+	indexSpan roachpb.Span
+}
+
+// planRestoreRowFilter returns the span of the primary index of table, in the
+// keyspace of the backup encoded with codec, holding the rows that match the
+// WHERE clause of a RESTORE.
+//
+// Since the restored data has to be a single span of the backup, only
+// comparisons of the first column of the primary key with constant
+// expressions are supported, optionally combined with AND.
+func planRestoreRowFilter(
+	ctx context.Context,
+	p sql.PlanHookState,
+	codec keys.SQLCodec,
+	table catalog.TableDescriptor,
+	where *tree.Where,
+) (roachpb.Span, error) {
+	// The secondary indexes of the table are rebuilt from the restored rows by
+	// a schema change once the table is published, which would conflict with
+	// any schema change that was in progress at the time of the backup.
+	if table.HasConcurrentSchemaChanges() || table.GetDeclarativeSchemaChangerState() != nil {
+		return roachpb.Span{}, errors.Errorf(
+			"cannot restore a subset of the rows of table %s as it has a schema change in progress",
+			table.GetName())
+	}
+	pk := table.GetPrimaryIndex()
+	col, err := table.FindColumnWithID(pk.GetKeyColumnID(0))
+	if err != nil {
+		return roachpb.Span{}, err
+	}
+	if col.GetType().UserDefined() {
+		return roachpb.Span{}, pgerror.Newf(pgcode.FeatureNotSupported,
+			"restoring a subset of the rows of a table whose primary key starts with column %q of a user-defined type",
+			col.GetName())
+	}
+	dir, err := pk.GetKeyColumnDirection(0).ToEncodingDirection()
+	if err != nil {
+		return roachpb.Span{}, err
+	}
+
+	f := restoreRowFilter{
+		p:         p,
+		table:     table,
+		col:       col,
+		dir:       dir,
+		indexSpan: table.IndexSpan(codec, pk.GetID()),
+	}
+	span, err := f.spanForExpr(ctx, where.Expr)
+	if err != nil {
+		return roachpb.Span{}, err
+	}
+	if span.Key.Compare(span.EndKey) >= 0 {
+		return roachpb.Span{}, errors.Errorf("WHERE clause %s matches no rows of table %s",
+			tree.AsString(where.Expr), table.GetName())
+	}
+	return span, nil
+}
+
+func (f *restoreRowFilter) spanForExpr(ctx context.Context, expr tree.Expr) (roachpb.Span, error) {
+	switch e := expr.(type) {
+	case *tree.ParenExpr:
+		return f.spanForExpr(ctx, e.Expr)
+
+	case *tree.AndExpr:
+		left, err := f.spanForExpr(ctx, e.Left)
+		if err != nil {
+			return roachpb.Span{}, err
+		}
+		right, err := f.spanForExpr(ctx, e.Right)
+		if err != nil {
+			return roachpb.Span{}, err
+		}
+		if left.Key.Compare(right.Key) < 0 {
+			left.Key = right.Key
+		}
+		if left.EndKey.Compare(right.EndKey) > 0 {
+			left.EndKey = right.EndKey
+		}
+		return left, nil
+
+	case *tree.ComparisonExpr:
+		if !f.isKeyColumn(e.Left) {
+			break
+		}
+		switch op := e.Operator.Symbol; op {
+		case treecmp.EQ, treecmp.LT, treecmp.LE, treecmp.GT, treecmp.GE:
+			key, err := f.encode(ctx, e.Right)
+			if err != nil {
+				return roachpb.Span{}, err
+			}
+			switch op {
+			case treecmp.EQ:
+				return f.span(key, true, key, true), nil
+			case treecmp.LT:
+				return f.span(nil, false, key, false), nil
+			case treecmp.LE:
+				return f.span(nil, false, key, true), nil
+			case treecmp.GT:
+				return f.span(key, false, nil, false), nil
+			default:
+				return f.span(key, true, nil, false), nil
+			}
+		}
+
+	case *tree.RangeCond:
+		if e.Not || e.Symmetric || !f.isKeyColumn(e.Left) {
+			break
+		}
+		from, err := f.encode(ctx, e.From)
+		if err != nil {
+			return roachpb.Span{}, err
+		}
+		to, err := f.encode(ctx, e.To)
+		if err != nil {
+			return roachpb.Span{}, err
+		}
+		return f.span(from, true, to, true), nil
+	}
+	return roachpb.Span{}, f.unsupported(expr)
+}
+
+// unsupported returns the error for a WHERE clause which doesn't translate to
+// a single span of the primary index. Filters on other columns can't be
+// applied by RESTORE, which ingests the backed up data without decoding it.
+func (f *restoreRowFilter) unsupported(expr tree.Expr) error {
+	return errors.WithHintf(
+		pgerror.Newf(pgcode.FeatureNotSupported,
+			"unsupported WHERE clause for RESTORE: %s: only comparisons of the first primary key "+
+				"column %s of table %s with constant values, combined with AND, are supported",
+			tree.AsString(expr), f.col.GetName(), f.table.GetName()),
+		"to recover rows matching other conditions, restore a range of %s containing them "+
+			"with the new_table_name option and copy them with INSERT INTO ... SELECT",
+		f.col.GetName())
+}
+
+// isKeyColumn returns whether expr refers to the first column of the primary
+// key.
+func (f *restoreRowFilter) isKeyColumn(expr tree.Expr) bool {
+	if p, ok := expr.(*tree.ParenExpr); ok {
+		return f.isKeyColumn(p.Expr)
+	}
+	n, ok := expr.(*tree.UnresolvedName)
+	return ok && n.NumParts == 1 && n.Parts[0] == f.col.GetName()
+}
+
+// encode evaluates expr as a value of the first column of the primary key and
+// returns its key encoding.
+func (f *restoreRowFilter) encode(ctx context.Context, expr tree.Expr) ([]byte, error) {
+	typed, err := tree.TypeCheckAndRequire(ctx, expr, f.p.SemaCtx(), f.col.GetType(), "RESTORE")
+	if err != nil {
+		return nil, err
+	}
+	d, err := typed.Eval(&f.p.ExtendedEvalContext().EvalContext)
+	if err != nil {
+		return nil, err
+	}
+	if d == tree.DNull {
+		return nil, errors.Errorf("cannot compare column %s of table %s with NULL",
+			f.col.GetName(), f.table.GetName())
+	}
+	return keyside.Encode(nil, d, f.dir)
+}
+
+// span returns the span of the primary index holding the rows whose first key
+// column lies between the given encoded values. Nil bounds are unbounded.
+func (f *restoreRowFilter) span(lo []byte, loInclusive bool, hi []byte, hiInclusive bool) roachpb.Span {
+	if f.dir == encoding.Descending {
+		// The key encoding of descending columns inverts the order of values.
+		lo, loInclusive, hi, hiInclusive = hi, hiInclusive, lo, loInclusive
+	}
+	span := f.indexSpan
+	if lo != nil {
+		span.Key = append(f.indexSpan.Key.Clone(), lo...)
+		if !loInclusive {
+			span.Key = span.Key.PrefixEnd()
+		}
+	}
+	if hi != nil {
+		span.EndKey = append(f.indexSpan.Key.Clone(), hi...)
+		if hiInclusive {
+			span.EndKey = span.EndKey.PrefixEnd()
+		}
+	}
+	return span
+}
@@ -182,6 +182,81 @@ func (m metadataSSTInfoReader) showBackup(
 	return nil
 }
 
+// fileCheckInfoReader checks the SSTs of the backups with CheckBackupFiles,
+// and shows a row for each of them.
+type fileCheckInfoReader struct {
+	inCol    tree.StringOrPlaceholderOptList
+	validate bool
+}
+
+var _ backupInfoReader = fileCheckInfoReader{}
+
+func (f fileCheckInfoReader) header() colinfo.ResultColumns {
+	return colinfo.ResultColumns{
+		{Name: "path", Typ: types.String},
+		{Name: "backup_type", Typ: types.String},
+		{Name: "locality", Typ: types.String},
+		{Name: "file_bytes", Typ: types.Int},
+		{Name: "keys", Typ: types.Int},
+	}
+}
+
+func (f fileCheckInfoReader) showBackup(
+	ctx context.Context,
+	mem *mon.BoundAccount,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	info backupInfo,
+	user security.SQLUsername,
+	resultsCh chan<- tree.Datums,
+) error {
+	var manifestDirs []string
+	var localityAware bool
+	if len(f.inCol) > 0 {
+		var err error
+		manifestDirs, err = getManifestDirs(info.subdir, info.defaultURIs)
+		if err != nil {
+			return err
+		}
+		localityAware = len(info.localityInfo[0].URIsByOriginalLocalityKV) > 0
+	}
+	push := func(check BackupFileCheck) error {
+		backupType := "full"
+		if info.manifests[check.Layer].isIncremental() {
+			backupType = "incremental"
+		}
+		filePath := check.Path
+		if manifestDirs != nil {
+			filePath = path.Join(manifestDirs[check.Layer], filePath)
+		}
+		locality := tree.DNull
+		if localityAware {
+			// A file stored in the default locality has a LocalityKV of NULL.
+			locality = tree.NewDString(check.LocalityKV)
+			if check.LocalityKV == "NULL" {
+				locality = tree.NewDString("default")
+			}
+		}
+		numKeys := tree.DNull
+		if f.validate {
+			numKeys = tree.NewDInt(tree.DInt(check.Keys))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case resultsCh <- tree.Datums{
+			tree.NewDString(filePath),
+			tree.NewDString(backupType),
+			locality,
+			tree.NewDInt(tree.DInt(check.FileBytes)),
+			numKeys,
+		}:
+			return nil
+		}
+	}
+	return CheckBackupFiles(ctx, mkStore, user, info.defaultURIs, info.manifests,
+		info.localityInfo, info.enc, f.validate, push)
+}
+
 // showBackupPlanHook implements PlanHookFn.
 func showBackupPlanHook(
 	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
@@ -217,6 +292,8 @@ func showBackupPlanHook(
 		backupOptIncStorage:       sql.KVStringOptRequireValue,
 		backupOptDebugMetadataSST: sql.KVStringOptRequireNoValue,
 		backupOptEncDir:           sql.KVStringOptRequireValue,
+		backupOptCheckFiles:       sql.KVStringOptRequireNoValue,
+		backupOptValidate:         sql.KVStringOptRequireNoValue,
 	}
 	optsFn, err := p.TypeAsStringOpts(ctx, backup.Options, expected)
 	if err != nil {
@@ -227,10 +304,23 @@ func showBackupPlanHook(
 		return nil, nil, nil, false, err
 	}
 
+	_, checkFiles := opts[backupOptCheckFiles]
+	_, validate := opts[backupOptValidate]
+	_, dumpSST := opts[backupOptDebugMetadataSST]
+	_, asJSON := opts[backupOptAsJSON]
+
 	var infoReader backupInfoReader
-	if _, dumpSST := opts[backupOptDebugMetadataSST]; dumpSST {
+	if checkFiles || validate {
+		if dumpSST || asJSON || backup.Details == tree.BackupRangeDetails ||
+			backup.Details == tree.BackupSchemaDetails {
+			return nil, nil, nil, false, errors.Newf(
+				"%s and %s are only supported by SHOW BACKUP and SHOW BACKUP FILES",
+				backupOptCheckFiles, backupOptValidate)
+		}
+		infoReader = fileCheckInfoReader{inCol: backup.InCollection, validate: validate}
+	} else if dumpSST {
 		infoReader = metadataSSTInfoReader{}
-	} else if _, asJSON := opts[backupOptAsJSON]; asJSON {
+	} else if asJSON {
 		infoReader = manifestInfoReader{shower: jsonShower}
 	} else {
 		var shower backupShower
@@ -401,6 +491,7 @@ you must pass the 'encryption_info_dir' parameter that points to the directory o
 			memReserved int64
 		)
 		info.subdir = computedSubdir
+		info.enc = encryption
 
 		mkStore := p.ExecCfg().DistSQLSrv.ExternalStorageFromURI
 
@@ -522,7 +613,7 @@ func backupShowerDefault(
 				schemaIDToName := make(map[descpb.ID]string)
 				schemaIDToName[keys.PublicSchemaIDForBackup] = catconstants.PublicSchemaName
 				for i := range manifest.Descriptors {
-					_, db, _, schema := descpb.FromDescriptor(&manifest.Descriptors[i])
+					_, db, _, schema, _ := descpb.FromDescriptor(&manifest.Descriptors[i])
 					if db != nil {
 						if _, ok := dbIDToName[db.ID]; !ok {
 							dbIDToName[db.ID] = db.Name
@@ -583,6 +674,12 @@ func backupShowerDefault(
 						dbID = desc.GetParentID()
 						parentSchemaName = schemaIDToName[desc.GetParentSchemaID()]
 						parentSchemaID = desc.GetParentSchemaID()
+					case catalog.FunctionDescriptor:
+						descriptorType = "function"
+						dbName = dbIDToName[desc.GetParentID()]
+						dbID = desc.GetParentID()
+						parentSchemaName = schemaIDToName[desc.GetParentSchemaID()]
+						parentSchemaID = desc.GetParentSchemaID()
 					case catalog.TableDescriptor:
 						descriptorType = "table"
 						dbName = dbIDToName[desc.GetParentID()]
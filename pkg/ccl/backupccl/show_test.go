@@ -837,3 +837,57 @@ func TestShowBackupPathIsCollectionRoot(t *testing.T) {
 	sqlDB.ExpectErr(t, "The specified path is the root of a backup collection.",
 		"SHOW BACKUP $1", localFoo)
 }
+
+func TestShowBackupCheckFiles(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 11
+	_, sqlDB, tempDir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+
+	const encrypted = localFoo + "/encrypted"
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, localFoo)
+	sqlDB.Exec(t, `INSERT INTO data.bank VALUES (100, 100, 'new')`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, localFoo)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1 WITH encryption_passphrase = 'abc'`, encrypted)
+
+	// Every SST of the chain is checked once, even if it holds several spans.
+	var expectedFiles int
+	sqlDB.QueryRow(t, `SELECT count(DISTINCT path) FROM [SHOW BACKUP FILES FROM LATEST IN $1]`,
+		localFoo).Scan(&expectedFiles)
+	require.Greater(t, expectedFiles, 1)
+
+	rows := sqlDB.QueryStr(t, `
+SELECT path, backup_type, file_bytes > 0, keys IS NULL
+FROM [SHOW BACKUP FROM LATEST IN $1 WITH check_files]`, localFoo)
+	require.Len(t, rows, expectedFiles)
+	sawIncremental := false
+	for _, row := range rows {
+		require.Equal(t, []string{"true", "true"}, row[2:])
+		sawIncremental = sawIncremental || row[1] == "incremental"
+	}
+	require.True(t, sawIncremental)
+
+	var files, totalKeys int
+	sqlDB.QueryRow(t, `SELECT count(*), sum(keys) FROM [SHOW BACKUP FILES FROM LATEST IN $1 WITH validate]`,
+		localFoo).Scan(&files, &totalKeys)
+	require.Equal(t, expectedFiles, files)
+	require.GreaterOrEqual(t, totalKeys, numAccounts+1)
+
+	sqlDB.QueryRow(t, `SELECT count(*) FROM [SHOW BACKUP FROM LATEST IN $1
+WITH validate, encryption_passphrase = 'abc']`, encrypted).Scan(&files)
+	require.Greater(t, files, 0)
+
+	sqlDB.ExpectErr(t, "check_files and validate are only supported by SHOW BACKUP and SHOW BACKUP FILES",
+		`SHOW BACKUP RANGES FROM LATEST IN $1 WITH check_files`, localFoo)
+
+	// Overwrite one of the files, and then delete it.
+	sstPath := filepath.Join(tempDir, "foo", rows[0][0])
+	require.NoError(t, ioutil.WriteFile(sstPath, []byte("not an sst"), 0644))
+	sqlDB.ExpectErr(t, "checking backup file .*: opening file",
+		`SHOW BACKUP FROM LATEST IN $1 WITH check_files`, localFoo)
+	require.NoError(t, os.Remove(sstPath))
+	sqlDB.ExpectErr(t, "checking backup file .*: file is missing",
+		`SHOW BACKUP FROM LATEST IN $1 WITH check_files`, localFoo)
+}
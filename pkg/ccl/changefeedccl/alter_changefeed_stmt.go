@@ -13,6 +13,7 @@ import (
 	"net/url"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupresolver"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -81,6 +82,38 @@ func alterChangefeedPlanHook(
 		}
 
 		newChangefeedStmt := &tree.CreateChangefeed{}
+		if prevDetails.Select != "" {
+			for _, cmd := range alterChangefeedStmt.Cmds {
+				switch cmd.(type) {
+				case *tree.AlterChangefeedAddTarget, *tree.AlterChangefeedDropTarget:
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						`cannot add or drop targets of a changefeed with a query`)
+				}
+			}
+			newChangefeedStmt.Select, err = cdceval.ParseSelectClause(prevDetails.Select)
+			if err != nil {
+				return err
+			}
+		}
+
+		dbTarget, isDatabaseFeed := databaseTarget(prevDetails)
+		if isDatabaseFeed {
+			for _, cmd := range alterChangefeedStmt.Cmds {
+				switch cmd.(type) {
+				case *tree.AlterChangefeedAddTarget, *tree.AlterChangefeedDropTarget:
+					return pgerror.Newf(pgcode.InvalidParameterValue,
+						`cannot add or drop targets of a changefeed on a database`)
+				}
+			}
+			// The database may have been renamed since the changefeed was created.
+			col := p.ExecCfg().CollectionFactory.MakeCollection(ctx, nil /* TemporarySchemaProvider */, nil /* monitor */)
+			dbDesc, err := col.Direct().MustGetDatabaseDescByID(
+				ctx, p.ExtendedEvalContext().Txn, dbTarget.DatabaseID)
+			if err != nil {
+				return err
+			}
+			newChangefeedStmt.Database = tree.Name(dbDesc.GetName())
+		}
 
 		prevOpts, err := getPrevOpts(job.Payload().Description, prevDetails.Opts)
 		if err != nil {
@@ -101,7 +134,9 @@ func alterChangefeedPlanHook(
 		if err != nil {
 			return err
 		}
-		newChangefeedStmt.Targets = newTargets
+		if !isDatabaseFeed {
+			newChangefeedStmt.Targets = newTargets
+		}
 
 		for key, value := range newOptions {
 			opt := tree.KVOption{Key: tree.Name(key)}
@@ -137,6 +172,14 @@ func alterChangefeedPlanHook(
 		// alteration, or it will be the high watermark of the job.
 		newDetails.StatementTime = newStatementTime
 
+		if isDatabaseFeed {
+			// The tables of a changefeed on a database are refreshed whenever it
+			// resumes. Keep the previous ones so that tables which were renamed
+			// keep their statement time names.
+			newDetails.Tables = prevDetails.Tables
+			newDetails.TargetSpecifications = prevDetails.TargetSpecifications
+		}
+
 		newPayload := job.Payload()
 		newPayload.Details = jobspb.WrapPayloadDetails(newDetails)
 		newPayload.Description = jobRecord.Description
@@ -370,6 +370,20 @@ func TestAlterChangefeedErrors(t *testing.T) {
 			`pq: cannot specify both "initial_scan" and "no_initial_scan"`,
 			fmt.Sprintf(`ALTER CHANGEFEED %d ADD bar WITH initial_scan, no_initial_scan`, feed.JobID()),
 		)
+
+		dbTestFeed := feed(t, f, `CREATE CHANGEFEED FOR DATABASE d`)
+		defer closeFeed(t, dbTestFeed)
+
+		dbFeed, ok := dbTestFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, dbFeed.JobID())
+		waitForJobStatus(sqlDB, t, dbFeed.JobID(), `paused`)
+
+		sqlDB.ExpectErr(t,
+			`pq: cannot add or drop targets of a changefeed on a database`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d DROP foo`, dbFeed.JobID()),
+		)
 	}
 
 	t.Run(`kafka`, kafkaTest(testFn))
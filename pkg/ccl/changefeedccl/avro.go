@@ -172,6 +172,9 @@ type avroMetadata map[string]interface{}
 type avroEnvelopeOpts struct {
 	beforeField, afterField     bool
 	updatedField, resolvedField bool
+	// debeziumFields adds the `op`, `source` and `ts_ms` fields of
+	// envelope=debezium.
+	debeziumFields bool
 }
 
 // avroEnvelopeRecord is an `avroRecord` that wraps a changed SQL row and some
@@ -181,6 +184,7 @@ type avroEnvelopeRecord struct {
 
 	opts          avroEnvelopeOpts
 	before, after *avroDataRecord
+	source        *avroRecord
 }
 
 // typeToAvroSchema converts a database type to an avro field
@@ -952,6 +956,37 @@ func envelopeToAvroSchema(
 		}
 		schema.Fields = append(schema.Fields, resolvedField)
 	}
+	if opts.debeziumFields {
+		schema.source = &avroRecord{
+			Name:       SQLNameToAvroName(topic) + `_source`,
+			SchemaType: `record`,
+			Namespace:  namespace,
+		}
+		for _, name := range debeziumSourceFields {
+			schema.source.Fields = append(schema.source.Fields, &avroSchemaField{
+				SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaString},
+				Name:       name,
+				Default:    nil,
+			})
+		}
+		schema.Fields = append(schema.Fields,
+			&avroSchemaField{
+				SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaString},
+				Name:       `op`,
+				Default:    nil,
+			},
+			&avroSchemaField{
+				SchemaType: []avroSchemaType{avroSchemaNull, schema.source},
+				Name:       `source`,
+				Default:    nil,
+			},
+			&avroSchemaField{
+				SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaLong},
+				Name:       `ts_ms`,
+				Default:    nil,
+			},
+		)
+	}
 
 	schemaJSON, err := json.Marshal(schema)
 	if err != nil {
@@ -1014,6 +1049,29 @@ func (r *avroEnvelopeRecord) BinaryFromRow(
 			native[`resolved`] = goavro.Union(avroUnionKey(avroSchemaString), ts.AsOfSystemTime())
 		}
 	}
+	if r.opts.debeziumFields {
+		native[`op`], native[`source`], native[`ts_ms`] = nil, nil, nil
+		if op, ok := meta[`op`]; ok {
+			delete(meta, `op`)
+			native[`op`] = goavro.Union(avroUnionKey(avroSchemaString), op)
+		}
+		if s, ok := meta[`source`]; ok {
+			delete(meta, `source`)
+			source, ok := s.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf(`unknown metadata source type: %T`, s)
+			}
+			sourceNative := make(map[string]interface{}, len(source))
+			for k, v := range source {
+				sourceNative[k] = goavro.Union(avroUnionKey(avroSchemaString), v)
+			}
+			native[`source`] = goavro.Union(avroUnionKey(r.source), sourceNative)
+		}
+		if ts, ok := meta[`ts_ms`]; ok {
+			delete(meta, `ts_ms`)
+			native[`ts_ms`] = goavro.Union(avroUnionKey(avroSchemaLong), ts)
+		}
+	}
 	for k := range meta {
 		return nil, errors.AssertionFailedf(`unhandled meta key: %s`, k)
 	}
@@ -0,0 +1,439 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdceval
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// PrevRowName is the name under which a CDC query refers to the value of the
+// row before the change, e.g. `WHERE status != cdc_prev.status`. It is only
+// available if the changefeed has the diff option.
+const PrevRowName = "cdc_prev"
+
+// Row is a row of the changefeed's table, as decoded by the changefeed.
+type Row struct {
+	// Desc is the table descriptor which is valid for interpreting Datums.
+	Desc catalog.TableDescriptor
+	// Datums has the values of the public columns of Desc.
+	Datums rowenc.EncDatumRow
+	// Deleted is set if the row is a deletion, or is missing.
+	Deleted bool
+}
+
+// Evaluator evaluates the projection and the filter of a CDC query, i.e. the
+// SELECT clause of a `CREATE CHANGEFEED ... AS SELECT ... FROM t WHERE ...`
+// statement, on the rows emitted by the changefeed.
+//
+// The query is compiled for each version of the table descriptor that it is
+// evaluated against, so that the schema changes of the table are taken into
+// account.
+type Evaluator struct {
+	sc       *tree.SelectClause
+	evalCtx  *tree.EvalContext
+	withDiff bool
+
+	// compiled is the query compiled for the last table descriptor the
+	// Evaluator was used with.
+	compiled *compiledQuery
+	alloc    tree.DatumAlloc
+}
+
+// ParseSelectClause parses the serialized SELECT clause of a CDC query.
+func ParseSelectClause(query string) (*tree.SelectClause, error) {
+	stmt, err := parser.ParseOne(query)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, errors.AssertionFailedf("expected a SELECT statement, got %T", stmt.AST)
+	}
+	sc, ok := sel.Select.(*tree.SelectClause)
+	if !ok {
+		return nil, errors.AssertionFailedf("expected a SELECT clause, got %T", sel.Select)
+	}
+	return sc, nil
+}
+
+// NewEvaluator returns an Evaluator for the given SELECT clause. withDiff
+// indicates whether the previous value of the rows is available, in which case
+// the query can refer to it as cdc_prev.
+func NewEvaluator(sc *tree.SelectClause, evalCtx *tree.EvalContext, withDiff bool) *Evaluator {
+	return &Evaluator{
+		sc:       sc,
+		evalCtx:  evalCtx,
+		withDiff: withDiff,
+	}
+}
+
+// Validate checks that the query is valid for the given table descriptor.
+func (e *Evaluator) Validate(ctx context.Context, desc catalog.TableDescriptor) error {
+	_, err := e.compile(ctx, desc)
+	return err
+}
+
+// ColumnNames returns the names of the columns produced by the query for the
+// given table descriptor.
+func (e *Evaluator) ColumnNames(
+	ctx context.Context, desc catalog.TableDescriptor,
+) ([]string, error) {
+	q, err := e.compile(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	return q.names, nil
+}
+
+// Eval evaluates the query on a changed row. It returns false if the row does
+// not match the WHERE clause, and otherwise the projected values, which
+// correspond to ColumnNames.
+//
+// Deletions are not evaluated, since the values of a deleted row are not
+// known; the caller is expected to emit them as they are.
+func (e *Evaluator) Eval(ctx context.Context, updated, prev Row) (bool, tree.Datums, error) {
+	if updated.Deleted {
+		return false, nil, errors.AssertionFailedf("cannot evaluate a CDC query on a deleted row")
+	}
+	q, err := e.compile(ctx, updated.Desc)
+	if err != nil {
+		return false, nil, err
+	}
+	if err := q.container.setRow(&e.alloc, updated, prev); err != nil {
+		return false, nil, err
+	}
+
+	e.evalCtx.PushIVarContainer(q.container)
+	defer e.evalCtx.PopIVarContainer()
+	if q.where != nil {
+		d, err := q.where.Eval(e.evalCtx)
+		if err != nil {
+			return false, nil, err
+		}
+		if d != tree.DBoolTrue {
+			return false, nil, nil
+		}
+	}
+	projection := make(tree.Datums, len(q.exprs))
+	for i, expr := range q.exprs {
+		if projection[i], err = expr.Eval(e.evalCtx); err != nil {
+			return false, nil, err
+		}
+	}
+	return true, projection, nil
+}
+
+// compiledQuery is a CDC query with its column references resolved against a
+// version of the table descriptor.
+type compiledQuery struct {
+	tableID   descpb.ID
+	version   descpb.DescriptorVersion
+	names     []string
+	exprs     []tree.TypedExpr
+	where     tree.TypedExpr
+	container *rowContainer
+}
+
+// compile returns the query compiled for the given table descriptor, reusing
+// the previous compilation if the descriptor did not change.
+func (e *Evaluator) compile(
+	ctx context.Context, desc catalog.TableDescriptor,
+) (*compiledQuery, error) {
+	if q := e.compiled; q != nil && q.tableID == desc.GetID() && q.version == desc.GetVersion() {
+		return q, nil
+	}
+	if len(e.sc.From.Tables) != 1 {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"CDC queries must select from exactly one table")
+	}
+	tn, err := selectedTable(e.sc.From.Tables[0])
+	if err != nil {
+		return nil, err
+	}
+	if e.sc.Distinct || e.sc.DistinctOn != nil || e.sc.GroupBy != nil ||
+		e.sc.Having != nil || e.sc.Window != nil || e.sc.From.AsOf.Expr != nil {
+		return nil, pgerror.New(pgcode.FeatureNotSupported,
+			"CDC queries only support projections and a WHERE clause")
+	}
+
+	cols := desc.PublicColumns()
+	q := &compiledQuery{
+		tableID:   desc.GetID(),
+		version:   desc.GetVersion(),
+		container: newRowContainer(cols),
+	}
+	resultCols := colinfo.ResultColumnsFromColumns(desc.GetID(), cols)
+	v := resolver{
+		withDiff:   e.withDiff,
+		numCols:    len(cols),
+		ivarHelper: tree.MakeIndexedVarHelper(q.container, 2*len(cols)),
+		current: colinfo.ColumnResolver{
+			Source: colinfo.NewSourceInfoForSingleTable(*tn, resultCols),
+		},
+		prev: colinfo.ColumnResolver{
+			Source: colinfo.NewSourceInfoForSingleTable(
+				tree.MakeUnqualifiedTableName(PrevRowName), resultCols),
+		},
+	}
+
+	semaCtx := tree.MakeSemaContext()
+	semaCtx.IVarContainer = q.container
+	if sd := e.evalCtx.SessionData(); sd != nil {
+		semaCtx.SearchPath = sd.SearchPath
+	}
+	semaCtx.Properties.Require("CDC queries",
+		tree.RejectSpecial|tree.RejectSubqueries|tree.RejectVolatileFunctions)
+
+	for _, target := range e.sc.Exprs {
+		targets, err := expandStar(target, tn, cols)
+		if err != nil {
+			return nil, err
+		}
+		for _, target := range targets {
+			name, err := tree.GetRenderColName(semaCtx.SearchPath, target)
+			if err != nil {
+				return nil, err
+			}
+			expr, err := v.resolveNames(target.Expr)
+			if err != nil {
+				return nil, err
+			}
+			typedExpr, err := tree.TypeCheck(ctx, expr, &semaCtx, types.Any)
+			if err != nil {
+				return nil, err
+			}
+			q.names = append(q.names, name)
+			q.exprs = append(q.exprs, typedExpr)
+		}
+	}
+
+	if e.sc.Where != nil {
+		expr, err := v.resolveNames(e.sc.Where.Expr)
+		if err != nil {
+			return nil, err
+		}
+		q.where, err = tree.TypeCheckAndRequire(ctx, expr, &semaCtx, types.Bool, "WHERE")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	e.compiled = q
+	return q, nil
+}
+
+// selectedTable returns the name of the table a CDC query selects from.
+func selectedTable(expr tree.TableExpr) (*tree.TableName, error) {
+	if aliased, ok := expr.(*tree.AliasedTableExpr); ok &&
+		aliased.As.Alias == "" && aliased.IndexFlags == nil && !aliased.Ordinality {
+		expr = aliased.Expr
+	}
+	if tn, ok := expr.(*tree.TableName); ok {
+		return tn, nil
+	}
+	return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+		"CDC queries cannot select from %s", tree.AsString(expr))
+}
+
+// expandStar expands `*` and `<table>.*` into the visible columns of the
+// table. Other targets are returned as they are.
+func expandStar(
+	target tree.SelectExpr, tn *tree.TableName, cols []catalog.Column,
+) ([]tree.SelectExpr, error) {
+	vn, ok := target.Expr.(tree.VarName)
+	if !ok {
+		return []tree.SelectExpr{target}, nil
+	}
+	vn, err := vn.NormalizeVarName()
+	if err != nil {
+		return nil, err
+	}
+	switch t := vn.(type) {
+	case tree.UnqualifiedStar:
+	case *tree.AllColumnsSelector:
+		if t.TableName.NumParts == 1 && t.TableName.Parts[0] == PrevRowName {
+			return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+				"%s.* is not supported, select the columns of %s individually",
+				PrevRowName, PrevRowName)
+		}
+		if t.TableName.Parts[0] != tn.Table() {
+			return nil, pgerror.Newf(pgcode.UndefinedTable,
+				"no data source matches pattern: %s", tree.AsString(t))
+		}
+	default:
+		return []tree.SelectExpr{{Expr: vn, As: target.As}}, nil
+	}
+	if target.As != "" {
+		return nil, pgerror.New(pgcode.Syntax, "\"*\" cannot be aliased")
+	}
+	var expanded []tree.SelectExpr
+	for _, col := range cols {
+		if col.IsHidden() {
+			continue
+		}
+		expanded = append(expanded, tree.SelectExpr{
+			Expr: &tree.ColumnItem{ColumnName: col.ColName()},
+		})
+	}
+	return expanded, nil
+}
+
+// resolver replaces the column references of a CDC query with IndexedVars.
+// The IndexedVars [0, numCols) refer to the columns of the changed row, and
+// [numCols, 2*numCols) to the columns of the previous value of the row.
+type resolver struct {
+	withDiff   bool
+	numCols    int
+	ivarHelper tree.IndexedVarHelper
+	current    colinfo.ColumnResolver
+	prev       colinfo.ColumnResolver
+	err        error
+}
+
+var _ tree.Visitor = &resolver{}
+
+func (v *resolver) resolveNames(expr tree.Expr) (tree.Expr, error) {
+	v.err = nil
+	expr, _ = tree.WalkExpr(v, expr)
+	return expr, v.err
+}
+
+// VisitPre implements the tree.Visitor interface.
+func (v *resolver) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.err != nil {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.IndexedVar:
+		v.err = pgerror.Newf(pgcode.FeatureNotSupported,
+			"ordinal column references are not supported in CDC queries")
+		return false, expr
+
+	case *tree.UnresolvedName:
+		vn, err := t.NormalizeVarName()
+		if err != nil {
+			v.err = err
+			return false, expr
+		}
+		return v.VisitPre(vn)
+
+	case *tree.ColumnItem:
+		r, offset := &v.current, 0
+		if t.TableName != nil && t.TableName.NumParts == 1 && t.TableName.Parts[0] == PrevRowName {
+			if !v.withDiff {
+				v.err = pgerror.Newf(pgcode.InvalidParameterValue,
+					"%s can only be used in changefeeds with the diff option", PrevRowName)
+				return false, expr
+			}
+			r, offset = &v.prev, v.numCols
+		}
+		if _, err := colinfo.ResolveColumnItem(context.TODO(), r, t); err != nil {
+			v.err = err
+			return false, expr
+		}
+		return false, v.ivarHelper.IndexedVar(r.ResolverState.ColIdx + offset)
+
+	case tree.UnqualifiedStar, *tree.AllColumnsSelector:
+		v.err = pgerror.Newf(pgcode.Syntax, "%q is not allowed in this context", tree.AsString(t))
+		return false, expr
+	}
+	return true, expr
+}
+
+// VisitPost implements the tree.Visitor interface.
+func (*resolver) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// rowContainer is the tree.IndexedVarContainer which holds the values of the
+// changed row followed by the previous values of the row.
+type rowContainer struct {
+	cols   []catalog.Column
+	datums tree.Datums
+}
+
+var _ tree.IndexedVarContainer = &rowContainer{}
+
+func newRowContainer(cols []catalog.Column) *rowContainer {
+	return &rowContainer{
+		cols:   cols,
+		datums: make(tree.Datums, 2*len(cols)),
+	}
+}
+
+// setRow decodes the values of the changed row and of its previous value into
+// the container. The previous value is mapped to the columns of the changed
+// row by column ID, since it may have been written with an older version of
+// the table descriptor; the columns it does not have are NULL.
+func (c *rowContainer) setRow(alloc *tree.DatumAlloc, updated, prev Row) error {
+	n := len(c.cols)
+	for i := range c.datums {
+		c.datums[i] = tree.DNull
+	}
+	if err := decodeRow(alloc, updated, c.datums[:n], c.cols); err != nil {
+		return err
+	}
+	if prev.Datums == nil || prev.Deleted {
+		return nil
+	}
+	return decodeRow(alloc, prev, c.datums[n:], c.cols)
+}
+
+// decodeRow decodes the values of row into datums, which correspond to cols.
+func decodeRow(
+	alloc *tree.DatumAlloc, row Row, datums tree.Datums, cols []catalog.Column,
+) error {
+	var colIdxByID catalog.TableColMap
+	for i, col := range row.Desc.PublicColumns() {
+		colIdxByID.Set(col.GetID(), i)
+	}
+	for i, col := range cols {
+		idx, ok := colIdxByID.Get(col.GetID())
+		if !ok || idx >= len(row.Datums) || row.Datums[idx].IsUnset() {
+			continue
+		}
+		ed := row.Datums[idx]
+		if err := ed.EnsureDecoded(col.GetType(), alloc); err != nil {
+			return err
+		}
+		datums[i] = ed.Datum
+	}
+	return nil
+}
+
+// IndexedVarEval implements the tree.IndexedVarContainer interface.
+func (c *rowContainer) IndexedVarEval(idx int, _ *tree.EvalContext) (tree.Datum, error) {
+	return c.datums[idx], nil
+}
+
+// IndexedVarResolvedType implements the tree.IndexedVarContainer interface.
+func (c *rowContainer) IndexedVarResolvedType(idx int) *types.T {
+	return c.cols[idx%len(c.cols)].GetType()
+}
+
+// IndexedVarNodeFormatter implements the tree.IndexedVarContainer interface.
+func (c *rowContainer) IndexedVarNodeFormatter(idx int) tree.NodeFormatter {
+	n := tree.Name(c.cols[idx%len(c.cols)].GetName())
+	if idx < len(c.cols) {
+		return &n
+	}
+	return &tree.ColumnItem{
+		TableName:  &tree.UnresolvedObjectName{NumParts: 1, Parts: [3]string{PrevRowName}},
+		ColumnName: n,
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdceval
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestTable(t *testing.T, schema string) catalog.TableDescriptor {
+	desc, err := sql.CreateTestTableDescriptor(
+		context.Background(), 50 /* parentID */, descpb.ID(52), schema,
+		catpb.NewBasePrivilegeDescriptor(security.RootUserName()),
+	)
+	require.NoError(t, err)
+	return desc.ImmutableCopy().(catalog.TableDescriptor)
+}
+
+func makeTestRow(desc catalog.TableDescriptor, datums ...tree.Datum) Row {
+	row := Row{Desc: desc}
+	for i, col := range desc.PublicColumns() {
+		row.Datums = append(row.Datums, rowenc.DatumToEncDatum(col.GetType(), datums[i]))
+	}
+	return row
+}
+
+func TestEvaluator(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+
+	desc := makeTestTable(t, `CREATE TABLE foo (a INT PRIMARY KEY, status STRING, c INT)`)
+	row := func(a int, status string, c int) Row {
+		return makeTestRow(desc, tree.NewDInt(tree.DInt(a)), tree.NewDString(status), tree.NewDInt(tree.DInt(c)))
+	}
+	noPrev := Row{}
+
+	for _, tc := range []struct {
+		name     string
+		query    string
+		withDiff bool
+		updated  Row
+		prev     Row
+		names    []string
+		expected tree.Datums // nil if the row is filtered out
+	}{
+		{
+			name:     "star",
+			query:    `SELECT * FROM foo`,
+			updated:  row(1, "open", 10),
+			prev:     noPrev,
+			names:    []string{"a", "status", "c"},
+			expected: tree.Datums{tree.NewDInt(1), tree.NewDString("open"), tree.NewDInt(10)},
+		},
+		{
+			name:     "projection",
+			query:    `SELECT a, c + 1 AS next, upper(status) FROM foo`,
+			updated:  row(1, "open", 10),
+			prev:     noPrev,
+			names:    []string{"a", "next", "upper"},
+			expected: tree.Datums{tree.NewDInt(1), tree.NewDInt(11), tree.NewDString("OPEN")},
+		},
+		{
+			name:     "filter matches",
+			query:    `SELECT foo.a FROM foo WHERE status = 'closed'`,
+			updated:  row(1, "closed", 10),
+			prev:     noPrev,
+			names:    []string{"a"},
+			expected: tree.Datums{tree.NewDInt(1)},
+		},
+		{
+			name:    "filter does not match",
+			query:   `SELECT a FROM foo WHERE status = 'closed'`,
+			updated: row(1, "open", 10),
+			prev:    noPrev,
+			names:   []string{"a"},
+		},
+		{
+			name:     "previous row changed",
+			query:    `SELECT a, cdc_prev.status AS prev_status FROM foo WHERE status != cdc_prev.status`,
+			withDiff: true,
+			updated:  row(1, "closed", 10),
+			prev:     row(1, "open", 10),
+			names:    []string{"a", "prev_status"},
+			expected: tree.Datums{tree.NewDInt(1), tree.NewDString("open")},
+		},
+		{
+			name:     "previous row unchanged",
+			query:    `SELECT a FROM foo WHERE status != cdc_prev.status`,
+			withDiff: true,
+			updated:  row(1, "open", 11),
+			prev:     row(1, "open", 10),
+			names:    []string{"a"},
+		},
+		{
+			name:     "previous row missing",
+			query:    `SELECT a FROM foo WHERE cdc_prev.a IS NULL`,
+			withDiff: true,
+			updated:  row(1, "open", 10),
+			prev:     Row{Desc: desc, Deleted: true},
+			names:    []string{"a"},
+			expected: tree.Datums{tree.NewDInt(1)},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sc, err := ParseSelectClause(tc.query)
+			require.NoError(t, err)
+			e := NewEvaluator(sc, &evalCtx, tc.withDiff)
+			names, err := e.ColumnNames(ctx, desc)
+			require.NoError(t, err)
+			require.Equal(t, tc.names, names)
+
+			matches, projection, err := e.Eval(ctx, tc.updated, tc.prev)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected != nil, matches)
+			if matches {
+				require.Equal(t, len(tc.expected), len(projection))
+				for i := range tc.expected {
+					require.Zero(t, tc.expected[i].Compare(&evalCtx, projection[i]),
+						"expected %s, got %s", tc.expected[i], projection[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluatorErrors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	evalCtx := tree.MakeTestingEvalContext(st)
+	defer evalCtx.Stop(ctx)
+
+	desc := makeTestTable(t, `CREATE TABLE foo (a INT PRIMARY KEY, status STRING)`)
+
+	for _, tc := range []struct {
+		query string
+		err   string
+	}{
+		{`SELECT d FROM foo`, `column "d" does not exist`},
+		{`SELECT a FROM foo WHERE status`, `argument of WHERE must be type bool`},
+		{`SELECT a FROM foo WHERE status != cdc_prev.status`,
+			`cdc_prev can only be used in changefeeds with the diff option`},
+		{`SELECT sum(a) FROM foo`, `aggregate functions are not allowed in CDC queries`},
+		{`SELECT a FROM foo WHERE random() > 0.5`, `volatile functions are not allowed in CDC queries`},
+		{`SELECT a FROM foo WHERE a IN (SELECT 1)`, `subqueries are not allowed in CDC queries`},
+		{`SELECT bar.* FROM foo`, `no data source matches pattern: bar.*`},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			sc, err := ParseSelectClause(tc.query)
+			require.NoError(t, err)
+			err = NewEvaluator(sc, &evalCtx, false /* withDiff */).Validate(ctx, desc)
+			require.True(t, testutils.IsError(err, tc.err), "expected %q, got %v", tc.err, err)
+		})
+	}
+}
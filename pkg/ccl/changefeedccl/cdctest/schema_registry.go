@@ -28,9 +28,10 @@ type SchemaRegistry struct {
 	server *httptest.Server
 	mu     struct {
 		syncutil.Mutex
-		idAlloc  int32
-		schemas  map[int32]string
-		subjects map[string]int32
+		idAlloc     int32
+		schemas     map[int32]string
+		schemaTypes map[int32]string
+		subjects    map[string]int32
 	}
 }
 
@@ -58,6 +59,7 @@ func StartTestSchemaRegistryWithTLS(certificate *tls.Certificate) (*SchemaRegist
 func makeTestSchemaRegistry() *SchemaRegistry {
 	r := &SchemaRegistry{}
 	r.mu.schemas = make(map[int32]string)
+	r.mu.schemaTypes = make(map[int32]string)
 	r.mu.subjects = make(map[string]int32)
 	r.server = httptest.NewUnstartedServer(http.HandlerFunc(r.requestHandler))
 	return r
@@ -90,13 +92,25 @@ func (r *SchemaRegistry) SchemaForSubject(subject string) string {
 	return r.mu.schemas[r.mu.subjects[subject]]
 }
 
-func (r *SchemaRegistry) registerSchema(subject string, schema string) int32 {
+// SchemaTypeForSubject returns the type of the schema of the specified
+// subject, which is AVRO unless another type was registered.
+func (r *SchemaRegistry) SchemaTypeForSubject(subject string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if schemaType := r.mu.schemaTypes[r.mu.subjects[subject]]; schemaType != "" {
+		return schemaType
+	}
+	return "AVRO"
+}
+
+func (r *SchemaRegistry) registerSchema(subject string, schemaType string, schema string) int32 {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	id := r.mu.idAlloc
 	r.mu.idAlloc++
 	r.mu.schemas[id] = schema
+	r.mu.schemaTypes[id] = schemaType
 	r.mu.subjects[subject] = id
 	return id
 }
@@ -130,7 +144,8 @@ func (r *SchemaRegistry) requestHandler(hw http.ResponseWriter, hr *http.Request
 // register is an http handler for the underlying server which registers schemas.
 func (r *SchemaRegistry) register(hw http.ResponseWriter, hr *http.Request) (err error) {
 	type confluentSchemaVersionRequest struct {
-		Schema string `json:"schema"`
+		SchemaType string `json:"schemaType"`
+		Schema     string `json:"schema"`
 	}
 	type confluentSchemaVersionResponse struct {
 		ID int32 `json:"id"`
@@ -146,7 +161,7 @@ func (r *SchemaRegistry) register(hw http.ResponseWriter, hr *http.Request) (err
 	}
 
 	subject := strings.Split(hr.URL.Path, "/")[2]
-	id := r.registerSchema(subject, req.Schema)
+	id := r.registerSchema(subject, req.SchemaType, req.Schema)
 	res, err := json.Marshal(confluentSchemaVersionResponse{ID: id})
 	if err != nil {
 		return err
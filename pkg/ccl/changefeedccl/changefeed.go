@@ -27,6 +27,17 @@ import (
 
 const (
 	jsonMetaSentinel = `__crdb__`
+
+	// The metadata columns which are added after the columns of the table by
+	// the formats which lay out rows as columns (parquet and CSV). The event
+	// type is omitted from CSV files when the changefeed cannot emit deletions,
+	// the timestamps are only present if the corresponding options are set.
+	metaEventTypeColName     = jsonMetaSentinel + `event_type`
+	metaUpdatedColName       = jsonMetaSentinel + `updated`
+	metaMVCCTimestampColName = jsonMetaSentinel + `mvcc_timestamp`
+
+	metaEventTypeUpsert = `upsert`
+	metaEventTypeDelete = `delete`
 )
 
 // emitResolvedTimestamp emits a changefeed-level resolved timestamp to the
@@ -52,13 +63,13 @@ func createProtectedTimestampRecord(
 	ctx context.Context,
 	codec keys.SQLCodec,
 	jobID jobspb.JobID,
-	targets []jobspb.ChangefeedTargetSpecification,
+	details jobspb.ChangefeedDetails,
 	resolved hlc.Timestamp,
 	progress *jobspb.ChangefeedProgress,
 ) *ptpb.Record {
 	progress.ProtectedTimestampRecord = uuid.MakeV4()
-	deprecatedSpansToProtect := makeSpansToProtect(codec, targets)
-	targetToProtect := makeTargetToProtect(targets)
+	deprecatedSpansToProtect := makeSpansToProtect(codec, AllTargets(details))
+	targetToProtect := makeTargetToProtect(details)
 
 	log.VEventf(ctx, 2, "creating protected timestamp %v at %v", progress.ProtectedTimestampRecord, resolved)
 	return jobsprotectedts.MakeRecord(
@@ -66,10 +77,18 @@ func createProtectedTimestampRecord(
 		jobsprotectedts.Jobs, targetToProtect)
 }
 
-func makeTargetToProtect(targets []jobspb.ChangefeedTargetSpecification) *ptpb.Target {
+func makeTargetToProtect(details jobspb.ChangefeedDetails) *ptpb.Target {
+	// A changefeed on a whole database protects the database rather than the
+	// tables it watched when the record was created, so that the tables created
+	// afterwards are protected too.
+	if db, ok := databaseTarget(details); ok {
+		return ptpb.MakeSchemaObjectsTarget(descpb.IDs{db.DatabaseID, keys.DescriptorTableID})
+	}
+
 	// NB: We add 1 because we're also going to protect system.descriptors.
 	// We protect system.descriptors because a changefeed needs all of the history
 	// of table descriptors to version data.
+	targets := AllTargets(details)
 	tablesToProtect := make(descpb.IDs, 0, len(targets)+1)
 	for _, t := range targets {
 		tablesToProtect = append(tablesToProtect, t.TableID)
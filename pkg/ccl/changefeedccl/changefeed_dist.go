@@ -11,6 +11,7 @@ package changefeedccl
 import (
 	"context"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupresolver"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeeddist"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -22,6 +23,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
 )
 
 func init() {
@@ -110,6 +113,14 @@ func distChangefeedFlow(
 			spansTS = spansTS.Next()
 		}
 		var err error
+		if _, isDatabaseFeed := databaseTarget(details); isDatabaseFeed {
+			// The tables of the database may have changed since the changefeed last
+			// ran, which is what restarted it in the first place.
+			details, err = refreshDatabaseTargets(ctx, execCfg, details, spansTS)
+			if err != nil {
+				return err
+			}
+		}
 		trackedSpans, err = fetchSpansForTargets(ctx, execCfg, AllTargets(details), spansTS)
 		if err != nil {
 			return err
@@ -130,6 +141,59 @@ func distChangefeedFlow(
 		ctx, execCtx, jobID, details, trackedSpans, initialHighWater, checkpoint, resultsCh, distflowKnobs)
 }
 
+// refreshDatabaseTargets updates the tables watched by a changefeed on a whole
+// database to the ones the database contains as of ts. Tables created since
+// the changefeed last ran are added and dropped ones are removed, while the
+// others keep their target specifications and statement time names.
+func refreshDatabaseTargets(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	details jobspb.ChangefeedDetails,
+	ts hlc.Timestamp,
+) (jobspb.ChangefeedDetails, error) {
+	dbTarget, _ := databaseTarget(details)
+	allDescs, err := backupresolver.LoadAllDescs(ctx, execCfg, ts)
+	if err != nil {
+		return details, err
+	}
+	r, err := backupresolver.NewDescriptorResolver(allDescs)
+	if err != nil {
+		return details, err
+	}
+	databaseTables := getDatabaseTargetTables(r, dbTarget.DatabaseID)
+	if len(databaseTables) == 0 {
+		// A feed without any spans never resolves, so give up rather than wait
+		// for new tables to show up.
+		return details, errors.Errorf("database %q has no tables left to watch",
+			dbTarget.DatabaseName)
+	}
+
+	prevSpecs := make(map[descpb.ID][]jobspb.ChangefeedTargetSpecification)
+	for _, spec := range details.TargetSpecifications {
+		if spec.TableID != descpb.InvalidID {
+			prevSpecs[spec.TableID] = append(prevSpecs[spec.TableID], spec)
+		}
+	}
+	targets := make([]jobspb.ChangefeedTargetSpecification, 0, len(databaseTables)+1)
+	tables := make(jobspb.ChangefeedTargets, len(databaseTables))
+	for _, t := range databaseTables {
+		id := t.desc.GetID()
+		if specs, ok := prevSpecs[id]; ok {
+			targets = append(targets, specs...)
+			tables[id] = details.Tables[id]
+			continue
+		}
+		log.Infof(ctx, "changefeed on database %s starts watching table %s",
+			dbTarget.DatabaseName, t.name.String())
+		spec := databaseTableTargetSpecification(t, details.Opts)
+		targets = append(targets, spec)
+		tables[id] = jobspb.ChangefeedTargetTable{StatementTimeName: spec.StatementTimeName}
+	}
+	details.TargetSpecifications = append(targets, dbTarget)
+	details.Tables = tables
+	return details, nil
+}
+
 func fetchSpansForTargets(
 	ctx context.Context,
 	execCfg *sql.ExecutorConfig,
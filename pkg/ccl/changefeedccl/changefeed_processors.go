@@ -11,8 +11,11 @@ package changefeedccl
 import (
 	"context"
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcutils"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeeddist"
@@ -84,6 +87,12 @@ type changeAggregator struct {
 	// span was forwarded to the frontier
 	recentKVCount uint64
 
+	// transactionRowCounts contains the number of rows emitted for each
+	// transaction since the last time a resolved span was forwarded to the
+	// frontier. It is nil unless the changefeed has the transaction_metadata
+	// option.
+	transactionRowCounts transactionRowCounts
+
 	// eventProducer produces the next event from the kv feed.
 	eventProducer kvevent.Reader
 	// eventConsumer consumes the event.
@@ -168,12 +177,12 @@ func newChangeAggregatorProcessor(
 	}
 
 	var err error
-	if ca.encoder, err = getEncoder(ca.spec.Feed.Opts, AllTargets(ca.spec.Feed)); err != nil {
+	if ca.encoder, err = getEncoder(ca.spec.Feed.Opts, AllTargets(ca.spec.Feed), flowCtx.EvalCtx.ClusterID); err != nil {
 		return nil, err
 	}
 
 	if _, needTopics := ca.spec.Feed.Opts[changefeedbase.OptTopicInValue]; needTopics {
-		ca.topicNamer, err = MakeTopicNamer(ca.spec.Feed.TargetSpecifications)
+		ca.topicNamer, err = MakeTopicNamer(AllTargets(ca.spec.Feed))
 		if err != nil {
 			return nil, err
 		}
@@ -273,7 +282,7 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	}
 
 	ca.sink, err = getSink(ctx, ca.flowCtx.Cfg, ca.spec.Feed, timestampOracle,
-		ca.spec.User(), ca.spec.JobID, ca.sliMetrics)
+		ca.spec.User(), ca.spec.JobID, ca.sinkPartition(), ca.sliMetrics)
 
 	if err != nil {
 		err = changefeedbase.MarkRetryableError(err)
@@ -299,12 +308,21 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		return
 	}
 
+	if _, ok := ca.spec.Feed.Opts[changefeedbase.OptTransactionMetadata]; ok {
+		ca.transactionRowCounts = make(transactionRowCounts)
+	}
+
 	if ca.spec.Feed.Opts[changefeedbase.OptFormat] == string(changefeedbase.OptFormatNative) {
 		ca.eventConsumer = newNativeKVConsumer(ca.sink)
 	} else {
-		ca.eventConsumer = newKVEventToRowConsumer(
-			ctx, ca.flowCtx.Cfg, ca.frontier.SpanFrontier(), initialHighWater,
-			ca.sink, ca.encoder, ca.spec.Feed, ca.knobs, ca.topicNamer)
+		ca.eventConsumer, err = newKVEventToRowConsumer(
+			ctx, ca.flowCtx.Cfg, ca.flowCtx.NewEvalCtx(), ca.frontier.SpanFrontier(), initialHighWater,
+			ca.sink, ca.encoder, ca.spec.Feed, ca.knobs, ca.topicNamer, ca.transactionRowCounts)
+		if err != nil {
+			ca.MoveToDraining(err)
+			ca.cancel()
+			return
+		}
 	}
 }
 
@@ -371,7 +389,13 @@ func (ca *changeAggregator) makeKVFeedCfg(
 	if schemaChangePolicy == changefeedbase.OptSchemaChangePolicyIgnore || initialScanOnly {
 		sf = schemafeed.DoNothingSchemaFeed
 	} else {
-		sf = schemafeed.New(ctx, cfg, schemaChangeEvents, AllTargets(ca.spec.Feed),
+		// The schema feed of a changefeed on a whole database also needs to know
+		// about the database, to notice the tables created in it.
+		targets := AllTargets(ca.spec.Feed)
+		if dbTarget, isDatabaseFeed := databaseTarget(ca.spec.Feed); isDatabaseFeed {
+			targets = append(targets, dbTarget)
+		}
+		sf = schemafeed.New(ctx, cfg, schemaChangeEvents, targets,
 			initialHighWater, &ca.metrics.SchemaFeedMetrics, ca.spec.Feed.Opts)
 	}
 
@@ -593,13 +617,29 @@ func (ca *changeAggregator) noteResolvedSpan(resolved *jobspb.ResolvedSpan) erro
 	return nil
 }
 
+// sinkPartition returns an identifier of the spans watched by the aggregator,
+// which is stable across restarts of the changefeed as long as the same spans
+// are assigned to an aggregator.
+func (ca *changeAggregator) sinkPartition() string {
+	h := fnv.New64a()
+	for _, watch := range ca.spec.Watches {
+		_, _ = h.Write(watch.Span.Key)
+		_, _ = h.Write(watch.Span.EndKey)
+	}
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// frontierSinkPartition identifies the sink of the changeFrontier, which
+// emits the resolved timestamps of the changefeed.
+const frontierSinkPartition = "frontier"
+
 // flushFrontier flushes sink and emits resolved timestamp if needed.
 func (ca *changeAggregator) flushFrontier() error {
 	// Make sure to flush the sink before forwarding resolved spans,
 	// otherwise, we could lose buffered messages and violate the
 	// at-least-once guarantee. This is also true for checkpointing the
 	// resolved spans in the job progress.
-	if err := ca.sink.Flush(ca.Ctx); err != nil {
+	if err := flushSinkForCheckpoint(ca.Ctx, ca.sink); err != nil {
 		return err
 	}
 
@@ -643,6 +683,9 @@ func (ca *changeAggregator) emitResolved(batch jobspb.ResolvedSpans) error {
 			ca.metrics.ResolvedMessages.Inc(1)
 		}
 
+		// The individual resolved spans have no room for the counts of rows of
+		// the transactions, so the transactions can't be marked complete.
+		ca.transactionRowCounts.drain()
 		return nil
 	}
 
@@ -651,6 +694,7 @@ func (ca *changeAggregator) emitResolved(batch jobspb.ResolvedSpans) error {
 		Stats: jobspb.ResolvedSpans_Stats{
 			RecentKvCount: ca.recentKVCount,
 		},
+		TransactionRowCounts: ca.transactionRowCounts.drain(),
 	}
 	updateBytes, err := protoutil.Marshal(&progressUpdate)
 	if err != nil {
@@ -690,13 +734,48 @@ type kvEventToRowConsumer struct {
 	kvFetcher            row.SpanKVFetcher
 	topicDescriptorCache map[TopicIdentifier]TopicDescriptor
 	topicNamer           *TopicNamer
+	// evaluator filters and projects the rows if the changefeed is a CDC
+	// query. It is nil otherwise.
+	evaluator *cdceval.Evaluator
+	// encodingSink is set if the rows are encoded by the sink rather than by
+	// the encoder, which is the case with format=parquet, and with format=csv
+	// for cloud storage sinks, which write headers into their files.
+	encodingSink SinkWithEncoder
+	// transactionRowCounts, if non-nil, counts the emitted rows which changed
+	// in each transaction.
+	transactionRowCounts transactionRowCounts
 }
 
 var _ kvEventConsumer = &kvEventToRowConsumer{}
 
+// transactionRowCounts contains the number of rows emitted for each
+// transaction, keyed by its commit timestamp, by changefeeds with the
+// transaction_metadata option.
+type transactionRowCounts map[hlc.Timestamp]uint64
+
+// drain returns the counts in timestamp order, and resets them.
+func (c transactionRowCounts) drain() []jobspb.ResolvedSpans_TransactionRowCount {
+	if len(c) == 0 {
+		return nil
+	}
+	counts := make([]jobspb.ResolvedSpans_TransactionRowCount, 0, len(c))
+	for ts, rowCount := range c {
+		counts = append(counts, jobspb.ResolvedSpans_TransactionRowCount{
+			Timestamp: ts,
+			RowCount:  rowCount,
+		})
+		delete(c, ts)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i].Timestamp.Less(counts[j].Timestamp)
+	})
+	return counts
+}
+
 func newKVEventToRowConsumer(
 	ctx context.Context,
 	cfg *execinfra.ServerConfig,
+	evalCtx *tree.EvalContext,
 	frontier *span.Frontier,
 	cursor hlc.Timestamp,
 	sink Sink,
@@ -704,7 +783,30 @@ func newKVEventToRowConsumer(
 	details jobspb.ChangefeedDetails,
 	knobs TestingKnobs,
 	topicNamer *TopicNamer,
-) kvEventConsumer {
+	transactionRowCounts transactionRowCounts,
+) (kvEventConsumer, error) {
+	var evaluator *cdceval.Evaluator
+	if details.Select != "" {
+		sc, err := cdceval.ParseSelectClause(details.Select)
+		if err != nil {
+			return nil, err
+		}
+		_, withDiff := details.Opts[changefeedbase.OptDiff]
+		evaluator = cdceval.NewEvaluator(sc, evalCtx, withDiff)
+	}
+
+	var encodingSink SinkWithEncoder
+	if encodesRows, err := sinkEncodesRows(details); err != nil {
+		return nil, err
+	} else if encodesRows {
+		s, ok := sink.(SinkWithEncoder)
+		if !ok {
+			return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+				changefeedbase.OptFormat, details.Opts[changefeedbase.OptFormat])
+		}
+		encodingSink = s
+	}
+
 	rfCache := newRowFetcherCache(
 		ctx,
 		cfg.Codec,
@@ -724,7 +826,10 @@ func newKVEventToRowConsumer(
 		knobs:                knobs,
 		topicDescriptorCache: make(map[TopicIdentifier]TopicDescriptor),
 		topicNamer:           topicNamer,
-	}
+		evaluator:            evaluator,
+		encodingSink:         encodingSink,
+		transactionRowCounts: transactionRowCounts,
+	}, nil
 }
 
 type tableDescriptorTopic struct {
@@ -889,6 +994,15 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		return err
 	}
 
+	if c.evaluator != nil && !r.deleted {
+		// Deletions are emitted as they are: the query cannot be evaluated on
+		// them since only the primary key of a deleted row is known.
+		matches, err := c.evaluateQuery(ctx, &r)
+		if err != nil || !matches {
+			return err
+		}
+	}
+
 	topic, err := c.topicForRow(r)
 	if err != nil {
 		return err
@@ -911,6 +1025,18 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 			"or equal to the local frontier %s.", r.updated, c.frontier.Frontier())
 		return nil
 	}
+
+	if c.encodingSink != nil {
+		if c.knobs.BeforeEmitRow != nil {
+			if err := c.knobs.BeforeEmitRow(ctx); err != nil {
+				return err
+			}
+		}
+		return c.encodingSink.EncodeAndEmitRow(
+			ctx, r, topic, r.updated, r.mvccTimestamp, ev.DetachAlloc(),
+		)
+	}
+
 	var keyCopy, valueCopy []byte
 	encodedKey, err := c.encoder.EncodeKey(ctx, r)
 	if err != nil {
@@ -934,12 +1060,32 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 	); err != nil {
 		return err
 	}
+	if c.transactionRowCounts != nil && !r.backfill {
+		c.transactionRowCounts[r.mvccTimestamp]++
+	}
 	if log.V(3) {
 		log.Infof(ctx, `r %s: %s -> %s`, r.tableDesc.GetName(), keyCopy, valueCopy)
 	}
 	return nil
 }
 
+// evaluateQuery evaluates the CDC query on the row, and sets its projection.
+// It returns false if the row is filtered out by the query.
+func (c *kvEventToRowConsumer) evaluateQuery(ctx context.Context, r *encodeRow) (bool, error) {
+	updated := cdceval.Row{Desc: r.tableDesc, Datums: r.datums}
+	prev := cdceval.Row{Desc: r.prevTableDesc, Datums: r.prevDatums, Deleted: r.prevDeleted}
+	matches, projection, err := c.evaluator.Eval(ctx, updated, prev)
+	if err != nil || !matches {
+		return false, err
+	}
+	r.projectionNames, err = c.evaluator.ColumnNames(ctx, r.tableDesc)
+	if err != nil {
+		return false, err
+	}
+	r.projection = projection
+	return true, nil
+}
+
 func (c *kvEventToRowConsumer) eventToRow(
 	ctx context.Context, event kvevent.Event,
 ) (encodeRow, error) {
@@ -984,6 +1130,7 @@ func (c *kvEventToRowConsumer) eventToRow(
 	}
 	r.datums = append(rowenc.EncDatumRow(nil), r.datums...)
 	r.deleted = rf.RowIsDeleted()
+	r.backfill = !event.BackfillTimestamp().IsEmpty()
 	r.updated = schemaTimestamp
 	r.mvccTimestamp = mvccTimestamp
 
@@ -1114,6 +1261,10 @@ type changeFrontier struct {
 	freqEmitResolved time.Duration
 	// lastEmitResolved is the last time a resolved timestamp was emitted.
 	lastEmitResolved time.Time
+	// transactionRowCounts contains the number of rows the changeAggregators
+	// emitted for each transaction which wasn't marked complete yet. It is nil
+	// unless the changefeed has the transaction_metadata option.
+	transactionRowCounts transactionRowCounts
 
 	// slowLogEveryN rate-limits the logging of slow spans
 	slowLogEveryN log.EveryN
@@ -1291,7 +1442,7 @@ func newChangeFrontierProcessor(
 		cf.freqEmitResolved = emitNoResolved
 	}
 
-	if cf.encoder, err = getEncoder(spec.Feed.Opts, AllTargets(spec.Feed)); err != nil {
+	if cf.encoder, err = getEncoder(spec.Feed.Opts, AllTargets(spec.Feed), flowCtx.EvalCtx.ClusterID); err != nil {
 		return nil, err
 	}
 
@@ -1330,7 +1481,7 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 	}
 	cf.sliMetrics = sli
 	cf.sink, err = getSink(ctx, cf.flowCtx.Cfg, cf.spec.Feed, nilOracle,
-		cf.spec.User(), cf.spec.JobID, sli)
+		cf.spec.User(), cf.spec.JobID, frontierSinkPartition, sli)
 
 	if err != nil {
 		err = changefeedbase.MarkRetryableError(err)
@@ -1344,6 +1495,10 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 
 	cf.sink = &errorWrapperSink{wrapped: cf.sink}
 
+	if _, ok := cf.spec.Feed.Opts[changefeedbase.OptTransactionMetadata]; ok {
+		cf.transactionRowCounts = make(transactionRowCounts)
+	}
+
 	cf.highWaterAtStart = cf.spec.Feed.StatementTime
 	if cf.spec.JobID != 0 {
 		job, err := cf.flowCtx.Cfg.JobRegistry.LoadClaimedJob(ctx, cf.spec.JobID)
@@ -1508,6 +1663,11 @@ func (cf *changeFrontier) noteAggregatorProgress(d rowenc.EncDatum) error {
 		}
 
 		cf.maybeMarkJobIdle(resolvedSpans.Stats.RecentKvCount)
+		if cf.transactionRowCounts != nil {
+			for _, c := range resolvedSpans.TransactionRowCounts {
+				cf.transactionRowCounts[c.Timestamp] += c.RowCount
+			}
+		}
 	} else { // TODO(smiskin): Remove post-22.2
 		// Progress used to be sent as individual ResolvedSpans
 		var resolved jobspb.ResolvedSpan
@@ -1579,12 +1739,41 @@ func (cf *changeFrontier) forwardFrontier(resolved jobspb.ResolvedSpan) error {
 		}
 		cf.metrics.mu.Unlock()
 
+		if err := cf.emitTransactionsComplete(newResolved); err != nil {
+			return err
+		}
 		return cf.maybeEmitResolved(newResolved)
 	}
 
 	return nil
 }
 
+// emitTransactionsComplete emits the messages marking complete the
+// transactions which committed at or before the resolved timestamp. Since
+// changeAggregators send the counts of rows they emitted before the resolved
+// spans which follow them, all the rows of these transactions were emitted and
+// counted.
+func (cf *changeFrontier) emitTransactionsComplete(resolved hlc.Timestamp) error {
+	var complete []hlc.Timestamp
+	for ts := range cf.transactionRowCounts {
+		if ts.LessEq(resolved) {
+			complete = append(complete, ts)
+		}
+	}
+	sort.Slice(complete, func(i, j int) bool { return complete[i].Less(complete[j]) })
+	for _, ts := range complete {
+		encoder := transactionCompleteEncoder{
+			Encoder:  cf.encoder,
+			rowCount: cf.transactionRowCounts[ts],
+		}
+		if err := cf.sink.EmitResolvedTimestamp(cf.Ctx, encoder, ts); err != nil {
+			return err
+		}
+		delete(cf.transactionRowCounts, ts)
+	}
+	return nil
+}
+
 func (cf *changeFrontier) maybeMarkJobIdle(recentKVCount uint64) {
 	if cf.spec.JobID == 0 {
 		return
@@ -1712,7 +1901,7 @@ func (cf *changeFrontier) manageProtectedTimestamps(
 
 	recordID := progress.ProtectedTimestampRecord
 	if recordID == uuid.Nil {
-		ptr := createProtectedTimestampRecord(ctx, cf.flowCtx.Codec(), cf.spec.JobID, AllTargets(cf.spec.Feed), highWater, progress)
+		ptr := createProtectedTimestampRecord(ctx, cf.flowCtx.Codec(), cf.spec.JobID, cf.spec.Feed, highWater, progress)
 		if err := pts.Protect(ctx, txn, ptr); err != nil {
 			return err
 		}
@@ -1742,7 +1931,7 @@ func (cf *changeFrontier) deprecatedManageProtectedTimestamps(
 	shouldProtectBoundaries := schemaChangePolicy == changefeedbase.OptSchemaChangePolicyBackfill
 	if cf.frontier.schemaChangeBoundaryReached() && shouldProtectBoundaries {
 		highWater := cf.frontier.Frontier()
-		ptr := createProtectedTimestampRecord(ctx, cf.flowCtx.Codec(), cf.spec.JobID, AllTargets(cf.spec.Feed), highWater, progress)
+		ptr := createProtectedTimestampRecord(ctx, cf.flowCtx.Codec(), cf.spec.JobID, cf.spec.Feed, highWater, progress)
 		return pts.Protect(ctx, txn, ptr)
 	}
 	return nil
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupresolver"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdceval"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
@@ -221,7 +222,7 @@ func changefeedPlanHook(
 			}
 			shouldProtectTimestamp := activeTimestampProtection || (initialScanType != changefeedbase.NoInitialScan)
 			if shouldProtectTimestamp {
-				ptr = createProtectedTimestampRecord(ctx, codec, jobID, AllTargets(details), details.StatementTime, progress.GetChangefeed())
+				ptr = createProtectedTimestampRecord(ctx, codec, jobID, details, details.StatementTime, progress.GetChangefeed())
 			}
 
 			jr.Progress = *progress.GetChangefeed()
@@ -345,20 +346,37 @@ func createChangefeedJobRecord(
 		}
 	}
 
-	tableOnlyTargetList := tree.TargetList{}
-	for _, t := range changefeedStmt.Targets {
-		tableOnlyTargetList.Tables = append(tableOnlyTargetList.Tables, t.TableName)
-	}
+	var targetDescs map[tree.TablePattern]catalog.Descriptor
+	var targets []jobspb.ChangefeedTargetSpecification
+	var tables jobspb.ChangefeedTargets
+	var databaseID descpb.ID
+	if changefeedStmt.Database != "" {
+		databaseID, targetDescs, targets, tables, err = getDatabaseTargetsAndTables(
+			ctx, p, changefeedStmt.Database, statementTime, initialHighWater, opts)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if _, ok := opts[changefeedbase.OptInitialScanNewTables]; ok {
+			return nil, errors.Errorf(`%s is only supported by changefeeds on a database`,
+				changefeedbase.OptInitialScanNewTables)
+		}
 
-	// This grabs table descriptors once to get their ids.
-	targetDescs, err := getTableDescriptors(ctx, p, &tableOnlyTargetList, statementTime, initialHighWater)
-	if err != nil {
-		return nil, err
-	}
+		tableOnlyTargetList := tree.TargetList{}
+		for _, t := range changefeedStmt.Targets {
+			tableOnlyTargetList.Tables = append(tableOnlyTargetList.Tables, t.TableName)
+		}
 
-	targets, tables, err := getTargetsAndTables(ctx, p, targetDescs, changefeedStmt.Targets, changefeedStmt.originalSpecs, opts)
-	if err != nil {
-		return nil, err
+		// This grabs table descriptors once to get their ids.
+		targetDescs, err = getTableDescriptors(ctx, p, &tableOnlyTargetList, statementTime, initialHighWater)
+		if err != nil {
+			return nil, err
+		}
+
+		targets, tables, err = getTargetsAndTables(ctx, p, targetDescs, changefeedStmt.Targets, changefeedStmt.originalSpecs, opts)
+		if err != nil {
+			return nil, err
+		}
 	}
 	for _, desc := range targetDescs {
 		if table, isTable := desc.(catalog.TableDescriptor); isTable {
@@ -380,6 +398,13 @@ func createChangefeedJobRecord(
 		TargetSpecifications: targets,
 	}
 
+	if changefeedStmt.Select != nil {
+		if err := validateQuery(ctx, p, changefeedStmt.Select, targetDescs, opts); err != nil {
+			return nil, err
+		}
+		details.Select = tree.AsStringWithFlags(changefeedStmt.Select, tree.FmtParsable)
+	}
+
 	// TODO(dan): In an attempt to present the most helpful error message to the
 	// user, the ordering requirements between all these usage validations have
 	// become extremely fragile and non-obvious.
@@ -424,9 +449,14 @@ func createChangefeedJobRecord(
 		return nil, err
 	}
 
-	if _, err := getEncoder(details.Opts, AllTargets(details)); err != nil {
+	if _, err := getEncoder(details.Opts, AllTargets(details), p.ExtendedEvalContext().ClusterID); err != nil {
 		return nil, err
 	}
+	if details.Opts[changefeedbase.OptFormat] == string(changefeedbase.OptFormatParquet) &&
+		!isCloudStorageSink(parsedSink) {
+		return nil, errors.Errorf(`%s=%s is only supported by cloud storage sinks`,
+			changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
 
 	if isCloudStorageSink(parsedSink) || isWebhookSink(parsedSink) {
 		details.Opts[changefeedbase.OptKeyInValue] = ``
@@ -506,6 +536,9 @@ func createChangefeedJobRecord(
 			for _, desc := range targetDescs {
 				sqlDescIDs = append(sqlDescIDs, desc.GetID())
 			}
+			if databaseID != descpb.InvalidID {
+				sqlDescIDs = append(sqlDescIDs, databaseID)
+			}
 			return sqlDescIDs
 		}(),
 		Details: details,
@@ -629,6 +662,14 @@ func getTargetsAndTables(
 			if err != nil {
 				return nil, nil, err
 			}
+			var dbName, schemaName string
+			if changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeDebezium {
+				tbName, err := getQualifiedTableNameObj(ctx, p.ExecCfg(), p.ExtendedEvalContext().Txn, td)
+				if err != nil {
+					return nil, nil, err
+				}
+				dbName, schemaName = tbName.Catalog(), tbName.Schema()
+			}
 
 			tables[td.GetID()] = jobspb.ChangefeedTargetTable{
 				StatementTimeName: name,
@@ -646,6 +687,8 @@ func getTargetsAndTables(
 				TableID:           td.GetID(),
 				FamilyName:        string(ct.FamilyName),
 				StatementTimeName: tables[td.GetID()].StatementTimeName,
+				DatabaseName:      dbName,
+				SchemaName:        schemaName,
 			}
 		}
 		if dup, isDup := seen[targets[i]]; isDup {
@@ -659,6 +702,154 @@ func getTargetsAndTables(
 	return targets, tables, nil
 }
 
+// databaseTargetTable is a table watched by a changefeed on a whole database.
+type databaseTargetTable struct {
+	name tree.TableName
+	desc catalog.TableDescriptor
+}
+
+// getDatabaseTargetTables returns the tables watched by a changefeed on the
+// database with the given ID, as of the time the descriptors known to r were
+// read. The tables are ordered by name.
+func getDatabaseTargetTables(
+	r *backupresolver.DescriptorResolver, databaseID descpb.ID,
+) []databaseTargetTable {
+	dbDesc, ok := r.DescByID[databaseID]
+	if !ok {
+		return nil
+	}
+	var tables []databaseTargetTable
+	for scName, objs := range r.ObjsByName[databaseID] {
+		for objName, id := range objs {
+			td, ok := r.DescByID[id].(catalog.TableDescriptor)
+			if !ok || !changefeedbase.IsDatabaseTargetTable(td) {
+				continue
+			}
+			tables = append(tables, databaseTargetTable{
+				name: tree.MakeTableNameWithSchema(
+					tree.Name(dbDesc.GetName()), tree.Name(scName), tree.Name(objName)),
+				desc: td,
+			})
+		}
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].name.String() < tables[j].name.String()
+	})
+	return tables
+}
+
+// databaseTableTargetSpecification returns the specification of a table
+// watched by a changefeed on a whole database.
+func databaseTableTargetSpecification(
+	t databaseTargetTable, opts map[string]string,
+) jobspb.ChangefeedTargetSpecification {
+	spec := jobspb.ChangefeedTargetSpecification{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           t.desc.GetID(),
+		StatementTimeName: t.desc.GetName(),
+	}
+	if t.desc.NumFamilies() > 1 {
+		spec.Type = jobspb.ChangefeedTargetSpecification_EACH_FAMILY
+	}
+	if _, qualified := opts[changefeedbase.OptFullTableName]; qualified {
+		spec.StatementTimeName = t.name.String()
+	}
+	if changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeDebezium {
+		spec.DatabaseName, spec.SchemaName = t.name.Catalog(), t.name.Schema()
+	}
+	return spec
+}
+
+// getDatabaseTargetsAndTables resolves the database targeted by a `CREATE
+// CHANGEFEED FOR DATABASE` statement and returns its ID along with the
+// descriptors, target specifications and tables of the tables it contains at
+// statement time. The returned specifications end with the one for the
+// database itself, which makes the changefeed pick up tables created later.
+func getDatabaseTargetsAndTables(
+	ctx context.Context,
+	p sql.PlanHookState,
+	dbName tree.Name,
+	statementTime hlc.Timestamp,
+	initialHighWater hlc.Timestamp,
+	opts map[string]string,
+) (
+	descpb.ID,
+	map[tree.TablePattern]catalog.Descriptor,
+	[]jobspb.ChangefeedTargetSpecification,
+	jobspb.ChangefeedTargets,
+	error,
+) {
+	allDescs, err := backupresolver.LoadAllDescs(ctx, p.ExecCfg(), statementTime)
+	if err != nil {
+		return descpb.InvalidID, nil, nil, nil, err
+	}
+	r, err := backupresolver.NewDescriptorResolver(allDescs)
+	if err != nil {
+		return descpb.InvalidID, nil, nil, nil, err
+	}
+	databaseID, ok := r.DbsByName[string(dbName)]
+	if !ok {
+		err = errors.Errorf("database %q does not exist", dbName)
+		if !initialHighWater.IsEmpty() {
+			err = errors.WithHintf(err,
+				"does the database exist at the specified cursor time %s?", initialHighWater)
+		}
+		return descpb.InvalidID, nil, nil, nil, err
+	}
+	dbDesc := r.DescByID[databaseID]
+	if catalog.IsSystemDescriptor(dbDesc) {
+		return descpb.InvalidID, nil, nil, nil, errors.Errorf(
+			`CHANGEFEEDs are not supported on system tables`)
+	}
+	// The changefeed will emit the rows of tables which don't exist yet, so
+	// watching a whole database requires more than watching all of its current
+	// tables.
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.ALL); err != nil {
+		return descpb.InvalidID, nil, nil, nil, err
+	}
+
+	databaseTables := getDatabaseTargetTables(r, databaseID)
+	if len(databaseTables) == 0 {
+		return descpb.InvalidID, nil, nil, nil, errors.Errorf(
+			"database %q has no tables to watch", dbName)
+	}
+	targetDescs := make(map[tree.TablePattern]catalog.Descriptor, len(databaseTables))
+	targets := make([]jobspb.ChangefeedTargetSpecification, 0, len(databaseTables)+1)
+	tables := make(jobspb.ChangefeedTargets, len(databaseTables))
+	for _, t := range databaseTables {
+		if err := p.CheckPrivilege(ctx, t.desc, privilege.SELECT); err != nil {
+			return descpb.InvalidID, nil, nil, nil, err
+		}
+		targetDescs[t.name.ToUnresolvedObjectName().ToUnresolvedName()] = t.desc
+		spec := databaseTableTargetSpecification(t, opts)
+		targets = append(targets, spec)
+		tables[spec.TableID] = jobspb.ChangefeedTargetTable{
+			StatementTimeName: spec.StatementTimeName,
+		}
+	}
+	targets = append(targets, jobspb.ChangefeedTargetSpecification{
+		Type:         jobspb.ChangefeedTargetSpecification_DATABASE,
+		DatabaseID:   databaseID,
+		DatabaseName: dbDesc.GetName(),
+	})
+	return databaseID, targetDescs, targets, tables, nil
+}
+
+// databaseTarget returns the specification of the database watched by a
+// changefeed on a whole database, if any.
+func databaseTarget(cd jobspb.ChangefeedDetails) (jobspb.ChangefeedTargetSpecification, bool) {
+	for _, ts := range cd.TargetSpecifications {
+		if ts.Type == jobspb.ChangefeedTargetSpecification_DATABASE {
+			return ts, true
+		}
+	}
+	return jobspb.ChangefeedTargetSpecification{}, false
+}
+
+// canarySinkPartition identifies the sink created by validateSink to check
+// that the sink of a changefeed can be reached.
+const canarySinkPartition = "canary"
+
 func validateSink(
 	ctx context.Context,
 	p sql.PlanHookState,
@@ -673,7 +864,7 @@ func validateSink(
 	}
 	var nilOracle timestampLowerBoundOracle
 	canarySink, err := getSink(ctx, &p.ExecCfg().DistSQLSrv.ServerConfig, details,
-		nilOracle, p.User(), jobID, sli)
+		nilOracle, p.User(), jobID, canarySinkPartition, sli)
 	if err != nil {
 		return changefeedbase.MaybeStripRetryableErrorMarker(err)
 	}
@@ -705,6 +896,7 @@ func changefeedJobDescription(
 		changefeedbase.SinkParamSASLPassword,
 		changefeedbase.SinkParamCACert,
 		changefeedbase.SinkParamClientCert,
+		changefeedbase.SinkParamAuthToken,
 	})
 
 	if err != nil {
@@ -714,8 +906,10 @@ func changefeedJobDescription(
 	cleanedSinkURI = redactUser(cleanedSinkURI)
 
 	c := &tree.CreateChangefeed{
-		Targets: changefeed.Targets,
-		SinkURI: tree.NewDString(cleanedSinkURI),
+		Targets:  changefeed.Targets,
+		SinkURI:  tree.NewDString(cleanedSinkURI),
+		Select:   changefeed.Select,
+		Database: changefeed.Database,
 	}
 	for k, v := range opts {
 		if k == changefeedbase.OptWebhookAuthHeader {
@@ -752,6 +946,33 @@ func validateNonNegativeDuration(optName string, optValue string) error {
 	return nil
 }
 
+// validateQuery checks that the query of a `CREATE CHANGEFEED ... AS SELECT`
+// statement can be evaluated on the rows of the table it selects from.
+func validateQuery(
+	ctx context.Context,
+	p sql.PlanHookState,
+	sc *tree.SelectClause,
+	targetDescs map[tree.TablePattern]catalog.Descriptor,
+	opts map[string]string,
+) error {
+	if len(targetDescs) != 1 {
+		return errors.AssertionFailedf("expected a single target for a changefeed query, found %d",
+			len(targetDescs))
+	}
+	for _, desc := range targetDescs {
+		table, ok := desc.(catalog.TableDescriptor)
+		if !ok {
+			return errors.Errorf(`CHANGEFEED cannot target %s`, desc.GetName())
+		}
+		_, withDiff := opts[changefeedbase.OptDiff]
+		evaluator := cdceval.NewEvaluator(sc, &p.ExtendedEvalContext().EvalContext, withDiff)
+		if err := evaluator.Validate(ctx, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails, error) {
 	if details.Opts == nil {
 		// The proto MarshalTo method omits the Opts field if the map is empty.
@@ -810,6 +1031,17 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeKeyOnly)
 		case ``, changefeedbase.OptEnvelopeWrapped:
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeWrapped)
+		case changefeedbase.OptEnvelopeDebezium:
+			switch f := changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]); f {
+			case ``, changefeedbase.OptFormatJSON, changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro:
+			default:
+				return jobspb.ChangefeedDetails{}, errors.Errorf(
+					`%s=%s is not supported with %s=%s`, opt, v, changefeedbase.OptFormat, f)
+			}
+			details.Opts[opt] = string(changefeedbase.OptEnvelopeDebezium)
+			// The previous value of the rows is needed to tell inserts from
+			// updates, and populates the before field.
+			details.Opts[changefeedbase.OptDiff] = ``
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
 				`unknown %s: %s`, opt, v)
@@ -820,8 +1052,37 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 		switch v := changefeedbase.FormatType(details.Opts[opt]); v {
 		case ``, changefeedbase.OptFormatJSON:
 			details.Opts[opt] = string(changefeedbase.OptFormatJSON)
-		case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro:
+		case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro,
+			changefeedbase.OptFormatProtobuf:
 			// No-op.
+		case changefeedbase.OptFormatParquet:
+			// The values of the rows before the change are not part of the
+			// schema of parquet files.
+			if _, ok := details.Opts[changefeedbase.OptDiff]; ok {
+				return jobspb.ChangefeedDetails{}, errors.Errorf(
+					`%s is not supported with %s=%s`, changefeedbase.OptDiff, opt, v)
+			}
+		case changefeedbase.OptFormatCSV:
+			// CSV changefeeds are exports of the table, either a full one or,
+			// with a cursor, of the changes until end_time.
+			initialScanType, err := initialScanTypeFromOpts(details.Opts)
+			if err != nil {
+				return jobspb.ChangefeedDetails{}, err
+			}
+			_, endTime := details.Opts[changefeedbase.OptEndTime]
+			if initialScanType != changefeedbase.OnlyInitialScan && !endTime {
+				return jobspb.ChangefeedDetails{}, errors.Errorf(
+					`%s=%s is only usable with %s or %s`, opt, v,
+					changefeedbase.OptInitialScanOnly, changefeedbase.OptEndTime)
+			}
+			for _, unsupported := range []string{
+				changefeedbase.OptDiff, changefeedbase.OptResolvedTimestamps,
+			} {
+				if _, ok := details.Opts[unsupported]; ok {
+					return jobspb.ChangefeedDetails{}, errors.Errorf(
+						`%s is not supported with %s=%s`, unsupported, opt, v)
+				}
+			}
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
 				`unknown %s: %s`, opt, v)
@@ -876,6 +1137,31 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			)
 		}
 	}
+	if _, ok := details.Opts[changefeedbase.OptTransactionMetadata]; ok {
+		// The metadata of the transaction is a field of the JSON object which
+		// wraps the value of the row, see jsonEncoder.
+		if v := details.Opts[changefeedbase.OptFormat]; v != string(changefeedbase.OptFormatJSON) {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`%s is not supported with %s=%s`, changefeedbase.OptTransactionMetadata,
+				changefeedbase.OptFormat, v)
+		}
+	}
+	if details.Select != `` {
+		// The result of a query only has a JSON encoding, and it replaces the
+		// value of the row, which these options would omit or split up.
+		if v := details.Opts[changefeedbase.OptFormat]; v != string(changefeedbase.OptFormatJSON) {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`%s=%s is not supported with a changefeed query`, changefeedbase.OptFormat, v)
+		}
+		if v := details.Opts[changefeedbase.OptEnvelope]; v == string(changefeedbase.OptEnvelopeKeyOnly) {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`%s=%s is not supported with a changefeed query`, changefeedbase.OptEnvelope, v)
+		}
+		if _, ok := details.Opts[changefeedbase.OptSplitColumnFamilies]; ok {
+			return jobspb.ChangefeedDetails{}, errors.Errorf(
+				`%s is not supported with a changefeed query`, changefeedbase.OptSplitColumnFamilies)
+		}
+	}
 	return details, nil
 }
 
@@ -1103,7 +1389,7 @@ func (b *changefeedResumer) OnPauseRequest(
 			return nil
 		}
 		pts := execCfg.ProtectedTimestampProvider
-		ptr := createProtectedTimestampRecord(ctx, execCfg.Codec, b.job.ID(), AllTargets(details), *resolved, cp)
+		ptr := createProtectedTimestampRecord(ctx, execCfg.Codec, b.job.ID(), details, *resolved, cp)
 		return pts.Protect(ctx, txn, ptr)
 	}
 
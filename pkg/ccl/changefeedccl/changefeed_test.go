@@ -356,6 +356,94 @@ func TestChangefeedDiff(t *testing.T) {
 	t.Run(`pubsub`, pubsubTest(testFn))
 }
 
+func TestChangefeedDebeziumEnvelope(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH envelope='debezium'`)
+		defer closeFeed(t, foo)
+
+		// assertOps checks the operation and the before and after values of
+		// the next messages, and the source of the changes. The timestamps
+		// are not deterministic.
+		assertOps := func(expected ...string) {
+			t.Helper()
+			msgs, err := readNextMessages(foo, len(expected))
+			require.NoError(t, err)
+			var actual []string
+			for _, m := range msgs {
+				var value struct {
+					Before, After map[string]interface{}
+					Op            string
+					Source        map[string]string
+					TsMs          int64 `json:"ts_ms"`
+				}
+				require.NoError(t, json.Unmarshal(m.Value, &value))
+				require.Equal(t, `d`, value.Source[`db`])
+				require.Equal(t, `public`, value.Source[`schema`])
+				require.Equal(t, `foo`, value.Source[`table`])
+				require.NotEmpty(t, value.Source[`cluster`])
+				require.NotEmpty(t, value.Source[`mvcc_timestamp`])
+				require.NotZero(t, value.TsMs)
+				actual = append(actual, fmt.Sprintf(`%s: %s %v->%v`, m.Key, value.Op, value.Before, value.After))
+			}
+			require.Equal(t, expected, actual)
+		}
+
+		assertOps(`[0]: r map[]->map[a:0 b:initial]`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+		assertOps(`[1]: c map[]->map[a:1 b:a]`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'b' WHERE a = 1`)
+		assertOps(`[1]: u map[a:1 b:a]->map[a:1 b:b]`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertOps(`[1]: d map[a:1 b:b]->map[]`)
+	}
+
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`kafka`, kafkaTest(testFn))
+}
+
+func TestChangefeedQuery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, status STRING, note STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'open', 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED WITH diff AS
+SELECT a, status, cdc_prev.status AS prev_status FROM foo
+WHERE status IS DISTINCT FROM cdc_prev.status`)
+		defer closeFeed(t, foo)
+
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "prev_status": null, "status": "open"}, "before": null}`,
+		})
+
+		// Rows which don't match the WHERE clause are not emitted.
+		sqlDB.Exec(t, `UPDATE foo SET note = 'updated' WHERE a = 0`)
+		sqlDB.Exec(t, `UPDATE foo SET status = 'closed' WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "prev_status": "open", "status": "closed"}, "before": {"a": 0, "note": "updated", "status": "open"}}`,
+		})
+
+		// Deletions are always emitted.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 0`)
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": null, "before": {"a": 0, "note": "updated", "status": "closed"}}`,
+		})
+	}
+
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`kafka`, kafkaTest(testFn))
+}
+
 func TestChangefeedTenants(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -558,6 +646,156 @@ func TestChangefeedMultiTable(t *testing.T) {
 	t.Run(`pubsub`, pubsubTest(testFn))
 }
 
+func TestChangefeedDatabase(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	skip.UnderRace(t)
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+		sqlDB.Exec(t, `CREATE VIEW foo_view AS SELECT a FROM foo`)
+		sqlDB.Exec(t, `CREATE SEQUENCE foo_seq`)
+
+		dbFeed := feed(t, f, `CREATE CHANGEFEED FOR DATABASE d WITH initial_scan_new_tables`)
+		defer closeFeed(t, dbFeed)
+		assertPayloads(t, dbFeed, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+		})
+
+		// Tables created after the changefeed are scanned as of the time they
+		// become public, which picks up the rows of CREATE TABLE AS, and then
+		// watched like the others.
+		sqlDB.Exec(t, `CREATE TABLE bar (a PRIMARY KEY, b) AS SELECT a + 1, b FROM foo`)
+		assertPayloads(t, dbFeed, []string{
+			`bar: [2]->{"after": {"a": 2, "b": "a"}}`,
+		})
+		sqlDB.Exec(t, `INSERT INTO bar VALUES (3, 'c')`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (4, 'd')`)
+		assertPayloads(t, dbFeed, []string{
+			`bar: [3]->{"after": {"a": 3, "b": "c"}}`,
+			`foo: [4]->{"after": {"a": 4, "b": "d"}}`,
+		})
+
+		// Dropping a table stops the changefeed from watching it rather than
+		// failing it.
+		sqlDB.Exec(t, `DROP TABLE bar`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (5, 'e')`)
+		assertPayloads(t, dbFeed, []string{
+			`foo: [5]->{"after": {"a": 5, "b": "e"}}`,
+		})
+	}
+
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`cloudstorage`, cloudStorageTest(testFn))
+	t.Run(`kafka`, kafkaTest(testFn))
+	t.Run(`webhook`, webhookTest(testFn))
+}
+
+// TestChangefeedDatabaseProtectedTimestampTarget checks that the protected
+// timestamp record of a changefeed on a whole database protects the database,
+// which covers the tables created after the changefeed started.
+func TestChangefeedDatabaseProtectedTimestampTarget(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const dbID, tableID = descpb.ID(104), descpb.ID(106)
+	details := jobspb.ChangefeedDetails{
+		Tables: jobspb.ChangefeedTargets{
+			tableID: {StatementTimeName: "d.public.foo"},
+		},
+		TargetSpecifications: []jobspb.ChangefeedTargetSpecification{
+			{Type: jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY, TableID: tableID},
+			{Type: jobspb.ChangefeedTargetSpecification_DATABASE, DatabaseID: dbID},
+		},
+	}
+	require.Equal(t,
+		ptpb.MakeSchemaObjectsTarget(descpb.IDs{dbID, keys.DescriptorTableID}),
+		makeTargetToProtect(details))
+
+	// Changefeeds on tables protect the tables.
+	details.TargetSpecifications = details.TargetSpecifications[:1]
+	require.Equal(t,
+		ptpb.MakeSchemaObjectsTarget(descpb.IDs{tableID, keys.DescriptorTableID}),
+		makeTargetToProtect(details))
+}
+
+func TestChangefeedTransactionMetadata(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	type transaction struct {
+		ID            string `json:"id"`
+		MVCCTimestamp string `json:"mvcc_timestamp"`
+		Status        string `json:"status"`
+		RowCount      uint64 `json:"row_count"`
+	}
+
+	testFn := func(t *testing.T, db *gosql.DB, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(db)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH transaction_metadata`)
+		defer closeFeed(t, foo)
+
+		// The rows read by the initial scan weren't changed by a transaction.
+		assertPayloads(t, foo, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+		sqlDB.Exec(t, `UPDATE foo SET b = 'c' WHERE a = 1`)
+
+		msgs, err := readNextMessages(foo, 3)
+		require.NoError(t, err)
+		rowCounts := make(map[string]uint64)
+		for _, m := range msgs {
+			var value struct {
+				Transaction transaction `json:"transaction"`
+			}
+			require.NoError(t, json.Unmarshal(m.Value, &value))
+			require.NotEmpty(t, value.Transaction.ID, `%s`, m.Value)
+			require.NotEmpty(t, value.Transaction.MVCCTimestamp, `%s`, m.Value)
+			rowCounts[value.Transaction.ID]++
+		}
+		require.Len(t, rowCounts, 2)
+
+		// Each transaction is marked complete, with the number of rows it
+		// changed, once they were all emitted.
+		for len(rowCounts) > 0 {
+			m, err := foo.Next()
+			require.NoError(t, err)
+			if m.Resolved == nil {
+				t.Fatalf(`unexpected row %s: %s -> %s`, m.Topic, m.Key, m.Value)
+			}
+			var marker struct {
+				Transaction *transaction `json:"transaction"`
+			}
+			require.NoError(t, json.Unmarshal(m.Resolved, &marker))
+			if marker.Transaction == nil {
+				// A resolved timestamp.
+				continue
+			}
+			rowCount, ok := rowCounts[marker.Transaction.ID]
+			if !ok {
+				// The marker of a transaction already seen in another topic or
+				// partition.
+				continue
+			}
+			require.Equal(t, `complete`, marker.Transaction.Status)
+			require.Equal(t, rowCount, marker.Transaction.RowCount)
+			delete(rowCounts, marker.Transaction.ID)
+		}
+	}
+
+	t.Run(`sinkless`, sinklessTest(testFn))
+	t.Run(`enterprise`, enterpriseTest(testFn))
+	t.Run(`kafka`, kafkaTest(testFn))
+}
+
 func TestChangefeedCursor(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -1744,7 +1982,7 @@ func fetchDescVersionModificationTime(
 			if err := value.GetProto(&desc); err != nil {
 				t.Fatal(err)
 			}
-			if tableDesc, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, k.Timestamp); tableDesc != nil {
+			if tableDesc, _, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, k.Timestamp); tableDesc != nil {
 				if int(tableDesc.Version) == version {
 					return tableDesc.ModificationTime
 				}
@@ -3517,6 +3755,36 @@ func TestChangefeedErrors(t *testing.T) {
 		`EXPERIMENTAL CHANGEFEED FOR foo family f_a, foo FAMILY f_b, foo FAMILY f_a`,
 	)
 
+	sqlDB.ExpectErr(
+		t, `database "nope" does not exist`,
+		`EXPERIMENTAL CHANGEFEED FOR DATABASE nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `database "d" has no tables to watch`,
+		`EXPERIMENTAL CHANGEFEED FOR DATABASE d`,
+	)
+	sqlDB.ExpectErr(
+		t, `not supported on system tables`,
+		`EXPERIMENTAL CHANGEFEED FOR DATABASE system`,
+	)
+	sqlDB.ExpectErr(
+		t, `initial_scan_new_tables is only supported by changefeeds on a database`,
+		`EXPERIMENTAL CHANGEFEED FOR foo WITH initial_scan_new_tables`,
+	)
+
+	sqlDB.ExpectErr(
+		t, `column "nope" does not exist`,
+		`CREATE CHANGEFEED AS SELECT nope FROM foo`,
+	)
+	sqlDB.ExpectErr(
+		t, `cdc_prev can only be used in changefeeds with the diff option`,
+		`CREATE CHANGEFEED AS SELECT a FROM foo WHERE b != cdc_prev.b`,
+	)
+	sqlDB.ExpectErr(
+		t, `format=avro is not supported with a changefeed query`,
+		`CREATE CHANGEFEED WITH format=avro AS SELECT a FROM foo`,
+	)
+
 	// Backup has the same bad error message #28170.
 	sqlDB.ExpectErr(
 		t, `"information_schema.tables" does not exist`,
@@ -3687,6 +3955,33 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH topic_in_value, format='experimental_avro'`,
 		`kafka://nope`,
 	)
+	// The parquet format is only supported by cloud storage sinks.
+	sqlDB.ExpectErr(
+		t, `format=parquet is only supported by cloud storage sinks`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='parquet'`,
+		`kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `diff is not supported with format=parquet`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH diff, format='parquet'`,
+		`experimental-nodelocal://0/bar`,
+	)
+	// CSV changefeeds are exports, which must end.
+	sqlDB.ExpectErr(
+		t, `format=csv is only usable with initial_scan_only or end_time`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='csv'`,
+		`experimental-nodelocal://0/bar`,
+	)
+	sqlDB.ExpectErr(
+		t, `diff is not supported with format=csv`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH initial_scan_only, diff, format='csv'`,
+		`experimental-nodelocal://0/bar`,
+	)
+	sqlDB.ExpectErr(
+		t, `envelope=debezium is not supported with format=parquet`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH envelope='debezium', format='parquet'`,
+		`experimental-nodelocal://0/bar`,
+	)
 
 	// The topics option should not be exposed to users since it is used
 	// internally to display topics in the show changefeed jobs query
@@ -3738,6 +4033,22 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH diff, envelope='row'`, `kafka://nope`,
 	)
 
+	// WITH transaction_metadata requires format=json, envelope=wrapped and a
+	// sink which doesn't write resolved timestamps to files.
+	sqlDB.ExpectErr(
+		t, `transaction_metadata is only usable with envelope=wrapped`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH transaction_metadata, envelope='row'`, `kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `transaction_metadata is not supported with format=avro`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH transaction_metadata, format='avro', confluent_schema_registry=$2`,
+		`kafka://nope`, schemaReg.URL(),
+	)
+	sqlDB.ExpectErr(
+		t, `this sink is incompatible with option transaction_metadata`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH transaction_metadata`, `experimental-nodelocal://0/bar`,
+	)
+
 	// WITH initial_scan and no_initial_scan disallowed
 	sqlDB.ExpectErr(
 		t, `cannot specify both initial_scan and no_initial_scan`,
@@ -3930,6 +4241,28 @@ func TestChangefeedErrors(t *testing.T) {
 		`webhook-https://fake-host?client_key=Zm9v`,
 	)
 
+	// Sanity check redis and nats sink options.
+	sqlDB.ExpectErr(
+		t, `this sink is incompatible with option compression`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH compression='gzip'`,
+		`redis://fake-host`,
+	)
+	sqlDB.ExpectErr(
+		t, `invalid redis sink configuration: MaxLen must not be negative`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH redis_sink_config='{"MaxLen": -1}'`,
+		`redis://fake-host`,
+	)
+	sqlDB.ExpectErr(
+		t, `this sink is incompatible with option redis_sink_config`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH redis_sink_config='{}'`,
+		`nats://fake-host`,
+	)
+	sqlDB.ExpectErr(
+		t, `this sink is incompatible with option confluent_schema_registry`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='avro', confluent_schema_registry=$2`,
+		`nats://fake-host`, schemaReg.URL(),
+	)
+
 	// Sanity check on_error option
 	sqlDB.ExpectErr(
 		t, `option "on_error" requires a value`,
@@ -111,13 +111,30 @@ const (
 
 	OptInitialScanOnly = `initial_scan_only`
 
+	// OptInitialScanNewTables enables an initial scan of the tables which are
+	// created in the database watched by a `CHANGEFEED FOR DATABASE` after the
+	// changefeed started. The scan happens as of the time the table becomes
+	// public, which picks up rows the rangefeed would miss, e.g. the ones
+	// written by CREATE TABLE AS or IMPORT.
+	OptInitialScanNewTables = `initial_scan_new_tables`
+
+	// OptTransactionMetadata includes the commit timestamp and an identifier
+	// of the transaction in the messages of the rows it changed, and emits a
+	// message marking the transaction complete, with the number of rows it
+	// changed, once the changefeed has emitted all of them.
+	OptTransactionMetadata = `transaction_metadata`
+
 	OptEnvelopeKeyOnly       EnvelopeType = `key_only`
 	OptEnvelopeRow           EnvelopeType = `row`
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
+	OptEnvelopeDebezium      EnvelopeType = `debezium`
 
-	OptFormatJSON FormatType = `json`
-	OptFormatAvro FormatType = `avro`
+	OptFormatJSON     FormatType = `json`
+	OptFormatAvro     FormatType = `avro`
+	OptFormatParquet  FormatType = `parquet`
+	OptFormatCSV      FormatType = `csv`
+	OptFormatProtobuf FormatType = `protobuf`
 
 	OptFormatNative FormatType = `native`
 
@@ -135,6 +152,15 @@ const (
 	// OptKafkaSinkConfig is a JSON configuration for kafka sink (kafkaSinkConfig).
 	OptKafkaSinkConfig   = `kafka_sink_config`
 	OptWebhookSinkConfig = `webhook_sink_config`
+	// OptPulsarSinkConfig is a JSON configuration for pulsar sink
+	// (pulsarSinkConfig).
+	OptPulsarSinkConfig = `pulsar_sink_config`
+	// OptRedisSinkConfig is a JSON configuration for redis sink
+	// (redisSinkConfig).
+	OptRedisSinkConfig = `redis_sink_config`
+	// OptNATSSinkConfig is a JSON configuration for nats sink
+	// (natsSinkConfig).
+	OptNATSSinkConfig = `nats_sink_config`
 
 	// OptSink allows users to alter the Sink URI of an existing changefeed.
 	// Note that this option is only allowed for alter changefeed statements.
@@ -168,6 +194,13 @@ const (
 	SinkParamSASLUser               = `sasl_user`
 	SinkParamSASLPassword           = `sasl_password`
 	SinkParamSASLMechanism          = `sasl_mechanism`
+	SinkParamAuthToken              = `auth_token`
+	SinkParamPulsarTenant           = `tenant`
+	SinkParamPulsarNamespace        = `namespace`
+	SinkSchemePulsar                = `pulsar`
+	SinkSchemePulsarSSL             = `pulsar+ssl`
+	SinkSchemeRedis                 = `redis`
+	SinkSchemeNATS                  = `nats`
 
 	RegistryParamCACert = `ca_cert`
 
@@ -201,9 +234,14 @@ var ChangefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	OptInitialScan:              sql.KVStringOptAny,
 	OptNoInitialScan:            sql.KVStringOptRequireNoValue,
 	OptInitialScanOnly:          sql.KVStringOptRequireNoValue,
+	OptInitialScanNewTables:     sql.KVStringOptRequireNoValue,
+	OptTransactionMetadata:      sql.KVStringOptRequireNoValue,
 	OptProtectDataFromGCOnPause: sql.KVStringOptRequireNoValue,
 	OptKafkaSinkConfig:          sql.KVStringOptRequireValue,
 	OptWebhookSinkConfig:        sql.KVStringOptRequireValue,
+	OptPulsarSinkConfig:         sql.KVStringOptRequireValue,
+	OptRedisSinkConfig:          sql.KVStringOptRequireValue,
+	OptNATSSinkConfig:           sql.KVStringOptRequireValue,
 	OptWebhookAuthHeader:        sql.KVStringOptRequireValue,
 	OptWebhookClientTimeout:     sql.KVStringOptRequireValue,
 	OptOnError:                  sql.KVStringOptRequireValue,
@@ -227,23 +265,32 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 	OptMVCCTimestamps, OptDiff, OptSplitColumnFamilies,
 	OptSchemaChangeEvents, OptSchemaChangePolicy,
 	OptProtectDataFromGCOnPause, OptOnError,
-	OptInitialScan, OptNoInitialScan, OptInitialScanOnly,
+	OptInitialScan, OptNoInitialScan, OptInitialScanOnly, OptInitialScanNewTables,
 	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics)
 
 // SQLValidOptions is options exclusive to SQL sink
-var SQLValidOptions map[string]struct{} = nil
+var SQLValidOptions = makeStringSet(OptTransactionMetadata)
 
 // KafkaValidOptions is options exclusive to Kafka sink
-var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig)
+var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig, OptTransactionMetadata)
 
 // CloudStorageValidOptions is options exclusive to cloud storage sink
 var CloudStorageValidOptions = makeStringSet(OptCompression)
 
 // WebhookValidOptions is options exclusive to webhook sink
-var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig)
+var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig, OptTransactionMetadata)
 
 // PubsubValidOptions is options exclusice to pubsub sink
-var PubsubValidOptions = makeStringSet()
+var PubsubValidOptions = makeStringSet(OptTransactionMetadata)
+
+// PulsarValidOptions is options exclusive to pulsar sink
+var PulsarValidOptions = makeStringSet(OptPulsarSinkConfig, OptTransactionMetadata)
+
+// RedisValidOptions is options exclusive to redis sink
+var RedisValidOptions = makeStringSet(OptRedisSinkConfig, OptTransactionMetadata)
+
+// NATSValidOptions is options exclusive to nats sink
+var NATSValidOptions = makeStringSet(OptNATSSinkConfig, OptTransactionMetadata)
 
 // CaseInsensitiveOpts options which supports case Insensitive value
 var CaseInsensitiveOpts = makeStringSet(OptFormat, OptEnvelope, OptCompression, OptSchemaChangeEvents, OptSchemaChangePolicy, OptOnError)
@@ -89,6 +89,14 @@ func ValidateTable(
 	return nil
 }
 
+// IsDatabaseTargetTable returns true if a changefeed on the whole database
+// containing the table should watch it. Views, sequences and temporary tables
+// are skipped, as are tables which are not public yet or anymore.
+func IsDatabaseTargetTable(tableDesc catalog.TableDescriptor) bool {
+	return tableDesc.IsTable() && !tableDesc.IsVirtualTable() && !tableDesc.IsTemporary() &&
+		tableDesc.Public()
+}
+
 // WarningsForTable returns any known nonfatal issues with running a changefeed on this kind of table.
 func WarningsForTable(
 	targets jobspb.ChangefeedTargets, tableDesc catalog.TableDescriptor, opts map[string]string,
@@ -24,9 +24,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding/csv"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 )
 
@@ -50,6 +52,10 @@ type encodeRow struct {
 	// deleted is true if row is a deletion. In this case, only the primary
 	// key columns are guaranteed to be set in `datums`.
 	deleted bool
+	// backfill is true if the row was read by a scan of the table, either the
+	// initial scan or a backfill after a schema change, rather than emitted
+	// because it changed.
+	backfill bool
 	// tableDesc is a TableDescriptor for the table containing `datums`.
 	// It's valid for interpreting the row at `updated`.
 	tableDesc catalog.TableDescriptor
@@ -68,6 +74,11 @@ type encodeRow struct {
 	prevFamilyID descpb.FamilyID
 	// topic is set to the string to be included if TopicInValue is true
 	topic string
+	// projection is the result of the CDC query on the row, if the changefeed
+	// has one; it replaces the columns of the row in the encoded value.
+	// projectionNames are the names of its columns.
+	projection      tree.Datums
+	projectionNames []string
 }
 
 // Encoder turns a row into a serialized changefeed key, value, or resolved
@@ -89,29 +100,144 @@ type Encoder interface {
 	EncodeResolvedTimestamp(context.Context, string, hlc.Timestamp) ([]byte, error)
 }
 
+// getEncoder returns the Encoder for the format of a changefeed. clusterID is
+// the ID of the cluster running the changefeed, which is part of the source of
+// the changes with envelope=debezium.
 func getEncoder(
-	opts map[string]string, targets []jobspb.ChangefeedTargetSpecification,
+	opts map[string]string, targets []jobspb.ChangefeedTargetSpecification, clusterID uuid.UUID,
 ) (Encoder, error) {
 	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
 	case ``, changefeedbase.OptFormatJSON:
-		return makeJSONEncoder(opts, targets)
+		return makeJSONEncoder(opts, targets, clusterID)
 	case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro:
-		return newConfluentAvroEncoder(opts, targets)
+		return newConfluentAvroEncoder(opts, targets, clusterID)
 	case changefeedbase.OptFormatNative:
 		return &nativeEncoder{}, nil
+	case changefeedbase.OptFormatCSV:
+		return makeCSVEncoder(opts)
+	case changefeedbase.OptFormatProtobuf:
+		return newProtobufEncoder(opts, targets)
+	case changefeedbase.OptFormatParquet:
+		// The rows of parquet changefeeds are encoded by the sink (see
+		// SinkWithEncoder), but resolved timestamps are still written as JSON.
+		return makeJSONEncoder(opts, targets, clusterID)
 	default:
 		return nil, errors.Errorf(`unknown %s: %s`, changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
 	}
 }
 
+// The operations of row changes in the Debezium envelope.
+const (
+	debeziumOpCreate = `c`
+	debeziumOpUpdate = `u`
+	debeziumOpDelete = `d`
+	debeziumOpRead   = `r`
+)
+
+// debeziumOp returns the operation of a row change in the Debezium envelope.
+// The rows read by a scan of the table are reads, the other upserts are
+// creates or updates depending on whether the row previously existed, which is
+// why envelope=debezium implies the diff option.
+func debeziumOp(row encodeRow) string {
+	switch {
+	case row.deleted:
+		return debeziumOpDelete
+	case row.backfill:
+		return debeziumOpRead
+	case row.prevDeleted:
+		return debeziumOpCreate
+	default:
+		return debeziumOpUpdate
+	}
+}
+
+// debeziumSourceFields are the fields of the `source` block of the Debezium
+// envelope.
+var debeziumSourceFields = []string{`cluster`, `db`, `schema`, `table`, `mvcc_timestamp`}
+
+// debeziumSource returns the `source` block of the Debezium envelope for a row
+// change: the cluster running the changefeed, the database, schema and table
+// of the row, and the MVCC timestamp of the change. The names of the database
+// and of the schema are the ones at the time the changefeed was created.
+func debeziumSource(
+	clusterID uuid.UUID, targets []jobspb.ChangefeedTargetSpecification, row encodeRow,
+) map[string]interface{} {
+	source := map[string]interface{}{
+		`cluster`:        clusterID.String(),
+		`db`:             ``,
+		`schema`:         ``,
+		`table`:          row.tableDesc.GetName(),
+		`mvcc_timestamp`: row.mvccTimestamp.AsOfSystemTime(),
+	}
+	for _, target := range targets {
+		if target.TableID == row.tableDesc.GetID() {
+			source[`db`], source[`schema`] = target.DatabaseName, target.SchemaName
+			break
+		}
+	}
+	return source
+}
+
+// debeziumTimestampMillis returns the `ts_ms` field of the Debezium envelope,
+// which is the time of the change in milliseconds since the epoch.
+func debeziumTimestampMillis(row encodeRow) int64 {
+	return row.updated.WallTime / int64(time.Millisecond)
+}
+
+// transactionMetadata returns the `transaction` field of the messages of
+// changefeeds with the transaction_metadata option, which identifies the
+// transaction that committed at the given MVCC timestamp.
+//
+// Changefeeds don't know which transaction wrote a row, so the identifier is
+// derived from the commit timestamp: it is the same for every row of the
+// transaction regardless of the node which emitted it, or of restarts of the
+// changefeed. Distinct transactions which committed at the same timestamp
+// didn't conflict, so they are grouped as one.
+func transactionMetadata(mvccTimestamp hlc.Timestamp) map[string]interface{} {
+	return map[string]interface{}{
+		`id`:             fmt.Sprintf(`%016x%08x`, mvccTimestamp.WallTime, uint32(mvccTimestamp.Logical)),
+		`mvcc_timestamp`: mvccTimestamp.AsOfSystemTime(),
+	}
+}
+
+// transactionCompleteEncoder encodes the message which marks a transaction
+// complete, along with the number of rows it changed. The change frontier
+// emits it like a resolved timestamp, at the commit timestamp of the
+// transaction, once all of these rows have been emitted.
+type transactionCompleteEncoder struct {
+	Encoder
+	rowCount uint64
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e transactionCompleteEncoder) EncodeResolvedTimestamp(
+	_ context.Context, _ string, mvccTimestamp hlc.Timestamp,
+) ([]byte, error) {
+	meta := transactionMetadata(mvccTimestamp)
+	meta[`status`] = `complete`
+	meta[`row_count`] = e.rowCount
+	return gojson.Marshal(map[string]interface{}{`transaction`: meta})
+}
+
 // jsonEncoder encodes changefeed entries as JSON. Keys are the primary key
 // columns in a JSON array. Values are a JSON object mapping every column name
 // to its value. Updated timestamps in rows and resolved timestamp payloads are
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
+//
+// With envelope=debezium, values follow the format of the change events of
+// Debezium: `before` and `after` hold the values of the row, `op` is the
+// operation (see debeziumOp), `source` describes where the change comes from
+// and `ts_ms` is the time of the change.
+//
+// With the transaction_metadata option, the values of the rows which changed,
+// as opposed to the ones read by a scan of the table, have a `transaction`
+// field (see transactionMetadata).
 type jsonEncoder struct {
 	updatedField, mvccTimestampField, beforeField, wrapped, keyOnly, keyInValue, topicInValue bool
+	debezium, transactionMetadata                                                             bool
 
 	targets                 []jobspb.ChangefeedTargetSpecification
+	clusterID               uuid.UUID
 	alloc                   tree.DatumAlloc
 	buf                     bytes.Buffer
 	virtualColumnVisibility string
@@ -132,32 +258,39 @@ type tableColumnMapCacheEntry struct {
 var _ Encoder = &jsonEncoder{}
 
 func makeJSONEncoder(
-	opts map[string]string, targets []jobspb.ChangefeedTargetSpecification,
+	opts map[string]string, targets []jobspb.ChangefeedTargetSpecification, clusterID uuid.UUID,
 ) (*jsonEncoder, error) {
 	e := &jsonEncoder{
 		targets:                 targets,
+		clusterID:               clusterID,
 		keyOnly:                 changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeKeyOnly,
 		wrapped:                 changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeWrapped,
+		debezium:                changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeDebezium,
 		virtualColumnVisibility: opts[changefeedbase.OptVirtualColumns],
 		columnMapCache:          map[descpb.ID]*tableColumnMapCacheEntry{},
 	}
 	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
 	_, e.mvccTimestampField = opts[changefeedbase.OptMVCCTimestamps]
 	_, e.beforeField = opts[changefeedbase.OptDiff]
-	if e.beforeField && !e.wrapped {
+	if e.beforeField && !e.wrapped && !e.debezium {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptDiff, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
 	_, e.keyInValue = opts[changefeedbase.OptKeyInValue]
-	if e.keyInValue && !e.wrapped {
+	if e.keyInValue && !e.wrapped && !e.debezium {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptKeyInValue, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
 	_, e.topicInValue = opts[changefeedbase.OptTopicInValue]
-	if e.topicInValue && !e.wrapped {
+	if e.topicInValue && !e.wrapped && !e.debezium {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptTopicInValue, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
+	_, e.transactionMetadata = opts[changefeedbase.OptTransactionMetadata]
+	if e.transactionMetadata && !e.wrapped && !e.debezium {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptTransactionMetadata, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
 	return e, nil
 }
 
@@ -205,12 +338,25 @@ func (e *jsonEncoder) encodeKeyRaw(row encodeRow) ([]interface{}, error) {
 
 // EncodeValue implements the Encoder interface.
 func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, error) {
-	if e.keyOnly || (!e.wrapped && row.deleted) {
+	if e.keyOnly || (!e.wrapped && !e.debezium && row.deleted) {
 		return nil, nil
 	}
 
 	var after map[string]interface{}
-	if !row.deleted {
+	if row.projection != nil {
+		after = make(map[string]interface{}, len(row.projection))
+		for i, datum := range row.projection {
+			var err error
+			after[row.projectionNames[i]], err = tree.AsJSON(
+				datum,
+				sessiondatapb.DataConversionConfig{},
+				time.UTC,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if !row.deleted {
 		family, err := row.tableDesc.FindFamilyByID(row.familyID)
 		if err != nil {
 			return nil, err
@@ -274,7 +420,31 @@ func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, err
 	}
 
 	var jsonEntries map[string]interface{}
-	if e.wrapped {
+	if e.debezium {
+		jsonEntries = map[string]interface{}{
+			`before`: nil,
+			`after`:  nil,
+			`op`:     debeziumOp(row),
+			`source`: debeziumSource(e.clusterID, e.targets, row),
+			`ts_ms`:  debeziumTimestampMillis(row),
+		}
+		if before != nil {
+			jsonEntries[`before`] = before
+		}
+		if after != nil {
+			jsonEntries[`after`] = after
+		}
+		if e.keyInValue {
+			keyEntries, err := e.encodeKeyRaw(row)
+			if err != nil {
+				return nil, err
+			}
+			jsonEntries[`key`] = keyEntries
+		}
+		if e.topicInValue {
+			jsonEntries[`topic`] = row.topic
+		}
+	} else if e.wrapped {
 		if after != nil {
 			jsonEntries = map[string]interface{}{`after`: after}
 		} else {
@@ -301,9 +471,13 @@ func (e *jsonEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, err
 		jsonEntries = after
 	}
 
+	if e.transactionMetadata && !row.backfill {
+		jsonEntries[`transaction`] = transactionMetadata(row.mvccTimestamp)
+	}
+
 	if e.updatedField || e.mvccTimestampField {
 		var meta map[string]interface{}
-		if e.wrapped {
+		if e.wrapped || e.debezium {
 			meta = jsonEntries
 		} else {
 			meta = make(map[string]interface{}, 1)
@@ -334,7 +508,7 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 		`resolved`: tree.TimestampToDecimalDatum(resolved).Decimal.String(),
 	}
 	var jsonEntries interface{}
-	if e.wrapped {
+	if e.wrapped || e.debezium {
 		jsonEntries = meta
 	} else {
 		jsonEntries = map[string]interface{}{
@@ -371,11 +545,12 @@ func (e *jsonEncoder) getTableColMap(desc catalog.TableDescriptor) catalog.Table
 // JSON format. Keys are the primary key columns in a record. Values are all
 // columns in a record.
 type confluentAvroEncoder struct {
-	schemaRegistry                     schemaRegistry
-	schemaPrefix                       string
-	updatedField, beforeField, keyOnly bool
-	virtualColumnVisibility            string
-	targets                            []jobspb.ChangefeedTargetSpecification
+	schemaRegistry                               schemaRegistry
+	schemaPrefix                                 string
+	updatedField, beforeField, keyOnly, debezium bool
+	virtualColumnVisibility                      string
+	targets                                      []jobspb.ChangefeedTargetSpecification
+	clusterID                                    uuid.UUID
 
 	keyCache   *cache.UnorderedCache // [tableIDAndVersion]confluentRegisteredKeySchema
 	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]confluentRegisteredEnvelopeSchema
@@ -413,11 +588,12 @@ var encoderCacheConfig = cache.Config{
 }
 
 func newConfluentAvroEncoder(
-	opts map[string]string, targets []jobspb.ChangefeedTargetSpecification,
+	opts map[string]string, targets []jobspb.ChangefeedTargetSpecification, clusterID uuid.UUID,
 ) (*confluentAvroEncoder, error) {
 	e := &confluentAvroEncoder{
 		schemaPrefix:            opts[changefeedbase.OptAvroSchemaPrefix],
 		targets:                 targets,
+		clusterID:               clusterID,
 		virtualColumnVisibility: opts[changefeedbase.OptVirtualColumns],
 	}
 
@@ -425,6 +601,8 @@ func newConfluentAvroEncoder(
 	case string(changefeedbase.OptEnvelopeKeyOnly):
 		e.keyOnly = true
 	case string(changefeedbase.OptEnvelopeWrapped):
+	case string(changefeedbase.OptEnvelopeDebezium):
+		e.debezium = true
 	default:
 		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
 			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope], changefeedbase.OptFormat, changefeedbase.OptFormatAvro)
@@ -470,17 +648,29 @@ func newConfluentAvroEncoder(
 func (e *confluentAvroEncoder) rawTableName(
 	desc catalog.TableDescriptor, familyID descpb.FamilyID,
 ) (string, error) {
-	for _, target := range e.targets {
+	return rawTableName(e.targets, e.schemaPrefix, desc, familyID)
+}
+
+// rawTableName returns the raw SQL-formatted name of the given table, and
+// column family if the target includes it, as named by the targets of the
+// changefeed with the given prefix.
+func rawTableName(
+	targets []jobspb.ChangefeedTargetSpecification,
+	schemaPrefix string,
+	desc catalog.TableDescriptor,
+	familyID descpb.FamilyID,
+) (string, error) {
+	for _, target := range targets {
 		if target.TableID == desc.GetID() {
 			switch target.Type {
 			case jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY:
-				return e.schemaPrefix + target.StatementTimeName, nil
+				return schemaPrefix + target.StatementTimeName, nil
 			case jobspb.ChangefeedTargetSpecification_EACH_FAMILY:
 				family, err := desc.FindFamilyByID(familyID)
 				if err != nil {
 					return "", err
 				}
-				return fmt.Sprintf("%s%s.%s", e.schemaPrefix, target.StatementTimeName, family.Name), nil
+				return fmt.Sprintf("%s%s.%s", schemaPrefix, target.StatementTimeName, family.Name), nil
 			case jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY:
 				family, err := desc.FindFamilyByID(familyID)
 				if err != nil {
@@ -490,7 +680,7 @@ func (e *confluentAvroEncoder) rawTableName(
 					// Not the right target specification for this family
 					continue
 				}
-				return fmt.Sprintf("%s%s.%s", e.schemaPrefix, target.StatementTimeName, target.FamilyName), nil
+				return fmt.Sprintf("%s%s.%s", schemaPrefix, target.StatementTimeName, target.FamilyName), nil
 			default:
 				// fall through to error
 			}
@@ -579,7 +769,9 @@ func (e *confluentAvroEncoder) EncodeValue(ctx context.Context, row encodeRow) (
 			return nil, err
 		}
 
-		opts := avroEnvelopeOpts{afterField: true, beforeField: e.beforeField, updatedField: e.updatedField}
+		opts := avroEnvelopeOpts{
+			afterField: true, beforeField: e.beforeField, updatedField: e.updatedField, debeziumFields: e.debezium,
+		}
 		name, err := e.rawTableName(row.tableDesc, row.familyID)
 		if err != nil {
 			return nil, err
@@ -601,10 +793,16 @@ func (e *confluentAvroEncoder) EncodeValue(ctx context.Context, row encodeRow) (
 	}
 
 	var meta avroMetadata
+	if registered.schema.opts.updatedField || registered.schema.opts.debeziumFields {
+		meta = map[string]interface{}{}
+	}
 	if registered.schema.opts.updatedField {
-		meta = map[string]interface{}{
-			`updated`: row.updated,
-		}
+		meta[`updated`] = row.updated
+	}
+	if registered.schema.opts.debeziumFields {
+		meta[`op`] = debeziumOp(row)
+		meta[`source`] = debeziumSource(e.clusterID, e.targets, row)
+		meta[`ts_ms`] = debeziumTimestampMillis(row)
 	}
 	var beforeDatums, afterDatums rowenc.EncDatumRow
 	if row.prevDatums != nil && !row.prevDeleted {
@@ -663,7 +861,268 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 func (e *confluentAvroEncoder) register(
 	ctx context.Context, schema *avroRecord, subject string,
 ) (int32, error) {
-	return e.schemaRegistry.RegisterSchemaForSubject(ctx, subject, schema.codec.Schema())
+	return e.schemaRegistry.RegisterSchemaForSubject(
+		ctx, subject, confluentSchemaTypeAvro, schema.codec.Schema())
+}
+
+// protobufEncoder encodes changefeed entries as protobuf messages, as described
+// in protobuf.go. Keys are the primary key columns in a record. Values are the
+// columns in a record, wrapped in an envelope message.
+//
+// If a schema registry is configured, the .proto schemas of the messages are
+// registered with it and the messages are encoded in the Confluent wire format,
+// otherwise the messages are encoded as is.
+type protobufEncoder struct {
+	// schemaRegistry is nil if no schema registry is configured.
+	schemaRegistry                                         schemaRegistry
+	updatedField, mvccTimestampField, beforeField, keyOnly bool
+	virtualColumnVisibility                                string
+	targets                                                []jobspb.ChangefeedTargetSpecification
+
+	keyCache   *cache.UnorderedCache // [tableIDAndVersion]protobufRegisteredKeyMessage
+	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]protobufRegisteredEnvelopeMessage
+
+	// resolvedCache doesn't need to be bounded like the other caches because the number of topics
+	// is fixed per changefeed.
+	resolvedCache map[string]protobufRegisteredEnvelopeMessage
+}
+
+type protobufRegisteredKeyMessage struct {
+	message    *protobufDataMessage
+	registryID int32
+}
+
+type protobufRegisteredEnvelopeMessage struct {
+	message    *protobufEnvelopeMessage
+	registryID int32
+}
+
+var _ Encoder = &protobufEncoder{}
+
+func newProtobufEncoder(
+	opts map[string]string, targets []jobspb.ChangefeedTargetSpecification,
+) (*protobufEncoder, error) {
+	e := &protobufEncoder{
+		targets:                 targets,
+		virtualColumnVisibility: opts[changefeedbase.OptVirtualColumns],
+	}
+
+	switch opts[changefeedbase.OptEnvelope] {
+	case string(changefeedbase.OptEnvelopeKeyOnly):
+		e.keyOnly = true
+	case string(changefeedbase.OptEnvelopeWrapped):
+	default:
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope], changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	for _, opt := range []struct {
+		name  string
+		field *bool
+	}{
+		{changefeedbase.OptUpdatedTimestamps, &e.updatedField},
+		{changefeedbase.OptMVCCTimestamps, &e.mvccTimestampField},
+		{changefeedbase.OptDiff, &e.beforeField},
+	} {
+		_, *opt.field = opts[opt.name]
+		if *opt.field && e.keyOnly {
+			return nil, errors.Errorf(`%s is only usable with %s=%s`,
+				opt.name, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+		}
+	}
+
+	if _, ok := opts[changefeedbase.OptKeyInValue]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptKeyInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	if _, ok := opts[changefeedbase.OptTopicInValue]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptTopicInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	if registryURL := opts[changefeedbase.OptConfluentSchemaRegistry]; registryURL != `` {
+		reg, err := newConfluentSchemaRegistry(registryURL)
+		if err != nil {
+			return nil, err
+		}
+		e.schemaRegistry = reg
+	}
+	e.keyCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.valueCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.resolvedCache = make(map[string]protobufRegisteredEnvelopeMessage)
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *protobufEncoder) EncodeKey(ctx context.Context, row encodeRow) ([]byte, error) {
+	// No familyID in the cache key for keys because it's the same message for all families
+	cacheKey := tableIDAndVersion{tableID: row.tableDesc.GetID(), version: row.tableDesc.GetVersion()}
+
+	var registered protobufRegisteredKeyMessage
+	v, ok := e.keyCache.Get(cacheKey)
+	if ok {
+		registered = v.(protobufRegisteredKeyMessage)
+		registered.message.refreshTypeMetadata(row.tableDesc)
+	} else {
+		tableName, err := rawTableName(e.targets, `` /* schemaPrefix */, row.tableDesc, row.familyID)
+		if err != nil {
+			return nil, err
+		}
+		registered.message, err = indexToProtobufMessage(
+			row.tableDesc, row.tableDesc.GetPrimaryIndex(), SQLNameToAvroName(tableName)+`_key`)
+		if err != nil {
+			return nil, err
+		}
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(tableName) + confluentSubjectSuffixKey
+		registered.registryID, err = e.register(ctx, registered.message.schema(), subject)
+		if err != nil {
+			return nil, err
+		}
+		e.keyCache.Add(cacheKey, registered)
+	}
+
+	return registered.message.BinaryFromRow(e.header(registered.registryID), row.datums)
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *protobufEncoder) EncodeValue(ctx context.Context, row encodeRow) ([]byte, error) {
+	if e.keyOnly {
+		return nil, nil
+	}
+
+	var cacheKey tableIDAndVersionPair
+	if e.beforeField && row.prevTableDesc != nil {
+		cacheKey[0] = tableIDAndVersion{
+			tableID: row.prevTableDesc.GetID(), version: row.prevTableDesc.GetVersion(), familyID: row.prevFamilyID,
+		}
+	}
+	cacheKey[1] = tableIDAndVersion{
+		tableID: row.tableDesc.GetID(), version: row.tableDesc.GetVersion(), familyID: row.familyID,
+	}
+
+	var registered protobufRegisteredEnvelopeMessage
+	v, ok := e.valueCache.Get(cacheKey)
+	if ok {
+		registered = v.(protobufRegisteredEnvelopeMessage)
+		registered.message.after.refreshTypeMetadata(row.tableDesc)
+		if row.prevTableDesc != nil && registered.message.before != nil {
+			registered.message.before.refreshTypeMetadata(row.prevTableDesc)
+		}
+	} else {
+		name, err := rawTableName(e.targets, `` /* schemaPrefix */, row.tableDesc, row.familyID)
+		if err != nil {
+			return nil, err
+		}
+		messageName := SQLNameToAvroName(name)
+
+		afterMessage, err := tableToProtobufMessage(
+			row.tableDesc, row.familyID, messageName, e.virtualColumnVisibility)
+		if err != nil {
+			return nil, err
+		}
+		var beforeMessage *protobufDataMessage
+		if e.beforeField {
+			// The before field is part of the envelope even when there is no
+			// previous version of the table, in which case it is never set, so
+			// that the schema does not depend on it.
+			prevTableDesc, prevFamilyID := row.prevTableDesc, row.prevFamilyID
+			if prevTableDesc == nil {
+				prevTableDesc, prevFamilyID = row.tableDesc, row.familyID
+			}
+			beforeMessage, err = tableToProtobufMessage(
+				prevTableDesc, prevFamilyID, messageName+`_before`, e.virtualColumnVisibility)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		opts := protobufEnvelopeOpts{
+			afterField:         true,
+			beforeField:        e.beforeField,
+			updatedField:       e.updatedField,
+			mvccTimestampField: e.mvccTimestampField,
+		}
+		registered.message = envelopeToProtobufMessage(
+			messageName+`_envelope`, opts, beforeMessage, afterMessage)
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(name) + confluentSubjectSuffixValue
+		registered.registryID, err = e.register(ctx, registered.message.schema(), subject)
+		if err != nil {
+			return nil, err
+		}
+		e.valueCache.Add(cacheKey, registered)
+	}
+
+	meta := protobufMetadata{updated: row.updated, mvccTimestamp: row.mvccTimestamp}
+	var beforeDatums, afterDatums rowenc.EncDatumRow
+	if row.prevDatums != nil && !row.prevDeleted {
+		beforeDatums = row.prevDatums
+	}
+	if !row.deleted {
+		afterDatums = row.datums
+	}
+	return registered.message.BinaryFromRow(
+		e.header(registered.registryID), meta, beforeDatums, afterDatums)
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *protobufEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	registered, ok := e.resolvedCache[topic]
+	if !ok {
+		opts := protobufEnvelopeOpts{resolvedField: true}
+		registered.message = envelopeToProtobufMessage(
+			SQLNameToAvroName(topic)+`_envelope`, opts, nil /* before */, nil /* after */)
+
+		// NB: This uses the kafka name escaper because it has to match the name
+		// of the kafka topic.
+		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		var err error
+		registered.registryID, err = e.register(ctx, registered.message.schema(), subject)
+		if err != nil {
+			return nil, err
+		}
+
+		e.resolvedCache[topic] = registered
+	}
+	meta := protobufMetadata{resolved: resolved}
+	return registered.message.BinaryFromRow(
+		e.header(registered.registryID), meta, nil /* beforeRow */, nil /* afterRow */)
+}
+
+// register registers the schema with the schema registry, if one is
+// configured, and returns its ID.
+func (e *protobufEncoder) register(
+	ctx context.Context, schema *protobufSchema, subject string,
+) (int32, error) {
+	if e.schemaRegistry == nil {
+		return 0, nil
+	}
+	return e.schemaRegistry.RegisterSchemaForSubject(
+		ctx, subject, confluentSchemaTypeProtobuf, schema.String())
+}
+
+// header returns the header of the messages encoded with the schema with the
+// given ID, which is empty if no schema registry is configured.
+func (e *protobufEncoder) header(registryID int32) []byte {
+	if e.schemaRegistry == nil {
+		return nil
+	}
+	// https://docs.confluent.io/platform/current/schema-registry/serdes-develop/index.html#wire-format
+	header := []byte{
+		changefeedbase.ConfluentAvroWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+		// The indexes of the encoded message in the schema. The encoded message
+		// is always the first one, whose indexes are encoded as a single 0.
+		0,
+	}
+	binary.BigEndian.PutUint32(header[1:5], uint32(registryID))
+	return header
 }
 
 // nativeEncoder only implements EncodeResolvedTimestamp.
@@ -689,3 +1148,175 @@ func (e *nativeEncoder) EncodeResolvedTimestamp(
 }
 
 var _ Encoder = &nativeEncoder{}
+
+// csvEncoder encodes changefeed entries as CSV records. Values are the columns
+// of the row, in the order of the columns of the table, followed by the
+// metadata columns (see metaEventTypeColName). Keys are the primary key
+// columns of the row. NULLs are encoded as empty fields. Records do not
+// include the line terminator.
+//
+// The columns of a record only depend on the version of the table and on the
+// column family of the row, which is what a file of the cloud storage sink is
+// for, so its files start with the header returned by EncodeHeader.
+type csvEncoder struct {
+	updatedField, mvccTimestampField, eventTypeField bool
+	virtualColumnVisibility                          string
+
+	alloc     tree.DatumAlloc
+	formatter *tree.FmtCtx
+	buf       bytes.Buffer
+	writer    *csv.Writer
+	record    []string
+}
+
+var _ Encoder = &csvEncoder{}
+
+func makeCSVEncoder(opts map[string]string) (*csvEncoder, error) {
+	if v := changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]); v == changefeedbase.OptEnvelopeKeyOnly {
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, v, changefeedbase.OptFormat, changefeedbase.OptFormatCSV)
+	}
+	initialScanType, err := initialScanTypeFromOpts(opts)
+	if err != nil {
+		return nil, err
+	}
+	e := &csvEncoder{
+		// A changefeed which only performs an initial scan never emits
+		// deletions, so all of its rows would be upserts.
+		eventTypeField:          initialScanType != changefeedbase.OnlyInitialScan,
+		virtualColumnVisibility: opts[changefeedbase.OptVirtualColumns],
+		formatter:               tree.NewFmtCtx(tree.FmtExport),
+	}
+	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
+	_, e.mvccTimestampField = opts[changefeedbase.OptMVCCTimestamps]
+	e.writer = csv.NewWriter(&e.buf)
+	return e, nil
+}
+
+// forEachColumn calls fn with the ordinal in the public columns of the table
+// of each column which is part of the encoded value of the row.
+func (e *csvEncoder) forEachColumn(row encodeRow, fn func(int, catalog.Column) error) error {
+	family, err := row.tableDesc.FindFamilyByID(row.familyID)
+	if err != nil {
+		return err
+	}
+	include := catalog.MakeTableColSet(family.ColumnIDs...)
+	virtualNull := e.virtualColumnVisibility == string(changefeedbase.OptVirtualColumnsNull)
+	for i, col := range row.tableDesc.PublicColumns() {
+		if include.Contains(col.GetID()) || (col.IsVirtual() && virtualNull) {
+			if err := fn(i, col); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeHeader returns the record naming the columns of the values encoded
+// for the rows of the same version of the table and column family as row.
+func (e *csvEncoder) EncodeHeader(row encodeRow) ([]byte, error) {
+	e.record = e.record[:0]
+	if err := e.forEachColumn(row, func(_ int, col catalog.Column) error {
+		e.record = append(e.record, col.GetName())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if e.eventTypeField {
+		e.record = append(e.record, metaEventTypeColName)
+	}
+	if e.updatedField {
+		e.record = append(e.record, metaUpdatedColName)
+	}
+	if e.mvccTimestampField {
+		e.record = append(e.record, metaMVCCTimestampColName)
+	}
+	return e.writeRecord()
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *csvEncoder) EncodeKey(_ context.Context, row encodeRow) ([]byte, error) {
+	primaryIndex := row.tableDesc.GetPrimaryIndex()
+	e.record = e.record[:0]
+	for i := 0; i < primaryIndex.NumKeyColumns(); i++ {
+		col, err := row.tableDesc.FindColumnWithID(primaryIndex.GetKeyColumnID(i))
+		if err != nil {
+			return nil, err
+		}
+		field, err := e.formatField(row.datums[col.Ordinal()], col)
+		if err != nil {
+			return nil, err
+		}
+		e.record = append(e.record, field)
+	}
+	return e.writeRecord()
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *csvEncoder) EncodeValue(_ context.Context, row encodeRow) ([]byte, error) {
+	e.record = e.record[:0]
+	if err := e.forEachColumn(row, func(i int, col catalog.Column) error {
+		// Only the primary key columns are set for deleted rows, the other
+		// fields are left empty.
+		field, err := e.formatField(row.datums[i], col)
+		if err != nil {
+			return err
+		}
+		e.record = append(e.record, field)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if e.eventTypeField {
+		if row.deleted {
+			e.record = append(e.record, metaEventTypeDelete)
+		} else {
+			e.record = append(e.record, metaEventTypeUpsert)
+		}
+	}
+	if e.updatedField {
+		e.record = append(e.record, row.updated.AsOfSystemTime())
+	}
+	if e.mvccTimestampField {
+		e.record = append(e.record, row.mvccTimestamp.AsOfSystemTime())
+	}
+	return e.writeRecord()
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *csvEncoder) EncodeResolvedTimestamp(
+	_ context.Context, _ string, _ hlc.Timestamp,
+) ([]byte, error) {
+	return nil, errors.Errorf(`%s is not supported with %s=%s`,
+		changefeedbase.OptResolvedTimestamps, changefeedbase.OptFormat, changefeedbase.OptFormatCSV)
+}
+
+// formatField formats a datum like EXPORT does, except that NULLs are always
+// encoded as empty fields.
+func (e *csvEncoder) formatField(datum rowenc.EncDatum, col catalog.Column) (string, error) {
+	if datum.IsUnset() {
+		return ``, nil
+	}
+	if err := datum.EnsureDecoded(col.GetType(), &e.alloc); err != nil {
+		return ``, err
+	}
+	if datum.Datum == tree.DNull {
+		return ``, nil
+	}
+	e.formatter.Reset()
+	datum.Datum.Format(e.formatter)
+	return e.formatter.String(), nil
+}
+
+// writeRecord encodes the fields in e.record, without the line terminator.
+func (e *csvEncoder) writeRecord() ([]byte, error) {
+	e.buf.Reset()
+	if err := e.writer.Write(e.record); err != nil {
+		return nil, err
+	}
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(e.buf.Bytes(), []byte{'\n'}), nil
+}
@@ -12,6 +12,7 @@ import (
 	"context"
 	gosql "database/sql"
 	"fmt"
+	"math"
 	"net/url"
 	"testing"
 
@@ -26,9 +27,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/cockroach/pkg/workload/ledger"
 	"github.com/cockroachdb/cockroach/pkg/workload/workloadsql"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
 func TestEncoders(t *testing.T) {
@@ -216,7 +219,7 @@ func TestEncoders(t *testing.T) {
 			}
 			targets := []jobspb.ChangefeedTargetSpecification{target}
 
-			e, err := getEncoder(o, targets)
+			e, err := getEncoder(o, targets, uuid.UUID{})
 			if len(expected.err) > 0 {
 				require.EqualError(t, err, expected.err)
 				return
@@ -259,6 +262,384 @@ func TestEncoders(t *testing.T) {
 	}
 }
 
+func TestCSVEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c STRING)`)
+	require.NoError(t, err)
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+	insert := encodeRow{
+		datums: rowenc.EncDatumRow{
+			rowenc.EncDatum{Datum: tree.NewDInt(1)},
+			rowenc.EncDatum{Datum: tree.NewDString(`bar, "baz"`)},
+			rowenc.EncDatum{Datum: tree.DNull},
+		},
+		updated:   ts,
+		tableDesc: tableDesc,
+	}
+	// Only the primary key is set for deletions.
+	deletion := encodeRow{
+		datums: rowenc.EncDatumRow{
+			rowenc.EncDatum{Datum: tree.NewDInt(2)}, {}, {},
+		},
+		deleted:   true,
+		updated:   ts,
+		tableDesc: tableDesc,
+	}
+
+	for _, tc := range []struct {
+		name     string
+		opts     map[string]string
+		header   string
+		insert   string
+		deletion string
+	}{
+		{
+			name:   `initial scan only`,
+			opts:   map[string]string{changefeedbase.OptInitialScanOnly: ``},
+			header: `a,b,c`,
+			insert: `1,"bar, ""baz""",`,
+		},
+		{
+			name: `end time`,
+			opts: map[string]string{
+				changefeedbase.OptEndTime:           `1`,
+				changefeedbase.OptUpdatedTimestamps: ``,
+			},
+			header:   `a,b,c,__crdb__event_type,__crdb__updated`,
+			insert:   `1,"bar, ""baz""",,upsert,1.0000000002`,
+			deletion: `2,,,delete,1.0000000002`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.opts[changefeedbase.OptFormat] = string(changefeedbase.OptFormatCSV)
+			e, err := getEncoder(tc.opts, nil /* targets */, uuid.UUID{})
+			require.NoError(t, err)
+			csvEncoder := e.(*csvEncoder)
+
+			header, err := csvEncoder.EncodeHeader(insert)
+			require.NoError(t, err)
+			require.Equal(t, tc.header, string(header))
+			key, err := e.EncodeKey(ctx, insert)
+			require.NoError(t, err)
+			require.Equal(t, `1`, string(key))
+			value, err := e.EncodeValue(ctx, insert)
+			require.NoError(t, err)
+			require.Equal(t, tc.insert, string(value))
+			if tc.deletion != `` {
+				value, err := e.EncodeValue(ctx, deletion)
+				require.NoError(t, err)
+				require.Equal(t, tc.deletion, string(value))
+			}
+
+			_, err = e.EncodeResolvedTimestamp(ctx, tableDesc.GetName(), ts)
+			require.EqualError(t, err, `resolved is not supported with format=csv`)
+		})
+	}
+
+	_, err = getEncoder(map[string]string{
+		changefeedbase.OptFormat:          string(changefeedbase.OptFormatCSV),
+		changefeedbase.OptEnvelope:        string(changefeedbase.OptEnvelopeKeyOnly),
+		changefeedbase.OptInitialScanOnly: ``,
+	}, nil /* targets */, uuid.UUID{})
+	require.EqualError(t, err, `envelope=key_only is not supported with format=csv`)
+}
+
+func TestDebeziumEnvelope(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	targets := []jobspb.ChangefeedTargetSpecification{{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           tableDesc.GetID(),
+		StatementTimeName: `foo`,
+		DatabaseName:      `d`,
+		SchemaName:        `public`,
+	}}
+	clusterID := uuid.MakeV4()
+	ts := hlc.Timestamp{WallTime: 1650000000123456789}
+	v1 := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+	}
+	v2 := rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.NewDString(`baz`)},
+	}
+	change := func(datums, prevDatums rowenc.EncDatumRow, deleted, prevDeleted, backfill bool) encodeRow {
+		return encodeRow{
+			datums:        datums,
+			deleted:       deleted,
+			backfill:      backfill,
+			prevDatums:    prevDatums,
+			prevDeleted:   prevDeleted,
+			updated:       ts,
+			mvccTimestamp: ts,
+			tableDesc:     tableDesc,
+			prevTableDesc: tableDesc,
+		}
+	}
+	source := fmt.Sprintf(`{"cluster": "%s", "db": "d", "mvcc_timestamp": "%s", "schema": "public", "table": "foo"}`,
+		clusterID, ts.AsOfSystemTime())
+
+	opts := map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeDebezium),
+		changefeedbase.OptDiff:     ``,
+	}
+	e, err := getEncoder(opts, targets, clusterID)
+	require.NoError(t, err)
+	for _, tc := range []struct {
+		name     string
+		row      encodeRow
+		expected string
+	}{
+		{
+			name: `read`,
+			row:  change(v1, v1, false /* deleted */, true /* prevDeleted */, true /* backfill */),
+			expected: `{"after": {"a": 1, "b": "bar"}, "before": null, "op": "r", "source": ` + source +
+				`, "ts_ms": 1650000000123}`,
+		},
+		{
+			name: `create`,
+			row:  change(v1, v1, false /* deleted */, true /* prevDeleted */, false /* backfill */),
+			expected: `{"after": {"a": 1, "b": "bar"}, "before": null, "op": "c", "source": ` + source +
+				`, "ts_ms": 1650000000123}`,
+		},
+		{
+			name: `update`,
+			row:  change(v2, v1, false /* deleted */, false /* prevDeleted */, false /* backfill */),
+			expected: `{"after": {"a": 1, "b": "baz"}, "before": {"a": 1, "b": "bar"}, "op": "u", "source": ` + source +
+				`, "ts_ms": 1650000000123}`,
+		},
+		{
+			name: `delete`,
+			row:  change(v2, v2, true /* deleted */, false /* prevDeleted */, false /* backfill */),
+			expected: `{"after": null, "before": {"a": 1, "b": "baz"}, "op": "d", "source": ` + source +
+				`, "ts_ms": 1650000000123}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := e.EncodeValue(ctx, tc.row)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(value))
+		})
+	}
+
+	t.Run(`avro`, func(t *testing.T) {
+		after, err := tableToAvroSchema(tableDesc, primary, avroSchemaNoSuffix, ``, ``)
+		require.NoError(t, err)
+		before, err := tableToAvroSchema(tableDesc, primary, `before`, ``, ``)
+		require.NoError(t, err)
+		envelope, err := envelopeToAvroSchema(`foo`, avroEnvelopeOpts{
+			beforeField: true, afterField: true, debeziumFields: true,
+		}, before, after, ``)
+		require.NoError(t, err)
+
+		row := change(v2, v1, false /* deleted */, false /* prevDeleted */, false /* backfill */)
+		meta := avroMetadata{
+			`op`:     debeziumOp(row),
+			`source`: debeziumSource(clusterID, targets, row),
+			`ts_ms`:  debeziumTimestampMillis(row),
+		}
+		buf, err := envelope.BinaryFromRow(nil, meta, v1, v2)
+		require.NoError(t, err)
+		native, _, err := envelope.codec.NativeFromBinary(buf)
+		require.NoError(t, err)
+		fields := native.(map[string]interface{})
+		require.Equal(t, map[string]interface{}{`string`: `u`}, fields[`op`])
+		require.Equal(t, map[string]interface{}{`long`: int64(1650000000123)}, fields[`ts_ms`])
+		sourceFields := fields[`source`].(map[string]interface{})[`foo_source`].(map[string]interface{})
+		require.Equal(t, map[string]interface{}{`string`: `d`}, sourceFields[`db`])
+		require.Equal(t, map[string]interface{}{`string`: clusterID.String()}, sourceFields[`cluster`])
+	})
+}
+
+func TestProtobufEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c FLOAT4, d DECIMAL)`)
+	require.NoError(t, err)
+	targets := []jobspb.ChangefeedTargetSpecification{{
+		Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:           tableDesc.GetID(),
+		StatementTimeName: `foo`,
+	}}
+	ts := hlc.Timestamp{WallTime: 1, Logical: 2}
+	insert := encodeRow{
+		datums: rowenc.EncDatumRow{
+			rowenc.EncDatum{Datum: tree.NewDInt(1)},
+			rowenc.EncDatum{Datum: tree.NewDString(`bar`)},
+			rowenc.EncDatum{Datum: tree.NewDFloat(1.5)},
+			rowenc.EncDatum{Datum: tree.DNull},
+		},
+		updated:       ts,
+		tableDesc:     tableDesc,
+		familyID:      primary,
+		prevDeleted:   true,
+		prevTableDesc: tableDesc,
+		prevFamilyID:  primary,
+	}
+	decimal, err := tree.ParseDDecimal(`1.50`)
+	require.NoError(t, err)
+	update := insert
+	update.datums = rowenc.EncDatumRow{
+		rowenc.EncDatum{Datum: tree.NewDInt(1)},
+		rowenc.EncDatum{Datum: tree.DNull},
+		rowenc.EncDatum{Datum: tree.DNull},
+		rowenc.EncDatum{Datum: decimal},
+	}
+	update.prevDatums, update.prevDeleted = insert.datums, false
+
+	// The expected messages are built by hand from the field numbers, which
+	// are the IDs of the columns.
+	appendString := func(buf []byte, num protowire.Number, s string) []byte {
+		buf = protowire.AppendTag(buf, num, protowire.BytesType)
+		return protowire.AppendString(buf, s)
+	}
+	appendMessage := func(buf []byte, num protowire.Number, m []byte) []byte {
+		buf = protowire.AppendTag(buf, num, protowire.BytesType)
+		return protowire.AppendBytes(buf, m)
+	}
+	var key []byte
+	key = protowire.AppendTag(key, 1, protowire.VarintType)
+	key = protowire.AppendVarint(key, 1)
+	insertAfter := appendString(append([]byte(nil), key...), 2, `bar`)
+	insertAfter = protowire.AppendTag(insertAfter, 3, protowire.Fixed32Type)
+	insertAfter = protowire.AppendFixed32(insertAfter, math.Float32bits(1.5))
+	updateAfter := appendString(append([]byte(nil), key...), 4, `1.50`)
+
+	const keySchema = `syntax = "proto3";
+
+message foo_key {
+  optional int64 a = 1; // INT8
+}
+`
+	const valueSchema = `syntax = "proto3";
+
+message foo_envelope {
+  foo after = 1;
+  foo_before before = 2;
+  string updated = 3;
+}
+
+message foo {
+  optional int64 a = 1; // INT8
+  optional string b = 2; // STRING
+  optional float c = 3; // FLOAT4
+  optional string d = 4; // DECIMAL
+}
+
+message foo_before {
+  optional int64 a = 1; // INT8
+  optional string b = 2; // STRING
+  optional float c = 3; // FLOAT4
+  optional string d = 4; // DECIMAL
+}
+`
+	const resolvedSchema = `syntax = "proto3";
+
+message foo_envelope {
+  string resolved = 5;
+}
+`
+
+	for _, withRegistry := range []bool{false, true} {
+		t.Run(fmt.Sprintf(`registry=%t`, withRegistry), func(t *testing.T) {
+			opts := map[string]string{
+				changefeedbase.OptFormat:            string(changefeedbase.OptFormatProtobuf),
+				changefeedbase.OptEnvelope:          string(changefeedbase.OptEnvelopeWrapped),
+				changefeedbase.OptDiff:              ``,
+				changefeedbase.OptUpdatedTimestamps: ``,
+			}
+			var reg *cdctest.SchemaRegistry
+			if withRegistry {
+				reg = cdctest.StartTestSchemaRegistry()
+				defer reg.Close()
+				opts[changefeedbase.OptConfluentSchemaRegistry] = reg.URL()
+			}
+			// header returns the header of the Confluent wire format of the
+			// messages with the given schema ID, if there is a schema registry.
+			header := func(id byte) []byte {
+				if reg == nil {
+					return nil
+				}
+				return []byte{0, 0, 0, 0, id, 0}
+			}
+			e, err := getEncoder(opts, targets, uuid.UUID{})
+			require.NoError(t, err)
+
+			encodedKey, err := e.EncodeKey(ctx, insert)
+			require.NoError(t, err)
+			require.Equal(t, append(header(0), key...), encodedKey)
+
+			value, err := e.EncodeValue(ctx, insert)
+			require.NoError(t, err)
+			expected := appendMessage(header(1), 1, insertAfter)
+			expected = appendString(expected, 3, `1.0000000002`)
+			require.Equal(t, expected, value)
+
+			value, err = e.EncodeValue(ctx, update)
+			require.NoError(t, err)
+			expected = appendMessage(header(1), 1, updateAfter)
+			expected = appendMessage(expected, 2, insertAfter)
+			expected = appendString(expected, 3, `1.0000000002`)
+			require.Equal(t, expected, value)
+
+			if reg != nil {
+				require.Equal(t, keySchema, reg.SchemaForSubject(`foo-key`))
+				require.Equal(t, `PROTOBUF`, reg.SchemaTypeForSubject(`foo-key`))
+				require.Equal(t, valueSchema, reg.SchemaForSubject(`foo-value`))
+				require.Equal(t, `PROTOBUF`, reg.SchemaTypeForSubject(`foo-value`))
+			}
+
+			// The resolved messages are registered under the subject of the
+			// values.
+			resolved, err := e.EncodeResolvedTimestamp(ctx, `foo`, ts)
+			require.NoError(t, err)
+			require.Equal(t, appendString(header(2), 5, `1.0000000002`), resolved)
+			if reg != nil {
+				require.Equal(t, resolvedSchema, reg.SchemaForSubject(`foo-value`))
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		opts map[string]string
+		err  string
+	}{
+		{
+			opts: map[string]string{changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeRow)},
+			err:  `envelope=row is not supported with format=protobuf`,
+		},
+		{
+			opts: map[string]string{
+				changefeedbase.OptEnvelope:       string(changefeedbase.OptEnvelopeKeyOnly),
+				changefeedbase.OptMVCCTimestamps: ``,
+			},
+			err: `mvcc_timestamp is only usable with envelope=wrapped`,
+		},
+		{
+			opts: map[string]string{
+				changefeedbase.OptEnvelope:   string(changefeedbase.OptEnvelopeWrapped),
+				changefeedbase.OptKeyInValue: ``,
+			},
+			err: `key_in_value is not supported with format=protobuf`,
+		},
+	} {
+		tc.opts[changefeedbase.OptFormat] = string(changefeedbase.OptFormatProtobuf)
+		_, err := getEncoder(tc.opts, targets, uuid.UUID{})
+		require.EqualError(t, err, tc.err)
+	}
+}
+
 func TestAvroEncoder(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -368,7 +749,7 @@ func TestAvroEncoderWithTLS(t *testing.T) {
 		}
 		targets := []jobspb.ChangefeedTargetSpecification{target}
 
-		e, err := getEncoder(opts, targets)
+		e, err := getEncoder(opts, targets, uuid.UUID{})
 		require.NoError(t, err)
 
 		rowInsert := encodeRow{
@@ -409,7 +790,7 @@ func TestAvroEncoderWithTLS(t *testing.T) {
 		defer noCertReg.Close()
 		opts[changefeedbase.OptConfluentSchemaRegistry] = noCertReg.URL()
 
-		enc, err := getEncoder(opts, targets)
+		enc, err := getEncoder(opts, targets, uuid.UUID{})
 		require.NoError(t, err)
 		_, err = enc.EncodeKey(context.Background(), rowInsert)
 		require.EqualError(t, err, fmt.Sprintf("retryable changefeed error: "+
@@ -424,7 +805,7 @@ func TestAvroEncoderWithTLS(t *testing.T) {
 		defer wrongCertReg.Close()
 		opts[changefeedbase.OptConfluentSchemaRegistry] = wrongCertReg.URL()
 
-		enc, err = getEncoder(opts, targets)
+		enc, err = getEncoder(opts, targets, uuid.UUID{})
 		require.NoError(t, err)
 		_, err = enc.EncodeKey(context.Background(), rowInsert)
 		require.EqualError(t, err, fmt.Sprintf("retryable changefeed error: "+
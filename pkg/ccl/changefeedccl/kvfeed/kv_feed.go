@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -261,7 +262,8 @@ func (f *kvFeed) run(ctx context.Context) (err error) {
 		boundaryType := jobspb.ResolvedSpan_BACKFILL
 		if f.schemaChangePolicy == changefeedbase.OptSchemaChangePolicyStop {
 			boundaryType = jobspb.ResolvedSpan_EXIT
-		} else if events, err := f.tableFeed.Peek(ctx, highWater.Next()); err == nil && isPrimaryKeyChange(events) {
+		} else if events, err := f.tableFeed.Peek(ctx, highWater.Next()); err == nil &&
+			(isPrimaryKeyChange(events) || f.isTargetSetChange(events)) {
 			boundaryType = jobspb.ResolvedSpan_RESTART
 		} else if err != nil {
 			return err
@@ -291,6 +293,33 @@ func isPrimaryKeyChange(events []schemafeed.TableEvent) bool {
 	return false
 }
 
+// isTargetSetChange returns true if the events add a table which the feed does
+// not watch yet or drop one it watches, which requires changefeeds on a whole
+// database to restart with a new set of targets.
+func (f *kvFeed) isTargetSetChange(events []schemafeed.TableEvent) bool {
+	for _, ev := range events {
+		if schemafeed.IsTableDropped(ev) {
+			return true
+		}
+		if schemafeed.IsTableAdded(ev) && !f.watchesTable(ev.After.GetID()) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchesTable returns true if the spans of the feed cover the given table.
+func (f *kvFeed) watchesTable(id descpb.ID) bool {
+	tablePrefix := f.codec.TablePrefix(uint32(id))
+	tableSpan := roachpb.Span{Key: tablePrefix, EndKey: tablePrefix.PrefixEnd()}
+	for _, sp := range f.spans {
+		if tableSpan.Overlaps(sp) {
+			return true
+		}
+	}
+	return false
+}
+
 // filterCheckpointSpans filters spans which have already been completed,
 // and returns the list of spans that still need to be done.
 func filterCheckpointSpans(spans []roachpb.Span, completed []roachpb.Span) []roachpb.Span {
@@ -333,6 +362,12 @@ func (f *kvFeed) scanIfShould(
 			if schemafeed.IsOnlyPrimaryIndexChange(ev) {
 				continue
 			}
+			// Dropped tables don't need a backfill either, the changefeed restarts
+			// without them. Tables added to a changefeed on a whole database are
+			// backfilled like any other table below.
+			if schemafeed.IsTableDropped(ev) {
+				continue
+			}
 			tablePrefix := f.codec.TablePrefix(uint32(ev.After.GetID()))
 			tableSpan := roachpb.Span{Key: tablePrefix, EndKey: tablePrefix.PrefixEnd()}
 			for _, sp := range f.spans {
@@ -56,6 +56,12 @@ type AggMetrics struct {
 	AdmitLatency          *aggmetric.AggHistogram
 	RunningCount          *aggmetric.AggGauge
 
+	// Metrics of the sinks publishing to streaming services.
+	RedisPublishedMessages *aggmetric.AggCounter
+	RedisPublishRetries    *aggmetric.AggCounter
+	NATSPublishedMessages  *aggmetric.AggCounter
+	NATSPublishRetries     *aggmetric.AggCounter
+
 	// There is always at least 1 sliMetrics created for defaultSLI scope.
 	mu struct {
 		syncutil.Mutex
@@ -81,6 +87,11 @@ type sliMetrics struct {
 	BackfillCount         *aggmetric.Gauge
 	BackfillPendingRanges *aggmetric.Gauge
 	RunningCount          *aggmetric.Gauge
+
+	RedisPublishedMessages *aggmetric.Counter
+	RedisPublishRetries    *aggmetric.Counter
+	NATSPublishedMessages  *aggmetric.Counter
+	NATSPublishRetries     *aggmetric.Counter
 }
 
 // sinkDoesNotCompress is a sentinel value indicating the sink
@@ -152,6 +163,39 @@ func (m *sliMetrics) recordFlushRequestCallback() func() {
 	}
 }
 
+// streamSinkMetrics are the metrics of the sinks publishing to a streaming
+// service. The zero value records nothing.
+type streamSinkMetrics struct {
+	published *aggmetric.Counter
+	retries   *aggmetric.Counter
+}
+
+func (m streamSinkMetrics) recordPublished(numMessages int) {
+	if m.published != nil {
+		m.published.Inc(int64(numMessages))
+	}
+}
+
+func (m streamSinkMetrics) recordRetry() {
+	if m.retries != nil {
+		m.retries.Inc(1)
+	}
+}
+
+func (m *sliMetrics) redisSinkMetrics() streamSinkMetrics {
+	if m == nil {
+		return streamSinkMetrics{}
+	}
+	return streamSinkMetrics{published: m.RedisPublishedMessages, retries: m.RedisPublishRetries}
+}
+
+func (m *sliMetrics) natsSinkMetrics() streamSinkMetrics {
+	if m == nil {
+		return streamSinkMetrics{}
+	}
+	return streamSinkMetrics{published: m.NATSPublishedMessages, retries: m.NATSPublishRetries}
+}
+
 func (m *sliMetrics) getBackfillCallback() func() func() {
 	return func() func() {
 		m.BackfillCount.Inc(1)
@@ -324,6 +368,30 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 		Measurement: "Bytes",
 		Unit:        metric.Unit_BYTES,
 	}
+	metaRedisPublishedMessages := metric.Metadata{
+		Name:        "changefeed.redis.published_messages",
+		Help:        "Messages, including resolved timestamps, appended to redis streams by all feeds",
+		Measurement: "Messages",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaRedisPublishRetries := metric.Metadata{
+		Name:        "changefeed.redis.publish_retries",
+		Help:        "Retries of batches of messages which redis failed to append to streams",
+		Measurement: "Retries",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaNATSPublishedMessages := metric.Metadata{
+		Name:        "changefeed.nats.published_messages",
+		Help:        "Messages, including resolved timestamps, acknowledged by NATS JetStream for all feeds",
+		Measurement: "Messages",
+		Unit:        metric.Unit_COUNT,
+	}
+	metaNATSPublishRetries := metric.Metadata{
+		Name:        "changefeed.nats.publish_retries",
+		Help:        "Retries of batches of messages which NATS JetStream failed to acknowledge",
+		Measurement: "Retries",
+		Unit:        metric.Unit_COUNT,
+	}
 	// NB: When adding new histograms, use sigFigs = 1.  Older histograms
 	// retain significant figures of 2.
 	b := aggmetric.MakeBuilder("scope")
@@ -347,6 +415,11 @@ func newAggregateMetrics(histogramWindow time.Duration) *AggMetrics {
 		BackfillCount:         b.Gauge(metaChangefeedBackfillCount),
 		BackfillPendingRanges: b.Gauge(metaChangefeedBackfillPendingRanges),
 		RunningCount:          b.Gauge(metaChangefeedRunning),
+
+		RedisPublishedMessages: b.Counter(metaRedisPublishedMessages),
+		RedisPublishRetries:    b.Counter(metaRedisPublishRetries),
+		NATSPublishedMessages:  b.Counter(metaNATSPublishedMessages),
+		NATSPublishRetries:     b.Counter(metaNATSPublishRetries),
 	}
 	a.mu.sliMetrics = make(map[string]*sliMetrics)
 	_, err := a.getOrCreateScope(defaultSLIScope)
@@ -404,6 +477,11 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 		BackfillCount:         a.BackfillCount.AddChild(scope),
 		BackfillPendingRanges: a.BackfillPendingRanges.AddChild(scope),
 		RunningCount:          a.RunningCount.AddChild(scope),
+
+		RedisPublishedMessages: a.RedisPublishedMessages.AddChild(scope),
+		RedisPublishRetries:    a.RedisPublishRetries.AddChild(scope),
+		NATSPublishedMessages:  a.NATSPublishedMessages.AddChild(scope),
+		NATSPublishRetries:     a.NATSPublishRetries.AddChild(scope),
 	}
 
 	a.mu.sliMetrics[scope] = sm
@@ -0,0 +1,153 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/importer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// parquetMaxRowGroupSize is the approximate size in bytes at which the rows
+// buffered by a parquetWriter are flushed as a row group.
+const parquetMaxRowGroupSize = 4 << 20 // 4MB
+
+// parquetWriter writes the rows of one version of a table (and one column
+// family) to a parquet file. The schema of the file is derived from the table
+// descriptor of the first row written to it, which is why the cloud storage
+// sink starts a new file for every version of the table.
+type parquetWriter struct {
+	w *goparquet.FileWriter
+
+	// cols are the columns of the file: the columns of the table followed by
+	// the metadata columns. colIdx has, for each column of the table, its
+	// ordinal in the public columns of the descriptor.
+	cols     []importer.ParquetColumn
+	colIdx   []int
+	colTypes []*types.T
+
+	updatedField, mvccTimestampField bool
+
+	alloc  tree.DatumAlloc
+	record map[string]interface{}
+}
+
+func newParquetWriter(
+	w io.Writer, row encodeRow, opts map[string]string, compression string,
+) (*parquetWriter, error) {
+	family, err := row.tableDesc.FindFamilyByID(row.familyID)
+	if err != nil {
+		return nil, err
+	}
+	include := catalog.MakeTableColSet(family.ColumnIDs...)
+	virtualNull := opts[changefeedbase.OptVirtualColumns] == string(changefeedbase.OptVirtualColumnsNull)
+
+	pw := &parquetWriter{}
+	_, pw.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
+	_, pw.mvccTimestampField = opts[changefeedbase.OptMVCCTimestamps]
+	for i, col := range row.tableDesc.PublicColumns() {
+		if !include.Contains(col.GetID()) && !(col.IsVirtual() && virtualNull) {
+			continue
+		}
+		// All the columns are nullable, since only the primary key columns of
+		// deleted rows are known.
+		parquetCol, err := importer.NewParquetColumn(col.GetType(), col.GetName(), true /* nullable */)
+		if err != nil {
+			return nil, err
+		}
+		pw.cols = append(pw.cols, parquetCol)
+		pw.colIdx = append(pw.colIdx, i)
+		pw.colTypes = append(pw.colTypes, col.GetType())
+	}
+
+	metaCols := []string{metaEventTypeColName}
+	if pw.updatedField {
+		metaCols = append(metaCols, metaUpdatedColName)
+	}
+	if pw.mvccTimestampField {
+		metaCols = append(metaCols, metaMVCCTimestampColName)
+	}
+	for _, name := range metaCols {
+		parquetCol, err := importer.NewParquetColumn(types.String, name, false /* nullable */)
+		if err != nil {
+			return nil, err
+		}
+		pw.cols = append(pw.cols, parquetCol)
+	}
+
+	codec := parquet.CompressionCodec_UNCOMPRESSED
+	if compression == sinkCompressionGzip {
+		codec = parquet.CompressionCodec_GZIP
+	}
+	pw.w = goparquet.NewFileWriter(w,
+		goparquet.WithCompressionCodec(codec),
+		goparquet.WithSchemaDefinition(importer.NewParquetSchema(pw.cols)),
+		goparquet.WithMaxRowGroupSize(parquetMaxRowGroupSize),
+	)
+	pw.record = make(map[string]interface{}, len(pw.cols))
+	return pw, nil
+}
+
+// addRow buffers a row in the current row group of the file.
+func (pw *parquetWriter) addRow(row encodeRow, updated, mvcc hlc.Timestamp) error {
+	for i, idx := range pw.colIdx {
+		var d tree.Datum = tree.DNull
+		// Only the primary key columns are set for deleted rows.
+		if ed := row.datums[idx]; !ed.IsUnset() {
+			if err := ed.EnsureDecoded(pw.colTypes[i], &pw.alloc); err != nil {
+				return err
+			}
+			d = ed.Datum
+		}
+		col := &pw.cols[i]
+		v, err := col.EncodeDatum(d)
+		if err != nil {
+			return err
+		}
+		pw.record[col.Name()] = v
+	}
+
+	meta := []string{metaEventTypeUpsert}
+	if row.deleted {
+		meta[0] = metaEventTypeDelete
+	}
+	if pw.updatedField {
+		meta = append(meta, updated.AsOfSystemTime())
+	}
+	if pw.mvccTimestampField {
+		meta = append(meta, mvcc.AsOfSystemTime())
+	}
+	for i, m := range meta {
+		col := &pw.cols[len(pw.colIdx)+i]
+		v, err := col.EncodeDatum(tree.NewDString(m))
+		if err != nil {
+			return err
+		}
+		pw.record[col.Name()] = v
+	}
+	return pw.w.AddData(pw.record)
+}
+
+// size returns an estimate of the size of the file, including the rows which
+// are buffered in the current row group.
+func (pw *parquetWriter) size() int64 {
+	return pw.w.CurrentFileSize() + pw.w.CurrentRowGroupSize()
+}
+
+// close flushes the buffered rows and writes the footer of the file.
+func (pw *parquetWriter) close() error {
+	return pw.w.Close()
+}
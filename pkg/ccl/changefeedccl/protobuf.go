@@ -0,0 +1,392 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// This file maps SQL tables to protobuf messages, in the same spirit as the
+// mapping to Avro records in avro.go.
+//
+// Each version of a table (and column family) is mapped to a proto3 message
+// with one field per column. The number of the field of a column is the ID of
+// the column, which never changes nor is reused in a table, so the messages of
+// all the versions of a table are wire compatible with each other: consumers
+// built with the message of an older version skip the fields of the columns
+// that were added since, and see the columns that were dropped as unset.
+//
+// All the fields are optional, regardless of whether the column allows NULLs,
+// so that a NULL is told apart from the zero value of the type of the column.
+// Columns whose type has no native protobuf counterpart, such as DECIMAL or
+// TIMESTAMP, are mapped to strings holding the same text as format=csv.
+//
+// The messages of the values are wrapped in an envelope message, which is the
+// first message of the schema of the values, so that its index is 0 in the
+// Confluent wire format.
+
+// protobufField is a field of a protobufMessage.
+type protobufField struct {
+	name     string
+	number   protowire.Number
+	typeName string
+	// optional is whether the field is declared optional, that is with
+	// explicit presence. Fields of message types always have it.
+	optional bool
+	comment  string
+}
+
+// protobufMessage is a message of a protobufSchema.
+type protobufMessage struct {
+	name   string
+	fields []protobufField
+}
+
+// protobufDataMessage is the message of the columns of a table, or of the
+// primary key of a table.
+type protobufDataMessage struct {
+	protobufMessage
+	colIdxByFieldIdx []int
+	colTypes         []*types.T
+
+	alloc tree.DatumAlloc
+}
+
+// protobufEnvelopeOpts controls which fields of an envelope message are
+// present.
+type protobufEnvelopeOpts struct {
+	beforeField, afterField, updatedField, mvccTimestampField, resolvedField bool
+}
+
+// The field numbers of the envelope messages.
+const (
+	protobufFieldAfter         protowire.Number = 1
+	protobufFieldBefore        protowire.Number = 2
+	protobufFieldUpdated       protowire.Number = 3
+	protobufFieldMVCCTimestamp protowire.Number = 4
+	protobufFieldResolved      protowire.Number = 5
+)
+
+// protobufEnvelopeMessage is the message of the values of a changefeed, which
+// wraps the messages of the columns of the rows.
+type protobufEnvelopeMessage struct {
+	protobufMessage
+	opts          protobufEnvelopeOpts
+	before, after *protobufDataMessage
+
+	scratch []byte
+}
+
+// protobufSchema is a .proto file with the messages used to encode a key or a
+// value. The first message is the one which is encoded.
+type protobufSchema struct {
+	messages []*protobufMessage
+}
+
+// String returns the text of the .proto file, which is registered with the
+// schema registry.
+func (s *protobufSchema) String() string {
+	var buf strings.Builder
+	buf.WriteString("syntax = \"proto3\";\n")
+	for _, m := range s.messages {
+		fmt.Fprintf(&buf, "\nmessage %s {\n", m.name)
+		for _, f := range m.fields {
+			buf.WriteString("  ")
+			if f.optional {
+				buf.WriteString("optional ")
+			}
+			fmt.Fprintf(&buf, "%s %s = %d;", f.typeName, f.name, f.number)
+			if f.comment != `` {
+				fmt.Fprintf(&buf, " // %s", f.comment)
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.String()
+}
+
+// protobufTypeName returns the protobuf scalar type of the fields of columns
+// of the given type.
+func protobufTypeName(typ *types.T) string {
+	switch typ.Family() {
+	case types.IntFamily:
+		if typ.Width() == 64 {
+			return `int64`
+		}
+		return `int32`
+	case types.FloatFamily:
+		if typ.Width() == 32 {
+			return `float`
+		}
+		return `double`
+	case types.BoolFamily:
+		return `bool`
+	case types.BytesFamily:
+		return `bytes`
+	default:
+		return `string`
+	}
+}
+
+func columnToProtobufField(col catalog.Column) (protobufField, error) {
+	number := protowire.Number(col.GetID())
+	if !number.IsValid() {
+		return protobufField{}, errors.Errorf(
+			`column %s has ID %d, which is not a valid protobuf field number`, col.GetName(), col.GetID())
+	}
+	return protobufField{
+		name:     SQLNameToAvroName(col.GetName()),
+		number:   number,
+		typeName: protobufTypeName(col.GetType()),
+		optional: true,
+		comment:  col.GetType().SQLString(),
+	}, nil
+}
+
+func (m *protobufDataMessage) addColumn(col catalog.Column) error {
+	field, err := columnToProtobufField(col)
+	if err != nil {
+		return err
+	}
+	m.fields = append(m.fields, field)
+	m.colIdxByFieldIdx = append(m.colIdxByFieldIdx, col.Ordinal())
+	m.colTypes = append(m.colTypes, col.GetType())
+	return nil
+}
+
+// refreshTypeMetadata refreshes the metadata for user-defined types on a
+// cached message. The only user-defined type is enum, so this is usually a
+// no-op.
+func (m *protobufDataMessage) refreshTypeMetadata(tbl catalog.TableDescriptor) {
+	for _, col := range tbl.UserDefinedTypeColumns() {
+		for i, colIdx := range m.colIdxByFieldIdx {
+			if colIdx == col.Ordinal() {
+				m.colTypes[i] = col.GetType()
+			}
+		}
+	}
+}
+
+// indexToProtobufMessage returns the message of the key columns of an index.
+func indexToProtobufMessage(
+	tableDesc catalog.TableDescriptor, index catalog.Index, name string,
+) (*protobufDataMessage, error) {
+	m := &protobufDataMessage{protobufMessage: protobufMessage{name: name}}
+	for i := 0; i < index.NumKeyColumns(); i++ {
+		col, err := tableDesc.FindColumnWithID(index.GetKeyColumnID(i))
+		if err != nil {
+			return nil, err
+		}
+		if err := m.addColumn(col); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// tableToProtobufMessage returns the message of the columns of a column family
+// of a table.
+func tableToProtobufMessage(
+	tableDesc catalog.TableDescriptor,
+	familyID descpb.FamilyID,
+	name string,
+	virtualColumnVisibility string,
+) (*protobufDataMessage, error) {
+	family, err := tableDesc.FindFamilyByID(familyID)
+	if err != nil {
+		return nil, err
+	}
+	include := catalog.MakeTableColSet(family.ColumnIDs...)
+	m := &protobufDataMessage{protobufMessage: protobufMessage{name: name}}
+	for _, col := range tableDesc.PublicColumns() {
+		virtual := col.IsVirtual() && virtualColumnVisibility == string(changefeedbase.OptVirtualColumnsNull)
+		if !include.Contains(col.GetID()) && !virtual {
+			continue
+		}
+		if err := m.addColumn(col); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// envelopeToProtobufMessage returns the envelope message of the values with
+// the given data messages.
+func envelopeToProtobufMessage(
+	name string, opts protobufEnvelopeOpts, before, after *protobufDataMessage,
+) *protobufEnvelopeMessage {
+	m := &protobufEnvelopeMessage{
+		protobufMessage: protobufMessage{name: name},
+		opts:            opts,
+		before:          before,
+		after:           after,
+	}
+	if opts.afterField {
+		m.fields = append(m.fields, protobufField{
+			name: `after`, number: protobufFieldAfter, typeName: after.name,
+		})
+	}
+	if opts.beforeField {
+		m.fields = append(m.fields, protobufField{
+			name: `before`, number: protobufFieldBefore, typeName: before.name,
+		})
+	}
+	if opts.updatedField {
+		m.fields = append(m.fields, protobufField{
+			name: `updated`, number: protobufFieldUpdated, typeName: `string`,
+		})
+	}
+	if opts.mvccTimestampField {
+		m.fields = append(m.fields, protobufField{
+			name: `mvcc_timestamp`, number: protobufFieldMVCCTimestamp, typeName: `string`,
+		})
+	}
+	if opts.resolvedField {
+		m.fields = append(m.fields, protobufField{
+			name: `resolved`, number: protobufFieldResolved, typeName: `string`,
+		})
+	}
+	return m
+}
+
+// schema returns the schema of the keys encoded with the message.
+func (m *protobufDataMessage) schema() *protobufSchema {
+	return &protobufSchema{messages: []*protobufMessage{&m.protobufMessage}}
+}
+
+// schema returns the schema of the values encoded with the message.
+func (m *protobufEnvelopeMessage) schema() *protobufSchema {
+	s := &protobufSchema{messages: []*protobufMessage{&m.protobufMessage}}
+	if m.opts.afterField {
+		s.messages = append(s.messages, &m.after.protobufMessage)
+	}
+	if m.opts.beforeField {
+		s.messages = append(s.messages, &m.before.protobufMessage)
+	}
+	return s
+}
+
+// BinaryFromRow encodes the given row as a message, appending it to buf.
+func (m *protobufDataMessage) BinaryFromRow(buf []byte, row rowenc.EncDatumRow) ([]byte, error) {
+	for i, field := range m.fields {
+		encDatum := row[m.colIdxByFieldIdx[i]]
+		// The columns which are not set, such as the non primary key columns
+		// of deleted rows, are left unset like NULLs.
+		if encDatum.IsUnset() {
+			continue
+		}
+		if err := encDatum.EnsureDecoded(m.colTypes[i], &m.alloc); err != nil {
+			return nil, err
+		}
+		if encDatum.Datum == tree.DNull {
+			continue
+		}
+		var err error
+		buf, err = appendProtobufDatum(buf, field.number, m.colTypes[i], encDatum.Datum)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+// appendProtobufDatum appends the field with the given number and datum to buf,
+// as a value of the type returned by protobufTypeName for the column's type.
+func appendProtobufDatum(
+	buf []byte, num protowire.Number, typ *types.T, d tree.Datum,
+) ([]byte, error) {
+	switch t := d.(type) {
+	case *tree.DInt:
+		buf = protowire.AppendTag(buf, num, protowire.VarintType)
+		return protowire.AppendVarint(buf, uint64(*t)), nil
+	case *tree.DFloat:
+		if typ.Width() == 32 {
+			buf = protowire.AppendTag(buf, num, protowire.Fixed32Type)
+			return protowire.AppendFixed32(buf, math.Float32bits(float32(*t))), nil
+		}
+		buf = protowire.AppendTag(buf, num, protowire.Fixed64Type)
+		return protowire.AppendFixed64(buf, math.Float64bits(float64(*t))), nil
+	case *tree.DBool:
+		buf = protowire.AppendTag(buf, num, protowire.VarintType)
+		return protowire.AppendVarint(buf, protowire.EncodeBool(bool(*t))), nil
+	case *tree.DBytes:
+		buf = protowire.AppendTag(buf, num, protowire.BytesType)
+		return protowire.AppendString(buf, string(*t)), nil
+	case *tree.DString:
+		buf = protowire.AppendTag(buf, num, protowire.BytesType)
+		return protowire.AppendString(buf, string(*t)), nil
+	case *tree.DCollatedString:
+		buf = protowire.AppendTag(buf, num, protowire.BytesType)
+		return protowire.AppendString(buf, t.Contents), nil
+	default:
+		buf = protowire.AppendTag(buf, num, protowire.BytesType)
+		return protowire.AppendString(buf, tree.AsStringWithFlags(d, tree.FmtExport)), nil
+	}
+}
+
+// BinaryFromRow encodes the given rows and metadata in the envelope message,
+// appending it to buf. The before and after rows are left unset if nil.
+func (m *protobufEnvelopeMessage) BinaryFromRow(
+	buf []byte, meta protobufMetadata, beforeRow, afterRow rowenc.EncDatumRow,
+) ([]byte, error) {
+	appendRow := func(num protowire.Number, data *protobufDataMessage, row rowenc.EncDatumRow) error {
+		var err error
+		m.scratch, err = data.BinaryFromRow(m.scratch[:0], row)
+		if err != nil {
+			return err
+		}
+		buf = protowire.AppendTag(buf, num, protowire.BytesType)
+		buf = protowire.AppendBytes(buf, m.scratch)
+		return nil
+	}
+	appendTimestamp := func(num protowire.Number, ts hlc.Timestamp) {
+		buf = protowire.AppendTag(buf, num, protowire.BytesType)
+		buf = protowire.AppendString(buf, ts.AsOfSystemTime())
+	}
+
+	if m.opts.afterField && afterRow != nil {
+		if err := appendRow(protobufFieldAfter, m.after, afterRow); err != nil {
+			return nil, err
+		}
+	}
+	if m.opts.beforeField && beforeRow != nil {
+		if err := appendRow(protobufFieldBefore, m.before, beforeRow); err != nil {
+			return nil, err
+		}
+	}
+	if m.opts.updatedField {
+		appendTimestamp(protobufFieldUpdated, meta.updated)
+	}
+	if m.opts.mvccTimestampField {
+		appendTimestamp(protobufFieldMVCCTimestamp, meta.mvccTimestamp)
+	}
+	if m.opts.resolvedField {
+		appendTimestamp(protobufFieldResolved, meta.resolved)
+	}
+	return buf, nil
+}
+
+// protobufMetadata has the values of the metadata fields of an envelope
+// message.
+type protobufMetadata struct {
+	updated, mvccTimestamp, resolved hlc.Timestamp
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/util/contextutil"
@@ -90,13 +91,20 @@ func New(
 		db:                cfg.DB,
 		clock:             cfg.DB.Clock(),
 		settings:          cfg.Settings,
-		targets:           targets,
 		leaseMgr:          cfg.LeaseManager.(*lease.Manager),
 		ie:                cfg.SessionBoundInternalExecutorFactory(ctx, &sessiondata.SessionData{}),
 		collectionFactory: cfg.CollectionFactory,
 		metrics:           metrics,
 		changefeedOpts:    changefeedOpts,
 	}
+	for _, t := range targets {
+		if t.Type == jobspb.ChangefeedTargetSpecification_DATABASE {
+			m.databaseID = t.DatabaseID
+			continue
+		}
+		m.targets = append(m.targets, t)
+	}
+	_, m.initialScanNewTables = changefeedOpts[changefeedbase.OptInitialScanNewTables]
 	m.mu.previousTableVersion = make(map[descpb.ID]catalog.TableDescriptor)
 	m.mu.highWater = initialHighwater
 	m.mu.typeDeps = typeDependencyTracker{deps: make(map[descpb.ID][]descpb.ID)}
@@ -123,6 +131,14 @@ type schemaFeed struct {
 	metrics        *Metrics
 	changefeedOpts map[string]string
 
+	// databaseID is set for changefeeds on a whole database. The tables of the
+	// database which become public or are dropped change the set of targets and
+	// are turned into events rather than failing validation.
+	databaseID descpb.ID
+	// initialScanNewTables indicates that tables which became targets after the
+	// feed started should be scanned as of the time they became public.
+	initialScanNewTables bool
+
 	// TODO(ajwerner): Should this live underneath the FilterFunc?
 	// Should there be another function to decide whether to update the
 	// lease manager?
@@ -293,6 +309,12 @@ func (tf *schemaFeed) primeInitialTableDescs(ctx context.Context) error {
 			flags.AvoidLeased = true
 			tableDesc, err := descriptors.GetImmutableTableByID(ctx, txn, table.TableID, flags)
 			if err != nil {
+				// Changefeeds on a database restart right before the tables
+				// they start watching become public, at which point those don't
+				// exist yet.
+				if tf.databaseID != descpb.InvalidID && sqlerrors.IsUndefinedRelationError(err) {
+					continue
+				}
 				return err
 			}
 			initialDescs = append(initialDescs, tableDesc)
@@ -518,9 +540,95 @@ func formatDesc(desc catalog.TableDescriptor) string {
 }
 
 func formatEvent(e TableEvent) string {
+	if e.Before == nil {
+		return fmt.Sprintf("nil->%v", formatDesc(e.After))
+	}
 	return fmt.Sprintf("%v->%v", formatDesc(e.Before), formatDesc(e.After))
 }
 
+// inWatchedDatabase returns true if the table belongs to the database watched
+// by a changefeed on a whole database.
+func (tf *schemaFeed) inWatchedDatabase(desc catalog.Descriptor) bool {
+	return tf.databaseID != descpb.InvalidID && desc.GetParentID() == tf.databaseID
+}
+
+func (tf *schemaFeed) isTableTarget(id descpb.ID) bool {
+	for _, t := range tf.targets {
+		if t.TableID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// addEventLocked adds an event to the queue of events. Requires that tf.mu is
+// held.
+func (tf *schemaFeed) addEventLocked(earliestTsBeingIngested hlc.Timestamp, e TableEvent) {
+	// Only sort the tail of the events from earliestTsBeingIngested.
+	// The head could already have been handed out and sorting is not
+	// stable.
+	idxToSort := sort.Search(len(tf.mu.events), func(i int) bool {
+		return !tf.mu.events[i].After.GetModificationTime().Less(earliestTsBeingIngested)
+	})
+	tf.mu.events = append(tf.mu.events, e)
+	toSort := tf.mu.events[idxToSort:]
+	sort.Slice(toSort, func(i, j int) bool {
+		return descLess(toSort[i].After, toSort[j].After)
+	})
+}
+
+// validateDatabaseTableLocked handles the versions of the tables in the
+// database watched by a changefeed on a whole database which change the set of
+// tables it watches:
+//
+//   - a table which is not a target yet becomes public: the changefeed needs to
+//     restart to start watching it, which is signaled with an event without a
+//     Before descriptor.
+//   - a target which did not exist when the feed started becomes public: this
+//     is the restarted feed seeing the table for the first time, which also
+//     gets an event without a Before descriptor if the table should be scanned.
+//   - a target is dropped: the changefeed needs to restart to stop watching it.
+//
+// It returns true if desc needs no further validation. Requires that tf.mu is
+// held.
+func (tf *schemaFeed) validateDatabaseTableLocked(
+	ctx context.Context, earliestTsBeingIngested hlc.Timestamp, desc catalog.TableDescriptor,
+) (handled bool, _ error) {
+	lastVersion, seen := tf.mu.previousTableVersion[desc.GetID()]
+	if seen && desc.GetModificationTime().LessEq(lastVersion.GetModificationTime()) {
+		return true, nil
+	}
+	isTarget := tf.isTableTarget(desc.GetID())
+	switch {
+	case !isTarget:
+		if seen || !changefeedbase.IsDatabaseTargetTable(desc) {
+			return true, nil
+		}
+		log.VEventf(ctx, 1, "new table in watched database %v", formatDesc(desc))
+		tf.addEventLocked(earliestTsBeingIngested, TableEvent{After: desc})
+	case !seen:
+		if !changefeedbase.IsDatabaseTargetTable(desc) {
+			return false, nil
+		}
+		if tf.initialScanNewTables {
+			tf.addEventLocked(earliestTsBeingIngested, TableEvent{After: desc})
+		}
+		if err := tf.mu.typeDeps.ingestTable(desc); err != nil {
+			return true, err
+		}
+	case desc.Dropped():
+		log.VEventf(ctx, 1, "watched table dropped %v", formatDesc(desc))
+		if err := tf.mu.typeDeps.purgeTable(lastVersion); err != nil {
+			return true, err
+		}
+		tf.addEventLocked(earliestTsBeingIngested, TableEvent{Before: lastVersion, After: desc})
+	default:
+		return false, nil
+	}
+	tf.mu.previousTableVersion[desc.GetID()] = desc
+	return true, nil
+}
+
 func (tf *schemaFeed) validateDescriptor(
 	ctx context.Context, earliestTsBeingIngested hlc.Timestamp, desc catalog.Descriptor,
 ) error {
@@ -535,6 +643,13 @@ func (tf *schemaFeed) validateDescriptor(
 		// manager to acquire the freshest version of the type.
 		return tf.leaseMgr.AcquireFreshestFromStore(ctx, desc.GetID())
 	case catalog.TableDescriptor:
+		if tf.inWatchedDatabase(desc) {
+			if handled, err := tf.validateDatabaseTableLocked(
+				ctx, earliestTsBeingIngested, desc,
+			); handled || err != nil {
+				return err
+			}
+		}
 		if err := changefeedbase.ValidateTable(tf.targets, desc, tf.changefeedOpts); err != nil {
 			return err
 		}
@@ -571,17 +686,7 @@ func (tf *schemaFeed) validateDescriptor(
 				return err
 			}
 			if !shouldFilter {
-				// Only sort the tail of the events from earliestTsBeingIngested.
-				// The head could already have been handed out and sorting is not
-				// stable.
-				idxToSort := sort.Search(len(tf.mu.events), func(i int) bool {
-					return !tf.mu.events[i].After.GetModificationTime().Less(earliestTsBeingIngested)
-				})
-				tf.mu.events = append(tf.mu.events, e)
-				toSort := tf.mu.events[idxToSort:]
-				sort.Slice(toSort, func(i, j int) bool {
-					return descLess(toSort[i].After, toSort[j].After)
-				})
+				tf.addEventLocked(earliestTsBeingIngested, e)
 			}
 		}
 		// Add the types used by the table into the dependency tracker.
@@ -705,14 +810,22 @@ func (tf *schemaFeed) fetchDescriptorVersions(
 					}
 				}
 				isType := tf.mu.typeDeps.containsType(descpb.ID(id))
-				// Check if the descriptor is an interesting table or type.
-				if !(isTable || isType) {
+				// Check if the descriptor is an interesting table or type. Any
+				// table could be in the watched database, which is only known once
+				// the descriptor is decoded.
+				if !(isTable || isType || tf.databaseID != descpb.InvalidID) {
 					// Uninteresting descriptor.
 					continue
 				}
 
 				unsafeValue := it.UnsafeValue()
 				if unsafeValue == nil {
+					// Tables in a watched database go through the dropped state
+					// before their descriptor is deleted, which is what stops the
+					// changefeed from watching them.
+					if tf.databaseID != descpb.InvalidID && !isType {
+						continue
+					}
 					name := origName
 					if name == "" {
 						name = fmt.Sprintf("desc(%d)", id)
@@ -729,7 +842,9 @@ func (tf *schemaFeed) fetchDescriptorVersions(
 
 				b := descbuilder.NewBuilderWithMVCCTimestamp(&desc, k.Timestamp)
 				if b != nil && (b.DescriptorType() == catalog.Table || b.DescriptorType() == catalog.Type) {
-					descriptors = append(descriptors, b.BuildImmutable())
+					if d := b.BuildImmutable(); isTable || isType || tf.inWatchedDatabase(d) {
+						descriptors = append(descriptors, d)
+					}
 				}
 			}
 		}(); err != nil {
@@ -27,6 +27,8 @@ const (
 	tableEventPrimaryKeyChange
 	tableEventLocalityRegionalByRowChange
 	tableEventAddHiddenColumn
+	tableEventTableAdded
+	tableEventTableDropped
 )
 
 var (
@@ -38,6 +40,8 @@ var (
 		tableEventPrimaryKeyChange:            false,
 		tableEventLocalityRegionalByRowChange: false,
 		tableEventAddHiddenColumn:             true,
+		tableEventTableAdded:                  false,
+		tableEventTableDropped:                false,
 	}
 
 	columnChangeTableEventFilter = tableEventFilter{
@@ -48,6 +52,8 @@ var (
 		tableEventPrimaryKeyChange:            false,
 		tableEventLocalityRegionalByRowChange: false,
 		tableEventAddHiddenColumn:             true,
+		tableEventTableAdded:                  false,
+		tableEventTableDropped:                false,
 	}
 
 	schemaChangeEventFilters = map[changefeedbase.SchemaChangeEventClass]tableEventFilter{
@@ -69,6 +75,15 @@ func (e tableEventType) Clear(event tableEventType) tableEventType {
 }
 
 func classifyTableEvent(e TableEvent) tableEventType {
+	// Tables entering or leaving the set of tables watched by a changefeed on a
+	// whole database don't compare two versions of a target.
+	if e.Before == nil {
+		return tableEventTableAdded
+	}
+	if e.After.Dropped() {
+		return tableEventTableDropped
+	}
+
 	et := tableEventTypeUnknown
 	if primaryKeyChanged(e) {
 		et = et | tableEventPrimaryKeyChange
@@ -215,3 +230,15 @@ func IsRegionalByRowChange(e TableEvent) bool {
 	et := classifyTableEvent(e)
 	return et.Contains(tableEventLocalityRegionalByRowChange)
 }
+
+// IsTableAdded returns true if the event corresponds to a table which
+// became public in the database watched by a changefeed on a whole database.
+func IsTableAdded(e TableEvent) bool {
+	return classifyTableEvent(e) == tableEventTableAdded
+}
+
+// IsTableDropped returns true if the event corresponds to a table watched by a
+// changefeed on a whole database being dropped.
+func IsTableDropped(e TableEvent) bool {
+	return classifyTableEvent(e) == tableEventTableDropped
+}
@@ -61,6 +61,65 @@ type SinkWithTopics interface {
 	Topics() []string
 }
 
+// SinkWithEncoder extends the Sink interface for sinks which encode the rows
+// themselves rather than emitting the messages produced by an Encoder. This is
+// the case of columnar formats such as parquet, which need the values of the
+// columns of the rows to lay them out in a file, and of formats whose files
+// start with a header describing the rows, such as CSV.
+type SinkWithEncoder interface {
+	Sink
+	// EncodeAndEmitRow is used instead of EmitRow to enqueue a row.
+	EncodeAndEmitRow(
+		ctx context.Context,
+		row encodeRow,
+		topic TopicDescriptor,
+		updated, mvcc hlc.Timestamp,
+		alloc kvevent.Alloc,
+	) error
+}
+
+// SinkWithCheckpoint extends the Sink interface for sinks which only make the
+// messages they delivered visible once they are committed, such as Kafka sinks
+// with a transactional producer.
+type SinkWithCheckpoint interface {
+	Sink
+	// FlushForCheckpoint is used instead of Flush before the progress covering
+	// the messages enqueued so far is forwarded to be checkpointed. It flushes
+	// the sink and then commits the messages.
+	FlushForCheckpoint(ctx context.Context) error
+}
+
+// flushSinkForCheckpoint flushes the sink before the progress covering the
+// messages enqueued so far is checkpointed, see SinkWithCheckpoint.
+func flushSinkForCheckpoint(ctx context.Context, s Sink) error {
+	if sink, ok := s.(SinkWithCheckpoint); ok {
+		return sink.FlushForCheckpoint(ctx)
+	}
+	return s.Flush(ctx)
+}
+
+// sinkEncodesRows returns whether the rows of the changefeed are encoded by its
+// sink, see SinkWithEncoder.
+func sinkEncodesRows(details jobspb.ChangefeedDetails) (bool, error) {
+	switch changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) {
+	case changefeedbase.OptFormatParquet:
+		return true, nil
+	case changefeedbase.OptFormatCSV:
+		// Cloud storage sinks start each file with a header, other sinks emit
+		// the records produced by the encoder.
+		u, err := url.Parse(details.SinkURI)
+		if err != nil {
+			return false, err
+		}
+		return isCloudStorageSink(u), nil
+	default:
+		return false, nil
+	}
+}
+
+// getSink returns the sink a changefeed emits to. The partition identifies the
+// part of the changefeed which emits to the sink, and must be stable across
+// restarts of the changefeed.
 func getSink(
 	ctx context.Context,
 	serverCfg *execinfra.ServerConfig,
@@ -68,6 +127,7 @@ func getSink(
 	timestampOracle timestampLowerBoundOracle,
 	user security.SQLUsername,
 	jobID jobspb.JobID,
+	partition string,
 	m *sliMetrics,
 ) (Sink, error) {
 	u, err := url.Parse(feedCfg.SinkURI)
@@ -97,7 +157,7 @@ func getSink(
 			return makeNullSink(sinkURL{URL: u}, m)
 		case u.Scheme == changefeedbase.SinkSchemeKafka:
 			return validateOptionsAndMakeSink(changefeedbase.KafkaValidOptions, func() (Sink, error) {
-				return makeKafkaSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), feedCfg.Opts, m)
+				return makeKafkaSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), feedCfg.Opts, jobID, partition, m)
 			})
 		case isWebhookSink(u):
 			return validateOptionsAndMakeSink(changefeedbase.WebhookValidOptions, func() (Sink, error) {
@@ -109,6 +169,18 @@ func getSink(
 			return validateOptionsAndMakeSink(changefeedbase.PubsubValidOptions, func() (Sink, error) {
 				return MakePubsubSink(ctx, u, feedCfg.Opts, AllTargets(feedCfg))
 			})
+		case isPulsarSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.PulsarValidOptions, func() (Sink, error) {
+				return makePulsarSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), feedCfg.Opts, m)
+			})
+		case u.Scheme == changefeedbase.SinkSchemeRedis:
+			return validateOptionsAndMakeSink(changefeedbase.RedisValidOptions, func() (Sink, error) {
+				return makeRedisSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), feedCfg.Opts, m)
+			})
+		case u.Scheme == changefeedbase.SinkSchemeNATS:
+			return validateOptionsAndMakeSink(changefeedbase.NATSValidOptions, func() (Sink, error) {
+				return makeNATSSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), feedCfg.Opts, m)
+			})
 		case isCloudStorageSink(u):
 			return validateOptionsAndMakeSink(changefeedbase.CloudStorageValidOptions, func() (Sink, error) {
 				return makeCloudStorageSink(
@@ -244,6 +316,24 @@ func (s errorWrapperSink) EmitRow(
 	return nil
 }
 
+// EncodeAndEmitRow implements SinkWithEncoder interface.
+func (s errorWrapperSink) EncodeAndEmitRow(
+	ctx context.Context,
+	row encodeRow,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	sink, ok := s.wrapped.(SinkWithEncoder)
+	if !ok {
+		return errors.AssertionFailedf("sink %T cannot encode rows", s.wrapped)
+	}
+	if err := sink.EncodeAndEmitRow(ctx, row, topic, updated, mvcc, alloc); err != nil {
+		return changefeedbase.MarkRetryableError(err)
+	}
+	return nil
+}
+
 // EmitResolvedTimestamp implements Sink interface.
 func (s errorWrapperSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
@@ -262,6 +352,14 @@ func (s errorWrapperSink) Flush(ctx context.Context) error {
 	return nil
 }
 
+// FlushForCheckpoint implements SinkWithCheckpoint interface.
+func (s errorWrapperSink) FlushForCheckpoint(ctx context.Context) error {
+	if err := flushSinkForCheckpoint(ctx, s.wrapped); err != nil {
+		return changefeedbase.MarkRetryableError(err)
+	}
+	return nil
+}
+
 // Close implements Sink interface.
 func (s errorWrapperSink) Close() error {
 	if err := s.wrapped.Close(); err != nil {
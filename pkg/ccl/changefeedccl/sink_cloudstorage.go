@@ -69,6 +69,9 @@ type cloudStorageSinkFile struct {
 	buf         bytes.Buffer
 	alloc       kvevent.Alloc
 	oldestMVCC  hlc.Timestamp
+	// parquet is set for files in the parquet format, which are written by
+	// the parquetWriter rather than with Write.
+	parquet *parquetWriter
 }
 
 var _ io.Writer = &cloudStorageSinkFile{}
@@ -175,9 +178,10 @@ func (f *cloudStorageSinkFile) Write(p []byte) (int, error) {
 // by a given `<sink_id>` and <session_id> is a unique identifying string for the job
 // session running the `changeAggregator` that owns this sink.
 //
-// `<ext>` implies the format of the file: currently the only option is
-// `ndjson`, which means a text file conforming to the "Newline Delimited JSON"
-// spec.
+// `<ext>` implies the format of the file: either `ndjson`, which means a text
+// file conforming to the "Newline Delimited JSON" spec, `parquet` for
+// format=parquet or `csv` for format=csv. CSV files start with a header naming
+// their columns.
 //
 // This naming convention of data files is carefully chosen in order to preserve
 // the external ordering guarantees of CDC. Naming output files in this fashion
@@ -288,6 +292,10 @@ type cloudStorageSink struct {
 
 	ext          string
 	rowDelimiter []byte
+	format       changefeedbase.FormatType
+	opts         map[string]string
+	// csv encodes the rows and the headers of files with format=csv.
+	csv *csvEncoder
 
 	compression string
 
@@ -386,19 +394,32 @@ func makeCloudStorageSink(
 		s.dataFilePartition = s.timestampOracle.inclusiveLowerBoundTS().GoTime().Format(s.partitionFormat)
 	}
 
-	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	s.format = changefeedbase.FormatType(opts[changefeedbase.OptFormat])
+	s.opts = opts
+	switch s.format {
 	case changefeedbase.OptFormatJSON:
 		// TODO(dan): It seems like these should be on the encoder, but that
 		// would require a bit of refactoring.
 		s.ext = `.ndjson`
 		s.rowDelimiter = []byte{'\n'}
+	case changefeedbase.OptFormatParquet:
+		// The rows are encoded by the sink, see EncodeAndEmitRow.
+		s.ext = `.parquet`
+	case changefeedbase.OptFormatCSV:
+		// The rows are encoded by the sink, see EncodeAndEmitRow.
+		s.ext = `.csv`
+		s.rowDelimiter = []byte{'\n'}
+		var err error
+		if s.csv, err = makeCSVEncoder(opts); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
 	}
 
 	switch changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) {
-	case changefeedbase.OptEnvelopeWrapped:
+	case changefeedbase.OptEnvelopeWrapped, changefeedbase.OptEnvelopeDebezium:
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope])
@@ -411,7 +432,10 @@ func makeCloudStorageSink(
 	if codec, ok := opts[changefeedbase.OptCompression]; ok && codec != "" {
 		if strings.EqualFold(codec, "gzip") {
 			s.compression = sinkCompressionGzip
-			s.ext = s.ext + ".gz"
+			// Parquet files are compressed internally, page by page.
+			if s.format != changefeedbase.OptFormatParquet {
+				s.ext = s.ext + ".gz"
+			}
 		} else {
 			return nil, errors.Errorf(`unsupported compression codec %q`, codec)
 		}
@@ -441,8 +465,7 @@ func (s *cloudStorageSink) getOrCreateFile(
 		cloudStorageSinkKey: key,
 		oldestMVCC:          eventMVCC,
 	}
-	switch s.compression {
-	case sinkCompressionGzip:
+	if s.compression == sinkCompressionGzip && s.format != changefeedbase.OptFormatParquet {
 		f.codec = gzip.NewWriter(&f.buf)
 	}
 	s.files.ReplaceOrInsert(f)
@@ -460,6 +483,10 @@ func (s *cloudStorageSink) EmitRow(
 	if s.files == nil {
 		return errors.New(`cannot EmitRow on a closed sink`)
 	}
+	if s.format == changefeedbase.OptFormatParquet || s.format == changefeedbase.OptFormatCSV {
+		return errors.AssertionFailedf(`rows must be encoded by the sink with %s=%s`,
+			changefeedbase.OptFormat, s.format)
+	}
 
 	s.metrics.recordMessageSize(int64(len(key) + len(value)))
 	file := s.getOrCreateFile(topic, mvcc)
@@ -480,6 +507,84 @@ func (s *cloudStorageSink) EmitRow(
 	return nil
 }
 
+// EncodeAndEmitRow implements the SinkWithEncoder interface. It is used
+// instead of EmitRow with format=parquet and format=csv. With parquet, the
+// rows of each file are buffered into row groups by a parquetWriter, whose
+// schema is derived from the table descriptor. With CSV, the first row of each
+// file is preceded by a header naming the columns of the rows. Like other
+// files, the file is written out once it exceeds the target file size, or when
+// the sink is flushed.
+func (s *cloudStorageSink) EncodeAndEmitRow(
+	ctx context.Context,
+	row encodeRow,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if s.files == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+
+	file := s.getOrCreateFile(topic, mvcc)
+	file.alloc.Merge(&alloc)
+
+	var size int64
+	switch s.format {
+	case changefeedbase.OptFormatParquet:
+		if file.parquet == nil {
+			var err error
+			if file.parquet, err = newParquetWriter(&file.buf, row, s.opts, s.compression); err != nil {
+				return err
+			}
+		}
+		prevSize := file.parquet.size()
+		if err := file.parquet.addRow(row, updated, mvcc); err != nil {
+			return err
+		}
+		size = file.parquet.size()
+		s.metrics.recordMessageSize(size - prevSize)
+		file.numMessages++
+		file.rawSize = int(size)
+	case changefeedbase.OptFormatCSV:
+		if file.rawSize == 0 {
+			header, err := s.csv.EncodeHeader(row)
+			if err != nil {
+				return err
+			}
+			if err := s.writeCSVRecord(file, header); err != nil {
+				return err
+			}
+		}
+		value, err := s.csv.EncodeValue(ctx, row)
+		if err != nil {
+			return err
+		}
+		s.metrics.recordMessageSize(int64(len(value)))
+		if err := s.writeCSVRecord(file, value); err != nil {
+			return err
+		}
+		size = int64(file.buf.Len())
+	default:
+		return errors.AssertionFailedf(`rows cannot be encoded by the sink with %s=%s`,
+			changefeedbase.OptFormat, s.format)
+	}
+
+	if size > s.targetMaxFileSize {
+		if err := s.flushTopicVersions(ctx, file.topic, file.schemaID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *cloudStorageSink) writeCSVRecord(file *cloudStorageSinkFile, record []byte) error {
+	if _, err := file.Write(record); err != nil {
+		return err
+	}
+	_, err := file.Write(s.rowDelimiter)
+	return err
+}
+
 // EmitResolvedTimestamp implements the Sink interface.
 func (s *cloudStorageSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
@@ -577,6 +682,11 @@ func (s *cloudStorageSink) flushFile(ctx context.Context, file *cloudStorageSink
 		return nil
 	}
 
+	if file.parquet != nil {
+		if err := file.parquet.close(); err != nil {
+			return err
+		}
+	}
 	if file.codec != nil {
 		if err := file.codec.Close(); err != nil {
 			return err
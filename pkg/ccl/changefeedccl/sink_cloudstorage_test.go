@@ -13,6 +13,7 @@ import (
 	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math"
 	"net/url"
@@ -33,13 +34,21 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/span"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	goparquet "github.com/fraugster/parquet-go"
 	"github.com/stretchr/testify/require"
 )
 
@@ -152,7 +161,7 @@ func TestCloudStorageSink(t *testing.T) {
 		changefeedbase.OptCompression: ``, // NB: overridden in single-node subtest.
 	}
 	ts := func(i int64) hlc.Timestamp { return hlc.Timestamp{WallTime: i} }
-	e, err := makeJSONEncoder(opts, []jobspb.ChangefeedTargetSpecification{})
+	e, err := makeJSONEncoder(opts, []jobspb.ChangefeedTargetSpecification{}, uuid.UUID{})
 	require.NoError(t, err)
 
 	clientFactory := blobs.TestBlobServiceClient(settings.ExternalIODir)
@@ -209,6 +218,158 @@ func TestCloudStorageSink(t *testing.T) {
 		return forwarded
 	}
 
+	t.Run(`parquet`, func(t *testing.T) {
+		desc, err := sql.CreateTestTableDescriptor(ctx, 50 /* parentID */, 104,
+			`CREATE TABLE t1 (a INT PRIMARY KEY, b STRING)`,
+			catpb.NewBasePrivilegeDescriptor(security.RootUserName()))
+		require.NoError(t, err)
+		tableDesc := desc.ImmutableCopy().(catalog.TableDescriptor)
+		t1 := &tableDescriptorTopic{
+			tableDesc: tableDesc,
+			spec: jobspb.ChangefeedTargetSpecification{
+				Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+				TableID:           tableDesc.GetID(),
+				StatementTimeName: `t1`,
+			},
+		}
+
+		parquetOpts := map[string]string{
+			changefeedbase.OptFormat:            string(changefeedbase.OptFormatParquet),
+			changefeedbase.OptEnvelope:          string(changefeedbase.OptEnvelopeWrapped),
+			changefeedbase.OptKeyInValue:        ``,
+			changefeedbase.OptUpdatedTimestamps: ``,
+		}
+		testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+		sf, err := span.MakeFrontier(testSpan)
+		require.NoError(t, err)
+		timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+		sinkDir := `parquet`
+		s, err := makeCloudStorageSink(
+			ctx, sinkURI(sinkDir, unlimitedFileSize), 1, settings,
+			parquetOpts, timestampOracle, externalStorageFromURI, user, nil,
+		)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+		sink := s.(*cloudStorageSink)
+
+		insert := encodeRow{
+			datums: rowenc.EncDatumRow{
+				rowenc.DatumToEncDatum(types.Int, tree.NewDInt(1)),
+				rowenc.DatumToEncDatum(types.String, tree.NewDString(`one`)),
+			},
+			tableDesc: tableDesc,
+		}
+		// Only the primary key is set for deletions.
+		deletion := encodeRow{
+			datums: rowenc.EncDatumRow{
+				rowenc.DatumToEncDatum(types.Int, tree.NewDInt(2)),
+				{},
+			},
+			deleted:   true,
+			tableDesc: tableDesc,
+		}
+		require.Error(t, s.EmitRow(ctx, t1, noKey, []byte(`v1`), ts(1), ts(1), zeroAlloc))
+		require.NoError(t, sink.EncodeAndEmitRow(ctx, insert, t1, ts(1), ts(1), zeroAlloc))
+		require.NoError(t, sink.EncodeAndEmitRow(ctx, deletion, t1, ts(2), ts(2), zeroAlloc))
+		require.NoError(t, s.Flush(ctx))
+
+		files := slurpDir(t, sinkDir)
+		require.Len(t, files, 1)
+		fr, err := goparquet.NewFileReader(strings.NewReader(files[0]))
+		require.NoError(t, err)
+		var names []string
+		for _, col := range fr.GetSchemaDefinition().RootColumn.Children {
+			names = append(names, col.SchemaElement.Name)
+		}
+		require.Equal(t, []string{`a`, `b`, `__crdb__event_type`, `__crdb__updated`}, names)
+
+		row, err := fr.NextRow()
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			`a`:                  int64(1),
+			`b`:                  []byte(`one`),
+			`__crdb__event_type`: []byte(`upsert`),
+			`__crdb__updated`:    []byte(ts(1).AsOfSystemTime()),
+		}, row)
+		row, err = fr.NextRow()
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			`a`:                  int64(2),
+			`__crdb__event_type`: []byte(`delete`),
+			`__crdb__updated`:    []byte(ts(2).AsOfSystemTime()),
+		}, row)
+		_, err = fr.NextRow()
+		require.Equal(t, io.EOF, err)
+	})
+
+	t.Run(`csv`, func(t *testing.T) {
+		makeTable := func(schema string, version descpb.DescriptorVersion) catalog.TableDescriptor {
+			desc, err := sql.CreateTestTableDescriptor(ctx, 50 /* parentID */, 104, schema,
+				catpb.NewBasePrivilegeDescriptor(security.RootUserName()))
+			require.NoError(t, err)
+			desc.Version = version
+			return desc.ImmutableCopy().(catalog.TableDescriptor)
+		}
+		// The second version of the table has a new column, the columns of
+		// each file are in the order of the version of the table it is for.
+		v1 := makeTable(`CREATE TABLE t1 (a INT PRIMARY KEY, b STRING)`, 1)
+		v2 := makeTable(`CREATE TABLE t1 (a INT PRIMARY KEY, c INT, b STRING)`, 2)
+		topic := func(desc catalog.TableDescriptor) *tableDescriptorTopic {
+			return &tableDescriptorTopic{
+				tableDesc: desc,
+				spec: jobspb.ChangefeedTargetSpecification{
+					Type:              jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+					TableID:           desc.GetID(),
+					StatementTimeName: `t1`,
+				},
+			}
+		}
+
+		csvOpts := map[string]string{
+			changefeedbase.OptFormat:     string(changefeedbase.OptFormatCSV),
+			changefeedbase.OptEnvelope:   string(changefeedbase.OptEnvelopeWrapped),
+			changefeedbase.OptKeyInValue: ``,
+			changefeedbase.OptEndTime:    `1`,
+		}
+		testSpan := roachpb.Span{Key: []byte("a"), EndKey: []byte("b")}
+		sf, err := span.MakeFrontier(testSpan)
+		require.NoError(t, err)
+		timestampOracle := &changeAggregatorLowerBoundOracle{sf: sf}
+		sinkDir := `csv`
+		s, err := makeCloudStorageSink(
+			ctx, sinkURI(sinkDir, unlimitedFileSize), 1, settings,
+			csvOpts, timestampOracle, externalStorageFromURI, user, nil,
+		)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, s.Close()) }()
+		sink := s.(*cloudStorageSink)
+
+		row := func(desc catalog.TableDescriptor, deleted bool, datums ...tree.Datum) encodeRow {
+			r := encodeRow{deleted: deleted, tableDesc: desc}
+			for i, col := range desc.PublicColumns() {
+				if i < len(datums) {
+					r.datums = append(r.datums, rowenc.DatumToEncDatum(col.GetType(), datums[i]))
+				} else {
+					r.datums = append(r.datums, rowenc.EncDatum{})
+				}
+			}
+			return r
+		}
+		require.Error(t, s.EmitRow(ctx, topic(v1), noKey, []byte(`v1`), ts(1), ts(1), zeroAlloc))
+		for i, r := range []encodeRow{
+			row(v1, false, tree.NewDInt(1), tree.NewDString(`one`)),
+			row(v1, true, tree.NewDInt(1)),
+			row(v2, false, tree.NewDInt(2), tree.NewDInt(20), tree.NewDString(`two`)),
+		} {
+			require.NoError(t, sink.EncodeAndEmitRow(ctx, r, topic(r.tableDesc), ts(int64(i+1)), ts(int64(i+1)), zeroAlloc))
+		}
+		require.NoError(t, s.Flush(ctx))
+		require.Equal(t, []string{
+			"a,b,__crdb__event_type\n1,one,upsert\n1,,delete\n",
+			"a,c,b,__crdb__event_type\n2,20,two,upsert\n",
+		}, slurpDir(t, sinkDir))
+	})
+
 	t.Run(`single-node`, func(t *testing.T) {
 		before := opts[changefeedbase.OptCompression]
 		// Compression codecs include buffering that interferes with other tests,
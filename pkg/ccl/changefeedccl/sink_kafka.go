@@ -96,6 +96,10 @@ type kafkaSink struct {
 
 	stats kafkaStats
 
+	// txnMu serializes the beginning and the end of the Kafka transactions of
+	// a transactional producer with the messages emitted in them.
+	txnMu syncutil.Mutex
+
 	// Only synchronized between the client goroutine and the worker goroutine.
 	mu struct {
 		syncutil.Mutex
@@ -119,6 +123,19 @@ type saramaConfig struct {
 
 	RequiredAcks string `json:",omitempty"`
 
+	// Idempotent enables the idempotent producer, which assigns sequence
+	// numbers to the messages so that the brokers discard the duplicates
+	// produced by retries. It requires RequiredAcks to be ALL.
+	Idempotent bool `json:",omitempty"`
+
+	// Transactional enables the transactional producer, which implies
+	// Idempotent. The messages are emitted in Kafka transactions, which are
+	// committed when the sink is flushed, i.e. before the changefeed
+	// checkpoints the progress covering them, and aborted if any message
+	// failed to be delivered, so that consumers with read_committed isolation
+	// only see complete batches of messages.
+	Transactional bool `json:",omitempty"`
+
 	Version string `json:",omitempty"`
 }
 
@@ -131,6 +148,11 @@ func (c saramaConfig) Validate() error {
 	if (c.Flush.Bytes > 0 || c.Flush.Messages > 1) && c.Flush.Frequency == 0 {
 		return errors.New("Flush.Frequency must be > 0 when Flush.Bytes > 0 or Flush.Messages > 1")
 	}
+	if (c.Idempotent || c.Transactional) && c.RequiredAcks != "" {
+		if acks, err := parseRequiredAcks(c.RequiredAcks); err == nil && acks != sarama.WaitForAll {
+			return errors.New("RequiredAcks must be ALL when Idempotent or Transactional is true")
+		}
+	}
 	return nil
 }
 
@@ -193,6 +215,19 @@ func (s *kafkaSink) Dial() error {
 
 // Close implements the Sink interface.
 func (s *kafkaSink) Close() error {
+	// Abort the open transaction, if any, rather than leaving it to time out,
+	// which would keep consumers with read_committed isolation from reading
+	// past it in the meantime. This needs the worker to acknowledge the
+	// outstanding messages, so it is done before stopping the worker.
+	if s.producer.IsTransactional() {
+		s.txnMu.Lock()
+		if s.producer.TxnStatus()&sarama.ProducerTxnFlagInTransaction != 0 {
+			if err := s.producer.AbortTxn(); err != nil {
+				log.Warningf(s.ctx, "aborting kafka transaction: %v", err)
+			}
+		}
+		s.txnMu.Unlock()
+	}
 	close(s.stopWorkerCh)
 	s.worker.Wait()
 	// If we're shutting down, we don't care what happens to the outstanding
@@ -256,7 +291,7 @@ func (s *kafkaSink) EmitResolvedTimestamp(
 		s.lastMetadataRefresh = timeutil.Now()
 	}
 
-	return s.topics.Each(func(topic string) error {
+	if err := s.topics.Each(func(topic string) error {
 		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
 		if err != nil {
 			return err
@@ -283,13 +318,62 @@ func (s *kafkaSink) EmitResolvedTimestamp(
 			}
 		}
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+	// Resolved timestamps are emitted once the progress they cover has been
+	// checkpointed, and the sink is not flushed afterwards, so commit their
+	// transaction right away.
+	if s.producer.IsTransactional() {
+		return s.endTxn(ctx)
+	}
+	return nil
 }
 
 // Flush implements the Sink interface.
 func (s *kafkaSink) Flush(ctx context.Context) error {
 	defer s.metrics.recordFlushRequestCallback()()
 
+	return s.waitForInflight(ctx)
+}
+
+// FlushForCheckpoint implements the SinkWithCheckpoint interface. With a
+// transactional producer, the current transaction is committed once all its
+// messages are delivered, so that the transactions end at the checkpoints of
+// the changefeed.
+func (s *kafkaSink) FlushForCheckpoint(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	if !s.producer.IsTransactional() {
+		return s.waitForInflight(ctx)
+	}
+	return s.endTxn(ctx)
+}
+
+// endTxn waits for the inflight messages and then commits the current
+// transaction of a transactional producer, or aborts it if any of the
+// messages could not be delivered. The transaction is left open if the
+// context is canceled.
+func (s *kafkaSink) endTxn(ctx context.Context) error {
+	s.txnMu.Lock()
+	defer s.txnMu.Unlock()
+
+	flushErr := s.waitForInflight(ctx)
+	if ctx.Err() != nil || s.producer.TxnStatus()&sarama.ProducerTxnFlagInTransaction == 0 {
+		return flushErr
+	}
+	if flushErr != nil {
+		if err := s.producer.AbortTxn(); err != nil {
+			return errors.CombineErrors(flushErr, errors.Wrap(err, "aborting kafka transaction"))
+		}
+		return flushErr
+	}
+	return errors.Wrap(s.producer.CommitTxn(), "committing kafka transaction")
+}
+
+// waitForInflight blocks until all the inflight messages are acknowledged and
+// returns the first error encountered delivering them, if any.
+func (s *kafkaSink) waitForInflight(ctx context.Context) error {
 	flushCh := make(chan struct{}, 1)
 
 	s.mu.Lock()
@@ -333,6 +417,17 @@ func (s *kafkaSink) startInflightMessage(ctx context.Context) error {
 }
 
 func (s *kafkaSink) emitMessage(ctx context.Context, msg *sarama.ProducerMessage) error {
+	if s.producer.IsTransactional() {
+		// The message must be handed to the producer before the transaction can
+		// end, so hold txnMu until then.
+		s.txnMu.Lock()
+		defer s.txnMu.Unlock()
+		if s.producer.TxnStatus()&sarama.ProducerTxnFlagInTransaction == 0 {
+			if err := s.producer.BeginTxn(); err != nil {
+				return errors.Wrap(err, "beginning kafka transaction")
+			}
+		}
+	}
 	if err := s.startInflightMessage(ctx); err != nil {
 		return err
 	}
@@ -465,9 +560,24 @@ func (c *saramaConfig) Apply(kafka *sarama.Config) error {
 		}
 		kafka.Producer.RequiredAcks = parsedAcks
 	}
+	if c.Idempotent || c.Transactional {
+		// Sarama only supports idempotent producers with a single in flight
+		// request per broker, so that retries cannot reorder the messages.
+		kafka.Producer.Idempotent = true
+		kafka.Producer.RequiredAcks = sarama.WaitForAll
+		kafka.Net.MaxOpenRequests = 1
+	}
+	if c.Transactional {
+		// The ID is completed for each sink by makeKafkaSink.
+		kafka.Producer.Transaction.ID = kafkaTransactionalIDPrefix
+	}
 	return nil
 }
 
+// kafkaTransactionalIDPrefix is the prefix of the transactional IDs of the
+// producers of transactional kafka sinks.
+const kafkaTransactionalIDPrefix = "crdb-changefeed"
+
 func parseRequiredAcks(a string) (sarama.RequiredAcks, error) {
 	switch a {
 	case "0", "NONE":
@@ -643,6 +753,8 @@ func makeKafkaSink(
 	u sinkURL,
 	targets []jobspb.ChangefeedTargetSpecification,
 	opts map[string]string,
+	jobID jobspb.JobID,
+	partition string,
 	m *sliMetrics,
 ) (Sink, error) {
 	kafkaTopicPrefix := u.consumeParam(changefeedbase.SinkParamTopicPrefix)
@@ -655,6 +767,15 @@ func makeKafkaSink(
 	if err != nil {
 		return nil, err
 	}
+	if config.Producer.Transaction.ID != "" {
+		// Every processor of the changefeed has its own sink, and producers
+		// sharing a transactional ID fence each other off. The ID of a sink is
+		// stable across restarts of the changefeed, so that the producer of a
+		// restarted processor fences off the producer it replaces and aborts
+		// its open transaction.
+		config.Producer.Transaction.ID = fmt.Sprintf("%s-%d-%s",
+			config.Producer.Transaction.ID, jobID, partition)
+	}
 
 	topics, err := MakeTopicNamer(
 		targets,
@@ -0,0 +1,480 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	defaultNATSPort = "4222"
+	// natsKeyHeader is the header of the messages holding the key of the row.
+	natsKeyHeader = "Cockroach-Key"
+	// natsMsgIDHeader is the header JetStream de-duplicates messages on, within
+	// the duplicate window of the stream.
+	natsMsgIDHeader = "Nats-Msg-Id"
+)
+
+// natsSinkConfig is the configuration of the nats sink, which can be
+// overridden with the nats_sink_config option, e.g.
+// {"Flush": {"Messages": 100}, "Retry": {"Max": 5, "Backoff": "1s"}, "Timeout": "30s"}.
+type natsSinkConfig struct {
+	streamSinkConfig
+}
+
+func getNATSSinkConfig(opts map[string]string) (natsSinkConfig, error) {
+	cfg := natsSinkConfig{streamSinkConfig: defaultStreamSinkConfig()}
+	if configStr, haveOverride := opts[changefeedbase.OptNATSSinkConfig]; haveOverride {
+		if err := json.Unmarshal([]byte(configStr), &cfg); err != nil {
+			return cfg, errors.Wrapf(err,
+				"failed to parse nats sink config; check %s option", changefeedbase.OptNATSSinkConfig)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, errors.Wrap(err, "invalid nats sink configuration")
+	}
+	return cfg, nil
+}
+
+// natsServerInfo is the part of the INFO sent by nats servers that we use.
+type natsServerInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	Headers     bool `json:"headers"`
+}
+
+// natsConnectOptions are the options sent with CONNECT.
+type natsConnectOptions struct {
+	Verbose      bool   `json:"verbose"`
+	Pedantic     bool   `json:"pedantic"`
+	TLSRequired  bool   `json:"tls_required"`
+	Name         string `json:"name"`
+	Lang         string `json:"lang"`
+	Version      string `json:"version"`
+	Protocol     int    `json:"protocol"`
+	Headers      bool   `json:"headers"`
+	NoResponders bool   `json:"no_responders"`
+	User         string `json:"user,omitempty"`
+	Pass         string `json:"pass,omitempty"`
+	AuthToken    string `json:"auth_token,omitempty"`
+}
+
+// natsMsg is a message delivered by the server to our subscription.
+type natsMsg struct {
+	subject string
+	header  []byte
+	data    []byte
+}
+
+// jetStreamAck is the reply of JetStream to a published message.
+type jetStreamAck struct {
+	Stream string `json:"stream"`
+	Seq    uint64 `json:"seq"`
+	Error  *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error"`
+}
+
+// natsClient publishes messages to JetStream with the nats protocol. Every
+// message is published with its own reply subject, on which JetStream
+// acknowledges it once it's stored in the stream capturing its subject. The
+// messages of a batch are all sent before their acknowledgements are read.
+// Every message carries an id derived from its subject, MVCC timestamp and key,
+// so that JetStream drops the messages published again by retries of batches
+// which were partially stored.
+type natsClient struct {
+	addr      string
+	tlsCfg    *tls.Config
+	user      string
+	password  string
+	authToken string
+	timeout   time.Duration
+
+	conn *streamConn
+	// inbox is the prefix of the reply subjects, to which the connection is
+	// subscribed.
+	inbox     string
+	nextReply uint64
+}
+
+var _ streamClient = (*natsClient)(nil)
+
+// Dial implements the streamClient interface.
+func (c *natsClient) Dial(ctx context.Context) error {
+	conn, err := dialStreamConn(ctx, c.addr)
+	if err == nil {
+		if err = c.handshake(ctx, conn); err != nil {
+			_ = conn.Close()
+		}
+	}
+	if err != nil {
+		return pgerror.Wrapf(err, pgcode.CannotConnectNow, `connecting to nats: %s`, c.addr)
+	}
+	c.conn = conn
+	return nil
+}
+
+// handshake reads the INFO of the server, sets up TLS, sends CONNECT and
+// subscribes to the inbox.
+func (c *natsClient) handshake(ctx context.Context, conn *streamConn) error {
+	stop := conn.watch(ctx, c.timeout)
+	line, err := readNATSLine(conn)
+	stop()
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, `INFO `) {
+		return errors.Errorf(`unexpected greeting from nats server: %q`, line)
+	}
+	var info natsServerInfo
+	if err := json.Unmarshal([]byte(line[len(`INFO `):]), &info); err != nil {
+		return errors.Wrap(err, `parsing nats server info`)
+	}
+	if !info.Headers {
+		return errors.New(`nats server does not support headers`)
+	}
+	if c.tlsCfg != nil {
+		if err := conn.startTLS(ctx, c.tlsCfg); err != nil {
+			return err
+		}
+	} else if info.TLSRequired {
+		return errors.Errorf(`nats server requires TLS; set %s=true`, changefeedbase.SinkParamTLSEnabled)
+	}
+	defer conn.watch(ctx, c.timeout)()
+
+	connectOpts, err := json.Marshal(natsConnectOptions{
+		TLSRequired:  c.tlsCfg != nil,
+		Name:         `cockroachdb-changefeed`,
+		Lang:         `go`,
+		Protocol:     1,
+		Headers:      true,
+		NoResponders: true,
+		User:         c.user,
+		Pass:         c.password,
+		AuthToken:    c.authToken,
+	})
+	if err != nil {
+		return err
+	}
+	c.inbox = `_INBOX.` + uuid.MakeV4().String() + `.`
+	_, _ = conn.w.WriteString(`CONNECT ` + string(connectOpts) + "\r\n")
+	_, _ = conn.w.WriteString(`SUB ` + c.inbox + "* 1\r\n")
+	_, _ = conn.w.WriteString("PING\r\n")
+	if err := conn.w.Flush(); err != nil {
+		return err
+	}
+	// The PONG tells that the server processed CONNECT and SUB.
+	for {
+		isMsg, _, err := readNATSOp(conn)
+		if err != nil {
+			return err
+		}
+		if !isMsg {
+			return nil
+		}
+	}
+}
+
+// Publish implements the streamClient interface.
+func (c *natsClient) Publish(
+	ctx context.Context, msgs []streamMessage,
+) (unacked []streamMessage, err error) {
+	if c.conn == nil {
+		if err := c.Dial(ctx); err != nil {
+			return msgs, err
+		}
+	}
+	conn := c.conn
+	defer conn.watch(ctx, c.timeout)()
+
+	pending := make(map[string]int, len(msgs))
+	var header []byte
+	for i, msg := range msgs {
+		c.nextReply++
+		reply := c.inbox + strconv.FormatUint(c.nextReply, 10)
+		pending[reply] = i
+		header = append(header[:0], "NATS/1.0\r\n"...)
+		header = appendNATSMsgIDHeader(header, msg)
+		if msg.key != nil {
+			header = append(header, natsKeyHeader+": "...)
+			header = append(header, msg.key...)
+			header = append(header, "\r\n"...)
+		}
+		header = append(header, "\r\n"...)
+		_, _ = conn.w.WriteString(`HPUB ` + msg.topic + ` ` + reply + ` ` +
+			strconv.Itoa(len(header)) + ` ` + strconv.Itoa(len(header)+len(msg.value)) + "\r\n")
+		_, _ = conn.w.Write(header)
+		_, _ = conn.w.Write(msg.value)
+		_, _ = conn.w.WriteString("\r\n")
+	}
+	if err := conn.w.Flush(); err != nil {
+		return msgs, c.connError(err)
+	}
+
+	// Wait for the acknowledgements of all the messages, and return the first
+	// error reported by JetStream along with the messages which weren't
+	// stored. The messages still pending after an I/O error may or may not
+	// have been stored; they are considered unacknowledged.
+	acked := make([]bool, len(msgs))
+	var firstErr error
+	for len(pending) > 0 {
+		isMsg, msg, err := readNATSOp(conn)
+		if err != nil {
+			firstErr = c.connError(err)
+			break
+		}
+		if !isMsg {
+			continue
+		}
+		i, ok := pending[msg.subject]
+		if !ok {
+			// A late acknowledgement of a batch which timed out.
+			continue
+		}
+		delete(pending, msg.subject)
+		if err := parseJetStreamAck(msg); err != nil {
+			if firstErr == nil {
+				firstErr = errors.Wrapf(err, `publishing to nats subject %s`, msgs[i].topic)
+			}
+			continue
+		}
+		acked[i] = true
+	}
+	if firstErr == nil {
+		return nil, nil
+	}
+	for i := range msgs {
+		if !acked[i] {
+			unacked = append(unacked, msgs[i])
+		}
+	}
+	return unacked, firstErr
+}
+
+// appendNATSMsgIDHeader appends the Nats-Msg-Id header of the message, made of
+// its MVCC timestamp and key. The subject is part of it too, since the rows of
+// different tables published to the subjects of a single stream may have the
+// same key and timestamp. Resolved messages have no key, so their id is made of
+// their subject and resolved timestamp.
+func appendNATSMsgIDHeader(header []byte, msg streamMessage) []byte {
+	header = append(header, natsMsgIDHeader+": "...)
+	header = append(header, msg.topic...)
+	header = append(header, ' ')
+	header = append(header, msg.mvcc.AsOfSystemTime()...)
+	if msg.key != nil {
+		header = append(header, ' ')
+		header = append(header, msg.key...)
+	}
+	return append(header, "\r\n"...)
+}
+
+// connError closes the connection after an I/O or protocol error, so that the
+// next batch is sent on a new connection.
+func (c *natsClient) connError(err error) error {
+	_ = c.conn.Close()
+	c.conn = nil
+	return errors.Wrap(err, `sending to nats`)
+}
+
+// Close implements the streamClient interface.
+func (c *natsClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+func parseJetStreamAck(msg natsMsg) error {
+	// Without a stream capturing the subject, the server replies right away
+	// with an empty message with a 503 status.
+	if bytes.HasPrefix(msg.header, []byte(`NATS/1.0 503`)) {
+		return errors.New(`no JetStream stream captures the subject`)
+	}
+	var ack jetStreamAck
+	if err := json.Unmarshal(msg.data, &ack); err != nil {
+		return errors.Wrapf(err, `parsing JetStream acknowledgement %q`, msg.data)
+	}
+	if ack.Error != nil {
+		return errors.Errorf(`JetStream error %d: %s`, ack.Error.Code, ack.Error.Description)
+	}
+	if ack.Stream == `` {
+		return errors.Errorf(`unexpected JetStream acknowledgement %q`, msg.data)
+	}
+	return nil
+}
+
+func readNATSLine(conn *streamConn) (string, error) {
+	line, err := conn.r.ReadString('\n')
+	if err != nil {
+		return ``, err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readNATSOp reads operations sent by the server until it gets a PONG, or a
+// message which it returns. PINGs are answered, -ERR is returned as an error
+// and other operations are skipped.
+func readNATSOp(conn *streamConn) (isMsg bool, msg natsMsg, err error) {
+	for {
+		line, err := readNATSLine(conn)
+		if err != nil {
+			return false, natsMsg{}, err
+		}
+		op, args := line, ``
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			op, args = line[:i], line[i+1:]
+		}
+		switch strings.ToUpper(op) {
+		case `PING`:
+			_, _ = conn.w.WriteString("PONG\r\n")
+			if err := conn.w.Flush(); err != nil {
+				return false, natsMsg{}, err
+			}
+		case `PONG`:
+			return false, natsMsg{}, nil
+		case `+OK`, `INFO`:
+		case `-ERR`:
+			return false, natsMsg{}, errors.Errorf(`nats server error: %s`, args)
+		case `MSG`, `HMSG`:
+			msg, err := readNATSMsg(conn, strings.ToUpper(op) == `HMSG`, strings.Fields(args))
+			return true, msg, err
+		default:
+			return false, natsMsg{}, errors.Errorf(`unexpected operation from nats server: %q`, line)
+		}
+	}
+}
+
+// readNATSMsg reads the payload of a MSG or HMSG operation, whose arguments are
+// the subject, the subscription id, the optional reply subject and the sizes
+// of the payload.
+func readNATSMsg(conn *streamConn, hasHeader bool, args []string) (natsMsg, error) {
+	numSizes := 1
+	if hasHeader {
+		numSizes = 2
+	}
+	if len(args) != 2+numSizes && len(args) != 3+numSizes {
+		return natsMsg{}, errors.Errorf(`malformed message from nats server: %q`, args)
+	}
+	sizes := make([]int, numSizes)
+	for i := range sizes {
+		n, err := strconv.Atoi(args[len(args)-numSizes+i])
+		if err != nil || n < 0 {
+			return natsMsg{}, errors.Errorf(`malformed message from nats server: %q`, args)
+		}
+		sizes[i] = n
+	}
+	total, headerLen := sizes[numSizes-1], 0
+	if hasHeader {
+		headerLen = sizes[0]
+		if headerLen > total {
+			return natsMsg{}, errors.Errorf(`malformed message from nats server: %q`, args)
+		}
+	}
+	buf := make([]byte, total+2)
+	if _, err := io.ReadFull(conn.r, buf); err != nil {
+		return natsMsg{}, err
+	}
+	return natsMsg{subject: args[0], header: buf[:headerLen], data: buf[headerLen:total]}, nil
+}
+
+// natsSubjectSanitizer turns the names of the topics into valid subjects by
+// replacing whitespace and the wildcards * and >.
+func natsSubjectSanitizer(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '*' || r == '>' || unicode.IsSpace(r) {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// makeNATSSink returns a sink publishing the messages of each topic to the
+// subject named after it, which has to be captured by a JetStream stream. The
+// key of the rows is sent in the Cockroach-Key header of their messages, and
+// every message has a Nats-Msg-Id header for JetStream to de-duplicate retries.
+func makeNATSSink(
+	ctx context.Context,
+	u sinkURL,
+	targets []jobspb.ChangefeedTargetSpecification,
+	opts map[string]string,
+	m *sliMetrics,
+) (Sink, error) {
+	// The keys are sent in headers, which can't hold arbitrary bytes.
+	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	case changefeedbase.OptFormatJSON:
+	default:
+		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
+			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
+	}
+
+	topicPrefix := u.consumeParam(changefeedbase.SinkParamTopicPrefix)
+	topicName := u.consumeParam(changefeedbase.SinkParamTopicName)
+	authToken := u.consumeParam(changefeedbase.SinkParamAuthToken)
+	tlsCfg, err := buildStreamSinkTLSConfig(u)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := getNATSSinkConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &natsClient{
+		addr:      u.Host,
+		tlsCfg:    tlsCfg,
+		authToken: authToken,
+		timeout:   time.Duration(cfg.Timeout),
+	}
+	if u.Port() == `` {
+		client.addr = net.JoinHostPort(u.Hostname(), defaultNATSPort)
+	}
+	if u.User != nil {
+		if authToken != `` {
+			return nil, errors.Errorf(`only one of %s and URL credentials can be set`,
+				changefeedbase.SinkParamAuthToken)
+		}
+		client.user = u.User.Username()
+		client.password, _ = u.User.Password()
+		// A lone username is a token, as in the URLs accepted by nats clients.
+		if client.password == `` {
+			client.user, client.authToken = ``, client.user
+		}
+	}
+
+	topics, err := MakeTopicNamer(targets,
+		WithPrefix(topicPrefix), WithSingleName(topicName), WithSanitizeFn(natsSubjectSanitizer))
+	if err != nil {
+		return nil, err
+	}
+
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown nats sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	return makeStreamSink(ctx, client, topics, cfg.streamSinkConfig, m, m.natsSinkMetrics()), nil
+}
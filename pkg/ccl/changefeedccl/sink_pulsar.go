@@ -0,0 +1,557 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	pulsarlog "github.com/apache/pulsar-client-go/pulsar/log"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+func isPulsarSink(u *url.URL) bool {
+	switch u.Scheme {
+	case changefeedbase.SinkSchemePulsar, changefeedbase.SinkSchemePulsarSSL:
+		return true
+	default:
+		return false
+	}
+}
+
+// pulsarClient is a small interface restricting the functionality in
+// pulsar.Client.
+type pulsarClient interface {
+	// CreateProducer creates a producer for the topic of the options.
+	CreateProducer(pulsar.ProducerOptions) (pulsar.Producer, error)
+	// TopicPartitions returns the names of the partitions of the topic.
+	TopicPartitions(topic string) ([]string, error)
+	// Close closes the connections to the brokers.
+	Close()
+}
+
+// pulsarSink emits to Pulsar asynchronously. It is not concurrency-safe; all
+// calls to Emit and Flush should be from the same goroutine.
+//
+// Rows are published with their encoded key as the message key, which the
+// producers hash to pick the partition of the message. Since a producer
+// delivers the messages it is given in order, all the changes to a row reach
+// the same partition in the order in which they were emitted.
+type pulsarSink struct {
+	ctx        context.Context
+	clientOpts pulsar.ClientOptions
+	cfg        pulsarSinkConfig
+	client     pulsarClient
+	topics     *TopicNamer
+
+	// producers has the producer of each topic, which routes keyed messages
+	// to the partitions of the topic. Resolved timestamps are sent to every
+	// partition by producers of the individual partitions, which are also
+	// kept here under the name of the partition.
+	producers map[string]pulsar.Producer
+
+	scratch bufalloc.ByteAllocator
+	metrics *sliMetrics
+
+	// Only synchronized between the client goroutine and the goroutines of the
+	// pulsar client running the callbacks of the messages.
+	mu struct {
+		syncutil.Mutex
+		inflight int64
+		flushErr error
+		flushCh  chan struct{}
+	}
+}
+
+// pulsarSinkConfig is the configuration of the pulsar producers, which can be
+// overridden with the pulsar_sink_config option.
+type pulsarSinkConfig struct {
+	// Flush describes how the producers batch messages. These settings mirror
+	// the batching settings of pulsar.ProducerOptions; zero values keep the
+	// defaults of the pulsar client.
+	Flush struct {
+		Messages  int          `json:",omitempty"`
+		Bytes     int          `json:",omitempty"`
+		Frequency jsonDuration `json:",omitempty"`
+	}
+
+	// Compression is the compression codec of the batches, one of NONE (the
+	// default), LZ4, ZLIB or ZSTD.
+	Compression string `json:",omitempty"`
+}
+
+func (c pulsarSinkConfig) Validate() error {
+	if c.Flush.Messages < 0 || c.Flush.Bytes < 0 || c.Flush.Frequency < 0 {
+		return errors.New("Flush.Messages, Flush.Bytes and Flush.Frequency must not be negative")
+	}
+	_, err := parsePulsarCompression(c.Compression)
+	return err
+}
+
+// Apply configures the provided producer options based on this config.
+func (c pulsarSinkConfig) Apply(opts *pulsar.ProducerOptions) error {
+	opts.BatchingMaxMessages = uint(c.Flush.Messages)
+	opts.BatchingMaxSize = uint(c.Flush.Bytes)
+	opts.BatchingMaxPublishDelay = time.Duration(c.Flush.Frequency)
+	compression, err := parsePulsarCompression(c.Compression)
+	if err != nil {
+		return err
+	}
+	opts.CompressionType = compression
+	return nil
+}
+
+func parsePulsarCompression(c string) (pulsar.CompressionType, error) {
+	switch strings.ToUpper(c) {
+	case "", "NONE":
+		return pulsar.NoCompression, nil
+	case "LZ4":
+		return pulsar.LZ4, nil
+	case "ZLIB":
+		return pulsar.ZLib, nil
+	case "ZSTD":
+		return pulsar.ZSTD, nil
+	default:
+		return pulsar.NoCompression,
+			fmt.Errorf(`invalid compression value "%s", must be "NONE", "LZ4", "ZLIB" or "ZSTD"`, c)
+	}
+}
+
+func getPulsarSinkConfig(opts map[string]string) (pulsarSinkConfig, error) {
+	var config pulsarSinkConfig
+	if configStr, haveOverride := opts[changefeedbase.OptPulsarSinkConfig]; haveOverride {
+		if err := json.Unmarshal([]byte(configStr), &config); err != nil {
+			return config, errors.Wrapf(err,
+				"failed to parse pulsar sink config; check %s option", changefeedbase.OptPulsarSinkConfig)
+		}
+	}
+	if err := config.Validate(); err != nil {
+		return config, errors.Wrap(err, "invalid pulsar sink configuration")
+	}
+	return config, nil
+}
+
+// Dial implements the Sink interface.
+func (s *pulsarSink) Dial() error {
+	client, err := pulsar.NewClient(s.clientOpts)
+	if err != nil {
+		return pgerror.Wrapf(err, pgcode.CannotConnectNow,
+			`connecting to pulsar: %s`, s.clientOpts.URL)
+	}
+	s.client = client
+	return nil
+}
+
+// Close implements the Sink interface.
+func (s *pulsarSink) Close() error {
+	for _, p := range s.producers {
+		p.Close()
+	}
+	s.producers = nil
+	// s.client is only nil if the sink was never dialed.
+	if s.client != nil {
+		s.client.Close()
+	}
+	return nil
+}
+
+// producer returns the producer of the given topic, creating it if this is the
+// first message sent to the topic.
+func (s *pulsarSink) producer(topic string) (pulsar.Producer, error) {
+	if p, ok := s.producers[topic]; ok {
+		return p, nil
+	}
+	opts := pulsar.ProducerOptions{
+		Topic: topic,
+		// The key based batch builder groups the messages of each key in their
+		// own batches, which lets consumers with a key shared subscription
+		// receive the changes to a row in order.
+		BatcherBuilderType: pulsar.KeyBasedBatchBuilder,
+	}
+	if err := s.cfg.Apply(&opts); err != nil {
+		return nil, err
+	}
+	p, err := s.client.CreateProducer(opts)
+	if err != nil {
+		return nil, pgerror.Wrapf(err, pgcode.CannotConnectNow,
+			`creating pulsar producer for topic %s`, topic)
+	}
+	if s.producers == nil {
+		s.producers = make(map[string]pulsar.Producer)
+	}
+	s.producers[topic] = p
+	return p, nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *pulsarSink) EmitRow(
+	ctx context.Context,
+	topicDescr TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	topic, err := s.topics.Name(topicDescr)
+	if err != nil {
+		return err
+	}
+	p, err := s.producer(topic)
+	if err != nil {
+		return err
+	}
+
+	msg := &pulsar.ProducerMessage{
+		Key:     string(key),
+		Payload: value,
+	}
+	updateMetrics := s.metrics.recordOneMessage()
+	s.emitMessage(ctx, p, msg, func(err error) {
+		if err == nil {
+			updateMetrics(mvcc, len(key)+len(value), sinkDoesNotCompress)
+		}
+		alloc.Release(s.ctx)
+	})
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *pulsarSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+
+	return s.topics.Each(func(topic string) error {
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+		if err != nil {
+			return err
+		}
+		s.scratch, payload = s.scratch.Copy(payload, 0 /* extraCap */)
+
+		// The partitions of a topic are looked up every time, so that the
+		// partitions added to the topic since the last resolved timestamp get
+		// this one.
+		partitions, err := s.client.TopicPartitions(topic)
+		if err != nil {
+			return err
+		}
+		for _, partition := range partitions {
+			p, err := s.producer(partition)
+			if err != nil {
+				return err
+			}
+			s.emitMessage(ctx, p, &pulsar.ProducerMessage{Payload: payload}, nil /* onAck */)
+		}
+		return nil
+	})
+}
+
+// Flush implements the Sink interface.
+func (s *pulsarSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	// Send the messages buffered in the batches of the producers right away
+	// instead of waiting for the batching delay.
+	for _, p := range s.producers {
+		if err := p.Flush(); err != nil {
+			s.setFlushErr(err)
+		}
+	}
+
+	flushCh := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	inflight := s.mu.inflight
+	flushErr := s.mu.flushErr
+	s.mu.flushErr = nil
+	immediateFlush := inflight == 0 || flushErr != nil
+	if !immediateFlush {
+		s.mu.flushCh = flushCh
+	}
+	s.mu.Unlock()
+
+	if immediateFlush {
+		return flushErr
+	}
+
+	if log.V(1) {
+		log.Infof(ctx, "flush waiting for %d inflight messages", inflight)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-flushCh:
+		s.mu.Lock()
+		flushErr := s.mu.flushErr
+		s.mu.flushErr = nil
+		s.mu.Unlock()
+		return flushErr
+	}
+}
+
+func (s *pulsarSink) setFlushErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.flushErr == nil {
+		s.mu.flushErr = err
+	}
+}
+
+// emitMessage sends the message asynchronously. onAck, if not nil, is called
+// once the message is acknowledged by the broker, or failed.
+func (s *pulsarSink) emitMessage(
+	ctx context.Context, p pulsar.Producer, msg *pulsar.ProducerMessage, onAck func(error),
+) {
+	s.mu.Lock()
+	s.mu.inflight++
+	if log.V(2) {
+		log.Infof(ctx, "emitting %d inflight records to pulsar", s.mu.inflight)
+	}
+	s.mu.Unlock()
+
+	p.SendAsync(ctx, msg, func(_ pulsar.MessageID, msg *pulsar.ProducerMessage, err error) {
+		if err != nil && msg != nil && msg.Key != "" {
+			err = errors.Wrapf(err, "while sending message with key=%s, size=%d",
+				msg.Key, len(msg.Key)+len(msg.Payload))
+		}
+		if onAck != nil {
+			onAck(err)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.mu.inflight--
+		if s.mu.flushErr == nil && err != nil {
+			s.mu.flushErr = err
+		}
+		if s.mu.inflight == 0 && s.mu.flushCh != nil {
+			s.mu.flushCh <- struct{}{}
+			s.mu.flushCh = nil
+		}
+	})
+}
+
+// Topics gives the names of all topics that have been initialized
+// and will receive resolved timestamps.
+func (s *pulsarSink) Topics() []string {
+	return s.topics.DisplayNamesSlice()
+}
+
+func buildPulsarClientOptions(ctx context.Context, u sinkURL) (pulsar.ClientOptions, error) {
+	dialConfig := struct {
+		tlsSkipVerify bool
+		caCert        []byte
+		clientCert    []byte
+		clientKey     []byte
+		authToken     string
+	}{}
+
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &dialConfig.tlsSkipVerify); err != nil {
+		return pulsar.ClientOptions{}, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &dialConfig.caCert); err != nil {
+		return pulsar.ClientOptions{}, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientCert, &dialConfig.clientCert); err != nil {
+		return pulsar.ClientOptions{}, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientKey, &dialConfig.clientKey); err != nil {
+		return pulsar.ClientOptions{}, err
+	}
+	dialConfig.authToken = u.consumeParam(changefeedbase.SinkParamAuthToken)
+
+	tlsEnabled := u.Scheme == changefeedbase.SinkSchemePulsarSSL
+	if !tlsEnabled {
+		if dialConfig.tlsSkipVerify {
+			return pulsar.ClientOptions{}, errors.Errorf(`%s requires a %s sink`,
+				changefeedbase.SinkParamSkipTLSVerify, changefeedbase.SinkSchemePulsarSSL)
+		}
+		if dialConfig.clientCert != nil {
+			return pulsar.ClientOptions{}, errors.Errorf(`%s requires a %s sink`,
+				changefeedbase.SinkParamClientCert, changefeedbase.SinkSchemePulsarSSL)
+		}
+	}
+	// The pulsar client only reads trusted certificates from files, so the
+	// certificate of the brokers has to be signed by one of the system's
+	// certificate authorities.
+	if dialConfig.caCert != nil {
+		return pulsar.ClientOptions{}, errors.Errorf(`%s is not supported by pulsar sinks`,
+			changefeedbase.SinkParamCACert)
+	}
+
+	if dialConfig.clientCert != nil && dialConfig.clientKey == nil {
+		return pulsar.ClientOptions{}, errors.Errorf(`%s requires %s to be set`,
+			changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+	} else if dialConfig.clientKey != nil && dialConfig.clientCert == nil {
+		return pulsar.ClientOptions{}, errors.Errorf(`%s requires %s to be set`,
+			changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+	if dialConfig.clientCert != nil && dialConfig.authToken != `` {
+		return pulsar.ClientOptions{}, errors.Errorf(`only one of %s and %s can be set`,
+			changefeedbase.SinkParamClientCert, changefeedbase.SinkParamAuthToken)
+	}
+
+	opts := pulsar.ClientOptions{
+		URL:                        u.Scheme + `://` + u.Host,
+		TLSAllowInsecureConnection: dialConfig.tlsSkipVerify,
+		TLSValidateHostname:        tlsEnabled && !dialConfig.tlsSkipVerify,
+		Logger:                     newPulsarLogAdapter(ctx),
+	}
+	if dialConfig.clientCert != nil {
+		cert, err := tls.X509KeyPair(dialConfig.clientCert, dialConfig.clientKey)
+		if err != nil {
+			return pulsar.ClientOptions{}, errors.Wrap(err, `invalid client certificate data provided`)
+		}
+		opts.Authentication = pulsar.NewAuthenticationFromTLSCertSupplier(
+			func() (*tls.Certificate, error) { return &cert, nil })
+	}
+	if dialConfig.authToken != `` {
+		opts.Authentication = pulsar.NewAuthenticationToken(dialConfig.authToken)
+	}
+	return opts, nil
+}
+
+// pulsarTopicSanitizer returns the function turning the names of the tables
+// into the names of the topics. Short topic names are resolved by the brokers
+// in the default namespace of the public tenant, a tenant and namespace can be
+// given to use fully qualified names instead.
+func pulsarTopicSanitizer(tenant, namespace string) (func(string) string, error) {
+	if tenant == `` && namespace == `` {
+		return SQLNameToKafkaName, nil
+	}
+	if tenant == `` || namespace == `` {
+		return nil, errors.Errorf(`%s and %s must be set together`,
+			changefeedbase.SinkParamPulsarTenant, changefeedbase.SinkParamPulsarNamespace)
+	}
+	prefix := fmt.Sprintf(`persistent://%s/%s/`, tenant, namespace)
+	return func(s string) string {
+		return prefix + SQLNameToKafkaName(s)
+	}, nil
+}
+
+func makePulsarSink(
+	ctx context.Context,
+	u sinkURL,
+	targets []jobspb.ChangefeedTargetSpecification,
+	opts map[string]string,
+	m *sliMetrics,
+) (Sink, error) {
+	topicPrefix := u.consumeParam(changefeedbase.SinkParamTopicPrefix)
+	topicName := u.consumeParam(changefeedbase.SinkParamTopicName)
+	sanitize, err := pulsarTopicSanitizer(
+		u.consumeParam(changefeedbase.SinkParamPulsarTenant),
+		u.consumeParam(changefeedbase.SinkParamPulsarNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	clientOpts, err := buildPulsarClientOptions(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := getPulsarSinkConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := MakeTopicNamer(
+		targets, WithPrefix(topicPrefix), WithSingleName(topicName), WithSanitizeFn(sanitize))
+	if err != nil {
+		return nil, err
+	}
+
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown pulsar sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	return &pulsarSink{
+		ctx:        ctx,
+		clientOpts: clientOpts,
+		cfg:        cfg,
+		topics:     topics,
+		metrics:    m,
+	}, nil
+}
+
+// pulsarLogAdapter sends the logs of the pulsar client to our logs. The fields
+// of the log entries become tags of the context.
+type pulsarLogAdapter struct {
+	ctx context.Context
+}
+
+var _ pulsarlog.Logger = (*pulsarLogAdapter)(nil)
+
+func newPulsarLogAdapter(ctx context.Context) *pulsarLogAdapter {
+	return &pulsarLogAdapter{ctx: logtags.AddTag(ctx, "pulsar-client", nil)}
+}
+
+func (l *pulsarLogAdapter) withFields(fields pulsarlog.Fields) *pulsarLogAdapter {
+	ctx := l.ctx
+	for name, value := range fields {
+		ctx = logtags.AddTag(ctx, name, value)
+	}
+	return &pulsarLogAdapter{ctx: ctx}
+}
+
+func (l *pulsarLogAdapter) SubLogger(fields pulsarlog.Fields) pulsarlog.Logger {
+	return l.withFields(fields)
+}
+func (l *pulsarLogAdapter) WithFields(fields pulsarlog.Fields) pulsarlog.Entry {
+	return l.withFields(fields)
+}
+func (l *pulsarLogAdapter) WithField(name string, value interface{}) pulsarlog.Entry {
+	return l.withFields(pulsarlog.Fields{name: value})
+}
+func (l *pulsarLogAdapter) WithError(err error) pulsarlog.Entry {
+	return l.withFields(pulsarlog.Fields{"error": err})
+}
+func (l *pulsarLogAdapter) Debug(args ...interface{}) {
+	if log.V(2) {
+		log.InfofDepth(l.ctx, 1, "", args...)
+	}
+}
+func (l *pulsarLogAdapter) Info(args ...interface{}) {
+	log.InfofDepth(l.ctx, 1, "", args...)
+}
+func (l *pulsarLogAdapter) Warn(args ...interface{}) {
+	log.WarningfDepth(l.ctx, 1, "", args...)
+}
+func (l *pulsarLogAdapter) Error(args ...interface{}) {
+	log.ErrorfDepth(l.ctx, 1, "", args...)
+}
+func (l *pulsarLogAdapter) Debugf(format string, args ...interface{}) {
+	if log.V(2) {
+		log.InfofDepth(l.ctx, 1, format, args...)
+	}
+}
+func (l *pulsarLogAdapter) Infof(format string, args ...interface{}) {
+	log.InfofDepth(l.ctx, 1, format, args...)
+}
+func (l *pulsarLogAdapter) Warnf(format string, args ...interface{}) {
+	log.WarningfDepth(l.ctx, 1, format, args...)
+}
+func (l *pulsarLogAdapter) Errorf(format string, args ...interface{}) {
+	log.ErrorfDepth(l.ctx, 1, format, args...)
+}
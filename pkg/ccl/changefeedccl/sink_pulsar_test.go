@@ -0,0 +1,418 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// pulsarBrokerMock is an in-process stand-in for a pulsar cluster in which
+// every topic has the same number of partitions. Its producers route the
+// messages with a key to a partition by hashing the key, and keep the messages
+// of each partition in the order in which they were sent.
+//
+// Messages are acknowledged as soon as they are sent, unless the broker is
+// paused, in which case they are acknowledged by release.
+type pulsarBrokerMock struct {
+	numPartitions int
+
+	mu struct {
+		syncutil.Mutex
+		producers  []pulsar.ProducerOptions
+		partitions map[string][]*pulsar.ProducerMessage
+		paused     bool
+		pending    []func(error)
+		closed     bool
+	}
+}
+
+var _ pulsarClient = (*pulsarBrokerMock)(nil)
+
+func newPulsarBrokerMock(numPartitions int) *pulsarBrokerMock {
+	b := &pulsarBrokerMock{numPartitions: numPartitions}
+	b.mu.partitions = make(map[string][]*pulsar.ProducerMessage)
+	return b
+}
+
+func pulsarPartitionName(topic string, partition int) string {
+	return fmt.Sprintf("%s-partition-%d", topic, partition)
+}
+
+// CreateProducer implements the pulsarClient interface.
+func (b *pulsarBrokerMock) CreateProducer(opts pulsar.ProducerOptions) (pulsar.Producer, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mu.producers = append(b.mu.producers, opts)
+	return &pulsarProducerMock{broker: b, topic: opts.Topic}, nil
+}
+
+// TopicPartitions implements the pulsarClient interface.
+func (b *pulsarBrokerMock) TopicPartitions(topic string) ([]string, error) {
+	partitions := make([]string, b.numPartitions)
+	for i := range partitions {
+		partitions[i] = pulsarPartitionName(topic, i)
+	}
+	return partitions, nil
+}
+
+// Close implements the pulsarClient interface.
+func (b *pulsarBrokerMock) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mu.closed = true
+}
+
+func (b *pulsarBrokerMock) pause() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mu.paused = true
+}
+
+// release acknowledges the pending messages, failing them with err if it is
+// not nil, and resumes acknowledging messages as they are sent.
+func (b *pulsarBrokerMock) release(err error) {
+	b.mu.Lock()
+	pending := b.mu.pending
+	b.mu.pending = nil
+	b.mu.paused = false
+	b.mu.Unlock()
+	for _, ack := range pending {
+		ack(err)
+	}
+}
+
+func (b *pulsarBrokerMock) send(partition string, msg *pulsar.ProducerMessage, ack func(error)) {
+	b.mu.Lock()
+	b.mu.partitions[partition] = append(b.mu.partitions[partition], msg)
+	if b.mu.paused {
+		b.mu.pending = append(b.mu.pending, ack)
+		b.mu.Unlock()
+		return
+	}
+	b.mu.Unlock()
+	ack(nil)
+}
+
+// messages returns the messages sent to the partition.
+func (b *pulsarBrokerMock) messages(partition string) []*pulsar.ProducerMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*pulsar.ProducerMessage(nil), b.mu.partitions[partition]...)
+}
+
+func (b *pulsarBrokerMock) producers() []pulsar.ProducerOptions {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]pulsar.ProducerOptions(nil), b.mu.producers...)
+}
+
+type pulsarProducerMock struct {
+	broker *pulsarBrokerMock
+	topic  string
+	next   int
+}
+
+var _ pulsar.Producer = (*pulsarProducerMock)(nil)
+
+func (p *pulsarProducerMock) Topic() string         { return p.topic }
+func (p *pulsarProducerMock) Name() string          { return p.topic }
+func (p *pulsarProducerMock) LastSequenceID() int64 { return -1 }
+func (p *pulsarProducerMock) Flush() error          { return nil }
+func (p *pulsarProducerMock) Close()                { p.closed = true }
+
+func (p *pulsarProducerMock) Send(
+	context.Context, *pulsar.ProducerMessage,
+) (pulsar.MessageID, error) {
+	panic(`unimplemented`)
+}
+
+func (p *pulsarProducerMock) SendAsync(
+	_ context.Context,
+	msg *pulsar.ProducerMessage,
+	callback func(pulsar.MessageID, *pulsar.ProducerMessage, error),
+) {
+	partition := p.topic
+	if !strings.Contains(p.topic, "-partition-") {
+		// Keyless messages are spread in round-robin, like the default router
+		// of pulsar producers does.
+		idx := p.next % p.broker.numPartitions
+		p.next++
+		if msg.Key != "" {
+			h := fnv.New32a()
+			_, _ = h.Write([]byte(msg.Key))
+			idx = int(h.Sum32() % uint32(p.broker.numPartitions))
+		}
+		partition = pulsarPartitionName(p.topic, idx)
+	}
+	p.broker.send(partition, msg, func(err error) {
+		callback(nil /* MessageID */, msg, err)
+	})
+}
+
+func makeTestPulsarSink(
+	t testing.TB, sinkURI string, opts map[string]string, b *pulsarBrokerMock, targetNames ...string,
+) *pulsarSink {
+	u, err := url.Parse(sinkURI)
+	require.NoError(t, err)
+	s, err := makePulsarSink(
+		context.Background(), sinkURL{URL: u}, makeChangefeedTargets(targetNames...), opts, nil /* metrics */)
+	require.NoError(t, err)
+	sink := s.(*pulsarSink)
+	sink.client = b
+	return sink
+}
+
+func TestPulsarSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	b := newPulsarBrokerMock(4)
+	sink := makeTestPulsarSink(t, `pulsar://localhost:6650`, nil, b, `t`)
+
+	// No inflight
+	require.NoError(t, sink.Flush(ctx))
+
+	// Timeout
+	var pool testAllocPool
+	b.pause()
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[1]`), []byte(`v1`), zeroTS, zeroTS, pool.alloc()))
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	require.True(t, testutils.IsError(sink.Flush(timeoutCtx), `context deadline exceeded`))
+	b.release(nil)
+	require.NoError(t, sink.Flush(ctx))
+
+	// Error
+	b.pause()
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[2]`), []byte(`v2`), zeroTS, zeroTS, pool.alloc()))
+	b.release(errors.New(`boom`))
+	require.True(t, testutils.IsError(sink.Flush(ctx), `while sending message with key=\[2\].*boom`))
+
+	// Check simple success again after error
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[3]`), []byte(`v3`), zeroTS, zeroTS, pool.alloc()))
+	require.NoError(t, sink.Flush(ctx))
+	// At the end, all of the resources has been released
+	require.EqualValues(t, 0, pool.used())
+
+	require.NoError(t, sink.Close())
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	require.True(t, b.mu.closed)
+}
+
+func TestPulsarSinkOrdersMessagesByKey(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	const numPartitions = 4
+	b := newPulsarBrokerMock(numPartitions)
+	sink := makeTestPulsarSink(t, `pulsar://localhost:6650`, nil, b, `t`)
+	defer func() { require.NoError(t, sink.Close()) }()
+
+	const numKeys, numUpdates = 20, 10
+	for i := 0; i < numUpdates; i++ {
+		for k := 0; k < numKeys; k++ {
+			key := fmt.Sprintf(`[%d]`, k)
+			value := fmt.Sprintf(`%d`, i)
+			require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(key), []byte(value), zeroTS, zeroTS, zeroAlloc))
+		}
+	}
+	require.NoError(t, sink.Flush(ctx))
+
+	// Every key is in a single partition, with its updates in order.
+	partitionOfKey := make(map[string]string)
+	updatesOfKey := make(map[string][]string)
+	for p := 0; p < numPartitions; p++ {
+		partition := pulsarPartitionName(`t`, p)
+		for _, m := range b.messages(partition) {
+			if other, ok := partitionOfKey[m.Key]; ok {
+				require.Equal(t, other, partition, "key %s in several partitions", m.Key)
+			}
+			partitionOfKey[m.Key] = partition
+			updatesOfKey[m.Key] = append(updatesOfKey[m.Key], string(m.Payload))
+		}
+	}
+	require.Len(t, updatesOfKey, numKeys)
+	for key, updates := range updatesOfKey {
+		require.Len(t, updates, numUpdates, key)
+		for i, u := range updates {
+			require.Equal(t, fmt.Sprintf(`%d`, i), u, key)
+		}
+	}
+
+	// Resolved timestamps are sent to every partition, after the rows.
+	opts := map[string]string{
+		changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+		changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeWrapped),
+	}
+	enc, err := makeJSONEncoder(opts, makeChangefeedTargets(`t`), uuid.UUID{})
+	require.NoError(t, err)
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, enc, hlc.Timestamp{WallTime: 1}))
+	require.NoError(t, sink.Flush(ctx))
+	for p := 0; p < numPartitions; p++ {
+		msgs := b.messages(pulsarPartitionName(`t`, p))
+		require.NotEmpty(t, msgs)
+		last := msgs[len(msgs)-1]
+		require.Empty(t, last.Key)
+		require.Equal(t, `{"resolved":"1.0000000000"}`, string(last.Payload))
+	}
+}
+
+func TestPulsarSinkTopicNames(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name     string
+		sinkURI  string
+		targets  []string
+		expected []string
+	}{
+		{
+			name:     `default`,
+			sinkURI:  `pulsar://localhost:6650`,
+			targets:  []string{`t☃`, `u`},
+			expected: []string{`t_u2603_`, `u`},
+		},
+		{
+			name:     `prefix`,
+			sinkURI:  `pulsar://localhost:6650?topic_prefix=cdc_`,
+			targets:  []string{`t`, `u`},
+			expected: []string{`cdc_t`, `cdc_u`},
+		},
+		{
+			name:     `single name`,
+			sinkURI:  `pulsar://localhost:6650?topic_name=general`,
+			targets:  []string{`t`, `u`},
+			expected: []string{`general`, `general`},
+		},
+		{
+			name:     `tenant and namespace`,
+			sinkURI:  `pulsar://localhost:6650?tenant=crdb&namespace=cdc&topic_prefix=p_`,
+			targets:  []string{`t`},
+			expected: []string{`persistent://crdb/cdc/p_t`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := newPulsarBrokerMock(1)
+			sink := makeTestPulsarSink(t, tc.sinkURI, nil, b, tc.targets...)
+			defer func() { require.NoError(t, sink.Close()) }()
+
+			expected := make(map[string]int)
+			for i, target := range tc.targets {
+				require.NoError(t, sink.EmitRow(ctx, topic(target), []byte(`[1]`), nil, zeroTS, zeroTS, zeroAlloc))
+				expected[tc.expected[i]]++
+			}
+			require.NoError(t, sink.Flush(ctx))
+			for name, n := range expected {
+				require.Len(t, b.messages(pulsarPartitionName(name, 0)), n, name)
+			}
+		})
+	}
+}
+
+func TestPulsarSinkOptionParsing(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	makeSink := func(sinkURI string, opts map[string]string) (*pulsarSink, error) {
+		u, err := url.Parse(sinkURI)
+		require.NoError(t, err)
+		s, err := makePulsarSink(ctx, sinkURL{URL: u}, makeChangefeedTargets(`t`), opts, nil)
+		if err != nil {
+			return nil, err
+		}
+		return s.(*pulsarSink), nil
+	}
+
+	t.Run("auth token", func(t *testing.T) {
+		s, err := makeSink(`pulsar+ssl://localhost:6651?auth_token=secret`, nil)
+		require.NoError(t, err)
+		require.Equal(t, `pulsar+ssl://localhost:6651`, s.clientOpts.URL)
+		require.NotNil(t, s.clientOpts.Authentication)
+		require.True(t, s.clientOpts.TLSValidateHostname)
+	})
+	t.Run("insecure tls", func(t *testing.T) {
+		s, err := makeSink(`pulsar+ssl://localhost:6651?insecure_tls_skip_verify=true`, nil)
+		require.NoError(t, err)
+		require.True(t, s.clientOpts.TLSAllowInsecureConnection)
+		require.False(t, s.clientOpts.TLSValidateHostname)
+	})
+	for _, tc := range []struct {
+		sinkURI string
+		opts    map[string]string
+		err     string
+	}{
+		{`pulsar://localhost:6650?insecure_tls_skip_verify=true`, nil,
+			`insecure_tls_skip_verify requires a pulsar\+ssl sink`},
+		{`pulsar://localhost:6650?client_cert=Zm9v&client_key=Zm9v`, nil,
+			`client_cert requires a pulsar\+ssl sink`},
+		{`pulsar+ssl://localhost:6651?client_cert=Zm9v`, nil,
+			`client_cert requires client_key to be set`},
+		{`pulsar+ssl://localhost:6651?ca_cert=Zm9v`, nil,
+			`ca_cert is not supported by pulsar sinks`},
+		{`pulsar://localhost:6650?tenant=crdb`, nil,
+			`tenant and namespace must be set together`},
+		{`pulsar://localhost:6650?foo=bar`, nil,
+			`unknown pulsar sink query parameters: foo`},
+		{`pulsar://localhost:6650`, map[string]string{changefeedbase.OptPulsarSinkConfig: `{"Compression": "snappy"}`},
+			`invalid compression value "snappy"`},
+		{`pulsar://localhost:6650`, map[string]string{changefeedbase.OptPulsarSinkConfig: `{"Flush": {"Messages": -1}}`},
+			`must not be negative`},
+		{`pulsar://localhost:6650`, map[string]string{changefeedbase.OptPulsarSinkConfig: `{"Flush": `},
+			`failed to parse pulsar sink config`},
+	} {
+		t.Run(tc.err, func(t *testing.T) {
+			_, err := makeSink(tc.sinkURI, tc.opts)
+			require.True(t, testutils.IsError(err, tc.err), "expected %q, got %v", tc.err, err)
+		})
+	}
+
+	t.Run("batching and compression", func(t *testing.T) {
+		opts := map[string]string{changefeedbase.OptPulsarSinkConfig: `{
+			"Flush": {"Messages": 100, "Bytes": 1048576, "Frequency": "50ms"},
+			"Compression": "zstd"
+		}`}
+
+		b := newPulsarBrokerMock(1)
+		sink := makeTestPulsarSink(t, `pulsar://localhost:6650`, opts, b, `t`)
+		defer func() { require.NoError(t, sink.Close()) }()
+		require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[1]`), nil, zeroTS, zeroTS, zeroAlloc))
+
+		producers := b.producers()
+		require.Len(t, producers, 1)
+		require.Equal(t, `t`, producers[0].Topic)
+		require.Equal(t, uint(100), producers[0].BatchingMaxMessages)
+		require.Equal(t, uint(1<<20), producers[0].BatchingMaxSize)
+		require.Equal(t, 50*time.Millisecond, producers[0].BatchingMaxPublishDelay)
+		require.Equal(t, pulsar.ZSTD, producers[0].CompressionType)
+		require.Equal(t, pulsar.KeyBasedBatchBuilder, producers[0].BatcherBuilderType)
+	})
+}
@@ -0,0 +1,319 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+const defaultRedisPort = "6379"
+
+// redisSinkConfig is the configuration of the redis sink, which can be
+// overridden with the redis_sink_config option, e.g.
+// {"Flush": {"Messages": 100}, "Retry": {"Max": 5}, "Timeout": "30s", "MaxLen": 100000}.
+type redisSinkConfig struct {
+	streamSinkConfig
+	// MaxLen, if set, caps the length of the streams. The streams are trimmed
+	// approximately (MAXLEN ~), which lets redis remove whole nodes of entries.
+	MaxLen int64 `json:",omitempty"`
+}
+
+func getRedisSinkConfig(opts map[string]string) (redisSinkConfig, error) {
+	cfg := redisSinkConfig{streamSinkConfig: defaultStreamSinkConfig()}
+	if configStr, haveOverride := opts[changefeedbase.OptRedisSinkConfig]; haveOverride {
+		if err := json.Unmarshal([]byte(configStr), &cfg); err != nil {
+			return cfg, errors.Wrapf(err,
+				"failed to parse redis sink config; check %s option", changefeedbase.OptRedisSinkConfig)
+		}
+	}
+	err := cfg.Validate()
+	if err == nil && cfg.MaxLen < 0 {
+		err = errors.New("MaxLen must not be negative")
+	}
+	if err != nil {
+		return cfg, errors.Wrap(err, "invalid redis sink configuration")
+	}
+	return cfg, nil
+}
+
+// redisError is an error reply of the redis server.
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+// redisClient appends messages to redis streams with XADD commands. The
+// commands of a batch are pipelined: they are all sent before their replies
+// are read.
+type redisClient struct {
+	addr     string
+	tlsCfg   *tls.Config
+	user     string
+	password string
+	db       int
+	maxLen   int64
+	timeout  time.Duration
+
+	conn *streamConn
+}
+
+var _ streamClient = (*redisClient)(nil)
+
+// Dial implements the streamClient interface.
+func (c *redisClient) Dial(ctx context.Context) error {
+	conn, err := dialStreamConn(ctx, c.addr)
+	if err == nil {
+		if err = c.handshake(ctx, conn); err != nil {
+			_ = conn.Close()
+		}
+	}
+	if err != nil {
+		return pgerror.Wrapf(err, pgcode.CannotConnectNow, `connecting to redis: %s`, c.addr)
+	}
+	c.conn = conn
+	return nil
+}
+
+// handshake sets up TLS, authenticates and selects the database.
+func (c *redisClient) handshake(ctx context.Context, conn *streamConn) error {
+	if c.tlsCfg != nil {
+		if err := conn.startTLS(ctx, c.tlsCfg); err != nil {
+			return err
+		}
+	}
+	defer conn.watch(ctx, c.timeout)()
+
+	var cmds [][][]byte
+	if c.password != `` {
+		if c.user != `` {
+			cmds = append(cmds, [][]byte{[]byte(`AUTH`), []byte(c.user), []byte(c.password)})
+		} else {
+			cmds = append(cmds, [][]byte{[]byte(`AUTH`), []byte(c.password)})
+		}
+	}
+	if c.db != 0 {
+		cmds = append(cmds, [][]byte{[]byte(`SELECT`), []byte(strconv.Itoa(c.db))})
+	}
+	for _, cmd := range cmds {
+		writeRedisCommand(conn.w, cmd...)
+	}
+	if err := conn.w.Flush(); err != nil {
+		return err
+	}
+	for range cmds {
+		if _, err := readRedisReply(conn.r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Publish implements the streamClient interface.
+func (c *redisClient) Publish(
+	ctx context.Context, msgs []streamMessage,
+) (unacked []streamMessage, err error) {
+	if c.conn == nil {
+		if err := c.Dial(ctx); err != nil {
+			return msgs, err
+		}
+	}
+	conn := c.conn
+	defer conn.watch(ctx, c.timeout)()
+
+	var maxLen []byte
+	if c.maxLen > 0 {
+		maxLen = []byte(strconv.FormatInt(c.maxLen, 10))
+	}
+	args := make([][]byte, 0, 10)
+	for _, msg := range msgs {
+		args = append(args[:0], []byte(`XADD`), []byte(msg.topic))
+		if maxLen != nil {
+			args = append(args, []byte(`MAXLEN`), []byte(`~`), maxLen)
+		}
+		args = append(args, []byte(`*`))
+		if msg.key != nil {
+			args = append(args, []byte(`key`), msg.key)
+		}
+		args = append(args, []byte(`value`), msg.value)
+		writeRedisCommand(conn.w, args...)
+	}
+	if err := conn.w.Flush(); err != nil {
+		return msgs, c.connError(err)
+	}
+
+	// Read every reply to keep the connection usable, and return the first
+	// error reply along with the messages which weren't appended. The messages
+	// whose replies can't be read may or may not have been appended; they are
+	// considered unacknowledged.
+	var firstErr error
+	for i := range msgs {
+		if _, err := readRedisReply(conn.r); err != nil {
+			if !errors.HasType(err, redisError(``)) {
+				return append(unacked, msgs[i:]...), c.connError(err)
+			}
+			if firstErr == nil {
+				firstErr = errors.Wrapf(err, `appending to redis stream %s`, msgs[i].topic)
+			}
+			unacked = append(unacked, msgs[i])
+		}
+	}
+	return unacked, firstErr
+}
+
+// connError closes the connection after an I/O or protocol error, so that the
+// next batch is sent on a new connection.
+func (c *redisClient) connError(err error) error {
+	_ = c.conn.Close()
+	c.conn = nil
+	return errors.Wrap(err, `sending to redis`)
+}
+
+// Close implements the streamClient interface.
+func (c *redisClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// writeRedisCommand writes a command in the RESP protocol. Errors are returned
+// by the next flush of the writer.
+func writeRedisCommand(w *bufio.Writer, args ...[]byte) {
+	_, _ = w.WriteString(`*` + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		_, _ = w.WriteString(`$` + strconv.Itoa(len(arg)) + "\r\n")
+		_, _ = w.Write(arg)
+		_, _ = w.WriteString("\r\n")
+	}
+}
+
+// readRedisReply reads a reply in the RESP protocol. The elements of array
+// replies are read but not returned, since none of the commands we send need
+// them. Error replies are returned as a redisError.
+func readRedisReply(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return ``, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return ``, errors.Errorf(`malformed redis reply %q`, line)
+	}
+	line = line[:len(line)-2]
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return ``, redisError(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return ``, errors.Errorf(`malformed redis reply %q`, line)
+		}
+		if n < 0 {
+			return ``, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return ``, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return ``, errors.Errorf(`malformed redis reply %q`, line)
+		}
+		var firstErr error
+		for i := 0; i < n; i++ {
+			if _, err := readRedisReply(r); err != nil {
+				if !errors.HasType(err, redisError(``)) {
+					return ``, err
+				}
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		return ``, firstErr
+	default:
+		return ``, errors.Errorf(`unexpected redis reply %q`, line)
+	}
+}
+
+// makeRedisSink returns a sink appending the messages of each topic to the
+// redis stream named after it. The key and value of the messages are the
+// fields of the entries of the streams; resolved timestamps only have a value.
+func makeRedisSink(
+	ctx context.Context,
+	u sinkURL,
+	targets []jobspb.ChangefeedTargetSpecification,
+	opts map[string]string,
+	m *sliMetrics,
+) (Sink, error) {
+	topicPrefix := u.consumeParam(changefeedbase.SinkParamTopicPrefix)
+	topicName := u.consumeParam(changefeedbase.SinkParamTopicName)
+	tlsCfg, err := buildStreamSinkTLSConfig(u)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := getRedisSinkConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &redisClient{
+		addr:    u.Host,
+		tlsCfg:  tlsCfg,
+		maxLen:  cfg.MaxLen,
+		timeout: time.Duration(cfg.Timeout),
+	}
+	if u.Port() == `` {
+		client.addr = net.JoinHostPort(u.Hostname(), defaultRedisPort)
+	}
+	if u.User != nil {
+		client.user = u.User.Username()
+		client.password, _ = u.User.Password()
+		// A lone username is the password of the default user, as in the URLs
+		// accepted by redis-cli.
+		if client.password == `` {
+			client.user, client.password = ``, client.user
+		}
+	}
+	if db := strings.TrimPrefix(u.Path, `/`); db != `` {
+		if client.db, err = strconv.Atoi(db); err != nil || client.db < 0 {
+			return nil, errors.Errorf(`invalid redis database %q`, db)
+		}
+	}
+
+	topics, err := MakeTopicNamer(targets, WithPrefix(topicPrefix), WithSingleName(topicName))
+	if err != nil {
+		return nil, err
+	}
+
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown redis sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	return makeStreamSink(ctx, client, topics, cfg.streamSinkConfig, m, m.redisSinkMetrics()), nil
+}
@@ -0,0 +1,509 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// streamMessage is a message appended to a stream of a streaming service, such
+// as a redis stream or a NATS JetStream subject. mvcc is the MVCC timestamp of
+// the row, or the resolved timestamp for resolved messages.
+type streamMessage struct {
+	topic      string
+	key, value []byte
+	mvcc       hlc.Timestamp
+}
+
+// streamClient is a connection to a streaming service used by a streamSink.
+type streamClient interface {
+	// Dial connects to the service.
+	Dial(ctx context.Context) error
+	// Publish appends the messages to their streams, in order, and returns once
+	// all of them have been acknowledged by the service. If an error is
+	// returned, unacked holds, in order, the messages which the service did not
+	// acknowledge; the others were published. The client reconnects on the
+	// next call if the connection was broken.
+	Publish(ctx context.Context, msgs []streamMessage) (unacked []streamMessage, err error)
+	// Close closes the connection to the service.
+	Close() error
+}
+
+// streamSinkConfig is the part of the configuration of the redis and nats
+// sinks which is common to both.
+type streamSinkConfig struct {
+	// Flush.Messages is the maximum number of messages published together.
+	// Messages are published as soon as they are emitted, along with the ones
+	// already queued when there are any.
+	Flush struct {
+		Messages int `json:",omitempty"`
+	} `json:",omitempty"`
+	// Retry configures the retries of the messages the service failed to
+	// acknowledge.
+	Retry retryConfig `json:",omitempty"`
+	// Timeout is how long the service has to acknowledge a batch of messages.
+	Timeout jsonDuration `json:",omitempty"`
+}
+
+const (
+	defaultStreamSinkBatchSize = 256
+	defaultStreamSinkTimeout   = 10 * time.Second
+)
+
+func defaultStreamSinkConfig() streamSinkConfig {
+	var cfg streamSinkConfig
+	retryOpts := defaultRetryConfig()
+	cfg.Flush.Messages = defaultStreamSinkBatchSize
+	cfg.Retry.Max = jsonMaxRetries(retryOpts.MaxRetries)
+	cfg.Retry.Backoff = jsonDuration(retryOpts.InitialBackoff)
+	cfg.Timeout = jsonDuration(defaultStreamSinkTimeout)
+	return cfg
+}
+
+func (c streamSinkConfig) Validate() error {
+	if c.Flush.Messages < 0 || c.Retry.Max < 0 || c.Retry.Backoff < 0 {
+		return errors.New("Flush.Messages, Retry.Max and Retry.Backoff must not be negative")
+	}
+	if c.Timeout <= 0 {
+		return errors.New("Timeout must be positive")
+	}
+	return nil
+}
+
+func (c streamSinkConfig) retryOptions() retry.Options {
+	opts := defaultRetryConfig()
+	opts.MaxRetries = int(c.Retry.Max)
+	opts.InitialBackoff = time.Duration(c.Retry.Backoff)
+	return opts
+}
+
+// streamEvent is a message queued for publication by a streamSink, or a flush
+// request if flushDone is set.
+type streamEvent struct {
+	msg      streamMessage
+	resolved bool
+	alloc    kvevent.Alloc
+	emitTime time.Time
+
+	flushDone chan struct{}
+}
+
+// streamSink emits to a streaming service through a streamClient. The messages
+// are published by a single worker, in the order in which they are emitted,
+// so that the changes to a row are appended to its stream in order. Only the
+// messages the service failed to acknowledge are published again by retries,
+// so a retried message can be appended after the ones which followed it in its
+// batch. The memory of a row is released once the service has acknowledged its message, which
+// backpressures the changefeed when the service falls behind.
+type streamSink struct {
+	client    streamClient
+	topics    *TopicNamer
+	batchSize int
+	retryOpts retry.Options
+
+	metrics       *sliMetrics
+	streamMetrics streamSinkMetrics
+
+	eventCh     chan streamEvent
+	errCh       chan error
+	workerCtx   context.Context
+	exitWorker  func()
+	workerGroup ctxgroup.Group
+}
+
+var _ SinkWithTopics = (*streamSink)(nil)
+
+func makeStreamSink(
+	ctx context.Context,
+	client streamClient,
+	topics *TopicNamer,
+	cfg streamSinkConfig,
+	m *sliMetrics,
+	streamMetrics streamSinkMetrics,
+) *streamSink {
+	batchSize := cfg.Flush.Messages
+	if batchSize == 0 {
+		batchSize = defaultStreamSinkBatchSize
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &streamSink{
+		client:        client,
+		topics:        topics,
+		batchSize:     batchSize,
+		retryOpts:     cfg.retryOptions(),
+		metrics:       m,
+		streamMetrics: streamMetrics,
+		eventCh:       make(chan streamEvent, batchSize),
+		// errCh has buffer size 1: the worker exits after saving its error in
+		// the buffer.
+		errCh:      make(chan error, 1),
+		workerCtx:  ctx,
+		exitWorker: cancel,
+	}
+	s.workerGroup = ctxgroup.WithContext(ctx)
+	s.workerGroup.GoCtx(func(ctx context.Context) error {
+		s.workerLoop(ctx)
+		return nil
+	})
+	return s
+}
+
+// Dial implements the Sink interface.
+func (s *streamSink) Dial() error {
+	return s.client.Dial(s.workerCtx)
+}
+
+// EmitRow implements the Sink interface.
+func (s *streamSink) EmitRow(
+	ctx context.Context,
+	topicDescr TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	topic, err := s.topics.Name(topicDescr)
+	if err != nil {
+		return err
+	}
+	if err := s.enqueue(ctx, streamEvent{
+		msg:      streamMessage{topic: topic, key: key, value: value, mvcc: mvcc},
+		alloc:    alloc,
+		emitTime: timeutil.Now(),
+	}); err != nil {
+		return err
+	}
+	s.metrics.recordMessageSize(int64(len(key) + len(value)))
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *streamSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+
+	return s.topics.Each(func(topic string) error {
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+		if err != nil {
+			return err
+		}
+		return s.enqueue(ctx, streamEvent{
+			msg:      streamMessage{topic: topic, value: payload, mvcc: resolved},
+			resolved: true,
+		})
+	})
+}
+
+// Flush implements the Sink interface.
+func (s *streamSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	flushDone := make(chan struct{})
+	if err := s.enqueue(ctx, streamEvent{flushDone: flushDone}); err != nil {
+		return err
+	}
+
+	// Wait for flush completion -- or an error.
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-s.errCh:
+		return err
+	case <-flushDone:
+		return s.sinkError()
+	}
+}
+
+// Close implements the Sink interface.
+func (s *streamSink) Close() error {
+	s.exitWorker()
+	// Ignore errors here since we're closing the sink anyway.
+	_ = s.workerGroup.Wait()
+	return s.client.Close()
+}
+
+// Topics gives the names of all topics that have been initialized
+// and will receive resolved timestamps.
+func (s *streamSink) Topics() []string {
+	return s.topics.DisplayNamesSlice()
+}
+
+func (s *streamSink) enqueue(ctx context.Context, ev streamEvent) error {
+	// Check the worker context first in case the worker has exited, since the
+	// select below could still pick the send to the channel.
+	if err := s.workerCtx.Err(); err != nil {
+		return errors.CombineErrors(err, s.sinkError())
+	}
+	select {
+	case <-s.workerCtx.Done():
+		// Check again for an error in case it was sent since the last check,
+		// which is more helpful than "context canceled".
+		return errors.CombineErrors(s.workerCtx.Err(), s.sinkError())
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-s.errCh:
+		return err
+	case s.eventCh <- ev:
+		return nil
+	}
+}
+
+func (s *streamSink) sinkError() error {
+	select {
+	case err := <-s.errCh:
+		return err
+	default:
+		return nil
+	}
+}
+
+// exitWorkerWithError stops the worker before reporting the error, so that
+// the events enqueued after the error is seen are rejected.
+func (s *streamSink) exitWorkerWithError(err error) {
+	s.exitWorker()
+	select {
+	case s.errCh <- err:
+	default:
+	}
+}
+
+func (s *streamSink) workerLoop(ctx context.Context) {
+	batch := make([]streamEvent, 0, s.batchSize)
+	msgs := make([]streamMessage, 0, s.batchSize)
+	for {
+		batch = batch[:0]
+		var flushDone chan struct{}
+		add := func(ev streamEvent) {
+			if ev.flushDone != nil {
+				flushDone = ev.flushDone
+			} else {
+				batch = append(batch, ev)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-s.eventCh:
+			add(ev)
+		}
+		// Publish the events queued behind the first one along with it, up to
+		// a full batch. A flush request ends the batch, so that it's answered
+		// once everything emitted before it has been published.
+	gather:
+		for flushDone == nil && len(batch) < s.batchSize {
+			select {
+			case ev := <-s.eventCh:
+				add(ev)
+			default:
+				break gather
+			}
+		}
+
+		if len(batch) > 0 {
+			msgs = msgs[:0]
+			for _, ev := range batch {
+				msgs = append(msgs, ev.msg)
+			}
+			if err := s.publishWithRetries(ctx, msgs); err != nil {
+				s.exitWorkerWithError(err)
+				return
+			}
+			s.recordPublished(ctx, batch)
+		}
+		if flushDone != nil {
+			close(flushDone)
+		}
+	}
+}
+
+// publishWithRetries publishes the messages, retrying the ones which were not
+// acknowledged so that the acknowledged ones aren't duplicated.
+func (s *streamSink) publishWithRetries(ctx context.Context, msgs []streamMessage) error {
+	var err error
+	for r := retry.StartWithCtx(ctx, s.retryOpts); r.Next(); {
+		if err != nil {
+			s.streamMetrics.recordRetry()
+			if log.V(1) {
+				log.Infof(ctx, "retrying publication of %d messages after error: %v", len(msgs), err)
+			}
+		}
+		if msgs, err = s.client.Publish(ctx, msgs); err == nil {
+			return nil
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// recordPublished releases the resources of the published events and updates
+// the metrics.
+func (s *streamSink) recordPublished(ctx context.Context, batch []streamEvent) {
+	s.streamMetrics.recordPublished(len(batch))
+
+	var rows, bytes int
+	var alloc kvevent.Alloc
+	var mvcc hlc.Timestamp
+	var emitTime time.Time
+	for i := range batch {
+		ev := &batch[i]
+		if ev.resolved {
+			continue
+		}
+		if rows == 0 || ev.emitTime.Before(emitTime) {
+			emitTime = ev.emitTime
+		}
+		if rows == 0 || ev.msg.mvcc.Less(mvcc) {
+			mvcc = ev.msg.mvcc
+		}
+		rows++
+		bytes += len(ev.msg.key) + len(ev.msg.value)
+		alloc.Merge(&ev.alloc)
+	}
+	alloc.Release(ctx)
+	if rows > 0 {
+		s.metrics.recordEmittedBatch(emitTime, rows, mvcc, bytes, sinkDoesNotCompress)
+	}
+}
+
+// streamConn is a buffered connection to a streaming service.
+type streamConn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+const streamDialTimeout = 10 * time.Second
+
+func dialStreamConn(ctx context.Context, addr string) (*streamConn, error) {
+	d := net.Dialer{Timeout: streamDialTimeout}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamConn(conn), nil
+}
+
+func newStreamConn(conn net.Conn) *streamConn {
+	return &streamConn{Conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+}
+
+// startTLS upgrades the connection to TLS.
+func (c *streamConn) startTLS(ctx context.Context, cfg *tls.Config) error {
+	tlsConn := tls.Client(c.Conn, cfg)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return errors.Wrap(err, "TLS handshake")
+	}
+	*c = *newStreamConn(tlsConn)
+	return nil
+}
+
+// watch makes the pending and future reads and writes of the connection fail
+// once the context is canceled or the timeout elapses. The returned function
+// must be called to stop watching.
+func (c *streamConn) watch(ctx context.Context, timeout time.Duration) (stop func()) {
+	_ = c.SetDeadline(timeutil.Now().Add(timeout))
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		select {
+		case <-ctx.Done():
+			// A deadline in the past interrupts the blocked calls.
+			_ = c.SetDeadline(timeutil.Unix(1, 0))
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+		_ = c.SetDeadline(time.Time{})
+	}
+}
+
+// buildStreamSinkTLSConfig returns the TLS configuration given by the
+// parameters of the sink URL, or nil if TLS is not enabled.
+func buildStreamSinkTLSConfig(u sinkURL) (*tls.Config, error) {
+	dialConfig := struct {
+		tlsEnabled    bool
+		tlsSkipVerify bool
+		caCert        []byte
+		clientCert    []byte
+		clientKey     []byte
+	}{}
+	if _, err := u.consumeBool(changefeedbase.SinkParamTLSEnabled, &dialConfig.tlsEnabled); err != nil {
+		return nil, err
+	}
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &dialConfig.tlsSkipVerify); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &dialConfig.caCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientCert, &dialConfig.clientCert); err != nil {
+		return nil, err
+	}
+	if err := u.decodeBase64(changefeedbase.SinkParamClientKey, &dialConfig.clientKey); err != nil {
+		return nil, err
+	}
+
+	if !dialConfig.tlsEnabled {
+		if dialConfig.caCert != nil {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamCACert, changefeedbase.SinkParamTLSEnabled)
+		}
+		if dialConfig.clientCert != nil {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamTLSEnabled)
+		}
+		if dialConfig.tlsSkipVerify {
+			return nil, errors.Errorf(`%s requires %s=true`, changefeedbase.SinkParamSkipTLSVerify, changefeedbase.SinkParamTLSEnabled)
+		}
+		return nil, nil
+	}
+
+	cfg := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: dialConfig.tlsSkipVerify,
+	}
+	if dialConfig.caCert != nil {
+		caCertPool := x509.NewCertPool()
+		caCertPool.AppendCertsFromPEM(dialConfig.caCert)
+		cfg.RootCAs = caCertPool
+	}
+	if dialConfig.clientCert != nil && dialConfig.clientKey == nil {
+		return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientCert, changefeedbase.SinkParamClientKey)
+	} else if dialConfig.clientKey != nil && dialConfig.clientCert == nil {
+		return nil, errors.Errorf(`%s requires %s to be set`, changefeedbase.SinkParamClientKey, changefeedbase.SinkParamClientCert)
+	}
+	if dialConfig.clientCert != nil {
+		cert, err := tls.X509KeyPair(dialConfig.clientCert, dialConfig.clientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, `invalid client certificate data provided`)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
@@ -42,9 +42,14 @@ type asyncProducerMock struct {
 	inputCh     chan *sarama.ProducerMessage
 	successesCh chan *sarama.ProducerMessage
 	errorsCh    chan *sarama.ProducerError
-	mu          struct {
+	// transactional makes the mock behave as a transactional producer.
+	transactional bool
+	mu            struct {
 		syncutil.Mutex
 		outstanding []*sarama.ProducerMessage
+		inTxn       bool
+		committed   int
+		aborted     int
 	}
 }
 
@@ -62,6 +67,61 @@ func (p *asyncProducerMock) Input() chan<- *sarama.ProducerMessage     { return
 func (p *asyncProducerMock) Successes() <-chan *sarama.ProducerMessage { return p.successesCh }
 func (p *asyncProducerMock) Errors() <-chan *sarama.ProducerError      { return p.errorsCh }
 func (p *asyncProducerMock) AsyncClose()                               { panic(`unimplemented`) }
+func (p *asyncProducerMock) IsTransactional() bool                     { return p.transactional }
+func (p *asyncProducerMock) AddOffsetsToTxn(
+	map[string][]*sarama.PartitionOffsetMetadata, string,
+) error {
+	return sarama.ErrNonTransactedProducer
+}
+func (p *asyncProducerMock) AddMessageToTxn(*sarama.ConsumerMessage, string, *string) error {
+	return sarama.ErrNonTransactedProducer
+}
+
+func (p *asyncProducerMock) TxnStatus() sarama.ProducerTxnStatusFlag {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.mu.inTxn {
+		return sarama.ProducerTxnFlagInTransaction
+	}
+	return sarama.ProducerTxnFlagReady
+}
+
+func (p *asyncProducerMock) BeginTxn() error {
+	if !p.transactional {
+		return sarama.ErrNonTransactedProducer
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.mu.inTxn = true
+	return nil
+}
+
+func (p *asyncProducerMock) CommitTxn() error {
+	return p.endTxn(&p.mu.committed)
+}
+
+func (p *asyncProducerMock) AbortTxn() error {
+	return p.endTxn(&p.mu.aborted)
+}
+
+func (p *asyncProducerMock) endTxn(count *int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.mu.inTxn {
+		return sarama.ErrTransactionNotReady
+	}
+	p.mu.inTxn = false
+	*count++
+	return nil
+}
+
+// txnCounts returns the number of committed and aborted transactions.
+func (p *asyncProducerMock) txnCounts() (committed, aborted int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.mu.committed, p.mu.aborted
+}
+
 func (p *asyncProducerMock) Close() error {
 	close(p.inputCh)
 	close(p.successesCh)
@@ -319,6 +379,72 @@ func TestKafkaSinkEscaping(t *testing.T) {
 	require.Equal(t, sarama.ByteEncoder(`v☃`), m.Value)
 }
 
+func TestKafkaSinkTransactional(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	p := newAsyncProducerMock(1)
+	p.transactional = true
+	sink, cleanup := makeTestKafkaSink(
+		t, noTopicPrefix, defaultTopicName, p, "t")
+
+	// No transaction is started until a message is emitted.
+	require.NoError(t, sink.FlushForCheckpoint(ctx))
+	committed, aborted := p.txnCounts()
+	require.Equal(t, 0, committed)
+	require.Equal(t, 0, aborted)
+
+	// The messages emitted between checkpoints are committed together. Flushing
+	// the sink does not end the transaction.
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`1`), nil, zeroTS, zeroTS, zeroAlloc))
+	m1 := <-p.inputCh
+	go func() { p.successesCh <- m1 }()
+	require.NoError(t, sink.Flush(ctx))
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`2`), nil, zeroTS, zeroTS, zeroAlloc))
+	m2 := <-p.inputCh
+	require.Equal(t, sarama.ProducerTxnFlagInTransaction, p.TxnStatus())
+	go func() { p.successesCh <- m2 }()
+	require.NoError(t, sink.FlushForCheckpoint(ctx))
+	committed, aborted = p.txnCounts()
+	require.Equal(t, 1, committed)
+	require.Equal(t, 0, aborted)
+
+	// A flush which times out leaves the transaction open.
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`3`), nil, zeroTS, zeroTS, zeroAlloc))
+	m3 := <-p.inputCh
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	require.True(t, testutils.IsError(sink.FlushForCheckpoint(timeoutCtx), `context deadline exceeded`))
+	require.Equal(t, sarama.ProducerTxnFlagInTransaction, p.TxnStatus())
+
+	// A failed message aborts the transaction.
+	go func() {
+		p.errorsCh <- &sarama.ProducerError{Msg: m3, Err: errors.New("m3")}
+	}()
+	require.True(t, testutils.IsError(sink.FlushForCheckpoint(ctx), `m3`))
+	committed, aborted = p.txnCounts()
+	require.Equal(t, 1, committed)
+	require.Equal(t, 1, aborted)
+
+	// A new transaction is started after the abort.
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`4`), nil, zeroTS, zeroTS, zeroAlloc))
+	m4 := <-p.inputCh
+	go func() { p.successesCh <- m4 }()
+	require.NoError(t, sink.FlushForCheckpoint(ctx))
+	committed, aborted = p.txnCounts()
+	require.Equal(t, 2, committed)
+	require.Equal(t, 1, aborted)
+
+	// Closing the sink aborts the open transaction.
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`5`), nil, zeroTS, zeroTS, zeroAlloc))
+	<-p.inputCh
+	cleanup()
+	committed, aborted = p.txnCounts()
+	require.Equal(t, 2, committed)
+	require.Equal(t, 2, aborted)
+}
+
 func TestKafkaTopicNameProvided(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -651,6 +777,67 @@ func TestSaramaConfigOptionParsing(t *testing.T) {
 		require.Error(t, err)
 
 	})
+	t.Run("apply configures idempotent producer", func(t *testing.T) {
+		opts := make(map[string]string)
+		opts[changefeedbase.OptKafkaSinkConfig] = `{"Idempotent": true}`
+
+		cfg, err := getSaramaConfig(opts)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate())
+
+		saramaCfg := sarama.NewConfig()
+		err = cfg.Apply(saramaCfg)
+		require.NoError(t, err)
+		require.True(t, saramaCfg.Producer.Idempotent)
+		require.Equal(t, sarama.WaitForAll, saramaCfg.Producer.RequiredAcks)
+		require.Equal(t, 1, saramaCfg.Net.MaxOpenRequests)
+		require.NoError(t, saramaCfg.Validate())
+	})
+	t.Run("apply configures transactional producer", func(t *testing.T) {
+		opts := make(map[string]string)
+		opts[changefeedbase.OptKafkaSinkConfig] = `{"Transactional": true}`
+
+		cfg, err := getSaramaConfig(opts)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate())
+
+		saramaCfg := sarama.NewConfig()
+		err = cfg.Apply(saramaCfg)
+		require.NoError(t, err)
+		require.True(t, saramaCfg.Producer.Idempotent)
+		require.Equal(t, kafkaTransactionalIDPrefix, saramaCfg.Producer.Transaction.ID)
+		require.Equal(t, sarama.WaitForAll, saramaCfg.Producer.RequiredAcks)
+		require.Equal(t, 1, saramaCfg.Net.MaxOpenRequests)
+		require.NoError(t, saramaCfg.Validate())
+
+		// The transactional ID of a sink is derived from the job and the
+		// partition of the changefeed it emits.
+		u, err := url.Parse(`kafka://localhost:9092`)
+		require.NoError(t, err)
+		sink, err := makeKafkaSink(context.Background(), sinkURL{URL: u},
+			makeChangefeedTargets("t"), opts, jobspb.JobID(123), "abc", nil /* metrics */)
+		require.NoError(t, err)
+		require.Equal(t, kafkaTransactionalIDPrefix+"-123-abc",
+			sink.(*kafkaSink).kafkaCfg.Producer.Transaction.ID)
+
+		opts[changefeedbase.OptKafkaSinkConfig] = `{"Transactional": true, "RequiredAcks": "ONE"}`
+		cfg, err = getSaramaConfig(opts)
+		require.NoError(t, err)
+		require.Error(t, cfg.Validate())
+	})
+	t.Run("validate returns error for idempotent producer without acks from all", func(t *testing.T) {
+		opts := make(map[string]string)
+		opts[changefeedbase.OptKafkaSinkConfig] = `{"Idempotent": true, "RequiredAcks": "ONE"}`
+
+		cfg, err := getSaramaConfig(opts)
+		require.NoError(t, err)
+		require.Error(t, cfg.Validate())
+
+		opts[changefeedbase.OptKafkaSinkConfig] = `{"Idempotent": true, "RequiredAcks": "ALL"}`
+		cfg, err = getSaramaConfig(opts)
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate())
+	})
 }
 
 func TestKafkaSinkTracksMemory(t *testing.T) {
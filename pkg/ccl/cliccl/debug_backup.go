@@ -122,6 +122,8 @@ var debugBackupArgs struct {
 	startKey        key
 	withRevisions   bool
 
+	validate bool
+
 	rowCount int
 }
 
@@ -139,6 +141,7 @@ func setDebugContextDefault() {
 	debugBackupArgs.startKey = key{}
 	debugBackupArgs.rowCount = 0
 	debugBackupArgs.withRevisions = false
+	debugBackupArgs.validate = false
 }
 
 func init() {
@@ -175,6 +178,18 @@ func init() {
 		RunE:  clierrorplus.MaybeDecorateError(runExportDataCmd),
 	}
 
+	verifyCmd := &cobra.Command{
+		Use:   "verify <backup_path>",
+		Short: "verify backup files",
+		Long: `
+Checks that every file referenced by the manifest of a SQL backup exists and
+can be read, without restoring the backup. Encrypted and locality aware
+backups are not supported; use SHOW BACKUP ... WITH check_files for them.
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: clierrorplus.MaybeDecorateError(runVerifyCmd),
+	}
+
 	backupCmds := &cobra.Command{
 		Use:   "backup [command]",
 		Short: "debug backups",
@@ -254,11 +269,18 @@ func init() {
 		"", /*value*/
 		cliflags.ExportRevisionsUpTo.Usage())
 
+	verifyCmd.Flags().BoolVar(
+		&debugBackupArgs.validate,
+		cliflags.ValidateBackupFiles.Name,
+		false, /*value*/
+		cliflags.ValidateBackupFiles.Usage())
+
 	backupSubCmds := []*cobra.Command{
 		showCmd,
 		listBackupsCmd,
 		listIncrementalCmd,
 		exportDataCmd,
+		verifyCmd,
 	}
 
 	for _, cmd := range backupSubCmds {
@@ -323,6 +345,42 @@ func runShowCmd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runVerifyCmd(cmd *cobra.Command, args []string) error {
+
+	path := args[0]
+	if !strings.Contains(path, "://") {
+		path = nodelocal.MakeLocalStorageURI(path)
+	}
+	ctx := context.Background()
+	desc, err := getManifestFromURI(ctx, path)
+	if err != nil {
+		return errors.Wrapf(err, "fetching backup manifest")
+	}
+
+	cols := []string{"path", "file_bytes"}
+	align := "lr"
+	if debugBackupArgs.validate {
+		cols = append(cols, "keys")
+		align += "r"
+	}
+	rows := make([][]string, 0)
+	addRow := func(check backupccl.BackupFileCheck) error {
+		row := []string{check.Path, strconv.FormatInt(check.FileBytes, 10)}
+		if debugBackupArgs.validate {
+			row = append(row, strconv.FormatInt(check.Keys, 10))
+		}
+		rows = append(rows, row)
+		return nil
+	}
+	if err := backupccl.CheckBackupFiles(ctx, externalStorageFromURIFactory,
+		security.RootUserName(), []string{path}, []backupccl.BackupManifest{desc},
+		nil /* localityInfo */, nil /* enc */, debugBackupArgs.validate, addRow); err != nil {
+		return errors.Wrapf(err, "verifying backup")
+	}
+	rowSliceIter := clisqlexec.NewRowSliceIter(rows, align)
+	return cli.PrintQueryOutput(os.Stdout, cols, rowSliceIter)
+}
+
 func runListBackupsCmd(cmd *cobra.Command, args []string) error {
 
 	path := args[0]
@@ -777,7 +835,7 @@ func (b backupMetaDisplayMsg) MarshalJSON() ([]byte, error) {
 	for i := range b.Descriptors {
 		d := &b.Descriptors[i]
 		id := descpb.GetDescriptorID(d)
-		tableDesc, databaseDesc, typeDesc, schemaDesc := descpb.FromDescriptor(d)
+		tableDesc, databaseDesc, typeDesc, schemaDesc, _ := descpb.FromDescriptor(d)
 		if databaseDesc != nil {
 			dbIDToName[id] = descpb.GetDescriptorName(d)
 		} else if schemaDesc != nil {
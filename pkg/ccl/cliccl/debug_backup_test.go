@@ -14,6 +14,8 @@ import (
 	gojson "encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -281,6 +283,68 @@ func TestListIncremental(t *testing.T) {
 	checkExpectedOutput(t, buf.String(), out)
 }
 
+func TestVerify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	c := cli.NewCLITest(cli.TestCLIParams{T: t, NoServer: true})
+	defer c.Cleanup()
+
+	ctx := context.Background()
+	dir, cleanFn := testutils.TempDir(t)
+	defer cleanFn()
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir, Insecure: true})
+	defer srv.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE DATABASE testDB`)
+	sqlDB.Exec(t, `USE testDB`)
+	sqlDB.Exec(t, `CREATE TABLE fooTable (id INT PRIMARY KEY, value INT)`)
+	sqlDB.Exec(t, `INSERT INTO fooTable VALUES (1, 123), (2, 223)`)
+	const backupPath = "nodelocal://0/fooFolder"
+	sqlDB.Exec(t, `BACKUP TABLE fooTable TO $1`, backupPath)
+
+	files := sqlDB.QueryStr(t, `SELECT DISTINCT path FROM [SHOW BACKUP FILES $1]`, backupPath)
+	require.Len(t, files, 1)
+	sstPath := filepath.Join(dir, "fooFolder", files[0][0])
+	stat, err := os.Stat(sstPath)
+	require.NoError(t, err)
+
+	for _, validate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("validate=%t", validate), func(t *testing.T) {
+			setDebugContextDefault()
+			out, err := c.RunWithCapture(fmt.Sprintf("debug backup verify %s --external-io-dir=%s --validate=%t",
+				backupPath, dir, validate))
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			cols := []string{"path", "file_bytes"}
+			row := []string{files[0][0], strconv.FormatInt(stat.Size(), 10)}
+			align := "lr"
+			if validate {
+				cols = append(cols, "keys")
+				row = append(row, "2")
+				align += "r"
+			}
+			rowSliceIter := clisqlexec.NewRowSliceIter([][]string{row}, align)
+			if err := cli.PrintQueryOutput(&buf, cols, rowSliceIter); err != nil {
+				t.Fatalf("TestVerify: PrintQueryOutput: %v", err)
+			}
+			checkExpectedOutput(t, buf.String(), out)
+		})
+	}
+
+	t.Run("missing-file", func(t *testing.T) {
+		require.NoError(t, os.Remove(sstPath))
+		setDebugContextDefault()
+		out, err := c.RunWithCapture(fmt.Sprintf("debug backup verify %s --external-io-dir=%s",
+			backupPath, dir))
+		require.NoError(t, err)
+		require.Contains(t, out, fmt.Sprintf(
+			"ERROR: verifying backup: checking backup file %s in %s: file is missing", files[0][0], backupPath))
+	})
+}
+
 func TestExportData(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -1636,6 +1636,13 @@ The bytekey format does not require table-key prefix.`,
 		Description: `Export revisions of data from a backup table up to a specific timestamp.`,
 	}
 
+	ValidateBackupFiles = FlagInfo{
+		Name: "validate",
+		Description: `
+Read every key of the backup files and check them against the sizes recorded
+in the backup manifest, rather than only checking that the files can be opened.`,
+	}
+
 	Recursive = FlagInfo{
 		Name:      "recursive",
 		Shorthand: "r",
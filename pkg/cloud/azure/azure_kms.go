@@ -0,0 +1,231 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package azure
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/keyvault/v7.0/keyvault"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/errors"
+)
+
+const azureKMSScheme = "azure-kms"
+
+const (
+	// AzureVaultNameParam is the query parameter for the name of the Key Vault
+	// holding the key in an azure-kms URI.
+	AzureVaultNameParam = "AZURE_VAULT_NAME"
+	// AzureClientIDParam is the query parameter for the client (application) ID
+	// of the service principal used to authenticate with Azure.
+	AzureClientIDParam = "AZURE_CLIENT_ID"
+	// AzureClientSecretParam is the query parameter for the client secret of the
+	// service principal used to authenticate with Azure.
+	AzureClientSecretParam = "AZURE_CLIENT_SECRET"
+	// AzureTenantIDParam is the query parameter for the tenant (directory) ID of
+	// the service principal used to authenticate with Azure.
+	AzureTenantIDParam = "AZURE_TENANT_ID"
+	// AzureEnvironmentParam is the query parameter for the name of the Azure
+	// cloud environment, e.g. AzureUSGovernmentCloud. It defaults to
+	// AzurePublicCloud.
+	AzureEnvironmentParam = "AZURE_ENVIRONMENT"
+)
+
+type azureKMS struct {
+	client     keyvault.BaseClient
+	vaultURL   string
+	keyName    string
+	keyVersion string
+}
+
+var _ cloud.KMS = &azureKMS{}
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(MakeAzureKMS, azureKMSScheme)
+}
+
+type kmsURIParams struct {
+	vaultName    string
+	clientID     string
+	clientSecret string
+	tenantID     string
+	environment  string
+	auth         string
+}
+
+func resolveKMSURIParams(kmsURI url.URL) kmsURIParams {
+	params := kmsURIParams{
+		vaultName:    kmsURI.Query().Get(AzureVaultNameParam),
+		clientID:     kmsURI.Query().Get(AzureClientIDParam),
+		clientSecret: kmsURI.Query().Get(AzureClientSecretParam),
+		tenantID:     kmsURI.Query().Get(AzureTenantIDParam),
+		environment:  kmsURI.Query().Get(AzureEnvironmentParam),
+		auth:         kmsURI.Query().Get(cloud.AuthParam),
+	}
+	if params.environment == "" {
+		params.environment = azure.PublicCloud.Name
+	}
+	return params
+}
+
+// parseKeyPath extracts the key name and key version from the path of an
+// azure-kms URI, which is of the form /{key name}/{key version}.
+func parseKeyPath(path string) (keyName string, keyVersion string, _ error) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf(
+			"azure-kms URI path must be of the form /<key name>/<key version>, got %q", path)
+	}
+	return parts[0], parts[1], nil
+}
+
+// MakeAzureKMS is the factory method which returns a configured, ready-to-use
+// Azure Key Vault KMS object. The key must be an RSA key of the vault named
+// by the URI, and both its name and version have to be specified so that data
+// encrypted with it can still be decrypted once the key has been rotated.
+func MakeAzureKMS(uri string, env cloud.KMSEnv) (cloud.KMS, error) {
+	if env.KMSConfig().DisableOutbound {
+		return nil, errors.New("external IO must be enabled to use Azure KMS")
+	}
+	kmsURI, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract the URI parameters required to setup the Key Vault client.
+	kmsURIParams := resolveKMSURIParams(*kmsURI)
+	if kmsURIParams.vaultName == "" {
+		return nil, errors.Errorf("azure-kms URI missing %q parameter", AzureVaultNameParam)
+	}
+	keyName, keyVersion, err := parseKeyPath(kmsURI.Path)
+	if err != nil {
+		return nil, err
+	}
+	azureEnv, err := azure.EnvironmentFromName(kmsURIParams.environment)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unsupported value %s for %s",
+			kmsURIParams.environment, AzureEnvironmentParam)
+	}
+	// Tokens for Key Vault must be requested for the Key Vault resource rather
+	// than for the resource manager.
+	resource := strings.TrimSuffix(azureEnv.KeyVaultEndpoint, "/")
+
+	var authorizer autorest.Authorizer
+	switch kmsURIParams.auth {
+	case "", cloud.AuthParamSpecified:
+		for _, p := range []struct{ param, value string }{
+			{AzureClientIDParam, kmsURIParams.clientID},
+			{AzureClientSecretParam, kmsURIParams.clientSecret},
+			{AzureTenantIDParam, kmsURIParams.tenantID},
+		} {
+			if p.value == "" {
+				return nil, errors.Errorf(
+					"%s is set to '%s', but %s is not set",
+					cloud.AuthParam,
+					cloud.AuthParamSpecified,
+					p.param,
+				)
+			}
+		}
+		creds := auth.NewClientCredentialsConfig(
+			kmsURIParams.clientID, kmsURIParams.clientSecret, kmsURIParams.tenantID)
+		creds.AADEndpoint = azureEnv.ActiveDirectoryEndpoint
+		creds.Resource = resource
+		authorizer, err = creds.Authorizer()
+		if err != nil {
+			return nil, err
+		}
+	case cloud.AuthParamImplicit:
+		if env.KMSConfig().DisableImplicitCredentials {
+			return nil, errors.New(
+				"implicit credentials disallowed for azure due to --external-io-implicit-credentials flag")
+		}
+		// Implicit credentials are resolved from the AZURE_* environment
+		// variables of the node, falling back to its managed identity.
+		authorizer, err = auth.NewAuthorizerFromEnvironmentWithResource(resource)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported value %s for %s", kmsURIParams.auth, cloud.AuthParam)
+	}
+
+	vaultURL := fmt.Sprintf("https://%s.%s", kmsURIParams.vaultName, azureEnv.KeyVaultDNSSuffix)
+	return newAzureKMS(vaultURL, keyName, keyVersion, authorizer), nil
+}
+
+func newAzureKMS(
+	vaultURL, keyName, keyVersion string, authorizer autorest.Authorizer,
+) *azureKMS {
+	client := keyvault.New()
+	client.Authorizer = authorizer
+	return &azureKMS{
+		client:     client,
+		vaultURL:   vaultURL,
+		keyName:    keyName,
+		keyVersion: keyVersion,
+	}
+}
+
+// MasterKeyID implements the KMS interface. It returns the key identifier used
+// by Key Vault, i.e. {vault URL}/keys/{key name}/{key version}.
+func (k *azureKMS) MasterKeyID() (string, error) {
+	return fmt.Sprintf("%s/keys/%s/%s", k.vaultURL, k.keyName, k.keyVersion), nil
+}
+
+// Encrypt implements the KMS interface.
+func (k *azureKMS) Encrypt(ctx context.Context, data []byte) ([]byte, error) {
+	res, err := k.client.Encrypt(ctx, k.vaultURL, k.keyName, k.keyVersion,
+		keyvault.KeyOperationsParameters{
+			Algorithm: keyvault.RSAOAEP256,
+			Value:     to.StringPtr(base64.RawURLEncoding.EncodeToString(data)),
+		})
+	if err != nil {
+		return nil, err
+	}
+	return decodeKeyOperationResult(res)
+}
+
+// Decrypt implements the KMS interface.
+func (k *azureKMS) Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	res, err := k.client.Decrypt(ctx, k.vaultURL, k.keyName, k.keyVersion,
+		keyvault.KeyOperationsParameters{
+			Algorithm: keyvault.RSAOAEP256,
+			Value:     to.StringPtr(base64.RawURLEncoding.EncodeToString(data)),
+		})
+	if err != nil {
+		return nil, err
+	}
+	return decodeKeyOperationResult(res)
+}
+
+// decodeKeyOperationResult returns the bytes of the base64url encoded result
+// of a Key Vault key operation.
+func decodeKeyOperationResult(res keyvault.KeyOperationResult) ([]byte, error) {
+	if res.Result == nil {
+		return nil, errors.New("azure key vault returned an empty result")
+	}
+	// Key Vault omits the padding of base64url encoded values.
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(*res.Result, "="))
+}
+
+// Close implements the KMS interface.
+func (k *azureKMS) Close() error {
+	return nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package azure
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+// localAzureKMSScheme is the scheme of the KMS URIs pointing at a local
+// stand-in for Key Vault, of the form
+// azure-kms-local://{host:port}/{key name}/{key version}.
+const localAzureKMSScheme = "azure-kms-local"
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(makeLocalAzureKMS, localAzureKMSScheme)
+}
+
+func makeLocalAzureKMS(uri string, _ cloud.KMSEnv) (cloud.KMS, error) {
+	kmsURI, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	keyName, keyVersion, err := parseKeyPath(kmsURI.Path)
+	if err != nil {
+		return nil, err
+	}
+	return newAzureKMS("http://"+kmsURI.Host, keyName, keyVersion, autorest.NullAuthorizer{}), nil
+}
+
+// newLocalKeyVault returns a server implementing the encrypt and decrypt
+// operations of the Key Vault API for a single RSA key.
+func newLocalKeyVault(t *testing.T, keyName, keyVersion string) *httptest.Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Alg   string `json:"alg"`
+			Value string `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Alg != "RSA-OAEP-256" {
+			http.Error(w, "unsupported algorithm "+req.Alg, http.StatusBadRequest)
+			return
+		}
+		in, err := base64.RawURLEncoding.DecodeString(req.Value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var out []byte
+		prefix := fmt.Sprintf("/keys/%s/%s/", keyName, keyVersion)
+		switch op := strings.TrimPrefix(r.URL.Path, prefix); {
+		case !strings.HasPrefix(r.URL.Path, prefix):
+			http.Error(w, "key not found", http.StatusNotFound)
+			return
+		case op == "encrypt":
+			out, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, &key.PublicKey, in, nil)
+		case op == "decrypt":
+			out, err = rsa.DecryptOAEP(sha256.New(), rand.Reader, key, in, nil)
+		default:
+			http.Error(w, "unsupported operation "+op, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"kid":   "http://" + r.Host + strings.TrimSuffix(prefix, "/"),
+			"value": base64.RawURLEncoding.EncodeToString(out),
+		})
+	}))
+}
+
+func TestEncryptDecryptAzureLocal(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	srv := newLocalKeyVault(t, "backup-key", "0123456789abcdef")
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	cloud.KMSEncryptDecrypt(t,
+		fmt.Sprintf("%s://%s/backup-key/0123456789abcdef", localAzureKMSScheme, host),
+		cloud.TestKMSEnv{
+			Settings:         cluster.NoSettings,
+			ExternalIOConfig: &base.ExternalIODirConfig{},
+		})
+
+	t.Run("wrong-key", func(t *testing.T) {
+		kms, err := cloud.KMSFromURI(
+			fmt.Sprintf("%s://%s/other-key/0123456789abcdef", localAzureKMSScheme, host),
+			&cloud.TestKMSEnv{ExternalIOConfig: &base.ExternalIODirConfig{}})
+		require.NoError(t, err)
+		_, err = kms.Encrypt(context.Background(), []byte("hello world"))
+		require.Error(t, err)
+	})
+}
+
+func TestEncryptDecryptAzure(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	q := make(url.Values)
+	for _, param := range []string{
+		AzureVaultNameParam, AzureClientIDParam, AzureClientSecretParam, AzureTenantIDParam,
+	} {
+		v := os.Getenv(param)
+		if v == "" {
+			skip.IgnoreLintf(t, "%s env var must be set", param)
+		}
+		q.Add(param, v)
+	}
+
+	// The key is identified by its name and version, in the format
+	// {key name}/{key version}.
+	keyID := os.Getenv("AZURE_KMS_KEY_ID")
+	if keyID == "" {
+		skip.IgnoreLint(t, "AZURE_KMS_KEY_ID env var must be set")
+	}
+
+	t.Run("auth-implicit", func(t *testing.T) {
+		params := make(url.Values)
+		params.Add(cloud.AuthParam, cloud.AuthParamImplicit)
+		params.Add(AzureVaultNameParam, q.Get(AzureVaultNameParam))
+
+		uri := fmt.Sprintf("azure-kms:///%s?%s", keyID, params.Encode())
+		cloud.KMSEncryptDecrypt(t, uri, cloud.TestKMSEnv{
+			Settings:         cluster.NoSettings,
+			ExternalIOConfig: &base.ExternalIODirConfig{},
+		})
+	})
+
+	t.Run("auth-specified", func(t *testing.T) {
+		q.Set(cloud.AuthParam, cloud.AuthParamSpecified)
+		uri := fmt.Sprintf("azure-kms:///%s?%s", keyID, q.Encode())
+		cloud.KMSEncryptDecrypt(t, uri, cloud.TestKMSEnv{
+			Settings:         cluster.NoSettings,
+			ExternalIOConfig: &base.ExternalIODirConfig{},
+		})
+	})
+}
+
+func TestAzureKMSInvalidURIs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	env := &cloud.TestKMSEnv{
+		Settings:         cluster.NoSettings,
+		ExternalIOConfig: &base.ExternalIODirConfig{},
+	}
+	q := make(url.Values)
+	q.Add(AzureVaultNameParam, "vault")
+
+	for _, tc := range []struct {
+		name   string
+		uri    string
+		expErr string
+	}{
+		{
+			name:   "missing-vault",
+			uri:    "azure-kms:///key/version?AUTH=implicit",
+			expErr: `azure-kms URI missing "AZURE_VAULT_NAME" parameter`,
+		},
+		{
+			name:   "missing-version",
+			uri:    fmt.Sprintf("azure-kms:///key?%s", q.Encode()),
+			expErr: `azure-kms URI path must be of the form /<key name>/<key version>, got "/key"`,
+		},
+		{
+			name: "auth-specified-no-cred",
+			uri:  fmt.Sprintf("azure-kms:///key/version?%s", q.Encode()),
+			expErr: fmt.Sprintf(`%s is set to '%s', but %s is not set`,
+				cloud.AuthParam, cloud.AuthParamSpecified, AzureClientIDParam),
+		},
+		{
+			name:   "unsupported-auth",
+			uri:    fmt.Sprintf("azure-kms:///key/version?%s&AUTH=foo", q.Encode()),
+			expErr: `unsupported value foo for AUTH`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := cloud.KMSFromURI(tc.uri, env)
+			require.EqualError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestAzureKMSDisallowImplicitCredentials(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	q := make(url.Values)
+	q.Add(cloud.AuthParam, cloud.AuthParamImplicit)
+	q.Add(AzureVaultNameParam, "vault")
+	uri := fmt.Sprintf("azure-kms:///key/version?%s", q.Encode())
+	_, err := cloud.KMSFromURI(uri, &cloud.TestKMSEnv{
+		Settings:         cluster.NoSettings,
+		ExternalIOConfig: &base.ExternalIODirConfig{DisableImplicitCredentials: true}})
+	require.True(t, testutils.IsError(err,
+		"implicit credentials disallowed for azure due to --external-io-implicit-credentials flag"),
+	)
+}
+
+func TestAzureKMSRedactsClientSecret(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	q := make(url.Values)
+	q.Add(AzureVaultNameParam, "vault")
+	q.Add(AzureClientSecretParam, "secret")
+	redacted, err := cloud.RedactKMSURI(fmt.Sprintf("azure-kms:///key/version?%s", q.Encode()))
+	require.NoError(t, err)
+	require.Contains(t, redacted, AzureClientSecretParam+"=redacted")
+}
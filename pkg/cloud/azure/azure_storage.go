@@ -242,5 +242,6 @@ func (s *azureStorage) Close() error {
 
 func init() {
 	cloud.RegisterExternalStorageProvider(roachpb.ExternalStorageProvider_azure,
-		parseAzureURL, makeAzureStorage, cloud.RedactedParams(AzureAccountKeyParam), "azure")
+		parseAzureURL, makeAzureStorage,
+		cloud.RedactedParams(AzureAccountKeyParam, AzureClientSecretParam), "azure")
 }
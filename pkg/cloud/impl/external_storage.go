@@ -21,6 +21,7 @@ import (
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/azure"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/gcp"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/httpsink"
+	_ "github.com/cockroachdb/cockroach/pkg/cloud/localkms"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/nodelocal"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/nullsink"
 	_ "github.com/cockroachdb/cockroach/pkg/cloud/userfile"
@@ -56,6 +56,40 @@ func RegisterKMSFromURIFactory(factory KMSFromURIFactory, scheme string) {
 	kmsFactoryMap[scheme] = factory
 }
 
+// RegisterKMSRedactedParams registers query parameters of KMS URIs that should
+// be redacted whenever the URIs are displayed to a user. Parameters shared
+// with an external storage provider are already registered by that provider.
+func RegisterKMSRedactedParams(redactedParams map[string]struct{}) {
+	for param := range redactedParams {
+		redactedQueryParams[param] = struct{}{}
+	}
+}
+
+// Schemes of the KMSes which always access their keys with the node's own
+// access, e.g. to its local files.
+var implicitAccessKMSSchemes = make(map[string]struct{})
+
+// RegisterImplicitAccessKMSScheme registers a KMS scheme whose keys are always
+// accessed with the node's own access, so that URIs of that scheme are subject
+// to the same restrictions as implicit credentials.
+func RegisterImplicitAccessKMSScheme(scheme string) {
+	implicitAccessKMSSchemes[scheme] = struct{}{}
+}
+
+// KMSAccessIsWithExplicitAuth returns true if the KMS URI accesses its key with
+// credentials specified in the URI, as opposed to implicit credentials or other
+// access granted to the node, which only admin users are allowed to use.
+func KMSAccessIsWithExplicitAuth(uri string) (bool, error) {
+	kmsURL, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := implicitAccessKMSSchemes[kmsURL.Scheme]; ok {
+		return false, nil
+	}
+	return kmsURL.Query().Get(AuthParam) != AuthParamImplicit, nil
+}
+
 // KMSFromURI is the method used to create a KMS instance from the provided URI.
 func KMSFromURI(uri string, env KMSEnv) (KMS, error) {
 	var kmsURL *url.URL
@@ -0,0 +1,233 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package localkms
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/sysutil"
+	"github.com/cockroachdb/errors"
+)
+
+const fileKMSScheme = "file-kms"
+
+// maxKeyFilePermissions are the most permissive permissions a key file may
+// have; key files must not be readable by other users of the node.
+const maxKeyFilePermissions os.FileMode = 0600
+
+// fileKeyLen is the length of the keys in a key file, which are AES-256 keys.
+const fileKeyLen = 32
+
+// keyVersionLen is the length of the key version prefix of the ciphertexts
+// produced by the file KMS.
+const keyVersionLen = 4
+
+// fileKMS is a KMS backed by a key file local to each node.
+//
+// The key file lists the versions of the master key, one per line, as a
+// positive integer version followed by the base64 encoded 32 byte key:
+//
+//	# Lines starting with # are ignored.
+//	1 q2y3hKk6OaQ1L1Ydr0oO0DX7q1XcNsyDk+1JqYwf7Jk=
+//	2 Wm4t0Yx3tB2V8d2kP2cH6pJfI5T2xNn0ZlEc9m0nF1Q=
+//
+// Data is encrypted with AES-GCM under the latest version of the key, and the
+// version is recorded in the ciphertext so that the key can be rotated by
+// appending a new version to the file, as long as the previous versions are
+// kept around to decrypt existing data.
+type fileKMS struct {
+	path string
+	// keys maps the versions of the master key to the keys.
+	keys map[uint32][]byte
+	// latest is the version of the key used to encrypt data.
+	latest uint32
+}
+
+var _ cloud.KMS = &fileKMS{}
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(MakeFileKMS, fileKMSScheme)
+	// Key files are read with the node's own access to its filesystem.
+	cloud.RegisterImplicitAccessKMSScheme(fileKMSScheme)
+}
+
+// MakeFileKMS is the factory method which returns a KMS using the key file at
+// the absolute path of the URI, e.g. file-kms:///etc/cockroach/backup.keys.
+// The key file must be present at that path on every node of the cluster.
+//
+// Since the key file is read with the node's own access, the file KMS is
+// treated like implicit credentials: it is disabled by the
+// --external-io-implicit-credentials flag and only admin users can use it.
+func MakeFileKMS(uri string, env cloud.KMSEnv) (cloud.KMS, error) {
+	if env.KMSConfig().DisableImplicitCredentials {
+		return nil, errors.New(
+			"file-kms disallowed due to --external-io-implicit-credentials flag")
+	}
+	kmsURI, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if kmsURI.Host != "" {
+		return nil, errors.Errorf(
+			"file-kms URI must not specify a host, got %q; use file-kms:///<absolute path>", kmsURI.Host)
+	}
+	path := filepath.Clean(kmsURI.Path)
+	if !filepath.IsAbs(path) {
+		return nil, errors.Errorf("file-kms URI must specify an absolute path, got %q", kmsURI.Path)
+	}
+
+	keys, latest, err := readKeyFile(path)
+	if err != nil {
+		// The error is only logged on the node, and the user gets the same
+		// error whatever the reason, so that the KMS can't be used to find out
+		// whether a file exists on the node or what it contains.
+		log.Warningf(context.Background(), "could not load KMS key file %s: %v", path, err)
+		return nil, errors.Newf("could not load KMS key file %s; see the logs of the node for details", path)
+	}
+	return &fileKMS{path: path, keys: keys, latest: latest}, nil
+}
+
+// readKeyFile reads and parses the key file at path, checking that it is not
+// readable by other users of the node.
+func readKeyFile(path string) (map[uint32][]byte, uint32, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if sysutil.ExceedsPermissions(info.Mode().Perm(), maxKeyFilePermissions) {
+		return nil, 0, errors.Errorf("key file has permissions %s, exceeds %s",
+			info.Mode().Perm(), maxKeyFilePermissions)
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	return parseKeyFile(contents)
+}
+
+// parseKeyFile parses the contents of a key file, returning the keys by version
+// and the latest version. The contents of the file are deliberately left out of
+// the returned errors.
+func parseKeyFile(contents []byte) (map[uint32][]byte, uint32, error) {
+	keys := make(map[uint32][]byte)
+	var latest uint32
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, 0, errors.Errorf(
+				"line %d: expected a key version followed by a base64 encoded key", lineNum)
+		}
+		version, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil || version == 0 {
+			return nil, 0, errors.Errorf("line %d: key version must be a positive integer", lineNum)
+		}
+		if _, ok := keys[uint32(version)]; ok {
+			return nil, 0, errors.Errorf("line %d: duplicate key version %d", lineNum, version)
+		}
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil || len(key) != fileKeyLen {
+			return nil, 0, errors.Errorf(
+				"line %d: key must be a base64 encoded %d byte key", lineNum, fileKeyLen)
+		}
+		keys[uint32(version)] = key
+		if uint32(version) > latest {
+			latest = uint32(version)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(keys) == 0 {
+		return nil, 0, errors.New("no keys found")
+	}
+	return keys, latest, nil
+}
+
+// MasterKeyID implements the KMS interface. The ID does not depend on the
+// versions of the key so that it remains stable across key rotations.
+func (k *fileKMS) MasterKeyID() (string, error) {
+	return k.path, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt implements the KMS interface. The ciphertext is made of the version
+// of the key, the nonce and the sealed data. The version is authenticated as
+// additional data.
+func (k *fileKMS) Encrypt(_ context.Context, data []byte) ([]byte, error) {
+	gcm, err := newGCM(k.keys[k.latest])
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, keyVersionLen+gcm.NonceSize())
+	binary.BigEndian.PutUint32(header, k.latest)
+	if _, err := rand.Read(header[keyVersionLen:]); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(header, header[keyVersionLen:], data, header[:keyVersionLen]), nil
+}
+
+// Decrypt implements the KMS interface.
+func (k *fileKMS) Decrypt(_ context.Context, data []byte) ([]byte, error) {
+	if len(data) < keyVersionLen {
+		return nil, errors.New("file-kms ciphertext too short")
+	}
+	version := binary.BigEndian.Uint32(data)
+	key, ok := k.keys[version]
+	if !ok {
+		return nil, errors.Errorf("version %d of the key is not present in KMS key file %s",
+			version, k.path)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < keyVersionLen+gcm.NonceSize() {
+		return nil, errors.New("file-kms ciphertext too short")
+	}
+	nonce := data[keyVersionLen : keyVersionLen+gcm.NonceSize()]
+	plaintext, err := gcm.Open(nil, nonce, data[keyVersionLen+gcm.NonceSize():], data[:keyVersionLen])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt with file-kms key")
+	}
+	return plaintext, nil
+}
+
+// Close implements the KMS interface.
+func (k *fileKMS) Close() error {
+	return nil
+}
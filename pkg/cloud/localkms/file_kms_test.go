@@ -0,0 +1,204 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package localkms
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/errors/oserror"
+	"github.com/stretchr/testify/require"
+)
+
+func makeKeyLine(t *testing.T, version int) string {
+	key := make([]byte, fileKeyLen)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return fmt.Sprintf("%d %s\n", version, base64.StdEncoding.EncodeToString(key))
+}
+
+func writeKeyFile(t *testing.T, path string, contents string) {
+	require.NoError(t, ioutil.WriteFile(path, []byte(contents), 0600))
+}
+
+func TestEncryptDecryptFileKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "backup.keys")
+	writeKeyFile(t, path, "# backup keys\n"+makeKeyLine(t, 1))
+
+	cloud.KMSEncryptDecrypt(t, "file-kms://"+path, cloud.TestKMSEnv{
+		Settings:         cluster.NoSettings,
+		ExternalIOConfig: &base.ExternalIODirConfig{},
+	})
+}
+
+func TestFileKMSKeyRotation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "backup.keys")
+	uri := "file-kms://" + path
+	env := &cloud.TestKMSEnv{ExternalIOConfig: &base.ExternalIODirConfig{}}
+
+	v1 := makeKeyLine(t, 1)
+	writeKeyFile(t, path, v1)
+	kms, err := cloud.KMSFromURI(uri, env)
+	require.NoError(t, err)
+	idV1, err := kms.MasterKeyID()
+	require.NoError(t, err)
+	encryptedV1, err := kms.Encrypt(ctx, []byte("hello world"))
+	require.NoError(t, err)
+	require.NoError(t, kms.Close())
+
+	// Rotate the key by adding a new version. The master key ID is unchanged
+	// and data encrypted with the previous version can still be decrypted.
+	writeKeyFile(t, path, v1+makeKeyLine(t, 2))
+	kms, err = cloud.KMSFromURI(uri, env)
+	require.NoError(t, err)
+	idV2, err := kms.MasterKeyID()
+	require.NoError(t, err)
+	require.Equal(t, idV1, idV2)
+
+	decrypted, err := kms.Decrypt(ctx, encryptedV1)
+	require.NoError(t, err)
+	require.Equal(t, "hello world", string(decrypted))
+
+	encryptedV2, err := kms.Encrypt(ctx, []byte("hello world"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 0, 0, 2}, encryptedV2[:keyVersionLen])
+	require.NoError(t, kms.Close())
+
+	// Once the previous version is retired, data encrypted with it can no
+	// longer be decrypted.
+	writeKeyFile(t, path, makeKeyLine(t, 2))
+	kms, err = cloud.KMSFromURI(uri, env)
+	require.NoError(t, err)
+	_, err = kms.Decrypt(ctx, encryptedV1)
+	require.True(t, testutils.IsError(err, "version 1 of the key is not present in KMS key file"), err)
+
+	// Tampered ciphertexts are rejected.
+	encryptedV2[len(encryptedV2)-1] ^= 1
+	_, err = kms.Decrypt(ctx, encryptedV2)
+	require.True(t, testutils.IsError(err, "failed to decrypt with file-kms key"), err)
+	require.NoError(t, kms.Close())
+}
+
+func TestFileKMSInvalidKeyFiles(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	dir, cleanup := testutils.TempDir(t)
+	defer cleanup()
+	env := &cloud.TestKMSEnv{ExternalIOConfig: &base.ExternalIODirConfig{}}
+
+	t.Run("invalid-uri", func(t *testing.T) {
+		_, err := cloud.KMSFromURI("file-kms://host/backup.keys", env)
+		require.True(t, testutils.IsError(err, "file-kms URI must not specify a host"), err)
+	})
+
+	t.Run("implicit-disallowed", func(t *testing.T) {
+		path := filepath.Join(dir, "backup.keys")
+		writeKeyFile(t, path, makeKeyLine(t, 1))
+		_, err := cloud.KMSFromURI("file-kms://"+path, &cloud.TestKMSEnv{
+			ExternalIOConfig: &base.ExternalIODirConfig{DisableImplicitCredentials: true},
+		})
+		require.EqualError(t, err, "file-kms disallowed due to --external-io-implicit-credentials flag")
+	})
+
+	// The errors returned to the user do not depend on whether the file exists
+	// or on its contents.
+	loadErr := func(path string) string {
+		return fmt.Sprintf("could not load KMS key file %s; see the logs of the node for details", path)
+	}
+
+	t.Run("missing-file", func(t *testing.T) {
+		path := filepath.Join(dir, "missing.keys")
+		_, err := cloud.KMSFromURI("file-kms://"+path, env)
+		require.EqualError(t, err, loadErr(path))
+		_, _, err = readKeyFile(path)
+		require.True(t, oserror.IsNotExist(err), err)
+	})
+
+	t.Run("permissions", func(t *testing.T) {
+		path := filepath.Join(dir, "readable.keys")
+		writeKeyFile(t, path, makeKeyLine(t, 1))
+		require.NoError(t, os.Chmod(path, 0644))
+		_, err := cloud.KMSFromURI("file-kms://"+path, env)
+		require.EqualError(t, err, loadErr(path))
+		_, _, err = readKeyFile(path)
+		require.True(t, testutils.IsError(err, "has permissions -rw-r--r--, exceeds -rw-------"), err)
+	})
+
+	for _, tc := range []struct {
+		name     string
+		contents string
+		expErr   string
+	}{
+		{
+			name:     "empty",
+			contents: "# no keys\n",
+			expErr:   "no keys found",
+		},
+		{
+			name:     "missing-version",
+			contents: strings.Fields(makeKeyLine(t, 1))[1],
+			expErr:   "line 1: expected a key version followed by a base64 encoded key",
+		},
+		{
+			name:     "invalid-version",
+			contents: "0 " + strings.Fields(makeKeyLine(t, 1))[1],
+			expErr:   "line 1: key version must be a positive integer",
+		},
+		{
+			name:     "duplicate-version",
+			contents: makeKeyLine(t, 1) + makeKeyLine(t, 1),
+			expErr:   "line 2: duplicate key version 1",
+		},
+		{
+			name:     "short-key",
+			contents: "1 " + base64.StdEncoding.EncodeToString([]byte("too short")),
+			expErr:   "line 1: key must be a base64 encoded 32 byte key",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, tc.name+".keys")
+			writeKeyFile(t, path, tc.contents)
+			_, err := cloud.KMSFromURI("file-kms://"+path, env)
+			require.EqualError(t, err, loadErr(path))
+			_, _, err = parseKeyFile([]byte(tc.contents))
+			require.EqualError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestFileKMSAccessIsNotWithExplicitAuth(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	explicit, err := cloud.KMSAccessIsWithExplicitAuth("file-kms:///etc/cockroach/backup.keys")
+	require.NoError(t, err)
+	require.False(t, explicit)
+}
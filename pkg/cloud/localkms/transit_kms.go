@@ -0,0 +1,234 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package localkms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/errors"
+)
+
+const transitScheme = "transit"
+
+const (
+	// TransitTokenParam is the query parameter for the token used to
+	// authenticate with the transit endpoint.
+	TransitTokenParam = "TRANSIT_TOKEN"
+	// TransitNamespaceParam is the query parameter for the namespace of the
+	// transit endpoint, if any.
+	TransitNamespaceParam = "TRANSIT_NAMESPACE"
+
+	// transitTokenEnvVar is the environment variable holding the token used
+	// with implicit credentials.
+	transitTokenEnvVar = "VAULT_TOKEN"
+)
+
+// transitKMS is a KMS backed by an HTTPS endpoint implementing the encrypt and
+// decrypt operations of the HashiCorp Vault transit secrets engine API, such
+// as a Vault server. The key is managed, and rotated, by the endpoint, which
+// records the version of the key in the ciphertexts it returns.
+type transitKMS struct {
+	client    *http.Client
+	baseURL   string
+	mount     string
+	keyName   string
+	token     string
+	namespace string
+}
+
+var _ cloud.KMS = &transitKMS{}
+
+func init() {
+	cloud.RegisterKMSFromURIFactory(MakeTransitKMS, transitScheme)
+	cloud.RegisterKMSRedactedParams(cloud.RedactedParams(TransitTokenParam))
+}
+
+type transitURIParams struct {
+	token     string
+	namespace string
+	auth      string
+}
+
+func resolveTransitURIParams(kmsURI url.URL) transitURIParams {
+	return transitURIParams{
+		token:     kmsURI.Query().Get(TransitTokenParam),
+		namespace: kmsURI.Query().Get(TransitNamespaceParam),
+		auth:      kmsURI.Query().Get(cloud.AuthParam),
+	}
+}
+
+// MakeTransitKMS is the factory method which returns a KMS using the key of a
+// transit endpoint, given a URI of the form
+// transit://<host>[:<port>]/<mount path>/<key name>?TRANSIT_TOKEN=<token>.
+func MakeTransitKMS(uri string, env cloud.KMSEnv) (cloud.KMS, error) {
+	if env.KMSConfig().DisableOutbound {
+		return nil, errors.New("external IO must be enabled to use transit KMS")
+	}
+	if env.KMSConfig().DisableHTTP {
+		return nil, errors.New(
+			"transit kms disallowed due to --external-io-disable-http flag")
+	}
+	kmsURI, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	if kmsURI.Host == "" {
+		return nil, errors.New("transit URI must specify the host of the transit endpoint")
+	}
+	mount, keyName := path.Split(strings.Trim(kmsURI.Path, "/"))
+	mount = strings.TrimSuffix(mount, "/")
+	if mount == "" || keyName == "" {
+		return nil, errors.Errorf(
+			"transit URI path must be of the form /<mount path>/<key name>, got %q", kmsURI.Path)
+	}
+
+	params := resolveTransitURIParams(*kmsURI)
+	token := params.token
+	switch params.auth {
+	case "", cloud.AuthParamSpecified:
+		if token == "" {
+			return nil, errors.Errorf(
+				"%s is set to '%s', but %s is not set",
+				cloud.AuthParam,
+				cloud.AuthParamSpecified,
+				TransitTokenParam,
+			)
+		}
+	case cloud.AuthParamImplicit:
+		if env.KMSConfig().DisableImplicitCredentials {
+			return nil, errors.New(
+				"implicit credentials disallowed for transit due to --external-io-implicit-credentials flag")
+		}
+		token = os.Getenv(transitTokenEnvVar)
+		if token == "" {
+			return nil, errors.Errorf(
+				"%s is set to '%s', but the %s environment variable is not set",
+				cloud.AuthParam,
+				cloud.AuthParamImplicit,
+				transitTokenEnvVar,
+			)
+		}
+	default:
+		return nil, errors.Errorf("unsupported value %s for %s", params.auth, cloud.AuthParam)
+	}
+
+	client, err := cloud.MakeHTTPClient(env.ClusterSettings())
+	if err != nil {
+		return nil, err
+	}
+	return &transitKMS{
+		client:    client,
+		baseURL:   "https://" + kmsURI.Host,
+		mount:     mount,
+		keyName:   keyName,
+		token:     token,
+		namespace: params.namespace,
+	}, nil
+}
+
+// MasterKeyID implements the KMS interface.
+func (k *transitKMS) MasterKeyID() (string, error) {
+	return fmt.Sprintf("%s/%s/keys/%s", k.baseURL, k.mount, k.keyName), nil
+}
+
+// Encrypt implements the KMS interface. The returned ciphertext is the one
+// produced by the transit endpoint, e.g. vault:v1:<base64 ciphertext>.
+func (k *transitKMS) Encrypt(ctx context.Context, data []byte) ([]byte, error) {
+	var res struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	if err := k.do(ctx, "encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(data),
+	}, &res); err != nil {
+		return nil, err
+	}
+	if res.Data.Ciphertext == "" {
+		return nil, errors.New("transit encrypt returned an empty ciphertext")
+	}
+	return []byte(res.Data.Ciphertext), nil
+}
+
+// Decrypt implements the KMS interface.
+func (k *transitKMS) Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	var res struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := k.do(ctx, "decrypt", map[string]string{
+		"ciphertext": string(data),
+	}, &res); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(res.Data.Plaintext)
+}
+
+// do sends req to the endpoint of the given operation for the key and decodes
+// the response into res.
+func (k *transitKMS) do(
+	ctx context.Context, op string, req map[string]string, res interface{},
+) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/v1/%s/%s/%s", k.baseURL, k.mount, op, url.PathEscape(k.keyName))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-Vault-Token", k.token)
+	if k.namespace != "" {
+		httpReq.Header.Set("X-Vault-Namespace", k.namespace)
+	}
+
+	resp, err := k.client.Do(httpReq)
+	if err != nil {
+		return errors.Wrapf(err, "transit %s request failed", op)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// Errors are reported as {"errors": ["..."]}. Fall back to the status
+		// alone if the body can't be decoded as such.
+		var errRes struct {
+			Errors []string `json:"errors"`
+		}
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		if json.Unmarshal(msg, &errRes) == nil && len(errRes.Errors) > 0 {
+			return errors.Errorf("transit %s request failed with status %s: %s",
+				op, resp.Status, strings.Join(errRes.Errors, "; "))
+		}
+		return errors.Errorf("transit %s request failed with status %s", op, resp.Status)
+	}
+	return errors.Wrapf(json.NewDecoder(resp.Body).Decode(res),
+		"failed to decode transit %s response", op)
+}
+
+// Close implements the KMS interface.
+func (k *transitKMS) Close() error {
+	k.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,290 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package localkms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/stretchr/testify/require"
+)
+
+const testTransitToken = "s.test-token"
+
+// testTransitServer is a stand-in for the transit secrets engine, serving a
+// single key under the transit mount.
+type testTransitServer struct {
+	*httptest.Server
+
+	mu struct {
+		syncutil.Mutex
+		// keys holds the versions of the key, the latest one being used to
+		// encrypt.
+		keys [][]byte
+	}
+}
+
+func newTestTransitServer(t *testing.T, keyName string) *testTransitServer {
+	s := &testTransitServer{}
+	s.rotate(t)
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != testTransitToken {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string][]string{"errors": {"permission denied"}})
+			return
+		}
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var res map[string]string
+		var err error
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/" + keyName:
+			res, err = s.encrypt(req["plaintext"])
+		case "/v1/transit/decrypt/" + keyName:
+			res, err = s.decrypt(req["ciphertext"])
+		default:
+			http.Error(w, "unsupported path "+r.URL.Path, http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string][]string{"errors": {err.Error()}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]map[string]string{"data": res})
+	}))
+	return s
+}
+
+func (s *testTransitServer) rotate(t *testing.T) {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.keys = append(s.mu.keys, key)
+}
+
+func (s *testTransitServer) gcm(version int) (cipher.AEAD, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if version < 1 || version > len(s.mu.keys) {
+		return nil, fmt.Errorf("invalid key version %d", version)
+	}
+	block, err := aes.NewCipher(s.mu.keys[version-1])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (s *testTransitServer) encrypt(plaintext string) (map[string]string, error) {
+	data, err := base64.StdEncoding.DecodeString(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	version := len(s.mu.keys)
+	s.mu.Unlock()
+	gcm, err := s.gcm(version)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := gcm.Seal(nonce, nonce, data, nil)
+	return map[string]string{
+		"ciphertext": fmt.Sprintf("vault:v%d:%s", version, base64.StdEncoding.EncodeToString(sealed)),
+	}, nil
+}
+
+func (s *testTransitServer) decrypt(ciphertext string) (map[string]string, error) {
+	parts := strings.SplitN(ciphertext, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" || !strings.HasPrefix(parts[1], "v") {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := s.gcm(version)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+	data, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"plaintext": base64.StdEncoding.EncodeToString(data)}, nil
+}
+
+// makeTransitTestSettings returns cluster settings trusting the certificate of
+// the given server.
+func makeTransitTestSettings(t *testing.T, srv *httptest.Server) *cluster.Settings {
+	st := cluster.MakeTestingClusterSettings()
+	u := st.MakeUpdater()
+	require.NoError(t, u.Set(context.Background(), "cloudstorage.http.custom_ca", settings.EncodedValue{
+		Value: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})),
+		Type:  "s",
+	}))
+	return st
+}
+
+func TestEncryptDecryptTransitKMS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	srv := newTestTransitServer(t, "backup-key")
+	defer srv.Close()
+	st := makeTransitTestSettings(t, srv.Server)
+	host := strings.TrimPrefix(srv.URL, "https://")
+
+	q := make(url.Values)
+	q.Set(TransitTokenParam, testTransitToken)
+	uri := fmt.Sprintf("transit://%s/transit/backup-key?%s", host, q.Encode())
+	cloud.KMSEncryptDecrypt(t, uri, cloud.TestKMSEnv{
+		Settings:         st,
+		ExternalIOConfig: &base.ExternalIODirConfig{},
+	})
+
+	t.Run("key-rotation", func(t *testing.T) {
+		ctx := context.Background()
+		kms, err := cloud.KMSFromURI(uri, &cloud.TestKMSEnv{
+			Settings:         st,
+			ExternalIOConfig: &base.ExternalIODirConfig{},
+		})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, kms.Close()) }()
+
+		encrypted, err := kms.Encrypt(ctx, []byte("hello world"))
+		require.NoError(t, err)
+		srv.rotate(t)
+		rotated, err := kms.Encrypt(ctx, []byte("hello world"))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(string(rotated), "vault:v2:"), string(rotated))
+
+		decrypted, err := kms.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "hello world", string(decrypted))
+	})
+
+	t.Run("wrong-token", func(t *testing.T) {
+		q := make(url.Values)
+		q.Set(TransitTokenParam, "s.wrong-token")
+		kms, err := cloud.KMSFromURI(fmt.Sprintf("transit://%s/transit/backup-key?%s", host, q.Encode()),
+			&cloud.TestKMSEnv{Settings: st, ExternalIOConfig: &base.ExternalIODirConfig{}})
+		require.NoError(t, err)
+		defer func() { require.NoError(t, kms.Close()) }()
+		_, err = kms.Encrypt(context.Background(), []byte("hello world"))
+		require.True(t, testutils.IsError(err,
+			"transit encrypt request failed with status 403 Forbidden: permission denied"), err)
+	})
+}
+
+func TestTransitKMSInvalidURIs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	env := &cloud.TestKMSEnv{
+		Settings:         cluster.NoSettings,
+		ExternalIOConfig: &base.ExternalIODirConfig{},
+	}
+	q := make(url.Values)
+	q.Set(TransitTokenParam, testTransitToken)
+
+	for _, tc := range []struct {
+		name   string
+		uri    string
+		env    *cloud.TestKMSEnv
+		expErr string
+	}{
+		{
+			name:   "missing-host",
+			uri:    fmt.Sprintf("transit:///transit/backup-key?%s", q.Encode()),
+			expErr: "transit URI must specify the host of the transit endpoint",
+		},
+		{
+			name:   "missing-mount",
+			uri:    fmt.Sprintf("transit://vault:8200/backup-key?%s", q.Encode()),
+			expErr: `transit URI path must be of the form /<mount path>/<key name>, got "/backup-key"`,
+		},
+		{
+			name: "auth-specified-no-token",
+			uri:  "transit://vault:8200/transit/backup-key",
+			expErr: fmt.Sprintf("%s is set to '%s', but %s is not set",
+				cloud.AuthParam, cloud.AuthParamSpecified, TransitTokenParam),
+		},
+		{
+			name: "implicit-disallowed",
+			uri:  "transit://vault:8200/transit/backup-key?AUTH=implicit",
+			env: &cloud.TestKMSEnv{
+				ExternalIOConfig: &base.ExternalIODirConfig{DisableImplicitCredentials: true},
+			},
+			expErr: "implicit credentials disallowed for transit due to --external-io-implicit-credentials flag",
+		},
+		{
+			name: "http-disallowed",
+			uri:  fmt.Sprintf("transit://vault:8200/transit/backup-key?%s", q.Encode()),
+			env: &cloud.TestKMSEnv{
+				ExternalIOConfig: &base.ExternalIODirConfig{DisableHTTP: true},
+			},
+			expErr: "transit kms disallowed due to --external-io-disable-http flag",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e := env
+			if tc.env != nil {
+				e = tc.env
+			}
+			_, err := cloud.KMSFromURI(tc.uri, e)
+			require.EqualError(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestTransitKMSRedactsToken(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	q := make(url.Values)
+	q.Set(TransitTokenParam, testTransitToken)
+	redacted, err := cloud.RedactKMSURI(fmt.Sprintf("transit://vault:8200/transit/backup-key?%s", q.Encode()))
+	require.NoError(t, err)
+	require.Equal(t, "transit://vault:8200/redacted?TRANSIT_TOKEN=redacted", redacted)
+}
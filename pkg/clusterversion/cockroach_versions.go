@@ -345,6 +345,28 @@ const (
 	// SeedSpanCountTable seeds system.span_count with the number of committed
 	// tenant spans.
 	SeedSpanCountTable
+	// NotificationsTable adds system.notifications, which backs LISTEN and
+	// NOTIFY.
+	NotificationsTable
+	// FunctionDescriptors adds function descriptors, which back user-defined
+	// functions.
+	FunctionDescriptors
+	// CompositeTypes adds user-defined composite types. Their values are
+	// encoded in keys differently from other tuples.
+	CompositeTypes
+	// TableTriggers adds row-level triggers, which are stored in table
+	// descriptors.
+	TableTriggers
+	// SkipLockedReads adds the SKIP LOCKED wait policy to reads, which older
+	// nodes do not know to apply.
+	SkipLockedReads
+	// DeferrableConstraints allows foreign key and unique constraints to be
+	// declared DEFERRABLE, whose checks can be postponed until the end of the
+	// transaction.
+	DeferrableConstraints
+	// DomainTypes adds DOMAIN type descriptors and the domain constraints of
+	// columns, which older nodes do not know to enforce.
+	DomainTypes
 
 	// *************************************************
 	// Step (1): Add new versions here.
@@ -596,6 +618,34 @@ var versionsSingleton = keyedVersions{
 		Key:     SeedSpanCountTable,
 		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 112},
 	},
+	{
+		Key:     NotificationsTable,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 114},
+	},
+	{
+		Key:     FunctionDescriptors,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 116},
+	},
+	{
+		Key:     CompositeTypes,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 118},
+	},
+	{
+		Key:     TableTriggers,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 120},
+	},
+	{
+		Key:     SkipLockedReads,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 122},
+	},
+	{
+		Key:     DeferrableConstraints,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 124},
+	},
+	{
+		Key:     DomainTypes,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 126},
+	},
 
 	// *************************************************
 	// Step (2): Add new versions here.
@@ -481,6 +481,8 @@ const (
 	SchemaCommentType CommentType = 4
 	// ConstraintCommentType comment on a constraint.
 	ConstraintCommentType CommentType = 5
+	// FunctionCommentType comment on a user-defined function.
+	FunctionCommentType CommentType = 6
 )
 
 const (
@@ -57,6 +57,8 @@ func Get(
 		Txn:              h.Txn,
 		FailOnMoreRecent: args.KeyLocking != lock.None,
 		Uncertainty:      cArgs.Uncertainty,
+		SkipLocked:       h.WaitPolicy == lock.WaitPolicy_SkipLocked,
+		LockTable:        cArgs.Concurrency,
 		MemoryAccount:    cArgs.EvalCtx.GetResponseMemoryAccount(),
 	})
 	if err != nil {
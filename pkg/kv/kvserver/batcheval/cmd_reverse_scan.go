@@ -52,6 +52,8 @@ func ReverseScan(
 		AllowEmpty:             h.AllowEmpty,
 		WholeRowsOfSize:        h.WholeRowsOfSize,
 		FailOnMoreRecent:       args.KeyLocking != lock.None,
+		SkipLocked:             h.WaitPolicy == lock.WaitPolicy_SkipLocked,
+		LockTable:              cArgs.Concurrency,
 		Reverse:                true,
 		MemoryAccount:          cArgs.EvalCtx.GetResponseMemoryAccount(),
 	}
@@ -53,6 +53,8 @@ func Scan(
 		AllowEmpty:             h.AllowEmpty,
 		WholeRowsOfSize:        h.WholeRowsOfSize,
 		FailOnMoreRecent:       args.KeyLocking != lock.None,
+		SkipLocked:             h.WaitPolicy == lock.WaitPolicy_SkipLocked,
+		LockTable:              cArgs.Concurrency,
 		Reverse:                false,
 		MemoryAccount:          cArgs.EvalCtx.GetResponseMemoryAccount(),
 	}
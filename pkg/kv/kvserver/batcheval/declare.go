@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/uncertainty"
@@ -118,6 +119,10 @@ type CommandArgs struct {
 	Header  roachpb.Header
 	Args    roachpb.Request
 	// *Stats should be mutated to reflect any writes made by the command.
-	Stats       *enginepb.MVCCStats
+	Stats *enginepb.MVCCStats
+	// Concurrency is the request's concurrency guard. It is nil when the
+	// request is evaluated outside of the concurrency manager (e.g. during
+	// testing or for internal gossip scans).
+	Concurrency *concurrency.Guard
 	Uncertainty uncertainty.Interval
 }
@@ -767,6 +767,14 @@ type lockTableGuard interface {
 	// so this checking is practically only going to find unreplicated locks
 	// that conflict.
 	CheckOptimisticNoConflicts(*spanset.SpanSet) (ok bool)
+
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// or reserved by a conflicting transaction in the lockTable snapshot
+	// captured during the last call to ScanAndEnqueue, given the caller's own
+	// desired locking strength. If so, the lock holder is also returned. It is
+	// used by requests with a SkipLocked wait policy, which do not wait in lock
+	// wait-queues and instead skip over locked keys during evaluation.
+	IsKeyLockedByConflictingTxn(roachpb.Key, lock.Strength) (bool, *enginepb.TxnMeta)
 }
 
 // lockTableWaiter is concerned with waiting in lock wait-queues for locks held
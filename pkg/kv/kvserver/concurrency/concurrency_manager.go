@@ -731,6 +731,25 @@ func (g *Guard) CheckOptimisticNoLatchConflicts() (ok bool) {
 	return g.lm.CheckOptimisticNoConflicts(g.lg, g.Req.LatchSpans)
 }
 
+// IsKeyLockedByConflictingTxn returns whether the specified key is locked or
+// reserved by a conflicting transaction, given the caller's own desired locking
+// strength. If so, the lock holder is also returned. The method consults the
+// snapshot of the lockTable captured when the request was sequenced, so it is
+// only aware of locks that existed at that time. Requests using a SkipLocked
+// wait policy rely on the method to skip over keys locked by unreplicated locks
+// during evaluation.
+//
+// The method implements the storage.LockTableView interface. It is safe to
+// call on a nil Guard, in which case no key is considered locked.
+func (g *Guard) IsKeyLockedByConflictingTxn(
+	key roachpb.Key, strength lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	if g == nil || g.ltg == nil {
+		return false, nil
+	}
+	return g.ltg.IsKeyLockedByConflictingTxn(key, strength)
+}
+
 func (g *Guard) moveLatchGuard() latchGuard {
 	lg := g.lg
 	g.lg = nil
@@ -364,6 +364,7 @@ type lockTableGuardImpl struct {
 	txn                *enginepb.TxnMeta
 	ts                 hlc.Timestamp
 	spans              *spanset.SpanSet
+	waitPolicy         lock.WaitPolicy
 	maxWaitQueueLength int
 
 	// Snapshots of the trees for which this request has some spans. Note that
@@ -568,6 +569,23 @@ func (g *lockTableGuardImpl) CheckOptimisticNoConflicts(spanSet *spanset.SpanSet
 	return true
 }
 
+func (g *lockTableGuardImpl) IsKeyLockedByConflictingTxn(
+	key roachpb.Key, strength lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	ss := spanset.SpanGlobal
+	if keys.IsLocal(key) {
+		ss = spanset.SpanLocal
+	}
+	tree := g.tableSnapshot[ss]
+	iter := tree.MakeIter()
+	iter.SeekGE(&lockState{key: key})
+	if !iter.Valid() || !iter.Cur().key.Equal(key) {
+		// No lock on key.
+		return false, nil
+	}
+	return iter.Cur().isLockedByConflictingTxn(g, strength)
+}
+
 func (g *lockTableGuardImpl) notify() {
 	select {
 	case g.mu.signal <- struct{}{}:
@@ -1755,6 +1773,38 @@ func (l *lockState) isNonConflictingLock(g *lockTableGuardImpl, sa spanset.SpanA
 	return false
 }
 
+// Returns whether the lock is held or reserved by a transaction that conflicts
+// with the request, given the request's locking strength. If so, the lock
+// holder (or reservation holder) is also returned. Used by requests with a
+// SkipLocked wait policy to determine which keys to skip.
+// Acquires l.mu.
+func (l *lockState) isLockedByConflictingTxn(
+	g *lockTableGuardImpl, strength lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lockHolderTxn, lockHolderTS := l.getLockHolder()
+	if lockHolderTxn == nil {
+		// Not locked. A reservation only conflicts with locking requests, since
+		// the reservation holder has not yet acquired the lock and non-locking
+		// reads are allowed to proceed through reservations.
+		if l.reservation != nil && strength != lock.None && !g.isSameTxn(l.reservation.txn) {
+			return true, l.reservation.txn
+		}
+		return false, nil
+	}
+	if g.isSameTxn(lockHolderTxn) {
+		// Already locked by this txn.
+		return false, nil
+	}
+	if strength == lock.None && g.ts.Less(lockHolderTS) {
+		// Non-locking reads below the lock holder's timestamp do not conflict.
+		return false, nil
+	}
+	return true, lockHolderTxn
+}
+
 // Acquires this lock. Returns the list of guards that are done actively
 // waiting at this key -- these will be requests from the same transaction
 // that is acquiring the lock.
@@ -2412,6 +2462,13 @@ func (t *lockTableImpl) ScanAndEnqueue(req Request, guard lockTableGuard) lockTa
 		g.toResolve = g.toResolve[:0]
 	}
 	t.doSnapshotForGuard(g)
+	if g.waitPolicy == lock.WaitPolicy_SkipLocked {
+		// Requests using a SkipLocked wait policy capture a lockTable snapshot
+		// but do not wait in any lock wait-queues. Instead, they consult the
+		// snapshot through IsKeyLockedByConflictingTxn during evaluation to
+		// determine which keys to skip.
+		return g
+	}
 	g.findNextLockAfter(true /* notify */)
 	if g.notRemovableLock != nil {
 		// Either waiting at the notRemovableLock, or elsewhere. Either way we are
@@ -2429,6 +2486,7 @@ func (t *lockTableImpl) newGuardForReq(req Request) *lockTableGuardImpl {
 	g.txn = req.txnMeta()
 	g.ts = req.Timestamp
 	g.spans = req.LockSpans
+	g.waitPolicy = req.WaitPolicy
 	g.maxWaitQueueLength = req.MaxLockWaitQueueLength
 	g.sa = spanset.NumSpanAccess - 1
 	g.index = -1
@@ -76,12 +76,26 @@ func AlterColumnType(
 			)
 		}
 	}
+	for _, fnRef := range tableDesc.DependedOnByFunctions {
+		if descpb.ColumnIDs(fnRef.ColumnIDs).Contains(col.GetID()) {
+			return params.p.dependentFunctionError(
+				ctx, "column", col.GetName(), fnRef.ID, "alter type of",
+			)
+		}
+	}
 
 	typ, err := tree.ResolveType(ctx, t.ToType, params.p.semaCtx.GetTypeResolver())
 	if err != nil {
 		return err
 	}
 
+	// The type of a column cannot be changed from or to a DOMAIN type, since
+	// the column would need to start or stop referencing the domain.
+	if typ.IsDomain() || col.GetDomainTypeID() != descpb.InvalidID {
+		return unimplemented.NewWithIssuef(27796,
+			"ALTER COLUMN TYPE from or to a domain is not supported")
+	}
+
 	// Special handling for STRING COLLATE xy to verify that we recognize the language.
 	if t.Collation != "" {
 		if types.IsStringType(typ) {
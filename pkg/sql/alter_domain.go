@@ -0,0 +1,102 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type alterDomainNode struct {
+	n    *tree.AlterDomain
+	desc *typedesc.Mutable
+}
+
+// alterDomainNode implements planNode. We set n here to satisfy the linter.
+var _ planNode = &alterDomainNode{n: nil}
+
+// AlterDomain modifies a DOMAIN. Only ADD CONSTRAINT is supported.
+func (p *planner) AlterDomain(ctx context.Context, n *tree.AlterDomain) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER DOMAIN",
+	); err != nil {
+		return nil, err
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.DomainTypes) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to alter domains",
+			clusterversion.ByKey(clusterversion.DomainTypes))
+	}
+
+	_, desc, err := p.ResolveMutableTypeDescriptor(ctx, n.TypeName, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if desc.Kind != descpb.TypeDescriptor_DOMAIN {
+		return nil, errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "%q is not a domain", desc.Name),
+			"Use ALTER TYPE to modify a type.",
+		)
+	}
+
+	// The user needs ownership privilege to alter the domain.
+	if err := p.canModifyType(ctx, desc); err != nil {
+		return nil, err
+	}
+
+	return &alterDomainNode{n: n, desc: desc}, nil
+}
+
+func (n *alterDomainNode) startExec(params runParams) error {
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterDomainAddConstraint:
+		if err := addDomainCheckConstraint(
+			params.ctx, params.p.SemaCtx(), n.desc.Domain, n.desc.Name, t.Check,
+		); err != nil {
+			return err
+		}
+		// The new constraint must hold for the values already stored in the
+		// columns of the domain, which is checked by the type schema changer
+		// once all nodes enforce the constraint on writes.
+		check := &n.desc.Domain.CheckConstraints[len(n.desc.Domain.CheckConstraints)-1]
+		check.Validity = descpb.ConstraintValidity_Validating
+	default:
+		return errors.AssertionFailedf("unknown alter domain cmd %s", t)
+	}
+
+	if err := params.p.writeTypeSchemaChange(
+		params.ctx, n.desc, tree.AsStringWithFQNames(n.n, params.p.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Write a log event.
+	return params.p.logEvent(params.ctx,
+		n.desc.ID,
+		&eventpb.AlterType{
+			TypeName: tree.AsStringWithFQNames(n.n.TypeName, params.p.Ann()),
+		})
+}
+
+func (n *alterDomainNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *alterDomainNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *alterDomainNode) Close(ctx context.Context)           {}
+func (n *alterDomainNode) ReadingOwnWrites()                   {}
@@ -0,0 +1,251 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type alterFunctionRenameNode struct {
+	n      *tree.AlterFunctionRename
+	sc     *schemadesc.Mutable
+	fnDesc *funcdesc.Mutable
+}
+
+type alterFunctionSetSchemaNode struct {
+	n      *tree.AlterFunctionSetSchema
+	sc     *schemadesc.Mutable
+	fnDesc *funcdesc.Mutable
+}
+
+type alterFunctionSetOwnerNode struct {
+	n      *tree.AlterFunctionSetOwner
+	fnDesc *funcdesc.Mutable
+}
+
+// AlterFunctionRename renames a user-defined function.
+// Privileges: ownership of the function and CREATE on the schema.
+func (p *planner) AlterFunctionRename(
+	ctx context.Context, n *tree.AlterFunctionRename,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+	sc, fnDesc, err := p.resolveFuncObj(ctx, &n.Function, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkFunctionOwnership(ctx, fnDesc); err != nil {
+		return nil, err
+	}
+	// The bodies of functions which call this one reference it by name.
+	if ids := fnDesc.GetDependedOnByFunctions(); len(ids) > 0 {
+		return nil, p.dependentFunctionError(ctx, "function", fnDesc.GetName(), ids[0], "rename")
+	}
+	if err := p.canCreateOnSchema(
+		ctx, sc.GetID(), sc.GetParentID(), p.User(), checkPublicSchema,
+	); err != nil {
+		return nil, err
+	}
+	return &alterFunctionRenameNode{n: n, sc: sc, fnDesc: fnDesc}, nil
+}
+
+func (n *alterFunctionRenameNode) startExec(params runParams) error {
+	newName := string(n.n.NewName)
+	oldName := n.fnDesc.GetName()
+	if newName == oldName {
+		return nil
+	}
+	if _, ok := tree.FunDefs[newName]; ok {
+		return pgerror.Newf(pgcode.DuplicateFunction,
+			"function %q conflicts with a built-in function", newName)
+	}
+	if err := checkFunctionConflict(n.sc, newName, n.fnDesc); err != nil {
+		return err
+	}
+
+	overload := makeFunctionOverload(n.fnDesc)
+	n.sc.RemoveFunction(oldName, n.fnDesc.GetID())
+	n.sc.AddFunction(newName, overload)
+	n.fnDesc.SetName(newName)
+	if err := params.p.writeFuncDesc(params.ctx, n.fnDesc); err != nil {
+		return err
+	}
+	return params.p.writeSchemaDescChange(params.ctx, n.sc,
+		fmt.Sprintf("renaming function %s to %s in schema %s", oldName, newName, n.sc.GetName()))
+}
+
+// AlterFunctionSetSchema moves a user-defined function to another schema.
+// Privileges: ownership of the function and CREATE on the new schema.
+func (p *planner) AlterFunctionSetSchema(
+	ctx context.Context, n *tree.AlterFunctionSetSchema,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+	sc, fnDesc, err := p.resolveFuncObj(ctx, &n.Function, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkFunctionOwnership(ctx, fnDesc); err != nil {
+		return nil, err
+	}
+	// The bodies of functions which call this one reference it by name.
+	if ids := fnDesc.GetDependedOnByFunctions(); len(ids) > 0 {
+		return nil, p.dependentFunctionError(
+			ctx, "function", fnDesc.GetName(), ids[0], "set schema on",
+		)
+	}
+	return &alterFunctionSetSchemaNode{n: n, sc: sc, fnDesc: fnDesc}, nil
+}
+
+func (n *alterFunctionSetSchemaNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	newSchemaName := string(n.n.NewSchemaName)
+	if newSchemaName == n.sc.GetName() {
+		return nil
+	}
+
+	_, db, err := p.Descriptors().GetImmutableDatabaseByID(
+		ctx, p.txn, n.fnDesc.GetParentID(), tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return err
+	}
+	newSc, err := p.Descriptors().GetMutableSchemaByName(
+		ctx, p.txn, db, newSchemaName, tree.SchemaLookupFlags{Required: true},
+	)
+	if err != nil {
+		return err
+	}
+	newScDesc, ok := newSc.(*schemadesc.Mutable)
+	if !ok || newSc.SchemaKind() != catalog.SchemaUserDefined {
+		return pgerror.Newf(pgcode.InvalidSchemaName,
+			"cannot move function into schema %q", newSchemaName)
+	}
+	if err := p.canCreateOnSchema(
+		ctx, newScDesc.GetID(), db.GetID(), p.User(), checkPublicSchema,
+	); err != nil {
+		return err
+	}
+	if err := checkFunctionConflict(newScDesc, n.fnDesc.GetName(), n.fnDesc); err != nil {
+		return err
+	}
+
+	overload := makeFunctionOverload(n.fnDesc)
+	n.sc.RemoveFunction(n.fnDesc.GetName(), n.fnDesc.GetID())
+	newScDesc.AddFunction(n.fnDesc.GetName(), overload)
+	n.fnDesc.SetParentSchemaID(newScDesc.GetID())
+	if err := p.writeFuncDesc(ctx, n.fnDesc); err != nil {
+		return err
+	}
+	jobDesc := fmt.Sprintf("moving function %s from schema %s to schema %s",
+		n.fnDesc.GetName(), n.sc.GetName(), newSchemaName)
+	if err := p.writeSchemaDescChange(ctx, n.sc, jobDesc); err != nil {
+		return err
+	}
+	return p.writeSchemaDescChange(ctx, newScDesc, jobDesc)
+}
+
+// AlterFunctionSetOwner changes the owner of a user-defined function.
+// Privileges: ownership of the function and membership of the new owner role.
+func (p *planner) AlterFunctionSetOwner(
+	ctx context.Context, n *tree.AlterFunctionSetOwner,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+	_, fnDesc, err := p.resolveFuncObj(ctx, &n.Function, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	return &alterFunctionSetOwnerNode{n: n, fnDesc: fnDesc}, nil
+}
+
+func (n *alterFunctionSetOwnerNode) startExec(params runParams) error {
+	newOwner, err := n.n.NewOwner.ToSQLUsername(params.p.SessionData(), security.UsernameValidation)
+	if err != nil {
+		return err
+	}
+	if err := params.p.checkCanAlterToNewOwner(params.ctx, n.fnDesc, newOwner); err != nil {
+		return err
+	}
+	// If the owner we want to set to is the current owner, do a no-op.
+	if newOwner == n.fnDesc.GetPrivileges().Owner() {
+		return nil
+	}
+	n.fnDesc.GetPrivileges().SetOwner(newOwner)
+	return params.p.writeFuncDesc(params.ctx, n.fnDesc)
+}
+
+// checkFunctionConflict returns an error if the given schema already contains
+// a function with the given name and the same argument types as fnDesc.
+func checkFunctionConflict(
+	sc catalog.SchemaDescriptor, name string, fnDesc catalog.FunctionDescriptor,
+) error {
+	fn, ok := sc.GetFunction(name)
+	if !ok {
+		return nil
+	}
+	for _, ol := range fn.Overloads {
+		if ol.ID != fnDesc.GetID() && argTypesMatch(ol.ArgTypes, fnDesc.ArgTypes()) {
+			return pgerror.Newf(pgcode.DuplicateFunction,
+				"function %s already exists in schema %q", name, sc.GetName())
+		}
+	}
+	return nil
+}
+
+// makeFunctionOverload returns the overload stored in the parent schema of
+// the given function.
+func makeFunctionOverload(fnDesc catalog.FunctionDescriptor) descpb.SchemaDescriptor_FunctionOverload {
+	return descpb.SchemaDescriptor_FunctionOverload{
+		ID:         fnDesc.GetID(),
+		ArgTypes:   fnDesc.ArgTypes(),
+		ReturnType: fnDesc.GetReturnType(),
+		ReturnSet:  fnDesc.GetReturnSet(),
+	}
+}
+
+func (n *alterFunctionRenameNode) Next(runParams) (bool, error)    { return false, nil }
+func (n *alterFunctionRenameNode) Values() tree.Datums             { return tree.Datums{} }
+func (n *alterFunctionRenameNode) Close(context.Context)           {}
+func (n *alterFunctionRenameNode) ReadingOwnWrites()               {}
+func (n *alterFunctionSetSchemaNode) Next(runParams) (bool, error) { return false, nil }
+func (n *alterFunctionSetSchemaNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *alterFunctionSetSchemaNode) Close(context.Context)        {}
+func (n *alterFunctionSetSchemaNode) ReadingOwnWrites()            {}
+func (n *alterFunctionSetOwnerNode) Next(runParams) (bool, error)  { return false, nil }
+func (n *alterFunctionSetOwnerNode) Values() tree.Datums           { return tree.Datums{} }
+func (n *alterFunctionSetOwnerNode) Close(context.Context)         {}
+func (n *alterFunctionSetOwnerNode) ReadingOwnWrites()             {}
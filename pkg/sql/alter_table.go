@@ -1618,11 +1618,41 @@ func dropColumnImpl(
 		droppedViews = append(droppedViews, qualifiedView.FQString())
 	}
 
+	// You can't drop a column used by the body of a function unless CASCADE
+	// was specified, in which case the function is dropped.
+	// Copy out the set of dependencies as it may be overwritten in the loop.
+	fnRefs := append([]descpb.TableDescriptor_Reference(nil), tableDesc.DependedOnByFunctions...)
+	for _, ref := range fnRefs {
+		if !descpb.ColumnIDs(ref.ColumnIDs).Contains(colToDrop.GetID()) {
+			continue
+		}
+		if t.DropBehavior != tree.DropCascade {
+			return nil, params.p.dependentFunctionError(
+				params.ctx, "column", string(t.Column), ref.ID, "drop",
+			)
+		}
+		fnDesc, err := params.p.Descriptors().GetMutableFunctionByID(
+			params.ctx, params.p.txn, ref.ID,
+			params.p.ObjectLookupFlags(true /* required */, true /* requireMutable */),
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := params.p.dropDependentFunction(params.ctx, fnDesc); err != nil {
+			return nil, err
+		}
+	}
+
 	// We cannot remove this column if there are computed columns that use it.
 	if err := schemaexpr.ValidateColumnHasNoDependents(tableDesc, colToDrop); err != nil {
 		return nil, err
 	}
 
+	// We cannot remove this column if there are triggers that use it.
+	if err := checkColumnHasNoTriggerDependents(tableDesc, colToDrop); err != nil {
+		return nil, err
+	}
+
 	if tableDesc.GetPrimaryIndex().CollectKeyColumnIDs().Contains(colToDrop.GetID()) {
 		return nil, pgerror.Newf(pgcode.InvalidColumnReference,
 			"column %q is referenced by the primary key", colToDrop.GetName())
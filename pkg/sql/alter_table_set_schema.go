@@ -86,6 +86,13 @@ func (p *planner) AlterTableSetSchema(
 			)
 		}
 	}
+	// The bodies of functions are stored as text, so they always reference
+	// their dependencies by name.
+	for _, dependent := range tableDesc.DependedOnByFunctions {
+		return nil, p.dependentFunctionError(
+			ctx, string(tableDesc.DescriptorType()), tableDesc.Name, dependent.ID, "set schema on",
+		)
+	}
 
 	return &alterTableSetSchemaNode{
 		newSchema: string(n.Schema),
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
 )
@@ -76,6 +77,17 @@ func (p *planner) AlterType(ctx context.Context, n *tree.AlterType) (planNode, e
 		}
 	case descpb.TypeDescriptor_ENUM:
 		sqltelemetry.IncrementEnumCounter(sqltelemetry.EnumAlter)
+	case descpb.TypeDescriptor_DOMAIN:
+		return nil, unimplemented.NewWithIssuef(27796,
+			"%q is a domain and cannot be modified using the alter type command",
+			tree.AsStringWithFQNames(n.Type, &p.semaCtx.Annotations))
+	case descpb.TypeDescriptor_COMPOSITE:
+		// Composite types can't be modified except for OWNER TO.
+		if _, isAlterTypeOwner := n.Cmd.(*tree.AlterTypeOwner); !isAlterTypeOwner {
+			return nil, unimplemented.NewWithIssuef(27792,
+				"%q is a composite type and cannot be modified",
+				tree.AsStringWithFQNames(n.Type, &p.semaCtx.Annotations))
+		}
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		return nil, pgerror.Newf(
 			pgcode.WrongObjectType,
@@ -63,10 +63,46 @@ func (n *bufferNode) Values() tree.Datums {
 }
 
 func (n *bufferNode) Close(ctx context.Context) {
-	n.plan.Close(ctx)
+	if n.plan != nil {
+		n.plan.Close(ctx)
+	}
 	n.rows.Close(ctx)
 }
 
+// splitRows returns a new bufferNode for each row stored in n, which contains
+// only that row. The returned nodes have no input plan; they are only used to
+// provide the input of post-queries which are executed once for each row (see
+// exec.Cascade.ForEachRow). The caller is responsible for closing them.
+func (n *bufferNode) splitRows(
+	ctx context.Context, evalCtx *extendedEvalContext,
+) (_ []*bufferNode, err error) {
+	res := make([]*bufferNode, 0, n.rows.rows.Len())
+	defer func() {
+		if err != nil {
+			for _, b := range res {
+				b.Close(ctx)
+			}
+		}
+	}()
+	iter := newRowContainerIterator(ctx, n.rows, n.typs)
+	defer iter.Close()
+	for {
+		row, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			return res, nil
+		}
+		b := &bufferNode{typs: n.typs, label: n.label}
+		b.rows.Init(b.typs, evalCtx, redact.Sprint(b.label))
+		res = append(res, b)
+		if err := b.rows.AddRow(ctx, row); err != nil {
+			return nil, err
+		}
+	}
+}
+
 // scanBufferNode behaves like an iterator into the bufferNode it is
 // referencing. The bufferNode can be iterated over multiple times
 // simultaneously, however, a new scanBufferNode is needed.
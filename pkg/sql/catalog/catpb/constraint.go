@@ -36,3 +36,22 @@ var _ redact.SafeValue = ForeignKeyAction(0)
 
 // SafeValue implements redact.SafeValue.
 func (x ForeignKeyAction) SafeValue() {}
+
+// String implements the fmt.Stringer interface.
+func (x ConstraintDeferrability) String() string {
+	switch x {
+	case ConstraintDeferrability_NOT_DEFERRABLE:
+		return "NOT DEFERRABLE"
+	case ConstraintDeferrability_INITIALLY_IMMEDIATE:
+		return "DEFERRABLE INITIALLY IMMEDIATE"
+	case ConstraintDeferrability_INITIALLY_DEFERRED:
+		return "DEFERRABLE INITIALLY DEFERRED"
+	default:
+		return strconv.Itoa(int(x))
+	}
+}
+
+var _ redact.SafeValue = ConstraintDeferrability(0)
+
+// SafeValue implements redact.SafeValue.
+func (x ConstraintDeferrability) SafeValue() {}
@@ -102,6 +102,18 @@ func ValidateColumnDefType(t *types.T) error {
 		}
 		return ValidateColumnDefType(t.ArrayContents())
 
+	case types.TupleFamily:
+		// Only user defined composite types can be used for table columns.
+		if !t.IsComposite() {
+			return pgerror.Newf(pgcode.InvalidTableDefinition,
+				"value type %s cannot be used for table columns", t.String())
+		}
+		for _, typ := range t.TupleContents() {
+			if err := ValidateColumnDefType(typ); err != nil {
+				return err
+			}
+		}
+
 	case types.BitFamily, types.IntFamily, types.FloatFamily, types.BoolFamily, types.BytesFamily, types.DateFamily,
 		types.INetFamily, types.IntervalFamily, types.JsonFamily, types.OidFamily, types.TimeFamily,
 		types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily, types.TimeTZFamily,
@@ -118,6 +130,15 @@ func ValidateColumnDefType(t *types.T) error {
 
 // ColumnTypeIsIndexable returns whether the type t is valid as an indexed column.
 func ColumnTypeIsIndexable(t *types.T) bool {
+	if t.IsComposite() {
+		// Composite types are indexable if all of their elements are.
+		for _, typ := range t.TupleContents() {
+			if !ColumnTypeIsIndexable(typ) {
+				return false
+			}
+		}
+		return true
+	}
 	if t.IsAmbiguous() || t.Family() == types.TupleFamily {
 		return false
 	}
@@ -148,7 +169,16 @@ func MustBeValueEncoded(semanticType *types.T) bool {
 		default:
 			return MustBeValueEncoded(semanticType.ArrayContents())
 		}
-	case types.JsonFamily, types.TupleFamily, types.GeographyFamily, types.GeometryFamily:
+	case types.TupleFamily:
+		if !semanticType.IsComposite() {
+			return true
+		}
+		for _, typ := range semanticType.TupleContents() {
+			if MustBeValueEncoded(typ) {
+				return true
+			}
+		}
+	case types.JsonFamily, types.GeographyFamily, types.GeometryFamily:
 		return true
 	}
 	return false
@@ -68,6 +68,22 @@ var ForeignKeyReferenceActionValue = [...]catpb.ForeignKeyAction{
 	tree.Cascade:    catpb.ForeignKeyAction_CASCADE,
 }
 
+// ConstraintDeferrabilityType allows the conversion between a
+// catpb.ConstraintDeferrability and a tree.ConstraintDeferrability.
+var ConstraintDeferrabilityType = [...]tree.ConstraintDeferrability{
+	catpb.ConstraintDeferrability_NOT_DEFERRABLE:      tree.ConstraintNotDeferrable,
+	catpb.ConstraintDeferrability_INITIALLY_IMMEDIATE: tree.ConstraintInitiallyImmediate,
+	catpb.ConstraintDeferrability_INITIALLY_DEFERRED:  tree.ConstraintInitiallyDeferred,
+}
+
+// ConstraintDeferrabilityValue allows the conversion between a
+// tree.ConstraintDeferrability and a catpb.ConstraintDeferrability.
+var ConstraintDeferrabilityValue = [...]catpb.ConstraintDeferrability{
+	tree.ConstraintNotDeferrable:      catpb.ConstraintDeferrability_NOT_DEFERRABLE,
+	tree.ConstraintInitiallyImmediate: catpb.ConstraintDeferrability_INITIALLY_IMMEDIATE,
+	tree.ConstraintInitiallyDeferred:  catpb.ConstraintDeferrability_INITIALLY_DEFERRED,
+}
+
 // ConstraintType is used to identify the type of a constraint.
 type ConstraintType string
 
@@ -106,3 +122,16 @@ type ConstraintDetail struct {
 	// Only populated for Check Constraints.
 	CheckConstraint *TableDescriptor_CheckConstraint
 }
+
+// Deferrability returns the deferrability of the constraint. Only foreign key
+// and UNIQUE WITHOUT INDEX constraints can be deferrable.
+func (c *ConstraintDetail) Deferrability() catpb.ConstraintDeferrability {
+	switch {
+	case c.FK != nil:
+		return c.FK.Deferrability
+	case c.UniqueWithoutIndexConstraint != nil:
+		return c.UniqueWithoutIndexConstraint.Deferrability
+	default:
+		return catpb.ConstraintDeferrability_NOT_DEFERRABLE
+	}
+}
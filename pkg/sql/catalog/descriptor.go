@@ -45,6 +45,9 @@ const (
 
 	// Schema is for schema descriptors.
 	Schema = "schema"
+
+	// Function is for function descriptors.
+	Function = "function"
 )
 
 // MutationPublicationFilter is used by MakeFirstMutationPublic to filter the
@@ -476,8 +479,8 @@ type TableDescriptor interface {
 	// values when scanned, even if they are marked as not nullable.
 	ReadableColumns() []Column
 	// UserDefinedTypeColumns returns a slice of Column interfaces
-	// containing the table's columns with user defined types, in the
-	// canonical order.
+	// containing the table's columns with user defined types, including
+	// DOMAIN types, in the canonical order.
 	UserDefinedTypeColumns() []Column
 	// SystemColumns returns a slice of Column interfaces
 	// containing the table's system columns, as defined in
@@ -659,6 +662,14 @@ type TableDescriptor interface {
 	// referenced by the returned checks are writable, but not necessarily public.
 	ActiveChecks() []descpb.TableDescriptor_CheckConstraint
 
+	// GetTriggers returns the row-level triggers defined on the table.
+	GetTriggers() []descpb.TableDescriptor_Trigger
+	// GetDependedOnByFunctions returns the user-defined functions whose body
+	// references the table.
+	GetDependedOnByFunctions() []descpb.TableDescriptor_Reference
+	// FindTriggerByName returns the trigger with the given name, if it exists.
+	FindTriggerByName(name string) (*descpb.TableDescriptor_Trigger, bool)
+
 	// GetLocalityConfig returns the locality config for this table, which
 	// describes the table's multi-region locality policy if one is set (e.g.
 	// GLOBAL or REGIONAL BY ROW).
@@ -771,6 +782,60 @@ type TypeDescriptor interface {
 	GetReferencingDescriptorID(refOrdinal int) descpb.ID
 }
 
+// FunctionDescriptor is an interface around the function descriptor types.
+type FunctionDescriptor interface {
+	Descriptor
+
+	// FuncDesc returns the backing protobuf for this function.
+	FuncDesc() *descpb.FunctionDescriptor
+
+	// GetArgs returns the arguments of the function.
+	GetArgs() []descpb.FunctionDescriptor_Argument
+
+	// GetReturnType returns the return type of the function.
+	GetReturnType() *types.T
+
+	// GetReturnSet returns true if the function returns a set of rows.
+	GetReturnSet() bool
+
+	// GetBody returns the SQL body of the function.
+	GetBody() string
+
+	// GetVolatility returns the declared volatility of the function.
+	GetVolatility() descpb.FunctionDescriptor_Volatility
+
+	// GetLeakProof returns true if the function is declared LEAKPROOF.
+	GetLeakProof() bool
+
+	// GetNullInputBehavior returns how the function handles NULL arguments.
+	GetNullInputBehavior() descpb.FunctionDescriptor_NullInputBehavior
+
+	// GetDependsOn returns the IDs of the relations referenced by the body.
+	GetDependsOn() []descpb.ID
+
+	// GetDependsOnTypes returns the IDs of the types referenced by the
+	// function.
+	GetDependsOnTypes() []descpb.ID
+
+	// GetDependsOnFunctions returns the IDs of the functions called by the
+	// body.
+	GetDependsOnFunctions() []descpb.ID
+
+	// GetDependedOnBy returns the relations which depend on the function.
+	GetDependedOnBy() []descpb.FunctionDescriptor_Reference
+
+	// GetDependedOnByFunctions returns the IDs of the functions whose body
+	// calls the function.
+	GetDependedOnByFunctions() []descpb.ID
+
+	// ArgTypes returns the types of the arguments of the function, in order.
+	ArgTypes() []*types.T
+
+	// GetReturnsTrigger returns true if the function is a trigger function,
+	// which can only be executed by triggers.
+	GetReturnsTrigger() bool
+}
+
 // TypeDescriptorResolver is an interface used during hydration of type
 // metadata in types.T's. It is similar to tree.TypeReferenceResolver, except
 // that it has the power to return TypeDescriptor, rather than only a
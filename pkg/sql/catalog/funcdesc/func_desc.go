@@ -0,0 +1,630 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package funcdesc contains the concrete implementations of
+// catalog.FunctionDescriptor.
+package funcdesc
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/oidext"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+	"github.com/lib/pq/oid"
+)
+
+var _ catalog.FunctionDescriptor = (*immutable)(nil)
+var _ catalog.FunctionDescriptor = (*Mutable)(nil)
+var _ catalog.MutableDescriptor = (*Mutable)(nil)
+
+// immutable wraps a Function descriptor and provides methods on it.
+type immutable struct {
+	descpb.FunctionDescriptor
+
+	// isUncommittedVersion is set to true if this descriptor was created from
+	// a copy of a Mutable with an uncommitted version.
+	isUncommittedVersion bool
+
+	// changes represents how the descriptor was changed after
+	// RunPostDeserializationChanges.
+	changes catalog.PostDeserializationChanges
+}
+
+// Mutable is a mutable reference to a FunctionDescriptor.
+type Mutable struct {
+	immutable
+
+	// ClusterVersion represents the version of the function descriptor read
+	// from the store.
+	ClusterVersion *immutable
+}
+
+// NewMutableFunctionDescriptor returns a Mutable for a new function
+// descriptor.
+func NewMutableFunctionDescriptor(
+	id descpb.ID,
+	parentID descpb.ID,
+	parentSchemaID descpb.ID,
+	name string,
+	args []descpb.FunctionDescriptor_Argument,
+	returnType *types.T,
+	returnSet bool,
+	privs *catpb.PrivilegeDescriptor,
+) Mutable {
+	return Mutable{
+		immutable: immutable{
+			FunctionDescriptor: descpb.FunctionDescriptor{
+				Name:           name,
+				ID:             id,
+				ParentID:       parentID,
+				ParentSchemaID: parentSchemaID,
+				Args:           args,
+				ReturnType:     returnType,
+				ReturnSet:      returnSet,
+				Lang:           descpb.FunctionDescriptor_SQL,
+				Volatility:     descpb.FunctionDescriptor_VOLATILE,
+				Privileges:     privs,
+				Version:        1,
+			},
+		},
+	}
+}
+
+var _ redact.SafeMessager = (*immutable)(nil)
+
+// SafeMessage makes immutable a SafeMessager.
+func (desc *immutable) SafeMessage() string {
+	return formatSafeMessage("funcdesc.immutable", desc)
+}
+
+// SafeMessage makes Mutable a SafeMessager.
+func (desc *Mutable) SafeMessage() string {
+	return formatSafeMessage("funcdesc.Mutable", desc)
+}
+
+func formatSafeMessage(typeName string, desc catalog.FunctionDescriptor) string {
+	var buf redact.StringBuilder
+	buf.Printf(typeName + ": {")
+	catalog.FormatSafeDescriptorProperties(&buf, desc)
+	buf.Printf("}")
+	return buf.String()
+}
+
+// GetDrainingNames implements the Descriptor interface. Functions are not
+// stored in the namespace table and therefore never have draining names.
+func (desc *immutable) GetDrainingNames() []descpb.NameInfo {
+	return nil
+}
+
+// IsUncommittedVersion implements the Descriptor interface.
+func (desc *immutable) IsUncommittedVersion() bool {
+	return desc.isUncommittedVersion
+}
+
+// GetAuditMode implements the DescriptorProto interface.
+func (desc *immutable) GetAuditMode() descpb.TableDescriptor_AuditMode {
+	return descpb.TableDescriptor_DISABLED
+}
+
+// DescriptorType implements the DescriptorProto interface.
+func (desc *immutable) DescriptorType() catalog.DescriptorType {
+	return catalog.Function
+}
+
+// FuncDesc implements the FunctionDescriptor interface.
+func (desc *immutable) FuncDesc() *descpb.FunctionDescriptor {
+	return &desc.FunctionDescriptor
+}
+
+// Public implements the Descriptor interface.
+func (desc *immutable) Public() bool {
+	return desc.State == descpb.DescriptorState_PUBLIC
+}
+
+// Adding implements the Descriptor interface.
+func (desc *immutable) Adding() bool {
+	return desc.State == descpb.DescriptorState_ADD
+}
+
+// Offline implements the Descriptor interface.
+func (desc *immutable) Offline() bool {
+	return desc.State == descpb.DescriptorState_OFFLINE
+}
+
+// Dropped implements the Descriptor interface.
+func (desc *immutable) Dropped() bool {
+	return desc.State == descpb.DescriptorState_DROP
+}
+
+// DescriptorProto wraps a FunctionDescriptor in a Descriptor.
+func (desc *immutable) DescriptorProto() *descpb.Descriptor {
+	return &descpb.Descriptor{
+		Union: &descpb.Descriptor_Function{
+			Function: &desc.FunctionDescriptor,
+		},
+	}
+}
+
+// ByteSize implements the Descriptor interface.
+func (desc *immutable) ByteSize() int64 {
+	return int64(desc.Size())
+}
+
+// NewBuilder implements the catalog.Descriptor interface.
+//
+// It overrides the wrapper's implementation to deal with the fact that
+// mutable has overridden the definition of IsUncommittedVersion.
+func (desc *Mutable) NewBuilder() catalog.DescriptorBuilder {
+	return newBuilder(desc.FuncDesc(), desc.IsUncommittedVersion(), desc.changes)
+}
+
+// NewBuilder implements the catalog.Descriptor interface.
+func (desc *immutable) NewBuilder() catalog.DescriptorBuilder {
+	return newBuilder(desc.FuncDesc(), desc.IsUncommittedVersion(), desc.changes)
+}
+
+// ArgTypes implements the FunctionDescriptor interface.
+func (desc *immutable) ArgTypes() []*types.T {
+	ret := make([]*types.T, len(desc.Args))
+	for i := range desc.Args {
+		ret[i] = desc.Args[i].Type
+	}
+	return ret
+}
+
+// ValidateSelf implements the catalog.Descriptor interface.
+func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
+	vea.Report(catalog.ValidateName(desc.Name, "function"))
+	if desc.GetID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid ID %d", desc.GetID()))
+	}
+	if desc.GetParentID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid parentID %d", desc.GetParentID()))
+	}
+	if desc.GetParentSchemaID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid parent schema ID %d", desc.GetParentSchemaID()))
+	}
+
+	if desc.Privileges == nil {
+		vea.Report(errors.AssertionFailedf("privileges not set"))
+	} else {
+		vea.Report(catprivilege.Validate(*desc.Privileges, desc, privilege.Function))
+	}
+
+	if desc.ReturnType == nil {
+		vea.Report(errors.AssertionFailedf("return type not set"))
+	}
+	for i, arg := range desc.Args {
+		if arg.Type == nil {
+			vea.Report(errors.AssertionFailedf("type not set for arg %d", i))
+		}
+	}
+	if desc.Body == "" {
+		vea.Report(errors.AssertionFailedf("function body not set"))
+	}
+	if desc.LeakProof && desc.Volatility != descpb.FunctionDescriptor_IMMUTABLE {
+		vea.Report(errors.AssertionFailedf(
+			"leakproof is set for non-immutable function %q (%d)", desc.Name, desc.ID))
+	}
+	if desc.ReturnsTrigger && (len(desc.Args) > 0 || desc.ReturnSet) {
+		vea.Report(errors.AssertionFailedf(
+			"trigger function %q (%d) has arguments or returns a set", desc.Name, desc.ID))
+	}
+}
+
+// GetReferencedDescIDs implements the catalog.Descriptor interface.
+func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
+	ret := catalog.MakeDescriptorIDSet(desc.GetID(), desc.GetParentID(), desc.GetParentSchemaID())
+	for _, id := range desc.DependsOn {
+		ret.Add(id)
+	}
+	for _, id := range desc.DependsOnTypes {
+		ret.Add(id)
+	}
+	for _, id := range desc.DependsOnFunctions {
+		ret.Add(id)
+	}
+	for _, dep := range desc.DependedOnBy {
+		ret.Add(dep.ID)
+	}
+	for _, id := range desc.DependedOnByFunctions {
+		ret.Add(id)
+	}
+	return ret, nil
+}
+
+// ValidateCrossReferences implements the catalog.Descriptor interface.
+func (desc *immutable) ValidateCrossReferences(
+	vea catalog.ValidationErrorAccumulator, vdg catalog.ValidationDescGetter,
+) {
+	// Validate that the parent database exists.
+	dbDesc, err := vdg.GetDatabaseDescriptor(desc.GetParentID())
+	if err != nil {
+		vea.Report(err)
+	} else if dbDesc.Dropped() {
+		vea.Report(errors.AssertionFailedf("parent database %q (%d) is dropped",
+			dbDesc.GetName(), dbDesc.GetID()))
+	}
+
+	// Validate that the parent schema exists and references this function.
+	scDesc, err := vdg.GetSchemaDescriptor(desc.GetParentSchemaID())
+	if err != nil {
+		vea.Report(err)
+	} else if !desc.Dropped() {
+		if scDesc.Dropped() {
+			vea.Report(errors.AssertionFailedf("parent schema %q (%d) is dropped",
+				scDesc.GetName(), scDesc.GetID()))
+		}
+		fn, ok := scDesc.GetFunction(desc.GetName())
+		found := false
+		if ok {
+			for _, ol := range fn.Overloads {
+				if ol.ID == desc.GetID() {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			vea.Report(errors.AssertionFailedf("function does not exist in schema %q (%d)",
+				scDesc.GetName(), scDesc.GetID()))
+		}
+	}
+
+	for _, id := range desc.DependsOn {
+		tbl, err := vdg.GetTableDescriptor(id)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		found := false
+		for _, by := range tbl.GetDependedOnByFunctions() {
+			if by.ID == desc.GetID() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			vea.Report(errors.AssertionFailedf(
+				"depends-on relation %q (%d) has no corresponding depended-on-by back reference",
+				tbl.GetName(), tbl.GetID()))
+		}
+	}
+	for _, id := range desc.DependsOnTypes {
+		if _, err := vdg.GetTypeDescriptor(id); err != nil {
+			vea.Report(err)
+		}
+	}
+	for _, dep := range desc.DependedOnBy {
+		tbl, err := vdg.GetTableDescriptor(dep.ID)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		if !desc.ReturnsTrigger {
+			continue
+		}
+		found := false
+		for _, trig := range tbl.GetTriggers() {
+			if trig.FuncID == desc.GetID() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			vea.Report(errors.AssertionFailedf(
+				"depended-on-by relation %q (%d) has no trigger referencing this function",
+				tbl.GetName(), tbl.GetID()))
+		}
+	}
+	for _, id := range desc.DependsOnFunctions {
+		fn, err := vdg.GetFunctionDescriptor(id)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		if !containsID(fn.GetDependedOnByFunctions(), desc.GetID()) {
+			vea.Report(errors.AssertionFailedf(
+				"depends-on function %q (%d) has no corresponding depended-on-by back reference",
+				fn.GetName(), fn.GetID()))
+		}
+	}
+	for _, id := range desc.DependedOnByFunctions {
+		fn, err := vdg.GetFunctionDescriptor(id)
+		if err != nil {
+			vea.Report(err)
+			continue
+		}
+		if !containsID(fn.GetDependsOnFunctions(), desc.GetID()) {
+			vea.Report(errors.AssertionFailedf(
+				"depended-on-by function %q (%d) has no corresponding depends-on forward reference",
+				fn.GetName(), fn.GetID()))
+		}
+	}
+}
+
+func containsID(ids []descpb.ID, id descpb.ID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTxnCommit implements the catalog.Descriptor interface.
+func (desc *immutable) ValidateTxnCommit(
+	_ catalog.ValidationErrorAccumulator, _ catalog.ValidationDescGetter,
+) {
+	// No-op.
+}
+
+// GetPostDeserializationChanges implements the Descriptor interface.
+func (desc *immutable) GetPostDeserializationChanges() catalog.PostDeserializationChanges {
+	return desc.changes
+}
+
+// HasConcurrentSchemaChanges implements catalog.Descriptor.
+func (desc *immutable) HasConcurrentSchemaChanges() bool {
+	return desc.DeclarativeSchemaChangerState != nil &&
+		desc.DeclarativeSchemaChangerState.JobID != catpb.InvalidJobID
+}
+
+// IsUncommittedVersion implements the Descriptor interface.
+func (desc *Mutable) IsUncommittedVersion() bool {
+	return desc.IsNew() || desc.GetVersion() != desc.ClusterVersion.GetVersion()
+}
+
+// MaybeIncrementVersion implements the MutableDescriptor interface.
+func (desc *Mutable) MaybeIncrementVersion() {
+	// Already incremented, no-op.
+	if desc.ClusterVersion == nil || desc.Version == desc.ClusterVersion.Version+1 {
+		return
+	}
+	desc.Version++
+	desc.ModificationTime = hlc.Timestamp{}
+}
+
+// SetDrainingNames implements the MutableDescriptor interface.
+//
+// Deprecated: Do not use.
+func (desc *Mutable) SetDrainingNames(_ []descpb.NameInfo) {}
+
+// AddDrainingName implements the MutableDescriptor interface.
+//
+// Deprecated: Do not use.
+func (desc *Mutable) AddDrainingName(_ descpb.NameInfo) {}
+
+// OriginalName implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalName() string {
+	if desc.ClusterVersion == nil {
+		return ""
+	}
+	return desc.ClusterVersion.Name
+}
+
+// OriginalID implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalID() descpb.ID {
+	if desc.ClusterVersion == nil {
+		return descpb.InvalidID
+	}
+	return desc.ClusterVersion.ID
+}
+
+// OriginalVersion implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalVersion() descpb.DescriptorVersion {
+	if desc.ClusterVersion == nil {
+		return 0
+	}
+	return desc.ClusterVersion.Version
+}
+
+// ImmutableCopy implements the MutableDescriptor interface.
+func (desc *Mutable) ImmutableCopy() catalog.Descriptor {
+	return desc.NewBuilder().BuildImmutable()
+}
+
+// IsNew implements the MutableDescriptor interface.
+func (desc *Mutable) IsNew() bool {
+	return desc.ClusterVersion == nil
+}
+
+// SetPublic implements the MutableDescriptor interface.
+func (desc *Mutable) SetPublic() {
+	desc.State = descpb.DescriptorState_PUBLIC
+	desc.OfflineReason = ""
+}
+
+// SetDropped implements the MutableDescriptor interface.
+func (desc *Mutable) SetDropped() {
+	desc.State = descpb.DescriptorState_DROP
+	desc.OfflineReason = ""
+}
+
+// SetOffline implements the MutableDescriptor interface.
+func (desc *Mutable) SetOffline(reason string) {
+	desc.State = descpb.DescriptorState_OFFLINE
+	desc.OfflineReason = reason
+}
+
+// SetDeclarativeSchemaChangerState implements the MutableDescriptor interface.
+func (desc *Mutable) SetDeclarativeSchemaChangerState(state *scpb.DescriptorState) {
+	desc.DeclarativeSchemaChangerState = state
+}
+
+// SetName sets the name of the function.
+func (desc *Mutable) SetName(name string) {
+	desc.Name = name
+}
+
+// SetParentSchemaID sets the parent schema of the function.
+func (desc *Mutable) SetParentSchemaID(id descpb.ID) {
+	desc.ParentSchemaID = id
+}
+
+// SetBody sets the SQL body of the function.
+func (desc *Mutable) SetBody(body string) {
+	desc.Body = body
+}
+
+// SetVolatility sets the volatility of the function.
+func (desc *Mutable) SetVolatility(v descpb.FunctionDescriptor_Volatility) {
+	desc.Volatility = v
+}
+
+// SetLeakProof sets the leakproof attribute of the function.
+func (desc *Mutable) SetLeakProof(v bool) {
+	desc.LeakProof = v
+}
+
+// SetNullInputBehavior sets the behavior of the function on NULL arguments.
+func (desc *Mutable) SetNullInputBehavior(v descpb.FunctionDescriptor_NullInputBehavior) {
+	desc.NullInputBehavior = v
+}
+
+// SetReturnsTrigger marks the function as a trigger function.
+func (desc *Mutable) SetReturnsTrigger(v bool) {
+	desc.ReturnsTrigger = v
+}
+
+// SetDependsOn sets the relations referenced by the function body.
+func (desc *Mutable) SetDependsOn(ids []descpb.ID) {
+	desc.DependsOn = ids
+}
+
+// SetDependsOnFunctions sets the functions called by the function body.
+func (desc *Mutable) SetDependsOnFunctions(ids []descpb.ID) {
+	desc.DependsOnFunctions = ids
+}
+
+// AddDependedOnBy records that the relation with the given ID depends on the
+// function.
+func (desc *Mutable) AddDependedOnBy(id descpb.ID) {
+	for _, dep := range desc.DependedOnBy {
+		if dep.ID == id {
+			return
+		}
+	}
+	desc.DependedOnBy = append(desc.DependedOnBy, descpb.FunctionDescriptor_Reference{ID: id})
+}
+
+// RemoveDependedOnBy removes the reference from the relation with the given
+// ID.
+func (desc *Mutable) RemoveDependedOnBy(id descpb.ID) {
+	for i := range desc.DependedOnBy {
+		if desc.DependedOnBy[i].ID == id {
+			desc.DependedOnBy = append(desc.DependedOnBy[:i], desc.DependedOnBy[i+1:]...)
+			return
+		}
+	}
+}
+
+// AddDependedOnByFunction records that the body of the function with the
+// given ID calls the function.
+func (desc *Mutable) AddDependedOnByFunction(id descpb.ID) {
+	if !containsID(desc.DependedOnByFunctions, id) {
+		desc.DependedOnByFunctions = append(desc.DependedOnByFunctions, id)
+	}
+}
+
+// RemoveDependedOnByFunction removes the reference from the function with the
+// given ID.
+func (desc *Mutable) RemoveDependedOnByFunction(id descpb.ID) {
+	for i := range desc.DependedOnByFunctions {
+		if desc.DependedOnByFunctions[i] == id {
+			desc.DependedOnByFunctions = append(
+				desc.DependedOnByFunctions[:i], desc.DependedOnByFunctions[i+1:]...)
+			return
+		}
+	}
+}
+
+// VolatilityToProto converts a tree.Volatility to its descriptor
+// representation. The returned bool is true if the volatility is leakproof.
+func VolatilityToProto(v tree.Volatility) (descpb.FunctionDescriptor_Volatility, bool, error) {
+	switch v {
+	case tree.VolatilityLeakProof:
+		return descpb.FunctionDescriptor_IMMUTABLE, true, nil
+	case tree.VolatilityImmutable:
+		return descpb.FunctionDescriptor_IMMUTABLE, false, nil
+	case tree.VolatilityStable:
+		return descpb.FunctionDescriptor_STABLE, false, nil
+	case tree.VolatilityVolatile:
+		return descpb.FunctionDescriptor_VOLATILE, false, nil
+	}
+	return -1, false, errors.AssertionFailedf("unknown volatility: %s", v)
+}
+
+// VolatilityFromProto converts a descriptor volatility to a tree.Volatility.
+func VolatilityFromProto(
+	v descpb.FunctionDescriptor_Volatility, leakProof bool,
+) (tree.Volatility, error) {
+	switch v {
+	case descpb.FunctionDescriptor_IMMUTABLE:
+		if leakProof {
+			return tree.VolatilityLeakProof, nil
+		}
+		return tree.VolatilityImmutable, nil
+	case descpb.FunctionDescriptor_STABLE:
+		return tree.VolatilityStable, nil
+	case descpb.FunctionDescriptor_VOLATILE:
+		return tree.VolatilityVolatile, nil
+	}
+	return 0, errors.AssertionFailedf("unknown volatility: %s", v)
+}
+
+// FuncIDToOID converts a function descriptor ID into the OID of the function.
+// Like user-defined types, user-defined functions are assigned OIDs above
+// CockroachPredefinedOIDMax so they cannot collide with builtins.
+func FuncIDToOID(id descpb.ID) oid.Oid {
+	return oid.Oid(id) + oidext.CockroachPredefinedOIDMax
+}
+
+// UserDefinedFunctionOIDToID converts the OID of a user-defined function into
+// a descriptor ID. The function returns an error if the given OID is less than
+// or equal to CockroachPredefinedOIDMax.
+func UserDefinedFunctionOIDToID(o oid.Oid) (descpb.ID, error) {
+	if descpb.ID(o) <= oidext.CockroachPredefinedOIDMax {
+		return 0, errors.Newf("user-defined function OID %d should be greater "+
+			"than predefined Max: %d.", o, oidext.CockroachPredefinedOIDMax)
+	}
+	return descpb.ID(o) - oidext.CockroachPredefinedOIDMax, nil
+}
+
+// ToOverload returns the tree.Overload used to resolve calls to the given
+// function. The body of the function is inlined by the optimizer, so the
+// overload does not have an evaluation function.
+func ToOverload(desc catalog.FunctionDescriptor) (tree.Overload, error) {
+	volatility, err := VolatilityFromProto(desc.GetVolatility(), desc.GetLeakProof())
+	if err != nil {
+		return tree.Overload{}, err
+	}
+	args := make(tree.ArgTypes, len(desc.GetArgs()))
+	for i, arg := range desc.GetArgs() {
+		args[i].Name = arg.Name
+		args[i].Typ = arg.Type
+	}
+	return tree.Overload{
+		Types:             args,
+		ReturnType:        tree.FixedReturnType(desc.GetReturnType()),
+		Volatility:        volatility,
+		Oid:               FuncIDToOID(desc.GetID()),
+		Body:              desc.GetBody(),
+		CalledOnNullInput: desc.GetNullInputBehavior() == descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT,
+	}, nil
+}
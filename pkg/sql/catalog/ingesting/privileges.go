@@ -78,6 +78,12 @@ func GetIngestingDescriptorPrivileges(
 		if descCoverage == tree.RequestedDescriptors {
 			updatedPrivileges = catpb.NewBasePrivilegeDescriptor(user)
 		}
+	case catalog.FunctionDescriptor:
+		// Same as for types: the users on the ingesting cluster may not match
+		// the ones that were on the cluster that was backed up.
+		if descCoverage == tree.RequestedDescriptors {
+			updatedPrivileges = catpb.NewBasePrivilegeDescriptor(user)
+		}
 	case catalog.DatabaseDescriptor:
 		// If the ingestion is not a cluster restore we cannot know that the users
 		// on the ingesting cluster match the ones that were on the cluster that was
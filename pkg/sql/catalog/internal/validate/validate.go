@@ -218,6 +218,8 @@ func (vea *validationErrorAccumulator) decorate(err error) error {
 			err = errors.Wrapf(err, catalog.Schema+" %q (%d)", name, id)
 		case catalog.Type:
 			err = errors.Wrapf(err, catalog.Type+" %q (%d)", name, id)
+		case catalog.Function:
+			err = errors.Wrapf(err, catalog.Function+" %q (%d)", name, id)
 		default:
 			return err
 		}
@@ -301,12 +303,24 @@ func (vdg *validationDescGetterImpl) GetTypeDescriptor(
 	return descriptor, err
 }
 
+// GetFunctionDescriptor implements the ValidationDescGetter interface.
+func (vdg *validationDescGetterImpl) GetFunctionDescriptor(
+	id descpb.ID,
+) (catalog.FunctionDescriptor, error) {
+	desc, found := vdg.descriptors[id]
+	if !found || desc == nil {
+		return nil, catalog.WrapFunctionDescRefErr(id, catalog.ErrReferencedDescriptorNotFound)
+	}
+	return catalog.AsFunctionDescriptor(desc)
+}
+
 func (vdg *validationDescGetterImpl) addNamespaceEntries(
 	ctx context.Context, descriptors []catalog.Descriptor, vd ValidationDereferencer,
 ) error {
 	reqs := make([]descpb.NameInfo, 0, len(descriptors))
 	for _, desc := range descriptors {
-		if desc == nil {
+		if desc == nil || desc.DescriptorType() == catalog.Function {
+			// Functions are not present in the namespace table.
 			continue
 		}
 		reqs = append(reqs, descpb.NameInfo{
@@ -425,6 +439,11 @@ func validateNamespace(
 	if desc.GetID() == keys.NamespaceTableID || desc.GetID() == keys.DeprecatedNamespaceTableID {
 		return
 	}
+	if desc.DescriptorType() == catalog.Function {
+		// Functions are referenced by their parent schema descriptor rather than
+		// by namespace table entries.
+		return
+	}
 
 	key := descpb.NameInfo{
 		ParentID:       desc.GetParentID(),
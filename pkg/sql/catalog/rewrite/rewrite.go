@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -160,6 +161,14 @@ func TableDescs(
 				table.DependedOnBy = append(table.DependedOnBy, ref)
 			}
 		}
+		origFuncRefs := table.DependedOnByFunctions
+		table.DependedOnByFunctions = nil
+		for _, ref := range origFuncRefs {
+			if refRewrite, ok := descriptorRewrites[ref.ID]; ok {
+				ref.ID = refRewrite.ID
+				table.DependedOnByFunctions = append(table.DependedOnByFunctions, ref)
+			}
+		}
 
 		if table.IsSequence() && table.SequenceOpts.HasOwner() {
 			if ownerRewrite, ok := descriptorRewrites[table.SequenceOpts.SequenceOwner.OwnerTableID]; ok {
@@ -179,6 +188,11 @@ func TableDescs(
 			if err := rewriteIDsInTypesT(col.Type, descriptorRewrites); err != nil {
 				return err
 			}
+			if col.DomainTypeID != descpb.InvalidID {
+				if rewrite, ok := descriptorRewrites[col.DomainTypeID]; ok {
+					col.DomainTypeID = rewrite.ID
+				}
+			}
 			var newUsedSeqRefs []descpb.ID
 			for _, seqID := range col.UsesSequenceIds {
 				if rewrite, ok := descriptorRewrites[seqID]; ok {
@@ -423,11 +437,20 @@ func TypeDescs(types []*typedesc.Mutable, descriptorRewrites jobspb.DescRewriteM
 			if rw, ok := descriptorRewrites[typ.ArrayTypeID]; ok {
 				typ.ArrayTypeID = rw.ID
 			}
+		case descpb.TypeDescriptor_COMPOSITE:
+			// The elements of a composite type are never user defined types, so
+			// only the array type needs to be rewritten.
+			if rw, ok := descriptorRewrites[typ.ArrayTypeID]; ok {
+				typ.ArrayTypeID = rw.ID
+			}
 		case descpb.TypeDescriptor_ALIAS:
 			// We need to rewrite any ID's present in the aliased types.T.
 			if err := rewriteIDsInTypesT(typ.Alias, descriptorRewrites); err != nil {
 				return err
 			}
+		case descpb.TypeDescriptor_DOMAIN:
+			// The base type of a domain is never a user defined type, so there is
+			// nothing to rewrite.
 		default:
 			return errors.AssertionFailedf("unknown type kind %s", t.String())
 		}
@@ -450,6 +473,34 @@ func SchemaDescs(schemas []*schemadesc.Mutable, descriptorRewrites jobspb.DescRe
 		sc.ID = rewrite.ID
 		sc.ParentID = rewrite.ParentID
 
+		// Rewrite the IDs of the functions in the schema, dropping the ones
+		// which are not being restored.
+		for name, fn := range sc.Functions {
+			overloads := fn.Overloads[:0]
+			for _, ol := range fn.Overloads {
+				rw, ok := descriptorRewrites[ol.ID]
+				if !ok {
+					continue
+				}
+				ol.ID = rw.ID
+				for _, typ := range ol.ArgTypes {
+					if err := rewriteIDsInTypesT(typ, descriptorRewrites); err != nil {
+						return err
+					}
+				}
+				if err := rewriteIDsInTypesT(ol.ReturnType, descriptorRewrites); err != nil {
+					return err
+				}
+				overloads = append(overloads, ol)
+			}
+			if len(overloads) == 0 {
+				delete(sc.Functions, name)
+				continue
+			}
+			fn.Overloads = overloads
+			sc.Functions[name] = fn
+		}
+
 		if err := rewriteSchemaChangerState(sc, descriptorRewrites); err != nil {
 			return err
 		}
@@ -457,6 +508,79 @@ func SchemaDescs(schemas []*schemadesc.Mutable, descriptorRewrites jobspb.DescRe
 	return nil
 }
 
+// FunctionDescs rewrites all ID's in the input slice of FunctionDescriptors
+// using the input ID rewrite mapping.
+func FunctionDescs(
+	functions []*funcdesc.Mutable, descriptorRewrites jobspb.DescRewriteMap,
+) error {
+	for _, fn := range functions {
+		rewrite, ok := descriptorRewrites[fn.ID]
+		if !ok {
+			return errors.Errorf("missing rewrite for function %d", fn.ID)
+		}
+		// Reset the version and modification time on this new descriptor.
+		fn.Version = 1
+		fn.ModificationTime = hlc.Timestamp{}
+
+		if err := rewriteSchemaChangerState(fn, descriptorRewrites); err != nil {
+			return err
+		}
+
+		fn.ID = rewrite.ID
+		fn.ParentSchemaID = rewrite.ParentSchemaID
+		fn.ParentID = rewrite.ParentID
+
+		for i := range fn.Args {
+			if err := rewriteIDsInTypesT(fn.Args[i].Type, descriptorRewrites); err != nil {
+				return err
+			}
+		}
+		if err := rewriteIDsInTypesT(fn.ReturnType, descriptorRewrites); err != nil {
+			return err
+		}
+		for i, id := range fn.DependsOn {
+			rw, ok := descriptorRewrites[id]
+			if !ok {
+				return pgerror.Newf(pgcode.UndefinedTable,
+					"cannot restore function %q without referenced relation %d", fn.Name, id)
+			}
+			fn.DependsOn[i] = rw.ID
+		}
+		for i, id := range fn.DependsOnTypes {
+			rw, ok := descriptorRewrites[id]
+			if !ok {
+				return pgerror.Newf(pgcode.UndefinedObject,
+					"cannot restore function %q without referenced type %d", fn.Name, id)
+			}
+			fn.DependsOnTypes[i] = rw.ID
+		}
+		dependedOnBy := fn.DependedOnBy[:0]
+		for _, dep := range fn.DependedOnBy {
+			if rw, ok := descriptorRewrites[dep.ID]; ok {
+				dep.ID = rw.ID
+				dependedOnBy = append(dependedOnBy, dep)
+			}
+		}
+		fn.DependedOnBy = dependedOnBy
+		for i, id := range fn.DependsOnFunctions {
+			rw, ok := descriptorRewrites[id]
+			if !ok {
+				return pgerror.Newf(pgcode.UndefinedFunction,
+					"cannot restore function %q without referenced function %d", fn.Name, id)
+			}
+			fn.DependsOnFunctions[i] = rw.ID
+		}
+		dependedOnByFunctions := fn.DependedOnByFunctions[:0]
+		for _, id := range fn.DependedOnByFunctions {
+			if rw, ok := descriptorRewrites[id]; ok {
+				dependedOnByFunctions = append(dependedOnByFunctions, rw.ID)
+			}
+		}
+		fn.DependedOnByFunctions = dependedOnByFunctions
+	}
+	return nil
+}
+
 // rewriteSchemaChangerState handles rewriting any references to IDs stored in
 // the descriptor's declarative schema changer state.
 func rewriteSchemaChangerState(
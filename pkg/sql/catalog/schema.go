@@ -26,6 +26,15 @@ type SchemaDescriptor interface {
 	// GetDefaultPrivilegeDescriptor returns the default privileges for this
 	// database.
 	GetDefaultPrivilegeDescriptor() DefaultPrivilegeDescriptor
+
+	// GetFunction returns the overloads of the user-defined function with the
+	// given name in this schema, if any.
+	GetFunction(name string) (descpb.SchemaDescriptor_Function, bool)
+
+	// ForEachFunction iterates over the user-defined functions in this schema,
+	// in no particular order. Iteration stops without error if the function
+	// returns iterutil.StopIteration.
+	ForEachFunction(f func(fn descpb.SchemaDescriptor_Function) error) error
 }
 
 // ResolvedSchemaKind is an enum that represents what kind of schema
@@ -25,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
@@ -194,7 +195,13 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 // GetReferencedDescIDs returns the IDs of all descriptors referenced by
 // this descriptor, including itself.
 func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
-	return catalog.MakeDescriptorIDSet(desc.GetID(), desc.GetParentID()), nil
+	ret := catalog.MakeDescriptorIDSet(desc.GetID(), desc.GetParentID())
+	for _, fn := range desc.Functions {
+		for _, ol := range fn.Overloads {
+			ret.Add(ol.ID)
+		}
+	}
+	return ret, nil
 }
 
 // ValidateCrossReferences implements the catalog.Descriptor interface.
@@ -241,6 +248,25 @@ func (desc *immutable) ValidateCrossReferences(
 		vea.Report(errors.AssertionFailedf("not present in parent database [%d] schemas mapping",
 			desc.GetParentID()))
 	}
+
+	// Check that all functions exist and belong to this schema.
+	for name, fn := range desc.Functions {
+		for _, ol := range fn.Overloads {
+			fnDesc, err := vdg.GetFunctionDescriptor(ol.ID)
+			if err != nil {
+				vea.Report(err)
+				continue
+			}
+			if fnDesc.GetName() != name {
+				vea.Report(errors.AssertionFailedf("function %q (%d) is referenced under name %q",
+					fnDesc.GetName(), fnDesc.GetID(), errors.Safe(name)))
+			}
+			if fnDesc.GetParentSchemaID() != desc.GetID() {
+				vea.Report(errors.AssertionFailedf("function %q (%d) does not belong to schema",
+					fnDesc.GetName(), fnDesc.GetID()))
+			}
+		}
+	}
 }
 
 // ValidateTxnCommit implements the catalog.Descriptor interface.
@@ -259,6 +285,27 @@ func (desc *immutable) GetDefaultPrivilegeDescriptor() catalog.DefaultPrivilegeD
 	return catprivilege.MakeDefaultPrivileges(defaultPrivilegeDescriptor)
 }
 
+// GetFunction implements the catalog.SchemaDescriptor interface.
+func (desc *immutable) GetFunction(name string) (descpb.SchemaDescriptor_Function, bool) {
+	fn, ok := desc.Functions[name]
+	return fn, ok
+}
+
+// ForEachFunction implements the catalog.SchemaDescriptor interface.
+func (desc *immutable) ForEachFunction(
+	f func(fn descpb.SchemaDescriptor_Function) error,
+) error {
+	for _, fn := range desc.Functions {
+		if err := f(fn); err != nil {
+			if iterutil.Done(err) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 // GetPostDeserializationChanges implements the Descriptor interface.
 func (desc *immutable) GetPostDeserializationChanges() catalog.PostDeserializationChanges {
 	return desc.changes
@@ -342,6 +389,38 @@ func (desc *Mutable) IsUncommittedVersion() bool {
 	return desc.IsNew() || desc.GetVersion() != desc.ClusterVersion.GetVersion()
 }
 
+// AddFunction adds a user-defined function overload to the schema.
+func (desc *Mutable) AddFunction(name string, overload descpb.SchemaDescriptor_FunctionOverload) {
+	if desc.Functions == nil {
+		desc.Functions = make(map[string]descpb.SchemaDescriptor_Function)
+	}
+	fn := desc.Functions[name]
+	fn.Name = name
+	fn.Overloads = append(fn.Overloads, overload)
+	desc.Functions[name] = fn
+}
+
+// RemoveFunction removes the user-defined function overload with the given
+// ID from the schema.
+func (desc *Mutable) RemoveFunction(name string, id descpb.ID) {
+	fn, ok := desc.Functions[name]
+	if !ok {
+		return
+	}
+	overloads := fn.Overloads[:0]
+	for _, ol := range fn.Overloads {
+		if ol.ID != id {
+			overloads = append(overloads, ol)
+		}
+	}
+	if len(overloads) == 0 {
+		delete(desc.Functions, name)
+		return
+	}
+	fn.Overloads = overloads
+	desc.Functions[name] = fn
+}
+
 // GetMutableDefaultPrivilegeDescriptor returns a catprivilege.Mutable.
 func (desc *Mutable) GetMutableDefaultPrivilegeDescriptor() *catprivilege.Mutable {
 	defaultPrivilegeDescriptor := desc.GetDefaultPrivileges()
@@ -126,3 +126,15 @@ func (p synthetic) HasConcurrentSchemaChanges() bool {
 func (p synthetic) GetDefaultPrivilegeDescriptor() catalog.DefaultPrivilegeDescriptor {
 	return catprivilege.MakeDefaultPrivileges(catprivilege.MakeDefaultPrivilegeDescriptor(catpb.DefaultPrivilegeDescriptor_SCHEMA))
 }
+
+// GetFunction implements the catalog.SchemaDescriptor interface.
+func (p synthetic) GetFunction(name string) (descpb.SchemaDescriptor_Function, bool) {
+	return descpb.SchemaDescriptor_Function{}, false
+}
+
+// ForEachFunction implements the catalog.SchemaDescriptor interface.
+func (p synthetic) ForEachFunction(
+	_ func(fn descpb.SchemaDescriptor_Function) error,
+) error {
+	return nil
+}
@@ -90,7 +90,12 @@ func FormatColumnForDisplay(
 	name := col.GetName()
 	f.FormatNameP(&name)
 	f.WriteByte(' ')
-	f.WriteString(col.GetType().SQLString())
+	if typ := col.GetType(); typ.IsDomain() {
+		// Domains have the SQL representation of their base type.
+		f.WriteString(typ.TypeMeta.Name.FQName())
+	} else {
+		f.WriteString(typ.SQLString())
+	}
 	if col.IsHidden() {
 		f.WriteString(" NOT VISIBLE")
 	}
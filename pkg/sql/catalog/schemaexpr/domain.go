@@ -0,0 +1,105 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// DomainValueName is the name by which the CHECK constraints of a DOMAIN
+// refer to the value being checked.
+const DomainValueName = "value"
+
+// Domain is the definition of a DOMAIN, in a form which can be applied to
+// column definitions.
+type Domain struct {
+	BaseType    tree.ResolvableTypeReference
+	DefaultExpr tree.Expr
+	NotNull     bool
+	Checks      []tree.DomainCheck
+}
+
+// DomainFromCreateDomain returns the definition of the domain created by the
+// given CREATE DOMAIN statement.
+func DomainFromCreateDomain(n *tree.CreateDomain) Domain {
+	return Domain{
+		BaseType:    n.Type,
+		DefaultExpr: n.DefaultExpr,
+		NotNull:     n.NotNull,
+		Checks:      n.Checks,
+	}
+}
+
+// DomainFromType returns the definition of the given DOMAIN type.
+func DomainFromType(typ *types.T) (Domain, error) {
+	data := typ.TypeMeta.DomainData
+	baseType := *typ
+	baseType.TypeMeta = types.UserDefinedTypeMetadata{}
+	d := Domain{
+		BaseType: &baseType,
+		NotNull:  data.NotNull,
+		Checks:   make([]tree.DomainCheck, len(data.CheckConstraints)),
+	}
+	if data.DefaultExpr != nil {
+		expr, err := parser.ParseExpr(*data.DefaultExpr)
+		if err != nil {
+			return Domain{}, err
+		}
+		d.DefaultExpr = expr
+	}
+	for i, c := range data.CheckConstraints {
+		expr, err := parser.ParseExpr(c.Expr)
+		if err != nil {
+			return Domain{}, err
+		}
+		d.Checks[i] = tree.DomainCheck{Name: tree.Name(c.Name), Expr: expr}
+	}
+	return d, nil
+}
+
+// ApplyToColumn replaces the type of the given column definition with the
+// base type of the domain, and adds the constraints of the domain to the
+// column. The DEFAULT expression of the domain is used if the column has
+// none. The constraints are not linked to the domain, so later changes to the
+// domain do not affect the column. This is only used by IMPORT, which cannot
+// create the domains of a dump; columns of a domain type otherwise refer to
+// the domain (see ColumnDescriptor.DomainTypeID).
+func (d Domain) ApplyToColumn(col *tree.ColumnTableDef) error {
+	col.Type = d.BaseType
+	if d.NotNull {
+		col.Nullable.Nullability = tree.NotNull
+	}
+	if !col.HasDefaultExpr() && d.DefaultExpr != nil {
+		col.DefaultExpr.Expr = d.DefaultExpr
+	}
+	for _, c := range d.Checks {
+		expr, err := ReplaceDomainValue(c.Expr, tree.NewUnresolvedName(string(col.Name)))
+		if err != nil {
+			return err
+		}
+		col.CheckExprs = append(col.CheckExprs, tree.ColumnTableDefCheckExpr{Expr: expr})
+	}
+	return nil
+}
+
+// ReplaceDomainValue replaces the references to VALUE in the given CHECK
+// expression of a domain with the given expression.
+func ReplaceDomainValue(expr tree.Expr, replacement tree.Expr) (tree.Expr, error) {
+	return tree.SimpleVisit(expr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if name, ok := expr.(*tree.UnresolvedName); ok && !name.Star &&
+			name.NumParts == 1 && name.Parts[0] == DomainValueName {
+			return false, replacement, nil
+		}
+		return true, expr, nil
+	})
+}
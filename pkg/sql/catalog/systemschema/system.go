@@ -676,6 +676,19 @@ CREATE TABLE system.span_count (
 	CONSTRAINT single_row CHECK (singleton),
 	FAMILY "primary" (singleton, span_count)
 );`
+
+	// NotificationsTableSchema stores the notifications sent with NOTIFY until
+	// they have been delivered to the listening sessions on every node.
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+	id         INT8 NOT NULL DEFAULT unique_rowid(),
+	channel    STRING NOT NULL,
+	payload    STRING NOT NULL,
+	pid        INT8 NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT now(),
+	CONSTRAINT "primary" PRIMARY KEY (id),
+	FAMILY "primary" (id, channel, payload, pid, created_at)
+);`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -2396,6 +2409,30 @@ var (
 			}}
 		},
 	)
+
+	// NotificationsTable is the descriptor for the notifications table.
+	NotificationsTable = registerSystemTable(
+		NotificationsTableSchema,
+		systemTable(
+			catconstants.NotificationsTableName,
+			descpb.InvalidID, // dynamically assigned
+			[]descpb.ColumnDescriptor{
+				{Name: "id", ID: 1, Type: types.Int, DefaultExpr: &uniqueRowIDString},
+				{Name: "channel", ID: 2, Type: types.String},
+				{Name: "payload", ID: 3, Type: types.String},
+				{Name: "pid", ID: 4, Type: types.Int},
+				{Name: "created_at", ID: 5, Type: types.Timestamp, DefaultExpr: &nowString},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:        "primary",
+					ID:          0,
+					ColumnNames: []string{"id", "channel", "payload", "pid", "created_at"},
+					ColumnIDs:   []descpb.ColumnID{1, 2, 3, 4, 5},
+				},
+			},
+			pk("id"),
+		))
 )
 
 type descRefByName struct {
@@ -271,6 +271,10 @@ type Column interface {
 	// GetType returns the column type.
 	GetType() *types.T
 
+	// GetDomainTypeID returns the ID of the DOMAIN type of the column, or
+	// descpb.InvalidID if the column was not declared with a domain type.
+	GetDomainTypeID() descpb.ID
+
 	// IsNullable returns true iff the column allows NULL values.
 	IsNullable() bool
 
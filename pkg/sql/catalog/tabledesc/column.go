@@ -92,6 +92,12 @@ func (w column) GetType() *types.T {
 	return w.desc.Type
 }
 
+// GetDomainTypeID returns the ID of the DOMAIN type of the column, or
+// descpb.InvalidID if the column was not declared with a domain type.
+func (w column) GetDomainTypeID() descpb.ID {
+	return w.desc.DomainTypeID
+}
+
 // IsNullable returns true iff the column allows NULL values.
 func (w column) IsNullable() bool {
 	return w.desc.Nullable
@@ -334,7 +340,7 @@ func newColumnCache(desc *descpb.TableDescriptor, mutations *mutationCache) *col
 		if col.Public() && !col.IsInaccessible() {
 			lazyAllocAppendColumn(&c.accessible, col, numPublic)
 		}
-		if col.HasType() && col.GetType().UserDefined() {
+		if col.HasType() && (col.GetType().UserDefined() || col.GetDomainTypeID() != descpb.InvalidID) {
 			lazyAllocAppendColumn(&c.withUDTs, col, numDeletable)
 		}
 	}
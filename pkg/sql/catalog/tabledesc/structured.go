@@ -548,6 +548,9 @@ func (desc *wrapper) getAllReferencedTypesInTableColumns(
 
 	// Now add all of the column types in the table.
 	addIDsInColumn := func(c *descpb.ColumnDescriptor) error {
+		if c.DomainTypeID != descpb.InvalidID {
+			ids[c.DomainTypeID] = struct{}{}
+		}
 		children, err := typedesc.GetTypeDescriptorClosure(c.Type)
 		if err != nil {
 			return err
@@ -1575,6 +1578,35 @@ func (desc *wrapper) FindFKByName(name string) (*descpb.ForeignKeyConstraint, er
 	return nil, fmt.Errorf("fk %q does not exist", name)
 }
 
+// FindTriggerByName implements the TableDescriptor interface. It returns a
+// pointer to the trigger in the TableDescriptor, so that callers can use it
+// to modify the trigger.
+func (desc *wrapper) FindTriggerByName(name string) (*descpb.TableDescriptor_Trigger, bool) {
+	for i := range desc.Triggers {
+		if desc.Triggers[i].Name == name {
+			return &desc.Triggers[i], true
+		}
+	}
+	return nil, false
+}
+
+// AddTrigger adds a row-level trigger to the table.
+func (desc *Mutable) AddTrigger(trig descpb.TableDescriptor_Trigger) {
+	desc.Triggers = append(desc.Triggers, trig)
+}
+
+// RemoveTrigger removes the row-level trigger with the given name from the
+// table. It returns false if there is no such trigger.
+func (desc *Mutable) RemoveTrigger(name string) bool {
+	for i := range desc.Triggers {
+		if desc.Triggers[i].Name == name {
+			desc.Triggers = append(desc.Triggers[:i], desc.Triggers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // IsPrimaryIndexDefaultRowID returns whether or not the table's primary
 // index is the default primary key on the hidden rowid column.
 func (desc *wrapper) IsPrimaryIndexDefaultRowID() bool {
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -141,6 +142,23 @@ func MakeColumnDefDescs(
 		return nil, err
 	}
 	col.Type = resType
+	if resType.IsDomain() {
+		// A column of a DOMAIN type refers to the domain, whose metadata is
+		// installed in the type of the column when it is hydrated and whose
+		// constraints are enforced when values are written to the column. The
+		// DEFAULT expression of the domain is used if the column has none.
+		col.DomainTypeID, err = typedesc.UserDefinedTypeOIDToID(resType.TypeMeta.DomainData.TypeOID)
+		if err != nil {
+			return nil, err
+		}
+		domain, err := schemaexpr.DomainFromType(resType)
+		if err != nil {
+			return nil, err
+		}
+		if !d.HasDefaultExpr() && domain.DefaultExpr != nil {
+			d.DefaultExpr.Expr = domain.DefaultExpr
+		}
+	}
 
 	if d.HasDefaultExpr() {
 		// Verify the default expression type is compatible with the column type
@@ -675,6 +693,15 @@ func RenameColumnInTable(
 		}
 	}
 
+	// Rename the column in trigger WHEN conditions.
+	for i := range tableDesc.Triggers {
+		if trig := &tableDesc.Triggers[i]; trig.WhenExpr != "" {
+			if err := renameInExpr(&trig.WhenExpr); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Do all of the above renames inside check constraints, computed expressions,
 	// and idx predicates that are in mutations.
 	for i := range tableDesc.Mutations {
@@ -83,6 +83,9 @@ func (desc *wrapper) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
 		for id := range children {
 			ids.Add(id)
 		}
+		if id := col.GetDomainTypeID(); id != descpb.InvalidID {
+			ids.Add(id)
+		}
 		for i := 0; i < col.NumUsesSequences(); i++ {
 			ids.Add(col.GetUsesSequenceID(i))
 		}
@@ -119,6 +122,13 @@ func (desc *wrapper) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
 	for _, ref := range desc.GetDependedOnBy() {
 		ids.Add(ref.ID)
 	}
+	for _, ref := range desc.GetDependedOnByFunctions() {
+		ids.Add(ref.ID)
+	}
+	// Add trigger functions.
+	for i := range desc.Triggers {
+		ids.Add(desc.Triggers[i].FuncID)
+	}
 	// Add sequence dependencies
 	return ids, nil
 }
@@ -231,6 +241,16 @@ func (desc *wrapper) ValidateCrossReferences(
 		}
 	}
 
+	// Check trigger functions.
+	for i := range desc.Triggers {
+		vea.Report(desc.validateTriggerFunctionRef(&desc.Triggers[i], vdg))
+	}
+
+	// Check functions whose body references the table.
+	for _, by := range desc.DependedOnByFunctions {
+		vea.Report(desc.validateInboundFunctionRef(by, vdg))
+	}
+
 	// Check foreign keys.
 	for i := range desc.OutboundFKs {
 		vea.Report(desc.validateOutboundFK(&desc.OutboundFKs[i], vdg))
@@ -289,6 +309,51 @@ func (desc *wrapper) validateOutboundTypeRef(id descpb.ID, vdg catalog.Validatio
 	return nil
 }
 
+func (desc *wrapper) validateTriggerFunctionRef(
+	trig *descpb.TableDescriptor_Trigger, vdg catalog.ValidationDescGetter,
+) error {
+	fn, err := vdg.GetFunctionDescriptor(trig.FuncID)
+	if err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err,
+			"invalid function reference in trigger %q", trig.Name)
+	}
+	if fn.Dropped() {
+		return errors.AssertionFailedf("function %q (%d) of trigger %q is dropped",
+			fn.GetName(), fn.GetID(), trig.Name)
+	}
+	if !fn.GetReturnsTrigger() {
+		return errors.AssertionFailedf("function %q (%d) of trigger %q is not a trigger function",
+			fn.GetName(), fn.GetID(), trig.Name)
+	}
+	for _, by := range fn.GetDependedOnBy() {
+		if by.ID == desc.GetID() {
+			return nil
+		}
+	}
+	return errors.AssertionFailedf("function %q (%d) of trigger %q has no corresponding depended-on-by back reference",
+		fn.GetName(), fn.GetID(), trig.Name)
+}
+
+func (desc *wrapper) validateInboundFunctionRef(
+	by descpb.TableDescriptor_Reference, vdg catalog.ValidationDescGetter,
+) error {
+	fn, err := vdg.GetFunctionDescriptor(by.ID)
+	if err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err, "invalid depended-on-by function back reference")
+	}
+	if fn.Dropped() {
+		return errors.AssertionFailedf("depended-on-by function %q (%d) is dropped",
+			fn.GetName(), fn.GetID())
+	}
+	for _, id := range fn.GetDependsOn() {
+		if id == desc.GetID() {
+			return nil
+		}
+	}
+	return errors.AssertionFailedf("depended-on-by function %q (%d) has no corresponding depends-on forward reference",
+		fn.GetName(), by.ID)
+}
+
 func (desc *wrapper) validateInboundTableRef(
 	by descpb.TableDescriptor_Reference, vdg catalog.ValidationDescGetter,
 ) error {
@@ -605,6 +670,7 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			desc.validateColumnFamilies(columnIDs),
 			desc.validateCheckConstraints(columnIDs),
 			desc.validateUniqueWithoutIndexConstraints(columnIDs),
+			desc.validateTriggers(columnIDs),
 			desc.validateTableIndexes(columnNames, vea),
 			desc.validatePartitioning(),
 		}
@@ -981,6 +1047,36 @@ func (desc *wrapper) validateCheckConstraints(
 	return nil
 }
 
+// validateTriggers validates that the row-level triggers of the table are well
+// formed.
+func (desc *wrapper) validateTriggers(
+	columnIDs map[descpb.ColumnID]*descpb.ColumnDescriptor,
+) error {
+	names := make(map[string]struct{}, len(desc.Triggers))
+	for i := range desc.Triggers {
+		trig := &desc.Triggers[i]
+		if trig.Name == "" {
+			return errors.AssertionFailedf("empty trigger name")
+		}
+		if _, ok := names[trig.Name]; ok {
+			return errors.AssertionFailedf("duplicate trigger name: %q", trig.Name)
+		}
+		names[trig.Name] = struct{}{}
+		if len(trig.Events) == 0 {
+			return errors.AssertionFailedf("trigger %q has no events", trig.Name)
+		}
+		if trig.FuncID == descpb.InvalidID {
+			return errors.AssertionFailedf("trigger %q has no function", trig.Name)
+		}
+		for _, colID := range trig.UpdateColumnIDs {
+			if _, ok := columnIDs[colID]; !ok {
+				return errors.AssertionFailedf("trigger %q contains unknown column \"%d\"", trig.Name, colID)
+			}
+		}
+	}
+	return nil
+}
+
 // validateUniqueWithoutIndexConstraints validates that unique without index
 // constraints are well formed. Checks include validating the column IDs and
 // column names.
@@ -555,6 +555,40 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 		if desc.GetArrayTypeID() != descpb.InvalidID {
 			vea.Report(errors.AssertionFailedf("ALIAS type desc has array type ID %d", desc.GetArrayTypeID()))
 		}
+	case descpb.TypeDescriptor_DOMAIN:
+		if desc.RegionConfig != nil {
+			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
+		}
+		if desc.Domain == nil || desc.Domain.BaseType == nil {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil base type"))
+		} else if desc.Domain.BaseType.UserDefined() {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has user defined base type %s",
+				desc.Domain.BaseType.DebugString()))
+		}
+		if desc.GetArrayTypeID() != descpb.InvalidID {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has array type ID %d", desc.GetArrayTypeID()))
+		}
+	case descpb.TypeDescriptor_COMPOSITE:
+		if desc.RegionConfig != nil {
+			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
+		}
+		if desc.Composite == nil {
+			vea.Report(errors.AssertionFailedf("COMPOSITE type desc has nil composite data"))
+			break
+		}
+		seenLabels := make(map[string]struct{}, len(desc.Composite.Elements))
+		for _, e := range desc.Composite.Elements {
+			if e.ElementType == nil {
+				vea.Report(errors.AssertionFailedf("COMPOSITE type desc element %q has nil type", e.ElementLabel))
+			} else if e.ElementType.UserDefined() {
+				vea.Report(errors.AssertionFailedf("COMPOSITE type desc element %q has user defined type %s",
+					e.ElementLabel, e.ElementType.DebugString()))
+			}
+			if _, ok := seenLabels[e.ElementLabel]; ok {
+				vea.Report(errors.AssertionFailedf("duplicate COMPOSITE type desc element label %q", e.ElementLabel))
+			}
+			seenLabels[e.ElementLabel] = struct{}{}
+		}
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		vea.Report(errors.AssertionFailedf("invalid type descriptor: kind %s should never be serialized or validated", desc.Kind.String()))
 	default:
@@ -657,7 +691,7 @@ func (desc *immutable) ValidateCrossReferences(
 
 	// Validate that the referenced types exist.
 	switch desc.GetKind() {
-	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
+	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM, descpb.TypeDescriptor_COMPOSITE:
 		// Ensure that the referenced array type exists.
 		if typ, err := vdg.GetTypeDescriptor(desc.GetArrayTypeID()); err != nil {
 			vea.Report(errors.Wrapf(err, "arrayTypeID %d does not exist for %q", desc.GetArrayTypeID(), desc.GetKind()))
@@ -798,6 +832,26 @@ func (desc *immutable) MakeTypesT(
 			return nil, err
 		}
 		return desc.Alias, nil
+	case descpb.TypeDescriptor_DOMAIN:
+		// A domain is represented by a copy of its base type which carries the
+		// constraints of the domain in its metadata.
+		typ := *desc.Domain.BaseType
+		if err := desc.HydrateTypeInfoWithName(ctx, &typ, name, res); err != nil {
+			return nil, err
+		}
+		return &typ, nil
+	case descpb.TypeDescriptor_COMPOSITE:
+		contents := make([]*types.T, len(desc.Composite.Elements))
+		labels := make([]string, len(desc.Composite.Elements))
+		for i, e := range desc.Composite.Elements {
+			contents[i] = e.ElementType
+			labels[i] = e.ElementLabel
+		}
+		typ := types.MakeComposite(TypeIDToOID(desc.GetID()), TypeIDToOID(desc.ArrayTypeID), contents, labels)
+		if err := desc.HydrateTypeInfoWithName(ctx, typ, name, res); err != nil {
+			return nil, err
+		}
+		return typ, nil
 	default:
 		return nil, errors.AssertionFailedf("unknown type kind %s", t.String())
 	}
@@ -813,7 +867,10 @@ func EnsureTypeIsHydrated(
 				return err
 			}
 		}
-		return nil
+		// Unlike other tuples, composite types also need their name hydrated.
+		if !t.IsComposite() {
+			return nil
+		}
 	}
 	if !t.UserDefined() || t.IsHydrated() {
 		return nil
@@ -836,14 +893,14 @@ func HydrateTypesInTableDescriptor(
 	ctx context.Context, desc *descpb.TableDescriptor, res catalog.TypeDescriptorResolver,
 ) error {
 	for i := range desc.Columns {
-		if err := EnsureTypeIsHydrated(ctx, desc.Columns[i].Type, res); err != nil {
+		if err := hydrateColumnType(ctx, &desc.Columns[i], res); err != nil {
 			return err
 		}
 	}
 	for i := range desc.Mutations {
 		mut := &desc.Mutations[i]
 		if col := mut.GetColumn(); col != nil {
-			if err := EnsureTypeIsHydrated(ctx, col.Type, res); err != nil {
+			if err := hydrateColumnType(ctx, col, res); err != nil {
 				return err
 			}
 		}
@@ -851,6 +908,22 @@ func HydrateTypesInTableDescriptor(
 	return nil
 }
 
+// hydrateColumnType installs metadata in the type of the given column. The
+// type of a column declared with a DOMAIN type is the base type of the domain,
+// which is hydrated with the metadata of the domain.
+func hydrateColumnType(
+	ctx context.Context, col *descpb.ColumnDescriptor, res catalog.TypeDescriptorResolver,
+) error {
+	if col.DomainTypeID == descpb.InvalidID {
+		return EnsureTypeIsHydrated(ctx, col.Type, res)
+	}
+	name, typDesc, err := res.GetTypeDescriptor(ctx, col.DomainTypeID)
+	if err != nil {
+		return err
+	}
+	return typDesc.HydrateTypeInfoWithName(ctx, col.Type, &name, res)
+}
+
 // HydrateTypeInfoWithName implements the TypeDescriptor interface.
 func (desc *immutable) HydrateTypeInfoWithName(
 	ctx context.Context, typ *types.T, name *tree.TypeName, res catalog.TypeDescriptorResolver,
@@ -890,6 +963,23 @@ func (desc *immutable) HydrateTypeInfoWithName(
 			}
 		}
 		return nil
+	case descpb.TypeDescriptor_DOMAIN:
+		checks := make([]types.DomainCheckConstraint, len(desc.Domain.CheckConstraints))
+		for i, c := range desc.Domain.CheckConstraints {
+			checks[i] = types.DomainCheckConstraint{Name: c.Name, Expr: c.Expr}
+		}
+		typ.TypeMeta.DomainData = &types.DomainMetadata{
+			TypeOID:          TypeIDToOID(desc.GetID()),
+			NotNull:          desc.Domain.NotNull,
+			DefaultExpr:      desc.Domain.DefaultExpr,
+			CheckConstraints: checks,
+		}
+		return nil
+	case descpb.TypeDescriptor_COMPOSITE:
+		if typ.Family() != types.TupleFamily {
+			return errors.New("cannot hydrate a non-tuple type with a composite type descriptor")
+		}
+		return nil
 	default:
 		return errors.AssertionFailedf("unknown type descriptor kind %s", desc.Kind)
 	}
@@ -958,6 +1048,23 @@ func (desc *immutable) IsCompatibleWith(other catalog.TypeDescriptor) error {
 			}
 		}
 		return nil
+	case descpb.TypeDescriptor_COMPOSITE:
+		if other.GetKind() != desc.Kind {
+			return errors.Newf("%q of type %q is not compatible with type %q",
+				other.GetName(), other.GetKind(), desc.Kind)
+		}
+		// The elements of both composite types must be the same.
+		otherElems := other.TypeDesc().Composite.Elements
+		if len(desc.Composite.Elements) != len(otherElems) {
+			return errors.Newf("%q has a differing number of attributes", other.GetName())
+		}
+		for i, e := range desc.Composite.Elements {
+			if e.ElementLabel != otherElems[i].ElementLabel ||
+				!e.ElementType.Identical(otherElems[i].ElementType) {
+				return errors.Newf("%q has differing attribute %q", other.GetName(), e.ElementLabel)
+			}
+		}
+		return nil
 	default:
 		return errors.Newf("compatibility comparison unsupported for type kind %s", desc.Kind.String())
 	}
@@ -1012,8 +1119,8 @@ func (desc *immutable) GetIDClosure() (map[descpb.ID]struct{}, error) {
 		for id := range children {
 			ret[id] = struct{}{}
 		}
-	} else {
-		// Otherwise, take the array type ID.
+	} else if desc.ArrayTypeID != descpb.InvalidID {
+		// Otherwise, take the array type ID. Domains have no array type.
 		ret[desc.ArrayTypeID] = struct{}{}
 	}
 	return ret, nil
@@ -1022,6 +1129,14 @@ func (desc *immutable) GetIDClosure() (map[descpb.ID]struct{}, error) {
 // GetTypeDescriptorClosure returns all type descriptor IDs that are
 // referenced by this input types.T.
 func GetTypeDescriptorClosure(typ *types.T) (map[descpb.ID]struct{}, error) {
+	if typ.IsDomain() {
+		// A domain has the OID of its base type, which is never user defined.
+		id, err := UserDefinedTypeOIDToID(typ.TypeMeta.DomainData.TypeOID)
+		if err != nil {
+			return nil, err
+		}
+		return map[descpb.ID]struct{}{id: {}}, nil
+	}
 	if !typ.UserDefined() {
 		return map[descpb.ID]struct{}{}, nil
 	}
@@ -1044,6 +1159,14 @@ func GetTypeDescriptorClosure(typ *types.T) (map[descpb.ID]struct{}, error) {
 			ret[id] = struct{}{}
 		}
 	case types.TupleFamily:
+		// A composite type references its implicit array type, like enums do.
+		if typ.IsComposite() {
+			id, err := GetUserDefinedArrayTypeDescID(typ)
+			if err != nil {
+				return nil, err
+			}
+			ret[id] = struct{}{}
+		}
 		// If we have a tuple type, collect all types in the contents.
 		for _, elt := range typ.TupleContents() {
 			children, err := GetTypeDescriptorClosure(elt)
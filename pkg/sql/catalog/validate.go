@@ -110,4 +110,7 @@ type ValidationDescGetter interface {
 
 	// GetTypeDescriptor returns the corresponding TypeDescriptor or an error instead.
 	GetTypeDescriptor(id descpb.ID) (TypeDescriptor, error)
+
+	// GetFunctionDescriptor returns the corresponding FunctionDescriptor or an error instead.
+	GetFunctionDescriptor(id descpb.ID) (FunctionDescriptor, error)
 }
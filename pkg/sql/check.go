@@ -236,7 +236,7 @@ func nonMatchingRowQuery(
 // reuse an existing kv.Txn safely.
 func validateForeignKey(
 	ctx context.Context,
-	srcTable *tabledesc.Mutable,
+	srcTable catalog.TableDescriptor,
 	targetTable catalog.TableDescriptor,
 	fk *descpb.ForeignKeyConstraint,
 	ie sqlutil.InternalExecutor,
@@ -262,7 +262,7 @@ func validateForeignKey(
 
 		log.Infof(ctx, "validating MATCH FULL FK %q (%q [%v] -> %q [%v]) with query %q",
 			fk.Name,
-			srcTable.Name, colNames,
+			srcTable.GetName(), colNames,
 			targetTable.GetName(), referencedColumnNames,
 			query,
 		)
@@ -289,7 +289,7 @@ func validateForeignKey(
 
 	log.Infof(ctx, "validating FK %q (%q [%v] -> %q [%v]) with query %q",
 		fk.Name,
-		srcTable.Name, colNames, targetTable.GetName(), referencedColumnNames,
+		srcTable.GetName(), colNames, targetTable.GetName(), referencedColumnNames,
 		query,
 	)
 
@@ -301,7 +301,7 @@ func validateForeignKey(
 	if values.Len() > 0 {
 		return pgerror.WithConstraintName(pgerror.Newf(pgcode.ForeignKeyViolation,
 			"foreign key violation: %q row %s has no match in %q",
-			srcTable.Name, formatValues(colNames, values), targetTable.GetName()), fk.Name)
+			srcTable.GetName(), formatValues(colNames, values), targetTable.GetName()), fk.Name)
 	}
 	return nil
 }
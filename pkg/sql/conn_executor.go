@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/contention/txnidcache"
 	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgnotify"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirecancel"
@@ -665,6 +666,10 @@ func (s *Server) SetupConn(
 		ctx, sdMutIterator, stmtBuf, clientComm, memMetrics, &s.Metrics,
 		s.sqlStats.GetApplicationStats(sd.ApplicationName),
 	)
+	// The planner was initialized by newConnExecutor, so it needs to pick up
+	// the listener too.
+	ex.notifications = args.Notifications
+	ex.planner.notifications = args.Notifications
 	return ConnectionHandler{ex}, nil
 }
 
@@ -1004,6 +1009,10 @@ func (s *Server) newConnExecutorWithTxn(
 	// This allows the InternalExecutor to see schema changes made by the
 	// parent executor.
 	ex.extraTxnState.descCollection.SetSyntheticDescriptors(syntheticDescs)
+
+	// The executor does not commit the transaction, so the notifications sent
+	// by its statements cannot be buffered until commit time.
+	ex.planner.extendedEvalCtx.Notifications = nil
 	return ex
 }
 
@@ -1237,6 +1246,15 @@ type connExecutor struct {
 		// transaction and it is cleared after the transaction is committed.
 		schemaChangeJobRecords map[descpb.ID]*jobs.Record
 
+		// deferredConstraints tracks the modes set with SET CONSTRAINTS and the
+		// deferrable constraints violated in the transaction. The latter are
+		// re-validated before the transaction commits.
+		deferredConstraints deferredConstraintState
+
+		// notifications buffers the notifications sent in the transaction, which
+		// are written to system.notifications before the transaction commits.
+		notifications pendingNotifications
+
 		// atomicAutoRetryCounter keeps track of the which iteration of a transaction
 		// auto-retry we're currently in. It's 0 whenever the transaction state is not
 		// stateOpen.
@@ -1467,6 +1485,10 @@ type connExecutor struct {
 	// pgwire cancellation protocol.
 	queryCancelKey pgwirecancel.BackendKeyData
 
+	// notifications receives the notifications for the channels the session
+	// listens on. It is nil if the session does not support LISTEN.
+	notifications *pgnotify.Listener
+
 	sessionID ClusterWideID
 
 	// activated determines whether activate() was called already.
@@ -1646,6 +1668,9 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 		delete(ex.extraTxnState.schemaChangeJobRecords, k)
 	}
 
+	ex.extraTxnState.deferredConstraints.reset()
+	ex.extraTxnState.notifications.reset()
+
 	ex.extraTxnState.descCollection.ReleaseAll(ctx)
 
 	// Close all portals.
@@ -2673,6 +2698,8 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 		TxnModesSetter:         ex,
 		Jobs:                   &ex.extraTxnState.jobs,
 		SchemaChangeJobRecords: ex.extraTxnState.schemaChangeJobRecords,
+		DeferredConstraints:    &ex.extraTxnState.deferredConstraints,
+		Notifications:          &ex.extraTxnState.notifications,
 		statsProvider:          ex.server.sqlStats,
 		indexUsageStats:        ex.indexUsageStats,
 		statementPreparer:      ex,
@@ -2759,6 +2786,8 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.preparedStatements = ex.getPrepStmtsAccessor()
 	p.sqlCursors = ex.getCursorAccessor()
 	p.createdSequences = ex.getCreatedSequencesAccessor()
+	p.notifications = ex.notifications
+	p.queryCancelKey = ex.queryCancelKey
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -937,6 +937,14 @@ func (ex *connExecutor) commitSQLTransactionInternal(ctx context.Context) error
 	ctx, sp := tracing.EnsureChildSpan(ctx, ex.server.cfg.AmbientCtx.Tracer, "commit sql txn")
 	defer sp.Finish()
 
+	if err := ex.validateDeferredConstraints(ctx); err != nil {
+		return err
+	}
+
+	if err := ex.flushNotifications(ctx); err != nil {
+		return err
+	}
+
 	if err := ex.createJobs(ctx); err != nil {
 		return err
 	}
@@ -101,10 +101,12 @@ func (ex *connExecutor) execSavepointInOpenState(
 	}
 
 	sp := savepoint{
-		name:            s.Name,
-		commitOnRelease: commitOnRelease,
-		kvToken:         token,
-		numDDL:          ex.extraTxnState.numDDL,
+		name:                s.Name,
+		commitOnRelease:     commitOnRelease,
+		kvToken:             token,
+		numDDL:              ex.extraTxnState.numDDL,
+		deferredConstraints: ex.extraTxnState.deferredConstraints.clone(),
+		numNotifications:    ex.extraTxnState.notifications.len(),
 	}
 	savepoints.push(sp)
 	ex.sessionDataStack.PushTopClone()
@@ -295,11 +297,15 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 }
 
 // popSavepointsToIdx pops savepoints and SessionData elements related to
-// the savepoint up to the given idx.
+// the savepoint up to the given idx, and restores the state of deferrable
+// constraints and the notifications saved by the savepoint at idx.
 func (ex *connExecutor) popSavepointsToIdx(stmt tree.Statement, idx int) error {
 	if err := ex.reportSessionDataChanges(func() error {
 		numPoppedElems := len(ex.extraTxnState.savepoints) - idx
 		ex.extraTxnState.savepoints.popToIdx(idx)
+		ex.extraTxnState.deferredConstraints =
+			ex.extraTxnState.savepoints[idx].deferredConstraints.clone()
+		ex.extraTxnState.notifications.truncate(ex.extraTxnState.savepoints[idx].numNotifications)
 		if err := ex.sessionDataStack.PopN(numPoppedElems); err != nil {
 			return err
 		}
@@ -351,6 +357,17 @@ type savepoint struct {
 	// more DDL statements were executed since the savepoint's creation.
 	// TODO(knz): support partial DDL cancellation in pending txns.
 	numDDL int
+
+	// deferredConstraints is a copy of the state of deferrable constraints at
+	// the time the savepoint was created. Rolling back to the savepoint
+	// restores the SET CONSTRAINTS modes and discards the violations which
+	// were deferred since.
+	deferredConstraints deferredConstraintState
+
+	// The number of notifications that had been sent in the transaction (at the
+	// time the savepoint was created). Rolling back to the savepoint discards
+	// the notifications sent since.
+	numNotifications int
 }
 
 type savepointStack []savepoint
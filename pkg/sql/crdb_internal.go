@@ -49,6 +49,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -2401,6 +2402,62 @@ func writeCreateTypeDescRow(
 			tree.NewDString(tree.AsString(node)),      // create_statement
 			enumLabelsDatum,
 		)
+	case descpb.TypeDescriptor_DOMAIN:
+		name, err := tree.NewUnresolvedObjectName(2, [3]string{typeDesc.GetName(), sc}, 0)
+		if err != nil {
+			return false, err
+		}
+		domain := typeDesc.TypeDesc().Domain
+		node := &tree.CreateDomain{
+			TypeName: name,
+			Type:     domain.BaseType,
+			NotNull:  domain.NotNull,
+		}
+		if domain.DefaultExpr != nil {
+			if node.DefaultExpr, err = parser.ParseExpr(*domain.DefaultExpr); err != nil {
+				return false, err
+			}
+		}
+		for _, c := range domain.CheckConstraints {
+			expr, err := parser.ParseExpr(c.Expr)
+			if err != nil {
+				return false, err
+			}
+			node.Checks = append(node.Checks, tree.DomainCheck{Name: tree.Name(c.Name), Expr: expr})
+		}
+		return true, addRow(
+			tree.NewDInt(tree.DInt(db.GetID())),       // database_id
+			tree.NewDString(db.GetName()),             // database_name
+			tree.NewDString(sc),                       // schema_name
+			tree.NewDInt(tree.DInt(typeDesc.GetID())), // descriptor_id
+			tree.NewDString(typeDesc.GetName()),       // descriptor_name
+			tree.NewDString(tree.AsString(node)),      // create_statement
+			tree.DNull,                                // enum_members
+		)
+	case descpb.TypeDescriptor_COMPOSITE:
+		name, err := tree.NewUnresolvedObjectName(2, [3]string{typeDesc.GetName(), sc}, 0)
+		if err != nil {
+			return false, err
+		}
+		node := &tree.CreateType{
+			Variety:  tree.Composite,
+			TypeName: name,
+		}
+		for _, e := range typeDesc.TypeDesc().Composite.Elements {
+			node.CompositeTypeList = append(node.CompositeTypeList, tree.CompositeTypeElem{
+				Label: tree.Name(e.ElementLabel),
+				Type:  e.ElementType,
+			})
+		}
+		return true, addRow(
+			tree.NewDInt(tree.DInt(db.GetID())),       // database_id
+			tree.NewDString(db.GetName()),             // database_name
+			tree.NewDString(sc),                       // schema_name
+			tree.NewDInt(tree.DInt(typeDesc.GetID())), // descriptor_id
+			tree.NewDString(typeDesc.GetName()),       // descriptor_name
+			tree.NewDString(tree.AsString(node)),      // create_statement
+			tree.DNull,                                // enum_members
+		)
 	case descpb.TypeDescriptor_MULTIREGION_ENUM:
 		// Multi-region enums are created implicitly, so we don't have create
 		// statements for them.
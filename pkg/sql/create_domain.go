@@ -0,0 +1,254 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+type createDomainNode struct {
+	n        *tree.CreateDomain
+	typeName *tree.TypeName
+	dbDesc   catalog.DatabaseDescriptor
+	baseType *types.T
+}
+
+// Use to satisfy the linter.
+var _ planNode = &createDomainNode{n: nil}
+
+// CreateDomain creates a DOMAIN, which is stored as a type descriptor of the
+// DOMAIN kind.
+func (p *planner) CreateDomain(ctx context.Context, n *tree.CreateDomain) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE DOMAIN",
+	); err != nil {
+		return nil, err
+	}
+	// Nodes running older versions cannot decode the domain of a column, and
+	// would not enforce its constraints.
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.DomainTypes) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create domains",
+			clusterversion.ByKey(clusterversion.DomainTypes))
+	}
+
+	baseType, err := tree.ResolveType(ctx, n.Type, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return nil, err
+	}
+	if baseType.UserDefined() || baseType.IsDomain() {
+		return nil, unimplemented.NewWithIssuef(27796,
+			"domains over user defined type %s are not supported", baseType.SQLString())
+	}
+
+	// Resolve the desired new type name.
+	typeName, db, err := resolveNewTypeName(p.RunParams(ctx), n.TypeName)
+	if err != nil {
+		return nil, err
+	}
+	n.TypeName.SetAnnotation(&p.semaCtx.Annotations, typeName)
+	return &createDomainNode{
+		n:        n,
+		typeName: typeName,
+		dbDesc:   db,
+		baseType: baseType,
+	}, nil
+}
+
+func (n *createDomainNode) startExec(params runParams) error {
+	domain, err := makeDomainDescriptorPayload(params, n.n, n.typeName, n.baseType)
+	if err != nil {
+		return err
+	}
+
+	schema, err := getCreateTypeParams(params, n.typeName, n.dbDesc)
+	if err != nil {
+		return err
+	}
+
+	id, err := descidgen.GenerateUniqueDescID(
+		params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec,
+	)
+	if err != nil {
+		return err
+	}
+
+	privs := catprivilege.CreatePrivilegesFromDefaultPrivileges(
+		n.dbDesc.GetDefaultPrivilegeDescriptor(),
+		schema.GetDefaultPrivilegeDescriptor(),
+		n.dbDesc.GetID(),
+		params.SessionData().User(),
+		tree.Types,
+		n.dbDesc.GetPrivileges(),
+	)
+
+	// Unlike other user defined types, domains don't have an implicit array
+	// type.
+	typeDesc := typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:           n.typeName.Type(),
+		ID:             id,
+		ParentID:       n.dbDesc.GetID(),
+		ParentSchemaID: schema.GetID(),
+		Kind:           descpb.TypeDescriptor_DOMAIN,
+		Domain:         domain,
+		Version:        1,
+		Privileges:     privs,
+	}).BuildCreatedMutableType()
+
+	if err := params.p.createDescriptorWithID(
+		params.ctx,
+		catalogkeys.MakeObjectNameKey(params.ExecCfg().Codec, n.dbDesc.GetID(), schema.GetID(), n.typeName.Type()),
+		id,
+		typeDesc,
+		n.typeName.String(),
+	); err != nil {
+		return err
+	}
+
+	// Log the event.
+	return params.p.logEvent(params.ctx,
+		typeDesc.GetID(),
+		&eventpb.CreateType{
+			TypeName: n.typeName.FQString(),
+		})
+}
+
+// makeDomainDescriptorPayload validates the DEFAULT and CHECK expressions of
+// a CREATE DOMAIN statement and returns the DOMAIN part of the type
+// descriptor.
+func makeDomainDescriptorPayload(
+	params runParams, n *tree.CreateDomain, typeName *tree.TypeName, baseType *types.T,
+) (*descpb.TypeDescriptor_Domain, error) {
+	domain := &descpb.TypeDescriptor_Domain{
+		BaseType: baseType,
+		NotNull:  n.NotNull,
+	}
+
+	if n.DefaultExpr != nil {
+		if err := checkDomainExprHasNoSubquery(n.DefaultExpr, "DEFAULT expression"); err != nil {
+			return nil, err
+		}
+		typedExpr, err := schemaexpr.SanitizeVarFreeExpr(
+			params.ctx, n.DefaultExpr, baseType, "DEFAULT", params.p.SemaCtx(), tree.VolatilityVolatile,
+		)
+		if err != nil {
+			return nil, err
+		}
+		s := tree.Serialize(typedExpr)
+		domain.DefaultExpr = &s
+	}
+
+	for _, c := range n.Checks {
+		if err := addDomainCheckConstraint(
+			params.ctx, params.p.SemaCtx(), domain, typeName.Type(), c,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return domain, nil
+}
+
+// addDomainCheckConstraint validates the given CHECK constraint of the named
+// domain, and adds it to the domain. A name is generated for the constraint
+// if it has none.
+func addDomainCheckConstraint(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	domain *descpb.TypeDescriptor_Domain,
+	domainName string,
+	c tree.DomainCheck,
+) error {
+	if err := checkDomainExprHasNoSubquery(c.Expr, "check constraint"); err != nil {
+		return err
+	}
+	// The CHECK expression is validated with VALUE replaced by a NULL of the
+	// base type.
+	value := &tree.CastExpr{Expr: tree.DNull, Type: domain.BaseType, SyntaxMode: tree.CastShort}
+	expr, err := schemaexpr.ReplaceDomainValue(c.Expr, value)
+	if err != nil {
+		return err
+	}
+	if tree.ContainsVars(expr) {
+		return pgerror.New(pgcode.Syntax,
+			"cannot use column reference in domain check constraint")
+	}
+	if _, err := schemaexpr.SanitizeVarFreeExpr(
+		ctx, expr, types.Bool, "CHECK", semaCtx, tree.VolatilityImmutable,
+	); err != nil {
+		return err
+	}
+
+	names := make(map[string]struct{}, len(domain.CheckConstraints))
+	for i := range domain.CheckConstraints {
+		names[domain.CheckConstraints[i].Name] = struct{}{}
+	}
+	name := string(c.Name)
+	if name == "" {
+		// Generate a name like Postgres does.
+		name = fmt.Sprintf("%s_check", domainName)
+		for i := 1; ; i++ {
+			if _, ok := names[name]; !ok {
+				break
+			}
+			name = fmt.Sprintf("%s_check%d", domainName, i)
+		}
+	} else if _, ok := names[name]; ok {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"constraint %q for domain %q already exists", name, domainName)
+	}
+	domain.CheckConstraints = append(domain.CheckConstraints, descpb.TypeDescriptor_Domain_CheckConstraint{
+		Name: name,
+		Expr: tree.Serialize(c.Expr),
+	})
+	return nil
+}
+
+// checkDomainExprHasNoSubquery returns an error if the given expression of a
+// domain contains a subquery.
+func checkDomainExprHasNoSubquery(expr tree.Expr, context string) error {
+	found := false
+	if _, err := tree.SimpleVisit(expr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if _, ok := expr.(*tree.Subquery); ok {
+			found = true
+			return false, expr, nil
+		}
+		return true, expr, nil
+	}); err != nil {
+		return err
+	}
+	if found {
+		return pgerror.Newf(pgcode.FeatureNotSupported, "cannot use subquery in %s", context)
+	}
+	return nil
+}
+
+func (n *createDomainNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *createDomainNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *createDomainNode) Close(ctx context.Context)           {}
+func (n *createDomainNode) ReadingOwnWrites()                   {}
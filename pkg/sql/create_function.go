@@ -0,0 +1,350 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+type createFunctionNode struct {
+	n *tree.CreateFunction
+	// planDeps tracks the relations which are referenced by the body of the
+	// function. They are collected by the optimizer when the body is built.
+	planDeps planDependencies
+	// funcDeps holds the IDs of the user-defined functions which are called by
+	// the body of the function.
+	funcDeps []descpb.ID
+}
+
+// funcOptions holds the options of a CREATE FUNCTION statement.
+type funcOptions struct {
+	lang              tree.FunctionLanguage
+	body              string
+	volatility        descpb.FunctionDescriptor_Volatility
+	leakProof         bool
+	nullInputBehavior descpb.FunctionDescriptor_NullInputBehavior
+}
+
+// makeFuncOptions validates the options of a CREATE FUNCTION statement. The
+// body of a trigger function may also consist of a single INSERT, UPSERT,
+// UPDATE or DELETE statement, which is executed by AFTER triggers.
+func makeFuncOptions(options tree.FunctionOptions, returnsTrigger bool) (funcOptions, error) {
+	var res funcOptions
+	var seenLang, seenBody, seenVolatility, seenLeakProof, seenNullInput bool
+	redundant := func(seen *bool) error {
+		if *seen {
+			return pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+		}
+		*seen = true
+		return nil
+	}
+	for _, option := range options {
+		var err error
+		switch t := option.(type) {
+		case tree.FunctionLanguage:
+			err = redundant(&seenLang)
+			res.lang = t
+		case tree.FunctionBodyStr:
+			err = redundant(&seenBody)
+			res.body = string(t)
+		case tree.FunctionVolatility:
+			err = redundant(&seenVolatility)
+			switch t {
+			case tree.FunctionVolatile:
+				res.volatility = descpb.FunctionDescriptor_VOLATILE
+			case tree.FunctionStable:
+				res.volatility = descpb.FunctionDescriptor_STABLE
+			case tree.FunctionImmutable:
+				res.volatility = descpb.FunctionDescriptor_IMMUTABLE
+			}
+		case tree.FunctionLeakproof:
+			err = redundant(&seenLeakProof)
+			res.leakProof = bool(t)
+		case tree.FunctionNullInputBehavior:
+			err = redundant(&seenNullInput)
+			switch t {
+			case tree.FunctionCalledOnNullInput:
+				res.nullInputBehavior = descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT
+			case tree.FunctionReturnsNullOnNullInput:
+				res.nullInputBehavior = descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT
+			case tree.FunctionStrict:
+				res.nullInputBehavior = descpb.FunctionDescriptor_STRICT
+			}
+		default:
+			return funcOptions{}, errors.AssertionFailedf("unknown function option %T", t)
+		}
+		if err != nil {
+			return funcOptions{}, err
+		}
+	}
+
+	if !seenLang {
+		return funcOptions{}, pgerror.New(pgcode.InvalidFunctionDefinition, "no language specified")
+	}
+	if !strings.EqualFold(string(res.lang), string(tree.FunctionLangSQL)) {
+		return funcOptions{}, unimplemented.Newf("user-defined functions",
+			"language %s is not supported", string(res.lang))
+	}
+	if !seenBody {
+		return funcOptions{}, pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified")
+	}
+	if res.leakProof && res.volatility != descpb.FunctionDescriptor_IMMUTABLE {
+		return funcOptions{}, pgerror.New(pgcode.InvalidFunctionDefinition,
+			"cannot set leakproof on function with non-immutable volatility")
+	}
+
+	// The body is inlined into the calling query, so it must consist of a
+	// single SELECT statement. The body of a trigger function is executed for
+	// each row which fires a trigger instead.
+	stmts, err := parser.Parse(res.body)
+	if err != nil {
+		return funcOptions{}, pgerror.Wrap(err, pgcode.InvalidFunctionDefinition,
+			"invalid function body")
+	}
+	if len(stmts) != 1 {
+		return funcOptions{}, unimplemented.Newf("user-defined functions",
+			"function body must consist of a single statement")
+	}
+	switch stmts[0].AST.(type) {
+	case *tree.Select:
+		return res, nil
+	case *tree.Insert, *tree.Update, *tree.Delete:
+		if returnsTrigger {
+			return res, nil
+		}
+	}
+	if returnsTrigger {
+		return funcOptions{}, unimplemented.Newf("user-defined functions",
+			"trigger function body must be a SELECT, INSERT, UPSERT, UPDATE or DELETE "+
+				"statement, found %s", stmts[0].AST.StatementTag())
+	}
+	return funcOptions{}, unimplemented.Newf("user-defined functions",
+		"function body must be a SELECT statement, found %s", stmts[0].AST.StatementTag())
+}
+
+// resolveFuncType resolves a type in the signature of a user-defined function.
+func (p *planner) resolveFuncType(
+	ctx context.Context, ref tree.ResolvableTypeReference,
+) (*types.T, error) {
+	typ, err := tree.ResolveType(ctx, ref, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return nil, err
+	}
+	// Functions do not record references to the types in their signature, so
+	// user-defined types are disallowed for now.
+	if typ.UserDefined() {
+		return nil, unimplemented.Newf("user-defined functions",
+			"user-defined type %s cannot be used in the signature of a function", typ.SQLString())
+	}
+	return typ, nil
+}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	name := n.n.FuncName.Object()
+
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.FunctionDescriptors) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create functions",
+			clusterversion.ByKey(clusterversion.FunctionDescriptors))
+	}
+
+	db, sc, _, err := p.ResolveTargetObject(ctx, n.n.FuncName)
+	if err != nil {
+		return err
+	}
+	if db.GetID() == keys.SystemDatabaseID {
+		return errors.New("cannot create a function in the system database")
+	}
+	switch sc.SchemaKind() {
+	case catalog.SchemaUserDefined:
+	case catalog.SchemaPublic:
+		// The public schema of databases created before the introduction of
+		// descriptor-backed public schemas cannot store functions.
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot create a function in the public schema of database %q, which is not "+
+				"backed by a descriptor", db.GetName())
+	default:
+		return pgerror.Newf(pgcode.InvalidSchemaName,
+			"cannot create a function in schema %q", sc.GetName())
+	}
+	if err := p.canCreateOnSchema(
+		ctx, sc.GetID(), db.GetID(), p.User(), checkPublicSchema,
+	); err != nil {
+		return err
+	}
+	if _, ok := tree.FunDefs[name]; ok {
+		return pgerror.Newf(pgcode.DuplicateFunction,
+			"function %q conflicts with a built-in function", name)
+	}
+
+	returnsTrigger := n.n.ReturnType.IsTrigger()
+	opts, err := makeFuncOptions(n.n.Options, returnsTrigger)
+	if err != nil {
+		return err
+	}
+	if n.n.ReturnType.IsSet {
+		return unimplemented.Newf("user-defined functions", "RETURNS SETOF is not supported")
+	}
+	// Trigger functions do not return a value; the effects of their body are
+	// applied to the row which fires the trigger.
+	returnType := types.Void
+	if returnsTrigger {
+		if len(n.n.Args) > 0 {
+			return pgerror.New(pgcode.InvalidFunctionDefinition,
+				"trigger functions cannot have declared arguments")
+		}
+	} else if returnType, err = p.resolveFuncType(ctx, n.n.ReturnType.Type); err != nil {
+		return err
+	}
+	args := make([]descpb.FunctionDescriptor_Argument, len(n.n.Args))
+	argTypes := make([]*types.T, len(n.n.Args))
+	for i := range n.n.Args {
+		arg := &n.n.Args[i]
+		if argTypes[i], err = p.resolveFuncType(ctx, arg.Type); err != nil {
+			return err
+		}
+		args[i] = descpb.FunctionDescriptor_Argument{Name: string(arg.Name), Type: argTypes[i]}
+	}
+
+	mutSc, err := p.Descriptors().GetMutableDescriptorByID(ctx, p.txn, sc.GetID())
+	if err != nil {
+		return err
+	}
+	scDesc, ok := mutSc.(*schemadesc.Mutable)
+	if !ok {
+		return errors.AssertionFailedf("descriptor for schema %q is not Mutable", sc.GetName())
+	}
+
+	// Look for an existing overload with the same argument types.
+	if fn, ok := scDesc.GetFunction(name); ok {
+		for _, ol := range fn.Overloads {
+			if !argTypesMatch(ol.ArgTypes, argTypes) {
+				continue
+			}
+			if !n.n.IsReplace {
+				return pgerror.Newf(pgcode.DuplicateFunction,
+					"function %q already exists with same argument types", name)
+			}
+			return n.replaceFunction(params, scDesc, ol.ID, args, returnType, returnsTrigger, opts)
+		}
+	}
+
+	id, err := descidgen.GenerateUniqueDescID(ctx, p.ExecCfg().DB, p.ExecCfg().Codec)
+	if err != nil {
+		return err
+	}
+	// As in Postgres, anyone may execute a new function by default.
+	privs := catpb.NewBasePrivilegeDescriptor(p.User())
+	privs.Grant(security.PublicRoleName(), privilege.List{privilege.EXECUTE}, false /* withGrantOption */)
+
+	fnDesc := funcdesc.NewMutableFunctionDescriptor(
+		id, db.GetID(), sc.GetID(), name, args, returnType, false /* returnSet */, privs,
+	)
+	setFuncOptions(&fnDesc, opts)
+	fnDesc.SetReturnsTrigger(returnsTrigger)
+	if err := p.addFunctionDependencies(ctx, &fnDesc, n.planDeps, n.funcDeps); err != nil {
+		return err
+	}
+	if err := p.writeFuncDesc(ctx, &fnDesc); err != nil {
+		return err
+	}
+
+	scDesc.AddFunction(name, descpb.SchemaDescriptor_FunctionOverload{
+		ID:         id,
+		ArgTypes:   argTypes,
+		ReturnType: returnType,
+	})
+	return p.writeSchemaDescChange(ctx, scDesc,
+		fmt.Sprintf("creating function %s in schema %s", name, scDesc.GetName()))
+}
+
+// replaceFunction updates an existing function as part of a CREATE OR REPLACE
+// FUNCTION statement.
+func (n *createFunctionNode) replaceFunction(
+	params runParams,
+	scDesc *schemadesc.Mutable,
+	id descpb.ID,
+	args []descpb.FunctionDescriptor_Argument,
+	returnType *types.T,
+	returnsTrigger bool,
+	opts funcOptions,
+) error {
+	ctx, p := params.ctx, params.p
+	fnDesc, err := p.Descriptors().GetMutableFunctionByID(
+		ctx, p.txn, id, p.ObjectLookupFlags(true /* required */, true /* requireMutable */),
+	)
+	if err != nil {
+		return err
+	}
+	if err := p.checkFunctionOwnership(ctx, fnDesc); err != nil {
+		return err
+	}
+	if !fnDesc.GetReturnType().Identical(returnType) || fnDesc.GetReturnsTrigger() != returnsTrigger {
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"cannot change return type of existing function")
+	}
+	for i := range args {
+		if fnDesc.Args[i].Name != "" && fnDesc.Args[i].Name != args[i].Name {
+			return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"cannot change name of input parameter %q", fnDesc.Args[i].Name)
+		}
+	}
+	fnDesc.Args = args
+	setFuncOptions(fnDesc, opts)
+	// Replace the dependencies of the previous definition with those of the
+	// new one.
+	if err := p.removeFunctionBackReferences(ctx, fnDesc); err != nil {
+		return err
+	}
+	if err := p.addFunctionDependencies(ctx, fnDesc, n.planDeps, n.funcDeps); err != nil {
+		return err
+	}
+	if err := p.writeFuncDesc(ctx, fnDesc); err != nil {
+		return err
+	}
+	// The parent schema is not modified, but its version is bumped so that
+	// the new definition is picked up by queries which resolve the function.
+	return p.writeSchemaDescChange(ctx, scDesc,
+		fmt.Sprintf("replacing function %s in schema %s", fnDesc.GetName(), scDesc.GetName()))
+}
+
+func setFuncOptions(fnDesc *funcdesc.Mutable, opts funcOptions) {
+	fnDesc.SetBody(opts.body)
+	fnDesc.SetVolatility(opts.volatility)
+	fnDesc.SetLeakProof(opts.leakProof)
+	fnDesc.SetNullInputBehavior(opts.nullInputBehavior)
+}
+
+func (n *createFunctionNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *createFunctionNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *createFunctionNode) Close(ctx context.Context)           {}
+func (n *createFunctionNode) ReadingOwnWrites()                   {}
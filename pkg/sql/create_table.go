@@ -648,6 +648,7 @@ func addUniqueWithoutIndexColumnTableDef(
 		string(d.Unique.ConstraintName),
 		[]string{string(d.Name)},
 		"", /* predicate */
+		tree.ConstraintNotDeferrable,
 		ts,
 		validationBehavior,
 	); err != nil {
@@ -685,6 +686,11 @@ func addUniqueWithoutIndexTableDef(
 			"partitioned unique constraints without an index are not supported",
 		)
 	}
+	if err := checkDeferrableConstraintsSupported(
+		ctx, evalCtx.Settings.Version, d.Deferrability,
+	); err != nil {
+		return err
+	}
 
 	// If there is a predicate, validate it.
 	var predicate string
@@ -704,7 +710,7 @@ func addUniqueWithoutIndexTableDef(
 		colNames[i] = string(d.Columns[i].Column)
 	}
 	if err := ResolveUniqueWithoutIndexConstraint(
-		ctx, desc, string(d.Name), colNames, predicate, ts, validationBehavior,
+		ctx, desc, string(d.Name), colNames, predicate, d.Deferrability, ts, validationBehavior,
 	); err != nil {
 		return err
 	}
@@ -724,6 +730,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	constraintName string,
 	colNames []string,
 	predicate string,
+	deferrability tree.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
@@ -777,12 +784,13 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:         constraintName,
-		TableID:      tbl.ID,
-		ColumnIDs:    columnIDs,
-		Predicate:    predicate,
-		Validity:     validity,
-		ConstraintID: tbl.NextConstraintID,
+		Name:          constraintName,
+		TableID:       tbl.ID,
+		ColumnIDs:     columnIDs,
+		Predicate:     predicate,
+		Validity:      validity,
+		ConstraintID:  tbl.NextConstraintID,
+		Deferrability: descpb.ConstraintDeferrabilityValue[deferrability],
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -832,6 +840,11 @@ func ResolveFK(
 	validationBehavior tree.ValidationBehavior,
 	evalCtx *tree.EvalContext,
 ) error {
+	if err := checkDeferrableConstraintsSupported(
+		ctx, evalCtx.Settings.Version, d.Deferrability,
+	); err != nil {
+		return err
+	}
 	var originColSet catalog.TableColSet
 	originCols := make([]catalog.Column, len(d.FromCols))
 	for i, fromCol := range d.FromCols {
@@ -1038,6 +1051,7 @@ func ResolveFK(
 		OnUpdate:            descpb.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               descpb.CompositeKeyMatchMethodValue[d.Match],
 		ConstraintID:        tbl.NextConstraintID,
+		Deferrability:       descpb.ConstraintDeferrabilityValue[d.Deferrability],
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -0,0 +1,290 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+type createTriggerNode struct {
+	n         *tree.CreateTrigger
+	tableDesc *tabledesc.Mutable
+	fnDesc    *funcdesc.Mutable
+}
+
+// CreateTrigger creates a row-level trigger.
+// Privileges: CREATE on table and EXECUTE on the trigger function.
+//   notes: postgres requires TRIGGER on the table and EXECUTE on the function.
+func (p *planner) CreateTrigger(ctx context.Context, n *tree.CreateTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+	// Nodes running older versions ignore the triggers of a table, and would
+	// not execute them.
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.TableTriggers) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create triggers",
+			clusterversion.ByKey(clusterversion.TableTriggers))
+	}
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, true /* required */, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+
+	_, fnDesc, err := p.resolveFuncObj(
+		ctx, &tree.FuncObj{FuncName: n.FuncName, Args: tree.FuncArgs{}}, true, /* required */
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !fnDesc.GetReturnsTrigger() {
+		return nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+			"function %s must return type trigger", tree.Name(fnDesc.GetName()))
+	}
+	if err := p.CheckPrivilege(ctx, fnDesc, privilege.EXECUTE); err != nil {
+		return nil, err
+	}
+
+	return &createTriggerNode{n: n, tableDesc: tableDesc, fnDesc: fnDesc}, nil
+}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	if _, ok := n.tableDesc.FindTriggerByName(string(n.n.Name)); ok {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"trigger %s for relation %s already exists", n.n.Name, tree.Name(n.tableDesc.GetName()))
+	}
+
+	trig := descpb.TableDescriptor_Trigger{
+		Name:   string(n.n.Name),
+		FuncID: n.fnDesc.GetID(),
+	}
+	if n.n.ActionTime == tree.TriggerActionTimeAfter {
+		trig.ActionTime = descpb.TableDescriptor_Trigger_AFTER
+	}
+
+	// BEFORE triggers modify the row with the result of a query, while AFTER
+	// triggers perform a modification of their own.
+	if err := validateTriggerFunctionBody(n.n.ActionTime, n.fnDesc); err != nil {
+		return err
+	}
+
+	seenEvents := make(map[tree.TriggerEventType]struct{}, len(n.n.Events))
+	for _, ev := range n.n.Events {
+		if _, ok := seenEvents[ev.EventType]; ok {
+			return pgerror.New(pgcode.Syntax, "duplicate trigger events specified")
+		}
+		seenEvents[ev.EventType] = struct{}{}
+		switch ev.EventType {
+		case tree.TriggerEventInsert:
+			trig.Events = append(trig.Events, descpb.TableDescriptor_Trigger_INSERT)
+		case tree.TriggerEventUpdate:
+			trig.Events = append(trig.Events, descpb.TableDescriptor_Trigger_UPDATE)
+		case tree.TriggerEventDelete:
+			trig.Events = append(trig.Events, descpb.TableDescriptor_Trigger_DELETE)
+		}
+		for _, colName := range ev.Columns {
+			col, err := n.tableDesc.FindColumnWithName(colName)
+			if err != nil {
+				return err
+			}
+			if !col.Public() || col.IsInaccessible() || col.IsSystemColumn() {
+				return pgerror.Newf(pgcode.InvalidColumnReference,
+					"column %q cannot be referenced by a trigger", colName)
+			}
+			trig.UpdateColumnIDs = append(trig.UpdateColumnIDs, col.GetID())
+		}
+	}
+
+	if n.n.When != nil {
+		when, err := p.validateTriggerWhen(ctx, n.n, n.tableDesc)
+		if err != nil {
+			return err
+		}
+		trig.WhenExpr = when
+	}
+
+	n.tableDesc.AddTrigger(trig)
+	n.fnDesc.AddDependedOnBy(n.tableDesc.GetID())
+	if err := p.writeFuncDesc(ctx, n.fnDesc); err != nil {
+		return err
+	}
+	return p.writeSchemaChange(
+		ctx, n.tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+// validateTriggerFunctionBody returns an error if the body of the given
+// trigger function cannot be executed by a trigger with the given action time.
+func validateTriggerFunctionBody(
+	actionTime tree.TriggerActionTime, fnDesc catalog.FunctionDescriptor,
+) error {
+	stmt, err := parser.ParseOne(fnDesc.GetBody())
+	if err != nil {
+		return err
+	}
+	_, isSelect := stmt.AST.(*tree.Select)
+	switch {
+	case actionTime == tree.TriggerActionTimeBefore && !isSelect:
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"function %s of a BEFORE trigger must have a SELECT statement as its body",
+			tree.Name(fnDesc.GetName()))
+	case actionTime == tree.TriggerActionTimeAfter && isSelect:
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"function %s of an AFTER trigger must have an INSERT, UPSERT, UPDATE or DELETE "+
+				"statement as its body", tree.Name(fnDesc.GetName()))
+	}
+	return nil
+}
+
+// validateTriggerWhen validates the WHEN condition of a CREATE TRIGGER
+// statement, and returns its serialized form. The condition may only reference
+// the columns of the table qualified with NEW or OLD, and must have type BOOL.
+func (p *planner) validateTriggerWhen(
+	ctx context.Context, n *tree.CreateTrigger, tableDesc *tabledesc.Mutable,
+) (string, error) {
+	// Strip the NEW and OLD qualifiers so that the condition can be type
+	// checked against the columns of the table.
+	dequalified, err := tree.SimpleVisit(n.When, func(expr tree.Expr) (bool, tree.Expr, error) {
+		vBase, ok := expr.(tree.VarName)
+		if !ok {
+			return true, expr, nil
+		}
+		v, err := vBase.NormalizeVarName()
+		if err != nil {
+			return false, nil, err
+		}
+		c, ok := v.(*tree.ColumnItem)
+		if !ok || c.TableName == nil || c.TableName.NumParts != 1 {
+			return false, nil, pgerror.Newf(pgcode.InvalidColumnReference,
+				"trigger WHEN condition can only reference columns qualified with NEW or OLD: %s",
+				tree.AsString(v))
+		}
+		switch tree.Name(c.TableName.Parts[0]) {
+		case "new":
+			if !n.HasEvent(tree.TriggerEventInsert) && !n.HasEvent(tree.TriggerEventUpdate) {
+				return false, nil, pgerror.New(pgcode.InvalidColumnReference,
+					"DELETE trigger's WHEN condition cannot reference NEW values")
+			}
+		case "old":
+			if !n.HasEvent(tree.TriggerEventUpdate) && !n.HasEvent(tree.TriggerEventDelete) {
+				return false, nil, pgerror.New(pgcode.InvalidColumnReference,
+					"INSERT trigger's WHEN condition cannot reference OLD values")
+			}
+		default:
+			return false, nil, pgerror.Newf(pgcode.InvalidColumnReference,
+				"trigger WHEN condition can only reference columns qualified with NEW or OLD: %s",
+				tree.AsString(v))
+		}
+		col, err := tableDesc.FindColumnWithName(c.ColumnName)
+		if err != nil {
+			return false, nil, err
+		}
+		if !col.Public() || col.IsInaccessible() || col.IsSystemColumn() {
+			return false, nil, pgerror.Newf(pgcode.InvalidColumnReference,
+				"column %q cannot be referenced by a trigger", c.ColumnName)
+		}
+		return false, &tree.ColumnItem{ColumnName: c.ColumnName}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, _, _, err := schemaexpr.DequalifyAndValidateExpr(
+		ctx,
+		tableDesc,
+		dequalified,
+		types.Bool,
+		"trigger WHEN condition",
+		p.SemaCtx(),
+		tree.VolatilityVolatile,
+		&n.Table,
+	); err != nil {
+		return "", err
+	}
+	return tree.Serialize(n.When), nil
+}
+
+// checkColumnHasNoTriggerDependents returns an error if the given column is
+// referenced by a trigger of the table, either in an UPDATE OF clause or in
+// its WHEN condition.
+func checkColumnHasNoTriggerDependents(
+	tableDesc catalog.TableDescriptor, col catalog.Column,
+) error {
+	for i := range tableDesc.GetTriggers() {
+		trig := &tableDesc.GetTriggers()[i]
+		found := false
+		for _, id := range trig.UpdateColumnIDs {
+			if id == col.GetID() {
+				found = true
+				break
+			}
+		}
+		if !found && trig.WhenExpr != "" {
+			expr, err := parser.ParseExpr(trig.WhenExpr)
+			if err != nil {
+				return errors.NewAssertionErrorWithWrappedErrf(err,
+					"failed to parse WHEN condition of trigger %q", trig.Name)
+			}
+			_, err = tree.SimpleVisit(expr, func(expr tree.Expr) (bool, tree.Expr, error) {
+				if vBase, ok := expr.(tree.VarName); ok {
+					v, err := vBase.NormalizeVarName()
+					if err != nil {
+						return false, nil, err
+					}
+					if c, ok := v.(*tree.ColumnItem); ok && c.ColumnName == col.ColName() {
+						found = true
+					}
+					return false, expr, nil
+				}
+				return true, expr, nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if found {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"cannot drop column %s because trigger %s on table %s depends on it",
+					col.ColName(), tree.Name(trig.Name), tree.Name(tableDesc.GetName())),
+				"drop the trigger first")
+		}
+	}
+	return nil
+}
+
+func (n *createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *createTriggerNode) Close(context.Context)        {}
+func (n *createTriggerNode) ReadingOwnWrites()            {}
@@ -14,11 +14,13 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
@@ -107,6 +109,8 @@ func (n *createTypeNode) startExec(params runParams) error {
 	switch n.n.Variety {
 	case tree.Enum:
 		return params.p.createUserDefinedEnum(params, n)
+	case tree.Composite:
+		return params.p.createUserDefinedComposite(params, n)
 	default:
 		return unimplemented.NewWithIssue(25123, "CREATE TYPE")
 	}
@@ -223,6 +227,14 @@ func CreateEnumArrayTypeDesc(
 	switch t := typDesc.Kind; t {
 	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
 		elemTyp = types.MakeEnum(typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id))
+	case descpb.TypeDescriptor_COMPOSITE:
+		contents := make([]*types.T, len(typDesc.Composite.Elements))
+		labels := make([]string, len(typDesc.Composite.Elements))
+		for i, e := range typDesc.Composite.Elements {
+			contents[i] = e.ElementType
+			labels[i] = e.ElementLabel
+		}
+		elemTyp = types.MakeComposite(typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id), contents, labels)
 	default:
 		return nil, errors.AssertionFailedf("cannot make array type for kind %s", t.String())
 	}
@@ -430,6 +442,100 @@ func (p *planner) createEnumWithID(
 		})
 }
 
+func (p *planner) createUserDefinedComposite(params runParams, n *createTypeNode) error {
+	// Values of composite types use a key encoding which nodes running older
+	// versions cannot decode.
+	if !p.ExecCfg().Settings.Version.IsActive(params.ctx, clusterversion.CompositeTypes) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to create composite types",
+			clusterversion.ByKey(clusterversion.CompositeTypes))
+	}
+
+	// Resolve the types of the attributes and ensure that their names are
+	// unique.
+	elements := make([]descpb.TypeDescriptor_Composite_CompositeElement, len(n.n.CompositeTypeList))
+	seenLabels := make(map[tree.Name]struct{})
+	for i, e := range n.n.CompositeTypeList {
+		if _, ok := seenLabels[e.Label]; ok {
+			return pgerror.Newf(pgcode.DuplicateColumn,
+				"column %q specified more than once", e.Label)
+		}
+		seenLabels[e.Label] = struct{}{}
+		typ, err := tree.ResolveType(params.ctx, e.Type, p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return err
+		}
+		// Composite types which refer to other user defined types would need to
+		// track back-references to them, which is not supported yet.
+		if typ.UserDefined() {
+			return unimplemented.NewWithIssuef(27792,
+				"composite types containing user defined types are not supported: %s", typ.SQLString())
+		}
+		if err := colinfo.ValidateColumnDefType(typ); err != nil {
+			return err
+		}
+		elements[i] = descpb.TypeDescriptor_Composite_CompositeElement{
+			ElementType:  typ,
+			ElementLabel: string(e.Label),
+		}
+	}
+
+	// Generate a stable ID for the new type.
+	id, err := descidgen.GenerateUniqueDescID(
+		params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec,
+	)
+	if err != nil {
+		return err
+	}
+
+	schema, err := getCreateTypeParams(params, n.typeName, n.dbDesc)
+	if err != nil {
+		return err
+	}
+
+	privs := catprivilege.CreatePrivilegesFromDefaultPrivileges(
+		n.dbDesc.GetDefaultPrivilegeDescriptor(),
+		schema.GetDefaultPrivilegeDescriptor(),
+		n.dbDesc.GetID(),
+		params.SessionData().User(),
+		tree.Types,
+		n.dbDesc.GetPrivileges(),
+	)
+	typeDesc := typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:           n.typeName.Type(),
+		ID:             id,
+		ParentID:       n.dbDesc.GetID(),
+		ParentSchemaID: schema.GetID(),
+		Kind:           descpb.TypeDescriptor_COMPOSITE,
+		Composite:      &descpb.TypeDescriptor_Composite{Elements: elements},
+		Version:        1,
+		Privileges:     privs,
+	}).BuildCreatedMutableType()
+
+	// Create the implicit array type for this type before finishing the type.
+	arrayTypeID, err := p.createArrayType(params, n.typeName, typeDesc, n.dbDesc, schema.GetID())
+	if err != nil {
+		return err
+	}
+	typeDesc.ArrayTypeID = arrayTypeID
+
+	if err := p.createDescriptorWithID(
+		params.ctx,
+		catalogkeys.MakeObjectNameKey(params.ExecCfg().Codec, n.dbDesc.GetID(), schema.GetID(), n.typeName.Type()),
+		id,
+		typeDesc,
+		n.typeName.String(),
+	); err != nil {
+		return err
+	}
+
+	return p.logEvent(params.ctx,
+		typeDesc.GetID(),
+		&eventpb.CreateType{
+			TypeName: n.typeName.FQString(),
+		})
+}
+
 func (n *createTypeNode) Next(params runParams) (bool, error) { return false, nil }
 func (n *createTypeNode) Values() tree.Datums                 { return tree.Datums{} }
 func (n *createTypeNode) Close(ctx context.Context)           {}
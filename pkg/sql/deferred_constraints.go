@@ -0,0 +1,356 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// checkDeferrableConstraintsSupported returns an error if a constraint is
+// declared DEFERRABLE before all nodes in the cluster know how to defer its
+// checks.
+func checkDeferrableConstraintsSupported(
+	ctx context.Context, version clusterversion.Handle, d tree.ConstraintDeferrability,
+) error {
+	if d == tree.ConstraintNotDeferrable {
+		return nil
+	}
+	if version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+		return nil
+	}
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"version %v must be finalized to create deferrable constraints",
+		clusterversion.ByKey(clusterversion.DeferrableConstraints))
+}
+
+// constraintKey identifies a constraint of a table. Constraint names are only
+// unique within a table.
+type constraintKey struct {
+	tableID descpb.ID
+	name    string
+}
+
+// deferredViolation describes a row which violated a deferrable constraint
+// while the constraint was deferred.
+type deferredViolation struct {
+	// keyVals are the values of the constraint's columns in the violating row,
+	// as produced by the statement's check query. For foreign keys, they are in
+	// the order of the origin columns.
+	keyVals tree.Datums
+	// err is the error which the statement would have returned if the
+	// constraint had not been deferred. It is returned if the violation still
+	// exists when the constraint is re-checked.
+	err error
+}
+
+// deferredConstraint holds the violations of a deferrable constraint which
+// must be re-checked before the transaction commits.
+type deferredConstraint struct {
+	foreignKey bool
+	// violations is keyed by the formatted key values, so that each key is only
+	// re-checked once.
+	violations map[string]deferredViolation
+}
+
+// deferredConstraintState tracks the state of deferrable constraints in a
+// transaction: the modes set with SET CONSTRAINTS, and the violations whose
+// checks have been postponed until commit time.
+//
+// The zero value is ready to use. A nil *deferredConstraintState never defers
+// any constraint; this is the case for the internal executor.
+type deferredConstraintState struct {
+	// allSet is true if SET CONSTRAINTS ALL was run in the transaction, in
+	// which case allDeferred holds the mode it set.
+	allSet      bool
+	allDeferred bool
+
+	// byConstraint holds the modes set with SET CONSTRAINTS <name>, which take
+	// precedence over the mode set with SET CONSTRAINTS ALL.
+	byConstraint map[constraintKey]bool
+
+	// pending holds the constraints which were violated by statements while
+	// deferred.
+	pending map[constraintKey]*deferredConstraint
+}
+
+// isDeferred returns whether checks of the given constraint are currently
+// deferred until the end of the transaction.
+func (s *deferredConstraintState) isDeferred(key constraintKey, initiallyDeferred bool) bool {
+	if s == nil {
+		return false
+	}
+	if deferred, ok := s.byConstraint[key]; ok {
+		return deferred
+	}
+	if s.allSet {
+		return s.allDeferred
+	}
+	return initiallyDeferred
+}
+
+// maybeDefer records the violation described by err as pending if err is a
+// violation of a deferrable constraint which is currently deferred. It returns
+// true if the violation was deferred, in which case err should be ignored.
+func (s *deferredConstraintState) maybeDefer(err error) bool {
+	if s == nil {
+		return false
+	}
+	var dErr *exec.DeferrableCheckError
+	if !errors.As(err, &dErr) {
+		return false
+	}
+	key := constraintKey{tableID: descpb.ID(dErr.TableID), name: dErr.ConstraintName}
+	if !s.isDeferred(key, dErr.InitiallyDeferred) {
+		return false
+	}
+	if s.pending == nil {
+		s.pending = make(map[constraintKey]*deferredConstraint)
+	}
+	c, ok := s.pending[key]
+	if !ok {
+		c = &deferredConstraint{
+			foreignKey: dErr.ForeignKey,
+			violations: make(map[string]deferredViolation),
+		}
+		s.pending[key] = c
+	}
+	c.violations[fmt.Sprint(dErr.KeyVals)] = deferredViolation{keyVals: dErr.KeyVals, err: err}
+	return true
+}
+
+// setMode implements SET CONSTRAINTS. If keys is empty, the mode applies to
+// all constraints.
+func (s *deferredConstraintState) setMode(keys []constraintKey, deferred bool) {
+	if len(keys) == 0 {
+		s.allSet = true
+		s.allDeferred = deferred
+		s.byConstraint = nil
+		return
+	}
+	if s.byConstraint == nil {
+		s.byConstraint = make(map[constraintKey]bool, len(keys))
+	}
+	for _, key := range keys {
+		s.byConstraint[key] = deferred
+	}
+}
+
+// reset clears the state at the end of a transaction.
+func (s *deferredConstraintState) reset() {
+	*s = deferredConstraintState{}
+}
+
+// clone returns a deep copy of the state. It is used to save the state when a
+// savepoint is created, and to restore it when the savepoint is rolled back.
+func (s *deferredConstraintState) clone() deferredConstraintState {
+	cpy := deferredConstraintState{
+		allSet:      s.allSet,
+		allDeferred: s.allDeferred,
+	}
+	if len(s.byConstraint) > 0 {
+		cpy.byConstraint = make(map[constraintKey]bool, len(s.byConstraint))
+		for key, deferred := range s.byConstraint {
+			cpy.byConstraint[key] = deferred
+		}
+	}
+	if len(s.pending) > 0 {
+		cpy.pending = make(map[constraintKey]*deferredConstraint, len(s.pending))
+		for key, c := range s.pending {
+			violations := make(map[string]deferredViolation, len(c.violations))
+			for k, v := range c.violations {
+				violations[k] = v
+			}
+			cpy.pending[key] = &deferredConstraint{foreignKey: c.foreignKey, violations: violations}
+		}
+	}
+	return cpy
+}
+
+// validate re-checks the violations of the pending constraints for which the
+// filter returns true, and removes them from the pending set. A nil filter
+// selects all pending constraints. The first violation which still exists is
+// returned as an error.
+func (s *deferredConstraintState) validate(
+	ctx context.Context,
+	txn *kv.Txn,
+	descsCol *descs.Collection,
+	ie sqlutil.InternalExecutor,
+	filter func(constraintKey) bool,
+) error {
+	if s == nil || len(s.pending) == 0 {
+		return nil
+	}
+	// Validate the constraints in a deterministic order, so that the error
+	// reported in case of multiple violations is stable.
+	toValidate := make([]constraintKey, 0, len(s.pending))
+	for key := range s.pending {
+		if filter == nil || filter(key) {
+			toValidate = append(toValidate, key)
+		}
+	}
+	sort.Slice(toValidate, func(i, j int) bool {
+		if toValidate[i].tableID != toValidate[j].tableID {
+			return toValidate[i].tableID < toValidate[j].tableID
+		}
+		return toValidate[i].name < toValidate[j].name
+	})
+	for _, key := range toValidate {
+		if err := validateDeferredConstraint(ctx, txn, descsCol, ie, key, s.pending[key]); err != nil {
+			return err
+		}
+		delete(s.pending, key)
+	}
+	return nil
+}
+
+// validateDeferredConstraint re-checks the recorded violations of a deferred
+// constraint. Rather than validating the whole table, the checks of the
+// statements which violated the constraint are replayed for the violating
+// keys only: a key still violates a foreign key if a referencing row with that
+// key remains and no referenced row was added, and a unique constraint if more
+// than one row with that key remains. Constraints on tables which were dropped
+// in the meantime, or which no longer exist, are ignored.
+func validateDeferredConstraint(
+	ctx context.Context,
+	txn *kv.Txn,
+	descsCol *descs.Collection,
+	ie sqlutil.InternalExecutor,
+	key constraintKey,
+	c *deferredConstraint,
+) error {
+	flags := tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{
+		Required:       true,
+		AvoidLeased:    true,
+		IncludeDropped: true,
+	}}
+	tableDesc, err := descsCol.GetImmutableTableByID(ctx, txn, key.tableID, flags)
+	if err != nil {
+		return err
+	}
+	if tableDesc.Dropped() {
+		return nil
+	}
+
+	// Build the query which returns the rows still violating the constraint,
+	// and which is then restricted to each of the violating keys.
+	var query string
+	var colNames []string
+	if c.foreignKey {
+		var fk *descpb.ForeignKeyConstraint
+		for _, other := range tableDesc.AllActiveAndInactiveForeignKeys() {
+			if other.Name == key.name {
+				fk = other
+				break
+			}
+		}
+		if fk == nil {
+			return nil
+		}
+		targetDesc, err := descsCol.GetImmutableTableByID(ctx, txn, fk.ReferencedTableID, flags)
+		if err != nil {
+			return err
+		}
+		if targetDesc.Dropped() {
+			return nil
+		}
+		for _, v := range c.violations {
+			for _, d := range v.keyVals {
+				if d == tree.DNull {
+					// The violation was a MATCH FULL key mixing null and non-null
+					// values, which is not found by the query below. Fall back to
+					// validating the whole table.
+					return validateForeignKey(ctx, tableDesc, targetDesc, fk, ie, txn)
+				}
+			}
+		}
+		query, colNames, err = nonMatchingRowQuery(tableDesc, fk, targetDesc, false /* limitResults */)
+		if err != nil {
+			return err
+		}
+	} else {
+		var uc *descpb.UniqueWithoutIndexConstraint
+		for _, other := range tableDesc.AllActiveAndInactiveUniqueWithoutIndexConstraints() {
+			if other.Name == key.name {
+				uc = other
+				break
+			}
+		}
+		if uc == nil {
+			return nil
+		}
+		query, colNames, err = duplicateRowQuery(
+			tableDesc, uc.ColumnIDs, uc.Predicate, false, /* limitResults */
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Check the keys in a deterministic order, so that the error reported in
+	// case of multiple violations is stable.
+	violationKeys := make([]string, 0, len(c.violations))
+	for k := range c.violations {
+		violationKeys = append(violationKeys, k)
+	}
+	sort.Strings(violationKeys)
+	for _, k := range violationKeys {
+		v := c.violations[k]
+		where := make([]string, len(v.keyVals))
+		args := make([]interface{}, len(v.keyVals))
+		for i := range v.keyVals {
+			where[i] = fmt.Sprintf("v.%s = $%d", tree.NameString(colNames[i]), i+1)
+			args[i] = v.keyVals[i]
+		}
+		keyQuery := fmt.Sprintf(
+			`SELECT 1 FROM (%s) AS v WHERE %s LIMIT 1`, query, strings.Join(where, " AND "),
+		)
+		log.VEventf(ctx, 2, "re-checking deferred constraint %q (%q) with query %q",
+			key.name, tableDesc.GetName(), keyQuery)
+		row, err := ie.QueryRowEx(ctx, "validate deferred constraint", txn,
+			sessiondata.NodeUserSessionDataOverride, keyQuery, args...)
+		if err != nil {
+			return err
+		}
+		if row.Len() > 0 {
+			return v.err
+		}
+	}
+	return nil
+}
+
+// validateDeferredConstraints re-checks all the constraints whose checks were
+// deferred in the current transaction. It is called before the
+// transaction commits.
+func (ex *connExecutor) validateDeferredConstraints(ctx context.Context) error {
+	return ex.extraTxnState.deferredConstraints.validate(
+		ctx,
+		ex.state.mu.txn,
+		&ex.extraTxnState.descCollection,
+		ex.server.cfg.InternalExecutor,
+		nil, /* filter */
+	)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 )
 
 // delegateShowGrants implements SHOW GRANTS which returns grant details for the
@@ -65,6 +66,10 @@ FROM "".information_schema.type_privileges`
 	var cond bytes.Buffer
 	var orderBy string
 
+	if n.Targets != nil && n.Targets.Functions != nil {
+		return nil, unimplemented.Newf("user-defined functions", "SHOW GRANTS ON FUNCTION")
+	}
+
 	if n.Targets != nil && len(n.Targets.Databases) > 0 {
 		// Get grants of database from information_schema.schema_privileges
 		// if the type of target is database.
@@ -1471,6 +1471,32 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 				// No rows were actually modified.
 				continue
 			}
+			if plan.cascades[i].ForEachRow && plan.cascades[i].rowBuffer == nil && numBufferedRows > 1 {
+				// Replace the cascade with one cascade for each buffered row, which
+				// are executed in order (before any cascades they queue). Each of
+				// them counts toward the cascades limit, which is checked before the
+				// buffer is split.
+				if limit := int(planner.SessionData().OptimizerFKCascadesLimit); len(plan.cascades)+numBufferedRows-1 > limit {
+					telemetry.Inc(sqltelemetry.CascadesLimitReached)
+					err := pgerror.Newf(pgcode.TriggeredActionException, "cascades limit (%d) reached", limit)
+					recv.SetError(err)
+					return false
+				}
+				rowBufs, err := buf.(*bufferNode).splitRows(ctx, evalCtxFactory())
+				if err != nil {
+					recv.SetError(err)
+					return false
+				}
+				rowCascades := make([]cascadeMetadata, len(rowBufs))
+				for j := range rowBufs {
+					rowCascades[j].Cascade = plan.cascades[i].Cascade
+					rowCascades[j].Buffer = rowBufs[j]
+					rowCascades[j].rowBuffer = rowBufs[j]
+				}
+				rest := append([]cascadeMetadata(nil), plan.cascades[i+1:]...)
+				plan.cascades = append(append(plan.cascades[:i], rowCascades...), rest...)
+				buf, numBufferedRows = rowBufs[0], 1
+			}
 		}
 
 		log.VEventf(ctx, 2, "executing cascade for constraint %s", plan.cascades[i].FKName)
@@ -1506,6 +1532,11 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 		cp := cascadePlan.(*planComponents)
 		plan.cascades[i].plan = cp.main
 		if len(cp.subqueryPlans) > 0 {
+			if plan.cascades[i].ForEachRow {
+				recv.SetError(unimplemented.NewWithIssuef(28296,
+					"subqueries are not supported in the body of AFTER trigger %s", plan.cascades[i].FKName))
+				return false
+			}
 			recv.SetError(errors.AssertionFailedf("cascades should not have subqueries"))
 			return false
 		}
@@ -1523,6 +1554,8 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 		// In cyclical reference situations, the number of cascading operations can
 		// be arbitrarily large. To avoid OOM, we enforce a limit. This is also a
 		// safeguard in case we have a bug that results in an infinite cascade loop.
+		// The cascades executing AFTER triggers once for each row are counted
+		// too.
 		if limit := int(evalCtx.SessionData().OptimizerFKCascadesLimit); len(plan.cascades) > limit {
 			telemetry.Inc(sqltelemetry.CascadesLimitReached)
 			err := pgerror.Newf(pgcode.TriggeredActionException, "cascades limit (%d) reached", limit)
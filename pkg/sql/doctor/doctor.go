@@ -352,6 +352,9 @@ func descriptorModifiedForInsert(r DescriptorTableRow) ([]byte, error) {
 	case catalog.TypeDescriptor:
 		d.TypeDesc().ModificationTime = hlc.Timestamp{}
 		d.TypeDesc().Version = 1
+	case catalog.FunctionDescriptor:
+		d.FuncDesc().ModificationTime = hlc.Timestamp{}
+		d.FuncDesc().Version = 1
 	case catalog.TableDescriptor:
 		d.TableDesc().ModificationTime = hlc.Timestamp{}
 		d.TableDesc().CreateAsOfTime = hlc.Timestamp{}
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -36,6 +37,7 @@ type dropCascadeState struct {
 	toDeleteByID            map[descpb.ID]*toDelete
 	allTableObjectsToDelete []*tabledesc.Mutable
 	typesToDelete           []*typedesc.Mutable
+	functionsToDelete       []*funcdesc.Mutable
 
 	droppedNames []string
 }
@@ -66,6 +68,20 @@ func (d *dropCascadeState) collectObjectsInSchema(
 	for i := range names {
 		d.objectNamesToDelete = append(d.objectNamesToDelete, &names[i])
 	}
+	if err := schema.ForEachFunction(func(fn descpb.SchemaDescriptor_Function) error {
+		for _, ol := range fn.Overloads {
+			fnDesc, err := p.Descriptors().GetMutableFunctionByID(
+				ctx, p.txn, ol.ID, p.ObjectLookupFlags(true /* required */, true /* requireMutable */),
+			)
+			if err != nil {
+				return err
+			}
+			d.functionsToDelete = append(d.functionsToDelete, fnDesc)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
 	d.schemasToDelete = append(d.schemasToDelete, schemaWithDbDesc{schema: schema, dbDesc: db})
 	return nil
 }
@@ -219,9 +235,27 @@ func (d *dropCascadeState) dropAllCollectedObjects(ctx context.Context, p *plann
 		}
 	}
 
+	// Drop the functions, along with any triggers which execute them. Their
+	// parent schemas are dropped along with them.
+	for _, fn := range d.functionsToDelete {
+		// The function may already have been dropped along with a relation or
+		// function which it depends on.
+		if fn.Dropped() {
+			continue
+		}
+		if err := p.dropFunctionImpl(ctx, fn, tree.DropCascade); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// numObjectsToDelete returns the number of objects collected so far.
+func (d *dropCascadeState) numObjectsToDelete() int {
+	return len(d.objectNamesToDelete) + len(d.functionsToDelete)
+}
+
 func (d *dropCascadeState) canDropType(
 	ctx context.Context, p *planner, typ *typedesc.Mutable,
 ) error {
@@ -0,0 +1,95 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type dropFunctionNode struct {
+	n *tree.DropFunction
+	// toDrop holds the functions to drop, along with their parent schemas.
+	toDrop []functionWithSchema
+}
+
+type functionWithSchema struct {
+	sc *schemadesc.Mutable
+	fn *funcdesc.Mutable
+}
+
+// DropFunction drops user-defined functions.
+// Privileges: ownership of the functions.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+
+	node := &dropFunctionNode{n: n}
+	var seen catalog.DescriptorIDSet
+	for i := range n.Functions {
+		sc, fn, err := p.resolveFuncObj(ctx, &n.Functions[i], !n.IfExists)
+		if err != nil {
+			return nil, err
+		}
+		if fn == nil {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				"function %s does not exist, skipping", tree.AsString(&n.Functions[i])))
+			continue
+		}
+		if err := p.checkFunctionOwnership(ctx, fn); err != nil {
+			return nil, err
+		}
+		// The same function may be named several times in a single statement.
+		if seen.Contains(fn.GetID()) {
+			continue
+		}
+		seen.Add(fn.GetID())
+		node.toDrop = append(node.toDrop, functionWithSchema{sc: sc, fn: fn})
+	}
+	return node, nil
+}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	for _, f := range n.toDrop {
+		// The function may already have been dropped along with another
+		// function which it calls.
+		if f.fn.Dropped() {
+			continue
+		}
+		if err := p.dropFunctionImpl(ctx, f.fn, n.n.DropBehavior); err != nil {
+			return err
+		}
+		f.sc.RemoveFunction(f.fn.GetName(), f.fn.GetID())
+		if err := p.writeSchemaDescChange(ctx, f.sc,
+			fmt.Sprintf("dropping function %s in schema %s", f.fn.GetName(), f.sc.GetName()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *dropFunctionNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *dropFunctionNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *dropFunctionNode) Close(ctx context.Context)           {}
+func (n *dropFunctionNode) ReadingOwnWrites()                   {}
@@ -76,6 +76,7 @@ const (
 	table            objectType = "table"
 	schema           objectType = "schema"
 	typeObject       objectType = "type"
+	function         objectType = "function"
 	defaultPrivilege objectType = "default_privilege"
 )
 
@@ -235,6 +236,31 @@ func (n *DropRoleNode) startExec(params runParams) error {
 				})
 		}
 	}
+	// Functions are not part of the lookup context, since they are not
+	// referenced from the namespace table.
+	for _, desc := range all.OrderedDescriptors() {
+		fnDesc, ok := desc.(catalog.FunctionDescriptor)
+		if !ok || !descriptorIsVisible(fnDesc, true /* allowAdding */) {
+			continue
+		}
+		if _, ok := userNames[fnDesc.GetPrivileges().Owner()]; ok {
+			userNames[fnDesc.GetPrivileges().Owner()] = append(
+				userNames[fnDesc.GetPrivileges().Owner()],
+				objectAndType{
+					ObjectType: function,
+					ObjectName: fnDesc.GetName(),
+				})
+		}
+		for _, u := range fnDesc.GetPrivileges().Users {
+			if _, ok := userNames[u.User()]; ok {
+				if privilegeObjectFormatter.Len() > 0 {
+					privilegeObjectFormatter.WriteString(", ")
+				}
+				privilegeObjectFormatter.FormatName(fnDesc.GetName())
+				break
+			}
+		}
+	}
 
 	// Was there any object depending on that user?
 	if privilegeObjectFormatter.Len() > 0 {
@@ -275,7 +301,7 @@ func (n *DropRoleNode) startExec(params runParams) error {
 			objectsMsg := tree.NewFmtCtx(tree.FmtSimple)
 			for _, obj := range dependentObjects {
 				switch obj.ObjectType {
-				case database, table, schema, typeObject:
+				case database, table, schema, typeObject, function:
 					objectsMsg.WriteString(fmt.Sprintf("\nowner of %s %s", obj.ObjectType, obj.ObjectName))
 				case defaultPrivilege:
 					hasDependentDefaultPrivilege = true
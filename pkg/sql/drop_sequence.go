@@ -121,6 +121,9 @@ func (p *planner) dropSequenceImpl(
 			return err
 		}
 	}
+	if err := p.dropDependentFunctions(ctx, seqDesc); err != nil {
+		return err
+	}
 	return p.initiateDropTable(ctx, seqDesc, queueJob, jobDesc)
 }
 
@@ -130,7 +133,8 @@ func (p *planner) dropSequenceImpl(
 func (p *planner) sequenceDependencyError(
 	ctx context.Context, droppedDesc *tabledesc.Mutable, behavior tree.DropBehavior,
 ) error {
-	if behavior != tree.DropCascade && len(droppedDesc.DependedOnBy) > 0 {
+	if behavior != tree.DropCascade &&
+		(len(droppedDesc.DependedOnBy) > 0 || len(droppedDesc.DependedOnByFunctions) > 0) {
 		return pgerror.Newf(
 			pgcode.DependentObjectsStillExist,
 			"cannot drop sequence %s because other objects depend on it",
@@ -179,6 +183,15 @@ func (p *planner) canRemoveOwnedSequencesImpl(
 			return err
 		}
 
+		// Functions which use the sequence are dropped along with it.
+		if len(seqDesc.GetDependedOnByFunctions()) > 0 && behavior != tree.DropCascade {
+			return pgerror.Newf(
+				pgcode.DependentObjectsStillExist,
+				"cannot drop table %s because other objects depend on it",
+				desc.Name,
+			)
+		}
+
 		var firstDep *descpb.TableDescriptor_Reference
 		multipleIterationErr := seqDesc.ForeachDependedOnBy(func(dep *descpb.TableDescriptor_Reference) error {
 			if firstDep != nil {
@@ -90,6 +90,9 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 				}
 			}
 		}
+		if err := p.canRemoveDependentFunctions(ctx, droppedDesc, n.DropBehavior); err != nil {
+			return nil, err
+		}
 		if err := p.canRemoveAllTableOwnedSequences(ctx, droppedDesc, n.DropBehavior); err != nil {
 			return nil, err
 		}
@@ -254,6 +257,18 @@ func (p *planner) dropTableImpl(
 	}
 	tableDesc.InboundFKs = nil
 
+	// Remove trigger function back references.
+	var triggerFuncIDs catalog.DescriptorIDSet
+	for i := range tableDesc.Triggers {
+		triggerFuncIDs.Add(tableDesc.Triggers[i].FuncID)
+	}
+	tableDesc.Triggers = nil
+	for _, fnID := range triggerFuncIDs.Ordered() {
+		if err := p.removeTriggerFunctionBackReference(ctx, tableDesc, fnID); err != nil {
+			return droppedViews, err
+		}
+	}
+
 	// Remove sequence dependencies.
 	for _, col := range tableDesc.PublicColumns() {
 		if err := p.removeSequenceDependencies(ctx, tableDesc, col); err != nil {
@@ -297,6 +312,12 @@ func (p *planner) dropTableImpl(
 		droppedViews = append(droppedViews, qualifiedView.FQString())
 	}
 
+	// Drop all functions that depend on this table, assuming that we wouldn't
+	// have made it to this point if `cascade` wasn't enabled.
+	if err := p.dropDependentFunctions(ctx, tableDesc); err != nil {
+		return droppedViews, err
+	}
+
 	err := p.removeTableComments(ctx, tableDesc)
 	if err != nil {
 		return droppedViews, err
@@ -0,0 +1,88 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+type dropTriggerNode struct {
+	n         *tree.DropTrigger
+	tableDesc *tabledesc.Mutable
+}
+
+// DropTrigger drops a row-level trigger.
+// Privileges: CREATE on table.
+//   notes: postgres requires ownership of the table.
+func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+	_, tableDesc, err := p.ResolveMutableTableDescriptor(
+		ctx, &n.Table, !n.IfExists, tree.ResolveRequireTableDesc,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc == nil {
+		p.BufferClientNotice(ctx, pgnotice.Newf(
+			"relation %s does not exist, skipping", tree.AsString(&n.Table)))
+		return newZeroNode(nil /* columns */), nil
+	}
+	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	return &dropTriggerNode{n: n, tableDesc: tableDesc}, nil
+}
+
+func (n *dropTriggerNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	trig, ok := n.tableDesc.FindTriggerByName(string(n.n.Name))
+	if !ok {
+		if n.n.IfExists {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				"trigger %s for relation %s does not exist, skipping",
+				n.n.Name, tree.Name(n.tableDesc.GetName())))
+			return nil
+		}
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"trigger %s for table %s does not exist", n.n.Name, tree.Name(n.tableDesc.GetName()))
+	}
+
+	// Triggers have no dependents, so RESTRICT and CASCADE behave the same.
+	fnID := trig.FuncID
+	n.tableDesc.RemoveTrigger(string(n.n.Name))
+	if !tableHasTriggerWithFunction(n.tableDesc, fnID) {
+		if err := p.removeTriggerFunctionBackReference(ctx, n.tableDesc, fnID); err != nil {
+			return err
+		}
+	}
+	return p.writeSchemaChange(
+		ctx, n.tableDesc, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (n *dropTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (n *dropTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (n *dropTriggerNode) Close(context.Context)        {}
+func (n *dropTriggerNode) ReadingOwnWrites()            {}
@@ -29,7 +29,8 @@ import (
 )
 
 type dropTypeNode struct {
-	n      *tree.DropType
+	// n is either a *tree.DropType or a *tree.DropDomain.
+	n      tree.Statement
 	toDrop map[descpb.ID]*typedesc.Mutable
 }
 
@@ -44,17 +45,45 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 	); err != nil {
 		return nil, err
 	}
+	if n.DropBehavior == tree.DropCascade {
+		return nil, unimplemented.NewWithIssue(51480, "DROP TYPE CASCADE is not yet supported")
+	}
+	return p.dropTypes(ctx, n, n.Names, n.IfExists, n.DropBehavior, false /* domains */)
+}
 
+// DropDomain drops the given domains. Domains are type descriptors, so this
+// is handled like DROP TYPE.
+func (p *planner) DropDomain(ctx context.Context, n *tree.DropDomain) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP DOMAIN",
+	); err != nil {
+		return nil, err
+	}
+	if n.DropBehavior == tree.DropCascade {
+		return nil, unimplemented.NewWithIssue(51480, "DROP DOMAIN CASCADE is not yet supported")
+	}
+	return p.dropTypes(ctx, n, n.Names, n.IfExists, n.DropBehavior, true /* domains */)
+}
+
+// dropTypes plans the removal of the named types. If domains is true, the
+// types must be domains, otherwise they must not be.
+func (p *planner) dropTypes(
+	ctx context.Context,
+	n tree.Statement,
+	names []*tree.UnresolvedObjectName,
+	ifExists bool,
+	behavior tree.DropBehavior,
+	domains bool,
+) (planNode, error) {
 	node := &dropTypeNode{
 		n:      n,
 		toDrop: make(map[descpb.ID]*typedesc.Mutable),
 	}
-	if n.DropBehavior == tree.DropCascade {
-		return nil, unimplemented.NewWithIssue(51480, "DROP TYPE CASCADE is not yet supported")
-	}
-	for _, name := range n.Names {
+	for _, name := range names {
 		// Resolve the desired type descriptor.
-		_, typeDesc, err := p.ResolveMutableTypeDescriptor(ctx, name, !n.IfExists)
+		_, typeDesc, err := p.ResolveMutableTypeDescriptor(ctx, name, !ifExists)
 		if err != nil {
 			return nil, err
 		}
@@ -65,6 +94,15 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 		if _, ok := node.toDrop[typeDesc.ID]; ok {
 			continue
 		}
+		if isDomain := typeDesc.Kind == descpb.TypeDescriptor_DOMAIN; isDomain && !domains {
+			return nil, errors.WithHint(
+				pgerror.Newf(pgcode.WrongObjectType, "%q is not a type", typeDesc.GetName()),
+				"Use DROP DOMAIN to remove a domain.")
+		} else if !isDomain && domains {
+			return nil, errors.WithHint(
+				pgerror.Newf(pgcode.WrongObjectType, "%q is not a domain", typeDesc.GetName()),
+				"Use DROP TYPE to remove a type.")
+		}
 		switch typeDesc.Kind {
 		case descpb.TypeDescriptor_ALIAS:
 			// The implicit array types are not directly droppable.
@@ -93,9 +131,15 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 		}
 
 		// Check if we can drop the type.
-		if err := p.canDropTypeDesc(ctx, typeDesc, n.DropBehavior); err != nil {
+		if err := p.canDropTypeDesc(ctx, typeDesc, behavior); err != nil {
 			return nil, err
 		}
+		node.toDrop[typeDesc.ID] = typeDesc
+
+		// Domains don't have an implicit array type.
+		if typeDesc.ArrayTypeID == descpb.InvalidID {
+			continue
+		}
 
 		// Get the array type that needs to be dropped as well.
 		mutArrayDesc, err := p.Descriptors().GetMutableTypeVersionByID(ctx, p.txn, typeDesc.ArrayTypeID)
@@ -103,11 +147,10 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 			return nil, err
 		}
 		// Ensure that we can drop the array type as well.
-		if err := p.canDropTypeDesc(ctx, mutArrayDesc, n.DropBehavior); err != nil {
+		if err := p.canDropTypeDesc(ctx, mutArrayDesc, behavior); err != nil {
 			return nil, err
 		}
-		// Record these descriptors for deletion.
-		node.toDrop[typeDesc.ID] = typeDesc
+		// Record the array type for deletion as well.
 		node.toDrop[mutArrayDesc.ID] = mutArrayDesc
 	}
 	return node, nil
@@ -76,6 +76,9 @@ func (p *planner) DropView(ctx context.Context, n *tree.DropView) (planNode, err
 				return nil, err
 			}
 		}
+		if err := p.canRemoveDependentFunctions(ctx, droppedDesc, n.DropBehavior); err != nil {
+			return nil, err
+		}
 	}
 
 	if len(td) == 0 {
@@ -249,6 +252,12 @@ func (p *planner) dropViewImpl(
 		}
 	}
 
+	// Drop all functions that depend on this view, assuming that we wouldn't
+	// have made it to this point if `cascade` wasn't enabled.
+	if err := p.dropDependentFunctions(ctx, viewDesc); err != nil {
+		return cascadeDroppedViews, err
+	}
+
 	// Remove any references to types that this view has.
 	if err := p.removeBackRefsFromAllTypesInTable(ctx, viewDesc); err != nil {
 		return cascadeDroppedViews, err
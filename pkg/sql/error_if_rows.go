@@ -39,14 +39,19 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	}
 	n.nexted = true
 
-	ok, err := n.plan.Next(params)
-	if err != nil {
-		return false, err
+	for {
+		ok, err := n.plan.Next(params)
+		if err != nil || !ok {
+			return false, err
+		}
+		err = n.mkErr(n.plan.Values())
+		// Violations of deferred constraints are postponed until the end of the
+		// transaction (see deferredConstraintState). All the violating rows are
+		// recorded, so that only their keys need to be re-checked.
+		if !params.extendedEvalCtx.DeferredConstraints.maybeDefer(err) {
+			return false, err
+		}
 	}
-	if ok {
-		return false, n.mkErr(n.plan.Values())
-	}
-	return false, nil
 }
 
 func (n *errorIfRowsNode) Values() tree.Datums {
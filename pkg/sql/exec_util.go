@@ -71,6 +71,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgnotify"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
@@ -1231,6 +1232,10 @@ type ExecutorConfig struct {
 	// StmtDiagnosticsRecorder deals with recording statement diagnostics.
 	StmtDiagnosticsRecorder *stmtdiagnostics.Registry
 
+	// NotificationRegistry delivers the notifications sent with NOTIFY to the
+	// sessions on this node.
+	NotificationRegistry *pgnotify.Registry
+
 	ExternalIODirConfig base.ExternalIODirConfig
 
 	GCJobNotifier *gcjobnotifier.Notifier
@@ -1921,6 +1926,10 @@ type SessionArgs struct {
 	// authentication is skipped. Once the token is used to authenticate, this
 	// value should be zeroed out.
 	SessionRevivalToken []byte
+	// Notifications receives the notifications for the channels that the
+	// session listens on. It is nil for sessions that cannot use LISTEN, such
+	// as internal sessions.
+	Notifications *pgnotify.Listener
 }
 
 // SessionRegistry stores a set of all sessions on this node.
@@ -0,0 +1,468 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// resolveFuncObj resolves a reference to a single user-defined function
+// overload for use in a schema change. It returns mutable descriptors for the
+// function and for the schema which contains it. If required is false and no
+// such function exists, nil descriptors are returned.
+func (p *planner) resolveFuncObj(
+	ctx context.Context, fn *tree.FuncObj, required bool,
+) (*schemadesc.Mutable, *funcdesc.Mutable, error) {
+	var dbName, scName string
+	if fn.FuncName.NumParts > 2 {
+		dbName = fn.FuncName.Catalog()
+	}
+	if fn.FuncName.NumParts > 1 {
+		scName = fn.FuncName.Schema()
+	}
+	fnName := fn.FuncName.Object()
+
+	var immutableSc catalog.SchemaDescriptor
+	var overloads descpb.SchemaDescriptor_Function
+	var err error
+	p.runWithOptions(resolveFlags{skipCache: true}, func() {
+		immutableSc, overloads, err = p.lookupFunction(ctx, dbName, scName, fnName, p.CurrentSearchPath())
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var argTypes []*types.T
+	if fn.Args != nil {
+		argTypes = make([]*types.T, len(fn.Args))
+		for i := range fn.Args {
+			if argTypes[i], err = tree.ResolveType(ctx, fn.Args[i].Type, p.semaCtx.GetTypeResolver()); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	// Find the overload matching the given argument types, if any.
+	var id descpb.ID
+	for _, ol := range overloads.Overloads {
+		if argTypes == nil {
+			if id != descpb.InvalidID {
+				return nil, nil, pgerror.Newf(pgcode.AmbiguousFunction,
+					"function name %q is not unique", fnName)
+			}
+			id = ol.ID
+			continue
+		}
+		if argTypesMatch(ol.ArgTypes, argTypes) {
+			id = ol.ID
+			break
+		}
+	}
+	if id == descpb.InvalidID {
+		if !required {
+			return nil, nil, nil
+		}
+		return nil, nil, sqlerrors.NewUndefinedFunctionError(fn.FuncName.String())
+	}
+
+	mutSc, err := p.Descriptors().GetMutableDescriptorByID(ctx, p.txn, immutableSc.GetID())
+	if err != nil {
+		return nil, nil, err
+	}
+	sc, ok := mutSc.(*schemadesc.Mutable)
+	if !ok {
+		return nil, nil, errors.AssertionFailedf(
+			"descriptor for schema %q is not Mutable", immutableSc.GetName())
+	}
+	fnDesc, err := p.Descriptors().GetMutableFunctionByID(
+		ctx, p.txn, id, p.ObjectLookupFlags(true /* required */, true /* requireMutable */),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sc, fnDesc, nil
+}
+
+// argTypesMatch returns whether the argument types of a function overload are
+// identical to the given types.
+func argTypesMatch(a, b []*types.T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Identical(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkFunctionOwnership returns an error if the current user is not allowed
+// to alter or drop the given function.
+func (p *planner) checkFunctionOwnership(ctx context.Context, fnDesc catalog.FunctionDescriptor) error {
+	isAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+	hasOwnership, err := p.HasOwnership(ctx, fnDesc)
+	if err != nil {
+		return err
+	}
+	if !(isAdmin || hasOwnership) {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of function %s", tree.Name(fnDesc.GetName()))
+	}
+	return nil
+}
+
+// writeFuncDesc writes a function descriptor to the store.
+func (p *planner) writeFuncDesc(ctx context.Context, desc *funcdesc.Mutable) error {
+	b := p.txn.NewBatch()
+	if err := p.Descriptors().WriteDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), desc, b,
+	); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+// dropFunctionImpl deletes the descriptor of a user-defined function, along
+// with its comment. Removing the function from its parent schema is left to
+// the caller, since the schema may itself be in the process of being dropped.
+//
+// Functions may be depended on by the bodies of other functions, and trigger
+// functions by the triggers of tables. These dependents are dropped if
+// behavior is DropCascade; otherwise an error is returned.
+func (p *planner) dropFunctionImpl(
+	ctx context.Context, fnDesc *funcdesc.Mutable, behavior tree.DropBehavior,
+) error {
+	// Copy out the set of dependent functions as it is modified in the loop.
+	dependedOnByFuncs := append([]descpb.ID(nil), fnDesc.DependedOnByFunctions...)
+	for _, id := range dependedOnByFuncs {
+		depDesc, err := p.Descriptors().GetMutableFunctionByID(
+			ctx, p.txn, id, p.ObjectLookupFlags(true /* required */, true /* requireMutable */),
+		)
+		if err != nil {
+			return err
+		}
+		if behavior != tree.DropCascade {
+			return errors.WithHintf(
+				pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"cannot drop function %s because function %s depends on it",
+					tree.Name(fnDesc.GetName()), tree.Name(depDesc.GetName())),
+				"use DROP ... CASCADE to drop the dependent objects too")
+		}
+		if err := p.dropDependentFunction(ctx, depDesc); err != nil {
+			return err
+		}
+	}
+	if err := p.removeFunctionBackReferences(ctx, fnDesc); err != nil {
+		return err
+	}
+
+	// Copy out the set of dependents as it is modified in the loop.
+	dependedOnBy := append([]descpb.FunctionDescriptor_Reference(nil), fnDesc.DependedOnBy...)
+	for _, ref := range dependedOnBy {
+		tableDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, ref.ID, p.txn)
+		if err != nil {
+			return err
+		}
+		if err := p.dropTriggersOfFunction(ctx, tableDesc, fnDesc, behavior); err != nil {
+			return err
+		}
+	}
+
+	fnDesc.SetDropped()
+	// Function descriptors are not referenced from the namespace table and no
+	// data is associated with them, so they can be deleted immediately.
+	b := p.txn.NewBatch()
+	if err := p.Descriptors().WriteDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), fnDesc, b,
+	); err != nil {
+		return err
+	}
+	b.Del(catalogkeys.MakeDescMetadataKey(p.ExecCfg().Codec, fnDesc.GetID()))
+	if err := p.txn.Run(ctx, b); err != nil {
+		return err
+	}
+	p.Descriptors().AddDeletedDescriptor(fnDesc.GetID())
+	return p.removeFunctionComment(ctx, fnDesc.GetID())
+}
+
+func (p *planner) removeFunctionComment(ctx context.Context, fnID descpb.ID) error {
+	_, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.ExecEx(
+		ctx,
+		"delete-function-comment",
+		p.txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		"DELETE FROM system.comments WHERE type=$1 AND object_id=$2 AND sub_id=0",
+		keys.FunctionCommentType,
+		fnID)
+
+	return err
+}
+
+// dropTriggersOfFunction drops the triggers of the given table which execute
+// the given function, and removes the back-reference from the function to the
+// table. An error is returned unless behavior is DropCascade.
+func (p *planner) dropTriggersOfFunction(
+	ctx context.Context, tableDesc *tabledesc.Mutable, fnDesc *funcdesc.Mutable, behavior tree.DropBehavior,
+) error {
+	// The table may be in the process of being dropped, in which case its
+	// triggers have already been removed.
+	if tableDesc.Dropped() {
+		fnDesc.RemoveDependedOnBy(tableDesc.GetID())
+		return nil
+	}
+	var names []string
+	for i := range tableDesc.Triggers {
+		if tableDesc.Triggers[i].FuncID == fnDesc.GetID() {
+			names = append(names, tableDesc.Triggers[i].Name)
+		}
+	}
+	if behavior != tree.DropCascade && len(names) > 0 {
+		return errors.WithHintf(
+			pgerror.Newf(pgcode.DependentObjectsStillExist,
+				"cannot drop function %s because trigger %s on table %s depends on it",
+				tree.Name(fnDesc.GetName()), tree.Name(names[0]), tree.Name(tableDesc.GetName())),
+			"use DROP ... CASCADE to drop the dependent objects too")
+	}
+	for _, name := range names {
+		tableDesc.RemoveTrigger(name)
+		p.BufferClientNotice(ctx, pgnotice.Newf(
+			"drop cascades to trigger %s on table %s", tree.Name(name), tree.Name(tableDesc.GetName())))
+	}
+	fnDesc.RemoveDependedOnBy(tableDesc.GetID())
+	return p.writeSchemaChange(ctx, tableDesc, descpb.InvalidMutationID,
+		fmt.Sprintf("dropping triggers of function %s", fnDesc.GetName()))
+}
+
+// tableHasTriggerWithFunction returns true if the given table has a trigger
+// which executes the function with the given ID.
+func tableHasTriggerWithFunction(tableDesc catalog.TableDescriptor, fnID descpb.ID) bool {
+	for _, trig := range tableDesc.GetTriggers() {
+		if trig.FuncID == fnID {
+			return true
+		}
+	}
+	return false
+}
+
+// removeTriggerFunctionBackReference removes the back-reference from the
+// function with the given ID to the given table. It must be called once the
+// table no longer has any trigger which executes the function.
+func (p *planner) removeTriggerFunctionBackReference(
+	ctx context.Context, tableDesc *tabledesc.Mutable, fnID descpb.ID,
+) error {
+	fnDesc, err := p.Descriptors().GetMutableFunctionByID(
+		ctx, p.txn, fnID, p.ObjectLookupFlags(true /* required */, true /* requireMutable */),
+	)
+	if err != nil {
+		return err
+	}
+	fnDesc.RemoveDependedOnBy(tableDesc.GetID())
+	return p.writeFuncDesc(ctx, fnDesc)
+}
+
+// addFunctionDependencies records that the body of the given function
+// references the given relations and calls the given functions, and writes
+// the corresponding back-references to their descriptors. The function
+// descriptor itself is left to the caller to write.
+func (p *planner) addFunctionDependencies(
+	ctx context.Context, fnDesc *funcdesc.Mutable, planDeps planDependencies, funcDeps []descpb.ID,
+) error {
+	dependsOn := make([]descpb.ID, 0, len(planDeps))
+	for id, updated := range planDeps {
+		tableDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		// Temporary tables are dropped along with the session which created
+		// them, so a function which outlives them cannot depend on them.
+		if tableDesc.Temporary {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot reference temporary table %q in the body of function %s",
+				tableDesc.GetName(), tree.Name(fnDesc.GetName()))
+		}
+		for _, dep := range updated.deps {
+			// The optimizer did not know the ID of the function, so it is filled
+			// in here.
+			dep.ID = fnDesc.GetID()
+			dep.ByID = updated.desc.IsSequence()
+			tableDesc.DependedOnByFunctions = append(tableDesc.DependedOnByFunctions, dep)
+		}
+		if err := p.writeSchemaChange(ctx, tableDesc, descpb.InvalidMutationID,
+			fmt.Sprintf("updating function reference %q in table %s(%d)",
+				fnDesc.GetName(), tableDesc.GetName(), tableDesc.GetID()),
+		); err != nil {
+			return err
+		}
+		dependsOn = append(dependsOn, id)
+	}
+	sort.Slice(dependsOn, func(i, j int) bool { return dependsOn[i] < dependsOn[j] })
+	fnDesc.SetDependsOn(dependsOn)
+
+	for _, id := range funcDeps {
+		depDesc, err := p.Descriptors().GetMutableFunctionByID(
+			ctx, p.txn, id, p.ObjectLookupFlags(true /* required */, true /* requireMutable */),
+		)
+		if err != nil {
+			return err
+		}
+		depDesc.AddDependedOnByFunction(fnDesc.GetID())
+		if err := p.writeFuncDesc(ctx, depDesc); err != nil {
+			return err
+		}
+	}
+	fnDesc.SetDependsOnFunctions(funcDeps)
+	return nil
+}
+
+// removeFunctionBackReferences removes the back-references to the given
+// function from the relations and functions which its body depends on, and
+// clears its dependencies. The function descriptor itself is left to the
+// caller to write.
+func (p *planner) removeFunctionBackReferences(
+	ctx context.Context, fnDesc *funcdesc.Mutable,
+) error {
+	for _, id := range fnDesc.GetDependsOn() {
+		tableDesc, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		// The relation may be in the process of being dropped.
+		if tableDesc.Dropped() {
+			continue
+		}
+		tableDesc.DependedOnByFunctions = removeMatchingReferences(
+			tableDesc.DependedOnByFunctions, fnDesc.GetID(),
+		)
+		if err := p.writeSchemaChange(ctx, tableDesc, descpb.InvalidMutationID,
+			fmt.Sprintf("removing function reference %q in table %s(%d)",
+				fnDesc.GetName(), tableDesc.GetName(), tableDesc.GetID()),
+		); err != nil {
+			return err
+		}
+	}
+	fnDesc.SetDependsOn(nil)
+
+	for _, id := range fnDesc.GetDependsOnFunctions() {
+		depDesc, err := p.Descriptors().GetMutableFunctionByID(
+			ctx, p.txn, id, p.ObjectLookupFlags(true /* required */, true /* requireMutable */),
+		)
+		if err != nil {
+			return err
+		}
+		depDesc.RemoveDependedOnByFunction(fnDesc.GetID())
+		if err := p.writeFuncDesc(ctx, depDesc); err != nil {
+			return err
+		}
+	}
+	fnDesc.SetDependsOnFunctions(nil)
+	return nil
+}
+
+// canRemoveDependentFunctions returns an error if the bodies of any functions
+// reference the given relation, unless behavior is DropCascade.
+func (p *planner) canRemoveDependentFunctions(
+	ctx context.Context, desc catalog.TableDescriptor, behavior tree.DropBehavior,
+) error {
+	refs := desc.GetDependedOnByFunctions()
+	if behavior == tree.DropCascade || len(refs) == 0 {
+		return nil
+	}
+	return p.dependentFunctionError(
+		ctx, string(desc.DescriptorType()), desc.GetName(), refs[0].ID, "drop",
+	)
+}
+
+// dependentFunctionError returns an error reporting that op cannot be applied
+// to the given object because the body of the function with the given ID
+// references it.
+func (p *planner) dependentFunctionError(
+	ctx context.Context, typeName, objName string, fnID descpb.ID, op string,
+) error {
+	fnDesc, err := p.Descriptors().GetImmutableFunctionByID(
+		ctx, p.txn, fnID, tree.ObjectLookupFlagsWithRequired(),
+	)
+	if err != nil {
+		return err
+	}
+	return errors.WithHintf(
+		sqlerrors.NewDependentObjectErrorf("cannot %s %s %q because function %q depends on it",
+			op, typeName, objName, fnDesc.GetName()),
+		"you can drop %s instead.", fnDesc.GetName())
+}
+
+// dropDependentFunctions drops the functions whose bodies reference the
+// given relation, which is being dropped.
+func (p *planner) dropDependentFunctions(ctx context.Context, tableDesc *tabledesc.Mutable) error {
+	// Copy out the set of dependencies as it is modified in the loop.
+	dependedOnBy := append([]descpb.TableDescriptor_Reference(nil), tableDesc.DependedOnByFunctions...)
+	for _, ref := range dependedOnBy {
+		fnDesc, err := p.Descriptors().GetMutableFunctionByID(
+			ctx, p.txn, ref.ID, p.ObjectLookupFlags(true /* required */, true /* requireMutable */),
+		)
+		if err != nil {
+			return err
+		}
+		if err := p.dropDependentFunction(ctx, fnDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropDependentFunction drops a function as a result of dropping an object
+// which its body depends on, and removes it from its parent schema.
+func (p *planner) dropDependentFunction(ctx context.Context, fnDesc *funcdesc.Mutable) error {
+	// The function may already be getting dropped. Don't do it twice.
+	if fnDesc.Dropped() {
+		return nil
+	}
+	if err := p.dropFunctionImpl(ctx, fnDesc, tree.DropCascade); err != nil {
+		return err
+	}
+	p.BufferClientNotice(ctx, pgnotice.Newf(
+		"drop cascades to function %s", tree.Name(fnDesc.GetName())))
+
+	mutSc, err := p.Descriptors().GetMutableDescriptorByID(ctx, p.txn, fnDesc.GetParentSchemaID())
+	if err != nil {
+		return err
+	}
+	sc, ok := mutSc.(*schemadesc.Mutable)
+	if !ok {
+		return errors.AssertionFailedf(
+			"descriptor for schema %q is not Mutable", mutSc.GetName())
+	}
+	sc.RemoveFunction(fnDesc.GetName(), fnDesc.GetID())
+	return p.writeSchemaDescChange(ctx, sc,
+		fmt.Sprintf("dropping function %s in schema %s", fnDesc.GetName(), sc.GetName()))
+}
@@ -5762,13 +5762,13 @@ revoke privileges on sequence: could not be parsed
 grant privileges on sequence: could not be parsed
 grant privileges on sequence: could not be parsed
 comment on extension: could not be parsed
-comment on function: could not be parsed
+COMMENT ON FUNCTION f() IS 'f': unsupported by IMPORT
 create extension if not exists with: could not be parsed
 alter aggregate: could not be parsed
 alter domain: could not be parsed
 `,
-			`create function: could not be parsed
-alter function: could not be parsed
+			`CREATE FUNCTION public.isnumeric(STRING) RETURNS BOOL LANGUAGE SQL AS e'\nSELECT $1 ~ \'^[0-9]+$\'\n': unsupported by IMPORT
+ALTER FUNCTION public.isnumeric(STRING) OWNER TO roland: unsupported by IMPORT
 alter table alter column add: could not be parsed
 copy from unsupported format: could not be parsed
 grant privileges on schema with: could not be parsed
@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -236,6 +237,10 @@ type schemaParsingObjects struct {
 	createTbl    map[schemaAndTableName]*tree.CreateTable
 	createSeq    map[schemaAndTableName]*tree.CreateSequence
 	tableFKs     map[schemaAndTableName][]*tree.ForeignKeyConstraintTableDef
+	// createDomain holds the domains defined in the dump. Columns of a domain
+	// type are imported as columns of the base type of the domain, with the
+	// constraints of the domain.
+	createDomain map[schemaAndTableName]*tree.CreateDomain
 }
 
 func createPostgresSchemas(
@@ -500,6 +505,7 @@ func readPostgresCreateTable(
 		createTbl:    make(map[schemaAndTableName]*tree.CreateTable),
 		createSeq:    make(map[schemaAndTableName]*tree.CreateSequence),
 		tableFKs:     make(map[schemaAndTableName][]*tree.ForeignKeyConstraintTableDef),
+		createDomain: make(map[schemaAndTableName]*tree.CreateDomain),
 	}
 	ps := newPostgreStream(ctx, input, max, unsupportedStmtLogger)
 	for {
@@ -610,11 +616,20 @@ func readPostgresStmt(
 			break
 		}
 		schemaObjects.createSchema[name] = stmt
+	case *tree.CreateDomain:
+		name, err := getSchemaAndTableName2(stmt.TypeName)
+		if err != nil {
+			return err
+		}
+		schemaObjects.createDomain[name] = stmt
 	case *tree.CreateTable:
 		// If the target table columns have data type INT or INTEGER, they need to
 		// be updated to conform to the session variable `default_int_size`.
 		for _, def := range stmt.Defs {
 			if d, ok := def.(*tree.ColumnTableDef); ok {
+				if err := applyPostgresDomain(d, schemaObjects.createDomain); err != nil {
+					return err
+				}
 				if dType, ok := d.Type.(*types.T); ok {
 					if dType.Equivalent(types.Int) {
 						d.Type = parser.NakedIntTypeFromDefaultIntSize(p.SessionData().DefaultIntSize)
@@ -903,7 +918,8 @@ func readPostgresStmt(
 		// handled during the data ingestion pass.
 	case *tree.CreateExtension, *tree.CommentOnDatabase, *tree.CommentOnTable,
 		*tree.CommentOnIndex, *tree.CommentOnConstraint, *tree.CommentOnColumn, *tree.SetVar, *tree.Analyze,
-		*tree.CommentOnSchema:
+		*tree.CommentOnSchema, *tree.CommentOnFunction, *tree.CreateFunction, *tree.AlterFunctionRename,
+		*tree.AlterFunctionSetOwner, *tree.AlterFunctionSetSchema, *tree.DropFunction:
 		// These are the statements that can be parsed by CRDB but are not
 		// supported, or are not required to be processed, during an IMPORT.
 		// - ignore txns.
@@ -930,6 +946,27 @@ func readPostgresStmt(
 	return nil
 }
 
+// applyPostgresDomain replaces the type of the given column definition with
+// the base type of its domain, if the column type is one of the given
+// domains, and adds the constraints of the domain to the column.
+func applyPostgresDomain(
+	d *tree.ColumnTableDef, domains map[schemaAndTableName]*tree.CreateDomain,
+) error {
+	typName, ok := d.Type.(*tree.UnresolvedObjectName)
+	if !ok {
+		return nil
+	}
+	name, err := getSchemaAndTableName2(typName)
+	if err != nil {
+		return err
+	}
+	domain, ok := domains[name]
+	if !ok {
+		return nil
+	}
+	return schemaexpr.DomainFromCreateDomain(domain).ApplyToColumn(d)
+}
+
 func getSchemaName(sc *tree.ObjectNamePrefix) (string, error) {
 	if sc.ExplicitCatalog {
 		return "", unimplemented.Newf("import into database specified in dump file",
@@ -1407,12 +1444,13 @@ func (m *pgDumpReader) readFile(
 			}
 		case *tree.CreateExtension, *tree.CommentOnDatabase, *tree.CommentOnTable,
 			*tree.CommentOnIndex, *tree.CommentOnConstraint, *tree.CommentOnColumn, *tree.AlterSequence,
-			*tree.CommentOnSchema:
+			*tree.CommentOnSchema, *tree.CommentOnFunction, *tree.CreateFunction, *tree.AlterFunctionRename,
+			*tree.AlterFunctionSetOwner, *tree.AlterFunctionSetSchema, *tree.DropFunction:
 			// handled during schema extraction.
 		case *tree.SetVar, *tree.BeginTransaction, *tree.CommitTransaction, *tree.Analyze:
 			// handled during schema extraction.
 		case *tree.CreateTable, *tree.CreateSchema, *tree.AlterTable, *tree.AlterTableOwner,
-			*tree.CreateIndex, *tree.CreateSequence, *tree.DropTable:
+			*tree.CreateIndex, *tree.CreateSequence, *tree.DropTable, *tree.CreateDomain:
 			// handled during schema extraction.
 		default:
 			err := errors.Errorf("unsupported %T statement: %v", i, i)
@@ -477,7 +477,14 @@ https://www.postgresql.org/docs/9.5/infoschema-columns.html`,
 				// the schema it is under.
 				udtSchema := pgCatalogNameDString
 				typeMetaName := column.GetType().TypeMeta.Name
-				if typeMetaName != nil {
+				// The domain_* columns describe the DOMAIN type of the column, if
+				// any, in which case the udt_* columns describe its base type.
+				domainCatalog, domainSchema, domainName := tree.DNull, tree.DNull, tree.DNull
+				if column.GetType().IsDomain() {
+					domainCatalog = dbNameStr
+					domainSchema = tree.NewDString(typeMetaName.Schema)
+					domainName = tree.NewDString(typeMetaName.Name)
+				} else if typeMetaName != nil {
 					udtSchema = tree.NewDString(typeMetaName.Schema)
 				}
 
@@ -505,9 +512,9 @@ https://www.postgresql.org/docs/9.5/infoschema-columns.html`,
 					collationCatalog,                                          // collation_catalog
 					collationSchema,                                           // collation_schema
 					collationName,                                             // collation_name
-					tree.DNull,                                                // domain_catalog
-					tree.DNull,                                                // domain_schema
-					tree.DNull,                                                // domain_name
+					domainCatalog,                                             // domain_catalog
+					domainSchema,                                              // domain_schema
+					domainName,                                                // domain_name
 					dbNameStr,                                                 // udt_catalog
 					udtSchema,                                                 // udt_schema
 					tree.NewDString(column.GetType().PGName()), // udt_name
@@ -1266,6 +1273,7 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 				tbNameStr := tree.NewDString(table.GetName())
 
 				for conName, c := range conInfo {
+					deferrability := c.Deferrability()
 					if err := addRow(
 						dbNameStr,                       // constraint_catalog
 						scNameStr,                       // constraint_schema
@@ -1274,8 +1282,8 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 						scNameStr,                       // table_schema
 						tbNameStr,                       // table_name
 						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						yesOrNoDatum(deferrability != catpb.ConstraintDeferrability_NOT_DEFERRABLE),     // is_deferrable
+						yesOrNoDatum(deferrability == catpb.ConstraintDeferrability_INITIALLY_DEFERRED), // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -1755,12 +1763,53 @@ var informationSchemaUsagePrivilegesTable = virtualSchemaTable{
 }
 
 var informationSchemaDomainsTable = virtualSchemaTable{
-	comment: "domains was created for compatibility and is currently unimplemented",
-	schema:  vtable.InformationSchemaDomains,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `domains
+https://www.postgresql.org/docs/current/infoschema-domains.html`,
+	schema: vtable.InformationSchemaDomains,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachTypeDesc(ctx, p, dbContext, func(db catalog.DatabaseDescriptor, sc string, typDesc catalog.TypeDescriptor) error {
+			if typDesc.GetKind() != descpb.TypeDescriptor_DOMAIN {
+				return nil
+			}
+			domain := typDesc.TypeDesc().Domain
+			baseType := domain.BaseType
+			dbNameStr := tree.NewDString(db.GetName())
+			dataType := tree.NewDString(baseType.InformationSchemaName())
+			domainDefault := tree.DNull
+			if domain.DefaultExpr != nil {
+				domainDefault = tree.NewDString(*domain.DefaultExpr)
+			}
+			return addRow(
+				dbNameStr,                          // domain_catalog
+				tree.NewDString(sc),                // domain_schema
+				tree.NewDString(typDesc.GetName()), // domain_name
+				dataType,                           // data_type
+				characterMaximumLength(baseType),   // character_maximum_length
+				characterOctetLength(baseType),     // character_octet_length
+				tree.DNull,                         // character_set_catalog
+				tree.DNull,                         // character_set_schema
+				tree.DNull,                         // character_set_name
+				tree.DNull,                         // collation_catalog
+				tree.DNull,                         // collation_schema
+				tree.DNull,                         // collation_name
+				numericPrecision(baseType),         // numeric_precision
+				numericPrecisionRadix(baseType),    // numeric_precision_radix
+				numericScale(baseType),             // numeric_scale
+				datetimePrecision(baseType),        // datetime_precision
+				tree.DNull,                         // interval_type
+				tree.DNull,                         // interval_precision
+				domainDefault,                      // domain_default
+				dbNameStr,                          // udt_catalog
+				tree.NewDString(pgCatalogName),     // udt_schema
+				tree.NewDString(baseType.PGName()), // udt_name
+				tree.DNull,                         // scope_catalog
+				tree.DNull,                         // scope_schema
+				tree.DNull,                         // scope_name
+				tree.DNull,                         // maximum_cardinality
+				tree.NewDString("1"),               // dtd_identifier
+			)
+		})
 	},
-	unimplemented: true,
 }
 
 var informationSchemaSQLImplementationInfoTable = virtualSchemaTable{
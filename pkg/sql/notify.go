@@ -0,0 +1,273 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/errors"
+)
+
+// maxNotifyChannelLength and maxNotifyPayloadLength match the limits of
+// Postgres, where channel names are identifiers (NAMEDATALEN-1 bytes) and
+// payloads must be shorter than 8000 bytes.
+const (
+	maxNotifyChannelLength = 63
+	maxNotifyPayloadLength = 7999
+)
+
+type listenNode struct {
+	channel string
+}
+
+// Listen implements the LISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-listen.html for details.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	if err := p.checkNotificationsSupported(ctx, "LISTEN"); err != nil {
+		return nil, err
+	}
+	return &listenNode{channel: string(n.ChannelName)}, nil
+}
+
+func (n *listenNode) startExec(params runParams) error {
+	// As in Postgres, the registration only takes effect once the transaction
+	// commits.
+	l, channel := params.p.notifications, n.channel
+	params.p.txn.AddCommitTrigger(func(context.Context) { l.Listen(channel) })
+	return nil
+}
+
+func (n *listenNode) Next(runParams) (bool, error) { return false, nil }
+func (n *listenNode) Values() tree.Datums          { return nil }
+func (n *listenNode) Close(context.Context)        {}
+
+type unlistenNode struct {
+	n *tree.Unlisten
+}
+
+// Unlisten implements the UNLISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-unlisten.html for details.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	if err := p.checkNotificationsSupported(ctx, "UNLISTEN"); err != nil {
+		return nil, err
+	}
+	return &unlistenNode{n: n}, nil
+}
+
+func (n *unlistenNode) startExec(params runParams) error {
+	l := params.p.notifications
+	if n.n.All {
+		params.p.txn.AddCommitTrigger(func(context.Context) { l.UnlistenAll() })
+		return nil
+	}
+	channel := string(n.n.ChannelName)
+	params.p.txn.AddCommitTrigger(func(context.Context) { l.Unlisten(channel) })
+	return nil
+}
+
+func (n *unlistenNode) Next(runParams) (bool, error) { return false, nil }
+func (n *unlistenNode) Values() tree.Datums          { return nil }
+func (n *unlistenNode) Close(context.Context)        {}
+
+type notifyNode struct {
+	n *tree.Notify
+}
+
+// Notify implements the NOTIFY statement.
+// See https://www.postgresql.org/docs/current/sql-notify.html for details.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	return &notifyNode{n: n}, nil
+}
+
+func (n *notifyNode) startExec(params runParams) error {
+	var payload string
+	if n.n.Payload != nil {
+		payload = n.n.Payload.RawString()
+	}
+	return params.p.SendNotification(params.ctx, string(n.n.ChannelName), payload)
+}
+
+func (n *notifyNode) Next(runParams) (bool, error) { return false, nil }
+func (n *notifyNode) Values() tree.Datums          { return nil }
+func (n *notifyNode) Close(context.Context)        {}
+
+// notification is a notification sent by a transaction.
+type notification struct {
+	channel string
+	payload string
+}
+
+// pendingNotifications buffers the notifications sent by a transaction, which
+// are written to system.notifications in a single statement before the
+// transaction commits. As in Postgres, a notification which is sent several
+// times with the same payload in a transaction is only delivered once.
+//
+// The zero value is ready to use. A nil *pendingNotifications does not buffer
+// notifications; this is the case for the internal executor running in a
+// higher-level transaction, which it does not commit.
+type pendingNotifications struct {
+	// ordered holds the notifications in the order they were first sent.
+	ordered []notification
+	seen    map[notification]struct{}
+	// numWritten is the number of notifications at the start of ordered which
+	// were already written to system.notifications.
+	numWritten int
+}
+
+// add buffers a notification. It returns false if the notification was
+// already sent in the transaction.
+func (n *pendingNotifications) add(channel, payload string) bool {
+	key := notification{channel: channel, payload: payload}
+	if _, ok := n.seen[key]; ok {
+		return false
+	}
+	if n.seen == nil {
+		n.seen = make(map[notification]struct{})
+	}
+	n.seen[key] = struct{}{}
+	n.ordered = append(n.ordered, key)
+	return true
+}
+
+// len returns the number of notifications sent in the transaction.
+func (n *pendingNotifications) len() int {
+	return len(n.ordered)
+}
+
+// truncate discards the notifications sent after the first count ones. It is
+// used when rolling back to a savepoint, which also rolls back the writes of
+// the discarded notifications.
+func (n *pendingNotifications) truncate(count int) {
+	for _, key := range n.ordered[count:] {
+		delete(n.seen, key)
+	}
+	n.ordered = n.ordered[:count]
+	if n.numWritten > count {
+		n.numWritten = count
+	}
+}
+
+// reset clears the buffer at the end of a transaction.
+func (n *pendingNotifications) reset() {
+	*n = pendingNotifications{}
+}
+
+// write inserts the buffered notifications which were not written yet into
+// system.notifications, with a single statement.
+func (n *pendingNotifications) write(
+	ctx context.Context, ie sqlutil.InternalExecutor, txn *kv.Txn, pid int32,
+) error {
+	if err := writeNotifications(ctx, ie, txn, n.ordered[n.numWritten:], pid); err != nil {
+		return err
+	}
+	n.numWritten = len(n.ordered)
+	return nil
+}
+
+// flushNotifications writes the notifications sent in the current transaction
+// to system.notifications. It is called before the transaction commits.
+func (ex *connExecutor) flushNotifications(ctx context.Context) error {
+	return ex.extraTxnState.notifications.write(
+		ctx,
+		ex.server.cfg.InternalExecutor,
+		ex.state.mu.txn,
+		ex.queryCancelKey.GetPGBackendPID(),
+	)
+}
+
+// writeNotifications inserts the given notifications, sent by the session
+// with the given pid, into system.notifications with a single statement.
+func writeNotifications(
+	ctx context.Context,
+	ie sqlutil.InternalExecutor,
+	txn *kv.Txn,
+	notifications []notification,
+	pid int32,
+) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	var query strings.Builder
+	query.WriteString(`INSERT INTO system.notifications (channel, payload, pid) VALUES `)
+	args := make([]interface{}, 0, 3*len(notifications))
+	for i, n := range notifications {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		fmt.Fprintf(&query, "($%d, $%d, $%d)", 3*i+1, 3*i+2, 3*i+3)
+		args = append(args, n.channel, n.payload, pid)
+	}
+	_, err := ie.ExecEx(
+		ctx, "notify", txn, sessiondata.NodeUserSessionDataOverride, query.String(), args...,
+	)
+	return errors.Wrap(err, "sending notifications")
+}
+
+// SendNotification is part of the tree.EvalPlanner interface. It sends a
+// notification on the given channel as part of the current transaction; the
+// listeners receive it once the transaction commits.
+func (p *planner) SendNotification(ctx context.Context, channel, payload string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"NOTIFY is not supported until upgrade to version %s is finalized",
+			clusterversion.NotificationsTable.String())
+	}
+	if channel == "" {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(channel) > maxNotifyChannelLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "channel name too long")
+	}
+	if len(payload) > maxNotifyPayloadLength {
+		return pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	pid := p.queryCancelKey.GetPGBackendPID()
+	pending := p.extendedEvalCtx.Notifications
+	if pending == nil {
+		return writeNotifications(
+			ctx, p.ExecCfg().InternalExecutor, p.txn,
+			[]notification{{channel: channel, payload: payload}}, pid,
+		)
+	}
+	if !pending.add(channel, payload) {
+		return nil
+	}
+	if p.autoCommit {
+		// The statement may commit the transaction along with its last write,
+		// before the buffered notifications are written, so they are written
+		// right away.
+		return pending.write(ctx, p.ExecCfg().InternalExecutor, p.txn, pid)
+	}
+	return nil
+}
+
+// checkNotificationsSupported returns an error if the session cannot receive
+// notifications, which is the case for internal sessions.
+func (p *planner) checkNotificationsSupported(ctx context.Context, stmt string) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is not supported until upgrade to version %s is finalized",
+			stmt, clusterversion.NotificationsTable.String())
+	}
+	if p.notifications == nil {
+		return pgerror.Newf(pgcode.FeatureNotSupported, "%s is not supported in this session", stmt)
+	}
+	return nil
+}
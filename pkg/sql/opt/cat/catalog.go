@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/lib/pq/oid"
 )
@@ -126,6 +127,21 @@ type Catalog interface {
 		ctx context.Context, name *tree.UnresolvedObjectName,
 	) (*types.T, error)
 
+	// ResolveFunction locates the overloads of the user-defined function with
+	// the given name. If no such function exists, or the current user does not
+	// have the EXECUTE privilege on any of its overloads, then ResolveFunction
+	// returns an error with code UndefinedFunction.
+	ResolveFunction(
+		ctx context.Context, name *tree.UnresolvedName, path sessiondata.SearchPath,
+	) (*tree.FunctionDefinition, error)
+
+	// ResolveFunctionByID locates the user-defined function with the given
+	// StableID and returns a definition with its single overload. Unlike
+	// ResolveFunction, it does not check the privileges of the current user,
+	// since it is used to resolve the functions of triggers, which are checked
+	// when the trigger is created.
+	ResolveFunctionByID(ctx context.Context, id StableID) (*tree.FunctionDefinition, error)
+
 	// CheckPrivilege verifies that the current user has the given privilege on
 	// the given catalog object. If not, then CheckPrivilege returns an error.
 	CheckPrivilege(ctx context.Context, o Object, priv privilege.Kind) error
@@ -134,6 +134,15 @@ type Table interface {
 	// i < UniqueCount.
 	Unique(i UniqueOrdinal) UniqueConstraint
 
+	// TriggerCount returns the number of row-level triggers defined on this
+	// table.
+	TriggerCount() int
+
+	// Trigger returns the ith trigger defined on this table, where
+	// i < TriggerCount. Triggers are ordered by name, which is the order in
+	// which they fire.
+	Trigger(i int) Trigger
+
 	// Zone returns a table's zone.
 	Zone() Zone
 
@@ -257,6 +266,10 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether the checking of the constraint can be
+	// postponed until the end of the transaction.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -302,6 +315,10 @@ type UniqueConstraint interface {
 	// satisfied when building functional dependencies for the table. This enables
 	// additional optimizations, such as omission of uniqueness checks.
 	UniquenessGuaranteedByAnotherIndex() bool
+
+	// Deferrability returns whether the checking of the constraint can be
+	// postponed until the end of the transaction.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueOrdinal identifies a unique constraint (in the context of a Table).
@@ -310,3 +327,35 @@ type UniqueOrdinal = int
 // UniqueOrdinals identifies a list of unique constraints (in the context of
 // a Table).
 type UniqueOrdinals = []UniqueOrdinal
+
+// Trigger is an interface to a row-level trigger defined on a table. A trigger
+// executes the body of a user-defined function for each row that is inserted,
+// updated, or deleted by a mutation of the table.
+type Trigger interface {
+	// Name of the trigger.
+	Name() tree.Name
+
+	// ActionTime returns whether the trigger fires before or after the row is
+	// mutated.
+	ActionTime() tree.TriggerActionTime
+
+	// HasEvent returns true if the trigger fires for the given event.
+	HasEvent(event tree.TriggerEventType) bool
+
+	// UpdateColumnCount returns the number of columns in the UPDATE OF clause
+	// of the trigger. If the count is zero, the trigger fires for an UPDATE
+	// event regardless of which columns are updated.
+	UpdateColumnCount() int
+
+	// UpdateColumnOrdinal returns the table column ordinal of the ith column in
+	// the UPDATE OF clause of the trigger, where i < UpdateColumnCount.
+	UpdateColumnOrdinal(tab Table, i int) int
+
+	// WhenExpr returns the WHEN condition of the trigger and true if the
+	// trigger has one. If it does not, the empty string and false are
+	// returned.
+	WhenExpr() (string, bool)
+
+	// FunctionID returns the stable identifier of the trigger function.
+	FunctionID() StableID
+}
@@ -169,6 +169,7 @@ func (cb *cascadeBuilder) setupCascade(cascade *memo.FKCascade) exec.Cascade {
 				ctx, semaCtx, evalCtx, execFactory, cascade, bufferRef, numBufferedRows, allowAutoCommit,
 			)
 		},
+		ForEachRow: cascade.ForEachRow,
 	}
 }
 
@@ -123,6 +123,10 @@ func (b *Builder) buildInsert(ins *memo.InsertExpr) (execPlan, error) {
 		return execPlan{}, err
 	}
 
+	if err := b.buildFKCascades(ins.WithID, ins.FKCascades); err != nil {
+		return execPlan{}, err
+	}
+
 	return ep, nil
 }
 
@@ -156,10 +160,17 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 		return execPlan{}, false, nil
 	}
 
+	// We cannot use the fast path if there are any post-queries (e.g. AFTER
+	// triggers), since they need the buffered input.
+	if len(ins.FKCascades) > 0 {
+		return execPlan{}, false, nil
+	}
+
 	md := b.mem.Metadata()
 	tab := md.Table(ins.Table)
 
 	//  - there are no self-referencing foreign keys;
+	//  - there are no deferrable foreign keys;
 	//  - all FK checks can be performed using direct lookups into unique indexes.
 	fkChecks := make([]exec.InsertFastPathFKCheck, len(ins.FKChecks))
 	for i := range ins.FKChecks {
@@ -169,6 +180,11 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 			return execPlan{}, false, nil
 		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
+		if fk.Deferrability() != tree.ConstraintNotDeferrable {
+			// The check may need to be postponed until the end of the
+			// transaction, which only the ErrorIfRows check plans support.
+			return execPlan{}, false, nil
+		}
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
 			// Not a lookup anti-join.
@@ -407,10 +423,14 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	// do not need to be fetched or separately updated (i.e. ups.FetchCols and
 	// ups.UpdateCols are both empty).
 	//
+	// If DeleteCol != 0, then the Upsert was built for a MERGE statement, and
+	// the existing rows for which the delete column is true are deleted rather
+	// than updated.
+	//
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
 	cnt := len(ups.InsertCols) + len(ups.FetchCols) + len(ups.UpdateCols) + len(ups.CheckCols) +
-		len(ups.PartialIndexPutCols) + len(ups.PartialIndexDelCols) + 1
+		len(ups.PartialIndexPutCols) + len(ups.PartialIndexDelCols) + 2
 	colList := make(opt.ColList, 0, cnt)
 	colList = appendColsWhenPresent(colList, ups.InsertCols)
 	colList = appendColsWhenPresent(colList, ups.FetchCols)
@@ -418,6 +438,9 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	if ups.CanaryCol != 0 {
 		colList = append(colList, ups.CanaryCol)
 	}
+	if ups.DeleteCol != 0 {
+		colList = append(colList, ups.DeleteCol)
+	}
 	colList = appendColsWhenPresent(colList, ups.CheckCols)
 	colList = appendColsWhenPresent(colList, ups.PartialIndexPutCols)
 	colList = appendColsWhenPresent(colList, ups.PartialIndexDelCols)
@@ -434,6 +457,10 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	if ups.CanaryCol != 0 {
 		canaryCol = input.getNodeColumnOrdinal(ups.CanaryCol)
 	}
+	deleteCol := exec.NodeColumnOrdinal(-1)
+	if ups.DeleteCol != 0 {
+		deleteCol = input.getNodeColumnOrdinal(ups.DeleteCol)
+	}
 	insertColOrds := ordinalSetFromColList(ups.InsertCols)
 	fetchColOrds := ordinalSetFromColList(ups.FetchCols)
 	updateColOrds := ordinalSetFromColList(ups.UpdateCols)
@@ -445,6 +472,7 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 		ups.ArbiterIndexes,
 		ups.ArbiterConstraints,
 		canaryCol,
+		deleteCol,
 		insertColOrds,
 		fetchColOrds,
 		updateColOrds,
@@ -490,8 +518,14 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	//
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
-	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PartialIndexDelCols))
+	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PassthroughCols)+len(del.PartialIndexDelCols))
 	colList = appendColsWhenPresent(colList, del.FetchCols)
+	// The RETURNING clause of the Delete can refer to the columns in any of the
+	// USING tables. As a result, the Delete may need to passthrough those
+	// columns so the projection above can use them.
+	if del.NeedResults() {
+		colList = append(colList, del.PassthroughCols...)
+	}
 	colList = appendColsWhenPresent(colList, del.PartialIndexDelCols)
 
 	input, err := b.buildMutationInput(del, del.Input, colList, &del.MutationPrivate)
@@ -504,11 +538,22 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	tab := md.Table(del.Table)
 	fetchColOrds := ordinalSetFromColList(del.FetchCols)
 	returnColOrds := ordinalSetFromColList(del.ReturnCols)
+
+	// Construct the result columns for the passthrough set.
+	var passthroughCols colinfo.ResultColumns
+	if del.NeedResults() {
+		for _, passthroughCol := range del.PassthroughCols {
+			colMeta := b.mem.Metadata().ColumnMeta(passthroughCol)
+			passthroughCols = append(passthroughCols, colinfo.ResultColumn{Name: colMeta.Alias, Typ: colMeta.Type})
+		}
+	}
+
 	node, err := b.factory.ConstructDelete(
 		input.root,
 		tab,
 		fetchColOrds,
 		returnColOrds,
+		passthroughCols,
 		b.allowAutoCommit && len(del.FKChecks) == 0 && len(del.FKCascades) == 0,
 	)
 	if err != nil {
@@ -697,7 +742,17 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 			for i, col := range c.KeyCols {
 				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
 			}
-			return mkUniqueCheckErr(md, c, keyVals)
+			err := mkUniqueCheckErr(md, c, keyVals)
+			tab := md.Table(c.Table)
+			if uc := tab.Unique(c.CheckOrdinal); uc.Deferrability() != tree.ConstraintNotDeferrable {
+				err = exec.NewDeferrableCheckError(
+					err, tab.ID(), uc.Name(),
+					uc.Deferrability() == tree.ConstraintInitiallyDeferred,
+					false, /* foreignKey */
+					keyVals,
+				)
+			}
+			return err
 		}
 		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
 		if err != nil {
@@ -723,7 +778,16 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 			for i, col := range c.KeyCols {
 				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
 			}
-			return mkFKCheckErr(md, c, keyVals)
+			err := mkFKCheckErr(md, c, keyVals)
+			if d := fkCheckDeferrability(md, c); d != tree.ConstraintNotDeferrable {
+				err = exec.NewDeferrableCheckError(
+					err, md.Table(c.OriginTable).ID(), fkCheckConstraintName(md, c),
+					d == tree.ConstraintInitiallyDeferred,
+					true, /* foreignKey */
+					keyVals,
+				)
+			}
+			return err
 		}
 		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
 		if err != nil {
@@ -734,6 +798,33 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	return nil
 }
 
+// fkCheckDeferrability returns the deferrability of the foreign key checked by
+// the given FK check. A check for an ON DELETE or ON UPDATE RESTRICT action is
+// never deferred, even if the constraint is.
+func fkCheckDeferrability(md *opt.Metadata, c *memo.FKChecksItem) tree.ConstraintDeferrability {
+	if c.FKOutbound {
+		return md.Table(c.OriginTable).OutboundForeignKey(c.FKOrdinal).Deferrability()
+	}
+	fk := md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal)
+	action := fk.UpdateReferenceAction()
+	if c.OpName == "delete" {
+		action = fk.DeleteReferenceAction()
+	}
+	if action == tree.Restrict {
+		return tree.ConstraintNotDeferrable
+	}
+	return fk.Deferrability()
+}
+
+// fkCheckConstraintName returns the name of the foreign key checked by the
+// given FK check.
+func fkCheckConstraintName(md *opt.Metadata, c *memo.FKChecksItem) string {
+	if c.FKOutbound {
+		return md.Table(c.OriginTable).OutboundForeignKey(c.FKOrdinal).Name()
+	}
+	return md.Table(c.ReferencedTable).InboundForeignKey(c.FKOrdinal).Name()
+}
+
 // mkUniqueCheckErr generates a user-friendly error describing a uniqueness
 // violation. The keyVals are the values that correspond to the
 // cat.UniqueConstraint columns.
@@ -211,7 +211,8 @@ type ApplyJoinPlanRightSideFn func(ef Factory, leftRow tree.Datums) (Plan, error
 // ConstructBuffer as an input; it should only be triggered if this buffer is
 // not empty.
 type Cascade struct {
-	// FKName is the name of the foreign key constraint.
+	// FKName is the name of the foreign key constraint, or of the trigger if the
+	// cascade executes an AFTER trigger.
 	FKName string
 
 	// Buffer is the Node returned by ConstructBuffer which stores the input to
@@ -243,6 +244,11 @@ type Cascade struct {
 		numBufferedRows int,
 		allowAutoCommit bool,
 	) (Plan, error)
+
+	// ForEachRow is true if PlanFn must be called separately for each row of
+	// the buffer, with a bufferRef which only contains that row (see
+	// memo.FKCascade.ForEachRow).
+	ForEachRow bool
 }
 
 // InsertFastPathFKCheck contains information about a foreign key check to be
@@ -269,6 +275,62 @@ type InsertFastPathFKCheck struct {
 // relevant row.
 type MkErrFn func(tree.Datums) error
 
+// DeferrableCheckError wraps the error produced by a check query (see
+// ConstructErrorIfRows) for a constraint that was declared DEFERRABLE. The
+// execution engine can use it to postpone the violation until the end of the
+// transaction if the constraint is currently deferred.
+type DeferrableCheckError struct {
+	cause error
+
+	// TableID is the ID of the table the constraint belongs to; for foreign
+	// keys, this is the origin (referencing) table.
+	TableID cat.StableID
+
+	// ConstraintName is the name of the violated constraint.
+	ConstraintName string
+
+	// InitiallyDeferred is true if the constraint was declared INITIALLY
+	// DEFERRED.
+	InitiallyDeferred bool
+
+	// ForeignKey is true if the constraint is a foreign key constraint, and
+	// false if it is a unique constraint.
+	ForeignKey bool
+
+	// KeyVals are the values of the constraint's columns in the violating row.
+	// For foreign keys, they are in the order of the origin columns.
+	KeyVals tree.Datums
+}
+
+// NewDeferrableCheckError wraps the given violation error in a
+// DeferrableCheckError.
+func NewDeferrableCheckError(
+	cause error,
+	tableID cat.StableID,
+	constraintName string,
+	initiallyDeferred bool,
+	foreignKey bool,
+	keyVals tree.Datums,
+) *DeferrableCheckError {
+	return &DeferrableCheckError{
+		cause:             cause,
+		TableID:           tableID,
+		ConstraintName:    constraintName,
+		InitiallyDeferred: initiallyDeferred,
+		ForeignKey:        foreignKey,
+		KeyVals:           keyVals,
+	}
+}
+
+// Error implements the error interface.
+func (e *DeferrableCheckError) Error() string { return e.cause.Error() }
+
+// Cause implements the causer interface.
+func (e *DeferrableCheckError) Cause() error { return e.cause }
+
+// Unwrap implements the wrapper interface.
+func (e *DeferrableCheckError) Unwrap() error { return e.cause }
+
 // ExplainFactory is an extension of Factory used when constructing a plan that
 // can be explained. It allows annotation of nodes with extra information.
 type ExplainFactory interface {
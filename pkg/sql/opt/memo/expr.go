@@ -1029,7 +1029,8 @@ type FKCascades []FKCascade
 // FKCascade stores metadata necessary for building a cascading query.
 // Cascading queries are built as needed, after the original query is executed.
 type FKCascade struct {
-	// FKName is the name of the FK constraint.
+	// FKName is the name of the FK constraint. For an AFTER trigger, which is
+	// planned in the same way as a cascade, it is the name of the trigger.
 	FKName string
 
 	// Builder is an object that can be used as the "optbuilder" for the cascading
@@ -1050,6 +1051,12 @@ type FKCascade struct {
 	// It is empty if the mutation is a deletion. Empty if the cascade does not
 	// require input.
 	NewValues opt.ColList
+
+	// ForEachRow is true if the query must be planned and executed separately
+	// for each buffered row of the mutation input, rather than once for all of
+	// them. It is used for AFTER triggers, whose body must observe the effects
+	// of the trigger executions for the previous rows.
+	ForEachRow bool
 }
 
 // CascadeBuilder is an interface used to construct a cascading query for a
@@ -213,7 +213,7 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		*WindowExpr, *OpaqueRelExpr, *OpaqueMutationExpr, *OpaqueDDLExpr,
 		*AlterTableSplitExpr, *AlterTableUnsplitExpr, *AlterTableUnsplitAllExpr,
 		*AlterTableRelocateExpr, *AlterRangeRelocateExpr, *ControlJobsExpr, *CancelQueriesExpr,
-		*CancelSessionsExpr, *CreateViewExpr, *ExportExpr:
+		*CancelSessionsExpr, *CreateViewExpr, *CreateFunctionExpr, *ExportExpr:
 		fmt.Fprintf(f.Buffer, "%v", e.Op())
 		FormatPrivate(f, e.Private(), required)
 
@@ -612,6 +612,9 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			}
 			if t.CanaryCol != 0 {
 				f.formatColList(e, tp, "canary column:", opt.ColList{t.CanaryCol})
+				if t.DeleteCol != 0 {
+					f.formatColList(e, tp, "delete column:", opt.ColList{t.DeleteCol})
+				}
 				f.formatOptionalColList(e, tp, "fetch columns:", t.FetchCols)
 				f.formatMutationCols(e, tp, "insert-mapping:", t.InsertCols, t.Table)
 				f.formatMutationCols(e, tp, "update-mapping:", t.UpdateCols, t.Table)
@@ -692,6 +695,13 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			n.Child(f.Buffer.String())
 		}
 
+	case *CreateFunctionExpr:
+		n := tp.Child("dependencies")
+		for _, dep := range t.Deps {
+			name := dep.DataSource.Name()
+			n.Child(name.String())
+		}
+
 	case *CreateStatisticsExpr:
 		tp.Child(t.Syntax.String())
 
@@ -1536,6 +1546,10 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 		schema := f.Memo.Metadata().Schema(t.Schema)
 		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.ViewName)
 
+	case *CreateFunctionPrivate:
+		schema := f.Memo.Metadata().Schema(t.Schema)
+		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.Syntax.FuncName.Object())
+
 	case *JoinPrivate:
 		// Nothing to show; flags are shown separately.
 
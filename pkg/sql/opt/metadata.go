@@ -320,7 +320,7 @@ func (md *Metadata) CheckDependencies(
 	}
 	// Check that all of the user defined types present have not changed.
 	for _, typ := range md.AllUserDefinedTypes() {
-		toCheck, err := catalog.ResolveTypeByOID(ctx, typ.Oid())
+		toCheck, err := catalog.ResolveTypeByOID(ctx, userDefinedTypeOID(typ))
 		if err != nil {
 			// Handle when the type no longer exists.
 			if pgerror.GetPGCode(err) == pgcode.UndefinedObject {
@@ -349,18 +349,29 @@ func (md *Metadata) Schema(schID SchemaID) cat.Schema {
 
 // AddUserDefinedType adds a user defined type to the metadata for this query.
 func (md *Metadata) AddUserDefinedType(typ *types.T) {
-	if !typ.UserDefined() {
+	if !typ.UserDefined() && !typ.IsDomain() {
 		return
 	}
 	if md.userDefinedTypes == nil {
 		md.userDefinedTypes = make(map[oid.Oid]struct{})
 	}
-	if _, ok := md.userDefinedTypes[typ.Oid()]; !ok {
-		md.userDefinedTypes[typ.Oid()] = struct{}{}
+	typOID := userDefinedTypeOID(typ)
+	if _, ok := md.userDefinedTypes[typOID]; !ok {
+		md.userDefinedTypes[typOID] = struct{}{}
 		md.userDefinedTypesSlice = append(md.userDefinedTypesSlice, typ)
 	}
 }
 
+// userDefinedTypeOID returns the OID of the descriptor backing the given user
+// defined type. Domains have the OID of their base type, so the OID of the
+// domain descriptor is stored in the type metadata instead.
+func userDefinedTypeOID(typ *types.T) oid.Oid {
+	if typ.IsDomain() {
+		return typ.TypeMeta.DomainData.TypeOID
+	}
+	return typ.Oid()
+}
+
 // AllUserDefinedTypes returns all user defined types contained in this query.
 func (md *Metadata) AllUserDefinedTypes() []*types.T {
 	return md.userDefinedTypesSlice
@@ -77,6 +77,18 @@ func (c *CustomFuncs) neededMutationCols(
 	if private.CanaryCol != 0 {
 		cols.Add(private.CanaryCol)
 	}
+	if private.DeleteCol != 0 {
+		cols.Add(private.DeleteCol)
+	}
+
+	// Add the input columns which are buffered for cascades and AFTER
+	// triggers. These are usually referenced by the mutation private as well,
+	// but AFTER triggers may need columns which are not (e.g. the new values
+	// of an UPSERT when there is no RETURNING clause).
+	for i := range private.FKCascades {
+		cols.UnionWith(private.FKCascades[i].OldValues.ToSet())
+		cols.UnionWith(private.FKCascades[i].NewValues.ToSet())
+	}
 
 	if private.WithID != 0 {
 		for i := range uniqueChecks {
@@ -222,7 +234,11 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 			}
 		}
 
-	case opt.DeleteOp:
+	}
+
+	// An Upsert with a delete column (built for a MERGE statement) deletes
+	// some of the existing rows, so it needs the same columns as a Delete.
+	if op == opt.DeleteOp || private.DeleteCol != 0 {
 		// Add in all strict key columns from all indexes, since these are needed
 		// to compose the keys of rows to delete. Include mutation indexes, since
 		// it is necessary to delete rows even from indexes that are being added
@@ -135,6 +135,18 @@ type Builder struct {
 	// using AST annotations.
 	qualifyDataSourceNamesInAST bool
 
+	// If set, we are processing the body of a function in a CREATE FUNCTION
+	// statement. Dependencies on relations are collected in viewDeps, and
+	// the OIDs of the user-defined functions called by the body are collected
+	// in funcDeps.
+	insideFuncDef bool
+	funcDeps      opt.ViewTypeDeps
+
+	// udfStack contains the OIDs of the user-defined functions whose bodies
+	// are currently being inlined. It is used to detect recursive calls,
+	// which cannot be inlined.
+	udfStack []oid.Oid
+
 	// isCorrelated is set to true if we already reported to telemetry that the
 	// query contains a correlated subquery.
 	isCorrelated bool
@@ -203,6 +215,12 @@ func (b *Builder) Build() (err error) {
 	}
 	b.semaCtx.TypeResolver = typeTracker
 
+	// User-defined functions are resolved through the catalog, since they are
+	// inlined while building the query. Only the optimizer can resolve them.
+	existingFunctionResolver := b.semaCtx.FunctionResolver
+	defer func() { b.semaCtx.FunctionResolver = existingFunctionResolver }()
+	b.semaCtx.FunctionResolver = b.catalog
+
 	// Special case for CannedOptPlan.
 	if canned, ok := b.stmt.(*tree.CannedOptPlan); ok {
 		b.factory.DisableOptimizations()
@@ -267,7 +285,8 @@ func (b *Builder) buildStmt(
 	if b.insideViewDef {
 		// A blocklist of statements that can't be used from inside a view.
 		switch stmt := stmt.(type) {
-		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
+		case *tree.Delete, *tree.Insert, *tree.Merge, *tree.Update, *tree.CreateTable, *tree.CreateView,
+			*tree.CreateFunction,
 			*tree.Split, *tree.Unsplit, *tree.Relocate, *tree.RelocateRange,
 			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions:
 			panic(pgerror.Newf(
@@ -293,6 +312,11 @@ func (b *Builder) buildStmt(
 			return b.buildInsert(stmt, inScope)
 		})
 
+	case *tree.Merge:
+		return b.processWiths(stmt.With, inScope, func(inScope *scope) *scope {
+			return b.buildMerge(stmt, inScope)
+		})
+
 	case *tree.Update:
 		return b.processWiths(stmt.With, inScope, func(inScope *scope) *scope {
 			return b.buildUpdate(stmt, inScope)
@@ -304,6 +328,9 @@ func (b *Builder) buildStmt(
 	case *tree.CreateView:
 		return b.buildCreateView(stmt, inScope)
 
+	case *tree.CreateFunction:
+		return b.buildCreateFunction(stmt, inScope)
+
 	case *tree.Explain:
 		return b.buildExplain(stmt, inScope)
 
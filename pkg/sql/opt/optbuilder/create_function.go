@@ -0,0 +1,172 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+func (b *Builder) buildCreateFunction(cf *tree.CreateFunction, inScope *scope) (outScope *scope) {
+	b.DisableMemoReuse = true
+	tn := cf.FuncName.ToTableName()
+	sch, _ := b.resolveSchemaForCreate(&tn)
+	schID := b.factory.Metadata().AddSchema(sch)
+
+	seenNames := make(map[tree.Name]struct{})
+	for i := range cf.Args {
+		if name := cf.Args[i].Name; name != "" {
+			if _, ok := seenNames[name]; ok {
+				panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"parameter name %q used more than once", name))
+			}
+			seenNames[name] = struct{}{}
+		}
+	}
+
+	// We build the body of the function to:
+	//  - check the body semantically, and
+	//  - collect the relations and functions it references in b.viewDeps and
+	//    b.funcDeps.
+	// The result is not otherwise used.
+	b.insideFuncDef = true
+	b.trackViewDeps = true
+	defer func() {
+		b.insideFuncDef = false
+		b.trackViewDeps = false
+		b.viewDeps = nil
+		b.viewTypeDeps = util.FastIntSet{}
+		b.funcDeps = util.FastIntSet{}
+	}()
+	if body := inlinableFunctionBody(cf); body != nil {
+		b.buildFunctionBody(cf, body)
+	}
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateFunction(
+		&memo.CreateFunctionPrivate{
+			Schema:   schID,
+			Syntax:   cf,
+			Deps:     b.viewDeps,
+			FuncDeps: b.funcDeps,
+		},
+	)
+	return outScope
+}
+
+// inlinableFunctionBody returns the body of the function defined by the given
+// statement if the function can be inlined by replaceUDF, or nil otherwise.
+// Statements which define other functions, such as trigger functions, or
+// which have invalid options are rejected or validated during execution.
+func inlinableFunctionBody(cf *tree.CreateFunction) *tree.Select {
+	if cf.ReturnType.IsSet || cf.ReturnType.IsTrigger() {
+		return nil
+	}
+	var lang tree.FunctionLanguage
+	var body string
+	for _, option := range cf.Options {
+		switch t := option.(type) {
+		case tree.FunctionLanguage:
+			lang = t
+		case tree.FunctionBodyStr:
+			body = string(t)
+		}
+	}
+	if !strings.EqualFold(string(lang), string(tree.FunctionLangSQL)) || body == "" {
+		return nil
+	}
+	stmts, err := parser.Parse(body)
+	if err != nil || len(stmts) != 1 {
+		return nil
+	}
+	sel, _ := stmts[0].AST.(*tree.Select)
+	return sel
+}
+
+// buildFunctionBody builds the body of the function defined by the given
+// statement in the same way as a call to the function with NULL arguments
+// is inlined by replaceUDF.
+func (b *Builder) buildFunctionBody(cf *tree.CreateFunction, body *tree.Select) {
+	argTypes := make(tree.ArgTypes, len(cf.Args))
+	args := make(tree.Exprs, len(cf.Args))
+	for i := range cf.Args {
+		typ, err := tree.ResolveType(b.ctx, cf.Args[i].Type, b.semaCtx.GetTypeResolver())
+		if err != nil {
+			panic(err)
+		}
+		argTypes[i].Name = string(cf.Args[i].Name)
+		argTypes[i].Typ = typ
+		args[i] = tree.DNull
+	}
+	retType, err := tree.ResolveType(b.ctx, cf.ReturnType.Type, b.semaCtx.GetTypeResolver())
+	if err != nil {
+		panic(err)
+	}
+	// User-defined types cannot be used in the signature of a function; this
+	// is reported during execution.
+	if retType.UserDefined() {
+		return
+	}
+	for i := range argTypes {
+		if argTypes[i].Typ.UserDefined() {
+			return
+		}
+	}
+
+	// The existing definition of a function which is being replaced may be
+	// called by the new body, directly or through other functions. Such calls
+	// would become recursive once the function is replaced.
+	if cf.IsReplace {
+		if def, err := b.catalog.ResolveFunction(
+			b.ctx, cf.FuncName.ToUnresolvedName(), b.semaCtx.SearchPath,
+		); err == nil {
+			for _, impl := range def.Definition {
+				if o := impl.(*tree.Overload); o.Types.Match(argTypes.Types()) {
+					b.udfStack = append(b.udfStack, o.Oid)
+					defer func() { b.udfStack = b.udfStack[:len(b.udfStack)-1] }()
+				}
+			}
+		}
+	}
+
+	name := cf.FuncName.Object()
+	b.buildStmtAtRoot(
+		makeUDFSelect(name, body, argTypes, args, true /* calledOnNullInput */, retType),
+		nil, /* desiredTypes */
+	)
+
+	// The function does not record dependencies on user-defined types, so
+	// they may only be referenced through the columns of the relations which
+	// the function depends on, which cannot be dropped while in use.
+	typeDeps := b.viewTypeDeps.Copy()
+	for _, d := range b.viewDeps {
+		d.ColumnOrdinals.ForEach(func(ord int) {
+			ids, err := d.DataSource.CollectTypes(ord)
+			if err != nil {
+				panic(err)
+			}
+			for _, id := range ids {
+				typeDeps.Remove(int(id))
+			}
+		})
+	}
+	if !typeDeps.Empty() {
+		panic(unimplemented.Newf("user-defined functions",
+			"user-defined types cannot be referenced by the body of function %s()", name))
+	}
+}
@@ -11,6 +11,7 @@
 package optbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -27,6 +28,11 @@ import (
 // limit. The ORDER BY makes no additional guarantees about the order in which
 // mutations are applied, or the order of any returned rows (i.e. it won't
 // become a physical property required of the Delete operator).
+//
+// The tables of the USING clause are joined with the target table in the same
+// way as the FROM clause of an UPDATE (see buildInputForDelete). If the join
+// produces more than one row for a row of the target table, the row is only
+// deleted once.
 func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope) {
 	// UX friendliness safeguard.
 	if del.Where == nil && b.evalCtx.SessionData().SafeUpdates {
@@ -58,11 +64,11 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 	// Build the input expression that selects the rows that will be deleted:
 	//
 	//   WITH <with>
-	//   SELECT <cols> FROM <table> WHERE <where>
+	//   SELECT <cols> FROM <table>, <using> WHERE <where>
 	//   ORDER BY <order-by> LIMIT <limit>
 	//
 	// All columns from the delete table will be projected.
-	mb.buildInputForDelete(inScope, del.Table, del.Where, del.Limit, del.OrderBy)
+	mb.buildInputForDelete(inScope, del.Table, del.Using, del.Where, del.Limit, del.OrderBy)
 
 	// Build the final delete statement, including any returned expressions.
 	if resultsNeeded(del.Returning) {
@@ -77,12 +83,22 @@ func (b *Builder) buildDelete(del *tree.Delete, inScope *scope) (outScope *scope
 // buildDelete constructs a Delete operator, possibly wrapped by a Project
 // operator that corresponds to the given RETURNING clause.
 func (mb *mutationBuilder) buildDelete(returning tree.ReturningExprs) {
+	// Apply any BEFORE triggers, which may skip the deletion of some rows.
+	mb.buildBeforeTriggers(tree.TriggerEventDelete)
+
 	mb.buildFKChecksAndCascadesForDelete()
 
+	mb.buildAfterTriggers(opt.DeleteOp)
+
 	// Project partial index DEL boolean columns.
 	mb.projectPartialIndexDelCols()
 
 	private := mb.makeMutationPrivate(returning != nil)
+	for _, col := range mb.extraAccessibleCols {
+		if col.id != 0 {
+			private.PassthroughCols = append(private.PassthroughCols, col.id)
+		}
+	}
 	mb.outScope.expr = mb.b.factory.ConstructDelete(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
 	)
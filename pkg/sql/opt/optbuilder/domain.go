@@ -0,0 +1,141 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+const checkDomainValueFuncName = "crdb_internal.check_domain_value"
+
+// buildDomainChecks wraps the given expression, which is the result of a cast
+// to the DOMAIN type typ, with checks of the NOT NULL and CHECK constraints of
+// the domain. Each constraint is checked by a call to
+// crdb_internal.check_domain_value, which returns the value unchanged or
+// raises an error if the constraint is violated.
+func (b *Builder) buildDomainChecks(
+	value opt.ScalarExpr, typ *types.T, inScope *scope, colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	d, err := schemaexpr.DomainFromType(typ)
+	if err != nil {
+		panic(err)
+	}
+	domainName := typ.TypeMeta.Name.Basename()
+
+	out := value
+	if d.NotNull {
+		out = b.buildCheckDomainValue(
+			out,
+			b.factory.ConstructIsNot(value, memo.NullSingleton),
+			typ,
+			pgcode.NotNullViolation,
+			fmt.Sprintf("domain %s does not allow null values", domainName),
+		)
+	}
+	for _, c := range d.Checks {
+		// Substitute the already built value for VALUE in the check expression.
+		expr, err := schemaexpr.ReplaceDomainValue(c.Expr, &domainValue{
+			typ:  d.BaseType.(*types.T),
+			expr: value,
+		})
+		if err != nil {
+			panic(err)
+		}
+		texpr := inScope.resolveAndRequireType(expr, types.Bool)
+		ok := b.buildScalar(texpr, inScope, nil, nil, colRefs)
+		out = b.buildCheckDomainValue(
+			out,
+			ok,
+			typ,
+			pgcode.CheckViolation,
+			fmt.Sprintf("value for domain %s violates check constraint %q", domainName, string(c.Name)),
+		)
+	}
+	return out
+}
+
+// buildCheckDomainValue builds a call to crdb_internal.check_domain_value
+// which returns value if ok is not false, and otherwise raises an error with
+// the given code and message.
+func (b *Builder) buildCheckDomainValue(
+	value, ok opt.ScalarExpr, typ *types.T, code pgcode.Code, msg string,
+) opt.ScalarExpr {
+	props, overloads := builtins.GetBuiltinProperties(checkDomainValueFuncName)
+	return b.factory.ConstructFunction(
+		memo.ScalarListExpr{
+			value,
+			ok,
+			b.factory.ConstructConstVal(tree.NewDString(code.String()), types.String),
+			b.factory.ConstructConstVal(tree.NewDString(msg), types.String),
+		},
+		&memo.FunctionPrivate{
+			Name:       checkDomainValueFuncName,
+			Typ:        typ,
+			Properties: props,
+			Overload:   &overloads[0],
+		},
+	)
+}
+
+// domainValue is a placeholder for VALUE in the CHECK constraints of a
+// domain. It refers to the already built expression being checked.
+type domainValue struct {
+	typ  *types.T
+	expr opt.ScalarExpr
+}
+
+var _ tree.TypedExpr = &domainValue{}
+
+// String is part of the tree.Expr interface.
+func (v *domainValue) String() string {
+	return tree.AsString(v)
+}
+
+// Format is part of the tree.Expr interface.
+func (v *domainValue) Format(ctx *tree.FmtCtx) {
+	ctx.WriteString("VALUE")
+}
+
+// Walk is part of the tree.Expr interface.
+func (v *domainValue) Walk(_ tree.Visitor) tree.Expr {
+	return v
+}
+
+// TypeCheck is part of the tree.Expr interface.
+func (v *domainValue) TypeCheck(
+	_ context.Context, _ *tree.SemaContext, _ *types.T,
+) (tree.TypedExpr, error) {
+	return v, nil
+}
+
+// ResolvedType is part of the tree.TypedExpr interface.
+func (v *domainValue) ResolvedType() *types.T {
+	return v.typ
+}
+
+// Eval is part of the tree.TypedExpr interface.
+func (*domainValue) Eval(_ *tree.EvalContext) (tree.Datum, error) {
+	panic(errors.AssertionFailedf("domainValue must be replaced before evaluation"))
+}
+
+// Variable is part of the tree.VariableExpr interface. This prevents the
+// value from being evaluated during normalization.
+func (*domainValue) Variable() {}
@@ -458,6 +458,7 @@ func (cb *onDeleteSetBuilder) Build(
 			}
 		}
 		mb.addUpdateCols(updateExprs)
+		mb.addSynthesizedColsForUpdate()
 
 		// TODO(radu): consider plumbing a flag to prevent building the FK check
 		// against the parent we are cascading from. Need to investigate in which
@@ -679,6 +680,7 @@ func (cb *onUpdateCascadeBuilder) Build(
 			}
 		}
 		mb.addUpdateCols(updateExprs)
+		mb.addSynthesizedColsForUpdate()
 
 		mb.buildUpdate(nil /* returning */)
 		return mb.outScope.expr
@@ -96,6 +96,12 @@ type groupby struct {
 	// projects that expression.
 	groupStrs groupByStrSet
 
+	// nulledStrs contains a string representation of each grouping expression
+	// of a GROUP BY clause with grouping sets which is not part of the grouping
+	// set being built. Each string maps to a column in the aggOutScope which
+	// projects NULL in place of the expression. See buildGroupingSets.
+	nulledStrs groupByStrSet
+
 	// buildingGroupingCols is true while the grouping columns are being built.
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
@@ -322,17 +328,42 @@ func (b *Builder) constructGroupBy(
 	return b.factory.ConstructGroupBy(input, aggs, &private)
 }
 
+// projectNulledGroupingCols projects the NULL values of the grouping
+// expressions which are not part of the grouping set being built (see
+// groupby.nulledStrs) on top of the aggregation in g.aggOutScope.
+func (b *Builder) projectNulledGroupingCols(g *groupby) {
+	if len(g.nulledStrs) == 0 {
+		return
+	}
+	var nulledCols opt.ColSet
+	for _, col := range g.nulledStrs {
+		nulledCols.Add(col.id)
+	}
+	projections := make(memo.ProjectionsExpr, 0, len(g.nulledStrs))
+	for i := range g.aggOutScope.cols {
+		if col := &g.aggOutScope.cols[i]; nulledCols.Contains(col.id) {
+			projections = append(projections, b.factory.ConstructProjectionsItem(col.scalar, col.id))
+		}
+	}
+	input := g.aggOutScope.expr
+	g.aggOutScope.expr = b.factory.ConstructProject(
+		input, projections, input.Relational().OutputCols,
+	)
+}
+
 // buildGroupingColumns builds the grouping columns and adds them to the
 // groupby scopes that will be used to build the aggregation expression.
 // Returns the slice of grouping columns.
-func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope, fromScope *scope) {
+func (b *Builder) buildGroupingColumns(
+	sel *tree.SelectClause, nulledGroupBy tree.Exprs, projectionsScope, fromScope *scope,
+) {
 	if fromScope.groupby == nil {
 		fromScope.initGrouping()
 	}
 	g := fromScope.groupby
 
 	// The "from" columns are visible to any grouping expressions.
-	b.buildGroupingList(sel.GroupBy, sel.Exprs, projectionsScope, fromScope)
+	b.buildGroupingList(sel.GroupBy, nulledGroupBy, sel.Exprs, projectionsScope, fromScope)
 
 	// Copy the grouping columns to the aggOutScope.
 	g.aggOutScope.appendColumns(g.groupingCols())
@@ -436,6 +467,7 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 		aggCols,
 		g.aggInScope.ordering,
 	)
+	b.projectNulledGroupingCols(g)
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -483,14 +515,22 @@ func (b *Builder) buildHaving(having tree.TypedExpr, fromScope *scope) opt.Scala
 // GROUP BY expressions, adding the group-by expressions as columns to
 // aggInScope and populating groupStrs.
 //
-// groupBy   The given GROUP BY expressions.
-// selects   The select expressions are needed in case one of the GROUP BY
-//           expressions is an index into to the select list. For example,
-//               SELECT count(*), k FROM t GROUP BY 2
-//           indicates that the grouping is on the second select expression, k.
-// fromScope The scope for the input to the aggregation (the FROM clause).
+// groupBy       The given GROUP BY expressions.
+// nulledGroupBy The grouping expressions which are not part of the grouping
+//               set being built, if the GROUP BY clause has grouping sets.
+//               They are added to nulledStrs rather than to aggInScope.
+// selects       The select expressions are needed in case one of the GROUP BY
+//               expressions is an index into to the select list. For example,
+//                   SELECT count(*), k FROM t GROUP BY 2
+//               indicates that the grouping is on the second select
+//               expression, k.
+// fromScope     The scope for the input to the aggregation (the FROM clause).
 func (b *Builder) buildGroupingList(
-	groupBy tree.GroupBy, selects tree.SelectExprs, projectionsScope *scope, fromScope *scope,
+	groupBy tree.GroupBy,
+	nulledGroupBy tree.Exprs,
+	selects tree.SelectExprs,
+	projectionsScope *scope,
+	fromScope *scope,
 ) {
 	g := fromScope.groupby
 	g.groupStrs = make(groupByStrSet, len(groupBy))
@@ -508,7 +548,13 @@ func (b *Builder) buildGroupingList(
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
 	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope, false /* nulled */)
+	}
+	if len(nulledGroupBy) > 0 {
+		g.nulledStrs = make(groupByStrSet, len(nulledGroupBy))
+		for _, e := range nulledGroupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope, true /* nulled */)
+		}
 	}
 	g.buildingGroupingCols = false
 }
@@ -527,8 +573,14 @@ func (b *Builder) buildGroupingList(
 //                  clause).
 // aggInScope       The scope that will contain the grouping expressions as well
 //                  as the aggregate function arguments.
+// nulled           True if the expression is not part of the grouping set
+//                  being built, in which case it is projected as NULL on top
+//                  of the aggregation instead of being added to aggInScope.
 func (b *Builder) buildGrouping(
-	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
+	groupBy tree.Expr,
+	selects tree.SelectExprs,
+	projectionsScope, fromScope, aggInScope *scope,
+	nulled bool,
 ) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
@@ -631,6 +683,20 @@ func (b *Builder) buildGrouping(
 			continue
 		}
 
+		if nulled {
+			// The expression is also skipped if it is part of the grouping set
+			// (see above), since the grouping columns are built first.
+			g := fromScope.groupby
+			if _, ok := g.nulledStrs[exprStr]; ok {
+				continue
+			}
+			typ := e.ResolvedType()
+			g.nulledStrs[exprStr] = b.synthesizeColumn(
+				g.aggOutScope, scopeColName(tree.Name(alias)), typ, e, b.factory.ConstructNull(typ),
+			)
+			continue
+		}
+
 		// Save a representation of the GROUP BY expression for validation of the
 		// SELECT and HAVING expressions. This enables queries such as:
 		//   SELECT x+y FROM t GROUP BY x+y
@@ -640,6 +706,43 @@ func (b *Builder) buildGrouping(
 	}
 }
 
+// buildGroupingFunc builds a call to the grouping() builtin, which is replaced
+// with a constant bit mask. The bit corresponding to each argument is set if
+// the argument is a grouping expression which is not part of the grouping set
+// being built, with the last argument corresponding to the least-significant
+// bit. For example, in:
+//
+//   SELECT a, b, grouping(a, b) FROM t GROUP BY ROLLUP (a, b)
+//
+// grouping(a, b) is 0 for the (a, b) grouping set, 1 for the (a) grouping set
+// and 3 for the empty grouping set.
+func (b *Builder) buildGroupingFunc(
+	f *tree.FuncExpr, inScope, outScope *scope, outCol *scopeColumn,
+) opt.ScalarExpr {
+	g := inScope.groupby
+	if g == nil || inScope.inAgg || g.buildingGroupingCols {
+		panic(errGroupingArgs)
+	}
+	if len(f.Exprs) > 31 {
+		panic(pgerror.New(pgcode.TooManyArguments, "GROUPING must have fewer than 32 arguments"))
+	}
+	var mask int64
+	for _, e := range f.Exprs {
+		mask <<= 1
+		exprStr := symbolicExprStr(e)
+		if _, ok := g.nulledStrs[exprStr]; ok {
+			mask |= 1
+		} else if _, ok := g.groupStrs[exprStr]; !ok {
+			panic(errGroupingArgs)
+		}
+	}
+	out := b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(mask)), types.Int)
+	return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+}
+
+var errGroupingArgs = pgerror.New(pgcode.Grouping,
+	"arguments to GROUPING must be grouping expressions of the associated query level")
+
 // buildAggArg builds a scalar expression which is used as an input in some form
 // to an aggregate expression. The scopeColumn for the built expression will
 // be added to tempScope.
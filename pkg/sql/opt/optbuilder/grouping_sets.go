@@ -0,0 +1,305 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// maxGroupingSets is the maximum number of grouping sets that a GROUP BY
+// clause can expand to. This is the same limit as in Postgres.
+const maxGroupingSets = 4096
+
+var errTooManyGroupingSets = pgerror.Newf(pgcode.StatementTooComplex,
+	"too many grouping sets present (maximum %d)", maxGroupingSets)
+
+// hasGroupingSets returns true if the given GROUP BY clause has a ROLLUP, CUBE
+// or GROUPING SETS item.
+func hasGroupingSets(groupBy tree.GroupBy) bool {
+	for _, e := range groupBy {
+		if _, ok := e.(*tree.GroupingSet); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// buildGroupingSets builds a select clause with a GROUP BY clause that has
+// ROLLUP, CUBE or GROUPING SETS items. The GROUP BY clause is expanded into the
+// list of its grouping sets, and the select clause is built as a UNION ALL of
+// one aggregation per grouping set. The grouping expressions which are not part
+// of a grouping set evaluate to NULL in the corresponding aggregation. The input
+// of the aggregations (the FROM and WHERE clauses) is only computed once, and is
+// shared by the aggregations through a With binding. For example:
+//
+//   SELECT a, b, sum(c) FROM t GROUP BY ROLLUP (a, b)
+//
+// is built as:
+//
+//   WITH w AS (SELECT * FROM t)
+//   SELECT a, b, sum(c) FROM w GROUP BY a, b
+//   UNION ALL
+//   SELECT a, NULL, sum(c) FROM w GROUP BY a
+//   UNION ALL
+//   SELECT NULL, NULL, sum(c) FROM w GROUP BY ()
+//
+// The ORDER BY and DISTINCT clauses apply to the result of the UNION ALL.
+//
+// fromScope contains the FROM and WHERE clauses. See Builder.buildStmt for a
+// description of the remaining input and return values.
+func (b *Builder) buildGroupingSets(
+	sel *tree.SelectClause,
+	orderBy tree.OrderBy,
+	locking lockingSpec,
+	desiredTypes []*types.T,
+	fromScope *scope,
+) (outScope *scope) {
+	if sel.DistinctOn != nil {
+		panic(unimplementedWithIssueDetailf(46280, "distinct on",
+			"DISTINCT ON is not supported with grouping sets"))
+	}
+
+	sets, groupingExprs := expandGroupingSets(sel.GroupBy)
+
+	withID := b.factory.Memo().NextWithID()
+	b.factory.Metadata().AddWithBinding(withID, fromScope.expr)
+
+	for i := range sets {
+		// The DISTINCT clause is applied to the result of the UNION ALL below.
+		branch := *sel
+		branch.GroupBy = sets[i]
+		branch.Distinct = false
+
+		branchFromScope := b.buildGroupingSetInput(withID, fromScope)
+		branchScope := b.buildSelectClauseFromScope(
+			&branch, orderBy, locking, desiredTypes, branchFromScope, groupingExprs,
+		)
+		if len(branchFromScope.windows) > 0 {
+			panic(unimplementedWithIssueDetailf(46280, "window functions",
+				"window functions are not supported with grouping sets"))
+		}
+
+		if outScope == nil {
+			outScope = branchScope
+		} else {
+			outScope = b.buildGroupingSetsUnion(outScope, branchScope)
+		}
+	}
+
+	outScope.expr = b.factory.ConstructWith(
+		fromScope.expr, outScope.expr, &memo.WithPrivate{ID: withID},
+	)
+
+	if sel.Distinct {
+		outScope.expr = b.constructDistinct(outScope)
+	}
+	return outScope
+}
+
+// buildGroupingSetInput returns a copy of fromScope with new column IDs, which
+// scans the given With binding of the expression of fromScope.
+func (b *Builder) buildGroupingSetInput(withID opt.WithID, fromScope *scope) *scope {
+	md := b.factory.Metadata()
+	inScope := fromScope.replace()
+	inScope.windowDefs = fromScope.windowDefs
+	inScope.cols = make([]scopeColumn, 0, len(fromScope.cols))
+
+	var inCols, outCols opt.ColList
+	newIDs := make(map[opt.ColumnID]opt.ColumnID, len(fromScope.cols))
+	for _, col := range fromScope.cols {
+		id, ok := newIDs[col.id]
+		if !ok {
+			id = md.AddColumn(col.name.MetadataName(), col.typ)
+			newIDs[col.id] = id
+			inCols = append(inCols, col.id)
+			outCols = append(outCols, id)
+		}
+		// Similar to appendColumnsFromScope, but with re-numbering the column IDs.
+		col.id = id
+		col.scalar = nil
+		inScope.cols = append(inScope.cols, col)
+	}
+
+	inScope.expr = b.factory.ConstructWithScan(&memo.WithScanPrivate{
+		With:    withID,
+		InCols:  inCols,
+		OutCols: outCols,
+		ID:      md.NextUniqueID(),
+	})
+	return inScope
+}
+
+// buildGroupingSetsUnion builds a UNION ALL of the aggregations of two grouping
+// sets. The ORDER BY columns of the inputs (including extra columns which are
+// not projected) are passed through the union, so that the ordering can be
+// applied to its result.
+func (b *Builder) buildGroupingSetsUnion(leftScope, rightScope *scope) (outScope *scope) {
+	md := b.factory.Metadata()
+	outScope = leftScope.replace()
+
+	newCol := func(c *scopeColumn) scopeColumn {
+		return scopeColumn{
+			name: c.name,
+			typ:  c.typ,
+			id:   md.AddColumn(c.name.MetadataName(), c.typ),
+		}
+	}
+	outScope.cols = make([]scopeColumn, len(leftScope.cols))
+	for i := range leftScope.cols {
+		outScope.cols[i] = newCol(&leftScope.cols[i])
+	}
+	if len(leftScope.extraCols) > 0 {
+		outScope.extraCols = make([]scopeColumn, len(leftScope.extraCols))
+		for i := range leftScope.extraCols {
+			outScope.extraCols[i] = newCol(&leftScope.extraCols[i])
+		}
+	}
+
+	leftCols := append(colsToColList(leftScope.cols), colsToColList(leftScope.extraCols)...)
+	rightCols := append(colsToColList(rightScope.cols), colsToColList(rightScope.extraCols)...)
+	newCols := append(colsToColList(outScope.cols), colsToColList(outScope.extraCols)...)
+	if len(leftCols) != len(rightCols) {
+		panic(errors.AssertionFailedf(
+			"grouping sets have different numbers of columns: %d and %d", len(leftCols), len(rightCols),
+		))
+	}
+
+	if len(leftScope.ordering) > 0 {
+		outScope.ordering = make(opt.Ordering, len(leftScope.ordering))
+		for i, col := range leftScope.ordering {
+			idx, ok := leftCols.Find(col.ID())
+			if !ok {
+				panic(errors.AssertionFailedf("ordering column %d not found", col.ID()))
+			}
+			outScope.ordering[i] = opt.MakeOrderingColumn(newCols[idx], col.Descending())
+		}
+	}
+
+	outScope.expr = b.factory.ConstructUnionAll(
+		leftScope.expr,
+		rightScope.expr,
+		&memo.SetPrivate{LeftCols: leftCols, RightCols: rightCols, OutCols: newCols},
+	)
+	return outScope
+}
+
+// expandGroupingSets expands a GROUP BY clause with ROLLUP, CUBE or GROUPING
+// SETS items into the list of its grouping sets, each of which is a GROUP BY
+// clause without grouping sets. The grouping sets are the cross product of the
+// grouping sets of each item. For example:
+//
+//   GROUP BY a, ROLLUP (b, c)
+//
+// is expanded into:
+//
+//   GROUP BY a, b, c
+//   GROUP BY a, b
+//   GROUP BY a
+//
+// expandGroupingSets also returns all of the grouping expressions which appear
+// in the GROUP BY clause.
+func expandGroupingSets(groupBy tree.GroupBy) (sets []tree.GroupBy, exprs tree.Exprs) {
+	sets = []tree.GroupBy{nil}
+	for _, item := range groupBy {
+		itemSets := expandGroupingSetItem(item)
+		if len(sets)*len(itemSets) > maxGroupingSets {
+			panic(errTooManyGroupingSets)
+		}
+		product := make([]tree.GroupBy, 0, len(sets)*len(itemSets))
+		for _, set := range sets {
+			for _, itemSet := range itemSets {
+				newSet := make(tree.GroupBy, 0, len(set)+len(itemSet))
+				newSet = append(newSet, set...)
+				product = append(product, append(newSet, itemSet...))
+			}
+		}
+		sets = product
+		exprs = appendGroupingExprs(exprs, item)
+	}
+
+	for i := range sets {
+		if len(sets[i]) == 0 {
+			// The empty grouping set is equivalent to GROUP BY (), which aggregates
+			// all of the rows into a single group.
+			sets[i] = tree.GroupBy{&tree.Tuple{}}
+		}
+	}
+	return sets, exprs
+}
+
+// expandGroupingSetItem returns the grouping sets of a single GROUP BY item.
+func expandGroupingSetItem(item tree.Expr) []tree.GroupBy {
+	gs, ok := item.(*tree.GroupingSet)
+	if !ok {
+		return []tree.GroupBy{{item}}
+	}
+
+	switch gs.Type {
+	case tree.RollupGroupingSet:
+		// ROLLUP (a, b) is equivalent to GROUPING SETS ((a, b), (a), ()).
+		sets := make([]tree.GroupBy, 0, len(gs.Exprs)+1)
+		for i := len(gs.Exprs); i >= 0; i-- {
+			sets = append(sets, tree.GroupBy(gs.Exprs[:i]))
+		}
+		return sets
+
+	case tree.CubeGroupingSet:
+		// CUBE (a, b) is equivalent to GROUPING SETS ((a, b), (a), (b), ()).
+		n := len(gs.Exprs)
+		if n >= 31 || 1<<n > maxGroupingSets {
+			panic(errTooManyGroupingSets)
+		}
+		sets := make([]tree.GroupBy, 0, 1<<n)
+		for mask := 1<<n - 1; mask >= 0; mask-- {
+			var set tree.GroupBy
+			for i, e := range gs.Exprs {
+				if mask&(1<<(n-1-i)) != 0 {
+					set = append(set, e)
+				}
+			}
+			sets = append(sets, set)
+		}
+		return sets
+
+	case tree.ExplicitGroupingSets:
+		// Nested grouping sets are flattened. For example, GROUPING SETS (a,
+		// ROLLUP (b)) is equivalent to GROUPING SETS ((a), (b), ()).
+		var sets []tree.GroupBy
+		for _, e := range gs.Exprs {
+			sets = append(sets, expandGroupingSetItem(e)...)
+			if len(sets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+		}
+		return sets
+
+	default:
+		panic(errors.AssertionFailedf("unknown grouping set type %d", gs.Type))
+	}
+}
+
+// appendGroupingExprs appends the grouping expressions of the given GROUP BY
+// item to exprs.
+func appendGroupingExprs(exprs tree.Exprs, item tree.Expr) tree.Exprs {
+	if gs, ok := item.(*tree.GroupingSet); ok {
+		for _, e := range gs.Exprs {
+			exprs = appendGroupingExprs(exprs, e)
+		}
+		return exprs
+	}
+	return append(exprs, item)
+}
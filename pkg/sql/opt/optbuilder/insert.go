@@ -331,10 +331,15 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 		mb.buildInputForUpsert(inScope, ins.OnConflict, ins.OnConflict.Where)
 
 		// Derive the columns that will be updated from the SET expressions.
-		mb.addTargetColsForUpdate(ins.OnConflict.Exprs)
+		exprs := mb.expandFieldUpdates(ins.OnConflict.Exprs)
+		mb.addTargetColsForUpdate(exprs)
 
 		// Build each of the SET expressions.
-		mb.addUpdateCols(ins.OnConflict.Exprs)
+		mb.addUpdateCols(exprs)
+
+		// Add additional columns for computed expressions that may depend on the
+		// updated columns.
+		mb.addSynthesizedColsForUpdate()
 
 		// Build the final upsert statement, including any returned expressions.
 		mb.buildUpsert(returning)
@@ -355,6 +360,7 @@ func (b *Builder) buildInsert(ins *tree.Insert, inScope *scope) (outScope *scope
 //      values specified for them.
 //   4. Each update value is the same as the corresponding insert value.
 //   5. There are no inbound foreign keys containing non-key columns.
+//   6. There are no triggers on the table.
 //
 // TODO(andyk): The fast path is currently only enabled when the UPSERT alias
 // is explicitly selected by the user. It's possible to fast path some queries
@@ -366,6 +372,12 @@ func (mb *mutationBuilder) needExistingRows() bool {
 		return true
 	}
 
+	// Triggers need the existing values of updated rows, and need to be able
+	// to tell inserted rows from updated rows.
+	if mb.tab.TriggerCount() > 0 {
+		return true
+	}
+
 	// If there are any implicit partitioning columns in the primary index,
 	// these columns will need to be fetched.
 	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
@@ -660,11 +672,18 @@ func (mb *mutationBuilder) addSynthesizedColsForInsert() {
 	// Add assignment casts for default column values.
 	mb.addAssignmentCasts(mb.insertColIDs)
 
+	// Apply any BEFORE triggers, which may modify the inserted values before
+	// computed columns are derived from them.
+	mb.buildBeforeTriggers(tree.TriggerEventInsert)
+
 	// Now add all computed columns.
 	mb.addSynthesizedComputedCols(mb.insertColIDs, false /* restrict */)
 
 	// Add assignment casts for computed column values.
 	mb.addAssignmentCasts(mb.insertColIDs)
+
+	// Check the constraints of the DOMAIN types of the inserted columns.
+	mb.addDomainChecks(mb.insertColIDs)
 }
 
 // buildInsert constructs an Insert operator, possibly wrapped by a Project
@@ -684,6 +703,8 @@ func (mb *mutationBuilder) buildInsert(returning tree.ReturningExprs) {
 
 	mb.buildFKChecksForInsert()
 
+	mb.buildAfterTriggers(opt.InsertOp)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructInsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -907,6 +928,8 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 
 	mb.buildFKChecksForUpsert()
 
+	mb.buildAfterTriggers(opt.UpsertOp)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	mb.outScope.expr = mb.b.factory.ConstructUpsert(
 		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
@@ -933,7 +956,8 @@ func (mb *mutationBuilder) buildUpsert(returning tree.ReturningExprs) {
 // For each column, a CASE expression is created that toggles between the insert
 // and update values depending on whether the canary column is null. These
 // columns can then feed into any constraint checking expressions, which operate
-// on the final result values.
+// on the final result values. For a MERGE which deletes rows, the values are
+// NULL for the rows for which the delete column is true.
 func (mb *mutationBuilder) projectUpsertColumns() {
 	projectionsScope := mb.outScope.replace()
 	projectionsScope.appendColumnsFromScope(mb.outScope)
@@ -952,8 +976,9 @@ func (mb *mutationBuilder) projectUpsertColumns() {
 			continue
 		}
 
-		// Skip columns where the insert value and update value are the same.
-		if insertColID == updateColID {
+		// Skip columns where the insert value and update value are the same,
+		// unless the existing rows can be deleted by a MERGE.
+		if insertColID == updateColID && mb.deleteColID == 0 {
 			continue
 		}
 
@@ -964,24 +989,32 @@ func (mb *mutationBuilder) projectUpsertColumns() {
 		}
 
 		// Generate CASE that toggles between insert and update column.
+		typ := mb.md.ColumnMeta(insertColID).Type
+		whens := memo.ScalarListExpr{
+			mb.b.factory.ConstructWhen(
+				mb.b.factory.ConstructIs(
+					mb.b.factory.ConstructVariable(mb.canaryColID),
+					memo.NullSingleton,
+				),
+				mb.b.factory.ConstructVariable(insertColID),
+			),
+		}
+		if mb.deleteColID != 0 {
+			// The new values of the rows deleted by a MERGE are NULL.
+			whens = append(whens, mb.b.factory.ConstructWhen(
+				mb.b.factory.ConstructVariable(mb.deleteColID),
+				mb.b.factory.ConstructNull(typ),
+			))
+		}
 		caseExpr := mb.b.factory.ConstructCase(
 			memo.TrueSingleton,
-			memo.ScalarListExpr{
-				mb.b.factory.ConstructWhen(
-					mb.b.factory.ConstructIs(
-						mb.b.factory.ConstructVariable(mb.canaryColID),
-						memo.NullSingleton,
-					),
-					mb.b.factory.ConstructVariable(insertColID),
-				),
-			},
+			whens,
 			mb.b.factory.ConstructVariable(updateColID),
 		)
 
 		name := scopeColName(col.ColName()).WithMetadataName(
 			fmt.Sprintf("upsert_%s", mb.tab.Column(i).ColName()),
 		)
-		typ := mb.md.ColumnMeta(insertColID).Type
 		scopeCol := mb.b.synthesizeColumn(projectionsScope, name, typ, nil /* expr */, caseExpr)
 
 		// Update the scope ordinals for the update columns that are involved in
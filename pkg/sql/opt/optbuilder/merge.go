@@ -0,0 +1,625 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+)
+
+// duplicateMergeErrText is the error raised when a row of the target table of
+// a MERGE statement is matched by more than one row of the source.
+const duplicateMergeErrText = "MERGE command cannot affect row a second time"
+
+// buildMerge builds a memo group for a MERGE statement. The target table is
+// joined with the source (using a left join if there is a WHEN NOT MATCHED
+// clause), and an "action" column is projected which contains the (1-based)
+// ordinal of the first WHEN clause that applies to each row, or NULL if no
+// clause applies or the clause is DO NOTHING. For example:
+//
+//   MERGE INTO t USING s ON t.k = s.k
+//   WHEN MATCHED AND s.v IS NULL THEN DELETE
+//   WHEN MATCHED THEN UPDATE SET v = s.v
+//   WHEN NOT MATCHED THEN INSERT VALUES (s.k, s.v)
+//
+// is built as an Upsert operator with the input:
+//
+//   SELECT
+//     *,
+//     CASE WHEN action = 3 THEN s.k ELSE t.k END AS ins_k,
+//     CASE WHEN action = 3 THEN s.v ELSE t.v END AS ins_v,
+//     CASE WHEN action = 2 THEN s.v ELSE t.v END AS upd_v,
+//     action = 1 AS del
+//   FROM (
+//     SELECT *, CASE
+//       WHEN t.k IS NOT NULL AND s.v IS NULL THEN 1
+//       WHEN t.k IS NOT NULL THEN 2
+//       WHEN t.k IS NULL THEN 3
+//     END AS action
+//     FROM s LEFT JOIN t ON t.k = s.k
+//   )
+//   WHERE action IS NOT NULL
+//
+// The primary key of the target table is used as the canary column of the
+// Upsert: rows for which it is NULL are inserted, and the other rows are
+// updated, or deleted if the delete column is true (see
+// mutationBuilder.deleteColID). Since all of the WHEN clauses are executed by
+// a single mutation of the table, a MERGE can update the key columns of the
+// table, and each target row is affected at most once (see
+// duplicateMergeErrText). The MERGE returns the number of rows affected.
+func (b *Builder) buildMerge(merge *tree.Merge, inScope *scope) (outScope *scope) {
+	// Find which table we're working on, check the permissions. Select
+	// permission is needed, since existing values must be read.
+	tab, depName, alias, refColumns := b.resolveTableForMutation(merge.Table, privilege.SELECT)
+
+	if refColumns != nil {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"cannot specify a list of column IDs with MERGE"))
+	}
+
+	var hasMatched, hasNotMatched, hasUpdate, hasDelete, hasInsert bool
+	for _, w := range merge.Whens {
+		if w.Matched {
+			hasMatched = true
+		} else {
+			hasNotMatched = true
+		}
+		switch w.Action {
+		case tree.MergeActionUpdate:
+			b.checkPrivilege(depName, tab, privilege.UPDATE)
+			hasUpdate = true
+		case tree.MergeActionDelete:
+			b.checkPrivilege(depName, tab, privilege.DELETE)
+			hasDelete = true
+		case tree.MergeActionInsert:
+			b.checkPrivilege(depName, tab, privilege.INSERT)
+			hasInsert = true
+		}
+	}
+	mutates := hasUpdate || hasDelete || hasInsert
+
+	// Check if this table has already been mutated in another subquery.
+	if mutates {
+		b.checkMultipleMutations(tab, false /* simpleInsert */)
+	}
+
+	var indexFlags *tree.IndexFlags
+	if source, ok := merge.Table.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
+		indexFlags = source.IndexFlags
+		telemetry.Inc(sqltelemetry.IndexHintUseCounter)
+	}
+
+	var mb mutationBuilder
+	mb.init(b, "merge", tab, alias)
+
+	// Build the target table in the same way as the input of an UPDATE or
+	// DELETE, so that its columns are the fetch columns of the mutation.
+	//
+	// NOTE: Include mutation columns, but be careful to never use them for any
+	//       reason other than as "fetch columns". See buildScan comment.
+	mb.fetchScope = b.buildScan(
+		b.addTable(tab, &mb.alias),
+		tableOrdinals(tab, columnKinds{
+			includeMutations: true,
+			includeSystem:    true,
+			includeInverted:  false,
+		}),
+		indexFlags,
+		noRowLocking,
+		inScope,
+	)
+	mb.setFetchColIDs(mb.fetchScope.cols)
+	sourceScope := b.buildDataSource(merge.Source, nil /* indexFlags */, noRowLocking, inScope)
+
+	// Check that the same table name is not used multiple times.
+	b.validateJoinTableNames(mb.fetchScope, sourceScope)
+
+	// Join the source with the target table.
+	mb.outScope = inScope.push()
+	mb.outScope.appendColumnsFromScope(mb.fetchScope)
+	mb.outScope.appendColumnsFromScope(sourceScope)
+
+	on := b.resolveAndBuildScalar(
+		merge.On, types.Bool, exprKindOn, tree.RejectGenerators|tree.RejectWindowApplications, mb.outScope,
+	)
+	filters := memo.FiltersExpr{b.factory.ConstructFiltersItem(on)}
+	if hasNotMatched {
+		mb.outScope.expr = b.factory.ConstructLeftJoin(
+			sourceScope.expr, mb.fetchScope.expr, filters, memo.EmptyJoinPrivate,
+		)
+	} else {
+		mb.outScope.expr = b.factory.ConstructInnerJoin(
+			mb.fetchScope.expr, sourceScope.expr, filters, memo.EmptyJoinPrivate,
+		)
+	}
+
+	// A row is matched if a not-null column of the primary index of the target
+	// table is not NULL.
+	canaryOrd := findNotNullIndexCol(tab.Index(cat.PrimaryIndex))
+	mb.canaryColID = mb.fetchColIDs[canaryOrd]
+
+	// Project the action column, and filter out the rows to which no action
+	// applies.
+	actionColID := mb.buildMergeAction(merge.Whens, sourceScope)
+	mb.outScope.expr = b.factory.ConstructSelect(
+		mb.outScope.expr,
+		memo.FiltersExpr{b.factory.ConstructFiltersItem(
+			b.factory.ConstructIsNot(b.factory.ConstructVariable(actionColID), memo.NullSingleton),
+		)},
+	)
+
+	if !mutates {
+		// None of the WHEN clauses modify the table, so the MERGE does not
+		// affect any rows: all of the rows were filtered out above.
+		outScope = inScope.push()
+		countCol := b.synthesizeColumn(
+			outScope, scopeColName("count"), types.Int, nil /* expr */, nil, /* scalar */
+		)
+		outScope.expr = b.factory.ConstructScalarGroupBy(
+			mb.outScope.expr,
+			memo.AggregationsExpr{b.factory.ConstructAggregationsItem(
+				b.factory.ConstructCountRows(), countCol.id,
+			)},
+			&memo.GroupingPrivate{},
+		)
+		return outScope
+	}
+
+	// Ensure that each row of the target table is matched at most once. The
+	// primary key columns of the target table are NULL for rows of the source
+	// which are not matched, so NULL values are treated as distinct.
+	if hasMatched {
+		var pkCols opt.ColSet
+		primaryIndex := tab.Index(cat.PrimaryIndex)
+		for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+			pkCols.Add(mb.fetchColIDs[primaryIndex.Column(i).Ordinal()])
+		}
+		mb.outScope = b.buildDistinctOn(
+			pkCols, mb.outScope, true /* nullsAreDistinct */, duplicateMergeErrText,
+		)
+	}
+
+	if hasDelete {
+		mb.projectDeleteColForMerge(merge.Whens, actionColID)
+	}
+
+	// Build the values of the updated columns, along with the additional
+	// columns for computed expressions that may depend on them.
+	if hasUpdate {
+		mb.addUpdateColsForMerge(merge.Whens, actionColID)
+		mb.addSynthesizedColsForUpdate()
+	}
+
+	// Build the values of the inserted rows. If no rows are inserted, the
+	// existing values of the rows are used as the insert values, which are
+	// never written.
+	if hasInsert {
+		mb.addInsertColsForMerge(merge.Whens, actionColID, sourceScope.cols)
+	} else {
+		for i, n := 0, tab.ColumnCount(); i < n; i++ {
+			if tab.Column(i).Kind() == cat.Ordinary {
+				mb.insertColIDs[i] = mb.fetchColIDs[i]
+			}
+		}
+	}
+
+	// Apply any BEFORE triggers of the deleted rows.
+	if hasDelete {
+		mb.buildBeforeTriggers(tree.TriggerEventDelete)
+	}
+
+	// Merge the insert and update columns using CASE expressions.
+	mb.projectUpsertColumns()
+
+	// Disambiguate names so that references in any expressions, such as a
+	// check constraint, refer to the correct columns.
+	mb.disambiguateColumns()
+
+	// Add any check constraint boolean columns to the input. The deleted rows
+	// don't need to satisfy them.
+	mb.addCheckConstraintCols(false /* isUpdate */)
+	if hasDelete {
+		mb.projectCheckColsForMerge()
+	}
+
+	// Add the partial index predicate expressions to the table metadata.
+	// These expressions are used to prune fetch columns during
+	// normalization.
+	mb.b.addPartialIndexPredicatesForTable(mb.md.TableMeta(mb.tabID), nil /* scan */)
+
+	// Project partial index PUT and DEL boolean columns.
+	mb.projectPartialIndexPutAndDelCols()
+
+	// The FK checks must be built first, since they project the old values
+	// given to the cascades before the input of the mutation is buffered.
+	mb.buildFKChecksForMerge()
+
+	mb.buildUniqueChecksForUpsert()
+
+	mb.buildAfterTriggers(opt.UpsertOp)
+
+	private := mb.makeMutationPrivate(false /* needResults */)
+	mb.outScope.expr = mb.b.factory.ConstructUpsert(
+		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
+	)
+
+	mb.buildReturning(nil /* returning */)
+	return mb.outScope
+}
+
+// buildMergeAction projects the action column of a MERGE statement (see
+// buildMerge) on top of mb.outScope, and returns its ID. The WHEN MATCHED
+// conditions can reference the columns of both the target and the source,
+// while the WHEN NOT MATCHED conditions can only reference the columns of the
+// source.
+func (mb *mutationBuilder) buildMergeAction(
+	whens tree.MergeWhens, sourceScope *scope,
+) (actionColID opt.ColumnID) {
+	f := mb.b.factory
+	matched := f.ConstructIsNot(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+	notMatched := f.ConstructIs(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+
+	notMatchedScope := mb.outScope.replace()
+	notMatchedScope.appendColumnsFromScope(sourceScope)
+
+	whenExprs := make(memo.ScalarListExpr, len(whens))
+	for i, w := range whens {
+		cond := notMatched
+		condScope := notMatchedScope
+		if w.Matched {
+			cond = matched
+			condScope = mb.outScope
+		}
+		if w.Cond != nil {
+			userCond := mb.b.resolveAndBuildScalar(
+				w.Cond, types.Bool, exprKindMergeWhen, tree.RejectSpecial, condScope,
+			)
+			cond = f.ConstructAnd(cond, userCond)
+		}
+
+		val := f.ConstructNull(types.Int)
+		if w.Action != tree.MergeActionDoNothing {
+			val = f.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int)
+		}
+		whenExprs[i] = f.ConstructWhen(cond, val)
+	}
+	action := f.ConstructCase(memo.TrueSingleton, whenExprs, f.ConstructNull(types.Int))
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	colName := scopeColName("").WithMetadataName("merge_action")
+	actionCol := mb.b.synthesizeColumn(projectionsScope, colName, types.Int, nil /* expr */, action)
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+	return actionCol.id
+}
+
+// isMergeAction returns a condition which is true for the rows to which the
+// WHEN clause with the given ordinal applies.
+func (mb *mutationBuilder) isMergeAction(actionColID opt.ColumnID, i int) opt.ScalarExpr {
+	return mb.b.factory.ConstructEq(
+		mb.b.factory.ConstructVariable(actionColID),
+		mb.b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i+1)), types.Int),
+	)
+}
+
+// projectDeleteColForMerge projects the delete column of a MERGE statement,
+// which is true for the rows to which a DELETE clause applies, and sets
+// mb.deleteColID.
+func (mb *mutationBuilder) projectDeleteColForMerge(whens tree.MergeWhens, actionColID opt.ColumnID) {
+	var isDelete opt.ScalarExpr
+	for i, w := range whens {
+		if w.Action != tree.MergeActionDelete {
+			continue
+		}
+		cond := mb.isMergeAction(actionColID, i)
+		if isDelete == nil {
+			isDelete = cond
+		} else {
+			isDelete = mb.b.factory.ConstructOr(isDelete, cond)
+		}
+	}
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	colName := scopeColName("").WithMetadataName("merge_delete")
+	mb.deleteColID = mb.b.synthesizeColumn(
+		projectionsScope, colName, types.Bool, nil /* expr */, isDelete,
+	).id
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+}
+
+// addUpdateColsForMerge builds the SET expressions of the UPDATE clauses of a
+// MERGE statement. The SET expressions of each clause are built in the same way
+// as those of an UPDATE statement, and the new value of each updated column is
+// chosen according to the action column:
+//
+//   CASE WHEN action = 1 THEN upd1_v WHEN action = 2 THEN upd2_v ELSE fetch_v END
+//
+// Columns which are not updated by a clause keep their existing values for the
+// rows to which it applies, unless they have an ON UPDATE expression.
+func (mb *mutationBuilder) addUpdateColsForMerge(whens tree.MergeWhens, actionColID opt.ColumnID) {
+	f := mb.b.factory
+	whenExprs := make([]memo.ScalarListExpr, mb.tab.ColumnCount())
+	var targetColList opt.ColList
+	var targetColSet opt.ColSet
+	for i, w := range whens {
+		if w.Action != tree.MergeActionUpdate {
+			continue
+		}
+		mb.targetColList = mb.targetColList[:0]
+		mb.targetColSet = opt.ColSet{}
+		mb.subqueries = nil
+
+		exprs := mb.expandFieldUpdates(w.Exprs)
+		mb.addTargetColsForUpdate(exprs)
+		mb.addUpdateCols(exprs)
+
+		var clauseCols opt.ColSet
+		for _, colID := range mb.targetColList {
+			ord := mb.tabID.ColumnOrdinal(colID)
+			whenExprs[ord] = append(whenExprs[ord], f.ConstructWhen(
+				mb.isMergeAction(actionColID, i), f.ConstructVariable(mb.updateColIDs[ord]),
+			))
+			clauseCols.Add(mb.updateColIDs[ord])
+			mb.updateColIDs[ord] = 0
+			if !targetColSet.Contains(colID) {
+				targetColList = append(targetColList, colID)
+				targetColSet.Add(colID)
+			}
+		}
+
+		// The SET expressions of the next clauses must not refer to the new
+		// values of this clause.
+		for j := range mb.outScope.cols {
+			if clauseCols.Contains(mb.outScope.cols[j].id) {
+				mb.outScope.cols[j].clearName()
+			}
+		}
+	}
+
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	for _, colID := range targetColList {
+		ord := mb.tabID.ColumnOrdinal(colID)
+		col := mb.tab.Column(ord)
+		var elseExpr opt.ScalarExpr = f.ConstructVariable(mb.fetchColIDs[ord])
+		if col.UseOnUpdate(mb.b.evalCtx.SessionData()) {
+			texpr := mb.outScope.resolveAndRequireType(mb.parseOnUpdateExpr(colID), col.DatumType())
+			elseExpr = mb.b.buildScalar(texpr, mb.outScope, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+			elseExpr = mb.assignmentCastForMerge(elseExpr, texpr.ResolvedType(), ord)
+		}
+		caseExpr := f.ConstructCase(memo.TrueSingleton, whenExprs[ord], elseExpr)
+		colName := scopeColName(col.ColName()).WithMetadataName(string(col.ColName()) + "_new")
+		mb.updateColIDs[ord] = mb.b.synthesizeColumn(
+			projectionsScope, colName, col.DatumType(), nil /* expr */, caseExpr,
+		).id
+	}
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+	mb.targetColList = append(mb.targetColList[:0], targetColList...)
+	mb.targetColSet = targetColSet
+}
+
+// addInsertColsForMerge builds the VALUES expressions of the INSERT clauses of
+// a MERGE statement, along with the additional columns for default and
+// computed expressions. The value inserted into each column is chosen
+// according to the action column, similarly to addUpdateColsForMerge. Columns
+// which are not targeted by a clause are set to their default values for the
+// rows to which it applies. For the rows which are not inserted, the insert
+// values are the existing values of the row, which are never written.
+//
+// The VALUES expressions can only reference the columns of the source, which
+// are given by sourceCols.
+func (mb *mutationBuilder) addInsertColsForMerge(
+	whens tree.MergeWhens, actionColID opt.ColumnID, sourceCols []scopeColumn,
+) {
+	// VALUES expressions should reject aggregates, generators, etc.
+	scalarProps := &mb.b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	mb.b.semaCtx.Properties.Require(exprKindValues.String(), tree.RejectSpecial)
+
+	valuesScope := mb.outScope.replace()
+	valuesScope.appendColumns(sourceCols)
+	valuesScope.expr = mb.outScope.expr
+
+	f := mb.b.factory
+	numCols := mb.tab.ColumnCount()
+	whenExprs := make([]memo.ScalarListExpr, numCols)
+	for i, w := range whens {
+		if w.Action != tree.MergeActionInsert {
+			continue
+		}
+		mb.targetColList = mb.targetColList[:0]
+		mb.targetColSet = opt.ColSet{}
+		if len(w.Columns) != 0 {
+			// Target columns are explicitly specified by name.
+			mb.addTargetNamedColsForInsert(w.Columns)
+			mb.checkNumCols(len(mb.targetColList), len(w.Values))
+		} else {
+			// Target columns are implicitly targeted by the VALUES expressions in
+			// the same order they appear in the target table schema.
+			mb.addTargetTableColsForInsert(len(w.Values))
+		}
+
+		exprs := make([]tree.Expr, numCols)
+		for j, expr := range w.Values {
+			ord := mb.tabID.ColumnOrdinal(mb.targetColList[j])
+			targetCol := mb.tab.Column(ord)
+			if _, ok := expr.(tree.DefaultVal); ok {
+				expr = nil
+			} else if targetCol.IsGeneratedAlwaysAsIdentity() {
+				// GENERATED ALWAYS AS IDENTITY columns are not allowed to be
+				// explicitly written to.
+				panic(sqlerrors.NewGeneratedAlwaysAsIdentityColumnOverrideError(string(targetCol.ColName())))
+			}
+			exprs[ord] = expr
+		}
+
+		isClause := mb.isMergeAction(actionColID, i)
+		for ord := 0; ord < numCols; ord++ {
+			col := mb.tab.Column(ord)
+			if col.Kind() != cat.Ordinary || col.IsComputed() {
+				continue
+			}
+			expr := exprs[ord]
+			if expr == nil {
+				expr = mb.parseDefaultExpr(mb.tabID.ColumnID(ord))
+			}
+			texpr := valuesScope.resolveType(expr, col.DatumType())
+			scalar := mb.b.buildScalar(texpr, valuesScope, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+			scalar = mb.assignmentCastForMerge(scalar, texpr.ResolvedType(), ord)
+			whenExprs[ord] = append(whenExprs[ord], f.ConstructWhen(isClause, scalar))
+		}
+	}
+
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	for ord := 0; ord < numCols; ord++ {
+		if whenExprs[ord] == nil {
+			continue
+		}
+		col := mb.tab.Column(ord)
+		caseExpr := f.ConstructCase(
+			memo.TrueSingleton, whenExprs[ord], f.ConstructVariable(mb.fetchColIDs[ord]),
+		)
+		mb.insertColIDs[ord] = mb.b.synthesizeColumn(
+			projectionsScope, scopeColName(col.ColName()), col.DatumType(), nil /* expr */, caseExpr,
+		).id
+		colID := mb.tabID.ColumnID(ord)
+		mb.targetColList = append(mb.targetColList, colID)
+		mb.targetColSet.Add(colID)
+	}
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Add the columns for default and computed expressions. The existing and
+	// updated values are hidden while they are built, so that the names of the
+	// table columns refer to the inserted values. The names are restored
+	// afterwards.
+	names := make(map[opt.ColumnID]scopeColumnName, len(mb.outScope.cols))
+	for i := range mb.outScope.cols {
+		names[mb.outScope.cols[i].id] = mb.outScope.cols[i].name
+	}
+	fetchColIDs, updateColIDs := mb.fetchColIDs, mb.updateColIDs
+	mb.fetchColIDs = make(opt.OptionalColList, numCols)
+	mb.updateColIDs = make(opt.OptionalColList, numCols)
+	mb.disambiguateColumns()
+	mb.addSynthesizedColsForInsert()
+	mb.fetchColIDs, mb.updateColIDs = fetchColIDs, updateColIDs
+	for i := range mb.outScope.cols {
+		if name, ok := names[mb.outScope.cols[i].id]; ok {
+			mb.outScope.cols[i].name = name
+		}
+	}
+}
+
+// assignmentCastForMerge wraps the given scalar expression, which has the given
+// type, with an assignment cast to the type of the table column with the given
+// ordinal if necessary.
+func (mb *mutationBuilder) assignmentCastForMerge(
+	scalar opt.ScalarExpr, srcType *types.T, ord int,
+) opt.ScalarExpr {
+	targetCol := mb.tab.Column(ord)
+	targetType := targetCol.DatumType()
+	if srcType.Identical(targetType) {
+		return scalar
+	}
+	if !tree.ValidCast(srcType, targetType, tree.CastContextAssignment) {
+		panic(sqlerrors.NewInvalidAssignmentCastError(srcType, targetType, string(targetCol.ColName())))
+	}
+	return mb.b.factory.ConstructAssignmentCast(scalar, targetType)
+}
+
+// projectCheckColsForMerge replaces the check constraint columns of a MERGE
+// statement which deletes rows with columns that are also true for the
+// deleted rows, whose new values are NULL.
+func (mb *mutationBuilder) projectCheckColsForMerge() {
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	for i, colID := range mb.checkColIDs {
+		if colID == 0 {
+			continue
+		}
+		check := mb.b.factory.ConstructOr(
+			mb.b.factory.ConstructVariable(colID),
+			mb.b.factory.ConstructVariable(mb.deleteColID),
+		)
+		colName := scopeColName("").WithMetadataName(fmt.Sprintf("check%d_merge", i+1))
+		mb.checkColIDs[i] = mb.b.synthesizeColumn(
+			projectionsScope, colName, types.Bool, nil /* expr */, check,
+		).id
+	}
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+}
+
+// projectOldColsForMerge projects the old values of the columns with the given
+// table ordinals for the rows which are deleted by a MERGE statement (if
+// deleted is true), or for the rows which are updated (otherwise). The values
+// are NULL for the other rows. It returns the IDs of the projected columns,
+// indexed by table ordinal. If the MERGE does not delete rows, the fetch
+// columns are returned for the updated rows.
+func (mb *mutationBuilder) projectOldColsForMerge(
+	ords util.FastIntSet, deleted bool,
+) opt.OptionalColList {
+	colIDs := make(opt.OptionalColList, mb.tab.ColumnCount())
+	if ords.Empty() {
+		return colIDs
+	}
+	if mb.deleteColID == 0 {
+		ords.ForEach(func(ord int) {
+			colIDs[ord] = mb.fetchColIDs[ord]
+		})
+		return colIDs
+	}
+
+	f := mb.b.factory
+	var cond opt.ScalarExpr = f.ConstructVariable(mb.deleteColID)
+	suffix := "deleted"
+	if !deleted {
+		cond = f.ConstructNot(cond)
+		suffix = "updated"
+	}
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	ords.ForEach(func(ord int) {
+		fetchColID := mb.fetchColIDs[ord]
+		typ := mb.md.ColumnMeta(fetchColID).Type
+		caseExpr := f.ConstructCase(
+			memo.TrueSingleton,
+			memo.ScalarListExpr{f.ConstructWhen(cond, f.ConstructVariable(fetchColID))},
+			f.ConstructNull(typ),
+		)
+		colName := scopeColName("").WithMetadataName(
+			fmt.Sprintf("%s_%s", mb.tab.Column(ord).ColName(), suffix),
+		)
+		colIDs[ord] = mb.b.synthesizeColumn(projectionsScope, colName, typ, nil /* expr */, caseExpr).id
+	})
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+	return colIDs
+}
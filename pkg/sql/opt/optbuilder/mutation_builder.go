@@ -137,6 +137,11 @@ type mutationBuilder struct {
 	// an insert; otherwise it's an update.
 	canaryColID opt.ColumnID
 
+	// deleteColID is the ID of the column that is used to decide whether to
+	// delete an existing row rather than to update it. It is only set for an
+	// Upsert operator built for a MERGE statement with a DELETE clause.
+	deleteColID opt.ColumnID
+
 	// arbiters is the set of indexes and unique constraints that are used to
 	// detect conflicts for UPSERT and INSERT ON CONFLICT statements.
 	arbiters arbiterSet
@@ -347,42 +352,57 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// Build a distinct on to ensure there is at most one row in the joined output
 	// for every row in the table.
 	if fromClausePresent {
-		var pkCols opt.ColSet
-
-		// We need to ensure that the join has a maximum of one row for every row
-		// in the table and we ensure this by constructing a distinct on the primary
-		// key columns.
-		primaryIndex := mb.tab.Index(cat.PrimaryIndex)
-		for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
-			// If the primary key column is hidden, then we don't need to use it
-			// for the distinct on.
-			// TODO(radu): this logic seems fragile, is it assuming that only an
-			// implicit `rowid` column can be a hidden PK column?
-			if col := primaryIndex.Column(i); col.Visibility() != cat.Hidden {
-				pkCols.Add(mb.fetchColIDs[col.Ordinal()])
-			}
-		}
+		mb.buildDistinctOnPrimaryKey()
+	}
+}
 
-		if !pkCols.Empty() {
-			mb.outScope = mb.b.buildDistinctOn(
-				pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+// buildDistinctOnPrimaryKey wraps the input expression in a DistinctOn
+// operator on the primary key columns of the target table. It is used to
+// ensure that the input has at most one row for every row in the table when
+// the table is joined with other data sources.
+func (mb *mutationBuilder) buildDistinctOnPrimaryKey() {
+	var pkCols opt.ColSet
+
+	// We need to ensure that the join has a maximum of one row for every row
+	// in the table and we ensure this by constructing a distinct on the primary
+	// key columns.
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+		// If the primary key column is hidden, then we don't need to use it
+		// for the distinct on.
+		// TODO(radu): this logic seems fragile, is it assuming that only an
+		// implicit `rowid` column can be a hidden PK column?
+		if col := primaryIndex.Column(i); col.Visibility() != cat.Hidden {
+			pkCols.Add(mb.fetchColIDs[col.Ordinal()])
 		}
 	}
+
+	if !pkCols.Empty() {
+		mb.outScope = mb.b.buildDistinctOn(
+			pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+	}
 }
 
 // buildInputForDelete constructs a Select expression from the fields in
 // the Delete operator, similar to this:
 //
 //   SELECT <cols>
-//   FROM <table>
+//   FROM <table>, <using>
 //   WHERE <where>
 //   ORDER BY <order-by>
 //   LIMIT <limit>
 //
-// All columns from the table to update are added to fetchColList.
+// All columns from the table to update are added to fetchColList. If using is
+// not empty, its tables are joined with the target table in the same way as
+// the FROM clause of an UPDATE (see buildInputForUpdate).
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
 func (mb *mutationBuilder) buildInputForDelete(
-	inScope *scope, texpr tree.TableExpr, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
+	inScope *scope,
+	texpr tree.TableExpr,
+	using tree.TableExprs,
+	where *tree.Where,
+	limit *tree.Limit,
+	orderBy tree.OrderBy,
 ) {
 	var indexFlags *tree.IndexFlags
 	if source, ok := texpr.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
@@ -410,7 +430,35 @@ func (mb *mutationBuilder) buildInputForDelete(
 		noRowLocking,
 		inScope,
 	)
-	mb.outScope = mb.fetchScope
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	usingClausePresent := len(using) > 0
+	if usingClausePresent {
+		usingScope := mb.b.buildFromTables(using, noRowLocking, inScope)
+
+		// Check that the same table name is not used multiple times.
+		mb.b.validateJoinTableNames(mb.fetchScope, usingScope)
+
+		// The USING table columns can be accessed by the RETURNING clause of the
+		// query and so we have to make them accessible.
+		mb.extraAccessibleCols = usingScope.cols
+
+		// Add the columns in the USING scope.
+		// We create a new scope so that fetchScope is not modified. It will be
+		// used later to build partial index predicate expressions, and we do
+		// not want ambiguities with column names in the USING clause.
+		mb.outScope = mb.fetchScope.replace()
+		mb.outScope.appendColumnsFromScope(mb.fetchScope)
+		mb.outScope.appendColumnsFromScope(usingScope)
+
+		left := mb.fetchScope.expr
+		right := usingScope.expr
+		mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+	} else {
+		mb.outScope = mb.fetchScope
+	}
 
 	// WHERE
 	mb.b.buildWhere(where, mb.outScope)
@@ -429,8 +477,11 @@ func (mb *mutationBuilder) buildInputForDelete(
 
 	mb.outScope = projectionsScope
 
-	// Set list of columns that will be fetched by the input expression.
-	mb.setFetchColIDs(mb.outScope.cols)
+	// Build a distinct on to ensure there is at most one row in the joined output
+	// for every row in the table.
+	if usingClausePresent {
+		mb.buildDistinctOnPrimaryKey()
+	}
 }
 
 // addTargetColsByName adds one target column for each of the names in the given
@@ -905,6 +956,7 @@ func (mb *mutationBuilder) makeMutationPrivate(needResults bool) *memo.MutationP
 		FetchCols:           checkEmptyList(mb.fetchColIDs),
 		UpdateCols:          checkEmptyList(mb.updateColIDs),
 		CanaryCol:           mb.canaryColID,
+		DeleteCol:           mb.deleteColID,
 		ArbiterIndexes:      mb.arbiters.IndexOrdinals(),
 		ArbiterConstraints:  mb.arbiters.UniqueConstraintOrdinals(),
 		CheckCols:           checkEmptyList(mb.checkColIDs),
@@ -1290,6 +1342,50 @@ func (mb *mutationBuilder) addAssignmentCasts(srcCols opt.OptionalColList) {
 	}
 }
 
+// addDomainChecks builds a projection that wraps the columns in srcCols whose
+// target columns have a DOMAIN type with checks of the constraints of the
+// domain (see buildDomainChecks). The new columns replace the old ones in
+// srcCols.
+func (mb *mutationBuilder) addDomainChecks(srcCols opt.OptionalColList) {
+	var projectionScope *scope
+	for ord, colID := range srcCols {
+		if colID == 0 {
+			// Column not mutated, so nothing to do.
+			continue
+		}
+
+		targetCol := mb.tab.Column(ord)
+		targetType := targetCol.DatumType()
+		if !targetType.IsDomain() {
+			continue
+		}
+
+		// Lazily create the new scope.
+		if projectionScope == nil {
+			projectionScope = mb.outScope.replace()
+			projectionScope.appendColumnsFromScope(mb.outScope)
+		}
+
+		// The plan must be invalidated if the constraints of the domain change.
+		mb.md.AddUserDefinedType(targetType)
+		variable := mb.b.factory.ConstructVariable(colID)
+		checked := mb.b.buildDomainChecks(variable, targetType, projectionScope, nil /* colRefs */)
+
+		// See addAssignmentCasts for why the column is looked up with its name.
+		scopeCol := projectionScope.getColumnWithIDAndReferenceName(colID, targetCol.ColName())
+		scopeCol.name = scopeCol.name.WithMetadataName(fmt.Sprintf("%s_domain", targetCol.ColName()))
+		mb.b.populateSynthesizedColumn(scopeCol, checked)
+
+		// Replace old source column with the new one.
+		srcCols[ord] = scopeCol.id
+	}
+
+	if projectionScope != nil {
+		projectionScope.expr = mb.b.constructProject(mb.outScope.expr, projectionScope.cols)
+		mb.outScope = projectionScope
+	}
+}
+
 // partialIndexCount returns the number of public, write-only, and delete-only
 // partial indexes defined on the table.
 func partialIndexCount(tab cat.Table) int {
@@ -1360,9 +1456,11 @@ func (mb *mutationBuilder) buildCheckInputScan(
 
 		// If a table column is not nullable, NULLs cannot be inserted (the
 		// mutation will fail). So for the purposes of checks, we can treat
-		// these columns as not null.
+		// these columns as not null. This does not apply to the new values of
+		// the rows deleted by a MERGE, which are NULL.
 		if mb.outScope.expr.Relational().NotNullCols.Contains(inputCols[i]) ||
-			!mb.tab.Column(tabOrd).IsNullable() {
+			(!mb.tab.Column(tabOrd).IsNullable() &&
+				(typ != checkInputScanNewVals || mb.deleteColID == 0)) {
 			notNullOutCols.Add(outCol)
 		}
 	}
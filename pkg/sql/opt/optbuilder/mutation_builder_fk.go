@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/errors"
 )
 
@@ -454,6 +455,156 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
 }
 
+// buildFKChecksForMerge builds FK check queries and cascades for an upsert
+// built for a MERGE statement.
+//
+// See the comment at the top of the file for general information on checks and
+// cascades.
+//
+// The case of merge is very similar to upsert; see buildFKChecksForUpsert. The
+// main difference is that some of the existing rows may be deleted rather than
+// updated (see mutationBuilder.deleteColID). The new values of the deleted rows
+// are NULL, so the deletion-side checks, which compare the "old" values with
+// the "new" values, apply to them as well. The cascades are given the old
+// values of the deleted rows and of the updated rows separately; the old values
+// of the other rows are NULL, which are ignored by the cascades.
+//
+func (mb *mutationBuilder) buildFKChecksForMerge() {
+	numOutbound := mb.tab.OutboundForeignKeyCount()
+	numInbound := mb.tab.InboundForeignKeyCount()
+
+	if numOutbound == 0 && numInbound == 0 {
+		return
+	}
+
+	// Existing values are only removed by the rows which are deleted, or which
+	// update at least one of the FK columns.
+	isCascade := func(a tree.ReferenceAction) bool {
+		return a != tree.Restrict && a != tree.NoAction
+	}
+	var deletedOrds, updatedOrds util.FastIntSet
+	for i := 0; i < numInbound; i++ {
+		fk := mb.tab.InboundForeignKey(i)
+		onDelete := mb.deleteColID != 0 && isCascade(fk.DeleteReferenceAction())
+		onUpdate := mb.inboundFKColsUpdated(i) && isCascade(fk.UpdateReferenceAction())
+		for j, n := 0, fk.ColumnCount(); j < n; j++ {
+			ord := fk.ReferencedColumnOrdinal(mb.tab, j)
+			if onDelete {
+				deletedOrds.Add(ord)
+			}
+			if onUpdate {
+				updatedOrds.Add(ord)
+			}
+		}
+	}
+
+	// Project the old values given to the cascades before the input of the
+	// mutation is buffered.
+	deletedColIDs := mb.projectOldColsForMerge(deletedOrds, true /* deleted */)
+	updatedColIDs := mb.projectOldColsForMerge(updatedOrds, false /* deleted */)
+
+	h := &mb.fkCheckHelper
+	for i := 0; i < numOutbound; i++ {
+		if h.initWithOutboundFK(mb, i) {
+			mb.fkChecks = append(mb.fkChecks, h.buildInsertionCheck())
+		}
+	}
+
+	for i := 0; i < numInbound; i++ {
+		updated := mb.inboundFKColsUpdated(i)
+		if !updated && mb.deleteColID == 0 {
+			continue
+		}
+
+		if !h.initWithInboundFK(mb, i) {
+			continue
+		}
+
+		needsCheck := false
+		if mb.deleteColID != 0 {
+			if a := h.fk.DeleteReferenceAction(); isCascade(a) {
+				telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
+				mb.ensureWithID()
+				var builder memo.CascadeBuilder
+				switch a {
+				case tree.Cascade:
+					builder = newOnDeleteCascadeBuilder(mb.tab, i, h.otherTab)
+				case tree.SetNull, tree.SetDefault:
+					builder = newOnDeleteSetBuilder(mb.tab, i, h.otherTab, a)
+				default:
+					panic(errors.AssertionFailedf("unhandled action type %s", a))
+				}
+
+				oldCols := make(opt.ColList, len(h.tabOrdinals))
+				for i, tabOrd := range h.tabOrdinals {
+					oldCols[i] = deletedColIDs[tabOrd]
+				}
+				mb.cascades = append(mb.cascades, memo.FKCascade{
+					FKName:    h.fk.Name(),
+					Builder:   builder,
+					WithID:    mb.withID,
+					OldValues: oldCols,
+					NewValues: nil,
+				})
+			} else {
+				needsCheck = true
+			}
+		}
+
+		if updated {
+			if a := h.fk.UpdateReferenceAction(); isCascade(a) {
+				telemetry.Inc(sqltelemetry.ForeignKeyCascadesUseCounter)
+				mb.ensureWithID()
+				builder := newOnUpdateCascadeBuilder(mb.tab, i, h.otherTab, a)
+
+				oldCols := make(opt.ColList, len(h.tabOrdinals))
+				newCols := make(opt.ColList, len(h.tabOrdinals))
+				for i, tabOrd := range h.tabOrdinals {
+					updateColID := mb.updateColIDs[tabOrd]
+					if updateColID == 0 {
+						updateColID = mb.fetchColIDs[tabOrd]
+					}
+					oldCols[i] = updatedColIDs[tabOrd]
+					newCols[i] = updateColID
+				}
+				mb.cascades = append(mb.cascades, memo.FKCascade{
+					FKName:    h.fk.Name(),
+					Builder:   builder,
+					WithID:    mb.withID,
+					OldValues: oldCols,
+					NewValues: newCols,
+				})
+			} else {
+				needsCheck = true
+			}
+		}
+
+		if !needsCheck {
+			continue
+		}
+
+		// Construct an Except expression for the set difference between "old" FK
+		// values and "new" FK values. See buildFKChecksForUpsert for more
+		// details. The checks run after the cascades, so the values removed by
+		// the rows handled by a cascade no longer have any references.
+		oldRowsScope, _ := mb.buildCheckInputScan(checkInputScanFetchedVals, h.tabOrdinals, true /* isFK */)
+		newRowsScope, _ := mb.buildCheckInputScan(checkInputScanNewVals, h.tabOrdinals, true /* isFK */)
+		colsForOldRow := oldRowsScope.colList()
+		colsForNewRow := newRowsScope.colList()
+		deletedRows := mb.b.factory.ConstructExcept(
+			oldRowsScope.expr,
+			newRowsScope.expr,
+			&memo.SetPrivate{
+				LeftCols:  colsForOldRow,
+				RightCols: colsForNewRow,
+				OutCols:   colsForOldRow,
+			},
+		)
+		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(deletedRows, colsForOldRow))
+	}
+	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
+}
+
 // outboundFKColsUpdated returns true if any of the FK columns for an outbound
 // constraint are being updated (according to updateColIDs).
 func (mb *mutationBuilder) outboundFKColsUpdated(fkOrdinal int) bool {
@@ -0,0 +1,732 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// Row-level triggers execute the body of a trigger function for each row that
+// is inserted, updated or deleted by a mutation. The body of the function can
+// refer to the new and old values of the row by qualifying a column name with
+// NEW or OLD, respectively.
+//
+// BEFORE triggers must have a single SELECT statement as their body. The body
+// is built as a lateral subquery of the mutation input, and each column it
+// returns replaces the value of the table column with the same name. If the
+// body returns no rows, the mutation of the row is skipped. For example, given
+// the trigger:
+//
+//   CREATE FUNCTION f() RETURNS TRIGGER AS 'SELECT now() AS updated_at' LANGUAGE SQL
+//   CREATE TRIGGER t BEFORE UPDATE ON abc FOR EACH ROW EXECUTE FUNCTION f()
+//
+// the input to an update of abc is built similarly to:
+//
+//   SELECT * FROM <input> LEFT JOIN LATERAL (
+//     SELECT updated_at::TIMESTAMPTZ, true AS marker FROM (<body>) LIMIT 1
+//   ) ON true
+//   WHERE marker IS NOT NULL
+//
+// AFTER triggers must have a single INSERT, UPSERT, UPDATE or DELETE statement
+// as their body. They are planned as post-queries, in the same way as FK
+// cascades, and are executed once for each mutated row (see
+// memo.FKCascade.ForEachRow). The NEW and OLD values are read from the
+// buffered mutation input; they are exposed to the body as data sources which
+// are joined with its input. For example, the body:
+//
+//   UPDATE counts SET n = n + 1 WHERE k = new.k
+//
+// is built as if it were:
+//
+//   UPDATE counts SET n = n + 1 FROM <new values> AS new WHERE k = new.k
+//
+// The columns of the NEW and OLD data sources can only be referenced when they
+// are qualified, so that they do not shadow the columns of the body's own data
+// sources.
+
+// Names of the data sources through which the body of a trigger function
+// accesses the new and old values of a row.
+const (
+	triggerNewName = tree.Name("new")
+	triggerOldName = tree.Name("old")
+)
+
+// Names of the internal data sources and columns used to build AFTER
+// triggers.
+const (
+	triggerNewSourceName    = "crdb_internal_trigger_new"
+	triggerOldSourceName    = "crdb_internal_trigger_old"
+	triggerValuesSourceName = "crdb_internal_trigger_values"
+	triggerCanaryColName    = "crdb_internal_trigger_canary"
+	triggerDeleteColName    = "crdb_internal_trigger_delete"
+)
+
+// triggerRowOrdinals returns the ordinals of the columns of the given table
+// which are visible to the body of a trigger function as NEW and OLD values.
+// These are the public, accessible columns of the table.
+func triggerRowOrdinals(tab cat.Table) []int {
+	ords := make([]int, 0, tab.ColumnCount())
+	for i, n := 0, tab.ColumnCount(); i < n; i++ {
+		col := tab.Column(i)
+		if col.Kind() == cat.Ordinary && col.Visibility() != cat.Inaccessible {
+			ords = append(ords, i)
+		}
+	}
+	return ords
+}
+
+// triggerFires returns true if the given trigger fires for the given event. A
+// trigger with an UPDATE OF clause only fires for an UPDATE event if at least
+// one of the listed columns is updated.
+func (mb *mutationBuilder) triggerFires(trig cat.Trigger, event tree.TriggerEventType) bool {
+	if !trig.HasEvent(event) {
+		return false
+	}
+	if event != tree.TriggerEventUpdate || trig.UpdateColumnCount() == 0 {
+		return true
+	}
+	for i, n := 0, trig.UpdateColumnCount(); i < n; i++ {
+		if mb.updateColIDs[trig.UpdateColumnOrdinal(mb.tab, i)] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTriggerFunction resolves the function executed by the given trigger,
+// and returns its name and its parsed body.
+func (b *Builder) parseTriggerFunction(trig cat.Trigger) (name string, body tree.Statement) {
+	def, err := b.catalog.ResolveFunctionByID(b.ctx, trig.FunctionID())
+	if err != nil {
+		panic(err)
+	}
+	o, ok := def.Definition[0].(*tree.Overload)
+	if !ok {
+		panic(errors.AssertionFailedf("unexpected overload for function %s()", def.Name))
+	}
+	stmt, err := parser.ParseOne(o.Body)
+	if err != nil {
+		panic(pgerror.Wrapf(err, pgcode.InvalidFunctionDefinition,
+			"failed to parse body of function %s()", def.Name))
+	}
+	return def.Name, stmt.AST
+}
+
+// parseTriggerWhen returns the parsed WHEN condition of the given trigger, or
+// nil if it has none.
+func parseTriggerWhen(trig cat.Trigger) tree.Expr {
+	when, ok := trig.WhenExpr()
+	if !ok {
+		return nil
+	}
+	expr, err := parser.ParseExpr(when)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// buildBeforeTriggers applies the BEFORE triggers of the target table which
+// fire for the given event to the mutation input. It must be called after the
+// default values of the new row are known, but before any computed columns are
+// built:
+//
+//   - for INSERT, mb.insertColIDs contain the new values of the row.
+//   - for UPDATE, mb.updateColIDs contain the new values of the updated
+//     columns, and mb.fetchColIDs contain the old values of the row.
+//   - for DELETE, mb.fetchColIDs contain the old values of the row.
+//
+// The columns replaced by a trigger are updated in mb.insertColIDs or
+// mb.updateColIDs, and rows for which a trigger returns no result are filtered
+// from the input.
+func (mb *mutationBuilder) buildBeforeTriggers(event tree.TriggerEventType) {
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trig := mb.tab.Trigger(i)
+		if trig.ActionTime() != tree.TriggerActionTimeBefore || !mb.triggerFires(trig, event) {
+			continue
+		}
+		mb.buildBeforeTrigger(trig, event)
+	}
+}
+
+// buildBeforeTrigger applies a single BEFORE trigger to the mutation input. See
+// buildBeforeTriggers.
+func (mb *mutationBuilder) buildBeforeTrigger(trig cat.Trigger, event tree.TriggerEventType) {
+	fnName, body := mb.b.parseTriggerFunction(trig)
+	sel, ok := body.(*tree.Select)
+	if !ok {
+		panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"function %s() of BEFORE trigger %s must have a single SELECT statement as its body",
+			fnName, trig.Name()))
+	}
+
+	// The body of a trigger function is not tied to a particular query, so it
+	// cannot be cached along with the memo.
+	mb.b.DisableMemoReuse = true
+
+	// Determine the input columns which provide the NEW and OLD values of the
+	// row.
+	ords := triggerRowOrdinals(mb.tab)
+	var colIDs opt.OptionalColList
+	switch event {
+	case tree.TriggerEventInsert:
+		colIDs = mb.insertColIDs
+	case tree.TriggerEventUpdate:
+		colIDs = mb.updateColIDs
+	}
+	newCols := make(opt.OptionalColList, mb.tab.ColumnCount())
+	oldCols := make(opt.OptionalColList, mb.tab.ColumnCount())
+	for _, ord := range ords {
+		switch event {
+		case tree.TriggerEventInsert:
+			newCols[ord] = mb.insertColIDs[ord]
+		case tree.TriggerEventUpdate:
+			newCols[ord] = mb.updateColIDs[ord]
+			if newCols[ord] == 0 {
+				newCols[ord] = mb.fetchColIDs[ord]
+			}
+			oldCols[ord] = mb.fetchColIDs[ord]
+		case tree.TriggerEventDelete:
+			oldCols[ord] = mb.fetchColIDs[ord]
+		}
+	}
+
+	// The NEW and OLD values are only accessible by qualified name from the
+	// body of the function and from the WHEN condition. Build a scope without
+	// a parent, so that the body cannot refer to anything else in the
+	// statement.
+	trigScope := mb.b.allocScope()
+	addCols := func(tabName tree.Name, cols opt.OptionalColList) {
+		tn := tree.MakeUnqualifiedTableName(tabName)
+		for _, ord := range ords {
+			if cols[ord] == 0 {
+				continue
+			}
+			trigScope.cols = append(trigScope.cols, scopeColumn{
+				name:       scopeColName(mb.tab.Column(ord).ColName()),
+				table:      tn,
+				typ:        mb.md.ColumnMeta(cols[ord]).Type,
+				id:         cols[ord],
+				visibility: accessibleByQualifiedStar,
+			})
+		}
+	}
+	addCols(triggerNewName, newCols)
+	addCols(triggerOldName, oldCols)
+
+	// Project a column which determines whether the trigger fires for each
+	// row. The trigger does not fire if its WHEN condition is not true, or if
+	// the event does not apply to the row (see triggerEventCond).
+	var cond opt.ScalarExpr
+	if when := parseTriggerWhen(trig); when != nil {
+		texpr := trigScope.resolveAndRequireType(when, types.Bool)
+		cond = mb.b.factory.ConstructIs(
+			mb.b.buildScalar(texpr, trigScope, nil, nil, nil), memo.TrueSingleton,
+		)
+	}
+	if isEvent := mb.triggerEventCond(event); isEvent != nil {
+		if cond == nil {
+			cond = isEvent
+		} else {
+			cond = mb.b.factory.ConstructAnd(cond, isEvent)
+		}
+	}
+	var fireColID opt.ColumnID
+	if cond != nil {
+		projectionsScope := mb.outScope.replace()
+		projectionsScope.appendColumnsFromScope(mb.outScope)
+		colName := scopeColName("").WithMetadataName(fmt.Sprintf("trigger_%s_fires", trig.Name()))
+		fireColID = mb.b.synthesizeColumn(projectionsScope, colName, types.Bool, nil, cond).id
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		mb.outScope = projectionsScope
+	}
+
+	// Build the body of the function as a subquery which can refer to the NEW
+	// and OLD columns as outer columns.
+	s := trigScope.replaceSubquery(
+		&tree.Subquery{Select: &tree.ParenSelect{Select: sel}},
+		false /* wrapInTuple */, -1 /* desiredNumColumns */, noExtraColsAllowed,
+	)
+	s.buildSubquery(nil /* desiredTypes */)
+
+	// Match the columns returned by the body with the columns of the table, and
+	// cast them to the type of the table column. Add a marker column, which is
+	// NULL if the body returns no rows. The values returned by a DELETE trigger
+	// are ignored.
+	resultScope := trigScope.push()
+	var resultOrds []int
+	if event != tree.TriggerEventDelete {
+		var seen util.FastIntSet
+		resultOrds = make([]int, len(s.cols))
+		for i := range s.cols {
+			col := &s.cols[i]
+			name := col.name.ReferenceName()
+			ord := findPublicTableColumnByName(mb.tab, name)
+			if ord == -1 {
+				panic(colinfo.NewUndefinedColumnError(string(name)))
+			}
+			tabCol := mb.tab.Column(ord)
+			switch {
+			case tabCol.Kind() == cat.System:
+				panic(pgerror.Newf(pgcode.InvalidColumnReference, "cannot modify system column %q", name))
+			case tabCol.IsMutation():
+				panic(makeBackfillError(name))
+			case tabCol.IsComputed():
+				panic(schemaexpr.CannotWriteToComputedColError(string(name)))
+			case seen.Contains(ord):
+				panic(pgerror.Newf(pgcode.Syntax,
+					"multiple assignments to the same column %q", name))
+			}
+			seen.Add(ord)
+
+			srcType, targetType := col.typ, tabCol.DatumType()
+			var scalar opt.ScalarExpr = mb.b.factory.ConstructVariable(col.id)
+			if !srcType.Identical(targetType) {
+				if !tree.ValidCast(srcType, targetType, tree.CastContextAssignment) {
+					panic(sqlerrors.NewInvalidAssignmentCastError(srcType, targetType, string(name)))
+				}
+				scalar = mb.b.factory.ConstructAssignmentCast(scalar, targetType)
+			}
+			colName := scopeColName(tabCol.ColName()).WithMetadataName(
+				fmt.Sprintf("%s_%s", tabCol.ColName(), trig.Name()),
+			)
+			mb.b.synthesizeColumn(resultScope, colName, targetType, nil, scalar)
+			resultOrds[i] = ord
+		}
+	}
+	markerName := scopeColName("").WithMetadataName(fmt.Sprintf("trigger_%s", trig.Name()))
+	markerColID := mb.b.synthesizeColumn(resultScope, markerName, types.Bool, nil, memo.TrueSingleton).id
+	resultScope.expr = mb.b.constructProject(s.node, resultScope.cols)
+	resultScope.expr = mb.b.factory.ConstructLimit(
+		resultScope.expr,
+		mb.b.factory.ConstructConst(tree.NewDInt(1), types.Int),
+		props.OrderingChoice{},
+	)
+
+	// Join the result with the mutation input. The trigger is only executed for
+	// the rows for which it fires.
+	on := memo.TrueFilter
+	if fireColID != 0 {
+		on = memo.FiltersExpr{mb.b.factory.ConstructFiltersItem(mb.b.factory.ConstructVariable(fireColID))}
+	}
+	joinScope := mb.outScope.replace()
+	joinScope.appendColumnsFromScope(mb.outScope)
+	joinScope.appendColumnsFromScope(resultScope)
+	joinScope.expr = mb.b.factory.ConstructLeftJoinApply(
+		mb.outScope.expr, resultScope.expr, on, memo.EmptyJoinPrivate,
+	)
+	mb.outScope = joinScope
+
+	// Filter out the rows for which the trigger fired but returned no rows.
+	var keep opt.ScalarExpr = mb.b.factory.ConstructIsNot(
+		mb.b.factory.ConstructVariable(markerColID), memo.NullSingleton,
+	)
+	if fireColID != 0 {
+		keep = mb.b.factory.ConstructOr(
+			keep, mb.b.factory.ConstructNot(mb.b.factory.ConstructVariable(fireColID)),
+		)
+	}
+	mb.outScope.expr = mb.b.factory.ConstructSelect(
+		mb.outScope.expr, memo.FiltersExpr{mb.b.factory.ConstructFiltersItem(keep)},
+	)
+
+	if len(resultOrds) == 0 {
+		return
+	}
+
+	// Replace the new values of the row with the values returned by the
+	// trigger. If the trigger does not fire for every row, the new value is
+	// only replaced for the rows for which it fires.
+	var projectionsScope *scope
+	if fireColID != 0 {
+		projectionsScope = mb.outScope.replace()
+		projectionsScope.appendColumnsFromScope(mb.outScope)
+	}
+	for i, ord := range resultOrds {
+		resultColID := resultScope.cols[i].id
+		if projectionsScope != nil {
+			tabCol := mb.tab.Column(ord)
+			caseExpr := mb.b.factory.ConstructCase(
+				memo.TrueSingleton,
+				memo.ScalarListExpr{
+					mb.b.factory.ConstructWhen(
+						mb.b.factory.ConstructVariable(fireColID),
+						mb.b.factory.ConstructVariable(resultColID),
+					),
+				},
+				mb.b.factory.ConstructVariable(newCols[ord]),
+			)
+			colName := scopeColName(tabCol.ColName()).WithMetadataName(
+				fmt.Sprintf("%s_%s_new", tabCol.ColName(), trig.Name()),
+			)
+			resultColID = mb.b.synthesizeColumn(
+				projectionsScope, colName, tabCol.DatumType(), nil /* expr */, caseExpr,
+			).id
+		}
+		if tabColID := mb.tabID.ColumnID(ord); !mb.targetColSet.Contains(tabColID) {
+			// The column was not previously targeted, so it becomes a target
+			// column of the mutation.
+			mb.targetColList = append(mb.targetColList, tabColID)
+			mb.targetColSet.Add(tabColID)
+		}
+		colIDs[ord] = resultColID
+	}
+	if projectionsScope != nil {
+		mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+		mb.outScope = projectionsScope
+	}
+
+	// Disambiguate names so that references in any computed column expressions
+	// refer to the new values of the columns.
+	mb.disambiguateColumns()
+}
+
+// triggerEventCond returns a condition which is true for the rows of the
+// mutation input to which the given event applies, or nil if it applies to all
+// of them. For an UPSERT, the INSERT event applies to the rows which do not
+// conflict with an existing row, and the UPDATE event to the others. For a
+// MERGE, the DELETE event applies to the existing rows for which the delete
+// column is true, and the UPDATE event does not apply to them.
+func (mb *mutationBuilder) triggerEventCond(event tree.TriggerEventType) opt.ScalarExpr {
+	f := mb.b.factory
+	var cond opt.ScalarExpr
+	switch event {
+	case tree.TriggerEventInsert:
+		if mb.canaryColID != 0 {
+			cond = f.ConstructIs(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+		}
+	case tree.TriggerEventUpdate:
+		if mb.canaryColID != 0 {
+			cond = f.ConstructIsNot(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+			if mb.deleteColID != 0 {
+				cond = f.ConstructAnd(cond, f.ConstructNot(f.ConstructVariable(mb.deleteColID)))
+			}
+		}
+	case tree.TriggerEventDelete:
+		if mb.deleteColID != 0 {
+			cond = f.ConstructVariable(mb.deleteColID)
+		}
+	}
+	return cond
+}
+
+// buildAfterTriggers adds a post-query for each AFTER trigger of the target
+// table which fires for the given mutation operator. For an UPSERT, the
+// triggers of both INSERT and UPDATE events are considered, as well as DELETE
+// events for an UPSERT built for a MERGE which deletes rows. It must be called
+// once the final values of all columns are known.
+func (mb *mutationBuilder) buildAfterTriggers(op opt.Operator) {
+	if mb.tab.TriggerCount() == 0 {
+		return
+	}
+	ords := triggerRowOrdinals(mb.tab)
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trig := mb.tab.Trigger(i)
+		if trig.ActionTime() != tree.TriggerActionTimeAfter {
+			continue
+		}
+		tb := &afterTriggerBuilder{mutatedTable: mb.tab, triggerOrdinal: i}
+		switch op {
+		case opt.InsertOp:
+			tb.onInsert = mb.triggerFires(trig, tree.TriggerEventInsert)
+		case opt.UpdateOp:
+			tb.onUpdate = mb.triggerFires(trig, tree.TriggerEventUpdate)
+		case opt.UpsertOp:
+			tb.onInsert = mb.triggerFires(trig, tree.TriggerEventInsert)
+			tb.onUpdate = mb.triggerFires(trig, tree.TriggerEventUpdate)
+			tb.onDelete = mb.deleteColID != 0 && mb.triggerFires(trig, tree.TriggerEventDelete)
+		case opt.DeleteOp:
+			tb.onDelete = mb.triggerFires(trig, tree.TriggerEventDelete)
+		default:
+			panic(errors.AssertionFailedf("unexpected mutation operator %s", op))
+		}
+		if !tb.onInsert && !tb.onUpdate && !tb.onDelete {
+			continue
+		}
+
+		// Buffer the NEW and OLD values of each row. For an UPSERT, the canary
+		// column (and the delete column of a MERGE) is buffered as well, so that
+		// the inserted, updated and deleted rows can be told apart.
+		var oldCols, newCols opt.ColList
+		if op != opt.DeleteOp {
+			tb.newOrds = ords
+			newCols = make(opt.ColList, len(ords))
+			for j, ord := range ords {
+				newCols[j] = mb.mapToReturnColID(ord)
+			}
+		}
+		if op != opt.InsertOp {
+			tb.oldOrds = ords
+			oldCols = make(opt.ColList, len(ords), len(ords)+2)
+			for j, ord := range ords {
+				oldCols[j] = mb.fetchColIDs[ord]
+			}
+			if op == opt.UpsertOp {
+				tb.hasCanary = true
+				oldCols = append(oldCols, mb.canaryColID)
+				if mb.deleteColID != 0 {
+					tb.hasDeleteCol = true
+					oldCols = append(oldCols, mb.deleteColID)
+				}
+			}
+		}
+
+		mb.ensureWithID()
+		mb.cascades = append(mb.cascades, memo.FKCascade{
+			FKName:     string(trig.Name()),
+			Builder:    tb,
+			WithID:     mb.withID,
+			OldValues:  oldCols,
+			NewValues:  newCols,
+			ForEachRow: true,
+		})
+	}
+}
+
+// afterTriggerBuilder is a memo.CascadeBuilder implementation for AFTER
+// triggers. It builds the body of the trigger function for the rows that were
+// mutated by the original statement, which are provided by the buffered
+// mutation input. See the comment at the top of the file for more details.
+type afterTriggerBuilder struct {
+	mutatedTable cat.Table
+	// triggerOrdinal is the ordinal of the trigger on the mutated table (can be
+	// passed to mutatedTable.Trigger).
+	triggerOrdinal int
+
+	// onInsert, onUpdate and onDelete are true if the trigger fires for rows
+	// which were inserted, updated or deleted, respectively.
+	onInsert, onUpdate, onDelete bool
+
+	// newOrds and oldOrds are the table ordinals of the columns provided by
+	// the new and old values.
+	newOrds, oldOrds []int
+
+	// hasCanary is true if the last column of the old values is the canary
+	// column of an UPSERT, which is NULL for inserted rows.
+	hasCanary bool
+
+	// hasDeleteCol is true if the canary column is followed by the delete
+	// column of an UPSERT built for a MERGE, which is true for deleted rows.
+	hasDeleteCol bool
+}
+
+var _ memo.CascadeBuilder = &afterTriggerBuilder{}
+
+// Build is part of the memo.CascadeBuilder interface.
+func (tb *afterTriggerBuilder) Build(
+	ctx context.Context,
+	semaCtx *tree.SemaContext,
+	evalCtx *tree.EvalContext,
+	catalog cat.Catalog,
+	factoryI interface{},
+	binding opt.WithID,
+	bindingProps *props.Relational,
+	oldValues, newValues opt.ColList,
+) (_ memo.RelExpr, err error) {
+	return buildCascadeHelper(ctx, semaCtx, evalCtx, catalog, factoryI, func(b *Builder) memo.RelExpr {
+		trig := tb.mutatedTable.Trigger(tb.triggerOrdinal)
+		fnName, body := b.parseTriggerFunction(trig)
+
+		md := b.factory.Metadata()
+		md.AddWithBinding(binding, b.factory.ConstructFakeRel(&memo.FakeRelPrivate{Props: bindingProps}))
+
+		// Make the buffered values available to the body as CTEs, which are
+		// referenced under the names NEW and OLD.
+		inScope := b.allocScope()
+		inScope.atRoot = true
+		inScope.ctes = make(map[string]*cteSource)
+		var sources tree.TableExprs
+		addSource := func(
+			sourceName string, alias tree.Name, ords []int, cols opt.ColList, hiddenColNames []string,
+		) {
+			tn := tree.MakeUnqualifiedTableName(tree.Name(sourceName))
+			presentation := make(physical.Presentation, len(cols))
+			for i := range cols {
+				var colName string
+				if i < len(ords) {
+					colName = string(tb.mutatedTable.Column(ords[i]).ColName())
+				} else {
+					colName = hiddenColNames[i-len(ords)]
+				}
+				presentation[i] = opt.AliasedColumn{Alias: colName, ID: cols[i]}
+			}
+			inScope.ctes[tn.String()] = &cteSource{
+				id:       binding,
+				name:     tree.AliasClause{Alias: tree.Name(sourceName)},
+				cols:     presentation,
+				hideCols: true,
+				built:    true,
+			}
+			sources = append(sources, &tree.AliasedTableExpr{
+				Expr: &tn,
+				As:   tree.AliasClause{Alias: alias},
+			})
+		}
+		var hiddenColNames []string
+		if tb.hasCanary {
+			hiddenColNames = append(hiddenColNames, triggerCanaryColName)
+		}
+		if tb.hasDeleteCol {
+			hiddenColNames = append(hiddenColNames, triggerDeleteColName)
+		}
+		var canary, isDelete tree.Expr
+		if tb.newOrds != nil {
+			cols := newValues
+			if len(hiddenColNames) > 0 {
+				// Expose the canary and delete columns as hidden columns of NEW.
+				cols = append(cols[:len(cols):len(cols)], oldValues[len(oldValues)-len(hiddenColNames):]...)
+				canary = tree.NewUnresolvedName(string(triggerNewName), triggerCanaryColName)
+				if tb.hasDeleteCol {
+					isDelete = tree.NewUnresolvedName(string(triggerNewName), triggerDeleteColName)
+				}
+			}
+			addSource(triggerNewSourceName, triggerNewName, tb.newOrds, cols, hiddenColNames)
+		}
+		if tb.oldOrds != nil {
+			cols := oldValues[:len(oldValues)-len(hiddenColNames)]
+			addSource(triggerOldSourceName, triggerOldName, tb.oldOrds, cols, nil /* hiddenColNames */)
+		}
+
+		// Only execute the body for the rows which match the WHEN condition of
+		// the trigger. For an UPSERT, rows were either inserted or updated
+		// depending on whether the canary column is NULL. For a MERGE, the
+		// existing rows for which the delete column is true were deleted.
+		cond := parseTriggerWhen(trig)
+		if canary != nil {
+			var events []tree.Expr
+			if tb.onInsert {
+				events = append(events, &tree.IsNullExpr{Expr: canary})
+			}
+			if tb.onUpdate {
+				var isUpdate tree.Expr = &tree.IsNotNullExpr{Expr: canary}
+				if isDelete != nil {
+					isUpdate = andTriggerConds(isUpdate, &tree.NotExpr{Expr: isDelete})
+				}
+				events = append(events, isUpdate)
+			}
+			if tb.onDelete {
+				events = append(events, isDelete)
+			}
+			numEvents := len(hiddenColNames) + 1
+			if len(events) < numEvents {
+				isEvent := events[0]
+				for _, e := range events[1:] {
+					isEvent = &tree.OrExpr{Left: &tree.ParenExpr{Expr: isEvent}, Right: &tree.ParenExpr{Expr: e}}
+				}
+				cond = andTriggerConds(cond, isEvent)
+			}
+		}
+
+		var outScope *scope
+		switch t := body.(type) {
+		case *tree.Insert:
+			ins := *t
+			ins.Rows = buildTriggerInsertRows(fnName, t.Rows, sources, cond)
+			ins.Returning = tree.AbsentReturningClause
+			outScope = b.buildStmt(&ins, nil /* desiredTypes */, inScope)
+
+		case *tree.Update:
+			upd := *t
+			upd.From = append(sources[:len(sources):len(sources)], t.From...)
+			var where tree.Expr
+			if t.Where != nil {
+				where = t.Where.Expr
+			}
+			upd.Where = tree.NewWhere(tree.AstWhere, andTriggerConds(cond, where))
+			upd.Returning = tree.AbsentReturningClause
+			outScope = b.buildStmt(&upd, nil /* desiredTypes */, inScope)
+
+		case *tree.Delete:
+			del := *t
+			del.Using = append(sources[:len(sources):len(sources)], t.Using...)
+			var where tree.Expr
+			if t.Where != nil {
+				where = t.Where.Expr
+			}
+			del.Where = tree.NewWhere(tree.AstWhere, andTriggerConds(cond, where))
+			del.Returning = tree.AbsentReturningClause
+			outScope = b.buildStmt(&del, nil /* desiredTypes */, inScope)
+
+		default:
+			panic(pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"function %s() of AFTER trigger %s must have a single INSERT, UPSERT, UPDATE "+
+					"or DELETE statement as its body", fnName, trig.Name()))
+		}
+
+		// Build With operators for any CTEs defined by the body.
+		return b.buildWiths(outScope.expr, b.ctes)
+	})
+}
+
+// buildTriggerInsertRows returns the input rows of an INSERT statement in the
+// body of an AFTER trigger, joined laterally with the data sources which
+// provide the NEW and OLD values of the row:
+//
+//   SELECT * FROM <sources>, LATERAL (<rows>) AS crdb_internal_trigger_values
+//   WHERE <cond>
+func buildTriggerInsertRows(
+	fnName string, rows *tree.Select, sources tree.TableExprs, cond tree.Expr,
+) *tree.Select {
+	if rows == nil || rows.Select == nil {
+		panic(unimplemented.NewWithIssueDetail(28296, "trigger default values",
+			fmt.Sprintf("INSERT ... DEFAULT VALUES is not supported in the body of trigger function %s()", fnName)))
+	}
+	if values, ok := rows.Select.(*tree.ValuesClause); ok {
+		for _, row := range values.Rows {
+			for _, expr := range row {
+				if _, ok := expr.(tree.DefaultVal); ok {
+					panic(unimplemented.NewWithIssueDetail(28296, "trigger default values",
+						fmt.Sprintf("DEFAULT is not supported in the body of trigger function %s()", fnName)))
+				}
+			}
+		}
+	}
+	from := append(sources[:len(sources):len(sources)], &tree.AliasedTableExpr{
+		Expr:    &tree.Subquery{Select: &tree.ParenSelect{Select: rows}},
+		Lateral: true,
+		As:      tree.AliasClause{Alias: triggerValuesSourceName},
+	})
+	return &tree.Select{Select: &tree.SelectClause{
+		Exprs: tree.SelectExprs{tree.StarSelectExpr()},
+		From:  tree.From{Tables: from},
+		Where: tree.NewWhere(tree.AstWhere, cond),
+	}}
+}
+
+// andTriggerConds returns the conjunction of the given conditions, either of
+// which may be nil.
+func andTriggerConds(left, right tree.Expr) tree.Expr {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	}
+	return &tree.AndExpr{Left: &tree.ParenExpr{Expr: left}, Right: &tree.ParenExpr{Expr: right}}
+}
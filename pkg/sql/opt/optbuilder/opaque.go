@@ -66,6 +66,13 @@ func (b *Builder) tryBuildOpaque(stmt tree.Statement, inScope *scope) (outScope
 	if !ok {
 		return nil
 	}
+	// Opaque statements are planned outside of the optimizer, so they cannot
+	// reference user-defined functions, which are only resolved when they can
+	// be inlined.
+	defer func(r tree.FunctionReferenceResolver) { b.semaCtx.FunctionResolver = r }(
+		b.semaCtx.FunctionResolver,
+	)
+	b.semaCtx.FunctionResolver = nil
 	obj, err := info.buildFn(b.ctx, b.semaCtx, b.evalCtx, stmt)
 	if err != nil {
 		panic(err)
@@ -62,7 +62,14 @@ func (b *Builder) buildScalar(
 		// TODO(rytaft): This currently regenerates a string for each subexpression.
 		// Change this to generate the string once for the top-level expression and
 		// check the relevant slice for this subexpression.
-		if col, ok := inScope.groupby.groupStrs[symbolicExprStr(scalar)]; ok {
+		exprStr := symbolicExprStr(scalar)
+		col, ok := inScope.groupby.groupStrs[exprStr]
+		if !ok {
+			// Grouping expressions which are not part of the current grouping set
+			// are projected as NULL on top of the aggregation.
+			col, ok = inScope.groupby.nulledStrs[exprStr]
+		}
+		if ok {
 			// We pass aggOutScope as the input scope because it contains all of
 			// the aggregates and grouping columns that are available for projection.
 			// finishBuildScalarRef wraps projected columns in a variable expression
@@ -271,6 +278,12 @@ func (b *Builder) buildScalar(
 		texpr := t.Expr.(tree.TypedExpr)
 		arg := b.buildScalar(texpr, inScope, nil, nil, colRefs)
 		out = b.factory.ConstructCast(arg, t.ResolvedType())
+		if t.ResolvedType().IsDomain() {
+			out = b.buildDomainChecks(out, t.ResolvedType(), inScope, colRefs)
+		}
+
+	case *domainValue:
+		out = t.expr
 
 	case *tree.CoalesceExpr:
 		args := make(memo.ScalarListExpr, len(t.Exprs))
@@ -534,6 +547,14 @@ func (b *Builder) buildFunction(
 		panic(errors.AssertionFailedf("window function should have been replaced"))
 	}
 
+	if isUDF(def) {
+		panic(errors.AssertionFailedf("user-defined function should have been replaced"))
+	}
+
+	if def.Name == "grouping" {
+		return b.buildGroupingFunc(f, inScope, outScope, outCol)
+	}
+
 	args := make(memo.ScalarListExpr, len(f.Exprs))
 	for i, pexpr := range f.Exprs {
 		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -123,6 +123,7 @@ const (
 	exprKindHaving
 	exprKindLateralJoin
 	exprKindLimit
+	exprKindMergeWhen
 	exprKindOffset
 	exprKindOn
 	exprKindOrderBy
@@ -144,6 +145,7 @@ var exprKindName = [...]string{
 	exprKindHaving:            "HAVING",
 	exprKindLateralJoin:       "LATERAL JOIN",
 	exprKindLimit:             "LIMIT",
+	exprKindMergeWhen:         "MERGE WHEN",
 	exprKindOffset:            "OFFSET",
 	exprKindOn:                "ON",
 	exprKindOrderBy:           "ORDER BY",
@@ -1013,11 +1015,18 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 		return false, colI.(*scopeColumn)
 
 	case *tree.FuncExpr:
-		def, err := t.Func.Resolve(s.builder.semaCtx.SearchPath)
+		def, err := t.Func.ResolveWithResolver(
+			s.builder.ctx, s.builder.semaCtx.SearchPath, s.builder.semaCtx.FunctionResolver,
+		)
 		if err != nil {
 			panic(err)
 		}
 
+		if isUDF(def) {
+			expr = s.replaceUDF(t, def)
+			break
+		}
+
 		if isGenerator(def) && s.replaceSRFs {
 			expr = s.replaceSRF(t, def)
 			break
@@ -11,6 +11,7 @@
 package optbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -86,6 +87,9 @@ func (b *Builder) buildDataSource(
 				c := b.factory.Metadata().ColumnMeta(id)
 				newCol := b.synthesizeColumn(outScope, scopeColName(tree.Name(col.Alias)), c.Type, nil, nil)
 				newCol.table = *tn
+				if cte.hideCols {
+					newCol.visibility = accessibleByQualifiedStar
+				}
 				inCols[i] = id
 				outCols[i] = newCol.id
 			}
@@ -582,6 +586,13 @@ func (b *Builder) buildScan(
 	}
 	if locking.isSet() {
 		private.Locking = locking.get()
+		// SKIP LOCKED skips over the individual keys which are locked by other
+		// transactions, so it could return rows with some of their column
+		// families missing.
+		if private.Locking.WaitPolicy == tree.LockWaitSkip && tab.FamilyCount() > 1 {
+			panic(unimplementedWithIssueDetailf(40476, "",
+				"SKIP LOCKED is not supported on tables with multiple column families"))
+		}
 	}
 	if b.evalCtx.AsOfSystemTime != nil && b.evalCtx.AsOfSystemTime.BoundedStaleness {
 		private.Flags.NoIndexJoin = true
@@ -982,6 +993,29 @@ func (b *Builder) buildSelectClause(
 	b.processWindowDefs(sel, fromScope)
 	b.buildWhere(sel.Where, fromScope)
 
+	if hasGroupingSets(sel.GroupBy) {
+		return b.buildGroupingSets(sel, orderBy, locking, desiredTypes, fromScope)
+	}
+	return b.buildSelectClauseFromScope(
+		sel, orderBy, locking, desiredTypes, fromScope, nil, /* nulledGroupBy */
+	)
+}
+
+// buildSelectClauseFromScope builds the remainder of the given select clause
+// (everything except the FROM and WHERE clauses) on top of fromScope.
+//
+// nulledGroupBy contains the grouping expressions of a GROUP BY clause with
+// grouping sets which are not part of the grouping set being built (see
+// buildGroupingSets). These expressions evaluate to NULL. It is nil if the
+// GROUP BY clause does not have grouping sets.
+func (b *Builder) buildSelectClauseFromScope(
+	sel *tree.SelectClause,
+	orderBy tree.OrderBy,
+	locking lockingSpec,
+	desiredTypes []*types.T,
+	fromScope *scope,
+	nulledGroupBy tree.Exprs,
+) (outScope *scope) {
 	projectionsScope := fromScope.replace()
 
 	// This is where the magic happens. When this call reaches an aggregate
@@ -1002,7 +1036,7 @@ func (b *Builder) buildSelectClause(
 		// Grouping columns must be built before building the projection list so
 		// we can check that any column references that appear in the SELECT list
 		// outside of aggregate functions are present in the grouping list.
-		b.buildGroupingColumns(sel, projectionsScope, fromScope)
+		b.buildGroupingColumns(sel, nulledGroupBy, projectionsScope, fromScope)
 		having = b.buildHaving(havingExpr, fromScope)
 	}
 
@@ -1306,8 +1340,13 @@ func (b *Builder) validateLockingInFrom(
 		case tree.LockWaitBlock:
 			// Default. Block on conflicting locks.
 		case tree.LockWaitSkip:
-			panic(unimplementedWithIssueDetailf(40476, "",
-				"SKIP LOCKED lock wait policy is not supported"))
+			// Skip rows that can't be locked. Nodes running older versions do not
+			// know the SKIP LOCKED wait policy and would block on the locks instead.
+			if !b.evalCtx.Settings.Version.IsActive(b.ctx, clusterversion.SkipLockedReads) {
+				panic(pgerror.Newf(pgcode.FeatureNotSupported,
+					"version %v must be finalized to use SKIP LOCKED",
+					clusterversion.ByKey(clusterversion.SkipLockedReads)))
+			}
 		case tree.LockWaitError:
 			// Raise an error on conflicting locks.
 		default:
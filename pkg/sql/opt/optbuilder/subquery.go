@@ -71,6 +71,10 @@ type subquery struct {
 	// scope is the input scope of the subquery. It is needed to lazily build
 	// the subquery in TypeCheck.
 	scope *scope
+
+	// udf is the overload of the user-defined function whose body is inlined
+	// by the subquery, if any. See replaceUDF.
+	udf *tree.Overload
 }
 
 // isMultiRow returns whether the subquery can return multiple rows.
@@ -209,6 +213,24 @@ func (s *subquery) buildSubquery(desiredTypes []*types.T) {
 		s.scope.replaceSRFs = false
 	}
 
+	if s.udf != nil {
+		b := s.scope.builder
+		b.udfStack = append(b.udfStack, s.udf.Oid)
+		defer func() { b.udfStack = b.udfStack[:len(b.udfStack)-1] }()
+		if b.trackViewDeps {
+			// Only the direct dependencies of a function definition are
+			// tracked, and not those of the functions which it calls. The
+			// columns referenced by the arguments of the call are still
+			// tracked, since they belong to existing dependencies.
+			numDeps, typeDeps, funcDeps := len(b.viewDeps), b.viewTypeDeps.Copy(), b.funcDeps.Copy()
+			defer func() {
+				b.viewDeps = b.viewDeps[:numDeps]
+				b.viewTypeDeps = typeDeps
+				b.funcDeps = funcDeps
+			}()
+		}
+	}
+
 	// Save and restore the previous value of s.builder.subquery in case we are
 	// recursively called within a subquery context.
 	outer := s.scope.builder.subquery
@@ -0,0 +1,186 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+func isUDF(def *tree.FunctionDefinition) bool {
+	return def.IsUDF
+}
+
+// replaceUDF returns an expression that inlines the body of a call to a
+// user-defined function. For example, given a function declared as:
+//
+//   CREATE FUNCTION f(a INT, b INT) RETURNS INT AS 'SELECT a + b' LANGUAGE SQL
+//
+// the call f(x, y) is replaced with the scalar subquery:
+//
+//   (SELECT CAST((SELECT a + b) AS INT) FROM (VALUES (x::INT, y::INT)) AS f(a, b))
+//
+// The arguments of the function are thus visible to the body by name, or by
+// qualifying their name with the name of the function. Arguments referenced
+// by position ($1, $2, ...) are replaced with references to these columns.
+// If the function returns NULL on NULL input, the body is wrapped in a CASE
+// expression which returns NULL if any of the arguments is NULL.
+func (s *scope) replaceUDF(f *tree.FuncExpr, def *tree.FunctionDefinition) tree.Expr {
+	if s.builder.trackViewDeps && !s.builder.insideFuncDef {
+		// Views do not record dependencies on functions, so dropping the
+		// function would break the view.
+		panic(unimplemented.Newf("user-defined functions",
+			"user-defined function %s() cannot be referenced by a view", def.Name))
+	}
+
+	// We need to save and restore the previous value of the field in
+	// semaCtx in case we are recursively called within a subquery
+	// context.
+	defer s.builder.semaCtx.Properties.Restore(s.builder.semaCtx.Properties)
+
+	s.builder.semaCtx.Properties.Require("user-defined function", tree.RejectSpecial)
+
+	expr := f.Walk(s)
+	typedFunc, err := tree.TypeCheck(s.builder.ctx, expr, s.builder.semaCtx, types.Any)
+	if err != nil {
+		panic(err)
+	}
+	f = typedFunc.(*tree.FuncExpr)
+	o := f.ResolvedOverload()
+	argTypes, ok := o.Types.(tree.ArgTypes)
+	if !ok {
+		panic(errors.AssertionFailedf("unexpected argument types for %s()", def.Name))
+	}
+
+	// The body of a recursive function would be inlined indefinitely.
+	for _, id := range s.builder.udfStack {
+		if id == o.Oid {
+			panic(unimplemented.Newf("user-defined functions",
+				"recursive call to function %s() is not supported", def.Name))
+		}
+	}
+	if s.builder.trackViewDeps {
+		// The body of a function definition is being built; record the call.
+		s.builder.funcDeps.Add(int(o.Oid))
+	}
+
+	stmt, err := parser.ParseOne(o.Body)
+	if err != nil {
+		panic(pgerror.Wrapf(err, pgcode.InvalidFunctionDefinition,
+			"failed to parse body of function %s()", def.Name))
+	}
+	body, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		panic(unimplemented.Newf("user-defined functions",
+			"function %s() must have a single SELECT statement as its body", def.Name))
+	}
+
+	// The body of a function is not tied to a particular query, so it cannot
+	// be cached along with the memo.
+	s.builder.DisableMemoReuse = true
+
+	sel := makeUDFSelect(def.Name, body, argTypes, f.Exprs, o.CalledOnNullInput, f.ResolvedType())
+	sub := s.replaceSubquery(
+		&tree.Subquery{Select: &tree.ParenSelect{Select: sel}},
+		false /* wrapInTuple */, 1 /* desiredNumColumns */, noExtraColsAllowed,
+	)
+	sub.udf = o
+	return sub
+}
+
+// makeUDFSelect returns the statement which evaluates the given body of a
+// user-defined function for a single row of argument values. See replaceUDF
+// for the shape of the statement.
+func makeUDFSelect(
+	name string,
+	body *tree.Select,
+	argTypes tree.ArgTypes,
+	args tree.Exprs,
+	calledOnNullInput bool,
+	retType *types.T,
+) *tree.Select {
+	// Name the arguments so that they can be referenced by the body. Unnamed
+	// arguments can only be referenced by position.
+	argNames := make(tree.NameList, len(argTypes))
+	argRefs := make(tree.Exprs, len(argTypes))
+	row := make(tree.Exprs, len(argTypes))
+	for i := range argTypes {
+		argName := argTypes[i].Name
+		if argName == "" {
+			argName = fmt.Sprintf("$%d", i+1)
+		}
+		argNames[i] = tree.Name(argName)
+		argRefs[i] = tree.NewUnresolvedName(name, argName)
+		row[i] = &tree.CastExpr{Expr: args[i], Type: argTypes[i].Typ, SyntaxMode: tree.CastShort}
+	}
+	body = replacePlaceholders(body, argRefs).(*tree.Select)
+
+	var result tree.Expr = &tree.Subquery{Select: &tree.ParenSelect{Select: body}}
+	if !calledOnNullInput && len(argRefs) > 0 {
+		var anyNull tree.Expr
+		for i := range argRefs {
+			isNull := &tree.IsNullExpr{Expr: argRefs[i]}
+			if anyNull == nil {
+				anyNull = isNull
+			} else {
+				anyNull = &tree.OrExpr{Left: anyNull, Right: isNull}
+			}
+		}
+		result = &tree.CaseExpr{
+			Whens: []*tree.When{{Cond: anyNull, Val: tree.DNull}},
+			Else:  result,
+		}
+	}
+	result = &tree.CastExpr{Expr: result, Type: retType, SyntaxMode: tree.CastShort}
+
+	sel := &tree.SelectClause{Exprs: tree.SelectExprs{{Expr: result}}}
+	if len(row) > 0 {
+		values := &tree.Select{Select: &tree.ValuesClause{Rows: []tree.Exprs{row}}}
+		sel.From.Tables = tree.TableExprs{&tree.AliasedTableExpr{
+			Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: values}},
+			As:   tree.AliasClause{Alias: tree.Name(name), Cols: argNames},
+		}}
+	}
+	return &tree.Select{Select: sel}
+}
+
+// replacePlaceholders replaces the positional references to arguments ($1,
+// $2, ...) in the body of a user-defined function with the given expressions.
+func replacePlaceholders(stmt tree.Statement, args tree.Exprs) tree.Statement {
+	newStmt, err := tree.SimpleStmtVisit(stmt, func(expr tree.Expr) (bool, tree.Expr, error) {
+		switch t := expr.(type) {
+		case *tree.Placeholder:
+			if int(t.Idx) >= len(args) {
+				// SimpleStmtVisit does not propagate errors, so panic instead.
+				panic(pgerror.Newf(pgcode.UndefinedParameter, "there is no parameter $%d", t.Idx+1))
+			}
+			return false, args[t.Idx], nil
+		case *tree.Subquery:
+			// Subqueries are not traversed by the visitor, so their
+			// placeholders are replaced here.
+			cpy := *t
+			cpy.Select = replacePlaceholders(t.Select, args).(tree.SelectStatement)
+			return false, &cpy, nil
+		}
+		return true, expr, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return newStmt
+}
@@ -13,6 +13,7 @@ package optbuilder
 import (
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -101,10 +102,15 @@ func (b *Builder) buildUpdate(upd *tree.Update, inScope *scope) (outScope *scope
 	mb.buildInputForUpdate(inScope, upd.Table, upd.From, upd.Where, upd.Limit, upd.OrderBy)
 
 	// Derive the columns that will be updated from the SET expressions.
-	mb.addTargetColsForUpdate(upd.Exprs)
+	exprs := mb.expandFieldUpdates(upd.Exprs)
+	mb.addTargetColsForUpdate(exprs)
 
 	// Build each of the SET expressions.
-	mb.addUpdateCols(upd.Exprs)
+	mb.addUpdateCols(exprs)
+
+	// Add additional columns for computed expressions that may depend on the
+	// updated columns.
+	mb.addSynthesizedColsForUpdate()
 
 	// Build the final update statement, including any returned expressions.
 	if resultsNeeded(upd.Returning) {
@@ -126,6 +132,9 @@ func (mb *mutationBuilder) addTargetColsForUpdate(exprs tree.UpdateExprs) {
 	}
 
 	for _, expr := range exprs {
+		if expr.Field != "" {
+			panic(errors.AssertionFailedf("field assignments must be expanded by expandFieldUpdates"))
+		}
 		mb.addTargetColsByName(expr.Names)
 
 		if expr.Tuple {
@@ -162,6 +171,93 @@ func (mb *mutationBuilder) addTargetColsForUpdate(exprs tree.UpdateExprs) {
 	}
 }
 
+// expandFieldUpdates rewrites SET expressions which assign to a field of a
+// column of a composite type into assignments of the entire column. The
+// remaining fields keep their existing values:
+//
+//   CREATE TYPE typ AS (x INT, y INT, z INT)
+//   CREATE TABLE abc (a INT PRIMARY KEY, c typ)
+//   UPDATE abc SET c.x=1, c.y=2
+//   =>
+//   UPDATE abc SET c=(1, 2, (c).z)::typ
+//
+// The input slice is returned unchanged if there are no field assignments.
+func (mb *mutationBuilder) expandFieldUpdates(exprs tree.UpdateExprs) tree.UpdateExprs {
+	hasFields := false
+	for _, expr := range exprs {
+		if expr.Field != "" {
+			hasFields = true
+			break
+		}
+	}
+	if !hasFields {
+		return exprs
+	}
+
+	res := make(tree.UpdateExprs, 0, len(exprs))
+	// fieldUpdates maps the name of a column to the tuple which has been built
+	// for it, so that multiple fields of the same column can be assigned.
+	type fieldUpdate struct {
+		tuple    *tree.Tuple
+		assigned []bool
+	}
+	fieldUpdates := make(map[tree.Name]*fieldUpdate)
+	for _, expr := range exprs {
+		if expr.Field == "" {
+			res = append(res, expr)
+			continue
+		}
+		colName := expr.Names[0]
+		ord := findPublicTableColumnByName(mb.tab, colName)
+		if ord == -1 {
+			panic(colinfo.NewUndefinedColumnError(string(colName)))
+		}
+		typ := mb.tab.Column(ord).DatumType()
+		if !typ.IsComposite() {
+			panic(pgerror.Newf(pgcode.DatatypeMismatch,
+				"cannot assign to field %q of column %q because its type %s is not a composite type",
+				expr.Field, colName, typ.SQLString()))
+		}
+		fieldIdx := -1
+		for i, label := range typ.TupleLabels() {
+			if label == string(expr.Field) {
+				fieldIdx = i
+				break
+			}
+		}
+		if fieldIdx == -1 {
+			panic(pgerror.Newf(pgcode.UndefinedColumn,
+				"cannot assign to field %q of column %q because there is no such column in data type %s",
+				expr.Field, colName, typ.SQLString()))
+		}
+
+		u, ok := fieldUpdates[colName]
+		if !ok {
+			// Start with the existing values of all fields.
+			colRef := &tree.UnresolvedName{NumParts: 1, Parts: tree.NameParts{string(colName)}}
+			u = &fieldUpdate{
+				tuple:    &tree.Tuple{Exprs: make(tree.Exprs, len(typ.TupleContents()))},
+				assigned: make([]bool, len(typ.TupleContents())),
+			}
+			for i, label := range typ.TupleLabels() {
+				u.tuple.Exprs[i] = &tree.ColumnAccessExpr{Expr: colRef, ColName: tree.Name(label)}
+			}
+			fieldUpdates[colName] = u
+			res = append(res, &tree.UpdateExpr{
+				Names: tree.NameList{colName},
+				Expr:  &tree.CastExpr{Expr: u.tuple, Type: typ, SyntaxMode: tree.CastShort},
+			})
+		}
+		if u.assigned[fieldIdx] {
+			panic(pgerror.Newf(pgcode.Syntax,
+				"multiple assignments to the same column %q", string(colName)+"."+string(expr.Field)))
+		}
+		u.assigned[fieldIdx] = true
+		u.tuple.Exprs[fieldIdx] = expr.Expr
+	}
+	return res
+}
+
 // addUpdateCols builds nested Project and LeftOuterJoin expressions that
 // correspond to the given SET expressions:
 //
@@ -282,10 +378,6 @@ func (mb *mutationBuilder) addUpdateCols(exprs tree.UpdateExprs) {
 
 	// Add assignment casts for update columns.
 	mb.addAssignmentCasts(mb.updateColIDs)
-
-	// Add additional columns for computed expressions that may depend on the
-	// updated columns.
-	mb.addSynthesizedColsForUpdate()
 }
 
 // addSynthesizedColsForUpdate wraps an Update input expression with a Project
@@ -300,6 +392,10 @@ func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
 		mb.outScope.cols[i].mutation = false
 	}
 
+	// Apply any BEFORE triggers, which may modify the updated values before
+	// computed columns are derived from them.
+	mb.buildBeforeTriggers(tree.TriggerEventUpdate)
+
 	// Add non-computed columns that are being dropped or added (mutated) to the
 	// table. These are not visible to queries, and will always be updated to
 	// their default values. This is necessary because they may not yet have been
@@ -322,6 +418,9 @@ func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
 
 	// Add assignment casts for computed column values.
 	mb.addAssignmentCasts(mb.updateColIDs)
+
+	// Check the constraints of the DOMAIN types of the updated columns.
+	mb.addDomainChecks(mb.updateColIDs)
 }
 
 // buildUpdate constructs an Update operator, possibly wrapped by a Project
@@ -346,6 +445,8 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 
 	mb.buildFKChecksForUpdate()
 
+	mb.buildAfterTriggers(opt.UpdateOp)
+
 	private := mb.makeMutationPrivate(returning != nil)
 	for _, col := range mb.extraAccessibleCols {
 		if col.id != 0 {
@@ -37,6 +37,12 @@ type cteSource struct {
 	// error.
 	onRef func()
 
+	// hideCols is true if the columns of the CTE can only be referenced by a
+	// qualified name or a qualified star, so that they do not shadow the
+	// columns of other data sources. It is used for the NEW and OLD values of
+	// row-level triggers.
+	hideCols bool
+
 	// built is true if we have constructed a With operator for this CTE.
 	built bool
 }
@@ -190,6 +196,10 @@ func (b *Builder) buildCTEs(
 func (b *Builder) buildCTE(
 	cte *tree.CTE, inScope *scope, isRecursive bool,
 ) (memo.RelExpr, physical.Presentation, opt.Ordering) {
+	if _, ok := cte.Stmt.(*tree.Merge); ok {
+		panic(pgerror.Newf(pgcode.FeatureNotSupported, "MERGE not supported in WITH query"))
+	}
+
 	if !isRecursive {
 		cteScope := b.buildStmt(cte.Stmt, nil /* desiredTypes */, inScope)
 		cteScope.removeHiddenCols()
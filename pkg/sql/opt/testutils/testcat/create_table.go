@@ -529,10 +529,11 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		referencedTableID:        targetTable.ID(),
 		originColumnOrdinals:     fromCols,
 		referencedColumnOrdinals: toCols,
-		validated:                true,
+		validated:                d.Deferrability == tree.ConstraintNotDeferrable,
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrability:            d.Deferrability,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
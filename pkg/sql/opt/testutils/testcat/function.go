@@ -0,0 +1,92 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package testcat
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+)
+
+var _ tree.FunctionReferenceResolver = (*Catalog)(nil)
+
+// CreateFunction handles the CREATE FUNCTION statement.
+func (tc *Catalog) CreateFunction(c *tree.CreateFunction) {
+	// We don't handle fully qualified names.
+	name := c.FuncName.Object()
+
+	ctx := context.Background()
+	argTypes := make(tree.ArgTypes, len(c.Args))
+	for i := range c.Args {
+		typ, err := tree.ResolveType(ctx, c.Args[i].Type, tc)
+		if err != nil {
+			panic(err)
+		}
+		argTypes[i].Name = string(c.Args[i].Name)
+		argTypes[i].Typ = typ
+	}
+	retType, err := tree.ResolveType(ctx, c.ReturnType.Type, tc)
+	if err != nil {
+		panic(err)
+	}
+
+	overload := tree.Overload{
+		Types:             argTypes,
+		ReturnType:        tree.FixedReturnType(retType),
+		Volatility:        tree.VolatilityVolatile,
+		CalledOnNullInput: true,
+	}
+	for _, option := range c.Options {
+		switch t := option.(type) {
+		case tree.FunctionBodyStr:
+			overload.Body = string(t)
+		case tree.FunctionNullInputBehavior:
+			overload.CalledOnNullInput = t == tree.FunctionCalledOnNullInput
+		case tree.FunctionVolatility:
+			switch t {
+			case tree.FunctionImmutable:
+				overload.Volatility = tree.VolatilityImmutable
+			case tree.FunctionStable:
+				overload.Volatility = tree.VolatilityStable
+			}
+		}
+	}
+
+	if tc.udfs == nil {
+		tc.udfs = make(map[string][]tree.Overload)
+	}
+	tc.udfs[name] = append(tc.udfs[name], overload)
+}
+
+// ResolveFunction is part of the cat.Catalog interface.
+func (tc *Catalog) ResolveFunction(
+	ctx context.Context, name *tree.UnresolvedName, path sessiondata.SearchPath,
+) (*tree.FunctionDefinition, error) {
+	overloads, ok := tc.udfs[name.Parts[0]]
+	if !ok {
+		return nil, pgerror.Newf(pgcode.UndefinedFunction, "unknown function: %s()", name)
+	}
+	// Copy the overloads, since NewUDFFunctionDefinition modifies them.
+	def := make([]tree.Overload, len(overloads))
+	copy(def, overloads)
+	return tree.NewUDFFunctionDefinition(name.Parts[0], def), nil
+}
+
+// ResolveFunctionByID is part of the cat.Catalog interface.
+func (tc *Catalog) ResolveFunctionByID(
+	ctx context.Context, id cat.StableID,
+) (*tree.FunctionDefinition, error) {
+	return nil, pgerror.Newf(pgcode.UndefinedFunction, "function [%d] does not exist", id)
+}
@@ -48,6 +48,7 @@ type Catalog struct {
 	testSchema Schema
 	counter    int
 	enumTypes  map[string]*types.T
+	udfs       map[string][]tree.Overload
 }
 
 type dataSource interface {
@@ -401,6 +402,10 @@ func (tc *Catalog) ExecuteDDLWithIndexVersion(
 		tc.CreateType(stmt)
 		return "", nil
 
+	case *tree.CreateFunction:
+		tc.CreateFunction(stmt)
+		return "", nil
+
 	case *tree.SetZoneConfig:
 		tc.SetZoneConfig(stmt)
 		return "", nil
@@ -749,6 +754,16 @@ func (tt *Table) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &tt.uniqueConstraints[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (tt *Table) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (tt *Table) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
 // Zone is part of the cat.Table interface.
 func (tt *Table) Zone() cat.Zone {
 	zone := zonepb.DefaultZoneConfig()
@@ -1149,10 +1164,11 @@ type ForeignKeyConstraint struct {
 	originColumnOrdinals     []int
 	referencedColumnOrdinals []int
 
-	validated    bool
-	matchMethod  tree.CompositeKeyMatchMethod
-	deleteAction tree.ReferenceAction
-	updateAction tree.ReferenceAction
+	validated     bool
+	matchMethod   tree.CompositeKeyMatchMethod
+	deleteAction  tree.ReferenceAction
+	updateAction  tree.ReferenceAction
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &ForeignKeyConstraint{}
@@ -1220,6 +1236,11 @@ func (fk *ForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction {
 	return fk.updateAction
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *ForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // UniqueConstraint implements cat.UniqueConstraint. See that interface
 // for more information on the fields.
 type UniqueConstraint struct {
@@ -1280,6 +1301,11 @@ func (u *UniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return false
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *UniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return tree.ConstraintNotDeferrable
+}
+
 // Sequence implements the cat.Sequence interface for testing purposes.
 type Sequence struct {
 	SeqID      cat.StableID
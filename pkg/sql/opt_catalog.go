@@ -13,6 +13,7 @@ package sql
 import (
 	"context"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
@@ -25,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
@@ -37,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -260,6 +263,31 @@ func (oc *optCatalog) ResolveType(
 	return oc.planner.ResolveType(ctx, name)
 }
 
+// ResolveFunction is part of the cat.Catalog interface.
+func (oc *optCatalog) ResolveFunction(
+	ctx context.Context, name *tree.UnresolvedName, path sessiondata.SearchPath,
+) (*tree.FunctionDefinition, error) {
+	return oc.planner.ResolveFunction(ctx, name, path)
+}
+
+// ResolveFunctionByID is part of the cat.Catalog interface.
+func (oc *optCatalog) ResolveFunctionByID(
+	ctx context.Context, id cat.StableID,
+) (*tree.FunctionDefinition, error) {
+	desc, err := oc.planner.Descriptors().GetImmutableFunctionByID(
+		ctx, oc.planner.txn, descpb.ID(id),
+		oc.planner.ObjectLookupFlags(true /* required */, false /* requireMutable */),
+	)
+	if err != nil {
+		return nil, err
+	}
+	overload, err := funcdesc.ToOverload(desc)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewUDFFunctionDefinition(desc.GetName(), []tree.Overload{overload}), nil
+}
+
 func getDescFromCatalogObjectForPermissions(o cat.Object) (catalog.Descriptor, error) {
 	switch t := o.(type) {
 	case *optSchema:
@@ -648,6 +676,9 @@ type optTable struct {
 	// constraints for user defined types.
 	checkConstraints []cat.CheckConstraint
 
+	// triggers are the row-level triggers of the table, ordered by name.
+	triggers []optTrigger
+
 	// colMap is a mapping from unique ColumnID to column ordinal within the
 	// table. This is a common lookup that needs to be fast.
 	colMap catalog.TableColMap
@@ -777,12 +808,13 @@ func newOptTable(
 	for i := range ot.desc.GetUniqueWithoutIndexConstraints() {
 		u := &ot.desc.GetUniqueWithoutIndexConstraints()[i]
 		ot.uniqueConstraints = append(ot.uniqueConstraints, optUniqueConstraint{
-			name:         u.Name,
-			table:        ot.ID(),
-			columns:      u.ColumnIDs,
-			predicate:    u.Predicate,
-			withoutIndex: true,
-			validity:     u.Validity,
+			name:          u.Name,
+			table:         ot.ID(),
+			columns:       u.ColumnIDs,
+			predicate:     u.Predicate,
+			withoutIndex:  true,
+			validity:      u.Validity,
+			deferrability: u.Deferrability,
 		})
 	}
 
@@ -882,6 +914,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability,
 		})
 		return nil
 	})
@@ -896,10 +929,23 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability,
 		})
 		return nil
 	})
 
+	// Triggers fire in the order of their names.
+	ot.triggers = make([]optTrigger, len(ot.desc.GetTriggers()))
+	for i := range ot.desc.GetTriggers() {
+		ot.triggers[i] = optTrigger{
+			table:   ot.ID(),
+			trigger: &ot.desc.GetTriggers()[i],
+		}
+	}
+	sort.Slice(ot.triggers, func(i, j int) bool {
+		return ot.triggers[i].trigger.Name < ot.triggers[j].trigger.Name
+	})
+
 	ot.primaryFamily.init(ot, &desc.GetFamilies()[0])
 	ot.families = make([]optFamily, len(desc.GetFamilies())-1)
 	for i := range ot.families {
@@ -1162,6 +1208,16 @@ func (ot *optTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return &ot.triggers[i]
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optTable) Zone() cat.Zone {
 	return ot.zone
@@ -1643,8 +1699,9 @@ type optUniqueConstraint struct {
 	columns   []descpb.ColumnID
 	predicate string
 
-	withoutIndex bool
-	validity     descpb.ConstraintValidity
+	withoutIndex  bool
+	validity      descpb.ConstraintValidity
+	deferrability catpb.ConstraintDeferrability
 
 	uniquenessGuaranteedByAnotherIndex bool
 }
@@ -1689,9 +1746,12 @@ func (u *optUniqueConstraint) WithoutIndex() bool {
 	return u.withoutIndex
 }
 
-// Validated is part of the cat.UniqueConstraint interface.
+// Validated is part of the cat.UniqueConstraint interface. A deferrable
+// constraint can be violated in the middle of a transaction, so it is never
+// considered validated.
 func (u *optUniqueConstraint) Validated() bool {
-	return u.validity == descpb.ConstraintValidity_Validated
+	return u.validity == descpb.ConstraintValidity_Validated &&
+		u.deferrability == catpb.ConstraintDeferrability_NOT_DEFERRABLE
 }
 
 // UniquenessGuaranteedByAnotherIndex is part of the cat.UniqueConstraint
@@ -1702,6 +1762,81 @@ func (u *optUniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return u.uniquenessGuaranteedByAnotherIndex
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return descpb.ConstraintDeferrabilityType[u.deferrability]
+}
+
+// optTrigger implements cat.Trigger and represents a row-level trigger.
+type optTrigger struct {
+	table   cat.StableID
+	trigger *descpb.TableDescriptor_Trigger
+}
+
+var _ cat.Trigger = &optTrigger{}
+
+// Name is part of the cat.Trigger interface.
+func (t *optTrigger) Name() tree.Name {
+	return tree.Name(t.trigger.Name)
+}
+
+// ActionTime is part of the cat.Trigger interface.
+func (t *optTrigger) ActionTime() tree.TriggerActionTime {
+	if t.trigger.ActionTime == descpb.TableDescriptor_Trigger_AFTER {
+		return tree.TriggerActionTimeAfter
+	}
+	return tree.TriggerActionTimeBefore
+}
+
+// HasEvent is part of the cat.Trigger interface.
+func (t *optTrigger) HasEvent(event tree.TriggerEventType) bool {
+	var want descpb.TableDescriptor_Trigger_Event
+	switch event {
+	case tree.TriggerEventInsert:
+		want = descpb.TableDescriptor_Trigger_INSERT
+	case tree.TriggerEventUpdate:
+		want = descpb.TableDescriptor_Trigger_UPDATE
+	case tree.TriggerEventDelete:
+		want = descpb.TableDescriptor_Trigger_DELETE
+	default:
+		return false
+	}
+	for _, e := range t.trigger.Events {
+		if e == want {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateColumnCount is part of the cat.Trigger interface.
+func (t *optTrigger) UpdateColumnCount() int {
+	return len(t.trigger.UpdateColumnIDs)
+}
+
+// UpdateColumnOrdinal is part of the cat.Trigger interface.
+func (t *optTrigger) UpdateColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != t.table {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to UpdateColumnOrdinal (expected %d)",
+			tab.ID(), t.table,
+		))
+	}
+	optTab := convertTableToOptTable(tab)
+	ord, _ := optTab.lookupColumnOrdinal(t.trigger.UpdateColumnIDs[i])
+	return ord
+}
+
+// WhenExpr is part of the cat.Trigger interface.
+func (t *optTrigger) WhenExpr() (string, bool) {
+	return t.trigger.WhenExpr, t.trigger.WhenExpr != ""
+}
+
+// FunctionID is part of the cat.Trigger interface.
+func (t *optTrigger) FunctionID() cat.StableID {
+	return cat.StableID(t.trigger.FuncID)
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -1715,10 +1850,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []descpb.ColumnID
 
-	validity     descpb.ConstraintValidity
-	match        descpb.ForeignKeyReference_Match
-	deleteAction catpb.ForeignKeyAction
-	updateAction catpb.ForeignKeyAction
+	validity      descpb.ConstraintValidity
+	match         descpb.ForeignKeyReference_Match
+	deleteAction  catpb.ForeignKeyAction
+	updateAction  catpb.ForeignKeyAction
+	deferrability catpb.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1770,9 +1906,12 @@ func (fk *optForeignKeyConstraint) ReferencedColumnOrdinal(referencedTable cat.T
 	return ord
 }
 
-// Validated is part of the cat.ForeignKeyConstraint interface.
+// Validated is part of the cat.ForeignKeyConstraint interface. A deferrable
+// constraint can be violated in the middle of a transaction, so it is never
+// considered validated.
 func (fk *optForeignKeyConstraint) Validated() bool {
-	return fk.validity == descpb.ConstraintValidity_Validated
+	return fk.validity == descpb.ConstraintValidity_Validated &&
+		fk.deferrability == catpb.ConstraintDeferrability_NOT_DEFERRABLE
 }
 
 // MatchMethod is part of the cat.ForeignKeyConstraint interface.
@@ -1790,6 +1929,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return descpb.ForeignKeyReferenceActionType[fk.updateAction]
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return descpb.ConstraintDeferrabilityType[fk.deferrability]
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc catalog.TableDescriptor
@@ -2082,6 +2226,16 @@ func (ot *optVirtualTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optVirtualTable) Zone() cat.Zone {
 	panic(errors.AssertionFailedf("no zone"))
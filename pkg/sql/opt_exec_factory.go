@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -41,6 +42,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
 	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
 )
 
 type execFactory struct {
@@ -1556,6 +1558,7 @@ func (ef *execFactory) ConstructUpsert(
 	arbiterIndexes cat.IndexOrdinals,
 	arbiterConstraints cat.UniqueOrdinals,
 	canaryCol exec.NodeColumnOrdinal,
+	deleteCol exec.NodeColumnOrdinal,
 	insertColOrdSet exec.TableColumnOrdinalSet,
 	fetchColOrdSet exec.TableColumnOrdinalSet,
 	updateColOrdSet exec.TableColumnOrdinalSet,
@@ -1621,6 +1624,7 @@ func (ef *execFactory) ConstructUpsert(
 			tw: optTableUpserter{
 				ri:            ri,
 				canaryOrdinal: int(canaryCol),
+				deleteOrdinal: int(deleteCol),
 				fetchCols:     fetchCols,
 				updateCols:    updateCols,
 				ru:            ru,
@@ -1628,6 +1632,20 @@ func (ef *execFactory) ConstructUpsert(
 		},
 	}
 
+	// Create the table deleter if existing rows can be deleted (for a MERGE
+	// statement). The fetch columns include the columns needed to delete the
+	// rows.
+	if deleteCol != -1 {
+		ups.run.tw.rd = row.MakeDeleter(
+			ef.planner.ExecCfg().Codec,
+			tabDesc,
+			fetchCols,
+			&ef.planner.ExecCfg().Settings.SV,
+			internal,
+			ef.planner.ExecCfg().GetRowMetrics(internal),
+		)
+	}
+
 	// If rows are not needed, no columns are returned.
 	if rowsNeeded {
 		returnCols := makeColList(table, returnColOrdSet)
@@ -1662,6 +1680,7 @@ func (ef *execFactory) ConstructDelete(
 	table cat.Table,
 	fetchColOrdSet exec.TableColumnOrdinalSet,
 	returnColOrdSet exec.TableColumnOrdinalSet,
+	passthrough colinfo.ResultColumns,
 	autoCommit bool,
 ) (exec.Node, error) {
 	// Derive table and column descriptors.
@@ -1693,7 +1712,8 @@ func (ef *execFactory) ConstructDelete(
 		source: input.(planNode),
 		run: deleteRun{
 			td:                        tableDeleter{rd: rd, alloc: ef.planner.alloc},
-			partialIndexDelValsOffset: len(rd.FetchCols),
+			partialIndexDelValsOffset: len(rd.FetchCols) + len(passthrough),
+			numPassthrough:            len(passthrough),
 		},
 	}
 
@@ -1703,6 +1723,8 @@ func (ef *execFactory) ConstructDelete(
 		// Delete returns the non-mutation columns specified, in the same
 		// order they are defined in the table.
 		del.columns = colinfo.ResultColumnsFromColumns(tabDesc.GetID(), returnCols)
+		// Add the passthrough columns to the returning columns.
+		del.columns = append(del.columns, passthrough...)
 
 		del.run.rowIdxToRetIdx = row.ColMapping(rd.FetchCols, returnCols)
 		del.run.rowsNeeded = true
@@ -1810,6 +1832,66 @@ func (ef *execFactory) ConstructCreateView(
 		return nil, err
 	}
 
+	planDeps, err := makePlanDependencies(deps)
+	if err != nil {
+		return nil, err
+	}
+
+	typeDepSet := make(typeDependencies, typeDeps.Len())
+	typeDeps.ForEach(func(id int) {
+		typeDepSet[descpb.ID(id)] = struct{}{}
+	})
+
+	return &createViewNode{
+		viewName:     viewName,
+		ifNotExists:  ifNotExists,
+		replace:      replace,
+		materialized: materialized,
+		persistence:  persistence,
+		viewQuery:    viewQuery,
+		dbDesc:       schema.(*optSchema).database,
+		columns:      columns,
+		planDeps:     planDeps,
+		typeDeps:     typeDepSet,
+	}, nil
+}
+
+// ConstructCreateFunction is part of the exec.Factory interface.
+func (ef *execFactory) ConstructCreateFunction(
+	schema cat.Schema, cf *tree.CreateFunction, deps opt.ViewDeps, funcDeps opt.ViewTypeDeps,
+) (exec.Node, error) {
+	if err := checkSchemaChangeEnabled(
+		ef.planner.EvalContext().Context,
+		ef.planner.ExecCfg(),
+		"CREATE FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+
+	planDeps, err := makePlanDependencies(deps)
+	if err != nil {
+		return nil, err
+	}
+	funcDepIDs := make([]descpb.ID, 0, funcDeps.Len())
+	for o, ok := funcDeps.Next(0); ok; o, ok = funcDeps.Next(o + 1) {
+		id, err := funcdesc.UserDefinedFunctionOIDToID(oid.Oid(o))
+		if err != nil {
+			return nil, err
+		}
+		funcDepIDs = append(funcDepIDs, id)
+	}
+
+	return &createFunctionNode{
+		n:        cf,
+		planDeps: planDeps,
+		funcDeps: funcDepIDs,
+	}, nil
+}
+
+// makePlanDependencies converts the data source dependencies collected by
+// the optimizer into the back references to be written to the descriptors of
+// the data sources.
+func makePlanDependencies(deps opt.ViewDeps) (planDependencies, error) {
 	planDeps := make(planDependencies, len(deps))
 	for _, d := range deps {
 		desc, err := getDescForDataSource(d.DataSource)
@@ -1832,24 +1914,7 @@ func (ef *execFactory) ConstructCreateView(
 		entry.deps = append(entry.deps, ref)
 		planDeps[desc.GetID()] = entry
 	}
-
-	typeDepSet := make(typeDependencies, typeDeps.Len())
-	typeDeps.ForEach(func(id int) {
-		typeDepSet[descpb.ID(id)] = struct{}{}
-	})
-
-	return &createViewNode{
-		viewName:     viewName,
-		ifNotExists:  ifNotExists,
-		replace:      replace,
-		materialized: materialized,
-		persistence:  persistence,
-		viewQuery:    viewQuery,
-		dbDesc:       schema.(*optSchema).database,
-		columns:      columns,
-		planDeps:     planDeps,
-		typeDeps:     typeDepSet,
-	}, nil
+	return planDeps, nil
 }
 
 // ConstructSequenceSelect is part of the exec.Factory interface.
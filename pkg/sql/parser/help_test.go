@@ -57,6 +57,12 @@ func TestContextualHelp(t *testing.T) {
 		{`ALTER TENANT ALL SET ??`, `ALTER TENANT`},
 		{`ALTER TENANT ALL RESET ??`, `ALTER TENANT`},
 
+		{`ALTER FUNCTION ??`, `ALTER FUNCTION`},
+		{`ALTER FUNCTION f ??`, `ALTER FUNCTION`},
+		{`ALTER FUNCTION f() RENAME ??`, `ALTER FUNCTION`},
+
+		{`ALTER DOMAIN ??`, `ALTER DOMAIN`},
+
 		{`ALTER TYPE ??`, `ALTER TYPE`},
 		{`ALTER TYPE t ??`, `ALTER TYPE`},
 		{`ALTER TYPE t ADD VALUE ??`, `ALTER TYPE`},
@@ -160,8 +166,20 @@ func TestContextualHelp(t *testing.T) {
 		{`CREATE TABLE blah AS SELECT 1 ??`, `SELECT`},
 
 		{`CREATE TYPE blah AS ENUM ??`, `CREATE TYPE`},
+		{`CREATE TYPE blah AS (a ??`, `CREATE TYPE`},
 		{`DROP TYPE ??`, `DROP TYPE`},
 
+		{`CREATE DOMAIN ??`, `CREATE DOMAIN`},
+		{`DROP DOMAIN ??`, `DROP DOMAIN`},
+
+		{`CREATE FUNCTION ??`, `CREATE FUNCTION`},
+		{`CREATE OR REPLACE FUNCTION f ??`, `CREATE FUNCTION`},
+		{`CREATE TRIGGER ??`, `CREATE TRIGGER`},
+		{`CREATE TRIGGER t BEFORE INSERT ON ??`, `CREATE TRIGGER`},
+		{`DROP FUNCTION ??`, `DROP FUNCTION`},
+		{`DROP TRIGGER ??`, `DROP TRIGGER`},
+		{`DROP TRIGGER IF EXISTS t ON ??`, `DROP TRIGGER`},
+
 		{`CREATE SCHEMA IF ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA IF NOT ??`, `CREATE SCHEMA`},
 		{`CREATE SCHEMA bli ??`, `CREATE SCHEMA`},
@@ -174,6 +192,11 @@ func TestContextualHelp(t *testing.T) {
 		{`DISCARD ALL ??`, `DISCARD`},
 		{`DISCARD ??`, `DISCARD`},
 
+		{`LISTEN ??`, `LISTEN`},
+		{`NOTIFY ??`, `NOTIFY`},
+		{`NOTIFY foo, ??`, `NOTIFY`},
+		{`UNLISTEN ??`, `UNLISTEN`},
+
 		{`DROP ??`, `DROP`},
 
 		{`DROP DATABASE IF ??`, `DROP DATABASE`},
@@ -251,6 +274,10 @@ func TestContextualHelp(t *testing.T) {
 		{`INSERT INTO blah VALUES (1) ??`, `VALUES`},
 		{`INSERT INTO blah TABLE foo ??`, `TABLE`},
 
+		{`MERGE ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON true ??`, `MERGE`},
+		{`MERGE INTO blah USING foo ON true WHEN MATCHED THEN ??`, `MERGE`},
+
 		{`UPSERT INTO ??`, `UPSERT`},
 		{`UPSERT INTO blah (??`, `<SELECTCLAUSE>`},
 		{`UPSERT INTO blah VALUES (1) RETURNING ??`, `UPSERT`},
@@ -440,6 +467,9 @@ func TestContextualHelp(t *testing.T) {
 		{`SET LOCAL TIME ??`, `SET LOCAL`},
 		{`SET LOCAL TIME ZONE 'UTC' ??`, `SET LOCAL`},
 
+		{`SET CONSTRAINTS ??`, `SET CONSTRAINTS`},
+		{`SET CONSTRAINTS ALL ??`, `SET CONSTRAINTS`},
+
 		{`SET TRANSACTION ??`, `SET TRANSACTION`},
 		{`SET TRANSACTION ISOLATION LEVEL SNAPSHOT ??`, `SET TRANSACTION`},
 		{`SET TIME ??`, `SET SESSION`},
@@ -303,6 +303,9 @@ func (u *sqlSymUnion) nameList() tree.NameList {
 func (u *sqlSymUnion) enumValueList() tree.EnumValueList {
     return u.val.(tree.EnumValueList)
 }
+func (u *sqlSymUnion) compositeTypeList() tree.CompositeTypeList {
+    return u.val.(tree.CompositeTypeList)
+}
 func (u *sqlSymUnion) unresolvedName() *tree.UnresolvedName {
     return u.val.(*tree.UnresolvedName)
 }
@@ -495,6 +498,12 @@ func (u *sqlSymUnion) updateExpr() *tree.UpdateExpr {
 func (u *sqlSymUnion) updateExprs() tree.UpdateExprs {
     return u.val.(tree.UpdateExprs)
 }
+func (u *sqlSymUnion) mergeWhen() *tree.MergeWhen {
+    return u.val.(*tree.MergeWhen)
+}
+func (u *sqlSymUnion) mergeWhens() tree.MergeWhens {
+    return u.val.(tree.MergeWhens)
+}
 func (u *sqlSymUnion) limit() *tree.Limit {
     return u.val.(*tree.Limit)
 }
@@ -591,6 +600,9 @@ func (u *sqlSymUnion) readWriteMode() tree.ReadWriteMode {
 func (u *sqlSymUnion) deferrableMode() tree.DeferrableMode {
     return u.val.(tree.DeferrableMode)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+    return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) idxElem() tree.IndexElem {
     return u.val.(tree.IndexElem)
 }
@@ -779,6 +791,36 @@ func (u *sqlSymUnion) cursorStmt() tree.CursorStmt {
 func (u *sqlSymUnion) asTenantClause() tree.TenantID {
     return u.val.(tree.TenantID)
 }
+func (u *sqlSymUnion) funcArg() tree.FuncArg {
+    return u.val.(tree.FuncArg)
+}
+func (u *sqlSymUnion) funcArgs() tree.FuncArgs {
+    return u.val.(tree.FuncArgs)
+}
+func (u *sqlSymUnion) funcReturnType() tree.FuncReturnType {
+    return u.val.(tree.FuncReturnType)
+}
+func (u *sqlSymUnion) functionOption() tree.FunctionOption {
+    return u.val.(tree.FunctionOption)
+}
+func (u *sqlSymUnion) functionOptions() tree.FunctionOptions {
+    return u.val.(tree.FunctionOptions)
+}
+func (u *sqlSymUnion) triggerActionTime() tree.TriggerActionTime {
+    return u.val.(tree.TriggerActionTime)
+}
+func (u *sqlSymUnion) triggerEvent() *tree.TriggerEvent {
+    return u.val.(*tree.TriggerEvent)
+}
+func (u *sqlSymUnion) triggerEvents() tree.TriggerEvents {
+    return u.val.(tree.TriggerEvents)
+}
+func (u *sqlSymUnion) funcObj() tree.FuncObj {
+    return u.val.(tree.FuncObj)
+}
+func (u *sqlSymUnion) funcObjs() tree.FuncObjs {
+    return u.val.(tree.FuncObjs)
+}
 %}
 
 // NB: the %token definitions must come before the %type definitions in this
@@ -806,7 +848,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> BUCKET_COUNT
 %token <str> BOOLEAN BOTH BOX2D BUNDLE BY
 
-%token <str> CACHE CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
+%token <str> CACHE CALLED CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK CLOSE
 %token <str> CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMENTS COMMIT
 %token <str> COMMITTED COMPACT COMPLETE COMPLETIONS CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
@@ -820,7 +862,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DESC DESTINATION DETACHED
 %token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
 
-%token <str> ELSE ENCODING ENCRYPTED ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> EACH ELSE ENCODING ENCRYPTED ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT EXPERIMENTAL_RELOCATE
@@ -838,11 +880,11 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> HAVING HASH HEADER HIGH HISTOGRAM HOLD HOUR
 
 %token <str> IDENTITY
-%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCLUDE
+%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMMUTABLE IMPORT IN INCLUDE
 %token <str> INCLUDING INCREMENT INCREMENTAL INCREMENTAL_LOCATION
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INITIALLY
-%token <str> INNER INSENSITIVE INSERT INT INTEGER
+%token <str> INNER INPUT INSENSITIVE INSERT INSTEAD INT INTEGER
 %token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED IS ISERROR ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
@@ -850,18 +892,18 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> KEY KEYS KMS KV
 
 %token <str> LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
-%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
+%token <str> LEADING LEAKPROOF LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
-%token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
+%token <str> LIST LISTEN LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH MOVE
+%token <str> MATCH MATCHED MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
 
-%token <str> NAN NAME NAMES NATURAL NEVER NEW_DB_NAME NEW_KMS NEXT NO NOCANCELQUERY NOCONTROLCHANGEFEED
+%token <str> NAN NAME NAMES NATURAL NEVER NEW_DB_NAME NEW_KMS NEW_TABLE_NAME NEXT NO NOCANCELQUERY NOCONTROLCHANGEFEED
 %token <str> NOCONTROLJOB NOCREATEDB NOCREATELOGIN NOCREATEROLE NOLOGIN NOMODIFYCLUSTERSETTING
-%token <str> NOSQLLOGIN NO_INDEX_JOIN NO_ZIGZAG_JOIN NO_FULL_SCAN NONE NONVOTERS NORMAL NOT NOTHING NOTNULL
+%token <str> NOSQLLOGIN NO_INDEX_JOIN NO_ZIGZAG_JOIN NO_FULL_SCAN NONE NONVOTERS NORMAL NOT NOTHING NOTIFY NOTNULL
 %token <str> NOVIEWACTIVITY NOVIEWACTIVITYREDACTED NOVIEWCLUSTERSETTING NOWAIT NULL NULLIF NULLS NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR OLD_KMS ON ONLY OPT OPTION OPTIONS OR
@@ -870,24 +912,24 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACEMENT PLACING
 %token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
 %token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
-%token <str> PROCEDURAL PUBLIC PUBLICATION
+%token <str> PROCEDURAL PROCEDURE PUBLIC PUBLICATION
 
 %token <str> QUERIES QUERY QUOTE
 
 %token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH RENAME REPEATABLE REPLACE REPLICATION
-%token <str> RELEASE RESET RESTORE RESTRICT RESTRICTED RESUME RETURNING RETRY REVISION_HISTORY
+%token <str> RELEASE RESET RESTORE RESTRICT RESTRICTED RESUME RETURNING RETURNS RETRY REVISION_HISTORY
 %token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUNNING
 
-%token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
-%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
+%token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMA_ONLY SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETOF SETS SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SKIP_LOCALITIES_CHECK SKIP_MISSING_FOREIGN_KEYS
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 %token <str> SQLLOGIN
 
-%token <str> START STATE STATISTICS STATUS STDIN STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING SUPER
-%token <str> SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENTS
+%token <str> STABLE START STATE STATISTICS STATUS STDIN STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING SUPER
+%token <str> SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENT STATEMENTS
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TENANTS TESTING_RELOCATE TEXT THEN
 %token <str> TIES TIME TIMETZ TIMESTAMP TIMESTAMPTZ TO THROTTLING TRAILING TRACE
@@ -895,11 +937,11 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> TRUNCATE TRUSTED TYPE TYPES
 %token <str> TRACING
 
-%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED UNSPLIT
+%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLISTEN UNLOGGED UNSPLIT
 %token <str> UPDATE UPSERT UNSET UNTIL USE USER USERS USING UUID
 
 %token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIEWACTIVITY VIEWACTIVITYREDACTED
-%token <str> VIEWCLUSTERSETTING VIRTUAL VISIBLE VOTERS
+%token <str> VIEWCLUSTERSETTING VIRTUAL VISIBLE VOLATILE VOTERS
 
 %token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRITE
 
@@ -954,6 +996,8 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.Statement> alter_role_stmt
 %type <*tree.SetVar> set_or_reset_clause
 %type <tree.Statement> alter_type_stmt
+%type <tree.Statement> alter_domain_stmt
+%type <tree.Statement> alter_func_stmt
 %type <tree.Statement> alter_schema_stmt
 %type <tree.Statement> alter_unsupported_stmt
 
@@ -1058,8 +1102,15 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <*tree.CreateStatsOptions> create_stats_option
 
 %type <tree.Statement> create_type_stmt
+%type <tree.Statement> create_domain_stmt
+%type <tree.Statement> create_func_stmt
+%type <tree.Statement> create_trigger_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
+%type <tree.Statement> listen_stmt
+%type <tree.Statement> notify_stmt
+%type <*tree.StrVal> opt_notify_payload
+%type <tree.Statement> unlisten_stmt
 
 %type <tree.Statement> drop_stmt
 %type <tree.Statement> drop_ddl_stmt
@@ -1069,6 +1120,9 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.Statement> drop_schema_stmt
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_type_stmt
+%type <tree.Statement> drop_domain_stmt
+%type <tree.Statement> drop_func_stmt
+%type <tree.Statement> drop_trigger_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
 
@@ -1084,6 +1138,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.Statement> grant_stmt
 %type <tree.Statement> insert_stmt
 %type <tree.Statement> import_stmt
+%type <tree.Statement> merge_stmt
 %type <tree.Statement> pause_stmt pause_jobs_stmt pause_schedules_stmt pause_all_jobs_stmt
 %type <*tree.Select>   for_schedules_clause
 %type <tree.Statement> reassign_owned_by_stmt
@@ -1107,6 +1162,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt set_or_reset_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -1221,6 +1277,18 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 
 %type <tree.DropBehavior> opt_drop_behavior
 
+%type <bool> opt_or_replace
+%type <*tree.UnresolvedObjectName> func_create_name
+%type <tree.FuncArgs> func_args func_args_list opt_func_args_list
+%type <tree.FuncArg> func_arg
+%type <tree.ResolvableTypeReference> func_type
+%type <tree.FuncReturnType> func_return_type
+%type <tree.FunctionOptions> opt_create_func_opt_list create_func_opt_list
+%type <tree.FunctionOption> create_func_opt_item common_func_opt_item
+%type <str> func_as param_name
+%type <tree.FuncObj> function_with_argtypes
+%type <tree.FuncObjs> function_with_argtypes_list
+
 %type <tree.ValidationBehavior> opt_validate_behavior
 
 %type <str> opt_template_clause opt_encoding_clause opt_lc_collate_clause opt_lc_ctype_clause
@@ -1288,7 +1356,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.NameList> name_list privilege_list
 %type <[]int32> opt_array_bounds
 %type <tree.From> from_clause
-%type <tree.TableExprs> from_list rowsfrom_list opt_from_list
+%type <tree.TableExprs> from_list rowsfrom_list opt_from_list opt_using_clause
 %type <tree.TablePatterns> table_pattern_list
 %type <tree.TableNames> table_name_list opt_locked_rels
 %type <tree.Exprs> expr_list opt_expr_list tuple1_ambiguous_values tuple1_unambiguous_values
@@ -1298,6 +1366,9 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.SelectExprs> target_list
 %type <tree.UpdateExprs> set_clause_list
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
+%type <tree.MergeWhens> merge_when_list
+%type <*tree.MergeWhen> merge_when_clause merge_matched_action merge_not_matched_action
+%type <tree.Expr> opt_merge_cond
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
 %type <tree.Exprs> group_by_list
@@ -1305,7 +1376,6 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <*tree.Limit> select_limit opt_select_limit
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
-%type <empty> opt_using_clause
 %type <tree.RefreshDataOption> opt_clear_data
 
 %type <[]tree.SequenceOption> sequence_option_list opt_sequence_option_list
@@ -1348,6 +1418,10 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <*tree.ColumnTableDef> column_def
 %type <tree.TableDef> table_elem
 %type <tree.Expr> where_clause opt_where_clause
+%type <tree.Expr> opt_trigger_when
+%type <tree.TriggerActionTime> trigger_action_time
+%type <*tree.TriggerEvent> trigger_event
+%type <tree.TriggerEvents> trigger_event_list
 %type <*tree.ArraySubscript> array_subscript
 %type <tree.Expr> opt_slice_bound
 %type <*tree.IndexFlags> opt_index_flags
@@ -1385,6 +1459,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 
 %type <str> explain_option_name
 %type <[]string> explain_option_list opt_enum_val_list enum_val_list
+%type <tree.CompositeTypeList> opt_composite_type_list composite_type_list
 
 %type <tree.ResolvableTypeReference> typename simple_typename cast_target
 %type <*types.T> const_typename
@@ -1432,6 +1507,8 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.NamedColumnQualification> col_qualification create_as_col_qualification
 %type <tree.ColumnQualification> col_qualification_elem create_as_col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
+%type <tree.ConstraintDeferrability> opt_deferrable
+%type <bool> constraints_mode
 %type <tree.ReferenceActions> reference_actions
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
@@ -1577,6 +1654,8 @@ stmt:
 | deallocate_stmt           // EXTEND WITH HELP: DEALLOCATE
 | discard_stmt              // EXTEND WITH HELP: DISCARD
 | grant_stmt                // EXTEND WITH HELP: GRANT
+| listen_stmt               // EXTEND WITH HELP: LISTEN
+| notify_stmt               // EXTEND WITH HELP: NOTIFY
 | prepare_stmt              // EXTEND WITH HELP: PREPARE
 | revoke_stmt               // EXTEND WITH HELP: REVOKE
 | savepoint_stmt            // EXTEND WITH HELP: SAVEPOINT
@@ -1591,6 +1670,7 @@ stmt:
 | fetch_cursor_stmt         // EXTEND WITH HELP: FETCH
 | move_cursor_stmt          // EXTEND WITH HELP: MOVE
 | reindex_stmt
+| unlisten_stmt             // EXTEND WITH HELP: UNLISTEN
 | /* EMPTY */
   {
     $$.val = tree.Statement(nil)
@@ -1616,6 +1696,8 @@ alter_ddl_stmt:
 | alter_partition_stmt          // EXTEND WITH HELP: ALTER PARTITION
 | alter_schema_stmt             // EXTEND WITH HELP: ALTER SCHEMA
 | alter_type_stmt               // EXTEND WITH HELP: ALTER TYPE
+| alter_domain_stmt             // EXTEND WITH HELP: ALTER DOMAIN
+| alter_func_stmt               // EXTEND WITH HELP: ALTER FUNCTION
 | alter_default_privileges_stmt // EXTEND WITH HELP: ALTER DEFAULT PRIVILEGES
 | alter_changefeed_stmt         // EXTEND WITH HELP: ALTER CHANGEFEED
 | alter_backup_stmt             // EXTEND WITH HELP: ALTER BACKUP
@@ -2586,6 +2668,64 @@ opt_validate_behavior:
     $$.val = tree.ValidationDefault
   }
 
+// %Help: ALTER FUNCTION - change the definition of a function
+// %Category: DDL
+// %Text:
+// ALTER FUNCTION <name> [ ( [ [<argname>] <argtype> [, ...] ] ) ] RENAME TO <newname>
+// ALTER FUNCTION <name> [ ( [ [<argname>] <argtype> [, ...] ] ) ] OWNER TO <newowner>
+// ALTER FUNCTION <name> [ ( [ [<argname>] <argtype> [, ...] ] ) ] SET SCHEMA <newschemaname>
+// %SeeAlso: CREATE FUNCTION, DROP FUNCTION
+alter_func_stmt:
+  ALTER FUNCTION function_with_argtypes RENAME TO name
+  {
+    $$.val = &tree.AlterFunctionRename{
+      Function: $3.funcObj(),
+      NewName: tree.Name($6),
+    }
+  }
+| ALTER FUNCTION function_with_argtypes OWNER TO role_spec
+  {
+    $$.val = &tree.AlterFunctionSetOwner{
+      Function: $3.funcObj(),
+      NewOwner: $6.roleSpec(),
+    }
+  }
+| ALTER FUNCTION function_with_argtypes SET SCHEMA schema_name
+  {
+    $$.val = &tree.AlterFunctionSetSchema{
+      Function: $3.funcObj(),
+      NewSchemaName: tree.Name($6),
+    }
+  }
+| ALTER FUNCTION error // SHOW HELP: ALTER FUNCTION
+
+// %Help: ALTER DOMAIN - change the definition of a domain.
+// %Category: DDL
+// %Text: ALTER DOMAIN <typename> ADD [CONSTRAINT <constraint_name>] CHECK (<expr>)
+//
+// The CHECK constraint refers to the value being checked as VALUE.
+// %SeeAlso: CREATE DOMAIN, DROP DOMAIN
+alter_domain_stmt:
+  ALTER DOMAIN type_name ADD CHECK '(' a_expr ')'
+  {
+    $$.val = &tree.AlterDomain{
+      TypeName: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainAddConstraint{
+        Check: tree.DomainCheck{Expr: $7.expr()},
+      },
+    }
+  }
+| ALTER DOMAIN type_name ADD CONSTRAINT constraint_name CHECK '(' a_expr ')'
+  {
+    $$.val = &tree.AlterDomain{
+      TypeName: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainAddConstraint{
+        Check: tree.DomainCheck{Name: tree.Name($6), Expr: $9.expr()},
+      },
+    }
+  }
+| ALTER DOMAIN error // SHOW HELP: ALTER DOMAIN
+
 // %Help: ALTER TYPE - change the definition of a type.
 // %Category: DDL
 // %Text: ALTER TYPE <typename> <command>
@@ -3090,6 +3230,7 @@ opt_with_schedule_options:
 // %Text:
 // RESTORE <targets...> FROM <location...>
 //         [ AS OF SYSTEM TIME <expr> ]
+//         [ WHERE <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
 // or
 // RESTORE SYSTEM USERS FROM <location...>
@@ -3115,6 +3256,8 @@ opt_with_schedule_options:
 //    skip_localities_check: ignore difference of zone configuration between restore cluster and backup cluster
 //    debug_pause_on: describes the events that the job should pause itself on for debugging purposes.
 //    new_db_name: renames the restored database. only applies to database restores
+//    new_table_name: renames the restored table. only applies to single table restores
+//    schema_only: restore the descriptors of the targets without their data
 // %SeeAlso: BACKUP, WEBDOCS/restore.html
 restore_stmt:
   RESTORE FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
@@ -3136,23 +3279,25 @@ restore_stmt:
 		Options: *($7.restoreOptions()),
     }
   }
-| RESTORE targets FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
+| RESTORE targets FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_where_clause opt_with_restore_options
   {
     $$.val = &tree.Restore{
     Targets: $2.targetList(),
     From: $4.listOfStringOrPlaceholderOptList(),
     AsOf: $5.asOfClause(),
-    Options: *($6.restoreOptions()),
+    Where: tree.NewWhere(tree.AstWhere, $6.expr()),
+    Options: *($7.restoreOptions()),
     }
   }
-| RESTORE targets FROM string_or_placeholder IN list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
+| RESTORE targets FROM string_or_placeholder IN list_of_string_or_placeholder_opt_list opt_as_of_clause opt_where_clause opt_with_restore_options
   {
     $$.val = &tree.Restore{
       Targets: $2.targetList(),
       Subdir: $4.expr(),
       From: $6.listOfStringOrPlaceholderOptList(),
       AsOf: $7.asOfClause(),
-      Options: *($8.restoreOptions()),
+      Where: tree.NewWhere(tree.AstWhere, $8.expr()),
+      Options: *($9.restoreOptions()),
     }
   }
 | RESTORE SYSTEM USERS FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
@@ -3301,6 +3446,10 @@ restore_options:
   {
     $$.val = &tree.RestoreOptions{NewDBName: $3.expr()}
   }
+| NEW_TABLE_NAME '=' string_or_placeholder
+  {
+    $$.val = &tree.RestoreOptions{NewTableName: $3.expr()}
+  }
 | INCREMENTAL_LOCATION '=' string_or_placeholder_opt_list
 	{
 		$$.val = &tree.RestoreOptions{IncrementalStorage: $3.stringOrPlaceholderOptList()}
@@ -3309,6 +3458,10 @@ restore_options:
   {
     $$.val = &tree.RestoreOptions{AsTenant: $3.expr()}
   }
+| SCHEMA_ONLY
+  {
+    $$.val = &tree.RestoreOptions{SchemaOnly: true}
+  }
 
 import_format:
   name
@@ -3317,15 +3470,7 @@ import_format:
   }
 
 alter_unsupported_stmt:
-  ALTER FUNCTION error
-  {
-    return unimplementedWithIssueDetail(sqllex, 17511, "alter function")
-  }
-| ALTER DOMAIN error
-  {
-    return unimplemented(sqllex, "alter domain")
-  }
-| ALTER AGGREGATE error
+  ALTER AGGREGATE error
   {
     return unimplementedWithIssueDetail(sqllex, 74775, "alter aggregate")
   }
@@ -3740,7 +3885,10 @@ comment_stmt:
     $$.val = &tree.CommentOnConstraint{Constraint:tree.Name($4), Table: $6.unresolvedObjectName(), Comment: $8.strPtr()}
   }
 | COMMENT ON EXTENSION error { return unimplementedWithIssueDetail(sqllex, 74777, "comment on extension") }
-| COMMENT ON FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "comment on function") }
+| COMMENT ON FUNCTION function_with_argtypes IS comment_text
+  {
+    $$.val = &tree.CommentOnFunction{Function: $4.funcObj(), Comment: $6.strPtr()}
+  }
 
 comment_text:
   SCONST
@@ -3799,8 +3947,6 @@ create_unsupported:
 | CREATE DEFAULT CONVERSION error { return unimplemented(sqllex, "create def conv") }
 | CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
-| CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
-| CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
 | CREATE OPERATOR error { return unimplementedWithIssue(sqllex, 65017) }
 | CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
@@ -3809,11 +3955,10 @@ create_unsupported:
 | CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TABLESPACE error { return unimplementedWithIssueDetail(sqllex, 54113, "create tablespace") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
-| CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create trigger") }
 
 opt_or_replace:
-  OR REPLACE {}
-| /* EMPTY */ {}
+  OR REPLACE { $$.val = true }
+| /* EMPTY */ { $$.val = false }
 
 opt_trusted:
   TRUSTED {}
@@ -3829,12 +3974,10 @@ drop_unsupported:
 | DROP CAST error { return unimplemented(sqllex, "drop cast") }
 | DROP COLLATION error { return unimplemented(sqllex, "drop collation") }
 | DROP CONVERSION error { return unimplemented(sqllex, "drop conversion") }
-| DROP DOMAIN error { return unimplementedWithIssueDetail(sqllex, 27796, "drop") }
 | DROP EXTENSION IF EXISTS name error { return unimplementedWithIssueDetail(sqllex, 74777, "drop extension if exists") }
 | DROP EXTENSION name error { return unimplementedWithIssueDetail(sqllex, 74777, "drop extension") }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
-| DROP FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "drop function") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
 | DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
@@ -3842,7 +3985,6 @@ drop_unsupported:
 | DROP SERVER error { return unimplemented(sqllex, "drop server") }
 | DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
-| DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
   create_database_stmt // EXTEND WITH HELP: CREATE DATABASE
@@ -3853,6 +3995,9 @@ create_ddl_stmt:
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
 | CREATE opt_persistence_temp_table TABLE error   // SHOW HELP: CREATE TABLE
 | create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
+| create_domain_stmt   // EXTEND WITH HELP: CREATE DOMAIN
+| create_func_stmt     // EXTEND WITH HELP: CREATE FUNCTION
+| create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 
@@ -3957,6 +4102,12 @@ create_stats_option:
 // CREATE CHANGEFEED
 // FOR <targets> [INTO sink] [WITH <options>]
 //
+// CREATE CHANGEFEED
+// FOR DATABASE <database_name> [INTO sink] [WITH <options>]
+//
+// CREATE CHANGEFEED [INTO sink] [WITH <options>]
+// AS SELECT <targets> FROM <table> [WHERE <expr>]
+//
 // Sink: Data caputre stream stream destination.  Enterprise only.
 create_changefeed_stmt:
   CREATE CHANGEFEED FOR changefeed_targets opt_changefeed_sink opt_with_options
@@ -3967,6 +4118,29 @@ create_changefeed_stmt:
       Options: $6.kvOptions(),
     }
   }
+| CREATE CHANGEFEED FOR DATABASE database_name opt_changefeed_sink opt_with_options
+  {
+    $$.val = &tree.CreateChangefeed{
+      Database: tree.Name($5),
+      SinkURI: $6.expr(),
+      Options: $7.kvOptions(),
+    }
+  }
+| CREATE CHANGEFEED opt_changefeed_sink opt_with_options AS SELECT target_list FROM table_name opt_where_clause
+  {
+    target := $9.unresolvedObjectName()
+    name := target.ToTableName()
+    $$.val = &tree.CreateChangefeed{
+      Targets: tree.ChangefeedTargets{{TableName: target.ToUnresolvedName()}},
+      SinkURI: $3.expr(),
+      Options: $4.kvOptions(),
+      Select: &tree.SelectClause{
+        Exprs: $7.selExprs(),
+        From:  tree.From{Tables: tree.TableExprs{&tree.AliasedTableExpr{Expr: &name}}},
+        Where: tree.NewWhere(tree.AstWhere, $10.expr()),
+      },
+    }
+  }
 | EXPERIMENTAL CHANGEFEED FOR changefeed_targets opt_with_options
   {
     /* SKIP DOC */
@@ -3975,6 +4149,14 @@ create_changefeed_stmt:
       Options: $5.kvOptions(),
     }
   }
+| EXPERIMENTAL CHANGEFEED FOR DATABASE database_name opt_with_options
+  {
+    /* SKIP DOC */
+    $$.val = &tree.CreateChangefeed{
+      Database: tree.Name($5),
+      Options: $6.kvOptions(),
+    }
+  }
 
 changefeed_targets:
   changefeed_target
@@ -4031,7 +4213,8 @@ opt_changefeed_sink:
 
 // %Help: DELETE - delete rows from a table
 // %Category: DML
-// %Text: DELETE FROM <tablename> [WHERE <expr>]
+// %Text: DELETE FROM <tablename> [USING <tables...>]
+//               [WHERE <expr>]
 //               [ORDER BY <exprs...>]
 //               [LIMIT <expr>]
 //               [RETURNING <exprs...>]
@@ -4042,6 +4225,7 @@ delete_stmt:
     $$.val = &tree.Delete{
       With: $1.with(),
       Table: $4.tblExpr(),
+      Using: $5.tblExprs(),
       Where: tree.NewWhere(tree.AstWhere, $6.expr()),
       OrderBy: $7.orderBy(),
       Limit: $8.limit(),
@@ -4051,8 +4235,14 @@ delete_stmt:
 | opt_with_clause DELETE error // SHOW HELP: DELETE
 
 opt_using_clause:
-  USING from_list { return unimplementedWithIssueDetail(sqllex, 40963, "delete using") }
-| /* EMPTY */ { }
+  USING from_list
+  {
+    $$.val = $2.tblExprs()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TableExprs{}
+  }
 
 
 // %Help: DISCARD - reset the session to its initial state
@@ -4069,6 +4259,53 @@ discard_stmt:
 | DISCARD TEMPORARY { return unimplemented(sqllex, "discard temp") }
 | DISCARD error // SHOW HELP: DISCARD
 
+// %Help: LISTEN - register the session as a listener on a notification channel
+// %Category: Misc
+// %Text: LISTEN <channel>
+// %SeeAlso: NOTIFY, UNLISTEN
+listen_stmt:
+  LISTEN name
+  {
+    $$.val = &tree.Listen{ChannelName: tree.Name($2)}
+  }
+| LISTEN error // SHOW HELP: LISTEN
+
+// %Help: NOTIFY - send a notification to the listeners of a channel
+// %Category: Misc
+// %Text: NOTIFY <channel> [, <payload>]
+// %SeeAlso: LISTEN, UNLISTEN
+notify_stmt:
+  NOTIFY name opt_notify_payload
+  {
+    $$.val = &tree.Notify{ChannelName: tree.Name($2), Payload: $3.strVal()}
+  }
+| NOTIFY error // SHOW HELP: NOTIFY
+
+opt_notify_payload:
+  ',' SCONST
+  {
+    $$.val = tree.NewStrVal($2)
+  }
+| /* EMPTY */
+  {
+    $$.val = (*tree.StrVal)(nil)
+  }
+
+// %Help: UNLISTEN - stop listening on a notification channel
+// %Category: Misc
+// %Text: UNLISTEN { <channel> | * }
+// %SeeAlso: LISTEN, NOTIFY
+unlisten_stmt:
+  UNLISTEN name
+  {
+    $$.val = &tree.Unlisten{ChannelName: tree.Name($2)}
+  }
+| UNLISTEN '*'
+  {
+    $$.val = &tree.Unlisten{All: true}
+  }
+| UNLISTEN error // SHOW HELP: UNLISTEN
+
 // %Help: DROP
 // %Category: Group
 // %Text:
@@ -4089,6 +4326,9 @@ drop_ddl_stmt:
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
 | drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_domain_stmt   // EXTEND WITH HELP: DROP DOMAIN
+| drop_func_stmt     // EXTEND WITH HELP: DROP FUNCTION
+| drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -4223,6 +4463,99 @@ drop_type_stmt:
   }
 | DROP TYPE error // SHOW HELP: DROP TYPE
 
+// %Help: DROP DOMAIN - remove a domain
+// %Category: DDL
+// %Text: DROP DOMAIN [IF EXISTS] <type_name> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE DOMAIN
+drop_domain_stmt:
+  DROP DOMAIN type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropDomain{
+      Names: $3.unresolvedObjectNames(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP DOMAIN IF EXISTS type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropDomain{
+      Names: $5.unresolvedObjectNames(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP DOMAIN error // SHOW HELP: DROP DOMAIN
+
+// %Help: DROP FUNCTION - remove a function
+// %Category: DDL
+// %Text:
+// DROP FUNCTION [IF EXISTS] <name> [ ( [ [<argname>] <argtype> [, ...] ] ) ] [, ...]
+//    [CASCADE | RESTRICT]
+// %SeeAlso: CREATE FUNCTION
+drop_func_stmt:
+  DROP FUNCTION function_with_argtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $3.funcObjs(),
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP FUNCTION IF EXISTS function_with_argtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      IfExists: true,
+      Functions: $5.funcObjs(),
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
+// %Help: DROP TRIGGER - remove a trigger
+// %Category: DDL
+// %Text: DROP TRIGGER [IF EXISTS] <name> ON <tablename> [CASCADE | RESTRICT]
+// %SeeAlso: CREATE TRIGGER
+drop_trigger_stmt:
+  DROP TRIGGER name ON table_name opt_drop_behavior
+  {
+    name := $5.unresolvedObjectName().ToTableName()
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($3),
+      Table: name,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP TRIGGER IF EXISTS name ON table_name opt_drop_behavior
+  {
+    name := $7.unresolvedObjectName().ToTableName()
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($5),
+      Table: name,
+      IfExists: true,
+      DropBehavior: $8.dropBehavior(),
+    }
+  }
+| DROP TRIGGER error // SHOW HELP: DROP TRIGGER
+
+function_with_argtypes_list:
+  function_with_argtypes
+  {
+    $$.val = tree.FuncObjs{$1.funcObj()}
+  }
+| function_with_argtypes_list ',' function_with_argtypes
+  {
+    $$.val = append($1.funcObjs(), $3.funcObj())
+  }
+
+function_with_argtypes:
+  db_object_name func_args
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName(), Args: $2.funcArgs()}
+  }
+| db_object_name
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName()}
+  }
+
 target_types:
   type_name_list
   {
@@ -4403,6 +4736,7 @@ preparable_stmt:
 | explain_stmt   // EXTEND WITH HELP: EXPLAIN
 | import_stmt    // EXTEND WITH HELP: IMPORT
 | insert_stmt    // EXTEND WITH HELP: INSERT
+| merge_stmt     // EXTEND WITH HELP: MERGE
 | pause_stmt     // help texts in sub-rule
 | reset_stmt     // help texts in sub-rule
 | restore_stmt   // EXTEND WITH HELP: RESTORE
@@ -4657,6 +4991,7 @@ deallocate_stmt:
 //   DATABASE <databasename> [, ...]
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //   TYPE <typename> [, <typename>]...
+//   FUNCTION <funcname> [ ( <argtypes> ) ] [, ...]
 //   SCHEMA [<databasename> .]<schemaname> [, [<databasename> .]<schemaname>]...
 //   ALL TABLES IN SCHEMA schema_name [, ...]
 //
@@ -4705,6 +5040,15 @@ grant_stmt:
       WithGrantOption: $11.bool(),
     }
   }
+| GRANT privileges ON FUNCTION function_with_argtypes_list TO role_spec_list opt_with_grant_option
+  {
+    $$.val = &tree.Grant{
+      Privileges: $2.privilegeList(),
+      Targets: tree.TargetList{Functions: $5.funcObjs()},
+      Grantees: $7.roleSpecList(),
+      WithGrantOption: $8.bool(),
+    }
+  }
 | GRANT privileges ON SEQUENCE error
   {
     return unimplementedWithIssueDetail(sqllex, 74780, "grant privileges on sequence")
@@ -4726,6 +5070,7 @@ grant_stmt:
 //   DATABASE <databasename> [, <databasename>]...
 //   [TABLE] [<databasename> .] { <tablename> | * } [, ...]
 //   TYPE <typename> [, <typename>]...
+//   FUNCTION <funcname> [ ( <argtypes> ) ] [, ...]
 //   SCHEMA [<databasename> .]<schemaname> [, [<databasename> .]<schemaname]...
 //   ALL TABLES IN SCHEMA schema_name [, ...]
 //
@@ -4755,6 +5100,24 @@ revoke_stmt:
   {
     $$.val = &tree.Revoke{Privileges: $5.privilegeList(), Targets: $8.targetList(), Grantees: $10.roleSpecList(), GrantOptionFor: true}
   }
+| REVOKE privileges ON FUNCTION function_with_argtypes_list FROM role_spec_list
+  {
+    $$.val = &tree.Revoke{
+      Privileges: $2.privilegeList(),
+      Targets: tree.TargetList{Functions: $5.funcObjs()},
+      Grantees: $7.roleSpecList(),
+      GrantOptionFor: false,
+    }
+  }
+| REVOKE GRANT OPTION FOR privileges ON FUNCTION function_with_argtypes_list FROM role_spec_list
+  {
+    $$.val = &tree.Revoke{
+      Privileges: $5.privilegeList(),
+      Targets: tree.TargetList{Functions: $8.funcObjs()},
+      Grantees: $10.roleSpecList(),
+      GrantOptionFor: true,
+    }
+  }
 | REVOKE privileges ON SCHEMA schema_name_list FROM role_spec_list
   {
     $$.val = &tree.Revoke{
@@ -4894,7 +5257,7 @@ use_stmt:
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 
 // SET SESSION / SET LOCAL / SET CLUSTER SETTING
 preparable_set_stmt:
@@ -5128,6 +5491,31 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - set the checking mode of deferrable constraints
+// %Category: Txn
+// %Text: SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+// %SeeAlso: SET TRANSACTION
+set_constraints_stmt:
+  SET CONSTRAINTS ALL constraints_mode
+  {
+    $$.val = &tree.SetConstraints{Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS name_list constraints_mode
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
+constraints_mode:
+  DEFERRED
+  {
+    $$.val = true
+  }
+| IMMEDIATE
+  {
+    $$.val = false
+  }
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -7601,7 +7989,7 @@ col_qualification_elem:
   {
     $$.val = &tree.ColumnOnUpdate{Expr: $3.expr()}
   }
-| REFERENCES table_name opt_name_parens key_match reference_actions
+| REFERENCES table_name opt_name_parens key_match reference_actions opt_deferrable
   {
     name := $2.unresolvedObjectName().ToTableName()
     $$.val = &tree.ColumnFKConstraint{
@@ -7609,6 +7997,7 @@ col_qualification_elem:
       Col: tree.Name($3),
       Actions: $5.referenceActions(),
       Match: $4.compositeKeyMatchMethod(),
+      Deferrability: $6.constraintDeferrability(),
     }
   }
 | generated_as '(' a_expr ')' STORED
@@ -7731,6 +8120,10 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability() != tree.ConstraintNotDeferrable {
+      sqllex.Error("CHECK constraints cannot be marked DEFERRABLE")
+      return 1
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
@@ -7738,8 +8131,12 @@ constraint_elem:
 | UNIQUE opt_without_index '(' index_params ')'
     opt_storing opt_partition_by_index opt_deferrable opt_where_clause
   {
+    if $8.constraintDeferrability() != tree.ConstraintNotDeferrable && !$2.bool() {
+      return unimplementedWithIssueDetail(sqllex, 31632, "deferrable unique index")
+    }
     $$.val = &tree.UniqueConstraintTableDef{
       WithoutIndex: $2.bool(),
+      Deferrability: $8.constraintDeferrability(),
       IndexTableDef: tree.IndexTableDef{
         Columns: $4.idxElems(),
         Storing: $6.nameList(),
@@ -7769,6 +8166,7 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrability: $11.constraintDeferrability(),
     }
   }
 | EXCLUDE USING error
@@ -7882,12 +8280,30 @@ create_as_col_qualification_elem:
   }
 
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */
+  {
+    $$.val = tree.ConstraintNotDeferrable
+  }
+| DEFERRABLE
+  {
+    $$.val = tree.ConstraintInitiallyImmediate
+  }
+| DEFERRABLE INITIALLY DEFERRED
+  {
+    $$.val = tree.ConstraintInitiallyDeferred
+  }
+| DEFERRABLE INITIALLY IMMEDIATE
+  {
+    $$.val = tree.ConstraintInitiallyImmediate
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.ConstraintInitiallyDeferred
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.ConstraintNotDeferrable
+  }
 
 storing:
   COVERING
@@ -8455,9 +8871,264 @@ opt_view_recursive:
 | RECURSIVE { return unimplemented(sqllex, "create recursive view") }
 
 
+// %Help: CREATE FUNCTION - define a new function
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] FUNCTION <name> ( [ [<argname>] <argtype> [, ...] ] )
+//    RETURNS [SETOF] <rettype>
+//    { LANGUAGE SQL
+//      | { IMMUTABLE | STABLE | VOLATILE }
+//      | [ NOT ] LEAKPROOF
+//      | { CALLED ON NULL INPUT | RETURNS NULL ON NULL INPUT | STRICT }
+//      | AS '<definition>'
+//    } ...
+// %SeeAlso: DROP FUNCTION, ALTER FUNCTION
+create_func_stmt:
+  CREATE opt_or_replace FUNCTION func_create_name '(' opt_func_args_list ')'
+  RETURNS func_return_type opt_create_func_opt_list
+  {
+    $$.val = &tree.CreateFunction{
+      IsReplace: $2.bool(),
+      FuncName: $4.unresolvedObjectName(),
+      Args: $6.funcArgs(),
+      ReturnType: $9.funcReturnType(),
+      Options: $10.functionOptions(),
+    }
+  }
+| CREATE opt_or_replace FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+func_create_name:
+  db_object_name
+
+// %Help: CREATE TRIGGER - define a new trigger
+// %Category: DDL
+// %Text:
+// CREATE TRIGGER <name> { BEFORE | AFTER } <event> [ OR ... ]
+//    ON <tablename> FOR EACH ROW
+//    [ WHEN ( <condition> ) ]
+//    EXECUTE FUNCTION <funcname> ( )
+//
+// Events:
+//    INSERT
+//    UPDATE [ OF <colnames...> ]
+//    DELETE
+// %SeeAlso: DROP TRIGGER, CREATE FUNCTION
+create_trigger_stmt:
+  CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name
+  trigger_for_each opt_trigger_when EXECUTE function_or_procedure db_object_name '(' ')'
+  {
+    name := $7.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      Table: name,
+      When: $9.expr(),
+      FuncName: $12.unresolvedObjectName(),
+    }
+  }
+| CREATE TRIGGER error // SHOW HELP: CREATE TRIGGER
+
+trigger_action_time:
+  BEFORE
+  {
+    $$.val = tree.TriggerActionTimeBefore
+  }
+| AFTER
+  {
+    $$.val = tree.TriggerActionTimeAfter
+  }
+| INSTEAD OF
+  {
+    return unimplementedWithIssueDetail(sqllex, 28296, "instead of")
+  }
+
+trigger_event_list:
+  trigger_event
+  {
+    $$.val = tree.TriggerEvents{$1.triggerEvent()}
+  }
+| trigger_event_list OR trigger_event
+  {
+    $$.val = append($1.triggerEvents(), $3.triggerEvent())
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventInsert}
+  }
+| UPDATE
+  {
+    $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventUpdate}
+  }
+| UPDATE OF name_list
+  {
+    $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventUpdate, Columns: $3.nameList()}
+  }
+| DELETE
+  {
+    $$.val = &tree.TriggerEvent{EventType: tree.TriggerEventDelete}
+  }
+| TRUNCATE
+  {
+    return unimplementedWithIssueDetail(sqllex, 28296, "truncate")
+  }
+
+// Only row-level triggers are supported. Postgres defaults to statement-level
+// triggers when FOR EACH is omitted, so the clause is required here.
+trigger_for_each:
+  FOR opt_each ROW {}
+| FOR opt_each STATEMENT
+  {
+    return unimplementedWithIssueDetail(sqllex, 28296, "statement-level")
+  }
+| /* EMPTY */
+  {
+    return unimplementedWithIssueDetail(sqllex, 28296, "statement-level")
+  }
+
+opt_each:
+  EACH {}
+| /* EMPTY */ {}
+
+opt_trigger_when:
+  WHEN '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+function_or_procedure:
+  FUNCTION {}
+| PROCEDURE {}
+
+opt_func_args_list:
+  func_args_list
+| /* EMPTY */
+  {
+    $$.val = tree.FuncArgs{}
+  }
+
+func_args:
+  '(' func_args_list ')'
+  {
+    $$.val = $2.funcArgs()
+  }
+| '(' ')'
+  {
+    $$.val = tree.FuncArgs{}
+  }
+
+func_args_list:
+  func_arg
+  {
+    $$.val = tree.FuncArgs{$1.funcArg()}
+  }
+| func_args_list ',' func_arg
+  {
+    $$.val = append($1.funcArgs(), $3.funcArg())
+  }
+
+func_arg:
+  param_name func_type
+  {
+    $$.val = tree.FuncArg{Name: tree.Name($1), Type: $2.typeReference()}
+  }
+| func_type
+  {
+    $$.val = tree.FuncArg{Type: $1.typeReference()}
+  }
+
+param_name:
+  type_function_name
+
+func_type:
+  typename
+
+func_return_type:
+  func_type
+  {
+    $$.val = tree.FuncReturnType{Type: $1.typeReference()}
+  }
+| SETOF func_type
+  {
+    $$.val = tree.FuncReturnType{Type: $2.typeReference(), IsSet: true}
+  }
+
+opt_create_func_opt_list:
+  create_func_opt_list
+| /* EMPTY */
+  {
+    $$.val = tree.FunctionOptions{}
+  }
+
+create_func_opt_list:
+  create_func_opt_item
+  {
+    $$.val = tree.FunctionOptions{$1.functionOption()}
+  }
+| create_func_opt_list create_func_opt_item
+  {
+    $$.val = append($1.functionOptions(), $2.functionOption())
+  }
+
+create_func_opt_item:
+  AS func_as
+  {
+    $$.val = tree.FunctionBodyStr($2)
+  }
+| LANGUAGE non_reserved_word_or_sconst
+  {
+    $$.val = tree.FunctionLanguage($2)
+  }
+| common_func_opt_item
+
+common_func_opt_item:
+  CALLED ON NULL INPUT
+  {
+    $$.val = tree.FunctionCalledOnNullInput
+  }
+| RETURNS NULL ON NULL INPUT
+  {
+    $$.val = tree.FunctionReturnsNullOnNullInput
+  }
+| STRICT
+  {
+    $$.val = tree.FunctionStrict
+  }
+| IMMUTABLE
+  {
+    $$.val = tree.FunctionImmutable
+  }
+| STABLE
+  {
+    $$.val = tree.FunctionStable
+  }
+| VOLATILE
+  {
+    $$.val = tree.FunctionVolatile
+  }
+| LEAKPROOF
+  {
+    $$.val = tree.FunctionLeakproof(true)
+  }
+| NOT LEAKPROOF
+  {
+    $$.val = tree.FunctionLeakproof(false)
+  }
+
+func_as:
+  SCONST
+
 // %Help: CREATE TYPE -- create a type
 // %Category: DDL
-// %Text: CREATE TYPE [IF NOT EXISTS] <type_name> AS ENUM (...)
+// %Text:
+// CREATE TYPE [IF NOT EXISTS] <type_name> AS ENUM (...)
+// CREATE TYPE [IF NOT EXISTS] <type_name> AS (<attr_name> <type> [, ...])
 create_type_stmt:
   // Enum types.
   CREATE TYPE type_name AS ENUM '(' opt_enum_val_list ')'
@@ -8477,17 +9148,55 @@ create_type_stmt:
       IfNotExists: true,
     }
   }
-| CREATE TYPE error // SHOW HELP: CREATE TYPE
   // Record/Composite types.
-| CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
+| CREATE TYPE type_name AS '(' opt_composite_type_list ')'
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Composite,
+      CompositeTypeList: $6.compositeTypeList(),
+    }
+  }
+| CREATE TYPE IF NOT EXISTS type_name AS '(' opt_composite_type_list ')'
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $6.unresolvedObjectName(),
+      Variety: tree.Composite,
+      CompositeTypeList: $9.compositeTypeList(),
+      IfNotExists: true,
+    }
+  }
+| CREATE TYPE error // SHOW HELP: CREATE TYPE
   // Range types.
 | CREATE TYPE type_name AS RANGE error    { return unimplementedWithIssue(sqllex, 27791) }
   // Base (primitive) types.
 | CREATE TYPE type_name '(' error         { return unimplementedWithIssueDetail(sqllex, 27793, "base") }
   // Shell types, gateway to define base types using the previous syntax.
 | CREATE TYPE type_name                   { return unimplementedWithIssueDetail(sqllex, 27793, "shell") }
-  // Domain types.
-| CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
+
+// %Help: CREATE DOMAIN - define a new domain
+// %Category: DDL
+// %Text:
+// CREATE DOMAIN <type_name> [AS] <type>
+//    [DEFAULT <expr>]
+//    [[CONSTRAINT <constraint_name>] {NOT NULL | NULL | CHECK (<expr>)} ...]
+//
+// The CHECK constraints refer to the value being checked as VALUE.
+// %SeeAlso: DROP DOMAIN, CREATE TYPE
+create_domain_stmt:
+  CREATE DOMAIN type_name opt_as_domain typename col_qual_list
+  {
+    n, err := tree.NewCreateDomain($3.unresolvedObjectName(), $5.typeReference(), $6.colQuals())
+    if err != nil {
+      return setErr(sqllex, err)
+    }
+    $$.val = n
+  }
+| CREATE DOMAIN error // SHOW HELP: CREATE DOMAIN
+
+opt_as_domain:
+  AS {}
+| /* EMPTY */ {}
 
 opt_enum_val_list:
   enum_val_list
@@ -8509,6 +9218,26 @@ enum_val_list:
     $$.val = append($1.enumValueList(), tree.EnumValue($3))
   }
 
+opt_composite_type_list:
+  composite_type_list
+  {
+    $$.val = $1.compositeTypeList()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.CompositeTypeList(nil)
+  }
+
+composite_type_list:
+  name typename
+  {
+    $$.val = tree.CompositeTypeList{{Label: tree.Name($1), Type: $2.typeReference()}}
+  }
+| composite_type_list ',' name typename
+  {
+    $$.val = append($1.compositeTypeList(), tree.CompositeTypeElem{Label: tree.Name($3), Type: $4.typeReference()})
+  }
+
 // %Help: CREATE INDEX - create a new index
 // %Category: DDL
 // %Text:
@@ -9841,9 +10570,8 @@ set_clause_list:
     $$.val = append($1.updateExprs(), $3.updateExpr())
   }
 
-// TODO(knz): The LHS in these can be extended to support
-// a path to a field member when compound types are supported.
-// Keep it simple for now.
+// The LHS of a single_set_clause can be a field of a column of a
+// composite type. Deeper paths and array subscripts are not supported.
 set_clause:
   single_set_clause
 | multiple_set_clause
@@ -9853,7 +10581,10 @@ single_set_clause:
   {
     $$.val = &tree.UpdateExpr{Names: tree.NameList{tree.Name($1)}, Expr: $3.expr()}
   }
-| column_name '.' error { return unimplementedWithIssue(sqllex, 27792) }
+| column_name '.' name '=' a_expr
+  {
+    $$.val = &tree.UpdateExpr{Names: tree.NameList{tree.Name($1)}, Field: tree.Name($3), Expr: $5.expr()}
+  }
 
 multiple_set_clause:
   '(' insert_column_list ')' '=' in_expr
@@ -9861,6 +10592,95 @@ multiple_set_clause:
     $$.val = &tree.UpdateExpr{Tuple: true, Names: $2.nameList(), Expr: $5.expr()}
   }
 
+// %Help: MERGE - insert, update or delete rows of a table based on a join
+// %Category: DML
+// %Text:
+// MERGE INTO <tablename> [[AS] <name>]
+//        USING <source> ON <expr>
+//        WHEN MATCHED [AND <expr>] THEN
+//          { UPDATE SET ... | DELETE | DO NOTHING }
+//        WHEN NOT MATCHED [AND <expr>] THEN
+//          { INSERT [( <colnames...> )] { VALUES ( <exprs...> ) | DEFAULT VALUES } | DO NOTHING }
+//        [...]
+// %SeeAlso: INSERT, UPSERT, UPDATE, DELETE
+merge_stmt:
+  opt_with_clause MERGE INTO table_expr_opt_alias_idx USING table_ref ON a_expr merge_when_list
+  {
+    $$.val = &tree.Merge{
+      With: $1.with(),
+      Table: $4.tblExpr(),
+      Source: $6.tblExpr(),
+      On: $8.expr(),
+      Whens: $9.mergeWhens(),
+    }
+  }
+| opt_with_clause MERGE error // SHOW HELP: MERGE
+
+merge_when_list:
+  merge_when_clause
+  {
+    $$.val = tree.MergeWhens{$1.mergeWhen()}
+  }
+| merge_when_list merge_when_clause
+  {
+    $$.val = append($1.mergeWhens(), $2.mergeWhen())
+  }
+
+merge_when_clause:
+  WHEN MATCHED opt_merge_cond THEN merge_matched_action
+  {
+    $$.val = $5.mergeWhen()
+    $$.val.(*tree.MergeWhen).Matched = true
+    $$.val.(*tree.MergeWhen).Cond = $3.expr()
+  }
+| WHEN NOT MATCHED opt_merge_cond THEN merge_not_matched_action
+  {
+    $$.val = $6.mergeWhen()
+    $$.val.(*tree.MergeWhen).Cond = $4.expr()
+  }
+
+opt_merge_cond:
+  AND a_expr
+  {
+    $$.val = $2.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+merge_matched_action:
+  UPDATE SET set_clause_list
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionUpdate, Exprs: $3.updateExprs()}
+  }
+| DELETE
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionDelete}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionDoNothing}
+  }
+
+merge_not_matched_action:
+  INSERT VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionInsert, Values: $4.exprs()}
+  }
+| INSERT '(' insert_column_list ')' VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionInsert, Columns: $3.nameList(), Values: $7.exprs()}
+  }
+| INSERT DEFAULT VALUES
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionInsert}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeWhen{Action: tree.MergeActionDoNothing}
+  }
+
 // %Help: REASSIGN OWNED BY - change ownership of all objects
 // %Category: Priv
 // %Text: REASSIGN OWNED BY {<name> | CURRENT_USER | SESSION_USER}[,...]
@@ -10504,9 +11324,18 @@ group_by_list:
 // rather than reducing the conflicting unreserved_keyword rule.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.RollupGroupingSet, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.CubeGroupingSet, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.ExplicitGroupingSets, Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -12437,7 +13266,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction($1), Exprs: $3.exprs()}
+  }
 
 func_application:
   func_name '(' ')'
@@ -14000,6 +14832,7 @@ unreserved_keyword:
 | BUNDLE
 | BY
 | CACHE
+| CALLED
 | CANCEL
 | CANCELQUERY
 | CASCADE
@@ -14051,6 +14884,7 @@ unreserved_keyword:
 | DOMAIN
 | DOUBLE
 | DROP
+| EACH
 | ENCODING
 | ENCRYPTED
 | ENCRYPTION_PASSPHRASE
@@ -14102,6 +14936,7 @@ unreserved_keyword:
 | HOUR
 | IDENTITY
 | IMMEDIATE
+| IMMUTABLE
 | IMPORT
 | INCLUDE
 | INCLUDING
@@ -14111,7 +14946,9 @@ unreserved_keyword:
 | INDEXES
 | INHERITS
 | INJECT
+| INPUT
 | INSERT
+| INSTEAD
 | INTO_DB
 | INVERTED
 | ISOLATION
@@ -14127,6 +14964,7 @@ unreserved_keyword:
 | LATEST
 | LC_COLLATE
 | LC_CTYPE
+| LEAKPROOF
 | LEASE
 | LESS
 | LEVEL
@@ -14135,6 +14973,7 @@ unreserved_keyword:
 | LINESTRINGZ
 | LINESTRINGZM
 | LIST
+| LISTEN
 | LOCAL
 | LOCKED
 | LOGIN
@@ -14142,6 +14981,7 @@ unreserved_keyword:
 | LOOKUP
 | LOW
 | MATCH
+| MATCHED
 | MATERIALIZED
 | MAXVALUE
 | MERGE
@@ -14168,6 +15008,7 @@ unreserved_keyword:
 | NEVER
 | NEW_DB_NAME
 | NEW_KMS
+| NEW_TABLE_NAME
 | NEXT
 | NO
 | NORMAL
@@ -14187,6 +15028,7 @@ unreserved_keyword:
 | NOVIEWACTIVITY
 | NOVIEWACTIVITYREDACTED
 | NOVIEWCLUSTERSETTING
+| NOTIFY
 | NOWAIT
 | NULLS
 | IGNORE_FOREIGN_KEYS
@@ -14227,6 +15069,7 @@ unreserved_keyword:
 | PRIOR
 | PRIORITY
 | PRIVILEGES
+| PROCEDURE
 | PUBLIC
 | PUBLICATION
 | QUERIES
@@ -14258,6 +15101,7 @@ unreserved_keyword:
 | RESTRICTED
 | RESUME
 | RETRY
+| RETURNS
 | REVISION_HISTORY
 | REVOKE
 | ROLE
@@ -14278,6 +15122,7 @@ unreserved_keyword:
 | SCANS
 | SCATTER
 | SCHEMA
+| SCHEMA_ONLY
 | SCHEMAS
 | SCRUB
 | SEARCH
@@ -14303,8 +15148,10 @@ unreserved_keyword:
 | SPLIT
 | SQL
 | SQLLOGIN
+| STABLE
 | START
 | STATE
+| STATEMENT
 | STATEMENTS
 | STATISTICS
 | STDIN
@@ -14342,6 +15189,7 @@ unreserved_keyword:
 | UNBOUNDED
 | UNCOMMITTED
 | UNKNOWN
+| UNLISTEN
 | UNLOGGED
 | UNSET
 | UNSPLIT
@@ -14359,6 +15207,7 @@ unreserved_keyword:
 | VIEWACTIVITYREDACTED
 | VIEWCLUSTERSETTING
 | VISIBLE
+| VOLATILE
 | VOTERS
 | WITHIN
 | WITHOUT
@@ -14414,6 +15263,7 @@ col_name_keyword:
 | PRECISION
 | REAL
 | ROW
+| SETOF
 | SMALLINT
 | STRING
 | SUBSTRING
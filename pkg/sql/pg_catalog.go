@@ -29,6 +29,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -933,6 +934,10 @@ func populateTableConstraints(
 				}
 				f.WriteString(strings.Join(colNames, ", "))
 				f.WriteByte(')')
+				if d := con.UniqueWithoutIndexConstraint.Deferrability; d != catpb.ConstraintDeferrability_NOT_DEFERRABLE {
+					f.WriteByte(' ')
+					f.WriteString(d.String())
+				}
 				if con.UniqueWithoutIndexConstraint.Validity != descpb.ConstraintValidity_Validated {
 					f.WriteString(" NOT VALID")
 				}
@@ -969,13 +974,17 @@ func populateTableConstraints(
 			condef = tree.NewDString(fmt.Sprintf("CHECK ((%s))%s", displayExpr, validity))
 		}
 
+		deferrability := con.Deferrability()
+		condeferrable := tree.MakeDBool(deferrability != catpb.ConstraintDeferrability_NOT_DEFERRABLE)
+		condeferred := tree.MakeDBool(deferrability == catpb.ConstraintDeferrability_INITIALLY_DEFERRED)
+
 		if err := addRow(
 			oid,                  // oid
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -1571,6 +1580,9 @@ https://www.postgresql.org/docs/9.5/catalog-pg-description.html`,
 				objID = getOIDFromConstraint(constraint, dbContext.GetID(), schema.GetName(), tableDesc)
 				objSubID = tree.DZero
 				classOid = tree.NewDOid(catconstants.PgCatalogConstraintTableID)
+			case keys.FunctionCommentType:
+				objID = tree.NewDOid(tree.DInt(funcdesc.FuncIDToOID(descpb.ID(tree.MustBeDInt(objID)))))
+				classOid = tree.NewDOid(catconstants.PgCatalogProcTableID)
 			case keys.IndexCommentType:
 				objID = makeOidHasher().IndexOid(
 					descpb.ID(tree.MustBeDInt(objID)),
@@ -2743,7 +2755,6 @@ var (
 	typTypeRange     = tree.NewDString("r")
 
 	// Avoid unused warning for constants.
-	_ = typTypeDomain
 	_ = typTypePseudo
 	_ = typTypeRange
 
@@ -2868,26 +2879,48 @@ func addPGTypeRow(
 		builtinPrefix = "enum_"
 		typType = typTypeEnum
 	}
+	if typ.IsComposite() {
+		builtinPrefix = "record_"
+		typType = typTypeComposite
+		cat = typCategoryComposite
+	}
 	if cat == typCategoryPseudo {
 		typType = typTypePseudo
 	}
+	typOid := tree.NewDOid(tree.DInt(typ.Oid()))
 	typname := typ.PGName()
+	typNotNull := tree.DBoolFalse
+	typBaseType := oidZero
+	typDefault := tree.DNull
+	if typ.IsDomain() {
+		// A domain has the OID of its base type, and no array type.
+		domain := typ.TypeMeta.DomainData
+		typType = typTypeDomain
+		typArray = oidZero
+		typBaseType = typOid
+		typOid = tree.NewDOid(tree.DInt(domain.TypeOID))
+		typname = typ.TypeMeta.Name.Basename()
+		typNotNull = tree.MakeDBool(tree.DBool(domain.NotNull))
+		if domain.DefaultExpr != nil {
+			typDefault = tree.NewDString(*domain.DefaultExpr)
+		}
+	}
 
 	return addRow(
-		tree.NewDOid(tree.DInt(typ.Oid())), // oid
-		tree.NewDName(typname),             // typname
-		nspOid,                             // typnamespace
-		owner,                              // typowner
-		typLen(typ),                        // typlen
-		typByVal(typ),                      // typbyval (is it fixedlen or not)
-		typType,                            // typtype
-		cat,                                // typcategory
-		tree.DBoolFalse,                    // typispreferred
-		tree.DBoolTrue,                     // typisdefined
-		typDelim,                           // typdelim
-		oidZero,                            // typrelid
-		typElem,                            // typelem
-		typArray,                           // typarray
+		typOid,                 // oid
+		tree.NewDName(typname), // typname
+		nspOid,                 // typnamespace
+		owner,                  // typowner
+		typLen(typ),            // typlen
+		typByVal(typ),          // typbyval (is it fixedlen or not)
+		typType,                // typtype
+		cat,                    // typcategory
+		tree.DBoolFalse,        // typispreferred
+		tree.DBoolTrue,         // typisdefined
+		typDelim,               // typdelim
+		oidZero,                // typrelid
+		typElem,                // typelem
+		typArray,               // typarray
 
 		// regproc references
 		h.RegProc(builtinPrefix+"in"),   // typinput
@@ -2900,13 +2933,13 @@ func addPGTypeRow(
 
 		tree.DNull,      // typalign
 		tree.DNull,      // typstorage
-		tree.DBoolFalse, // typnotnull
-		oidZero,         // typbasetype
+		typNotNull,      // typnotnull
+		typBaseType,     // typbasetype
 		negOneVal,       // typtypmod
 		zeroVal,         // typndims
 		typColl(typ, h), // typcollation
 		tree.DNull,      // typdefaultbin
-		tree.DNull,      // typdefault
+		typDefault,      // typdefault
 		tree.DNull,      // typacl
 	)
 }
@@ -3993,6 +4026,10 @@ var pgCatalogAvailableExtensionVersionsTable = virtualSchemaTable{
 // object identifiers for types are not arbitrary, but instead need to be kept in
 // sync with Postgres.
 func typOid(typ *types.T) tree.Datum {
+	if typ.IsDomain() {
+		// Domains have the OID of their base type.
+		return tree.NewDOid(tree.DInt(typ.TypeMeta.DomainData.TypeOID))
+	}
 	return tree.NewDOid(tree.DInt(typ.Oid()))
 }
 
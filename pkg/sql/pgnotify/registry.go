@@ -0,0 +1,348 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgnotify
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+)
+
+// Retention is the amount of time for which notifications are kept in
+// system.notifications. Notifications are delivered through a rangefeed on
+// that table, so this only needs to cover the time it takes for the rangefeeds
+// of all the nodes to observe a new row.
+var Retention = settings.RegisterDurationSetting(
+	settings.TenantWritable,
+	"sql.notifications.retention",
+	"the amount of time for which notifications sent with NOTIFY are kept in system.notifications",
+	time.Minute,
+	settings.PositiveDuration,
+)
+
+// maxPendingNotifications bounds the number of notifications queued for a
+// listener that does not consume them, for example because its session is in
+// the middle of a long-running transaction. Once the bound is reached, the
+// oldest notifications are discarded.
+const maxPendingNotifications = 10000
+
+// Notification is an asynchronous notification sent with NOTIFY or
+// pg_notify().
+type Notification struct {
+	// Channel is the name of the channel the notification was sent on.
+	Channel string
+	// Payload is the (possibly empty) payload of the notification.
+	Payload string
+	// PID is the backend process ID of the session that sent the notification.
+	PID int32
+}
+
+// Registry delivers the notifications sent anywhere in the cluster to the
+// sessions on this node that are listening on their channel.
+//
+// NOTIFY writes the notification to system.notifications as part of the
+// notifying transaction, so that it is only sent if the transaction commits.
+// Every node runs a rangefeed on that table and dispatches the new rows to the
+// Listeners of the matching channel. Old rows are periodically deleted.
+//
+// Notifications are delivered at least once: a rangefeed restart can deliver
+// a notification again. Unlike in Postgres, notifications sent by different
+// transactions are not guaranteed to be delivered in commit order.
+type Registry struct {
+	codec    keys.SQLCodec
+	clock    *hlc.Clock
+	f        *rangefeed.Factory
+	stopper  *stop.Stopper
+	settings *cluster.Settings
+	ie       sqlutil.InternalExecutor
+
+	// dec is only used by the rangefeed callback, which is never invoked
+	// concurrently.
+	dec rowDecoder
+
+	mu struct {
+		syncutil.Mutex
+		// listeners maps each channel to the set of listeners registered on it.
+		listeners map[string]map[*Listener]struct{}
+	}
+}
+
+// NewRegistry constructs a new Registry.
+func NewRegistry(
+	codec keys.SQLCodec,
+	clock *hlc.Clock,
+	f *rangefeed.Factory,
+	stopper *stop.Stopper,
+	settings *cluster.Settings,
+	ie sqlutil.InternalExecutor,
+) *Registry {
+	r := &Registry{
+		codec:    codec,
+		clock:    clock,
+		f:        f,
+		stopper:  stopper,
+		settings: settings,
+		ie:       ie,
+		dec:      makeRowDecoder(),
+	}
+	r.mu.listeners = make(map[string]map[*Listener]struct{})
+	return r
+}
+
+// Start starts the delivery of notifications and the deletion of expired
+// notifications. If the cluster version does not support
+// system.notifications yet, this is deferred until it does.
+func (r *Registry) Start(ctx context.Context, sysTableResolver catalog.SystemTableIDResolver) error {
+	versionOkCh := make(chan struct{})
+	var once sync.Once
+	versionOk := func() { once.Do(func() { close(versionOkCh) }) }
+	r.settings.Version.SetOnChange(func(ctx context.Context, newVersion clusterversion.ClusterVersion) {
+		if newVersion.IsActive(clusterversion.NotificationsTable) {
+			versionOk()
+		}
+	})
+	// Check the version again, in case it changed just before SetOnChange.
+	if r.settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		versionOk()
+	}
+	return r.stopper.RunAsyncTask(ctx, "pgnotify-registry", func(ctx context.Context) {
+		select {
+		case <-versionOkCh:
+		case <-r.stopper.ShouldQuiesce():
+			return
+		}
+		tableID, err := sysTableResolver.LookupSystemTableID(ctx, systemschema.NotificationsTable.GetName())
+		if err != nil {
+			log.Warningf(ctx, "failed to look up system.notifications, notifications will not be delivered: %v", err)
+			return
+		}
+		rf, err := r.startRangeFeed(ctx, tableID)
+		if err != nil {
+			log.Warningf(ctx, "failed to start the notifications rangefeed, notifications will not be delivered: %v", err)
+			return
+		}
+		r.stopper.AddCloser(rf)
+		r.deleteExpiredLoop(ctx)
+	})
+}
+
+// startRangeFeed starts the rangefeed over system.notifications which
+// dispatches the new notifications to the listeners on this node.
+func (r *Registry) startRangeFeed(
+	ctx context.Context, tableID descpb.ID,
+) (*rangefeed.RangeFeed, error) {
+	tablePrefix := r.codec.TablePrefix(uint32(tableID))
+	tableSpan := roachpb.Span{
+		Key:    tablePrefix,
+		EndKey: tablePrefix.PrefixEnd(),
+	}
+	onValue := func(ctx context.Context, kv *roachpb.RangeFeedValue) {
+		n, tombstone, err := r.dec.decodeRow(kv.Value)
+		if err != nil {
+			log.Warningf(ctx, "failed to decode notification %v: %v", kv.Key, err)
+			return
+		}
+		if tombstone {
+			// Expired notifications being deleted.
+			return
+		}
+		r.dispatch(n)
+	}
+	return r.f.RangeFeed(ctx,
+		"pgnotify",
+		[]roachpb.Span{tableSpan},
+		r.clock.Now(),
+		onValue,
+	)
+}
+
+// dispatch queues the notification on every listener of its channel.
+func (r *Registry) dispatch(n Notification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for l := range r.mu.listeners[n.Channel] {
+		l.enqueue(n)
+	}
+}
+
+// deleteExpiredLoop periodically deletes the notifications that are older than
+// the Retention.
+func (r *Registry) deleteExpiredLoop(ctx context.Context) {
+	ctx, cancel := r.stopper.WithCancelOnQuiesce(ctx)
+	defer cancel()
+	var t timeutil.Timer
+	defer t.Stop()
+	t.Reset(Retention.Get(&r.settings.SV))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			t.Read = true
+			if err := r.deleteExpired(ctx); err != nil {
+				log.Warningf(ctx, "failed to delete expired notifications: %v", err)
+			}
+			t.Reset(Retention.Get(&r.settings.SV))
+		}
+	}
+}
+
+func (r *Registry) deleteExpired(ctx context.Context) error {
+	ctx = multitenant.WithTenantCostControlExemption(ctx)
+	cutoff := r.clock.PhysicalTime().Add(-Retention.Get(&r.settings.SV))
+	const batchSize = 1000
+	for {
+		deleted, err := r.ie.ExecEx(ctx, "delete-expired-notifications", nil, /* txn */
+			sessiondata.NodeUserSessionDataOverride,
+			`DELETE FROM system.notifications WHERE created_at < $1 LIMIT $2`,
+			cutoff, batchSize,
+		)
+		if err != nil || deleted < batchSize {
+			return err
+		}
+	}
+}
+
+// NewListener creates a new Listener which is initially not listening on any
+// channel. It must be closed once it is no longer used.
+func (r *Registry) NewListener() *Listener {
+	l := &Listener{
+		r:        r,
+		notifyCh: make(chan struct{}, 1),
+	}
+	l.mu.channels = make(map[string]struct{})
+	return l
+}
+
+// Listener receives the notifications sent on the channels that a session
+// listens on.
+type Listener struct {
+	r *Registry
+
+	// notifyCh is signaled when notifications are queued.
+	notifyCh chan struct{}
+
+	mu struct {
+		syncutil.Mutex
+		channels map[string]struct{}
+		pending  []Notification
+	}
+}
+
+// Listen registers the listener on the given channel. It is a no-op if the
+// listener is already registered on it.
+func (l *Listener) Listen(channel string) {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.mu.channels[channel]; ok {
+		return
+	}
+	l.mu.channels[channel] = struct{}{}
+	listeners, ok := l.r.mu.listeners[channel]
+	if !ok {
+		listeners = make(map[*Listener]struct{})
+		l.r.mu.listeners[channel] = listeners
+	}
+	listeners[l] = struct{}{}
+}
+
+// Unlisten unregisters the listener from the given channel. It is a no-op if
+// the listener is not registered on it.
+func (l *Listener) Unlisten(channel string) {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.unlistenLocked(channel)
+}
+
+// UnlistenAll unregisters the listener from all the channels.
+func (l *Listener) UnlistenAll() {
+	l.r.mu.Lock()
+	defer l.r.mu.Unlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for channel := range l.mu.channels {
+		l.unlistenLocked(channel)
+	}
+}
+
+// unlistenLocked requires both l.r.mu and l.mu to be held.
+func (l *Listener) unlistenLocked(channel string) {
+	if _, ok := l.mu.channels[channel]; !ok {
+		return
+	}
+	delete(l.mu.channels, channel)
+	listeners := l.r.mu.listeners[channel]
+	delete(listeners, l)
+	if len(listeners) == 0 {
+		delete(l.r.mu.listeners, channel)
+	}
+}
+
+// Close unregisters the listener from all the channels and discards the
+// pending notifications.
+func (l *Listener) Close() {
+	l.UnlistenAll()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.mu.pending = nil
+}
+
+// NotifyCh returns a channel which is signaled when new notifications are
+// queued on the listener. The notifications are retrieved with Drain.
+func (l *Listener) NotifyCh() <-chan struct{} {
+	return l.notifyCh
+}
+
+// Drain returns the queued notifications and empties the queue.
+func (l *Listener) Drain() []Notification {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	pending := l.mu.pending
+	l.mu.pending = nil
+	return pending
+}
+
+// enqueue requires l.r.mu to be held, which guarantees that the listener is
+// still registered on the notification's channel.
+func (l *Listener) enqueue(n Notification) {
+	l.mu.Lock()
+	if len(l.mu.pending) >= maxPendingNotifications {
+		l.mu.pending = l.mu.pending[1:]
+	}
+	l.mu.pending = append(l.mu.pending, n)
+	l.mu.Unlock()
+	select {
+	case l.notifyCh <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgnotify
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenerDispatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	r := NewRegistry(keys.SystemSQLCodec, nil, nil, nil, nil, nil)
+	a, b := r.NewListener(), r.NewListener()
+	defer a.Close()
+	defer b.Close()
+
+	a.Listen("foo")
+	a.Listen("foo")
+	b.Listen("foo")
+	b.Listen("bar")
+
+	r.dispatch(Notification{Channel: "foo", Payload: "1", PID: 7})
+	r.dispatch(Notification{Channel: "bar", Payload: "2", PID: 7})
+	r.dispatch(Notification{Channel: "baz", Payload: "3", PID: 7})
+
+	select {
+	case <-a.NotifyCh():
+	default:
+		t.Fatal("expected listener to be signaled")
+	}
+	require.Equal(t, []Notification{{Channel: "foo", Payload: "1", PID: 7}}, a.Drain())
+	require.Equal(t, []Notification{
+		{Channel: "foo", Payload: "1", PID: 7},
+		{Channel: "bar", Payload: "2", PID: 7},
+	}, b.Drain())
+	require.Empty(t, a.Drain())
+
+	b.Unlisten("foo")
+	r.dispatch(Notification{Channel: "foo", Payload: "4"})
+	require.Len(t, a.Drain(), 1)
+	require.Empty(t, b.Drain())
+
+	a.UnlistenAll()
+	b.UnlistenAll()
+	r.dispatch(Notification{Channel: "foo", Payload: "5"})
+	r.dispatch(Notification{Channel: "bar", Payload: "6"})
+	require.Empty(t, a.Drain())
+	require.Empty(t, b.Drain())
+	require.Empty(t, r.mu.listeners)
+}
+
+func TestListenerPendingBound(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	r := NewRegistry(keys.SystemSQLCodec, nil, nil, nil, nil, nil)
+	l := r.NewListener()
+	defer l.Close()
+	l.Listen("foo")
+	for i := 0; i < maxPendingNotifications+10; i++ {
+		r.dispatch(Notification{Channel: "foo", PID: int32(i)})
+	}
+	pending := l.Drain()
+	require.Len(t, pending, maxPendingNotifications)
+	// The oldest notifications are the ones that are discarded.
+	require.Equal(t, int32(10), pending[0].PID)
+}
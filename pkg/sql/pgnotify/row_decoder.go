@@ -0,0 +1,66 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgnotify
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc/valueside"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// Column ordinals of system.notifications.
+const (
+	channelColIdx = 1
+	payloadColIdx = 2
+	pidColIdx     = 3
+)
+
+// rowDecoder decodes rows from the notifications table.
+type rowDecoder struct {
+	alloc   tree.DatumAlloc
+	decoder valueside.Decoder
+}
+
+func makeRowDecoder() rowDecoder {
+	return rowDecoder{
+		decoder: valueside.MakeDecoder(systemschema.NotificationsTable.PublicColumns()),
+	}
+}
+
+// decodeRow decodes the value of a row of the system.notifications table. If
+// the value is not present, the tombstone bool is set. The key is not decoded,
+// since the notification ID is not needed to deliver the notification.
+func (d *rowDecoder) decodeRow(value roachpb.Value) (_ Notification, tombstone bool, _ error) {
+	if !value.IsPresent() {
+		return Notification{}, true, nil
+	}
+	// All the non-key columns are stored in the single family.
+	bytes, err := value.GetTuple()
+	if err != nil {
+		return Notification{}, false, err
+	}
+	datums, err := d.decoder.Decode(&d.alloc, bytes)
+	if err != nil {
+		return Notification{}, false, err
+	}
+	for _, idx := range []int{channelColIdx, payloadColIdx, pidColIdx} {
+		if datums[idx] == nil || datums[idx] == tree.DNull {
+			return Notification{}, false, errors.AssertionFailedf("missing value for column %d", idx)
+		}
+	}
+	return Notification{
+		Channel: string(tree.MustBeDString(datums[channelColIdx])),
+		Payload: string(tree.MustBeDString(datums[payloadColIdx])),
+		PID:     int32(tree.MustBeDInt(datums[pidColIdx])),
+	}, false, nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgnotify"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
@@ -43,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/netutil"
 	"github.com/cockroachdb/cockroach/pkg/util/ring"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
@@ -96,6 +98,30 @@ type conn struct {
 		// network connection.
 		buf    bytes.Buffer
 		tagBuf [64]byte
+		// readyForQueryIdle is set when an idle ReadyForQuery message has been
+		// buffered, and reset by the next Flush.
+		readyForQueryIdle bool
+	}
+
+	// notifications receives the notifications for the channels that the
+	// session listens on. It is nil if the server does not support them.
+	notifications *pgnotify.Listener
+
+	// notifyState coordinates the asynchronous delivery of notifications with
+	// the writes done by the command processor.
+	notifyState struct {
+		// Mutex serializes writes to conn between Flush and
+		// deliverNotifications.
+		syncutil.Mutex
+		// idle is set once a ReadyForQuery message indicating that the session
+		// is not in a transaction has been sent, and reset when the next message
+		// is read from the client. Notifications are only sent asynchronously
+		// while the session is idle, like Postgres does.
+		idle syncutil.AtomicBool
+		// msgBuilder is used by deliverNotifications to build messages.
+		msgBuilder writeBuffer
+		// buf is used by deliverNotifications to buffer messages.
+		buf bytes.Buffer
 	}
 
 	readBuf    pgwirebase.ReadBuffer
@@ -201,6 +227,7 @@ func newConn(
 	c.writerState.fi.buf = &c.writerState.buf
 	c.writerState.fi.lastFlushed = -1
 	c.msgBuilder.init(metrics.BytesOutCount)
+	c.notifyState.msgBuilder.init(metrics.BytesOutCount)
 
 	return c
 }
@@ -399,6 +426,9 @@ func (c *conn) serveImpl(
 		breakLoop, isSimpleQuery, err := func() (bool, bool, error) {
 			typ, n, err := c.readBuf.ReadTypedMsg(&c.rd)
 			c.metrics.BytesInCount.Inc(int64(n))
+			// The client is sending a new command, so the session is no longer
+			// idle.
+			c.notifyState.idle.Set(false)
 			if err == nil && c.afterReadMsgTestingKnob != nil {
 				err = c.afterReadMsgTestingKnob(ctx)
 			}
@@ -707,6 +737,13 @@ func (c *conn) processCommandsAsync(
 			return
 		}
 
+		if reg := sqlServer.GetExecutorConfig().NotificationRegistry; reg != nil {
+			c.notifications = reg.NewListener()
+			defer c.notifications.Close()
+			c.sessionArgs.Notifications = c.notifications
+			go c.deliverNotifications(ctx)
+		}
+
 		// Inform the client of the default session settings.
 		connHandler, retErr = c.sendInitialConnData(ctx, sqlServer, onDefaultIntSizeChange)
 		if retErr != nil {
@@ -1399,6 +1436,16 @@ func (c *conn) bufferBatch(
 }
 
 func (c *conn) bufferReadyForQuery(txnStatus byte) {
+	if txnStatus == byte(sql.IdleTxnBlock) && c.notifications != nil {
+		// Like Postgres, send the notifications received while the session was
+		// busy right before it becomes idle.
+		for _, n := range c.notifications.Drain() {
+			if err := writeNotification(&c.msgBuilder, n, &c.writerState.buf); err != nil {
+				panic(errors.NewAssertionErrorWithWrappedErrf(err, "unexpected err from buffer"))
+			}
+		}
+		c.writerState.readyForQueryIdle = true
+	}
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgReady)
 	c.msgBuilder.writeByte(txnStatus)
 	if err := c.msgBuilder.finishMsg(&c.writerState.buf); err != nil {
@@ -1589,14 +1636,79 @@ func (c *conn) Flush(pos sql.CmdPos) error {
 	// Make sure that the entire cmdStarts buffer is drained.
 	c.writerState.fi.cmdStarts.clear()
 
+	c.notifyState.Lock()
+	defer c.notifyState.Unlock()
+	becomesIdle := c.writerState.readyForQueryIdle
+	c.writerState.readyForQueryIdle = false
 	_ /* n */, err := c.writerState.buf.WriteTo(c.conn)
 	if err != nil {
 		c.setErr(err)
 		return err
 	}
+	if becomesIdle {
+		c.notifyState.idle.Set(true)
+		// Send the notifications that arrived after the ReadyForQuery message
+		// was buffered; deliverNotifications may have skipped them while the
+		// session was not idle yet.
+		if err := c.sendPendingNotificationsLocked(); err != nil {
+			c.setErr(err)
+			return err
+		}
+	}
 	return nil
 }
 
+// deliverNotifications sends the notifications received by c.notifications to
+// the client whenever the session is idle. It returns when ctx is canceled or
+// when the connection fails.
+//
+// Notifications received while the session is not idle stay queued on the
+// listener; they are sent right before the next idle ReadyForQuery message.
+func (c *conn) deliverNotifications(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.notifications.NotifyCh():
+		}
+		if err := func() error {
+			c.notifyState.Lock()
+			defer c.notifyState.Unlock()
+			if !c.notifyState.idle.Get() {
+				return nil
+			}
+			return c.sendPendingNotificationsLocked()
+		}(); err != nil {
+			c.setErr(err)
+			return
+		}
+	}
+}
+
+// sendPendingNotificationsLocked writes the queued notifications to the
+// network connection. c.notifyState must be locked.
+func (c *conn) sendPendingNotificationsLocked() error {
+	if err := c.GetErr(); err != nil {
+		return err
+	}
+	for _, n := range c.notifications.Drain() {
+		if err := writeNotification(&c.notifyState.msgBuilder, n, &c.notifyState.buf); err != nil {
+			return err
+		}
+	}
+	_ /* n */, err := c.notifyState.buf.WriteTo(c.conn)
+	return err
+}
+
+// writeNotification writes a NotificationResponse message to w.
+func writeNotification(b *writeBuffer, n pgnotify.Notification, w io.Writer) error {
+	b.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	b.putInt32(n.PID)
+	b.writeTerminatedString(n.Channel)
+	b.writeTerminatedString(n.Payload)
+	return b.finishMsg(w)
+}
+
 // maybeFlush flushes the buffer to the network connection if it exceeded
 // sessionArgs.ConnResultsBufferSize.
 func (c *conn) maybeFlush(pos sql.CmdPos) (bool, error) {
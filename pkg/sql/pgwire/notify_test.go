@@ -0,0 +1,93 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListenNotify checks that notifications sent on one node are delivered
+// to the listening sessions of another node, and only once the sending
+// transaction commits.
+func TestListenNotify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := serverutils.StartNewTestCluster(t, 2, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+
+	connect := func(idx int) *pgx.Conn {
+		pgURL, cleanup := sqlutils.PGUrl(
+			t, tc.Server(idx).ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+		defer cleanup()
+		conn, err := pgx.Connect(ctx, pgURL.String())
+		require.NoError(t, err)
+		return conn
+	}
+	listener := connect(0)
+	defer func() { _ = listener.Close(ctx) }()
+	sender := connect(1)
+	defer func() { _ = sender.Close(ctx) }()
+
+	waitForNotification := func() (channel, payload string, pid uint32) {
+		ctx, cancel := context.WithTimeout(ctx, 45*time.Second)
+		defer cancel()
+		n, err := listener.WaitForNotification(ctx)
+		require.NoError(t, err)
+		return n.Channel, n.Payload, n.PID
+	}
+
+	_, err := listener.Exec(ctx, "LISTEN foo")
+	require.NoError(t, err)
+
+	_, err = sender.Exec(ctx, "NOTIFY bar, 'not listening'")
+	require.NoError(t, err)
+	_, err = sender.Exec(ctx, "NOTIFY foo, 'hello'")
+	require.NoError(t, err)
+	channel, payload, pid := waitForNotification()
+	require.Equal(t, "foo", channel)
+	require.Equal(t, "hello", payload)
+	require.Equal(t, sender.PgConn().PID(), pid)
+
+	// Notifications sent by a transaction which rolls back are not delivered.
+	tx, err := sender.Begin(ctx)
+	require.NoError(t, err)
+	_, err = tx.Exec(ctx, "NOTIFY foo, 'rolled back'")
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback(ctx))
+
+	_, err = sender.Exec(ctx, "SELECT pg_notify('foo', 'world')")
+	require.NoError(t, err)
+	channel, payload, _ = waitForNotification()
+	require.Equal(t, "foo", channel)
+	require.Equal(t, "world", payload)
+
+	// The listener can also receive its own notifications.
+	_, err = listener.Exec(ctx, "NOTIFY foo")
+	require.NoError(t, err)
+	channel, payload, pid = waitForNotification()
+	require.Equal(t, "foo", channel)
+	require.Equal(t, "", payload)
+	require.Equal(t, listener.PgConn().PID(), pid)
+}
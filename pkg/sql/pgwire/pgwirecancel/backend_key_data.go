@@ -72,3 +72,10 @@ func (b BackendKeyData) GetSQLInstanceID() base.SQLInstanceID {
 	return base.SQLInstanceID(bits >> 32)
 
 }
+
+// GetPGBackendPID returns the process ID that clients see for the session
+// identified by this BackendKeyData. The BackendKeyData message sends the
+// upper 32 bits as the process ID and the lower 32 bits as the secret key.
+func (b BackendKeyData) GetPGBackendPID() int32 {
+	return int32(uint64(b) >> 32)
+}
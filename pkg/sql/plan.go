@@ -147,8 +147,12 @@ type planNodeReadingOwnWrites interface {
 	ReadingOwnWrites()
 }
 
+var _ planNode = &alterFunctionRenameNode{}
+var _ planNode = &alterFunctionSetOwnerNode{}
+var _ planNode = &alterFunctionSetSchemaNode{}
 var _ planNode = &alterIndexNode{}
 var _ planNode = &alterSchemaNode{}
+var _ planNode = &alterDomainNode{}
 var _ planNode = &alterSequenceNode{}
 var _ planNode = &alterTableNode{}
 var _ planNode = &alterTableOwnerNode{}
@@ -158,11 +162,15 @@ var _ planNode = &bufferNode{}
 var _ planNode = &cancelQueriesNode{}
 var _ planNode = &cancelSessionsNode{}
 var _ planNode = &changePrivilegesNode{}
+var _ planNode = &commentOnFunctionNode{}
 var _ planNode = &createDatabaseNode{}
+var _ planNode = &createFunctionNode{}
+var _ planNode = &createDomainNode{}
 var _ planNode = &createIndexNode{}
 var _ planNode = &createSequenceNode{}
 var _ planNode = &createStatsNode{}
 var _ planNode = &createTableNode{}
+var _ planNode = &createTriggerNode{}
 var _ planNode = &createTypeNode{}
 var _ planNode = &CreateRoleNode{}
 var _ planNode = &createViewNode{}
@@ -171,10 +179,12 @@ var _ planNode = &deleteNode{}
 var _ planNode = &deleteRangeNode{}
 var _ planNode = &distinctNode{}
 var _ planNode = &dropDatabaseNode{}
+var _ planNode = &dropFunctionNode{}
 var _ planNode = &dropIndexNode{}
 var _ planNode = &dropSchemaNode{}
 var _ planNode = &dropSequenceNode{}
 var _ planNode = &dropTableNode{}
+var _ planNode = &dropTriggerNode{}
 var _ planNode = &dropTypeNode{}
 var _ planNode = &DropRoleNode{}
 var _ planNode = &dropViewNode{}
@@ -189,7 +199,9 @@ var _ planNode = &insertNode{}
 var _ planNode = &insertFastPathNode{}
 var _ planNode = &joinNode{}
 var _ planNode = &limitNode{}
+var _ planNode = &listenNode{}
 var _ planNode = &max1RowNode{}
+var _ planNode = &notifyNode{}
 var _ planNode = &ordinalityNode{}
 var _ planNode = &projectSetNode{}
 var _ planNode = &reassignOwnedByNode{}
@@ -217,6 +229,7 @@ var _ planNode = &splitNode{}
 var _ planNode = &topKNode{}
 var _ planNode = &unsplitNode{}
 var _ planNode = &unsplitAllNode{}
+var _ planNode = &unlistenNode{}
 var _ planNode = &truncateNode{}
 var _ planNode = &unaryNode{}
 var _ planNode = &unionNode{}
@@ -234,6 +247,10 @@ var _ planNodeFastPath = &setZoneConfigNode{}
 var _ planNodeFastPath = &controlJobsNode{}
 var _ planNodeFastPath = &controlSchedulesNode{}
 
+var _ planNodeReadingOwnWrites = &alterFunctionRenameNode{}
+var _ planNodeReadingOwnWrites = &alterDomainNode{}
+var _ planNodeReadingOwnWrites = &alterFunctionSetOwnerNode{}
+var _ planNodeReadingOwnWrites = &alterFunctionSetSchemaNode{}
 var _ planNodeReadingOwnWrites = &alterIndexNode{}
 var _ planNodeReadingOwnWrites = &alterSchemaNode{}
 var _ planNodeReadingOwnWrites = &alterSequenceNode{}
@@ -242,11 +259,16 @@ var _ planNodeReadingOwnWrites = &alterTypeNode{}
 var _ planNodeReadingOwnWrites = &createIndexNode{}
 var _ planNodeReadingOwnWrites = &createSequenceNode{}
 var _ planNodeReadingOwnWrites = &createDatabaseNode{}
+var _ planNodeReadingOwnWrites = &createDomainNode{}
+var _ planNodeReadingOwnWrites = &createFunctionNode{}
 var _ planNodeReadingOwnWrites = &createTableNode{}
+var _ planNodeReadingOwnWrites = &createTriggerNode{}
 var _ planNodeReadingOwnWrites = &createTypeNode{}
 var _ planNodeReadingOwnWrites = &createViewNode{}
 var _ planNodeReadingOwnWrites = &changePrivilegesNode{}
+var _ planNodeReadingOwnWrites = &dropFunctionNode{}
 var _ planNodeReadingOwnWrites = &dropSchemaNode{}
+var _ planNodeReadingOwnWrites = &dropTriggerNode{}
 var _ planNodeReadingOwnWrites = &dropTypeNode{}
 var _ planNodeReadingOwnWrites = &refreshMaterializedViewNode{}
 var _ planNodeReadingOwnWrites = &reparentDatabaseNode{}
@@ -434,6 +456,10 @@ type cascadeMetadata struct {
 	// plan for the cascade. This plan is not populated upfront; it is created
 	// only when it needs to run, after the main query (and previous cascades).
 	plan planMaybePhysical
+	// rowBuffer is set if the cascade is executed for a single row of the
+	// buffer of a ForEachRow cascade. It is the Buffer of the cascade, and it is
+	// owned by the cascade.
+	rowBuffer *bufferNode
 }
 
 // checkPlan is a query tree that is executed after the main one. It can only
@@ -450,6 +476,9 @@ func (p *planComponents) close(ctx context.Context) {
 	}
 	for i := range p.cascades {
 		p.cascades[i].plan.Close(ctx)
+		if p.cascades[i].rowBuffer != nil {
+			p.cascades[i].rowBuffer.Close(ctx)
+		}
 	}
 	for i := range p.checkPlans {
 		p.checkPlans[i].plan.Close(ctx)
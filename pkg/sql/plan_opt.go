@@ -59,19 +59,20 @@ func (p *planner) prepareUsingOptimizer(ctx context.Context) (planFlags, error)
 		*tree.Analyze,
 		*tree.BeginTransaction,
 		*tree.CommentOnColumn, *tree.CommentOnConstraint, *tree.CommentOnDatabase, *tree.CommentOnIndex, *tree.CommentOnTable, *tree.CommentOnSchema,
+		*tree.CommentOnFunction,
 		*tree.CommitTransaction,
-		*tree.CopyFrom, *tree.CreateDatabase, *tree.CreateIndex, *tree.CreateView,
+		*tree.CopyFrom, *tree.CreateDatabase, *tree.CreateFunction, *tree.CreateIndex, *tree.CreateView,
 		*tree.CreateSequence,
 		*tree.CreateStats,
 		*tree.Deallocate, *tree.Discard, *tree.DropDatabase, *tree.DropIndex,
-		*tree.DropTable, *tree.DropView, *tree.DropSequence, *tree.DropType,
+		*tree.DropTable, *tree.DropView, *tree.DropSequence, *tree.DropType, *tree.DropFunction,
 		*tree.Grant, *tree.GrantRole,
 		*tree.Prepare,
 		*tree.ReleaseSavepoint, *tree.RenameColumn, *tree.RenameDatabase,
 		*tree.RenameIndex, *tree.RenameTable, *tree.Revoke, *tree.RevokeRole,
 		*tree.RollbackToSavepoint, *tree.RollbackTransaction,
-		*tree.Savepoint, *tree.SetTransaction, *tree.SetTracing, *tree.SetSessionAuthorizationDefault,
-		*tree.SetSessionCharacteristics:
+		*tree.Savepoint, *tree.SetTransaction, *tree.SetConstraints, *tree.SetTracing,
+		*tree.SetSessionAuthorizationDefault, *tree.SetSessionCharacteristics:
 		// These statements do not have result columns and do not support placeholders
 		// so there is no need to do anything during prepare.
 		//
@@ -323,13 +324,13 @@ func (opc *optPlanningCtx) reset() {
 	opc.optimizer.Init(p.EvalContext(), &opc.catalog)
 	opc.flags = 0
 
-	// We only allow memo caching for SELECT/INSERT/UPDATE/DELETE. We could
+	// We only allow memo caching for SELECT/INSERT/UPDATE/DELETE/MERGE. We could
 	// support it for all statements in principle, but it would increase the
 	// surface of potential issues (conditions we need to detect to invalidate a
 	// cached memo).
 	switch p.stmt.AST.(type) {
 	case *tree.ParenSelect, *tree.Select, *tree.SelectClause, *tree.UnionClause, *tree.ValuesClause,
-		*tree.Insert, *tree.Update, *tree.Delete, *tree.CannedOptPlan:
+		*tree.Insert, *tree.Update, *tree.Delete, *tree.Merge, *tree.CannedOptPlan:
 		// If the current transaction has uncommitted DDL statements, we cannot rely
 		// on descriptor versions for detecting a "stale" memo. This is because
 		// descriptor versions are bumped at most once per transaction, even if there
@@ -30,6 +30,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgnotify"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirecancel"
 	"github.com/cockroachdb/cockroach/pkg/sql/querycache"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -96,6 +98,16 @@ type extendedEvalContext struct {
 	// records when transaction is committed.
 	SchemaChangeJobRecords map[descpb.ID]*jobs.Record
 
+	// DeferredConstraints refers to deferredConstraints in extraTxnState of
+	// sql.connExecutor. It tracks the deferrable constraints whose checks are
+	// postponed until the transaction commits.
+	DeferredConstraints *deferredConstraintState
+
+	// Notifications refers to notifications in extraTxnState of
+	// sql.connExecutor. It buffers the notifications sent in the transaction
+	// until the transaction commits.
+	Notifications *pendingNotifications
+
 	statsProvider *persistedsqlstats.PersistedSQLStats
 
 	indexUsageStats *idxusage.LocalIndexUsageStats
@@ -190,6 +202,14 @@ type planner struct {
 
 	createdSequences createdSequences
 
+	// notifications receives the notifications for the channels the session
+	// listens on. It is nil if the session does not support LISTEN.
+	notifications *pgnotify.Listener
+
+	// queryCancelKey identifies the session; the notifications it sends are
+	// tagged with the process ID derived from it.
+	queryCancelKey pgwirecancel.BackendKeyData
+
 	// avoidLeasedDescriptors, when true, instructs all code that
 	// accesses table/view descriptors to force reading the descriptors
 	// within the transaction. This is necessary to read descriptors
@@ -113,6 +113,11 @@ func (p *planner) findColumnToRename(
 			)
 		}
 	}
+	for _, fnRef := range tableDesc.DependedOnByFunctions {
+		if descpb.ColumnIDs(fnRef.ColumnIDs).Contains(col.GetID()) {
+			return nil, p.dependentFunctionError(ctx, "column", oldName.String(), fnRef.ID, "rename")
+		}
+	}
 	if oldName == newName {
 		// Noop.
 		return nil, nil
@@ -88,6 +88,13 @@ func (p *planner) RenameTable(ctx context.Context, n *tree.RenameTable) (planNod
 			)
 		}
 	}
+	// The bodies of functions are stored as text, so they always reference
+	// their dependencies by name.
+	for _, dependent := range tableDesc.DependedOnByFunctions {
+		return nil, p.dependentFunctionError(
+			ctx, string(tableDesc.DescriptorType()), oldTn.String(), dependent.ID, "rename",
+		)
+	}
 
 	return &renameTableNode{n: n, oldTn: &oldTn, newTn: &newTn, tableDesc: tableDesc}, nil
 }
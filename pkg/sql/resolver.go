@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -535,6 +536,104 @@ func (p *planner) ResolveTypeByOID(ctx context.Context, oid oid.Oid) (*types.T,
 	return desc.MakeTypesT(ctx, &name, p)
 }
 
+// ResolveFunction implements the tree.FunctionReferenceResolver interface.
+func (p *planner) ResolveFunction(
+	ctx context.Context, name *tree.UnresolvedName, path sessiondata.SearchPath,
+) (*tree.FunctionDefinition, error) {
+	fnName, scName, dbName := name.Parts[0], name.Parts[1], name.Parts[2]
+	_, fn, err := p.lookupFunction(ctx, dbName, scName, fnName, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(fn.Overloads) == 0 {
+		return nil, pgerror.Newf(pgcode.UndefinedFunction, "unknown function: %s()", name)
+	}
+
+	// Only the overloads which the user has the privilege to execute, and which
+	// are not trigger functions, can be resolved.
+	overloads := make([]tree.Overload, 0, len(fn.Overloads))
+	var skipErr error
+	for _, ol := range fn.Overloads {
+		desc, err := p.Descriptors().GetImmutableFunctionByID(
+			ctx, p.txn, ol.ID, p.ObjectLookupFlags(true /* required */, false /* requireMutable */),
+		)
+		if err != nil {
+			return nil, err
+		}
+		// Trigger functions can only be executed by triggers.
+		if desc.GetReturnsTrigger() {
+			skipErr = pgerror.Newf(pgcode.FeatureNotSupported,
+				"trigger functions can only be called as triggers")
+			continue
+		}
+		if err := p.CheckPrivilege(ctx, desc, privilege.EXECUTE); err != nil {
+			skipErr = err
+			continue
+		}
+		overload, err := funcdesc.ToOverload(desc)
+		if err != nil {
+			return nil, err
+		}
+		overloads = append(overloads, overload)
+	}
+	if len(overloads) == 0 {
+		return nil, skipErr
+	}
+	return tree.NewUDFFunctionDefinition(fnName, overloads), nil
+}
+
+// lookupFunction returns the overloads of the user-defined function with the
+// given name, along with the schema which contains them. If no schema name is
+// given, the schemas of the search path are searched in order, and the first
+// schema containing a function with the given name is used. If no database
+// name is given, the current database is used. A nil schema is returned if no
+// such function exists.
+func (p *planner) lookupFunction(
+	ctx context.Context, dbName, scName, fnName string, path sessiondata.SearchPath,
+) (catalog.SchemaDescriptor, descpb.SchemaDescriptor_Function, error) {
+	if dbName == "" {
+		dbName = p.CurrentDatabase()
+	}
+	if dbName == "" {
+		return nil, descpb.SchemaDescriptor_Function{}, nil
+	}
+	db, err := p.Descriptors().GetImmutableDatabaseByName(
+		ctx, p.txn, dbName, p.CommonLookupFlags(false /* required */),
+	)
+	if err != nil || db == nil {
+		return nil, descpb.SchemaDescriptor_Function{}, err
+	}
+
+	lookup := func(scName string) (catalog.SchemaDescriptor, descpb.SchemaDescriptor_Function, error) {
+		sc, err := p.Descriptors().GetImmutableSchemaByName(
+			ctx, p.txn, db, scName, p.CommonLookupFlags(false /* required */),
+		)
+		if err != nil || sc == nil {
+			return nil, descpb.SchemaDescriptor_Function{}, err
+		}
+		fn, ok := sc.GetFunction(fnName)
+		if !ok {
+			return nil, descpb.SchemaDescriptor_Function{}, nil
+		}
+		if err := p.canResolveDescUnderSchema(ctx, sc, nil /* desc */); err != nil {
+			return nil, descpb.SchemaDescriptor_Function{}, err
+		}
+		return sc, fn, nil
+	}
+
+	if scName != "" {
+		return lookup(scName)
+	}
+	iter := path.Iter()
+	for scName, ok := iter.Next(); ok; scName, ok = iter.Next() {
+		sc, fn, err := lookup(scName)
+		if err != nil || sc != nil {
+			return sc, fn, err
+		}
+	}
+	return nil, descpb.SchemaDescriptor_Function{}, nil
+}
+
 // ObjectLookupFlags is part of the resolver.SchemaResolver interface.
 func (p *planner) ObjectLookupFlags(required, requireMutable bool) tree.ObjectLookupFlags {
 	flags := p.CommonLookupFlags(required)
@@ -591,6 +690,21 @@ func getDescriptorsFromTargetListForPrivilegeChange(
 		return descs, nil
 	}
 
+	if targets.Functions != nil {
+		if len(targets.Functions) == 0 {
+			return nil, errNoFunction
+		}
+		descs := make([]catalog.Descriptor, 0, len(targets.Functions))
+		for i := range targets.Functions {
+			_, fnDesc, err := p.resolveFuncObj(ctx, &targets.Functions[i], required)
+			if err != nil {
+				return nil, err
+			}
+			descs = append(descs, fnDesc)
+		}
+		return descs, nil
+	}
+
 	if targets.Schemas != nil {
 		if len(targets.Schemas) == 0 {
 			return nil, errNoSchema
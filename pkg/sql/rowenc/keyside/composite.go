@@ -0,0 +1,70 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package keyside
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// encodeCompositeKey generates an ordered key encoding of a value of a
+// composite type. The encoding format is the same as the one used for arrays
+// (see encodeArrayKey), which makes the encoding self-delimiting so that
+// composite values can be decoded and used as index columns.
+func encodeCompositeKey(b []byte, tuple *tree.DTuple, dir encoding.Direction) ([]byte, error) {
+	var err error
+	b = encoding.EncodeArrayKeyMarker(b, dir)
+	for _, elem := range tuple.D {
+		if elem == tree.DNull {
+			b = encoding.EncodeNullWithinArrayKey(b, dir)
+		} else {
+			b, err = Encode(b, elem, dir)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return encoding.EncodeArrayKeyTerminator(b, dir), nil
+}
+
+// decodeCompositeKey decodes a key generated by encodeCompositeKey.
+func decodeCompositeKey(
+	a *tree.DatumAlloc, t *types.T, buf []byte, dir encoding.Direction,
+) (tree.Datum, []byte, error) {
+	var err error
+	buf, err = encoding.ValidateAndConsumeArrayKeyMarker(buf, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contents := t.TupleContents()
+	result := tree.NewDTupleWithLen(t, len(contents))
+	for i := range contents {
+		if len(buf) == 0 {
+			return nil, nil, errors.AssertionFailedf("invalid composite encoding (unterminated)")
+		}
+		if encoding.IsNextByteArrayEncodedNull(buf, dir) {
+			result.D[i] = tree.DNull
+			buf = buf[1:]
+			continue
+		}
+		result.D[i], buf, err = Decode(a, contents[i], buf, dir)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(buf) == 0 || !encoding.IsArrayKeyDone(buf, dir) {
+		return nil, nil, errors.AssertionFailedf("invalid composite encoding (expected terminator)")
+	}
+	return result, buf[1:], nil
+}
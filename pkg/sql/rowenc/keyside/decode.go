@@ -46,6 +46,12 @@ func Decode(
 	switch valType.Family() {
 	case types.ArrayFamily:
 		return decodeArrayKey(a, valType, key, dir)
+	case types.TupleFamily:
+		// Only values of composite types can be decoded; see Encode.
+		if valType.UserDefined() {
+			return decodeCompositeKey(a, valType, key, dir)
+		}
+		return nil, nil, errors.Errorf("unable to decode table key: %s", valType)
 	case types.BitFamily:
 		var r bitarray.BitArray
 		if dir == encoding.Ascending {
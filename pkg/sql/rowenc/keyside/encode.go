@@ -140,6 +140,13 @@ func Encode(b []byte, val tree.Datum, dir encoding.Direction) ([]byte, error) {
 		}
 		return encoding.EncodeBytesDescending(b, data), nil
 	case *tree.DTuple:
+		// Values of composite types, which can be stored in indexed columns,
+		// use a self-delimiting encoding. Other tuples keep the original
+		// encoding, which is relied on by mixed-version flows (for example,
+		// through EncDatum.Fingerprint).
+		if t.ResolvedType().UserDefined() {
+			return encodeCompositeKey(b, t, dir)
+		}
 		for _, datum := range t.D {
 			var err error
 			b, err = Encode(b, datum, dir)
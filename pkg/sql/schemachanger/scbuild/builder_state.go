@@ -523,6 +523,13 @@ func (b *builderState) ResolveEnumType(
 	case descpb.TypeDescriptor_ENUM:
 		b.ensureDescriptor(typ.GetID())
 		b.mustOwn(typ.GetID())
+	case descpb.TypeDescriptor_DOMAIN:
+		panic(errors.WithHint(pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a type", typ.GetName()),
+			"Use DROP DOMAIN to remove a domain."))
+	case descpb.TypeDescriptor_COMPOSITE:
+		// Composite types are only supported by the legacy schema changer.
+		panic(scerrors.NotImplementedErrorf(nil /* n */, "composite type %q", typ.GetName()))
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		// Implicit record types are not directly modifiable.
 		panic(pgerror.Newf(pgcode.DependentObjectsStillExist,
@@ -49,6 +49,9 @@ func alterTableAddColumn(
 	if d.IsSerial {
 		panic(scerrors.NotImplementedErrorf(d, "contains serial data type"))
 	}
+	if _, ok := d.Type.(*tree.UnresolvedObjectName); ok && b.ResolveTypeRef(d.Type).Type.IsDomain() {
+		panic(scerrors.NotImplementedErrorf(d, "contains domain data type"))
+	}
 	if d.IsComputed() {
 		d.Computed.Expr = schemaexpr.MaybeRewriteComputedColumn(d.Computed.Expr, b.SessionData())
 	}
@@ -125,6 +125,12 @@ func (w *walkCtx) walkDatabase(db catalog.DatabaseDescriptor) {
 }
 
 func (w *walkCtx) walkSchema(sc catalog.SchemaDescriptor) {
+	// User-defined functions are not modeled as elements, so schemas
+	// containing them can only be changed by the legacy schema changer.
+	_ = sc.ForEachFunction(func(fn descpb.SchemaDescriptor_Function) error {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"schema %q contains user-defined function %q", sc.GetName(), fn.Name))
+	})
 	w.ev(descriptorStatus(sc), &scpb.Schema{
 		SchemaID:    sc.GetID(),
 		IsPublic:    sc.GetName() == catconstants.PublicSchemaName,
@@ -160,6 +166,15 @@ func (w *walkCtx) walkType(typ catalog.TypeDescriptor) {
 			ArrayTypeID:   typ.GetArrayTypeID(),
 			IsMultiRegion: typ.GetKind() == descpb.TypeDescriptor_MULTIREGION_ENUM,
 		})
+	case descpb.TypeDescriptor_DOMAIN:
+		// Domains are not modeled as elements, so they can only be changed by the
+		// legacy schema changer.
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"domain %q", typ.GetName()))
+	case descpb.TypeDescriptor_COMPOSITE:
+		// Likewise for composite types.
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"composite type %q", typ.GetName()))
 	default:
 		panic(errors.AssertionFailedf("unsupported type kind %q", typ.GetKind()))
 	}
@@ -173,6 +188,12 @@ func (w *walkCtx) walkType(typ catalog.TypeDescriptor) {
 }
 
 func (w *walkCtx) walkRelation(tbl catalog.TableDescriptor) {
+	// Functions are not modeled as elements either, so relations which are
+	// referenced by them can only be changed by the legacy schema changer.
+	if refs := tbl.GetDependedOnByFunctions(); len(refs) > 0 {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"relation %q is referenced by function %d", tbl.GetName(), refs[0].ID))
+	}
 	switch {
 	case tbl.IsSequence():
 		w.ev(descriptorStatus(tbl), &scpb.Sequence{
@@ -191,6 +212,12 @@ func (w *walkCtx) walkRelation(tbl catalog.TableDescriptor) {
 			IsMaterialized:  tbl.MaterializedView(),
 		})
 	default:
+		// Triggers are not modeled as elements, so tables which have them can
+		// only be changed by the legacy schema changer.
+		if triggers := tbl.GetTriggers(); len(triggers) > 0 {
+			panic(scerrors.NotImplementedErrorf(nil, /* n */
+				"table %q has trigger %q", tbl.GetName(), triggers[0].Name))
+		}
 		w.ev(descriptorStatus(tbl), &scpb.Table{
 			TableID:     tbl.GetID(),
 			IsTemporary: tbl.IsTemporary(),
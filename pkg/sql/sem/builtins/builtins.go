@@ -2410,6 +2410,28 @@ var builtins = map[string]builtinDefinition{
 		},
 	),
 
+	// grouping is replaced with a constant by the optimizer when it is used in a
+	// query with a GROUP BY clause, so it is never evaluated directly.
+	"grouping": makeBuiltin(
+		tree.FunctionProperties{
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types:      tree.VariadicType{VarType: types.Any},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				return nil, pgerror.New(pgcode.Grouping,
+					"arguments to GROUPING must be grouping expressions of the associated query level")
+			},
+			Info: "Returns a bit mask indicating which of its arguments are not included " +
+				"in the current grouping set. Bits are assigned with the rightmost " +
+				"argument being the least-significant bit; each bit is 0 if the " +
+				"corresponding expression is included in the grouping criteria of the " +
+				"grouping set generating the current result row, and 1 if it is not.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
 	// Timestamp/Date functions.
 
 	"experimental_strftime": makeBuiltin(
@@ -5072,6 +5094,36 @@ value if you rely on the HLC for accuracy.`,
 		},
 	),
 
+	// crdb_internal.check_domain_value is used by the optimizer to enforce the
+	// constraints of a DOMAIN when a value is cast to it.
+	"crdb_internal.check_domain_value": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+			Undocumented: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"value", types.Any},
+				{"ok", types.Bool},
+				{"errorCode", types.String},
+				{"msg", types.String},
+			},
+			ReturnType: tree.IdentityReturnType(0),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				// A NULL result of a CHECK constraint does not violate it.
+				if args[1] != tree.DBoolFalse {
+					return args[0], nil
+				}
+				errCode := string(tree.MustBeDString(args[2]))
+				msg := string(tree.MustBeDString(args[3]))
+				return nil, pgerror.Newf(pgcode.MakeCode(errCode), "%s", msg)
+			},
+			Info:       "Returns value if ok is not false, and otherwise raises the given error.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
 	"crdb_internal.notice": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
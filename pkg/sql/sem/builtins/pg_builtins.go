@@ -1127,6 +1127,31 @@ SELECT description
 		},
 	),
 
+	// pg_notify sends a notification, like the NOTIFY statement does. As in
+	// Postgres, a NULL payload is treated as an empty payload.
+	// https://www.postgresql.org/docs/current/sql-notify.html
+	"pg_notify": makeBuiltin(tree.FunctionProperties{DistsqlBlocklist: true, NullableArgs: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"channel", types.String}, {"payload", types.String}},
+			ReturnType: tree.FixedReturnType(types.Void),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				var channel, payload string
+				if args[0] != tree.DNull {
+					channel = string(tree.MustBeDString(args[0]))
+				}
+				if args[1] != tree.DNull {
+					payload = string(tree.MustBeDString(args[1]))
+				}
+				if err := ctx.Planner.SendNotification(ctx.Ctx(), channel, payload); err != nil {
+					return nil, err
+				}
+				return tree.DVoidDatum, nil
+			},
+			Info:       "Sends a notification with the given payload to the sessions listening on the given channel.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	// https://www.postgresql.org/docs/10/static/functions-string.html
 	// CockroachDB supports just UTF8 for now.
 	"pg_client_encoding": makeBuiltin(defProps(),
@@ -131,8 +131,10 @@ type RestoreOptions struct {
 	SkipLocalitiesCheck       bool
 	DebugPauseOn              Expr
 	NewDBName                 Expr
+	NewTableName              Expr
 	IncrementalStorage        StringOrPlaceholderOptList
 	AsTenant                  Expr
+	SchemaOnly                bool
 }
 
 var _ NodeFormatter = &RestoreOptions{}
@@ -155,6 +157,10 @@ type Restore struct {
 	AsOf    AsOfClause
 	Options RestoreOptions
 
+	// Where, if set, restricts the rows of the restored table to those
+	// matching it.
+	Where *Where
+
 	// Subdir may be set by the parser when the SQL query is of the form `RESTORE
 	// ... FROM 'from' IN 'subdir'...`. Alternatively, restore_planning.go will set
 	// it for the query `RESTORE ... FROM 'from' IN LATEST...`
@@ -185,6 +191,10 @@ func (node *Restore) Format(ctx *FmtCtx) {
 		ctx.WriteString(" ")
 		ctx.FormatNode(&node.AsOf)
 	}
+	if node.Where != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.Where)
+	}
 	if !node.Options.IsDefault() {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
@@ -393,6 +403,12 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		ctx.FormatNode(o.NewDBName)
 	}
 
+	if o.NewTableName != nil {
+		maybeAddSep()
+		ctx.WriteString("new_table_name = ")
+		ctx.FormatNode(o.NewTableName)
+	}
+
 	if o.IncrementalStorage != nil {
 		maybeAddSep()
 		ctx.WriteString("incremental_location = ")
@@ -404,6 +420,11 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("tenant = ")
 		ctx.FormatNode(o.AsTenant)
 	}
+
+	if o.SchemaOnly {
+		maybeAddSep()
+		ctx.WriteString("schema_only")
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -487,6 +508,12 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		return errors.New("new_db_name specified multiple times")
 	}
 
+	if o.NewTableName == nil {
+		o.NewTableName = other.NewTableName
+	} else if other.NewTableName != nil {
+		return errors.New("new_table_name specified multiple times")
+	}
+
 	if o.IncrementalStorage == nil {
 		o.IncrementalStorage = other.IncrementalStorage
 	} else if other.IncrementalStorage != nil {
@@ -499,6 +526,14 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		return errors.New("tenant option specified multiple times")
 	}
 
+	if o.SchemaOnly {
+		if other.SchemaOnly {
+			return errors.New("schema_only option specified multiple times")
+		}
+	} else {
+		o.SchemaOnly = other.SchemaOnly
+	}
+
 	return nil
 }
 
@@ -516,6 +551,8 @@ func (o RestoreOptions) IsDefault() bool {
 		o.SkipLocalitiesCheck == options.SkipLocalitiesCheck &&
 		o.DebugPauseOn == options.DebugPauseOn &&
 		o.NewDBName == options.NewDBName &&
+		o.NewTableName == options.NewTableName &&
 		cmp.Equal(o.IncrementalStorage, options.IncrementalStorage) &&
-		o.AsTenant == options.AsTenant
+		o.AsTenant == options.AsTenant &&
+		o.SchemaOnly == options.SchemaOnly
 }
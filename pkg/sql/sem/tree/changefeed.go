@@ -15,12 +15,23 @@ type CreateChangefeed struct {
 	Targets ChangefeedTargets
 	SinkURI Expr
 	Options KVOptions
+	// Select is set for CDC queries, i.e. `CREATE CHANGEFEED ... AS SELECT`.
+	// The single table it selects from is also the only element of Targets.
+	Select *SelectClause
+	// Database is set for `CREATE CHANGEFEED FOR DATABASE`, in which case
+	// Targets is empty and the changefeed watches every table in the database,
+	// including ones created after the changefeed.
+	Database Name
 }
 
 var _ Statement = &CreateChangefeed{}
 
 // Format implements the NodeFormatter interface.
 func (node *CreateChangefeed) Format(ctx *FmtCtx) {
+	if node.Select != nil {
+		node.formatWithSelect(ctx)
+		return
+	}
 	if node.SinkURI != nil {
 		ctx.WriteString("CREATE ")
 	} else {
@@ -29,7 +40,26 @@ func (node *CreateChangefeed) Format(ctx *FmtCtx) {
 		ctx.WriteString("EXPERIMENTAL ")
 	}
 	ctx.WriteString("CHANGEFEED FOR ")
-	ctx.FormatNode(&node.Targets)
+	if node.Database != "" {
+		ctx.WriteString("DATABASE ")
+		ctx.FormatNode(&node.Database)
+	} else {
+		ctx.FormatNode(&node.Targets)
+	}
+	if node.SinkURI != nil {
+		ctx.WriteString(" INTO ")
+		ctx.FormatNode(node.SinkURI)
+	}
+	if node.Options != nil {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
+
+// formatWithSelect formats a CDC query. Unlike other changefeeds, the syntax
+// of CDC queries is the same with or without a sink.
+func (node *CreateChangefeed) formatWithSelect(ctx *FmtCtx) {
+	ctx.WriteString("CREATE CHANGEFEED")
 	if node.SinkURI != nil {
 		ctx.WriteString(" INTO ")
 		ctx.FormatNode(node.SinkURI)
@@ -38,6 +68,8 @@ func (node *CreateChangefeed) Format(ctx *FmtCtx) {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
 	}
+	ctx.WriteString(" AS ")
+	ctx.FormatNode(node.Select)
 }
 
 // ChangefeedTarget represents a database object to be watched by a changefeed.
@@ -322,12 +322,41 @@ func (l *EnumValueList) Format(ctx *FmtCtx) {
 	}
 }
 
+// CompositeTypeElem is a single attribute of a composite type.
+type CompositeTypeElem struct {
+	Label Name
+	Type  ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (n *CompositeTypeElem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(&n.Label)
+	ctx.WriteByte(' ')
+	ctx.FormatTypeReference(n.Type)
+}
+
+// CompositeTypeList represents the list of attributes of a composite type.
+type CompositeTypeList []CompositeTypeElem
+
+// Format implements the NodeFormatter interface.
+func (l *CompositeTypeList) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*l)[i])
+	}
+}
+
 // CreateType represents a CREATE TYPE statement.
 type CreateType struct {
 	TypeName *UnresolvedObjectName
 	Variety  CreateTypeVariety
 	// EnumLabels is set when this represents a CREATE TYPE ... AS ENUM statement.
 	EnumLabels EnumValueList
+	// CompositeTypeList is set when this represents a CREATE TYPE ... AS (...)
+	// statement.
+	CompositeTypeList CompositeTypeList
 	// IfNotExists is true if IF NOT EXISTS was requested.
 	IfNotExists bool
 }
@@ -347,6 +376,10 @@ func (node *CreateType) Format(ctx *FmtCtx) {
 		ctx.WriteString("AS ENUM (")
 		ctx.FormatNode(&node.EnumLabels)
 		ctx.WriteString(")")
+	case Composite:
+		ctx.WriteString("AS (")
+		ctx.FormatNode(&node.CompositeTypeList)
+		ctx.WriteString(")")
 	}
 }
 
@@ -446,6 +479,7 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
@@ -629,6 +663,7 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			d.References.Deferrability = t.Deferrability
 		case *ColumnComputedDef:
 			if d.GeneratedIdentity.IsGeneratedAsIdentity {
 				return nil, pgerror.Newf(pgcode.Syntax,
@@ -799,6 +834,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(&node.References.Deferrability)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
@@ -928,10 +964,11 @@ type ColumnCheckConstraint struct {
 
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
-	Table   TableName
-	Col     Name // empty-string means use PK
-	Actions ReferenceActions
-	Match   CompositeKeyMatchMethod
+	Table         TableName
+	Col           Name // empty-string means use PK
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
 }
 
 // ColumnComputedDef represents the description of a computed column.
@@ -1019,9 +1056,10 @@ func (*CheckConstraintTableDef) constraintTableDef()      {}
 // TABLE statement.
 type UniqueConstraintTableDef struct {
 	IndexTableDef
-	PrimaryKey   bool
-	WithoutIndex bool
-	IfNotExists  bool
+	PrimaryKey    bool
+	WithoutIndex  bool
+	Deferrability ConstraintDeferrability
+	IfNotExists   bool
 }
 
 // SetName implements the TableDef interface.
@@ -1066,6 +1104,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionByIndex != nil {
 		ctx.FormatNode(node.PartitionByIndex)
 	}
+	ctx.FormatNode(&node.Deferrability)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -1139,15 +1178,46 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability describes whether the checking of a constraint can be
+// postponed until the end of the transaction (see SET CONSTRAINTS).
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	ConstraintNotDeferrable ConstraintDeferrability = iota
+	ConstraintInitiallyImmediate
+	ConstraintInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	ConstraintNotDeferrable:      "NOT DEFERRABLE",
+	ConstraintInitiallyImmediate: "DEFERRABLE INITIALLY IMMEDIATE",
+	ConstraintInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (c ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[c]
+}
+
+// Format implements the NodeFormatter interface. Nothing is written for
+// constraints which are not deferrable.
+func (c *ConstraintDeferrability) Format(ctx *FmtCtx) {
+	if *c != ConstraintNotDeferrable {
+		ctx.WriteByte(' ')
+		ctx.WriteString(c.String())
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name        Name
-	Table       TableName
-	FromCols    NameList
-	ToCols      NameList
-	Actions     ReferenceActions
-	Match       CompositeKeyMatchMethod
-	IfNotExists bool
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
+	IfNotExists   bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -1178,6 +1248,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(&node.Deferrability)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1586,12 +1657,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:         *col.References.Table,
+					FromCols:      NameList{col.Name},
+					ToCols:        targetCol,
+					Name:          col.References.ConstraintName,
+					Actions:       col.References.Actions,
+					Match:         col.References.Match,
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
@@ -0,0 +1,180 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// DomainCheck represents a CHECK constraint of a CREATE DOMAIN statement.
+type DomainCheck struct {
+	// Name is the name of the constraint, if one was given.
+	Name Name
+	Expr Expr
+}
+
+// CreateDomain represents a CREATE DOMAIN statement.
+type CreateDomain struct {
+	TypeName *UnresolvedObjectName
+	Type     ResolvableTypeReference
+	// DefaultExpr is the optional DEFAULT expression of the domain.
+	DefaultExpr Expr
+	// NotNull is true if the domain was declared NOT NULL.
+	NotNull bool
+	Checks  []DomainCheck
+}
+
+var _ Statement = &CreateDomain{}
+
+// NewCreateDomain constructs a CREATE DOMAIN statement. Only DEFAULT, NULL,
+// NOT NULL and CHECK are accepted among the column qualifications.
+func NewCreateDomain(
+	name *UnresolvedObjectName,
+	typRef ResolvableTypeReference,
+	qualifications []NamedColumnQualification,
+) (*CreateDomain, error) {
+	n := &CreateDomain{
+		TypeName: name,
+		Type:     typRef,
+	}
+	nullability := SilentNull
+	for _, c := range qualifications {
+		switch t := c.Qualification.(type) {
+		case *ColumnDefault:
+			if n.DefaultExpr != nil {
+				return nil, pgerror.New(pgcode.Syntax, "multiple default expressions")
+			}
+			n.DefaultExpr = t.Expr
+		case NotNullConstraint:
+			if nullability == Null {
+				return nil, pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+			}
+			nullability = NotNull
+			n.NotNull = true
+		case NullConstraint:
+			if nullability == NotNull {
+				return nil, pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+			}
+			nullability = Null
+		case *ColumnCheckConstraint:
+			n.Checks = append(n.Checks, DomainCheck{Name: c.Name, Expr: t.Expr})
+		case ColumnCollation:
+			return nil, unimplemented.NewWithIssueDetail(27796, "collate", "COLLATE is not supported for domains")
+		case PrimaryKeyConstraint, ShardedPrimaryKeyConstraint:
+			return nil, pgerror.New(pgcode.Syntax, "primary key constraints not possible for domains")
+		case UniqueConstraint:
+			return nil, pgerror.New(pgcode.Syntax, "unique constraints not possible for domains")
+		case *ColumnFKConstraint:
+			return nil, pgerror.New(pgcode.Syntax, "foreign key constraints not possible for domains")
+		default:
+			return nil, pgerror.New(pgcode.Syntax,
+				"only DEFAULT, NULL, NOT NULL and CHECK constraints are possible for domains")
+		}
+	}
+	return n, nil
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateDomain) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE DOMAIN ")
+	ctx.FormatNode(node.TypeName)
+	ctx.WriteString(" AS ")
+	ctx.FormatTypeReference(node.Type)
+	if node.DefaultExpr != nil {
+		ctx.WriteString(" DEFAULT ")
+		ctx.FormatNode(node.DefaultExpr)
+	}
+	if node.NotNull {
+		ctx.WriteString(" NOT NULL")
+	}
+	for i := range node.Checks {
+		c := &node.Checks[i]
+		if c.Name != "" {
+			ctx.WriteString(" CONSTRAINT ")
+			ctx.FormatNode(&c.Name)
+		}
+		ctx.WriteString(" CHECK (")
+		ctx.FormatNode(c.Expr)
+		ctx.WriteByte(')')
+	}
+}
+
+// DropDomain represents a DROP DOMAIN statement.
+type DropDomain struct {
+	Names        []*UnresolvedObjectName
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropDomain{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropDomain) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP DOMAIN ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	for i := range node.Names {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(node.Names[i])
+	}
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// AlterDomain represents an ALTER DOMAIN statement.
+type AlterDomain struct {
+	TypeName *UnresolvedObjectName
+	Cmd      AlterDomainCmd
+}
+
+var _ Statement = &AlterDomain{}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomain) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER DOMAIN ")
+	ctx.FormatNode(node.TypeName)
+	ctx.FormatNode(node.Cmd)
+}
+
+// AlterDomainCmd represents a domain modification operation.
+type AlterDomainCmd interface {
+	NodeFormatter
+	alterDomainCmd()
+}
+
+func (*AlterDomainAddConstraint) alterDomainCmd() {}
+
+var _ AlterDomainCmd = &AlterDomainAddConstraint{}
+
+// AlterDomainAddConstraint represents an ALTER DOMAIN ADD CONSTRAINT command.
+type AlterDomainAddConstraint struct {
+	Check DomainCheck
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterDomainAddConstraint) Format(ctx *FmtCtx) {
+	ctx.WriteString(" ADD ")
+	if node.Check.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		ctx.FormatNode(&node.Check.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("CHECK (")
+	ctx.FormatNode(node.Check.Expr)
+	ctx.WriteByte(')')
+}
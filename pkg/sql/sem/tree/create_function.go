@@ -0,0 +1,236 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+)
+
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	IsReplace  bool
+	FuncName   *UnresolvedObjectName
+	Args       FuncArgs
+	ReturnType FuncReturnType
+	Options    FunctionOptions
+}
+
+var _ Statement = &CreateFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.IsReplace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Args)
+	ctx.WriteString(") RETURNS ")
+	ctx.FormatNode(&node.ReturnType)
+	for _, option := range node.Options {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(option)
+	}
+}
+
+// FuncArg represents an argument of a function signature.
+type FuncArg struct {
+	// Name is the name of the argument. It is empty for unnamed arguments.
+	Name Name
+	Type ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArg) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.FormatTypeReference(node.Type)
+}
+
+// FuncArgs represents a list of function arguments.
+type FuncArgs []FuncArg
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArgs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// FuncReturnType represents the return type of a function.
+type FuncReturnType struct {
+	Type ResolvableTypeReference
+	// IsSet is true if the function returns SETOF the type.
+	IsSet bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncReturnType) Format(ctx *FmtCtx) {
+	if node.IsSet {
+		ctx.WriteString("SETOF ")
+	}
+	ctx.FormatTypeReference(node.Type)
+}
+
+// IsTrigger returns true if the return type is the TRIGGER pseudo-type.
+func (node *FuncReturnType) IsTrigger() bool {
+	name, ok := node.Type.(*UnresolvedObjectName)
+	return ok && name.NumParts == 1 && name.Object() == "trigger"
+}
+
+// FunctionOption is an interface representing the options of a CREATE
+// FUNCTION statement, such as its language, volatility or body.
+type FunctionOption interface {
+	NodeFormatter
+	functionOption()
+}
+
+func (FunctionNullInputBehavior) functionOption() {}
+func (FunctionVolatility) functionOption()        {}
+func (FunctionLeakproof) functionOption()         {}
+func (FunctionLanguage) functionOption()          {}
+func (FunctionBodyStr) functionOption()           {}
+
+// FunctionOptions represents a list of function options.
+type FunctionOptions []FunctionOption
+
+// FunctionNullInputBehavior represent the behavior of a function when it is
+// called with NULL arguments.
+type FunctionNullInputBehavior int
+
+const (
+	// FunctionCalledOnNullInput indicates that the function is called even if
+	// some of its arguments are NULL. This is the default.
+	FunctionCalledOnNullInput FunctionNullInputBehavior = iota
+	// FunctionReturnsNullOnNullInput indicates that the function returns NULL
+	// without being evaluated whenever any of its arguments are NULL.
+	FunctionReturnsNullOnNullInput
+	// FunctionStrict is the same as FunctionReturnsNullOnNullInput.
+	FunctionStrict
+)
+
+// Format implements the NodeFormatter interface.
+func (node FunctionNullInputBehavior) Format(ctx *FmtCtx) {
+	switch node {
+	case FunctionCalledOnNullInput:
+		ctx.WriteString("CALLED ON NULL INPUT")
+	case FunctionReturnsNullOnNullInput:
+		ctx.WriteString("RETURNS NULL ON NULL INPUT")
+	case FunctionStrict:
+		ctx.WriteString("STRICT")
+	}
+}
+
+// FunctionVolatility represents the volatility option of a function.
+type FunctionVolatility int
+
+const (
+	// FunctionVolatile indicates that the function may have side effects or
+	// return different results given the same arguments. This is the default.
+	FunctionVolatile FunctionVolatility = iota
+	// FunctionStable indicates that the function returns the same results
+	// given the same arguments within a single statement.
+	FunctionStable
+	// FunctionImmutable indicates that the function always returns the same
+	// results given the same arguments.
+	FunctionImmutable
+)
+
+// Format implements the NodeFormatter interface.
+func (node FunctionVolatility) Format(ctx *FmtCtx) {
+	switch node {
+	case FunctionVolatile:
+		ctx.WriteString("VOLATILE")
+	case FunctionStable:
+		ctx.WriteString("STABLE")
+	case FunctionImmutable:
+		ctx.WriteString("IMMUTABLE")
+	}
+}
+
+// FunctionLeakproof indicates whether a function is leakproof.
+type FunctionLeakproof bool
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLeakproof) Format(ctx *FmtCtx) {
+	if !node {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("LEAKPROOF")
+}
+
+// FunctionLanguage is the language of a function body.
+type FunctionLanguage string
+
+// FunctionLangSQL is the only supported function language.
+const FunctionLangSQL FunctionLanguage = "sql"
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLanguage) Format(ctx *FmtCtx) {
+	ctx.WriteString("LANGUAGE ")
+	ctx.WriteString(strings.ToUpper(string(node)))
+}
+
+// FunctionBodyStr is the string literal of a function body.
+type FunctionBodyStr string
+
+// Format implements the NodeFormatter interface.
+func (node FunctionBodyStr) Format(ctx *FmtCtx) {
+	ctx.WriteString("AS ")
+	// The body may contain both literals and identifiers, so it is hidden
+	// entirely when either is requested to be removed.
+	if ctx.flags.HasFlags(FmtHideConstants) || ctx.flags.HasFlags(FmtAnonymize) {
+		ctx.WriteString("'_'")
+	} else {
+		lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, string(node), ctx.flags.EncodeFlags())
+	}
+}
+
+// FuncObj represents a reference to a function, with an optional list of
+// argument types used to select a single overload.
+type FuncObj struct {
+	FuncName *UnresolvedObjectName
+	// Args is nil if no argument list was specified, in which case the name
+	// must identify a single overload.
+	Args FuncArgs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObj) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.FuncName)
+	if node.Args != nil {
+		ctx.WriteByte('(')
+		ctx.FormatNode(&node.Args)
+		ctx.WriteByte(')')
+	}
+}
+
+// FuncObjs is a list of FuncObj.
+type FuncObjs []FuncObj
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObjs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
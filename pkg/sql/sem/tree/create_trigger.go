@@ -0,0 +1,152 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// TriggerActionTime specifies whether a trigger is executed before or after
+// the modification of a row.
+type TriggerActionTime uint8
+
+const (
+	// TriggerActionTimeBefore represents BEFORE.
+	TriggerActionTimeBefore TriggerActionTime = iota
+	// TriggerActionTimeAfter represents AFTER.
+	TriggerActionTimeAfter
+)
+
+var triggerActionTimeName = [...]string{
+	TriggerActionTimeBefore: "BEFORE",
+	TriggerActionTimeAfter:  "AFTER",
+}
+
+func (t TriggerActionTime) String() string {
+	return triggerActionTimeName[t]
+}
+
+// TriggerEventType is a kind of row modification which fires a trigger.
+type TriggerEventType uint8
+
+const (
+	// TriggerEventInsert represents INSERT.
+	TriggerEventInsert TriggerEventType = iota
+	// TriggerEventUpdate represents UPDATE.
+	TriggerEventUpdate
+	// TriggerEventDelete represents DELETE.
+	TriggerEventDelete
+)
+
+var triggerEventTypeName = [...]string{
+	TriggerEventInsert: "INSERT",
+	TriggerEventUpdate: "UPDATE",
+	TriggerEventDelete: "DELETE",
+}
+
+func (t TriggerEventType) String() string {
+	return triggerEventTypeName[t]
+}
+
+// TriggerEvent represents one of the events of a CREATE TRIGGER statement.
+type TriggerEvent struct {
+	EventType TriggerEventType
+	// Columns is the list of columns given in an UPDATE OF clause.
+	Columns NameList
+}
+
+// Format implements the NodeFormatter interface.
+func (node *TriggerEvent) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.EventType.String())
+	if len(node.Columns) > 0 {
+		ctx.WriteString(" OF ")
+		ctx.FormatNode(&node.Columns)
+	}
+}
+
+// TriggerEvents represents the list of events of a CREATE TRIGGER statement.
+type TriggerEvents []*TriggerEvent
+
+// Format implements the NodeFormatter interface.
+func (node *TriggerEvents) Format(ctx *FmtCtx) {
+	for i, ev := range *node {
+		if i > 0 {
+			ctx.WriteString(" OR ")
+		}
+		ctx.FormatNode(ev)
+	}
+}
+
+// CreateTrigger represents a CREATE TRIGGER statement. Only row-level
+// triggers are supported.
+type CreateTrigger struct {
+	Name       Name
+	ActionTime TriggerActionTime
+	Events     TriggerEvents
+	Table      TableName
+	// When is the optional WHEN condition of the trigger.
+	When     Expr
+	FuncName *UnresolvedObjectName
+}
+
+var _ Statement = &CreateTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE TRIGGER ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.WriteString(node.ActionTime.String())
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Events)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	ctx.WriteString(" FOR EACH ROW")
+	if node.When != nil {
+		ctx.WriteString(" WHEN (")
+		ctx.FormatNode(node.When)
+		ctx.WriteByte(')')
+	}
+	ctx.WriteString(" EXECUTE FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteString("()")
+}
+
+// HasEvent returns true if the trigger is fired by the given type of event.
+func (node *CreateTrigger) HasEvent(eventType TriggerEventType) bool {
+	for _, ev := range node.Events {
+		if ev.EventType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// DropTrigger represents a DROP TRIGGER statement.
+type DropTrigger struct {
+	Name         Name
+	Table        TableName
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropTrigger{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropTrigger) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP TRIGGER ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(&node.Table)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
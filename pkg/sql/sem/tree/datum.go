@@ -4303,6 +4303,16 @@ func (d *DArray) ResolvedType() *types.T {
 	return types.MakeArray(d.ParamTyp)
 }
 
+// IsComposite implements the CompositeDatum interface.
+func (d *DTuple) IsComposite() bool {
+	for _, elem := range d.D {
+		if cdatum, ok := elem.(CompositeDatum); ok && cdatum.IsComposite() {
+			return true
+		}
+	}
+	return false
+}
+
 // IsComposite implements the CompositeDatum interface.
 func (d *DArray) IsComposite() bool {
 	for _, elem := range d.Array {
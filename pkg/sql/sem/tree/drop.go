@@ -210,6 +210,28 @@ func (node *DropType) Format(ctx *FmtCtx) {
 	}
 }
 
+// DropFunction represents a DROP FUNCTION command.
+type DropFunction struct {
+	Functions    FuncObjs
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP FUNCTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Functions)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
 // DropSchema represents a DROP SCHEMA command.
 type DropSchema struct {
 	Names        ObjectNamePrefixList
@@ -3318,6 +3318,10 @@ type EvalPlanner interface {
 	// it is invalid.
 	RepairTTLScheduledJobForTable(ctx context.Context, tableID int64) error
 
+	// SendNotification sends a notification on the given channel, which is
+	// delivered to the listening sessions if the current transaction commits.
+	SendNotification(ctx context.Context, channel, payload string) error
+
 	// QueryRowEx executes the supplied SQL statement and returns a single row, or
 	// nil if no row is found, or an error if more that one row is returned.
 	//
@@ -113,6 +113,11 @@ type FunctionProperties struct {
 	//
 	// See memo.CanBeCompositeSensitive.
 	CompositeInsensitive bool
+
+	// IsUDF is true if the function is a user-defined function. User-defined
+	// functions are inlined by the optimizer using Overload.Body and are never
+	// evaluated directly.
+	IsUDF bool
 }
 
 // ShouldDocument returns whether the built-in function should be included in
@@ -174,6 +179,27 @@ func NewFunctionDefinition(
 	}
 }
 
+// NewUDFFunctionDefinition allocates a function definition corresponding to
+// the given user-defined function overloads. Unlike NewFunctionDefinition, it
+// does not set up telemetry counters for the overloads, since the names of
+// user-defined functions are not safe to report.
+func NewUDFFunctionDefinition(name string, def []Overload) *FunctionDefinition {
+	overloads := make([]overloadImpl, len(def))
+	for i := range def {
+		overloads[i] = &def[i]
+	}
+	return &FunctionDefinition{
+		Name:       name,
+		Definition: overloads,
+		FunctionProperties: FunctionProperties{
+			// The NULL input behavior of user-defined functions is handled per
+			// overload when the function body is inlined.
+			NullableArgs: true,
+			IsUDF:        true,
+		},
+	}
+}
+
 // FunDefs holds pre-allocated FunctionDefinition instances
 // for every builtin function. Initialized by builtins.init().
 var FunDefs map[string]*FunctionDefinition
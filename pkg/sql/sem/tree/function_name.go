@@ -11,8 +11,11 @@
 package tree
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
@@ -62,6 +65,48 @@ func (fn *ResolvableFunctionReference) Resolve(
 	}
 }
 
+// ResolveWithResolver is like Resolve, but if the name does not refer to a
+// builtin function it falls back to looking up a user-defined function with
+// the given resolver. The resolver may be nil, in which case only builtin
+// functions are considered.
+func (fn *ResolvableFunctionReference) ResolveWithResolver(
+	ctx context.Context, searchPath sessiondata.SearchPath, resolver FunctionReferenceResolver,
+) (*FunctionDefinition, error) {
+	fd, err := fn.Resolve(searchPath)
+	if err == nil || resolver == nil || pgerror.GetPGCode(err) != pgcode.UndefinedFunction {
+		return fd, err
+	}
+	name, ok := fn.FunctionReference.(*UnresolvedName)
+	if !ok {
+		return nil, err
+	}
+	udf, udfErr := resolver.ResolveFunction(ctx, name, searchPath)
+	if udfErr != nil {
+		if pgerror.GetPGCode(udfErr) == pgcode.UndefinedFunction {
+			// Report the original error, which is the same one that would be
+			// returned if there were no user-defined functions.
+			return nil, err
+		}
+		return nil, udfErr
+	}
+	// Unlike builtins, the resolved user-defined function is not cached in
+	// the reference, since the function may be altered or dropped before the
+	// statement is executed again.
+	return udf, nil
+}
+
+// FunctionReferenceResolver is the interface that provides the ability to
+// look up user-defined functions by name.
+type FunctionReferenceResolver interface {
+	// ResolveFunction returns the overloads of the user-defined function with
+	// the given name. An unqualified name is looked up in the schemas of the
+	// search path. An error with code UndefinedFunction is returned if no
+	// such function exists.
+	ResolveFunction(
+		ctx context.Context, name *UnresolvedName, path sessiondata.SearchPath,
+	) (*FunctionDefinition, error)
+}
+
 // WrapFunction creates a new ResolvableFunctionReference
 // holding a pre-resolved function. Helper for grammar rules.
 func WrapFunction(n string) ResolvableFunctionReference {
@@ -0,0 +1,107 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// Merge represents a MERGE statement.
+type Merge struct {
+	With   *With
+	Table  TableExpr
+	Source TableExpr
+	On     Expr
+	Whens  MergeWhens
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Merge) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.With)
+	ctx.WriteString("MERGE INTO ")
+	ctx.FormatNode(node.Table)
+	ctx.WriteString(" USING ")
+	ctx.FormatNode(node.Source)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.On)
+	for _, w := range node.Whens {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(w)
+	}
+}
+
+// MergeActionType represents the action of a WHEN clause of a MERGE
+// statement.
+type MergeActionType int
+
+const (
+	// MergeActionDoNothing represents a DO NOTHING action.
+	MergeActionDoNothing MergeActionType = iota
+	// MergeActionUpdate represents an UPDATE SET action.
+	MergeActionUpdate
+	// MergeActionDelete represents a DELETE action.
+	MergeActionDelete
+	// MergeActionInsert represents an INSERT action.
+	MergeActionInsert
+)
+
+// MergeWhens represents a list of WHEN clauses of a MERGE statement.
+type MergeWhens []*MergeWhen
+
+// MergeWhen represents a WHEN [NOT] MATCHED clause of a MERGE statement.
+type MergeWhen struct {
+	// Matched is true for a WHEN MATCHED clause, and false for a WHEN NOT
+	// MATCHED clause.
+	Matched bool
+	// Cond is the optional AND condition of the clause.
+	Cond   Expr
+	Action MergeActionType
+	// Exprs contains the SET expressions of an UPDATE action.
+	Exprs UpdateExprs
+	// Columns contains the optional target columns of an INSERT action.
+	Columns NameList
+	// Values contains the values of an INSERT action. It is nil if DEFAULT
+	// VALUES are inserted.
+	Values Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeWhen) Format(ctx *FmtCtx) {
+	if node.Matched {
+		ctx.WriteString("WHEN MATCHED")
+	} else {
+		ctx.WriteString("WHEN NOT MATCHED")
+	}
+	if node.Cond != nil {
+		ctx.WriteString(" AND ")
+		ctx.FormatNode(node.Cond)
+	}
+	ctx.WriteString(" THEN ")
+	switch node.Action {
+	case MergeActionDoNothing:
+		ctx.WriteString("DO NOTHING")
+	case MergeActionUpdate:
+		ctx.WriteString("UPDATE SET ")
+		ctx.FormatNode(&node.Exprs)
+	case MergeActionDelete:
+		ctx.WriteString("DELETE")
+	case MergeActionInsert:
+		ctx.WriteString("INSERT")
+		if len(node.Columns) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteByte(')')
+		}
+		if node.Values == nil {
+			ctx.WriteString(" DEFAULT VALUES")
+		} else {
+			ctx.WriteString(" VALUES (")
+			ctx.FormatNode(&node.Values)
+			ctx.WriteByte(')')
+		}
+	}
+}
@@ -111,6 +111,14 @@ type Overload struct {
 	// DistSQL. One example is when the type information for function arguments
 	// cannot be recovered.
 	DistsqlBlocklist bool
+
+	// Body is the SQL body of a user-defined function overload.
+	Body string
+
+	// CalledOnNullInput is set to true when a user-defined function overload
+	// is evaluated even if some of its arguments are NULL. Otherwise, the
+	// overload returns NULL whenever one of its arguments is NULL.
+	CalledOnNullInput bool
 }
 
 // params implements the overloadImpl interface.
@@ -1221,6 +1221,9 @@ func (node *UpdateExpr) doc(p *PrettyCfg) pretty.Doc {
 	if node.Tuple {
 		d = p.bracket("(", d, ")")
 	}
+	if node.Field != "" {
+		d = pretty.Concat(d, pretty.Concat(pretty.Text("."), p.Doc(&node.Field)))
+	}
 	e := node.Expr
 	if p.Simplify {
 		e = StripParens(e)
@@ -1773,6 +1776,9 @@ func (node *UniqueConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	if node.PartitionByIndex != nil {
 		clauses = append(clauses, p.Doc(node.PartitionByIndex))
 	}
+	if node.Deferrability != ConstraintNotDeferrable {
+		clauses = append(clauses, pretty.Keyword(node.Deferrability.String()))
+	}
 	if node.Predicate != nil {
 		clauses = append(clauses, p.nestUnder(pretty.Keyword("WHERE"), p.Doc(node.Predicate)))
 	}
@@ -1790,6 +1796,7 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    REFERENCES tbl (...)
 	//    [MATCH ...]
 	//    [ACTIONS ...]
+	//    [DEFERRABLE ...]
 	//
 	// or (no constraint name):
 	//
@@ -1797,6 +1804,7 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 	//    REFERENCES tbl [(...)]
 	//    [MATCH ...]
 	//    [ACTIONS ...]
+	//    [DEFERRABLE ...]
 	//
 	clauses := make([]pretty.Doc, 0, 4)
 	title := pretty.ConcatSpace(
@@ -1823,6 +1831,10 @@ func (node *ForeignKeyConstraintTableDef) doc(p *PrettyCfg) pretty.Doc {
 		clauses = append(clauses, actions)
 	}
 
+	if node.Deferrability != ConstraintNotDeferrable {
+		clauses = append(clauses, pretty.Keyword(node.Deferrability.String()))
+	}
+
 	return p.nestUnder(title, pretty.Group(pretty.Stack(clauses...)))
 }
 
@@ -2004,6 +2016,9 @@ func (node *ColumnTableDef) docRow(p *PrettyCfg) pretty.TableRow {
 		if ref := p.Doc(&node.References.Actions); ref != pretty.Nil {
 			fkDetails = append(fkDetails, ref)
 		}
+		if node.References.Deferrability != ConstraintNotDeferrable {
+			fkDetails = append(fkDetails, pretty.Keyword(node.References.Deferrability.String()))
+		}
 		fk := fkHead
 		if len(fkDetails) > 0 {
 			fk = p.nestUnder(fk, pretty.Group(pretty.Stack(fkDetails...)))
@@ -2128,6 +2143,9 @@ func (node *Restore) doc(p *PrettyCfg) pretty.Doc {
 	if node.AsOf.Expr != nil {
 		items = append(items, node.AsOf.docRow(p))
 	}
+	if node.Where != nil {
+		items = append(items, node.Where.docRow(p))
+	}
 	if !node.Options.IsDefault() {
 		items = append(items, p.row("WITH", p.Doc(&node.Options)))
 	}
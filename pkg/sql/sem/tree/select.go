@@ -20,12 +20,14 @@
 package tree
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treewindow"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
@@ -697,6 +699,70 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType represents the kind of a grouping set in a GROUP BY clause.
+type GroupingSetType int
+
+const (
+	// RollupGroupingSet represents ROLLUP (a, b, ...), which groups by every
+	// prefix of its list of expressions.
+	RollupGroupingSet GroupingSetType = iota
+	// CubeGroupingSet represents CUBE (a, b, ...), which groups by every subset
+	// of its list of expressions.
+	CubeGroupingSet
+	// ExplicitGroupingSets represents GROUPING SETS (...), which groups by each
+	// of the listed grouping items.
+	ExplicitGroupingSets
+)
+
+var groupingSetTypeName = [...]string{
+	RollupGroupingSet:    "ROLLUP",
+	CubeGroupingSet:      "CUBE",
+	ExplicitGroupingSets: "GROUPING SETS",
+}
+
+func (t GroupingSetType) String() string {
+	return groupingSetTypeName[t]
+}
+
+// GroupingSet represents a ROLLUP, CUBE or GROUPING SETS item in a GROUP BY
+// clause. Parenthesized lists of expressions within it are represented as
+// Tuples, and the empty grouping set as an empty Tuple.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+var _ Expr = &GroupingSet{}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
+// String implements the fmt.Stringer interface.
+func (node *GroupingSet) String() string { return AsString(node) }
+
+// Walk implements the Expr interface.
+func (node *GroupingSet) Walk(v Visitor) Expr {
+	if exprs, changed := walkExprSlice(v, node.Exprs); changed {
+		exprCopy := *node
+		exprCopy.Exprs = exprs
+		return &exprCopy
+	}
+	return node
+}
+
+// TypeCheck implements the Expr interface.
+func (node *GroupingSet) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, pgerror.Newf(pgcode.Syntax,
+		"%s can only appear in a GROUP BY clause", node.Type)
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
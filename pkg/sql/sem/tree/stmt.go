@@ -124,7 +124,7 @@ func CanModifySchema(stmt Statement) bool {
 func CanWriteData(stmt Statement) bool {
 	switch stmt.(type) {
 	// Normal write operations.
-	case *Insert, *Delete, *Update, *Truncate:
+	case *Insert, *Delete, *Merge, *Update, *Truncate:
 		return true
 	// Import operations.
 	case *CopyFrom, *Import, *Restore:
@@ -385,6 +385,33 @@ func (*AlterTenantSetClusterSetting) StatementType() StatementType { return Type
 // StatementTag returns a short string identifying the type of statement.
 func (*AlterTenantSetClusterSetting) StatementTag() string { return "ALTER TENANT SET CLUSTER SETTING" }
 
+// StatementReturnType implements the Statement interface.
+func (*AlterFunctionRename) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*AlterFunctionRename) StatementType() StatementType { return TypeDDL }
+
+// StatementTag implements the Statement interface.
+func (*AlterFunctionRename) StatementTag() string { return "ALTER FUNCTION" }
+
+// StatementReturnType implements the Statement interface.
+func (*AlterFunctionSetSchema) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*AlterFunctionSetSchema) StatementType() StatementType { return TypeDDL }
+
+// StatementTag implements the Statement interface.
+func (*AlterFunctionSetSchema) StatementTag() string { return "ALTER FUNCTION" }
+
+// StatementReturnType implements the Statement interface.
+func (*AlterFunctionSetOwner) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*AlterFunctionSetOwner) StatementType() StatementType { return TypeDDL }
+
+// StatementTag implements the Statement interface.
+func (*AlterFunctionSetOwner) StatementTag() string { return "ALTER FUNCTION" }
+
 // StatementReturnType implements the Statement interface.
 func (*AlterType) StatementReturnType() StatementReturnType { return DDL }
 
@@ -396,6 +423,15 @@ func (*AlterType) StatementTag() string { return "ALTER TYPE" }
 
 func (*AlterType) hiddenFromShowQueries() {}
 
+// StatementReturnType implements the Statement interface.
+func (*AlterDomain) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*AlterDomain) StatementType() StatementType { return TypeDDL }
+
+// StatementTag implements the Statement interface.
+func (*AlterDomain) StatementTag() string { return "ALTER DOMAIN" }
+
 // StatementReturnType implements the Statement interface.
 func (*AlterSequence) StatementReturnType() StatementReturnType { return DDL }
 
@@ -587,6 +623,15 @@ func (*CommentOnSchema) StatementType() StatementType { return TypeDDL }
 // StatementTag returns a short string identifying the type of statement.
 func (*CommentOnSchema) StatementTag() string { return "COMMENT ON SCHEMA" }
 
+// StatementReturnType implements the Statement interface.
+func (*CommentOnFunction) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CommentOnFunction) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*CommentOnFunction) StatementTag() string { return "COMMENT ON FUNCTION" }
+
 // StatementReturnType implements the Statement interface.
 func (*CommentOnIndex) StatementReturnType() StatementReturnType { return DDL }
 
@@ -708,6 +753,39 @@ func (*CreateType) StatementTag() string { return "CREATE TYPE" }
 
 func (*CreateType) modifiesSchema() bool { return true }
 
+// StatementReturnType implements the Statement interface.
+func (*CreateDomain) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreateDomain) StatementType() StatementType { return TypeDDL }
+
+// StatementTag implements the Statement interface.
+func (*CreateDomain) StatementTag() string { return "CREATE DOMAIN" }
+
+func (*CreateDomain) modifiesSchema() bool { return true }
+
+// StatementReturnType implements the Statement interface.
+func (*CreateFunction) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreateFunction) StatementType() StatementType { return TypeDDL }
+
+// StatementTag implements the Statement interface.
+func (*CreateFunction) StatementTag() string { return "CREATE FUNCTION" }
+
+func (*CreateFunction) modifiesSchema() bool { return true }
+
+// StatementReturnType implements the Statement interface.
+func (*CreateTrigger) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*CreateTrigger) StatementType() StatementType { return TypeDDL }
+
+// StatementTag implements the Statement interface.
+func (*CreateTrigger) StatementTag() string { return "CREATE TRIGGER" }
+
+func (*CreateTrigger) modifiesSchema() bool { return true }
+
 // StatementReturnType implements the Statement interface.
 func (*CreateRole) StatementReturnType() StatementReturnType { return Ack }
 
@@ -846,6 +924,33 @@ func (*DropRole) cclOnlyStatement() {}
 
 func (*DropRole) hiddenFromShowQueries() {}
 
+// StatementReturnType implements the Statement interface.
+func (*DropDomain) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*DropDomain) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropDomain) StatementTag() string { return "DROP DOMAIN" }
+
+// StatementReturnType implements the Statement interface.
+func (*DropFunction) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*DropFunction) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropFunction) StatementTag() string { return "DROP FUNCTION" }
+
+// StatementReturnType implements the Statement interface.
+func (*DropTrigger) StatementReturnType() StatementReturnType { return DDL }
+
+// StatementType implements the Statement interface.
+func (*DropTrigger) StatementType() StatementType { return TypeDDL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*DropTrigger) StatementTag() string { return "DROP TRIGGER" }
+
 // StatementReturnType implements the Statement interface.
 func (*DropType) StatementReturnType() StatementReturnType { return DDL }
 
@@ -914,6 +1019,15 @@ func (*FetchCursor) StatementType() StatementType { return TypeDML }
 // StatementTag returns a short string identifying the type of statement.
 func (*FetchCursor) StatementTag() string { return "FETCH" }
 
+// StatementReturnType implements the Statement interface.
+func (*Listen) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*Listen) StatementType() StatementType { return TypeTCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Listen) StatementTag() string { return "LISTEN" }
+
 // StatementReturnType implements the Statement interface.
 func (n *MoveCursor) StatementReturnType() StatementReturnType { return RowsAffected }
 
@@ -958,6 +1072,24 @@ func (*Import) StatementTag() string { return "IMPORT" }
 
 func (*Import) cclOnlyStatement() {}
 
+// StatementReturnType implements the Statement interface.
+func (*Merge) StatementReturnType() StatementReturnType { return RowsAffected }
+
+// StatementType implements the Statement interface.
+func (*Merge) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Merge) StatementTag() string { return "MERGE" }
+
+// StatementReturnType implements the Statement interface.
+func (*Notify) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*Notify) StatementType() StatementType { return TypeDML }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Notify) StatementTag() string { return "NOTIFY" }
+
 // StatementReturnType implements the Statement interface.
 func (*ParenSelect) StatementReturnType() StatementReturnType { return Rows }
 
@@ -1207,6 +1339,15 @@ func (*SetClusterSetting) StatementType() StatementType { return TypeDCL }
 // StatementTag returns a short string identifying the type of statement.
 func (*SetClusterSetting) StatementTag() string { return "SET CLUSTER SETTING" }
 
+// StatementReturnType implements the Statement interface.
+func (*SetConstraints) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*SetConstraints) StatementType() StatementType { return TypeDCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*SetConstraints) StatementTag() string { return "SET CONSTRAINTS" }
+
 // StatementReturnType implements the Statement interface.
 func (*SetTransaction) StatementReturnType() StatementReturnType { return Ack }
 
@@ -1742,6 +1883,15 @@ func (*Unsplit) StatementType() StatementType { return TypeDML }
 // StatementTag returns a short string identifying the type of statement.
 func (*Unsplit) StatementTag() string { return "UNSPLIT" }
 
+// StatementReturnType implements the Statement interface.
+func (*Unlisten) StatementReturnType() StatementReturnType { return Ack }
+
+// StatementType implements the Statement interface.
+func (*Unlisten) StatementType() StatementType { return TypeTCL }
+
+// StatementTag returns a short string identifying the type of statement.
+func (*Unlisten) StatementTag() string { return "UNLISTEN" }
+
 // StatementReturnType implements the Statement interface.
 func (*Truncate) StatementReturnType() StatementReturnType { return Ack }
 
@@ -1796,6 +1946,9 @@ func (n *AlterDatabaseDropSuperRegion) String() string   { return AsString(n) }
 func (n *AlterDatabaseAlterSuperRegion) String() string  { return AsString(n) }
 func (n *AlterDefaultPrivileges) String() string         { return AsString(n) }
 func (n *AlterSchema) String() string                    { return AsString(n) }
+func (n *AlterFunctionRename) String() string            { return AsString(n) }
+func (n *AlterFunctionSetOwner) String() string          { return AsString(n) }
+func (n *AlterFunctionSetSchema) String() string         { return AsString(n) }
 func (n *AlterTable) String() string                     { return AsString(n) }
 func (n *AlterTableCmds) String() string                 { return AsString(n) }
 func (n *AlterTableAddColumn) String() string            { return AsString(n) }
@@ -1813,6 +1966,7 @@ func (n *AlterTableOwner) String() string                { return AsString(n) }
 func (n *AlterTableSetSchema) String() string            { return AsString(n) }
 func (n *AlterTenantSetClusterSetting) String() string   { return AsString(n) }
 func (n *AlterType) String() string                      { return AsString(n) }
+func (n *AlterDomain) String() string                    { return AsString(n) }
 func (n *AlterRole) String() string                      { return AsString(n) }
 func (n *AlterRoleSet) String() string                   { return AsString(n) }
 func (n *AlterSequence) String() string                  { return AsString(n) }
@@ -1830,6 +1984,7 @@ func (n *CloseCursor) String() string                    { return AsString(n) }
 func (n *CommentOnColumn) String() string                { return AsString(n) }
 func (n *CommentOnConstraint) String() string            { return AsString(n) }
 func (n *CommentOnDatabase) String() string              { return AsString(n) }
+func (n *CommentOnFunction) String() string              { return AsString(n) }
 func (n *CommentOnSchema) String() string                { return AsString(n) }
 func (n *CommentOnIndex) String() string                 { return AsString(n) }
 func (n *CommentOnTable) String() string                 { return AsString(n) }
@@ -1837,23 +1992,29 @@ func (n *CommitTransaction) String() string              { return AsString(n) }
 func (n *CopyFrom) String() string                       { return AsString(n) }
 func (n *CreateChangefeed) String() string               { return AsString(n) }
 func (n *CreateDatabase) String() string                 { return AsString(n) }
+func (n *CreateDomain) String() string                   { return AsString(n) }
 func (n *CreateExtension) String() string                { return AsString(n) }
+func (n *CreateFunction) String() string                 { return AsString(n) }
 func (n *CreateIndex) String() string                    { return AsString(n) }
 func (n *CreateRole) String() string                     { return AsString(n) }
 func (n *CreateTable) String() string                    { return AsString(n) }
 func (n *CreateSchema) String() string                   { return AsString(n) }
 func (n *CreateSequence) String() string                 { return AsString(n) }
 func (n *CreateStats) String() string                    { return AsString(n) }
+func (n *CreateTrigger) String() string                  { return AsString(n) }
 func (n *CreateView) String() string                     { return AsString(n) }
 func (n *Deallocate) String() string                     { return AsString(n) }
 func (n *Delete) String() string                         { return AsString(n) }
 func (n *DeclareCursor) String() string                  { return AsString(n) }
 func (n *DropDatabase) String() string                   { return AsString(n) }
+func (n *DropDomain) String() string                     { return AsString(n) }
+func (n *DropFunction) String() string                   { return AsString(n) }
 func (n *DropIndex) String() string                      { return AsString(n) }
 func (n *DropOwnedBy) String() string                    { return AsString(n) }
 func (n *DropSchema) String() string                     { return AsString(n) }
 func (n *DropSequence) String() string                   { return AsString(n) }
 func (n *DropTable) String() string                      { return AsString(n) }
+func (n *DropTrigger) String() string                    { return AsString(n) }
 func (n *DropType) String() string                       { return AsString(n) }
 func (n *DropView) String() string                       { return AsString(n) }
 func (n *DropRole) String() string                       { return AsString(n) }
@@ -1867,6 +2028,9 @@ func (n *GrantRole) String() string                      { return AsString(n) }
 func (n *MoveCursor) String() string                     { return AsString(n) }
 func (n *Insert) String() string                         { return AsString(n) }
 func (n *Import) String() string                         { return AsString(n) }
+func (n *Listen) String() string                         { return AsString(n) }
+func (n *Merge) String() string                          { return AsString(n) }
+func (n *Notify) String() string                         { return AsString(n) }
 func (n *ParenSelect) String() string                    { return AsString(n) }
 func (n *Prepare) String() string                        { return AsString(n) }
 func (n *ReassignOwnedBy) String() string                { return AsString(n) }
@@ -1891,6 +2055,7 @@ func (n *Scrub) String() string                          { return AsString(n) }
 func (n *Select) String() string                         { return AsString(n) }
 func (n *SelectClause) String() string                   { return AsString(n) }
 func (n *SetClusterSetting) String() string              { return AsString(n) }
+func (n *SetConstraints) String() string                 { return AsString(n) }
 func (n *SetZoneConfig) String() string                  { return AsString(n) }
 func (n *SetSessionAuthorizationDefault) String() string { return AsString(n) }
 func (n *SetSessionCharacteristics) String() string      { return AsString(n) }
@@ -1950,6 +2115,7 @@ func (n *Split) String() string                          { return AsString(n) }
 func (n *StreamIngestion) String() string                { return AsString(n) }
 func (n *Unsplit) String() string                        { return AsString(n) }
 func (n *Truncate) String() string                       { return AsString(n) }
+func (n *Unlisten) String() string                       { return AsString(n) }
 func (n *UnionClause) String() string                    { return AsString(n) }
 func (n *Update) String() string                         { return AsString(n) }
 func (n *ValuesClause) String() string                   { return AsString(n) }
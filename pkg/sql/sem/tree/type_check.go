@@ -54,6 +54,10 @@ type SemaContext struct {
 	// name of a table given its ID.
 	TableNameResolver QualifiedNameResolver
 
+	// FunctionResolver is used to resolve the names of user-defined
+	// functions. If nil, only builtin functions can be used.
+	FunctionResolver FunctionReferenceResolver
+
 	// IntervalStyleEnabled determines whether IntervalStyle is enabled.
 	// TODO(sql-exp): remove this field in 22.2, since it will always be true.
 	IntervalStyleEnabled bool
@@ -528,7 +532,9 @@ func (expr *CastExpr) TypeCheck(
 		return nil, err
 	}
 	expr.Type = exprType
-	canElideCast := true
+	// Casts to a DOMAIN are never elided, since the constraints of the domain
+	// need to be checked.
+	canElideCast := !exprType.IsDomain()
 	switch {
 	case isConstant(expr.Expr):
 		c := expr.Expr.(Constant)
@@ -1005,10 +1011,12 @@ func (expr *FuncExpr) TypeCheck(
 	ctx context.Context, semaCtx *SemaContext, desired *types.T,
 ) (TypedExpr, error) {
 	var searchPath sessiondata.SearchPath
+	var resolver FunctionReferenceResolver
 	if semaCtx != nil {
 		searchPath = semaCtx.SearchPath
+		resolver = semaCtx.FunctionResolver
 	}
-	def, err := expr.Func.Resolve(searchPath)
+	def, err := expr.Func.ResolveWithResolver(ctx, searchPath, resolver)
 	if err != nil {
 		return nil, err
 	}
@@ -77,6 +77,9 @@ func (node *UpdateExprs) Format(ctx *FmtCtx) {
 type UpdateExpr struct {
 	Tuple bool
 	Names NameList
+	// Field is set when a single field of a column of a composite type is
+	// assigned, as in SET a.b = 1.
+	Field Name
 	Expr  Expr
 }
 
@@ -89,6 +92,10 @@ func (node *UpdateExpr) Format(ctx *FmtCtx) {
 	ctx.WriteString(open)
 	ctx.FormatNode(&node.Names)
 	ctx.WriteString(close)
+	if node.Field != "" {
+		ctx.WriteByte('.')
+		ctx.FormatNode(&node.Field)
+	}
 	ctx.WriteString(" = ")
 	ctx.FormatNode(node.Expr)
 }
@@ -1090,6 +1090,65 @@ func (stmt *Insert) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (stmt *Merge) copyNode() *Merge {
+	stmtCopy := *stmt
+	stmtCopy.Whens = make(MergeWhens, len(stmt.Whens))
+	for i, w := range stmt.Whens {
+		wCopy := *w
+		wCopy.Exprs = make(UpdateExprs, len(w.Exprs))
+		for j, e := range w.Exprs {
+			eCopy := *e
+			wCopy.Exprs[j] = &eCopy
+		}
+		if w.Values != nil {
+			wCopy.Values = append(Exprs(nil), w.Values...)
+		}
+		stmtCopy.Whens[i] = &wCopy
+	}
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (stmt *Merge) walkStmt(v Visitor) Statement {
+	ret := stmt
+	e, changed := WalkExpr(v, stmt.On)
+	if changed {
+		ret = stmt.copyNode()
+		ret.On = e
+	}
+	for i, w := range stmt.Whens {
+		if w.Cond != nil {
+			e, changed := WalkExpr(v, w.Cond)
+			if changed {
+				if ret == stmt {
+					ret = stmt.copyNode()
+				}
+				ret.Whens[i].Cond = e
+			}
+		}
+		for j, expr := range w.Exprs {
+			e, changed := WalkExpr(v, expr.Expr)
+			if changed {
+				if ret == stmt {
+					ret = stmt.copyNode()
+				}
+				ret.Whens[i].Exprs[j].Expr = e
+			}
+		}
+		for j, expr := range w.Values {
+			e, changed := WalkExpr(v, expr)
+			if changed {
+				if ret == stmt {
+					ret = stmt.copyNode()
+				}
+				ret.Whens[i].Values[j] = e
+			}
+		}
+	}
+	return ret
+}
+
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (stmt *CreateTable) copyNode() *CreateTable {
 	stmtCopy := *stmt
@@ -1218,6 +1277,10 @@ func (stmt *ParenSelect) walkStmt(v Visitor) Statement {
 func (stmt *Restore) copyNode() *Restore {
 	stmtCopy := *stmt
 	stmtCopy.From = append([]StringOrPlaceholderOptList(nil), stmt.From...)
+	if stmt.Where != nil {
+		wCopy := *stmt.Where
+		stmtCopy.Where = &wCopy
+	}
 	return &stmtCopy
 }
 
@@ -1233,6 +1296,15 @@ func (stmt *Restore) walkStmt(v Visitor) Statement {
 			ret.AsOf.Expr = e
 		}
 	}
+	if stmt.Where != nil {
+		e, changed := WalkExpr(v, stmt.Where.Expr)
+		if changed {
+			if ret == stmt {
+				ret = stmt.copyNode()
+			}
+			ret.Where.Expr = e
+		}
+	}
 	for i, backup := range stmt.From {
 		for j, expr := range backup {
 			e, changed := WalkExpr(v, expr)
@@ -1660,6 +1732,7 @@ var _ walkableStmt = &Backup{}
 var _ walkableStmt = &Delete{}
 var _ walkableStmt = &Explain{}
 var _ walkableStmt = &Insert{}
+var _ walkableStmt = &Merge{}
 var _ walkableStmt = &Import{}
 var _ walkableStmt = &ParenSelect{}
 var _ walkableStmt = &Restore{}
@@ -0,0 +1,114 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// SetConstraints sets the checking mode of deferrable constraints for the
+// current transaction. Switching constraints to IMMEDIATE re-validates those
+// of them that were violated while deferred.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	state := p.extendedEvalCtx.DeferredConstraints
+	if state == nil {
+		return nil, errors.AssertionFailedf("SET CONSTRAINTS used outside of a session")
+	}
+	var keys []constraintKey
+	for _, name := range n.Names {
+		resolved, err := p.resolveDeferrableConstraint(ctx, string(name))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, resolved...)
+	}
+	if !n.Deferred {
+		var filter func(constraintKey) bool
+		if len(keys) > 0 {
+			filter = func(key constraintKey) bool {
+				for _, k := range keys {
+					if k == key {
+						return true
+					}
+				}
+				return false
+			}
+		}
+		if err := state.validate(
+			ctx, p.Txn(), p.Descriptors(), p.ExecCfg().InternalExecutor, filter,
+		); err != nil {
+			return nil, err
+		}
+	}
+	state.setMode(keys, n.Deferred)
+	return newZeroNode(nil /* columns */), nil
+}
+
+// resolveDeferrableConstraint resolves a constraint name used in SET
+// CONSTRAINTS to the constraints of the tables it refers to. As in Postgres,
+// the schemas of the search path are searched in order, and the name refers
+// to the constraints with that name on all the tables of the first schema
+// which has any.
+func (p *planner) resolveDeferrableConstraint(
+	ctx context.Context, name string,
+) ([]constraintKey, error) {
+	db, err := p.Descriptors().GetImmutableDatabaseByName(
+		ctx, p.Txn(), p.CurrentDatabase(), tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := p.Descriptors().GetAllTableDescriptorsInDatabase(ctx, p.Txn(), db.GetID())
+	if err != nil {
+		return nil, err
+	}
+	iter := p.CurrentSearchPath().IterWithoutImplicitPGSchemas()
+	for scName, ok := iter.Next(); ok; scName, ok = iter.Next() {
+		sc, err := p.Descriptors().GetImmutableSchemaByName(
+			ctx, p.Txn(), db, scName, tree.SchemaLookupFlags{},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if sc == nil {
+			continue
+		}
+		var keys []constraintKey
+		for _, tbl := range tables {
+			if tbl.GetParentSchemaID() != sc.GetID() || tbl.Dropped() || !tbl.IsTable() {
+				continue
+			}
+			info, err := tbl.GetConstraintInfo()
+			if err != nil {
+				return nil, err
+			}
+			detail, ok := info[name]
+			if !ok {
+				continue
+			}
+			if detail.Deferrability() == catpb.ConstraintDeferrability_NOT_DEFERRABLE {
+				return nil, pgerror.Newf(pgcode.WrongObjectType,
+					"constraint %q is not deferrable", name)
+			}
+			keys = append(keys, constraintKey{tableID: tbl.GetID(), name: name})
+		}
+		if len(keys) > 0 {
+			return keys, nil
+		}
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject, "constraint %q does not exist", name)
+}
@@ -382,6 +382,10 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	if fk.Deferrability != catpb.ConstraintDeferrability_NOT_DEFERRABLE {
+		buf.WriteByte(' ')
+		buf.WriteString(fk.Deferrability.String())
+	}
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
@@ -628,6 +632,10 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		if c.Deferrability != catpb.ConstraintDeferrability_NOT_DEFERRABLE {
+			f.WriteString(" ")
+			f.WriteString(c.Deferrability.String())
+		}
 		if c.IsPartial() {
 			f.WriteString(" WHERE ")
 			pred, err := schemaexpr.FormatExprForDisplay(ctx, desc, c.Predicate, semaCtx, sessionData, tree.FmtParsable)
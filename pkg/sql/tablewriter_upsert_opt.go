@@ -79,12 +79,22 @@ type optTableUpserter struct {
 	// an update is performed. This column will always be one of the fetchCols.
 	canaryOrdinal int
 
+	// deleteOrdinal is the ordinal position of the column within the input row
+	// that is used to decide whether to delete an existing row rather than to
+	// update it. It is -1 unless the upsert was planned for a MERGE statement
+	// which can delete rows.
+	deleteOrdinal int
+
 	// resultRow is a reusable slice of Datums used to store result rows.
 	resultRow tree.Datums
 
 	// ru is used when updating rows.
 	ru row.Updater
 
+	// rd is used when deleting rows. It is only initialized if deleteOrdinal
+	// is not -1.
+	rd row.Deleter
+
 	// tabColIdxToRetIdx is the mapping from the columns in the table to the
 	// columns in the resultRowBuffer. A value of -1 is used to indicate
 	// that the table column at that index is not part of the resultRowBuffer
@@ -180,8 +190,13 @@ func (tu *optTableUpserter) row(
 		return tu.insertNonConflictingRow(ctx, tu.b, row[:insertEnd], pm, false /* overwrite */, traceKV)
 	}
 
-	// If no columns need to be updated, then possibly collect the unchanged row.
 	fetchEnd := insertEnd + len(tu.fetchCols)
+	if tu.deleteOrdinal != -1 && row[tu.deleteOrdinal] == tree.DBoolTrue {
+		// The existing row is deleted rather than updated.
+		return tu.rd.DeleteRow(ctx, tu.b, row[insertEnd:fetchEnd], pm, traceKV)
+	}
+
+	// If no columns need to be updated, then possibly collect the unchanged row.
 	if len(tu.updateCols) == 0 {
 		if !tu.rowsNeeded {
 			return nil
@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -97,6 +98,33 @@ func findTransitioningMembers(desc *typedesc.Mutable) ([][]byte, bool) {
 	return transitioningMembers, beingDropped
 }
 
+// findValidatingDomainChecks returns the names of the CHECK constraints of a
+// domain that were added in the current txn, and must be validated by the job
+// created for the txn.
+func findValidatingDomainChecks(desc *typedesc.Mutable) []string {
+	if desc.Kind != descpb.TypeDescriptor_DOMAIN {
+		return nil
+	}
+	validatingInClusterVersion := func(name string) bool {
+		if desc.IsNew() || desc.ClusterVersion.Domain == nil {
+			return false
+		}
+		for _, c := range desc.ClusterVersion.Domain.CheckConstraints {
+			if c.Name == name {
+				return c.Validity == descpb.ConstraintValidity_Validating
+			}
+		}
+		return false
+	}
+	var validating []string
+	for _, c := range desc.Domain.CheckConstraints {
+		if c.Validity == descpb.ConstraintValidity_Validating && !validatingInClusterVersion(c.Name) {
+			validating = append(validating, c.Name)
+		}
+	}
+	return validating
+}
+
 // writeTypeSchemaChange should be called on a mutated type descriptor to ensure that
 // the descriptor gets written to a batch, as well as ensuring that a job is
 // created to perform the schema change on the type.
@@ -106,11 +134,13 @@ func (p *planner) writeTypeSchemaChange(
 	// Check if there is a cached specification for this type, otherwise create one.
 	record, recordExists := p.extendedEvalCtx.SchemaChangeJobRecords[typeDesc.ID]
 	transitioningMembers, beingDropped := findTransitioningMembers(typeDesc)
+	validatingDomainChecks := findValidatingDomainChecks(typeDesc)
 	if recordExists {
 		// Update it.
 		newDetails := jobspb.TypeSchemaChangeDetails{
-			TypeID:               typeDesc.ID,
-			TransitioningMembers: transitioningMembers,
+			TypeID:                 typeDesc.ID,
+			TransitioningMembers:   transitioningMembers,
+			ValidatingDomainChecks: validatingDomainChecks,
 		}
 		record.Details = newDetails
 		record.AppendDescription(jobDesc)
@@ -135,8 +165,9 @@ func (p *planner) writeTypeSchemaChange(
 			Username:      p.User(),
 			DescriptorIDs: descpb.IDs{typeDesc.ID},
 			Details: jobspb.TypeSchemaChangeDetails{
-				TypeID:               typeDesc.ID,
-				TransitioningMembers: transitioningMembers,
+				TypeID:                 typeDesc.ID,
+				TransitioningMembers:   transitioningMembers,
+				ValidatingDomainChecks: validatingDomainChecks,
 			},
 			Progress: jobspb.TypeSchemaChangeProgress{},
 			// Type change jobs in general are not cancelable, unless they include
@@ -169,7 +200,10 @@ type typeSchemaChanger struct {
 	// for a typeSchemaChanger. This is used to group transitions together and
 	// ensure proper rollback semantics on job failure.
 	transitioningMembers [][]byte
-	execCfg              *ExecutorConfig
+	// validatingDomainChecks is a list of the names of the CHECK constraints of
+	// a domain that are validated in the job created for a typeSchemaChanger.
+	validatingDomainChecks []string
+	execCfg                *ExecutorConfig
 }
 
 // TypeSchemaChangerTestingKnobs contains testing knobs for the typeSchemaChanger.
@@ -494,6 +528,55 @@ func (t *typeSchemaChanger) exec(ctx context.Context) error {
 		}
 	}
 
+	// For all the CHECK constraints of a domain the current job is responsible
+	// for, validate them against the values already stored in the columns of
+	// the domain and mark them as validated. All nodes enforce the constraints
+	// on writes once the leases have been refreshed above.
+	if typeDesc.GetKind() == descpb.TypeDescriptor_DOMAIN && len(t.validatingDomainChecks) != 0 {
+		// The validation is done in a separate txn to the one that mutates the
+		// descriptor, as it can take arbitrarily long.
+		validateChecks := func(ctx context.Context, txn *kv.Txn, descsCol *descs.Collection) error {
+			typeDesc, err := descsCol.GetMutableTypeVersionByID(ctx, txn, t.typeID)
+			if err != nil {
+				return err
+			}
+			for i := range typeDesc.Domain.CheckConstraints {
+				check := &typeDesc.Domain.CheckConstraints[i]
+				if !t.isValidatingInCurrentJob(check) {
+					continue
+				}
+				if err := t.validateDomainCheck(ctx, typeDesc, txn, check, descsCol); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if err := DescsTxn(ctx, t.execCfg, validateChecks); err != nil {
+			return err
+		}
+
+		run := func(ctx context.Context, txn *kv.Txn, descsCol *descs.Collection) error {
+			typeDesc, err := descsCol.GetMutableTypeVersionByID(ctx, txn, t.typeID)
+			if err != nil {
+				return err
+			}
+			for i := range typeDesc.Domain.CheckConstraints {
+				check := &typeDesc.Domain.CheckConstraints[i]
+				if t.isValidatingInCurrentJob(check) {
+					check.Validity = descpb.ConstraintValidity_Validated
+				}
+			}
+			return descsCol.WriteDesc(ctx, true /* kvTrace */, typeDesc, txn)
+		}
+		if err := DescsTxn(ctx, t.execCfg, run); err != nil {
+			return err
+		}
+
+		if err := refreshTypeDescriptorLeases(ctx, leaseMgr, typeDesc); err != nil {
+			return err
+		}
+	}
+
 	// If the type is being dropped, remove the descriptor here.
 	if typeDesc.Dropped() {
 		if err := t.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
@@ -520,6 +603,22 @@ func (t *typeSchemaChanger) isTransitioningInCurrentJob(
 	return false
 }
 
+// isValidatingInCurrentJob returns true if the given CHECK constraint of a
+// domain is being validated in the current job.
+func (t *typeSchemaChanger) isValidatingInCurrentJob(
+	check *descpb.TypeDescriptor_Domain_CheckConstraint,
+) bool {
+	if check.Validity != descpb.ConstraintValidity_Validating {
+		return false
+	}
+	for _, name := range t.validatingDomainChecks {
+		if check.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // applyFilterOnEnumMembers modifies the supplied typeDesc by removing all enum
 // members as dictated by shouldRemove.
 func applyFilterOnEnumMembers(
@@ -602,6 +701,37 @@ func (t *typeSchemaChanger) cleanupEnumValues(ctx context.Context) error {
 	return DescsTxn(ctx, t.execCfg, cleanup)
 }
 
+// cleanupDomainChecks removes the CHECK constraints of a domain which were
+// being validated by the current job, if the job fails.
+func (t *typeSchemaChanger) cleanupDomainChecks(ctx context.Context) error {
+	if len(t.validatingDomainChecks) == 0 {
+		return nil
+	}
+	cleanup := func(ctx context.Context, txn *kv.Txn, descsCol *descs.Collection) error {
+		typeDesc, err := descsCol.GetMutableTypeVersionByID(ctx, txn, t.typeID)
+		if err != nil {
+			return err
+		}
+		if typeDesc.Kind != descpb.TypeDescriptor_DOMAIN {
+			return nil
+		}
+		checks := typeDesc.Domain.CheckConstraints
+		kept := checks[:0]
+		for i := range checks {
+			if !t.isValidatingInCurrentJob(&checks[i]) {
+				kept = append(kept, checks[i])
+			}
+		}
+		// No cleanup required.
+		if len(kept) == len(checks) {
+			return nil
+		}
+		typeDesc.Domain.CheckConstraints = kept
+		return descsCol.WriteDesc(ctx, true /* kvTrace */, typeDesc, txn)
+	}
+	return DescsTxn(ctx, t.execCfg, cleanup)
+}
+
 // convertToSQLStringRepresentation takes an array of bytes (the physical
 // representation of an enum) and converts it into a string that can be used
 // in a SQL predicate.
@@ -982,6 +1112,65 @@ func (t *typeSchemaChanger) canRemoveEnumValue(
 	return t.canRemoveEnumValueFromArrayUsages(ctx, arrayTypeDesc, member, txn, descsCol)
 }
 
+// validateDomainCheck checks that the values of all the columns declared with
+// the given domain satisfy the given CHECK constraint of the domain.
+func (t *typeSchemaChanger) validateDomainCheck(
+	ctx context.Context,
+	typeDesc *typedesc.Mutable,
+	txn *kv.Txn,
+	check *descpb.TypeDescriptor_Domain_CheckConstraint,
+	descsCol *descs.Collection,
+) error {
+	const validationErr = "could not validate domain check constraint %q"
+	checkExpr, err := parser.ParseExpr(check.Expr)
+	if err != nil {
+		return errors.Wrapf(err, validationErr, check.Name)
+	}
+	for _, ID := range typeDesc.ReferencingDescriptorIDs {
+		desc, err := descsCol.GetImmutableTableByID(ctx, txn, ID, tree.ObjectLookupFlags{
+			CommonLookupFlags: tree.CommonLookupFlags{
+				AvoidLeased:    true,
+				IncludeOffline: true,
+				IncludeDropped: true,
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(err, validationErr, check.Name)
+		}
+		if !desc.Public() {
+			// Dropped and offline tables are not validated.
+			continue
+		}
+		for _, col := range desc.PublicColumns() {
+			if col.GetDomainTypeID() != typeDesc.ID {
+				continue
+			}
+			expr, err := schemaexpr.ReplaceDomainValue(
+				checkExpr, &tree.ColumnItem{ColumnName: tree.Name(col.GetName())},
+			)
+			if err != nil {
+				return errors.Wrapf(err, validationErr, check.Name)
+			}
+			query := fmt.Sprintf(`SELECT 1 FROM [%d AS t] WHERE NOT (%s) LIMIT 1`,
+				ID, tree.Serialize(expr))
+			log.Infof(ctx, "validating domain check constraint %q with query %q", check.Name, query)
+			override := sessiondata.InternalExecutorOverride{User: security.RootUserName()}
+			row, err := t.execCfg.InternalExecutor.QueryRowEx(
+				ctx, "validate-domain-check", txn, override, query,
+			)
+			if err != nil {
+				return errors.Wrapf(err, validationErr, check.Name)
+			}
+			if len(row) > 0 {
+				return pgerror.Newf(pgcode.CheckViolation,
+					"column %q of table %q contains values that violate the new constraint",
+					col.GetName(), desc.GetName())
+			}
+		}
+	}
+	return nil
+}
+
 // findUsagesOfEnumValueInPartitioning is a recursive function to explore all of
 // the values used in partitioning and its subpartitions. The fakePrefixDatums
 // should be nil when first calling this function. They are needed to support
@@ -1290,9 +1479,10 @@ func (t *typeChangeResumer) Resume(ctx context.Context, execCtx interface{}) err
 		}
 	}
 	tc := &typeSchemaChanger{
-		typeID:               t.job.Details().(jobspb.TypeSchemaChangeDetails).TypeID,
-		transitioningMembers: t.job.Details().(jobspb.TypeSchemaChangeDetails).TransitioningMembers,
-		execCfg:              p.ExecCfg(),
+		typeID:                 t.job.Details().(jobspb.TypeSchemaChangeDetails).TypeID,
+		transitioningMembers:   t.job.Details().(jobspb.TypeSchemaChangeDetails).TransitioningMembers,
+		validatingDomainChecks: t.job.Details().(jobspb.TypeSchemaChangeDetails).ValidatingDomainChecks,
+		execCfg:                p.ExecCfg(),
 	}
 	return tc.execWithRetry(ctx)
 }
@@ -1301,9 +1491,10 @@ func (t *typeChangeResumer) Resume(ctx context.Context, execCtx interface{}) err
 func (t *typeChangeResumer) OnFailOrCancel(ctx context.Context, execCtx interface{}) error {
 	// If the job failed, just try again to clean up any draining names.
 	tc := &typeSchemaChanger{
-		typeID:               t.job.Details().(jobspb.TypeSchemaChangeDetails).TypeID,
-		transitioningMembers: t.job.Details().(jobspb.TypeSchemaChangeDetails).TransitioningMembers,
-		execCfg:              execCtx.(JobExecContext).ExecCfg(),
+		typeID:                 t.job.Details().(jobspb.TypeSchemaChangeDetails).TypeID,
+		transitioningMembers:   t.job.Details().(jobspb.TypeSchemaChangeDetails).TransitioningMembers,
+		validatingDomainChecks: t.job.Details().(jobspb.TypeSchemaChangeDetails).ValidatingDomainChecks,
+		execCfg:                execCtx.(JobExecContext).ExecCfg(),
 	}
 
 	if rollbackErr := func() error {
@@ -1311,6 +1502,10 @@ func (t *typeChangeResumer) OnFailOrCancel(ctx context.Context, execCtx interfac
 			return err
 		}
 
+		if err := tc.cleanupDomainChecks(ctx); err != nil {
+			return err
+		}
+
 		if err := drainNamesForDescriptor(
 			ctx, tc.typeID, tc.execCfg.CollectionFactory, tc.execCfg.DB,
 			tc.execCfg.InternalExecutor, tc.execCfg.Codec,
@@ -214,6 +214,9 @@ func CalcArrayOid(elemTyp *T) oid.Oid {
 		return elemTyp.UserDefinedArrayOID()
 
 	case TupleFamily:
+		if elemTyp.IsComposite() {
+			return elemTyp.UserDefinedArrayOID()
+		}
 		if elemTyp.UserDefined() {
 			// We're currently not creating array types for implicitly-defined
 			// per-table record types. So, we cheat a little, and return, as the OID
@@ -196,6 +196,9 @@ type UserDefinedTypeMetadata struct {
 
 	// enumData is non-nil iff the metadata is for an ENUM type.
 	EnumData *EnumMetadata
+
+	// DomainData is non-nil iff the metadata is for a DOMAIN type.
+	DomainData *DomainMetadata
 }
 
 // EnumMetadata is metadata about an ENUM needed for evaluation.
@@ -214,6 +217,30 @@ type EnumMetadata struct {
 	//  should occur, if at all.
 }
 
+// DomainMetadata is metadata about a DOMAIN needed to enforce its constraints.
+// A domain is represented by a copy of its base type which carries this
+// metadata, so the type behaves like its base type everywhere except in casts,
+// where the constraints of the domain are checked.
+type DomainMetadata struct {
+	// TypeOID is the OID of the domain type descriptor. Unlike the OID of the
+	// T, which is the OID of the base type, it identifies the domain itself.
+	TypeOID oid.Oid
+	// NotNull is true if the domain does not allow NULL values.
+	NotNull bool
+	// DefaultExpr is the serialized default expression of the domain, if any.
+	DefaultExpr *string
+	// CheckConstraints are the CHECK constraints of the domain.
+	CheckConstraints []DomainCheckConstraint
+}
+
+// DomainCheckConstraint is a CHECK constraint of a DOMAIN. Expr is a
+// serialized boolean expression which refers to the value being checked with
+// the VALUE keyword.
+type DomainCheckConstraint struct {
+	Name string
+	Expr string
+}
+
 func (e *EnumMetadata) debugString() string {
 	return fmt.Sprintf(
 		"PhysicalReps: %v; LogicalReps: %s",
@@ -1097,6 +1124,17 @@ func MakeEnum(typeOID, arrayTypeOID oid.Oid) *T {
 	}}
 }
 
+// MakeComposite constructs a new instance of a user defined composite type,
+// which is a labeled tuple with the given OID and array type OID.
+func MakeComposite(typeOID, arrayTypeOID oid.Oid, contents []*T, labels []string) *T {
+	t := MakeLabeledTuple(contents, labels)
+	t.InternalType.Oid = typeOID
+	t.InternalType.UDTMetadata = &PersistentUserDefinedTypeMetadata{
+		ArrayTypeOID: arrayTypeOID,
+	}
+	return t
+}
+
 // MakeArray constructs a new instance of an ArrayFamily type with the given
 // element type (which may itself be an ArrayFamily type).
 func MakeArray(typ *T) *T {
@@ -1364,6 +1402,21 @@ func RemapUserDefinedTypeOIDs(t *T, newOID, newArrayOID oid.Oid) {
 	}
 }
 
+// IsDomain returns whether or not t is a user defined DOMAIN type. Note that
+// domains have the OID of their base type, so UserDefined returns false for
+// domains over builtin types.
+func (t *T) IsDomain() bool {
+	return t.TypeMeta.DomainData != nil
+}
+
+// IsComposite returns whether or not t is a user defined composite type,
+// created with CREATE TYPE ... AS (...). Unlike the implicit record types of
+// tables, which are also user defined tuples, composite types have an array
+// type.
+func (t *T) IsComposite() bool {
+	return t.Family() == TupleFamily && t.UserDefined() && t.InternalType.UDTMetadata != nil
+}
+
 // UserDefined returns whether or not t is a user defined type.
 func (t *T) UserDefined() bool {
 	return IsOIDUserDefinedType(t.Oid())
@@ -1851,6 +1904,10 @@ func (t *T) SQLString() string {
 			return "anyenum"
 		}
 		return t.TypeMeta.Name.FQName()
+	case TupleFamily:
+		if t.IsComposite() {
+			return t.TypeMeta.Name.FQName()
+		}
 	}
 	return strings.ToUpper(t.Name())
 }
@@ -344,6 +344,10 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterDatabaseDropSuperRegion{}):     "alter database alter super region",
 	reflect.TypeOf(&alterDatabaseAlterSuperRegion{}):    "alter database drop super region",
 	reflect.TypeOf(&alterDefaultPrivilegesNode{}):       "alter default privileges",
+	reflect.TypeOf(&alterDomainNode{}):                  "alter domain",
+	reflect.TypeOf(&alterFunctionRenameNode{}):          "alter function rename",
+	reflect.TypeOf(&alterFunctionSetOwnerNode{}):        "alter function owner",
+	reflect.TypeOf(&alterFunctionSetSchemaNode{}):       "alter function set schema",
 	reflect.TypeOf(&alterIndexNode{}):                   "alter index",
 	reflect.TypeOf(&alterSequenceNode{}):                "alter sequence",
 	reflect.TypeOf(&alterSchemaNode{}):                  "alter schema",
@@ -363,18 +367,22 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&commentOnColumnNode{}):              "comment on column",
 	reflect.TypeOf(&commentOnConstraintNode{}):          "comment on constraint",
 	reflect.TypeOf(&commentOnDatabaseNode{}):            "comment on database",
+	reflect.TypeOf(&commentOnFunctionNode{}):            "comment on function",
 	reflect.TypeOf(&commentOnIndexNode{}):               "comment on index",
 	reflect.TypeOf(&commentOnTableNode{}):               "comment on table",
 	reflect.TypeOf(&commentOnSchemaNode{}):              "comment on schema",
 	reflect.TypeOf(&controlJobsNode{}):                  "control jobs",
 	reflect.TypeOf(&controlSchedulesNode{}):             "control schedules",
 	reflect.TypeOf(&createDatabaseNode{}):               "create database",
+	reflect.TypeOf(&createDomainNode{}):                 "create domain",
 	reflect.TypeOf(&createExtensionNode{}):              "create extension",
+	reflect.TypeOf(&createFunctionNode{}):               "create function",
 	reflect.TypeOf(&createIndexNode{}):                  "create index",
 	reflect.TypeOf(&createSequenceNode{}):               "create sequence",
 	reflect.TypeOf(&createSchemaNode{}):                 "create schema",
 	reflect.TypeOf(&createStatsNode{}):                  "create statistics",
 	reflect.TypeOf(&createTableNode{}):                  "create table",
+	reflect.TypeOf(&createTriggerNode{}):                "create trigger",
 	reflect.TypeOf(&createTypeNode{}):                   "create type",
 	reflect.TypeOf(&CreateRoleNode{}):                   "create user/role",
 	reflect.TypeOf(&createViewNode{}):                   "create view",
@@ -383,10 +391,12 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&deleteRangeNode{}):                  "delete range",
 	reflect.TypeOf(&distinctNode{}):                     "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):                 "drop database",
+	reflect.TypeOf(&dropFunctionNode{}):                 "drop function",
 	reflect.TypeOf(&dropIndexNode{}):                    "drop index",
 	reflect.TypeOf(&dropSequenceNode{}):                 "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                   "drop schema",
 	reflect.TypeOf(&dropTableNode{}):                    "drop table",
+	reflect.TypeOf(&dropTriggerNode{}):                  "drop trigger",
 	reflect.TypeOf(&dropTypeNode{}):                     "drop type",
 	reflect.TypeOf(&DropRoleNode{}):                     "drop user/role",
 	reflect.TypeOf(&dropViewNode{}):                     "drop view",
@@ -407,8 +417,10 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&invertedJoinNode{}):                 "inverted join",
 	reflect.TypeOf(&joinNode{}):                         "join",
 	reflect.TypeOf(&limitNode{}):                        "limit",
+	reflect.TypeOf(&listenNode{}):                       "listen",
 	reflect.TypeOf(&lookupJoinNode{}):                   "lookup join",
 	reflect.TypeOf(&max1RowNode{}):                      "max1row",
+	reflect.TypeOf(&notifyNode{}):                       "notify",
 	reflect.TypeOf(&ordinalityNode{}):                   "ordinality",
 	reflect.TypeOf(&projectSetNode{}):                   "project set",
 	reflect.TypeOf(&reassignOwnedByNode{}):              "reassign owned by",
@@ -446,6 +458,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&topKNode{}):                         "top-k",
 	reflect.TypeOf(&unsplitNode{}):                      "unsplit",
 	reflect.TypeOf(&unsplitAllNode{}):                   "unsplit all",
+	reflect.TypeOf(&unlistenNode{}):                     "unlisten",
 	reflect.TypeOf(&spoolNode{}):                        "spool",
 	reflect.TypeOf(&truncateNode{}):                     "truncate",
 	reflect.TypeOf(&unaryNode{}):                        "emptyrow",
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/uncertainty"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -675,6 +676,11 @@ type MVCCGetOptions struct {
 	FailOnMoreRecent bool
 	Txn              *roachpb.Transaction
 	Uncertainty      uncertainty.Interval
+	// SkipLocked instructs MVCCGet to return no value if the key is locked by
+	// a conflicting transaction, instead of returning a WriteIntentError. If
+	// set, LockTable is consulted to find unreplicated locks on the key.
+	SkipLocked bool
+	LockTable  LockTableView
 	// MemoryAccount is used for tracking memory allocations.
 	MemoryAccount *mon.BoundAccount
 }
@@ -686,9 +692,23 @@ func (opts *MVCCGetOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
 	return nil
 }
 
+// LockTableView is a transaction-bound view into an in-memory collection of
+// key-level locks. It is used by reads configured to skip locked keys to find
+// locks that are not stored in the engine, i.e. unreplicated locks.
+type LockTableView interface {
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// by a transaction other than the view's own transaction such that a
+	// request with the provided locking strength would conflict with it. If
+	// so, the lock holder is also returned.
+	IsKeyLockedByConflictingTxn(roachpb.Key, lock.Strength) (bool, *enginepb.TxnMeta)
+}
+
 func newMVCCIterator(reader Reader, inlineMeta bool, opts IterOptions) MVCCIterator {
 	iterKind := MVCCKeyAndIntentsIterKind
 	if inlineMeta {
@@ -762,6 +782,8 @@ func mvccGet(
 		inconsistent:     opts.Inconsistent,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		skipLocked:       opts.SkipLocked,
+		lockTable:        opts.LockTable,
 		keyBuf:           mvccScanner.keyBuf,
 	}
 
@@ -2269,6 +2291,8 @@ func mvccScanToBytes(
 		inconsistent:           opts.Inconsistent,
 		tombstones:             opts.Tombstones,
 		failOnMoreRecent:       opts.FailOnMoreRecent,
+		skipLocked:             opts.SkipLocked,
+		lockTable:              opts.LockTable,
 		keyBuf:                 mvccScanner.keyBuf,
 	}
 
@@ -2414,6 +2438,14 @@ type MVCCScanOptions struct {
 	// Not used in inconsistent scans.
 	// The zero value indicates no limit.
 	MaxIntents int64
+	// SkipLocked instructs the scan to skip over keys that are locked by
+	// conflicting transactions, instead of returning them as part of a
+	// WriteIntentError. If set, LockTable is consulted to find unreplicated
+	// locks held by other transactions, which are not visible in the engine.
+	//
+	// Not used in inconsistent scans.
+	SkipLocked bool
+	LockTable  LockTableView
 	// MemoryAccount is used for tracking memory allocations.
 	MemoryAccount *mon.BoundAccount
 }
@@ -2425,6 +2457,9 @@ func (opts *MVCCScanOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
 	return nil
 }
 
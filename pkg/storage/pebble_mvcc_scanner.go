@@ -18,6 +18,7 @@ import (
 	"sync"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/uncertainty"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -342,6 +343,11 @@ type pebbleMVCCScanner struct {
 	isGet                    bool
 	keyBuf                   []byte
 	savedBuf                 []byte
+	// If set, keys locked by conflicting transactions are skipped over instead
+	// of being returned as intents. lockTable, if non-nil, is consulted to find
+	// unreplicated locks that are not visible to the iterator.
+	skipLocked bool
+	lockTable  LockTableView
 	// cur* variables store the "current" record we're pointing to. Updated in
 	// updateCurrent. Note that the timestamp can be clobbered in the case of
 	// adding an intent from the intent history but is otherwise meaningful.
@@ -547,6 +553,13 @@ func (p *pebbleMVCCScanner) uncertaintyError(ts hlc.Timestamp) bool {
 // Emit a tuple and return true if we have reason to believe iteration can
 // continue.
 func (p *pebbleMVCCScanner) getAndAdvance(ctx context.Context) bool {
+	if p.skipLocked && p.isKeyLockedByConflictingTxn(p.curUnsafeKey.Key) {
+		// 0. The key is locked by a conflicting transaction according to the
+		// lock table and we're configured to skip locked keys. Skip the key
+		// entirely, without waiting on the lock or reporting it as an intent.
+		return p.advanceKey()
+	}
+
 	if !p.curUnsafeKey.Timestamp.IsEmpty() {
 		// ts < read_ts
 		if p.curUnsafeKey.Timestamp.Less(p.ts) {
@@ -698,10 +711,14 @@ func (p *pebbleMVCCScanner) getAndAdvance(ctx context.Context) bool {
 		//   the intent is in our transaction's uncertainty interval
 		// - our read timestamp is older than that of the intent but
 		//   we want to fail on more recent writes
-		// Note that this will trigger an error higher up the stack. We
+		// If we're configured to skip locked keys, we skip over the key.
+		// Otherwise, this will trigger an error higher up the stack. We
 		// continue scanning so that we can return all of the intents
 		// in the scan range.
-		//
+		if p.skipLocked {
+			return p.advanceKey()
+		}
+
 		// p.intents is a pebble.Batch which grows its byte slice capacity in
 		// chunks to amortize allocations. The memMonitor is under-counting here
 		// by only accounting for the key and value bytes.
@@ -777,6 +794,23 @@ func (p *pebbleMVCCScanner) getAndAdvance(ctx context.Context) bool {
 	return p.seekVersion(ctx, prevTS, false)
 }
 
+// isKeyLockedByConflictingTxn consults the scanner's lock table view, if one
+// was provided, to determine whether the specified key is locked by a
+// conflicting transaction. Locking reads (those that fail on more recent
+// writes) conflict with all locks held by other transactions, while
+// non-locking reads only conflict with locks at or below their timestamp.
+func (p *pebbleMVCCScanner) isKeyLockedByConflictingTxn(key roachpb.Key) bool {
+	if p.lockTable == nil {
+		return false
+	}
+	strength := lock.None
+	if p.failOnMoreRecent {
+		strength = lock.Exclusive
+	}
+	locked, _ := p.lockTable.IsKeyLockedByConflictingTxn(key, strength)
+	return locked
+}
+
 // nextKey advances to the next user key.
 func (p *pebbleMVCCScanner) nextKey() bool {
 	p.keyBuf = append(p.keyBuf[:0], p.curUnsafeKey.Key...)
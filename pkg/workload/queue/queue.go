@@ -29,9 +29,10 @@ const (
 )
 
 type queue struct {
-	flags     workload.Flags
-	connFlags *workload.ConnFlags
-	batchSize int
+	flags      workload.Flags
+	connFlags  *workload.ConnFlags
+	batchSize  int
+	skipLocked bool
 }
 
 func init() {
@@ -49,6 +50,8 @@ var queueMeta = workload.Meta{
 		g.flags.FlagSet = pflag.NewFlagSet(`queue`, pflag.ContinueOnError)
 		g.connFlags = workload.NewConnFlags(&g.flags)
 		g.flags.IntVar(&g.batchSize, `batch`, 1, `Number of blocks to insert in a single SQL statement`)
+		g.flags.BoolVar(&g.skipLocked, `skip-locked`, false,
+			`Dequeue rows claimed with SELECT FOR UPDATE SKIP LOCKED instead of deleting a prefix of the queue`)
 		return g
 	},
 }
@@ -100,7 +103,16 @@ func (w *queue) Ops(
 
 	// Generate queue deletion statement. This is intentionally in a naive form
 	// for testing purposes.
-	deleteStmt, err := db.Prepare(`DELETE FROM queue WHERE ts < $1`)
+	deleteQuery := `DELETE FROM queue WHERE ts < $1`
+	if w.skipLocked {
+		// Claim the oldest entries in the queue which are not already claimed
+		// by a concurrent worker. Concurrent workers skip over each other's
+		// locked rows instead of waiting on them, so dequeues do not contend.
+		deleteQuery = `DELETE FROM queue WHERE (ts, id) IN (
+			SELECT ts, id FROM queue ORDER BY ts LIMIT $1 FOR UPDATE SKIP LOCKED
+		)`
+	}
+	deleteStmt, err := db.Prepare(deleteQuery)
 	if err != nil {
 		return workload.QueryLoad{}, err
 	}
@@ -156,9 +168,14 @@ func (o *queueOp) run(ctx context.Context) error {
 	elapsed := timeutil.Since(startTime)
 	o.hists.Get("write").Record(elapsed)
 
-	// Delete batch which was just written.
+	// Delete batch which was just written. If configured to skip locked rows,
+	// dequeue a batch's worth of unclaimed rows instead.
+	deleteArg := end
+	if o.config.skipLocked {
+		deleteArg = o.config.batchSize
+	}
 	startTime = timeutil.Now()
-	_, err = o.deleteStmt.Exec(end)
+	_, err = o.deleteStmt.Exec(deleteArg)
 	elapsed = timeutil.Since(startTime)
 	o.hists.Get(`delete`).Record(elapsed)
 	return err